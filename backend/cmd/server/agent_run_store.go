@@ -0,0 +1,134 @@
+// Package main AutoDM 运行审计的 MySQL 适配器
+//
+// [IN]  internal/store（agent_runs、agent_tool_calls 读写）
+// [OUT] main.go（agent.Config.RunStore 与 api.WithAgentRunStore）
+// [POS] 启动入口的适配层，把 agent.AgentRunStore 映射到 store 模型
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// sqlAgentRunStore adapts store.Store to agent.AgentRunStore
+type sqlAgentRunStore struct {
+	st *store.Store
+}
+
+func (a *sqlAgentRunStore) SaveRun(ctx context.Context, run agent.AgentRun) error {
+	return a.st.SaveAgentRun(ctx, store.AgentRun{
+		ID:           run.ID,
+		RoomID:       run.RoomID,
+		SeqFrom:      run.SeqFrom,
+		SeqTo:        run.SeqTo,
+		AgentName:    run.AgentName,
+		InputDigest:  run.InputDigest,
+		OutputDigest: run.OutputDigest,
+		Status:       run.Status,
+		LatencyMs:    run.LatencyMs,
+		ErrorText:    run.ErrorText,
+		CreatedAt:    run.CreatedAt,
+		PlanJSON:     string(run.PlanJSON),
+	})
+}
+
+func (a *sqlAgentRunStore) SaveToolCall(ctx context.Context, call agent.ToolCallAudit) error {
+	return a.st.SaveAgentToolCall(ctx, store.AgentToolCall{
+		ID:         call.ID,
+		RunID:      call.RunID,
+		RoomID:     call.RoomID,
+		ToolName:   call.ToolName,
+		ArgsJSON:   string(call.Args),
+		ResultJSON: string(call.Result),
+		ErrorText:  call.Error,
+		DurationMs: call.DurationMs,
+		CreatedAt:  call.CreatedAt,
+	})
+}
+
+func (a *sqlAgentRunStore) ListRuns(ctx context.Context, roomID string, limit, offset int) ([]agent.AgentRun, error) {
+	rows, err := a.st.ListAgentRuns(ctx, roomID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]agent.AgentRun, len(rows))
+	for i, r := range rows {
+		runs[i] = toAgentRun(r)
+	}
+	return runs, nil
+}
+
+func (a *sqlAgentRunStore) GetRun(ctx context.Context, id string) (*agent.AgentRun, error) {
+	row, err := a.st.GetAgentRun(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, agent.ErrRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	calls, err := a.st.ListRunToolCalls(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	run := toAgentRun(*row)
+	for _, c := range calls {
+		run.ToolCalls = append(run.ToolCalls, toToolCallAudit(c))
+	}
+	return &run, nil
+}
+
+func (a *sqlAgentRunStore) ListToolCalls(ctx context.Context, roomID string, q agent.ToolCallQuery) ([]agent.ToolCallAudit, error) {
+	rows, err := a.st.ListAgentToolCalls(ctx, roomID, q.Tool, q.Limit, q.Offset)
+	if err != nil {
+		return nil, err
+	}
+	calls := make([]agent.ToolCallAudit, len(rows))
+	for i, c := range rows {
+		calls[i] = toToolCallAudit(c)
+	}
+	return calls, nil
+}
+
+func toAgentRun(r store.AgentRun) agent.AgentRun {
+	run := agent.AgentRun{
+		ID:           r.ID,
+		RoomID:       r.RoomID,
+		AgentName:    r.AgentName,
+		SeqFrom:      r.SeqFrom,
+		SeqTo:        r.SeqTo,
+		InputDigest:  r.InputDigest,
+		OutputDigest: r.OutputDigest,
+		Status:       r.Status,
+		LatencyMs:    r.LatencyMs,
+		ErrorText:    r.ErrorText,
+		CreatedAt:    r.CreatedAt,
+	}
+	if r.PlanJSON != "" {
+		run.PlanJSON = json.RawMessage(r.PlanJSON)
+	}
+	return run
+}
+
+func toToolCallAudit(c store.AgentToolCall) agent.ToolCallAudit {
+	audit := agent.ToolCallAudit{
+		ID:         c.ID,
+		RunID:      c.RunID,
+		ToolName:   c.ToolName,
+		Error:      c.ErrorText,
+		DurationMs: c.DurationMs,
+		CreatedAt:  c.CreatedAt,
+		RoomID:     c.RoomID,
+	}
+	if c.ArgsJSON != "" {
+		audit.Args = json.RawMessage(c.ArgsJSON)
+	}
+	if c.ResultJSON != "" {
+		audit.Result = json.RawMessage(c.ResultJSON)
+	}
+	return audit
+}
@@ -0,0 +1,44 @@
+// Package main Bot 断线补齐的事件来源适配器
+//
+// [IN]  internal/store（LoadEventsAfter）
+// [OUT] main.go（bot.Manager.SetEventSource）
+// [POS] 启动入口的适配层，把 bot.EventSource 映射到事件存储
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// botCatchUpLimit bounds how many missed events a restarted bot replays.
+const botCatchUpLimit = 500
+
+// botEventSource adapts store.Store to bot.EventSource
+type botEventSource struct {
+	st *store.Store
+}
+
+func (s botEventSource) EventsAfter(ctx context.Context, roomID string, afterSeq int64) ([]types.Event, error) {
+	stored, err := s.st.LoadEventsAfter(ctx, roomID, afterSeq, botCatchUpLimit)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]types.Event, 0, len(stored))
+	for _, e := range stored {
+		events = append(events, types.Event{
+			RoomID:            e.RoomID,
+			Seq:               e.Seq,
+			EventID:           e.EventID,
+			EventType:         e.EventType,
+			ActorUserID:       e.ActorUserID,
+			CausationCommand:  e.CausationCommand,
+			Payload:           json.RawMessage(e.PayloadJSON),
+			ServerTimestampMs: e.ServerTime.UnixMilli(),
+			CorrelationID:     e.CorrelationID,
+		})
+	}
+	return events, nil
+}
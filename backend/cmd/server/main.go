@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -65,7 +66,12 @@ func main() {
 	}
 	defer tp.Shutdown(ctx)
 
-	db, err := store.ConnectMySQL(cfg.DBDSN)
+	db, err := store.ConnectMySQLPool(cfg.DBDSN, store.PoolConfig{
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: cfg.DBConnMaxLifetime,
+		ConnectTimeout:  cfg.DBConnectTimeout,
+	})
 	if err != nil {
 		logger.Fatal("cannot connect db", zap.Error(err))
 	}
@@ -74,11 +80,13 @@ func main() {
 
 	metrics := observability.NewMetrics(prometheus.DefaultRegisterer.(*prometheus.Registry))
 	jwtMgr := auth.NewJWTManager(cfg.JWTSecret, 24*time.Hour)
+	auth.SetHashCost(cfg.PasswordHashCost)
 
 	// Initialize RAG system
 	var retriever *rag.RuleRetriever
 	if cfg.QdrantHost != "" {
 		qdrantClient := rag.NewQdrantClient(cfg.QdrantHost, cfg.QdrantPort, cfg.QdrantCollection)
+		qdrantClient.SetHTTPSProxy(cfg.HTTPSProxy)
 
 		var embedder rag.EmbeddingProvider
 		if cfg.AutoDMLLMProvider == "gemini" {
@@ -86,15 +94,19 @@ func main() {
 				APIKey:     cfg.GeminiAPIKey,
 				BaseURL:    cfg.AutoDMLLMBaseURL,
 				Dimensions: 768,
+				HTTPSProxy: cfg.HTTPSProxy,
 			})
 		} else {
 			embedder = rag.NewOpenAIEmbedding(rag.OpenAIEmbeddingConfig{
 				APIKey:     cfg.AutoDMLLMAPIKey,
 				BaseURL:    cfg.AutoDMLLMBaseURL,
 				Dimensions: 1536,
+				HTTPSProxy: cfg.HTTPSProxy,
 			})
 		}
 		retriever = rag.NewRuleRetriever(qdrantClient, embedder)
+		retriever.SetQueryCache(cfg.RAGQueryCacheSize, cfg.RAGQueryCacheTTL)
+		retriever.SetEmbedBatchSize(cfg.RAGEmbedBatchSize)
 
 		// Initialize with rules from docs/rules directory
 		rulesDir := "../docs/rules"
@@ -136,6 +148,12 @@ func main() {
 		taskQueueAdapter = &taskQueueAdapterImpl{q: taskQueue}
 	}
 
+	prompts, err := agent.LoadPromptRegistry(cfg.AutoDMPromptTemplates, cfg.AutoDMPersona, cfg.AutoDMLanguage)
+	if err != nil {
+		logger.Fatal("cannot load AutoDM prompt templates", zap.Error(err))
+	}
+
+	agentRuns := &sqlAgentRunStore{st: st}
 	autoDM := agent.NewAutoDM(agent.Config{
 		RoomID:  "", // Will be set per-room
 		Enabled: cfg.AutoDMEnabled,
@@ -147,12 +165,33 @@ func main() {
 				Timeout:    cfg.AutoDMLLMTimeout,
 				HTTPSProxy: cfg.HTTPSProxy,
 			},
+			Language: cfg.AutoDMLanguage,
+			Allowlist: agent.ModelAllowlist{
+				Models:   cfg.AutoDMModelAllowlist,
+				BaseURLs: cfg.AutoDMBaseURLAllowlist,
+			},
+			MaxTokens: cfg.AutoDMLLMMaxTokens,
 		},
+		Memory:    agent.MemoryConfig{Store: &memoryStoreAdapter{st: st}},
+		RunStore:  agentRuns,
 		Logger:    slogLogger,
 		Retriever: retrieverAdapter,
 		TaskQueue: taskQueueAdapter,
+
+		MaxConcurrentRuns: cfg.AutoDMMaxConcurrentRuns,
+		RunQueueTimeout:   cfg.AutoDMRunQueueTimeout,
+		Metrics:           metrics,
+
+		InlineEventTypes: cfg.AutoDMInlineEventTypes,
+		TaskDedup:        st,
+
+		WhisperLLMClassifier: cfg.AutoDMWhisperLLMClassifier,
+		MaxMessageChars:      cfg.AutoDMMaxMessageChars,
+
+		Prompts: prompts,
 	})
 
+	restoreModelOverrides(ctx, st, autoDM, logger)
 	if autoDM.Enabled() {
 		logger.Info("AutoDM enabled",
 			zap.String("provider", cfg.AutoDMLLMProvider),
@@ -168,7 +207,7 @@ func main() {
 			Timeout:    cfg.AutoDMLLMTimeout,
 			HTTPSProxy: cfg.HTTPSProxy,
 		},
-	})
+	}, prompts)
 	roomMgr := room.NewRoomManager(ctx, room.RoomDeps{
 		Store:            st,
 		Logger:           logger,
@@ -176,8 +215,20 @@ func main() {
 		SnapshotInterval: cfg.SnapshotInterval,
 		AutoDM:           autoDM,
 		Composer:         composer,
+
+		NightActionTimeout: cfg.DefaultNightActionTimeout,
+		DebugCommands:      cfg.DebugCommands,
+
+		SnapshotOnPhaseChange: cfg.SnapshotOnPhaseChange,
 	})
 	defer roomMgr.Close()
+	if cfg.EventRetention > 0 {
+		purger := room.NewRetentionPurger(st, roomMgr, room.RetentionConfig{
+			Retention:         cfg.EventRetention,
+			KeepFinalSnapshot: cfg.EventRetentionKeepSnapshot,
+		}, metrics, logger)
+		go purger.Run(ctx, cfg.EventRetentionInterval)
+	}
 	if autoDM.Enabled() {
 		autoDM.SetDispatcher(roomMgr, nil)
 		autoDM.Start()
@@ -224,9 +275,18 @@ func main() {
 	}
 
 	botMgr := bot.NewManager(observability.ZapToSlog(logger))
+	botMgr.SetEventSource(botEventSource{st: st})
 	roomMgr.SetBotNotifier(botMgr)
+	if cfg.BotLLMChat && cfg.AutoDMLLMAPIKey != "" {
+		botMgr.SetChat(bot.ChatConfig{
+			Model:     autoDM.BotChatModel(),
+			MaxPerDay: cfg.BotChatMaxPerDay,
+		})
+	}
 
 	wsServer := realtime.NewWSServer(jwtMgr, st, roomMgr, logger, metrics)
+	wsServer.SetCompressionThreshold(cfg.WSCompressionThreshold)
+	wsServer.SetAllowedOrigins(cfg.CORSAllowedOrigins)
 	server := api.NewServer(st, jwtMgr, roomMgr, wsServer, logger,
 		api.WithLLMInfo(&api.LLMInfo{
 			Provider: cfg.AutoDMLLMProvider,
@@ -235,6 +295,12 @@ func main() {
 			Enabled:  cfg.AutoDMEnabled,
 		}),
 		api.WithBotManager(botMgr),
+		api.WithAllowedOrigins(cfg.CORSAllowedOrigins),
+		api.WithAuthRateLimit(cfg.AuthRateLimitBurst, float64(cfg.AuthRateLimitPerMin)),
+		api.WithPasswordPolicy(auth.PasswordPolicy{MinLength: cfg.PasswordMinLength}),
+		api.WithAgentRunStore(agentRuns),
+		api.WithModelOverrides(autoDM),
+		api.WithArchiveSigningKey([]byte(cfg.ArchiveSigningKey)),
 	)
 
 	srv := &http.Server{Addr: cfg.HTTPAddr, Handler: server.Router}
@@ -252,6 +318,12 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer shutdownCancel()
 	srv.Shutdown(shutdownCtx)
+	// Flush AutoDM memory within the same shutdown budget, before the DB closes
+	if autoDM.Enabled() {
+		if err := autoDM.Flush(shutdownCtx); err != nil {
+			logger.Warn("AutoDM flush failed", zap.Error(err))
+		}
+	}
 }
 
 // ruleRetrieverAdapter adapts rag.RuleRetriever to agent.RuleRetriever
@@ -259,8 +331,8 @@ type ruleRetrieverAdapter struct {
 	r *rag.RuleRetriever
 }
 
-func (a *ruleRetrieverAdapter) Retrieve(ctx context.Context, query string, limit int) ([]agent.RetrieveResult, error) {
-	results, err := a.r.Retrieve(ctx, query, limit)
+func (a *ruleRetrieverAdapter) Retrieve(ctx context.Context, query string, limit int, filter map[string]string) ([]agent.RetrieveResult, error) {
+	results, err := a.r.RetrieveBiased(ctx, query, limit, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -303,3 +375,25 @@ func (a *taskQueueAdapterImpl) Publish(ctx context.Context, task interface{}) er
 		return fmt.Errorf("invalid task type")
 	}
 }
+
+// memoryStoreAdapter adapts store.Store to agent.MemoryStore
+type memoryStoreAdapter struct {
+	st *store.Store
+}
+
+func (a *memoryStoreAdapter) SaveEntries(ctx context.Context, entries []agent.MemoryRecord) error {
+	rows := make([]store.MemoryEntry, len(entries))
+	for i, e := range entries {
+		rows[i] = store.MemoryEntry{
+			ID:        e.ID,
+			RoomID:    e.Metadata.RoomID,
+			EntryType: string(e.Type),
+			Content:   e.Content,
+			Phase:     e.Metadata.Phase,
+			DayNumber: e.Metadata.DayNumber,
+			Tags:      strings.Join(e.Metadata.Tags, ","),
+			CreatedAt: e.Timestamp,
+		}
+	}
+	return a.st.SaveMemoryEntries(ctx, rows)
+}
@@ -0,0 +1,30 @@
+// Package main 启动时恢复按房间的 AutoDM 模型覆盖
+//
+// [IN]  internal/store（autodm_model_overrides）
+// [OUT] main.go（AutoDM 初始化后调用）
+// [POS] 启动入口的运行时配置恢复
+package main
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// restoreModelOverrides reapplies saved overrides; ones no longer allowlisted are skipped.
+func restoreModelOverrides(ctx context.Context, st *store.Store, autoDM *agent.AutoDM, logger *zap.Logger) {
+	rows, err := st.ListModelOverrides(ctx)
+	if err != nil {
+		logger.Warn("Failed to load AutoDM model overrides", zap.Error(err))
+		return
+	}
+	for _, o := range rows {
+		override := agent.ModelOverride{Model: o.Model, BaseURL: o.BaseURL}
+		if err := autoDM.SetModelOverride(o.RoomID, override); err != nil {
+			logger.Warn("Skipping AutoDM model override", zap.String("room_id", o.RoomID), zap.Error(err))
+		}
+	}
+}
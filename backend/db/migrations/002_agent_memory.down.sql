@@ -0,0 +1,3 @@
+-- 002_agent_memory.down.sql
+
+DROP TABLE IF EXISTS agent_memory;
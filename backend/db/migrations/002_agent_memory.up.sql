@@ -0,0 +1,15 @@
+-- 002_agent_memory.up.sql
+-- AutoDM 短期记忆落盘（关停时 Flush 写入）
+
+CREATE TABLE IF NOT EXISTS agent_memory (
+    id VARCHAR(64) PRIMARY KEY,
+    room_id VARCHAR(36) NOT NULL,
+    entry_type VARCHAR(32) NOT NULL,
+    content TEXT,
+    phase VARCHAR(32),
+    day_number INT NOT NULL DEFAULT 0,
+    tags VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE INDEX idx_agent_memory_room ON agent_memory(room_id, created_at);
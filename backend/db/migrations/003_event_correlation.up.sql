@@ -0,0 +1,6 @@
+-- 003_event_correlation.up.sql
+-- 事件关联 ID：同一命令产生的所有事件共享 correlation_id
+
+ALTER TABLE events
+    ADD COLUMN correlation_id VARCHAR(36) NULL,
+    ADD INDEX idx_events_correlation (room_id, correlation_id);
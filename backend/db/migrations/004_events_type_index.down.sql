@@ -0,0 +1,9 @@
+-- 004_events_type_index.down.sql
+-- docker-entrypoint-initdb.d 会按文件名顺序执行 down（先于 up），索引不存在时须跳过
+
+SET @has_idx := (SELECT COUNT(*) FROM information_schema.STATISTICS
+    WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'events' AND INDEX_NAME = 'idx_events_room_type');
+SET @ddl := IF(@has_idx > 0, 'DROP INDEX idx_events_room_type ON events', 'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
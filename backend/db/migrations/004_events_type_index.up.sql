@@ -0,0 +1,4 @@
+-- 004_events_type_index.up.sql
+-- 按事件类型查询（LoadEventsByType）的索引
+
+CREATE INDEX idx_events_room_type ON events(room_id, event_type, seq);
@@ -0,0 +1,11 @@
+-- 005_event_hash.down.sql
+-- docker-entrypoint-initdb.d 会按文件名顺序执行 down（先于 up），列不存在时须跳过
+
+SET @has_col := (SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'events' AND COLUMN_NAME = 'hash');
+SET @ddl := IF(@has_col > 0,
+    'ALTER TABLE events DROP COLUMN prev_hash, DROP COLUMN hash',
+    'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,6 @@
+-- 005_event_hash.up.sql
+-- 事件哈希链：hash = sha256(prev_hash + 事件内容)，由房间 Actor 追加时计算，用于防篡改审计
+
+ALTER TABLE events
+    ADD COLUMN prev_hash CHAR(64) NULL,
+    ADD COLUMN hash CHAR(64) NULL;
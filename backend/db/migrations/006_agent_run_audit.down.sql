@@ -0,0 +1,18 @@
+-- 006_agent_run_audit.down.sql
+-- docker-entrypoint-initdb.d 会按文件名顺序执行 down（先于 up），索引与列不存在时须跳过
+
+DROP TABLE IF EXISTS agent_tool_calls;
+
+SET @has_idx := (SELECT COUNT(*) FROM information_schema.STATISTICS
+    WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'agent_runs' AND INDEX_NAME = 'idx_agent_runs_room');
+SET @ddl := IF(@has_idx > 0, 'DROP INDEX idx_agent_runs_room ON agent_runs', 'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @has_col := (SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'agent_runs' AND COLUMN_NAME = 'plan_json');
+SET @ddl := IF(@has_col > 0, 'ALTER TABLE agent_runs DROP COLUMN plan_json', 'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
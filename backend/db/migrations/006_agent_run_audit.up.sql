@@ -0,0 +1,22 @@
+-- 006_agent_run_audit.up.sql
+-- AutoDM 运行审计：agent_runs 增加计划 JSON 与按房间时间索引，新增工具调用审计表
+
+ALTER TABLE agent_runs
+    ADD COLUMN plan_json MEDIUMTEXT NULL;
+
+CREATE INDEX idx_agent_runs_room ON agent_runs(room_id, created_at);
+
+CREATE TABLE IF NOT EXISTS agent_tool_calls (
+    id VARCHAR(64) PRIMARY KEY,
+    run_id VARCHAR(36) NOT NULL,
+    room_id VARCHAR(36) NOT NULL,
+    tool_name VARCHAR(64) NOT NULL,
+    args_json MEDIUMTEXT,
+    result_json MEDIUMTEXT,
+    error_text TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE INDEX idx_agent_tool_calls_room ON agent_tool_calls(room_id, created_at);
+CREATE INDEX idx_agent_tool_calls_run ON agent_tool_calls(run_id);
@@ -0,0 +1,3 @@
+-- 007_autodm_model_override.down.sql
+
+DROP TABLE IF EXISTS autodm_model_overrides;
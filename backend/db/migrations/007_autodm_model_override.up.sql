@@ -0,0 +1,11 @@
+-- 007_autodm_model_override.up.sql
+-- 按房间的 AutoDM 模型覆盖（DM 运行时切换，重启后恢复）
+
+CREATE TABLE IF NOT EXISTS autodm_model_overrides (
+    room_id VARCHAR(36) PRIMARY KEY,
+    provider VARCHAR(32) NOT NULL DEFAULT '',
+    model VARCHAR(128) NOT NULL,
+    base_url VARCHAR(255) NOT NULL DEFAULT '',
+    updated_by VARCHAR(36) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,3 @@
+-- 008_autodm_task_dedup.down.sql
+
+DROP TABLE IF EXISTS autodm_task_dedup;
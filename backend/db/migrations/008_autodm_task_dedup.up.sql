@@ -0,0 +1,8 @@
+-- 008_autodm_task_dedup.up.sql
+-- AutoDM 异步事件任务的短期去重键（房间 + 事件），保证每个源事件最多入队一次
+
+CREATE TABLE IF NOT EXISTS autodm_task_dedup (
+    dedup_key VARCHAR(191) PRIMARY KEY,
+    expires_at TIMESTAMP(3) NOT NULL,
+    INDEX idx_autodm_task_dedup_expires (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
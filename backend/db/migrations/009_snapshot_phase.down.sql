@@ -0,0 +1,3 @@
+-- 009_snapshot_phase.down.sql
+
+ALTER TABLE snapshots DROP COLUMN phase;
@@ -0,0 +1,4 @@
+-- 009_snapshot_phase.up.sql
+-- 快照记录生成时的游戏阶段（阶段切换快照便于回放与排查）
+
+ALTER TABLE snapshots ADD COLUMN phase VARCHAR(32) NOT NULL DEFAULT '' AFTER last_seq;
@@ -0,0 +1,3 @@
+-- 010_storyteller_notes.down.sql
+
+DROP TABLE IF EXISTS storyteller_notes;
@@ -0,0 +1,12 @@
+-- 010_storyteller_notes.up.sql
+-- 说书人笔记：DM 按房间的私有草稿 (下一步毒谁、怀疑对象)，不是游戏事件，不进事件流也不投影给玩家
+
+CREATE TABLE IF NOT EXISTS storyteller_notes (
+    id VARCHAR(36) PRIMARY KEY,
+    room_id VARCHAR(36) NOT NULL,
+    author_id VARCHAR(36) NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE INDEX idx_storyteller_notes_room ON storyteller_notes(room_id, created_at);
@@ -0,0 +1,3 @@
+-- 011_room_chain_head.down.sql
+
+ALTER TABLE room_sequences DROP COLUMN chain_head;
@@ -0,0 +1,4 @@
+-- 011_room_chain_head.up.sql
+-- 保留期清理删除房间事件后保留哈希链头：清理后追加的事件从 room_sequences.chain_head 接续
+
+ALTER TABLE room_sequences ADD COLUMN chain_head CHAR(64) NULL;
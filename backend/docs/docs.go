@@ -55,7 +55,7 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {"$ref": "#/definitions/AuthResponse"}
                     },
-                    "400": {"description": "invalid json"},
+                    "400": {"description": "invalid json, password too weak or too long (over 72 bytes)"},
                     "409": {"description": "user exists or db error"}
                 }
             }
@@ -121,7 +121,8 @@ const docTemplate = `{
                         "schema": {"$ref": "#/definitions/JoinRoomResponse"}
                     },
                     "401": {"description": "unauthorized"},
-                    "404": {"description": "room not found"}
+                    "404": {"description": "room not found"},
+                    "500": {"description": "db error"}
                 }
             }
         },
@@ -0,0 +1,41 @@
+// Package agent Auto-DM 自身发言的识别
+//
+// Auto-DM 的公开消息、私聊、复盘与身份声明记录会作为事件回到 OnEvent；若再次处理会形成反馈回路。
+// 作者判定统一走 isAutoDMActor：actor 为任一 Auto-DM id 变体 (types.IsAutoDMActor)，
+// 或 payload 的 from 标为 Auto-DM (人类 DM 转发、兜底命令等 actor 不一致的情况)。
+//
+// [IN]  internal/types（Event、IsAutoDMActor）
+// [OUT] autodm.go（OnEvent 跳过自身回声）
+// [POS] 防止 Auto-DM 处理自己产生的聊天事件
+package agent
+
+import (
+	"encoding/json"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// autoDMEchoTypes are the event types the Auto-DM produces by talking.
+var autoDMEchoTypes = map[string]bool{
+	"public.chat":  true,
+	"whisper.sent": true,
+	"game.recap":   true,
+	"claim.made":   true,
+}
+
+// isAutoDMActor reports whether ev was authored by the Auto-DM, by actor id or payload "from".
+func isAutoDMActor(ev types.Event) bool {
+	if types.IsAutoDMActor(ev.ActorUserID) {
+		return true
+	}
+	var payload struct {
+		From string `json:"from"`
+	}
+	_ = json.Unmarshal(ev.Payload, &payload)
+	return types.IsAutoDMActor(payload.From)
+}
+
+// isAutoDMEcho reports whether ev is the Auto-DM's own chat coming back and must be skipped.
+func isAutoDMEcho(ev types.Event) bool {
+	return autoDMEchoTypes[ev.EventType] && isAutoDMActor(ev)
+}
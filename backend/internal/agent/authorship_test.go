@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+type recordingQueue struct{ tasks []interface{} }
+
+func (q *recordingQueue) Publish(_ context.Context, task interface{}) error {
+	q.tasks = append(q.tasks, task)
+	return nil
+}
+
+func TestAutoDMAuthoredEventsAreNeverReprocessed(t *testing.T) {
+	queue := &recordingQueue{}
+	a := NewAutoDM(Config{Enabled: true, TaskQueue: queue})
+	chat := func(eventType, actor, from string) types.Event {
+		payload, _ := json.Marshal(map[string]string{"message": "天黑请闭眼", "from": from})
+		return types.Event{RoomID: "room-1", EventType: eventType, ActorUserID: actor, Payload: payload}
+	}
+
+	for _, ev := range []types.Event{
+		chat("public.chat", "autodm", ""),
+		chat("public.chat", "auto-dm", ""),
+		chat("public.chat", "dm", "auto-dm"),
+		chat("whisper.sent", "dm", "autodm"),
+		chat("game.recap", "auto-dm", "auto-dm"),
+	} {
+		a.OnEvent(context.Background(), ev, nil)
+		if len(queue.tasks) != 0 {
+			t.Fatalf("expected Auto-DM authored %s (actor %q, payload %s) to be skipped", ev.EventType, ev.ActorUserID, ev.Payload)
+		}
+	}
+
+	a.OnEvent(context.Background(), chat("public.chat", "p1", ""), nil)
+	if len(queue.tasks) != 1 {
+		t.Fatalf("expected a player's chat to be processed, got %d tasks", len(queue.tasks))
+	}
+}
@@ -6,6 +6,7 @@
 // [IN]  internal/agent/tools（工具注册与执行）
 // [IN]  internal/engine（游戏状态类型）
 // [IN]  internal/mcp（MCP 工具注册表）
+// [IN]  internal/observability（运行槽位指标）
 // [IN]  internal/types（事件与命令类型）
 // [OUT] room（事件回调与命令代理）
 // [POS] AI 自动主持人对外 API，连接游戏引擎与 AI 系统
@@ -26,9 +27,11 @@ import (
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/core"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/memory"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/subagent"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/tools"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/mcp"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/observability"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
 )
 
@@ -49,6 +52,46 @@ type AutoDM struct {
 	taskQueue    TaskQueue
 	eventTimeout time.Duration
 	mcpRegistry  *mcp.Registry
+	inflight     sync.WaitGroup // in-flight ProcessQueuedEvent calls and translation whispers, drained by Flush
+
+	// language is the default narration language; roomLanguages holds rooms whose
+	// narration_language setting overrides it (language.go)
+	language      string
+	roomLanguages map[string]string
+
+	// translator and per-room prefs drive localized announcement whispers (translation.go);
+	// translateCtx is cancelled by Stop to abandon translations still running
+	translator       Translator
+	translationPrefs map[string]translationPrefs
+	translateCtx     context.Context
+	stopTranslations context.CancelFunc
+
+	// discussions tracks per-room silence for discussion nudges (discussion_nudge.go)
+	discussions map[string]*discussionWatch
+
+	// runStore records runs and tool calls for inspection (run_audit.go); nil disables it
+	runStore AgentRunStore
+
+	// runSlots bounds concurrent orchestrator runs across rooms (run_limiter.go); nil = unlimited
+	runSlots *runLimiter
+
+	// inlineEvents are event types OnEvent never enqueues (processing_policy.go)
+	inlineEvents inlinePolicy
+
+	// taskDedup ensures each source event is enqueued at most once (task_dedup.go)
+	taskDedup TaskDeduper
+
+	// whisperClassifier settles ambiguous DM whispers (whisper_classifier.go); nil = keywords only
+	whisperClassifier WhisperClassifier
+
+	// maxMessageChars hard-caps broadcast messages (message_cap.go); 0 = unlimited
+	maxMessageChars int
+
+	// turningPoints are each room's deaths and executions for the end-of-game recap (game_recap.go)
+	turningPoints map[string][]turningPoint
+
+	// pacing holds each room's phase, nomination and chat timestamps (pacing.go)
+	pacing map[string][]subagent.PacingSample
 }
 
 // CommandDispatcher dispatches commands to the game engine.
@@ -60,10 +103,14 @@ type CommandDispatcher interface {
 type LLMRoutingConfig = llm.RoutingConfig
 type LLMClientConfig = llm.Config
 type MemoryConfig = memory.Config
+type ModelOverride = llm.ModelOverride
+type ModelAllowlist = llm.ModelAllowlist
+type PromptRegistry = subagent.PromptRegistry
 
-// RuleRetriever interface for RAG
+// RuleRetriever interface for RAG.
+// filter biases results toward matching chunk metadata (e.g. {"role_name": "slayer"}); nil means no bias.
 type RuleRetriever interface {
-	Retrieve(ctx context.Context, query string, limit int) ([]RetrieveResult, error)
+	Retrieve(ctx context.Context, query string, limit int, filter map[string]string) ([]RetrieveResult, error)
 }
 
 // RetrieveResult is the result from RAG retrieval
@@ -94,6 +141,36 @@ type Config struct {
 	Enabled   bool
 	Retriever RuleRetriever
 	TaskQueue TaskQueue
+
+	// RunStore records AutoDM runs and tool calls (optional)
+	RunStore AgentRunStore
+
+	// MaxActionsPerRun caps the orchestrator actions per event (0 = unlimited)
+	MaxActionsPerRun int
+
+	// MaxConcurrentRuns caps orchestrator runs across all rooms (0 = unlimited);
+	// excess runs wait up to RunQueueTimeout (default 30s) for a slot
+	MaxConcurrentRuns int
+	RunQueueTimeout   time.Duration
+	Metrics           *observability.Metrics
+
+	// InlineEventTypes are processed inline even when TaskQueue is set ("phase.*" matches a prefix)
+	InlineEventTypes []string
+
+	// TaskDedup claims room+event keys before enqueueing; nil uses an in-process set
+	TaskDedup TaskDeduper
+
+	// WhisperClassifier settles DM whispers the keyword heuristic is unsure about (optional);
+	// WhisperLLMClassifier builds one on LLM when WhisperClassifier is nil
+	WhisperClassifier    WhisperClassifier
+	WhisperLLMClassifier bool
+
+	// MaxMessageChars truncates AutoDM messages before broadcasting (0 = unlimited);
+	// LLM.MaxTokens bounds what the model is asked to produce
+	MaxMessageChars int
+
+	// Prompts overrides sub-agent system prompts (nil = built-in templates)
+	Prompts *PromptRegistry
 }
 
 // NewAutoDM creates a new Auto-DM instance.
@@ -111,6 +188,9 @@ func NewAutoDM(cfg Config) *AutoDM {
 		LLMConfig:    cfg.LLM,
 		MemoryConfig: cfg.Memory,
 		Logger:       cfg.Logger,
+
+		MaxActionsPerRun: cfg.MaxActionsPerRun,
+		Prompts:          cfg.Prompts,
 	})
 
 	a := &AutoDM{
@@ -120,6 +200,35 @@ func NewAutoDM(cfg Config) *AutoDM {
 		retriever:    cfg.Retriever,
 		taskQueue:    cfg.TaskQueue,
 		eventTimeout: eventTimeout,
+
+		language:      cfg.LLM.Language,
+		roomLanguages: make(map[string]string),
+
+		translator:       newRouterTranslator(cfg.LLM),
+		translationPrefs: make(map[string]translationPrefs),
+
+		discussions: make(map[string]*discussionWatch),
+
+		turningPoints: make(map[string][]turningPoint),
+		pacing:        make(map[string][]subagent.PacingSample),
+
+		runStore: cfg.RunStore,
+
+		runSlots: newRunLimiter(cfg.MaxConcurrentRuns, cfg.RunQueueTimeout, cfg.Metrics),
+
+		inlineEvents: newInlinePolicy(cfg.InlineEventTypes),
+		taskDedup:    cfg.TaskDedup,
+
+		whisperClassifier: cfg.WhisperClassifier,
+
+		maxMessageChars: cfg.MaxMessageChars,
+	}
+	a.translateCtx, a.stopTranslations = context.WithCancel(context.Background())
+	if a.taskDedup == nil {
+		a.taskDedup = newMemoryTaskDeduper()
+	}
+	if a.whisperClassifier == nil && cfg.WhisperLLMClassifier {
+		a.whisperClassifier = NewLLMWhisperClassifier(cfg.LLM)
 	}
 	a.initMCPRegistry()
 	return a
@@ -159,11 +268,14 @@ func (a *AutoDM) SetRulesProvider(rules tools.RulesProvider) {
 
 // Start activates the Auto-DM.
 func (a *AutoDM) Start() {
+	a.resumeTranslations()
 	a.orchestrator.Start()
 }
 
 // Stop deactivates the Auto-DM.
 func (a *AutoDM) Stop() {
+	a.stopTranslating()
+	a.forgetRoomLanguages()
 	a.orchestrator.Stop()
 }
 
@@ -290,13 +402,21 @@ func (a *AutoDM) OnEvent(ctx context.Context, ev types.Event, state interface{})
 	if !a.Enabled() {
 		return
 	}
-	if (ev.ActorUserID == "autodm" || ev.ActorUserID == "auto-dm") &&
-		(ev.EventType == "public.chat" || ev.EventType == "whisper.sent" || ev.EventType == "game.recap") {
+	if isAutoDMEcho(ev) {
 		return
 	}
 	a.updateGameStateFromEngineState(state)
+	a.recordTurningPoint(ev, state)
+	a.trackPacing(ev, state)
+	a.rememberRoomLanguage(state)
+	a.rememberTranslationPrefs(state)
+	a.watchDiscussion(state)
+	if pausedByHumanDM(state) {
+		return
+	}
+	a.detectClaim(ev, state)
 
-	if a.publishAsyncTask(ctx, ev) {
+	if !a.inlineEvents.inline(ev.EventType) && a.publishAsyncTask(ctx, ev) {
 		return
 	}
 	if err := a.ProcessQueuedEvent(ctx, ev); err != nil {
@@ -306,20 +426,44 @@ func (a *AutoDM) OnEvent(ctx context.Context, ev types.Event, state interface{})
 
 // ProcessQueuedEvent executes an event that was dequeued by RabbitMQ workers.
 // It bypasses queue publish to avoid enqueue loops.
-func (a *AutoDM) ProcessQueuedEvent(ctx context.Context, ev types.Event) error {
+func (a *AutoDM) ProcessQueuedEvent(ctx context.Context, ev types.Event) (err error) {
 	if !a.Enabled() {
 		return nil
 	}
-
-	event := a.convertEvent(ev)
+	a.inflight.Add(1)
+	defer a.inflight.Done()
+	ctx = llm.WithRoom(ctx, ev.RoomID) // applies the room's model override (model_override.go)
+	lang := a.languageFor(ev.RoomID)
+	ctx = llm.WithLanguage(ctx, lang)
+	ctx, run := a.startRun(ctx, ev)
+	defer func() { a.finishRun(ctx, run, err); a.reflectOnFailure(ctx, ev, err) }()
+
+	if notice, ok := nightTimeoutNotice(ev); ok {
+		recordPlan(ctx, "night_timeout_notice", notice)
+		a.sendMessage(ctx, ev.RoomID, notice)
+		return nil
+	}
+	if tally, ok := voteTallyNotice(lang, ev); ok {
+		recordPlan(ctx, "vote_tally", tally)
+		a.sendMessage(ctx, ev.RoomID, tally)
+		return nil
+	}
+	if to, result, ok := nightResultWhisper(lang, ev); ok {
+		recordPlan(ctx, "night_result_whisper", map[string]string{"to_user_id": to})
+		a.whisper(ev.RoomID, to, result)
+		return nil
+	}
+	event, ok := a.buildOrchestratorEvent(ev)
+	if !ok {
+		return nil
+	}
+	a.tagWhisperKind(ctx, &event) // whisper_classifier.go
 	a.injectRuleContext(ctx, &event)
 
-	processCtx, cancel := context.WithTimeout(ctx, a.eventTimeout)
-	defer cancel()
-
-	resp, err := a.ProcessEvent(processCtx, event)
+	resp, err := a.processLimited(ctx, event)
+	recordPlan(ctx, "orchestrator", resp)
 	if err != nil {
-		if fallback := defaultMessageForEvent(ev.EventType); fallback != "" {
+		if fallback := defaultMessageForEvent(lang, ev.EventType); fallback != "" {
 			a.sendMessage(ctx, ev.RoomID, fallback)
 		}
 		if ev.EventType == "game.ended" {
@@ -328,6 +472,10 @@ func (a *AutoDM) ProcessQueuedEvent(ctx context.Context, ev types.Event) error {
 		return err
 	}
 
+	if asker, _, ok := dmQuestion(ev); ok {
+		a.answerDMQuestion(ctx, ev.RoomID, asker, resp)
+		return nil
+	}
 	if resp != nil && resp.ShouldSpeak && resp.Message != "" {
 		a.sendMessage(ctx, ev.RoomID, resp.Message)
 	}
@@ -344,6 +492,9 @@ func (a *AutoDM) publishAsyncTask(ctx context.Context, ev types.Event) bool {
 	if taskQueue == nil {
 		return false
 	}
+	if !a.claimAsyncTask(ctx, ev) {
+		return true // already enqueued once (task_dedup.go)
+	}
 
 	task := AsyncEventTask{
 		Type:   autoDMEventTaskType,
@@ -401,8 +552,23 @@ func (a *AutoDM) convertEvent(ev types.Event) Event {
 		if executed, ok := event.Data["executed"]; ok {
 			event.Data["player_name"] = executed
 		}
+	case "player.died":
+		// Daytime ability deaths are narrated at once; night deaths are batched into dawn.summary
+		if cause, _ := event.Data["cause"].(string); cause == "slayer" || cause == "virgin_ability" {
+			event.Type = "death"
+			event.Data["player_name"] = event.Data["user_id"]
+		}
+	case "dawn.summary":
+		event.Type = "death"
+		event.Data["cause"] = "night"
+		event.Data["player_name"] = formatDawnNames(event.Data["names"])
 	case "game.started", "game.ended":
 		event.Type = "phase_change"
+	case "whisper.sent":
+		if _, question, ok := dmQuestion(ev); ok {
+			event.Type = "question" // dm_whisper.go
+			event.Data["question"] = question
+		}
 	}
 
 	event.PlayerID = ev.ActorUserID
@@ -430,7 +596,7 @@ func (a *AutoDM) injectRuleContext(ctx context.Context, event *Event) {
 		return
 	}
 
-	query := buildRuleQuery(*event)
+	query, filter := buildRuleQuery(*event)
 	if query == "" {
 		return
 	}
@@ -438,7 +604,7 @@ func (a *AutoDM) injectRuleContext(ctx context.Context, event *Event) {
 	retrieveCtx, cancel := context.WithTimeout(ctx, 1500*time.Millisecond)
 	defer cancel()
 
-	results, err := retriever.Retrieve(retrieveCtx, query, 2)
+	results, err := retriever.Retrieve(retrieveCtx, query, 2, filter)
 	if err != nil || len(results) == 0 {
 		return
 	}
@@ -462,24 +628,36 @@ func (a *AutoDM) injectRuleContext(ctx context.Context, event *Event) {
 	event.Description = event.Description + "\nRelevant rule context:\n- " + strings.Join(snippets, "\n- ")
 }
 
-func buildRuleQuery(event Event) string {
+// buildRuleQuery returns the rule search query for an event and, when the event
+// concerns a specific role, a role_name filter to bias retrieval toward that role.
+func buildRuleQuery(event Event) (string, map[string]string) {
+	filter := ruleRoleFilter(event)
 	switch event.Type {
 	case "phase_change":
 		if nightType, ok := event.Data["night_type"].(string); ok && nightType == "first_night" {
-			return "first night setup rules in Blood on the Clocktower"
+			return "first night setup rules in Blood on the Clocktower", filter
 		}
 		if phase, ok := event.Data["new_phase"].(string); ok && phase != "" {
-			return "phase transition to " + phase + " in Blood on the Clocktower"
+			return "phase transition to " + phase + " in Blood on the Clocktower", filter
 		}
-		return "phase transition in Blood on the Clocktower"
+		return "phase transition in Blood on the Clocktower", filter
 	case "nomination":
-		return "nomination and voting rules in Blood on the Clocktower"
+		return "nomination and voting rules in Blood on the Clocktower", filter
 	case "vote":
-		return "voting threshold and ghost vote rules in Blood on the Clocktower"
+		return "voting threshold and ghost vote rules in Blood on the Clocktower", filter
 	case "death":
-		return "execution and death resolution rules in Blood on the Clocktower"
+		return "execution and death resolution rules in Blood on the Clocktower", filter
+	case "question":
+		if kind, _ := event.Data["whisper_kind"].(string); kind == string(WhisperRules) {
+			question, _ := event.Data["question"].(string)
+			return question, filter
+		}
+		return "", nil
 	default:
-		return ""
+		if filter != nil {
+			return filter["role_name"] + " ability rules in Blood on the Clocktower", filter
+		}
+		return "", nil
 	}
 }
 
@@ -503,6 +681,11 @@ func formatEventDescription(eventType string, data map[string]interface{}) strin
 		return "The game has started"
 	case "game.ended":
 		return "The game has ended"
+	case "whisper.sent":
+		if q, ok := data["question"].(string); ok {
+			return fmt.Sprintf("%v whispers to DM: %s", data["sender_name"], q)
+		}
+		return eventType
 	default:
 		return eventType
 	}
@@ -512,6 +695,8 @@ func (a *AutoDM) sendMessage(ctx context.Context, roomID, message string) {
 	if strings.TrimSpace(message) == "" || strings.TrimSpace(roomID) == "" {
 		return
 	}
+	message = capMessage(message, a.maxMessageChars)
+	defer a.translateAsync(ctx, roomID, message)
 
 	a.mu.RLock()
 	registry := a.mcpRegistry
@@ -521,11 +706,12 @@ func (a *AutoDM) sendMessage(ctx context.Context, roomID, message string) {
 			"room_id": roomID,
 			"message": message,
 		})
-		result := registry.Invoke(ctx, mcp.ToolCall{
+		result := a.invokeTool(ctx, registry, mcp.ToolCall{
 			ID:         generateCommandID(),
 			ToolName:   "send_public_message",
 			Parameters: params,
 			Timestamp:  time.Now().UnixMilli(),
+			RoomID:     roomID,
 		})
 		if result.Success {
 			return
@@ -556,38 +742,26 @@ func generateCommandID() string {
 	return "autodm-" + uuid.NewString()
 }
 
-func defaultMessageForEvent(eventType string) string {
-	switch eventType {
-	case "phase.day":
-		return "☀️ 天亮了，开始讨论并寻找隐藏的邪恶吧。"
-	case "phase.night":
-		return "🌙 夜幕降临，请等待夜晚行动结算。"
-	case "nomination.created":
-		return "📣 提名已发起，请进行陈述与投票。"
-	case "game.started":
-		return "🎲 游戏开始，愿好运站在你这边。"
-	case "game.ended":
-		return "🏁 对局结束，感谢各位参与。"
-	default:
-		return ""
-	}
-}
-
 func (a *AutoDM) publishGameRecap(ctx context.Context, ev types.Event) {
 	recapCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.eventTimeout)
 	defer cancel()
 
-	summary, err := a.GetSummary(recapCtx, false)
+	points := a.takeTurningPoints(ev.RoomID)
+	// The game's last announcement has gone out
+	a.forgetTranslationPrefs(ev.RoomID)
+	a.forgetRoomLanguage(ev.RoomID)
+	summary, err := a.composeGameRecap(recapCtx, ev, points) // game_recap.go
 	if err != nil {
 		a.logger.Error("AutoDM failed to generate game recap", "error", err, "room_id", ev.RoomID)
 	}
 	summary = strings.TrimSpace(summary)
 	if summary == "" {
-		summary = a.buildFallbackGameRecap(ev)
+		summary = a.buildFallbackGameRecap(ev, points)
 	}
 	if strings.TrimSpace(summary) == "" {
 		return
 	}
+	a.rememberGameRecap(recapCtx, ev.RoomID, summary)
 
 	payload, _ := json.Marshal(map[string]interface{}{
 		"event_type": "game.recap",
@@ -609,7 +783,7 @@ func (a *AutoDM) publishGameRecap(ctx context.Context, ev types.Event) {
 	}
 }
 
-func (a *AutoDM) buildFallbackGameRecap(ev types.Event) string {
+func (a *AutoDM) buildFallbackGameRecap(ev types.Event, points []turningPoint) string {
 	winner, reason := parseWinnerAndReason(ev.Payload)
 	state := a.currentEngineState()
 	if state == nil {
@@ -646,6 +820,7 @@ func (a *AutoDM) buildFallbackGameRecap(ev types.Event) string {
 			parts = append(parts, fmt.Sprintf("最后被处决的是%d号。", player.SeatNumber))
 		}
 	}
+	parts = append(parts, fallbackRecapDetails(state, points)...)
 
 	return strings.Join(parts, " ")
 }
@@ -1011,6 +1186,8 @@ func (a *AutoDM) initMCPRegistry() {
 		}
 		return map[string]string{"status": "written", "event_type": p.EventType}, nil
 	})
+	a.registerPeekTool(registry)
+	_ = mcp.RegisterBatchTool(registry)
 
 	a.mu.Lock()
 	a.mcpRegistry = registry
@@ -1045,6 +1222,7 @@ func (a *AutoDM) updateGameStateFromEngineState(raw interface{}) {
 		Phase:       string(state.Phase),
 		DayNumber:   state.DayCount,
 		Edition:     state.Edition,
+		Script:      state.Script,
 		IsStarted:   state.Phase != engine.PhaseLobby,
 		IsFinished:  state.Phase == engine.PhaseEnded,
 		Players:     make([]Player, 0, len(state.Players)),
@@ -0,0 +1,50 @@
+// autodm_flush.go — AutoDM 关停落盘
+//
+// [IN]  internal/agent/core（编排器 Flush：最终摘要 + 记忆持久化）
+// [OUT] cmd/server（SIGTERM 时在关停超时内调用）
+// [POS] 优雅关停层，保证最后一天的上下文不会随进程退出丢失
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/memory"
+)
+
+// Type aliases so callers can implement memory persistence without importing memory.
+type MemoryStore = memory.Store
+type MemoryRecord = memory.Entry
+
+// Flush waits for in-flight event processing, then writes a final summary
+// and persists pending memory. Bounded by ctx; call before Stop.
+func (a *AutoDM) Flush(ctx context.Context) error {
+	if err := a.waitInflight(ctx); err != nil {
+		a.logger.Warn("AutoDM flush: in-flight events still running", "error", err)
+	}
+	if err := a.orchestrator.Flush(ctx); err != nil {
+		return fmt.Errorf("agent.Flush: %w", err)
+	}
+	return nil
+}
+
+// waitInflight blocks until all ProcessQueuedEvent calls return or ctx ends.
+func (a *AutoDM) waitInflight(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				a.logger.Error("AutoDM flush: wait panicked", "panic", r)
+			}
+		}()
+		a.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
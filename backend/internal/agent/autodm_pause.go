@@ -0,0 +1,18 @@
+// Package agent 人类 DM 接管后的暂停
+//
+// 引擎在人类 DM 发出推进流程类命令时记录 autodm.paused (State.AutoDMPaused)。暂停期间
+// OnEvent 仍更新状态视图、翻译偏好与讨论计时 (计时随暂停停止)，但不处理事件、不发言；
+// resume_autodm 产生 autodm.resumed 后恢复。
+//
+// [IN]  internal/engine（State.AutoDMPaused）
+// [OUT] autodm.go（OnEvent 观察后按房间暂停状态决定是否行动）
+// [POS] Auto-DM 与人类 DM 的控制权交接
+package agent
+
+import "github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+
+// pausedByHumanDM reports whether a human DM has taken over the room in raw engine state.
+func pausedByHumanDM(raw interface{}) bool {
+	state, ok := raw.(engine.State)
+	return ok && state.AutoDMPaused
+}
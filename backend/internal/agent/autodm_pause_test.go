@@ -0,0 +1,33 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestHumanDMTakeoverStopsAutoDMActions(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true})
+	dispatcher := &recordingDispatcher{}
+	a.SetDispatcher(dispatcher, nil)
+	a.SetTranslator(nil)
+	payload, _ := json.Marshal(map[string]string{"result": "not_on_the_block", "votes_for": "1", "threshold": "3"})
+	tally := types.Event{RoomID: "room-1", Seq: 9, EventType: "nomination.resolved", Payload: payload}
+
+	state := engine.NewState("room-1")
+	state.Players["dm"] = engine.Player{UserID: "dm", IsDM: true}
+	state.Reduce(engine.EventPayload{Seq: 8, Type: "autodm.paused", Actor: "dm", Payload: map[string]string{"by_user_id": "dm", "command_type": "advance_phase"}})
+	a.OnEvent(context.Background(), tally, state)
+	if len(dispatcher.cmds) != 0 {
+		t.Fatalf("expected a paused Auto-DM to stay silent, got %+v", dispatcher.cmds)
+	}
+
+	state.Reduce(engine.EventPayload{Seq: 10, Type: "autodm.resumed", Actor: "dm", Payload: map[string]string{"by_user_id": "dm"}})
+	a.OnEvent(context.Background(), tally, state)
+	if len(dispatcher.cmds) == 0 {
+		t.Fatal("expected the resumed Auto-DM to announce the tally")
+	}
+}
@@ -0,0 +1,43 @@
+// Package agent Bot 发言模型
+//
+// AutoDM.BotChatModel 让 Bot 发言走 AutoDM 自己的 LLM 路由 (bot_chat 任务，配置了 Quick 模型时走 Quick)，
+// 输出上限沿用 AUTODM_LLM_MAX_TOKENS 的 bot_chat 项，回复语言与 AutoDM 一致。每次发言与编排器运行
+// 共用跨房间的运行槽位 (run_limiter.go)，Bot 闲聊因此不会绕过 AUTODM_MAX_CONCURRENT_RUNS 挤占提供方配额。
+//
+// [IN]  internal/agent/llm（Router、TaskBotChat）
+// [IN]  run_limiter.go（runSlots）
+// [OUT] cmd/server（bot.Manager.SetChat）
+// [POS] Bot 与 LLM 路由之间的适配
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
+)
+
+// BotChatModel writes bot chat lines through the AutoDM's LLM router and run slots.
+type BotChatModel struct {
+	router *llm.Router
+	slots  *runLimiter
+}
+
+// BotChatModel returns the bot chat model sharing a's router and run limit.
+func (a *AutoDM) BotChatModel() *BotChatModel {
+	return &BotChatModel{router: a.orchestrator.Router(), slots: a.runSlots}
+}
+
+// BotChat implements bot.ChatModel.
+func (m *BotChatModel) BotChat(ctx context.Context, systemPrompt, userMessage string) (string, error) {
+	release, err := m.slots.acquire(ctx)
+	if err != nil {
+		return "", fmt.Errorf("agent.BotChatModel.BotChat: %w", err)
+	}
+	defer release()
+	out, err := m.router.SimpleChat(ctx, llm.TaskBotChat, systemPrompt, userMessage)
+	if err != nil {
+		return "", fmt.Errorf("agent.BotChatModel.BotChat: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,103 @@
+// Package agent 公开身份声明的启发式记录
+//
+// 玩家在公开聊天里自报身份 ("I'm the Empath"、"我是共情者") 时，detectClaim 以关键词启发式
+// 解析出角色，以 claim.made 事件 (user_id、role、confidence=low) 写入事件流，供复盘与玩家建模分析。
+// 声明只是参考：不经引擎校验、不影响状态，解析也可能误判或漏判。只记录对局中的在座玩家，
+// 人类 DM 接管时不写入。
+//
+// [IN]  internal/game（角色中英文名）
+// [OUT] autodm.go（OnEvent 在公开聊天时调用）
+// [POS] Auto-DM 的公开声明追踪，服务摘要与玩家建模
+package agent
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// claimPrefixesEN / claimPrefixesZH precede the role name in a self-claim.
+var (
+	claimPrefixesEN = []string{"i'm the ", "i am the ", "im the ", "i'm a ", "i am a ", "i'm an ", "i am an ", "i'm ", "i am "}
+	claimPrefixesZH = []string{"我是", "我就是", "我的身份是", "我的角色是", "本人是"}
+)
+
+// parseRoleClaim returns the role text claims for its author, if it reads like a claim.
+func parseRoleClaim(text string) (string, bool) {
+	q := strings.ToLower(strings.ReplaceAll(text, "’", "'"))
+	for _, r := range game.GetAllRoles() {
+		name := strings.ToLower(r.Name)
+		for _, prefix := range claimPrefixesEN {
+			if containsPhrase(q, prefix+name) {
+				return r.ID, true
+			}
+		}
+		for _, prefix := range claimPrefixesZH {
+			if r.NameCN != "" && strings.Contains(text, prefix+r.NameCN) {
+				return r.ID, true
+			}
+		}
+	}
+	return "", false
+}
+
+// containsPhrase reports whether phrase occurs in s between non-letters ("imp" not in "simple").
+func containsPhrase(s, phrase string) bool {
+	for from := 0; ; {
+		i := strings.Index(s[from:], phrase)
+		if i < 0 {
+			return false
+		}
+		start, end := from+i, from+i+len(phrase)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if (start == 0 || !unicode.IsLetter(before)) && (end == len(s) || !unicode.IsLetter(after)) {
+			return true
+		}
+		from = start + 1
+	}
+}
+
+// detectClaim records a claim.made event when a seated player claims a role in public chat.
+func (a *AutoDM) detectClaim(ev types.Event, raw interface{}) {
+	state, ok := raw.(engine.State)
+	if !ok || ev.EventType != "public.chat" || state.Phase == engine.PhaseLobby || state.Phase == engine.PhaseEnded {
+		return
+	}
+	player, ok := state.Players[ev.ActorUserID]
+	if !ok || player.IsDM {
+		return
+	}
+	var payload map[string]string
+	_ = json.Unmarshal(ev.Payload, &payload)
+	role, ok := parseRoleClaim(payload["message"])
+	if !ok {
+		return
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"event_type": "claim.made",
+		"data": map[string]string{
+			"user_id":    ev.ActorUserID,
+			"role":       role,
+			"confidence": "low",
+			"source":     "heuristic",
+		},
+	})
+	cmdID := generateCommandID()
+	cmd := types.CommandEnvelope{
+		CommandID:      cmdID,
+		IdempotencyKey: cmdID,
+		RoomID:         ev.RoomID,
+		Type:           "write_event",
+		ActorUserID:    "autodm",
+		Payload:        data,
+	}
+	if err := a.dispatchCommand(cmd); err != nil {
+		a.logger.Warn("AutoDM failed to record role claim", "error", err, "room_id", ev.RoomID)
+	}
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestParseRoleClaim(t *testing.T) {
+	tests := []struct {
+		text string
+		role string
+	}{
+		{"I'm the Empath, I got a 1 last night", "empath"},
+		{"ok fine, i am the fortune teller", "fortuneteller"},
+		{"我是共情者，昨晚得到 0", "empath"},
+		{"I'm not the empath", ""},
+		{"that plan is simple", ""},
+		{"I'm the imposter here, obviously", ""},
+	}
+	for _, tt := range tests {
+		role, ok := parseRoleClaim(tt.text)
+		if role != tt.role || ok != (tt.role != "") {
+			t.Errorf("parseRoleClaim(%q) = %q, %v; want %q", tt.text, role, ok, tt.role)
+		}
+	}
+}
+
+func TestPublicClaimEmitsClaimMade(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true})
+	dispatcher := &recordingDispatcher{}
+	state := engine.NewState("room-1")
+	state.Phase = engine.PhaseDay
+	state.Players["p1"] = engine.Player{UserID: "p1", Name: "Alice", Alive: true}
+	a.SetDispatcher(dispatcher, func() interface{} { return state })
+
+	payload, _ := json.Marshal(map[string]string{"message": "I'm the Empath and my neighbours look fine"})
+	a.detectClaim(types.Event{RoomID: "room-1", EventType: "public.chat", ActorUserID: "p1", Payload: payload}, state)
+
+	if len(dispatcher.cmds) != 1 || dispatcher.cmds[0].Type != "write_event" {
+		t.Fatalf("expected one write_event command, got %v", dispatcher.cmds)
+	}
+	var cmd struct {
+		EventType string            `json:"event_type"`
+		Data      map[string]string `json:"data"`
+	}
+	_ = json.Unmarshal(dispatcher.cmds[0].Payload, &cmd)
+	if cmd.EventType != "claim.made" || cmd.Data["user_id"] != "p1" || cmd.Data["role"] != "empath" || cmd.Data["confidence"] != "low" {
+		t.Fatalf("unexpected claim event: %+v", cmd)
+	}
+
+	// The recorded claim comes back as an event and must not be processed again
+	echo := types.Event{RoomID: "room-1", EventType: "claim.made", ActorUserID: "autodm"}
+	a.OnEvent(context.Background(), echo, state)
+	if len(dispatcher.cmds) != 1 {
+		t.Fatalf("expected the claim.made echo to be ignored, got %v", dispatcher.cmds)
+	}
+}
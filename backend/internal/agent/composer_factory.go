@@ -13,7 +13,7 @@ import (
 // NewComposer creates a game.Composer based on LLM config.
 // If LLM is configured, returns AI composer with random fallback.
 // Otherwise returns a pure random composer.
-func NewComposer(cfg LLMRoutingConfig) game.Composer {
+func NewComposer(cfg LLMRoutingConfig, prompts *PromptRegistry) game.Composer {
 	random := &game.RandomComposer{}
 
 	if cfg.Default.Model == "" || cfg.Default.APIKey == "" {
@@ -21,7 +21,7 @@ func NewComposer(cfg LLMRoutingConfig) game.Composer {
 	}
 
 	router := llm.NewRouterFromConfig(cfg)
-	aiComposer := subagent.NewAIComposer(router)
+	aiComposer := subagent.NewAIComposer(router, prompts)
 
 	return &game.FallbackComposer{
 		Primary:  aiComposer,
@@ -0,0 +1,105 @@
+// Package core 代理动作的确定性合并顺序
+//
+// 多个子代理的动作合并时按 Action.Priority 从高到低排序，同优先级按子代理固定次序
+// (moderator → rules → narrator → summarizer → player_modeler)，再按各自给出的顺序，
+// 保证例如旁白总在其后的阶段推进之前。排序后丢弃重复动作 (类型 + 目标 + 规范化参数相同，
+// 保留排在最前的一条，避免多个子代理重复发言)，再按 MaxActionsPerRun 截断 (0 不限)。
+//
+// [OUT] orchestrator.go（ProcessEvent 返回前合并 Response.Actions）
+// [POS] 代理动作进入引擎前的排序与限流
+package core
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Sub-agent names used to order contributions.
+const (
+	AgentModerator     = "moderator"
+	AgentRules         = "rules"
+	AgentNarrator      = "narrator"
+	AgentSummarizer    = "summarizer"
+	AgentPlayerModeler = "player_modeler"
+)
+
+// agentOrder breaks priority ties; unknown agents sort last.
+var agentOrder = map[string]int{
+	AgentModerator:     0,
+	AgentRules:         1,
+	AgentNarrator:      2,
+	AgentSummarizer:    3,
+	AgentPlayerModeler: 4,
+}
+
+// Contribution is the actions one sub-agent proposed for an event.
+type Contribution struct {
+	Agent   string
+	Actions []Action
+}
+
+// mergeContributions orders all actions by priority, then agent, then proposal order, drops
+// duplicates and keeps at most maxActions of them (0 keeps all).
+func mergeContributions(contribs []Contribution, maxActions int) []Action {
+	type ranked struct {
+		action Action
+		agent  int
+	}
+	var all []ranked
+	for _, c := range contribs {
+		rank, ok := agentOrder[c.Agent]
+		if !ok {
+			rank = len(agentOrder)
+		}
+		for _, a := range c.Actions {
+			all = append(all, ranked{action: a, agent: rank})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].action.Priority != all[j].action.Priority {
+			return all[i].action.Priority > all[j].action.Priority
+		}
+		return all[i].agent < all[j].agent
+	})
+	merged := make([]Action, 0, len(all))
+	seen := make(map[string]bool, len(all))
+	for _, r := range all {
+		key := actionKey(r.action)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, r.action)
+	}
+	if maxActions > 0 && len(merged) > maxActions {
+		merged = merged[:maxActions]
+	}
+	return merged
+}
+
+// actionKey identifies an action by type, target and arguments, ignoring whitespace
+// differences in string arguments.
+func actionKey(a Action) string {
+	data := make(map[string]interface{}, len(a.Data))
+	for k, v := range a.Data {
+		if s, ok := v.(string); ok {
+			v = strings.Join(strings.Fields(s), " ")
+		}
+		data[k] = v
+	}
+	args, _ := json.Marshal(data) // map keys marshal in sorted order
+	return a.Type + "\x00" + strings.TrimSpace(a.Target) + "\x00" + string(args)
+}
+
+// eventAgent names the sub-agent that handles an event type in routeEvent.
+func eventAgent(eventType string) string {
+	switch eventType {
+	case "phase_change", "death":
+		return AgentNarrator
+	case "question":
+		return AgentRules
+	default:
+		return AgentModerator
+	}
+}
@@ -0,0 +1,43 @@
+package core
+
+import "testing"
+
+func TestMergeOrdersByPriorityThenAgent(t *testing.T) {
+	contribs := []Contribution{
+		{Agent: AgentSummarizer, Actions: []Action{{Type: "send_message", Target: "summary", Priority: 1}}},
+		{Agent: AgentModerator, Actions: []Action{{Type: "advance_phase", Target: "night", Priority: 1}}},
+		{Agent: AgentNarrator, Actions: []Action{{Type: "send_message", Target: "narration", Priority: 5}}},
+	}
+
+	merged := mergeContributions(contribs, 0)
+	if len(merged) != 3 || merged[0].Target != "narration" || merged[1].Target != "night" || merged[2].Target != "summary" {
+		t.Fatalf("expected narration, then moderator, then summary, got %+v", merged)
+	}
+}
+
+func TestMergeAppliesMaxActionsAfterSorting(t *testing.T) {
+	contribs := []Contribution{
+		{Agent: AgentModerator, Actions: []Action{{Type: "advance_phase", Target: "night"}, {Type: "end_game"}}},
+		{Agent: AgentNarrator, Actions: []Action{{Type: "send_message", Target: "narration", Priority: 2}}},
+	}
+
+	merged := mergeContributions(contribs, 2)
+	if len(merged) != 2 || merged[0].Target != "narration" || merged[1].Target != "night" {
+		t.Fatalf("expected the narration kept ahead of the first moderator action, got %+v", merged)
+	}
+}
+
+func TestMergeCollapsesIdenticalNarration(t *testing.T) {
+	narration := func(text string) Action {
+		return Action{Type: "send_public_message", Data: map[string]interface{}{"message": text}}
+	}
+	contribs := []Contribution{
+		{Agent: AgentNarrator, Actions: []Action{narration("Dawn breaks over the village.")}},
+		{Agent: AgentModerator, Actions: []Action{narration("Dawn breaks  over the village. "), {Type: "advance_phase", Target: "day"}}},
+	}
+
+	merged := mergeContributions(contribs, 0)
+	if len(merged) != 2 || merged[0].Type != "send_public_message" || merged[1].Type != "advance_phase" {
+		t.Fatalf("expected one narration and the phase advance, got %+v", merged)
+	}
+}
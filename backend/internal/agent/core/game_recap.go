@@ -0,0 +1,43 @@
+// Package core 对局结束复盘
+//
+// 收到 game.ended 时 AutoDM 用揭晓后的魔典 (真实身份) 与对局中记下的关键节点调用 RecapGame，
+// 由摘要子代理写出全局复盘 (谁是什么身份、关键转折、获胜的一手)；RememberRecap 把最终复盘
+// 写入记忆并立即落盘，作为该房间的长期记忆保留。
+//
+// [IN]  internal/agent/subagent（Summarizer.RecapGame）
+// [IN]  internal/agent/memory（复盘写入与持久化）
+// [OUT] agent/autodm（game.ended 后发布复盘）
+// [POS] 编排器的对局结束收尾
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/memory"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/subagent"
+)
+
+// RecapGame asks the summarizer for the full-game recap of the revealed grimoire gs.
+func (o *Orchestrator) RecapGame(ctx context.Context, gs subagent.GameStateView, turningPoints []string, winner, reason string) (string, error) {
+	return o.summarizer.RecapGame(ctx, gs, turningPoints, winner, reason)
+}
+
+// RememberRecap stores the published recap in memory and persists it right away.
+func (o *Orchestrator) RememberRecap(ctx context.Context, roomID string, dayNumber int, recap string) error {
+	_ = o.memory.Add(ctx, memory.Entry{
+		Type:    memory.EntryNarration,
+		Content: recap,
+		Metadata: memory.Metadata{
+			RoomID:    roomID,
+			Phase:     "ended",
+			DayNumber: dayNumber,
+			Tags:      []string{"game_recap"},
+		},
+	})
+	if err := o.memory.Flush(ctx); err != nil {
+		return fmt.Errorf("core.RememberRecap: %w", err)
+	}
+	return nil
+}
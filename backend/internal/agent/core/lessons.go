@@ -0,0 +1,41 @@
+// Package core 反思教训的记录与注入
+//
+// RecordLessons 把反思得到的教训写入记忆 (长期保留、有界)；处理每个事件前 planView 取与该事件
+// 最相关的少量教训放进 GameStateView.Lessons，随 FormatGameState 进入子代理系统提示词。
+//
+// [IN]  internal/agent/memory（教训存取）
+// [OUT] agent/autodm（反思后记录教训）
+// [POS] 编排器的反思→记忆→提示词反馈环
+
+package core
+
+import (
+	"context"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/memory"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/subagent"
+)
+
+// RecordLessons stores reflection lessons for later prompts.
+func (o *Orchestrator) RecordLessons(ctx context.Context, lessons []string) {
+	o.mu.RLock()
+	roomID := o.roomID
+	phase := o.gameState.Phase
+	dayNumber := o.gameState.DayNumber
+	o.mu.RUnlock()
+
+	for _, l := range lessons {
+		if err := o.memory.AddLesson(ctx, roomID, phase, dayNumber, l); err != nil {
+			o.logger.Warn("Failed to record lesson", "error", err, "room_id", roomID)
+		}
+	}
+}
+
+// planView is the state view for handling event, with the lessons most relevant to it.
+func (o *Orchestrator) planView(event Event) subagent.GameStateView {
+	gs := o.toGameStateView()
+	for _, l := range o.memory.RelevantLessons(event.Type+" "+event.Description, memory.DefaultLessonLimit) {
+		gs.Lessons = append(gs.Lessons, l.Content)
+	}
+	return gs
+}
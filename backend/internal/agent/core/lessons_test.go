@@ -0,0 +1,25 @@
+package core
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/subagent"
+)
+
+func TestReflectionLessonReachesNextPlanPrompt(t *testing.T) {
+	o := New(Config{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	lesson := "A fast advance to nomination confused players; give a warning first"
+	o.RecordLessons(context.Background(), []string{"Keep death narration short", lesson})
+
+	view := o.planView(Event{Type: "phase_change", Description: "advance to nomination"})
+	if len(view.Lessons) == 0 || view.Lessons[0] != lesson {
+		t.Fatalf("expected the nomination lesson first, got %v", view.Lessons)
+	}
+	if prompt := subagent.FormatGameState(view); !strings.Contains(prompt, lesson) {
+		t.Fatalf("expected the lesson in the prompt state, got %q", prompt)
+	}
+}
@@ -40,6 +40,9 @@ type Orchestrator struct {
 	roomID    string
 	gameState *GameState
 	isActive  bool
+
+	// maxActions caps the actions returned per event (0 = unlimited)
+	maxActions int
 }
 
 // GameState represents the current game state.
@@ -80,6 +83,12 @@ type Config struct {
 	LLMConfig    llm.RoutingConfig
 	MemoryConfig memory.Config
 	Logger       *slog.Logger
+
+	// MaxActionsPerRun caps the merged actions returned per event (0 = unlimited)
+	MaxActionsPerRun int
+
+	// Prompts supplies sub-agent system prompts (nil = built-in templates)
+	Prompts *subagent.PromptRegistry
 }
 
 // New creates a new Orchestrator.
@@ -99,14 +108,25 @@ func New(cfg Config) *Orchestrator {
 		logger:        logger,
 		roomID:        cfg.RoomID,
 		gameState:     &GameState{RoomID: cfg.RoomID, Phase: "setup"},
-		moderator:     subagent.NewModerator(router),
-		narrator:      subagent.NewNarrator(router),
-		rules:         subagent.NewRules(router),
-		summarizer:    subagent.NewSummarizer(router),
-		playerModeler: subagent.NewPlayerModeler(router),
+		moderator:     subagent.NewModerator(router, cfg.Prompts),
+		narrator:      subagent.NewNarrator(router, cfg.Prompts),
+		rules:         subagent.NewRules(router, cfg.Prompts),
+		summarizer:    subagent.NewSummarizer(router, cfg.Prompts),
+		playerModeler: subagent.NewPlayerModeler(router, cfg.Prompts),
+		maxActions:    cfg.MaxActionsPerRun,
 	}
 }
 
+// Router returns the LLM router shared by every sub-agent.
+func (o *Orchestrator) Router() *llm.Router {
+	return o.router
+}
+
+// Moderator returns the moderator sub-agent.
+func (o *Orchestrator) Moderator() *subagent.Moderator {
+	return o.moderator
+}
+
 // SetCommander sets the game commander for tool execution.
 func (o *Orchestrator) SetCommander(commander tools.GameCommander) {
 	tools.RegisterGameTools(o.tools, commander, o.roomID)
@@ -167,6 +187,9 @@ type Action struct {
 	Type   string
 	Target string
 	Data   map[string]interface{}
+
+	// Priority orders merged actions; higher runs first (action_merge.go)
+	Priority int
 }
 
 // ProcessEvent handles a game event.
@@ -184,11 +207,16 @@ func (o *Orchestrator) ProcessEvent(ctx context.Context, event Event) (*Response
 	o.memory.AddEvent(ctx, roomID, phase, dayNumber, event.Description)
 	o.logger.Debug("Processing event", "type", event.Type, "description", event.Description)
 
-	return o.routeEvent(ctx, event)
+	resp, err := o.routeEvent(ctx, event)
+	if resp != nil {
+		resp.Actions = filterPhaseActions(o.logger, phase, resp.Actions)
+		resp.Actions = mergeContributions([]Contribution{{Agent: eventAgent(event.Type), Actions: resp.Actions}}, o.maxActions)
+	}
+	return resp, err
 }
 
 func (o *Orchestrator) routeEvent(ctx context.Context, event Event) (*Response, error) {
-	gsView := o.toGameStateView()
+	gsView := o.planView(event)
 
 	switch event.Type {
 	case "phase_change":
@@ -331,3 +359,37 @@ func (o *Orchestrator) AnalyzePlayers(ctx context.Context) (string, error) {
 	gsView := o.toGameStateView()
 	return o.playerModeler.IdentifySuspects(ctx, gsView)
 }
+
+// Flush writes a final DM summary into memory and persists pending
+// memory entries. A summary failure is logged but does not block persistence.
+func (o *Orchestrator) Flush(ctx context.Context) error {
+	o.mu.RLock()
+	roomID := o.roomID
+	phase := o.gameState.Phase
+	dayNumber := o.gameState.DayNumber
+	o.mu.RUnlock()
+
+	summary, err := o.GetSummary(ctx, true)
+	if err != nil {
+		o.logger.Warn("Failed to build final summary", "error", err)
+	} else if summary != "" {
+		err := o.memory.Add(ctx, memory.Entry{
+			Type:    memory.EntryNarration,
+			Content: summary,
+			Metadata: memory.Metadata{
+				RoomID:    roomID,
+				Phase:     phase,
+				DayNumber: dayNumber,
+				Tags:      []string{"final_summary"},
+			},
+		})
+		if err != nil {
+			o.logger.Warn("Failed to remember final summary", "error", err, "room_id", roomID)
+		}
+	}
+
+	if err := o.memory.Flush(ctx); err != nil {
+		return fmt.Errorf("core.Flush: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Package core 按阶段的代理动作白名单
+//
+// 模型可能幻觉出非法动作（如夜晚 advance_phase 到 nomination）。编排器在返回动作前
+// 按当前阶段过滤：动作类型须在该阶段白名单内，advance_phase/set_phase 的目标阶段须是
+// 合法转换；被丢弃的动作记录原因，不会到达引擎。未知阶段不过滤。
+//
+// [OUT] orchestrator.go（ProcessEvent 返回前过滤 Response.Actions）
+// [POS] 代理动作进入引擎前的阶段守卫
+package core
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// readOnlyActions never change game state and are allowed in every phase.
+var readOnlyActions = map[string]bool{
+	"get_game_state":    true,
+	"get_room_state":    true,
+	"get_recent_events": true,
+	"lookup_rule":       true,
+}
+
+// chatActions are allowed in every known phase.
+var chatActions = []string{"send_message", "send_public_message", "send_whisper"}
+
+// phaseAllowedActions lists the state-changing actions each phase accepts besides chat.
+var phaseAllowedActions = map[string][]string{
+	"lobby":       {},
+	"first_night": {"advance_phase", "set_phase", "kill_player", "end_game"},
+	"night":       {"advance_phase", "set_phase", "kill_player", "end_game"},
+	"day":         {"advance_phase", "set_phase", "start_nomination", "end_game"},
+	"nomination":  {"advance_phase", "set_phase", "start_nomination", "close_vote", "resolve_execution", "kill_player", "end_game"},
+	"voting":      {"advance_phase", "set_phase", "close_vote", "resolve_execution", "kill_player", "end_game"},
+	"ended":       {},
+}
+
+// phaseTransitions lists the phases advance_phase/set_phase may move to from each phase.
+var phaseTransitions = map[string][]string{
+	"first_night": {"day"},
+	"night":       {"day"},
+	"day":         {"nomination", "night"},
+	"nomination":  {"voting", "night"},
+	"voting":      {"nomination", "night"},
+}
+
+// filterPhaseActions drops actions not allowed in phase, logging why.
+func filterPhaseActions(logger *slog.Logger, phase string, actions []Action) []Action {
+	if _, known := phaseAllowedActions[phase]; !known || len(actions) == 0 {
+		return actions
+	}
+	kept := make([]Action, 0, len(actions))
+	for _, a := range actions {
+		if err := checkPhaseAction(phase, a); err != nil {
+			logger.Warn("Dropped out-of-phase action", "phase", phase, "type", a.Type, "reason", err.Error())
+			continue
+		}
+		kept = append(kept, a)
+	}
+	return kept
+}
+
+// checkPhaseAction returns why a is illegal in phase, or nil.
+func checkPhaseAction(phase string, a Action) error {
+	if readOnlyActions[a.Type] || contains(chatActions, a.Type) {
+		return nil
+	}
+	if !contains(phaseAllowedActions[phase], a.Type) {
+		return fmt.Errorf("%s not allowed during %s", a.Type, phase)
+	}
+	if a.Type != "advance_phase" && a.Type != "set_phase" {
+		return nil
+	}
+	target := actionTargetPhase(a)
+	if !contains(phaseTransitions[phase], target) {
+		return fmt.Errorf("cannot move from %s to %q", phase, target)
+	}
+	return nil
+}
+
+// actionTargetPhase reads the destination phase from Target or Data["phase"]/["to_phase"].
+func actionTargetPhase(a Action) string {
+	if a.Target != "" {
+		return a.Target
+	}
+	for _, key := range []string{"phase", "to_phase"} {
+		if v, ok := a.Data[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
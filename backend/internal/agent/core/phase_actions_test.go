@@ -0,0 +1,34 @@
+package core
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestNominationActionsDuringNightAreDropped(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	actions := []Action{
+		{Type: "advance_phase", Target: "nomination"},
+		{Type: "start_nomination"},
+		{Type: "send_message", Data: map[string]interface{}{"message": "Night falls"}},
+		{Type: "advance_phase", Data: map[string]interface{}{"phase": "day"}},
+	}
+
+	kept := filterPhaseActions(logger, "night", actions)
+	if len(kept) != 2 || kept[0].Type != "send_message" || actionTargetPhase(kept[1]) != "day" {
+		t.Fatalf("expected only the message and the move to day to survive, got %+v", kept)
+	}
+}
+
+func TestDayAllowsNominationAndUnknownPhasePassesThrough(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	actions := []Action{{Type: "advance_phase", Target: "nomination"}, {Type: "start_nomination"}}
+
+	if kept := filterPhaseActions(logger, "day", actions); len(kept) != 2 {
+		t.Fatalf("expected nomination actions allowed during day, got %+v", kept)
+	}
+	if kept := filterPhaseActions(logger, "setup", actions); len(kept) != 2 {
+		t.Fatalf("expected unknown phase to leave actions untouched, got %+v", kept)
+	}
+}
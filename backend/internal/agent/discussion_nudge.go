@@ -0,0 +1,87 @@
+// Package agent 白天讨论冷场提醒
+//
+// 每个房间在白天讨论阶段 (尚无提名) 维护一个沉默计时：任何事件都会重置计时，
+// 每沉默 Config.DiscussionNudgeSec 秒由 Moderator.DiscussionNudge 给出逐级升级的提醒
+// (温和 → 直接 → 即将推进) 并作为公开消息发出。进入提名、离开白天、人类 DM 接管或提醒用尽后停止。
+// Auto-DM 自己的公开消息不经过 OnEvent，不会重置计时。
+//
+// [IN]  internal/engine（阶段、提名与房间提醒配置）
+// [IN]  internal/agent/subagent（Moderator 提醒节奏）
+// [OUT] autodm.go（OnEvent 时更新计时）
+// [POS] Auto-DM 的讨论节奏控制
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/subagent"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+)
+
+// discussionWatch tracks one room's silence during day discussion.
+type discussionWatch struct {
+	since  time.Time
+	config subagent.NudgeConfig
+	timer  *time.Timer
+}
+
+// inDiscussion reports whether the room is in day discussion before any nomination.
+func inDiscussion(state engine.State) bool {
+	if state.Phase != engine.PhaseDay || state.Config.DiscussionNudgeSec <= 0 || state.AutoDMPaused {
+		return false
+	}
+	if state.SubPhase != engine.SubPhaseNone && state.SubPhase != engine.SubPhaseDiscussion {
+		return false
+	}
+	return state.Nomination == nil && len(state.NominationQueue) == 0
+}
+
+// watchDiscussion restarts the room's silence timer, or stops it outside discussion.
+func (a *AutoDM) watchDiscussion(raw interface{}) {
+	state, ok := raw.(engine.State)
+	if !ok || state.RoomID == "" {
+		return
+	}
+	lang := a.languageFor(state.RoomID)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if w := a.discussions[state.RoomID]; w != nil {
+		w.timer.Stop()
+		delete(a.discussions, state.RoomID)
+	}
+	if !inDiscussion(state) {
+		return
+	}
+	w := &discussionWatch{
+		since: time.Now(),
+		config: subagent.NudgeConfig{
+			Interval: time.Duration(state.Config.DiscussionNudgeSec) * time.Second,
+			Levels:   nudgeLevels(lang, state.Config.DiscussionNudgeMessage),
+		},
+	}
+	roomID := state.RoomID
+	w.timer = time.AfterFunc(w.config.Interval, func() { a.fireNudge(roomID, w) })
+	a.discussions[roomID] = w
+}
+
+// fireNudge sends the nudge due for w's silence and schedules the next one. A timer
+// that fires after its watch was replaced does nothing.
+func (a *AutoDM) fireNudge(roomID string, w *discussionWatch) {
+	a.mu.Lock()
+	if a.discussions[roomID] != w {
+		a.mu.Unlock()
+		return
+	}
+	msg, ok := a.orchestrator.Moderator().DiscussionNudge(w.config, time.Since(w.since))
+	if ok {
+		w.timer = time.AfterFunc(w.config.Interval, func() { a.fireNudge(roomID, w) })
+	} else {
+		delete(a.discussions, roomID)
+	}
+	a.mu.Unlock()
+
+	if ok && a.Enabled() {
+		a.sendMessage(context.Background(), roomID, msg)
+	}
+}
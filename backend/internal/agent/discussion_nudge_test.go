@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+)
+
+func discussionState() engine.State {
+	state := engine.NewState("room-1")
+	state.Phase = engine.PhaseDay
+	state.SubPhase = engine.SubPhaseDiscussion
+	state.Config.DiscussionNudgeSec = 10
+	return state
+}
+
+// publicMessages returns the text of every public_chat the dispatcher saw.
+func publicMessages(d *recordingDispatcher) []string {
+	var out []string
+	for _, cmd := range d.cmds {
+		if cmd.Type != "public_chat" {
+			continue
+		}
+		var p map[string]string
+		_ = json.Unmarshal(cmd.Payload, &p)
+		out = append(out, p["message"])
+	}
+	return out
+}
+
+func TestDiscussionNudgeEscalatesAndStopsAtNomination(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true})
+	dispatcher := &recordingDispatcher{}
+	a.SetDispatcher(dispatcher, nil)
+	a.SetTranslator(nil)
+
+	a.watchDiscussion(discussionState())
+	w := a.discussions["room-1"]
+	if w == nil {
+		t.Fatal("expected a silence watch during day discussion")
+	}
+	w.since = time.Now().Add(-10 * time.Second)
+	a.fireNudge("room-1", w)
+	w.since = time.Now().Add(-20 * time.Second)
+	a.fireNudge("room-1", w)
+
+	msgs := publicMessages(dispatcher)
+	if len(msgs) != 2 || msgs[0] == msgs[1] {
+		t.Fatalf("expected two nudges of different tone, got %q", msgs)
+	}
+
+	nominated := discussionState()
+	nominated.Nomination = &engine.Nomination{Nominator: "p1", Nominee: "p2"}
+	a.watchDiscussion(nominated)
+	a.fireNudge("room-1", w)
+	if _, ok := a.discussions["room-1"]; ok || len(publicMessages(dispatcher)) != 2 {
+		t.Fatal("expected nudging to stop once a nomination starts")
+	}
+}
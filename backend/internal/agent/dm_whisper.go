@@ -0,0 +1,40 @@
+// Package agent 玩家私聊 Auto-DM 的处理
+//
+// 玩家私聊说书人 (whisper to_user_id="dm"，无人类 DM 时由 engine 解析为 Auto-DM) 时，
+// convertEvent 把发给 Auto-DM 的 whisper.sent 转为 question 事件交给规则 Agent，
+// 回答私聊回提问者而不是公开发言。玩家之间、或发给人类 DM 的私聊不受影响。
+//
+// [IN]  engine（whisper 的 to_dm 标记与收件人解析）
+// [IN]  message_cap.go（回答长度上限）
+// [OUT] autodm.go（convertEvent 转换、ProcessQueuedEvent 私聊回答）
+// [POS] AutoDM 私聊提问入口
+package agent
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// dmQuestion reports whether ev is a player's whisper to the Auto-DM, returning the asker and question.
+func dmQuestion(ev types.Event) (asker, question string, ok bool) {
+	if ev.EventType != "whisper.sent" || types.IsAutoDMActor(ev.ActorUserID) {
+		return "", "", false
+	}
+	var payload map[string]string
+	_ = json.Unmarshal(ev.Payload, &payload)
+	if payload["to_dm"] != "true" || !types.IsAutoDMActor(payload["to_user_id"]) || payload["message"] == "" {
+		return "", "", false
+	}
+	return ev.ActorUserID, payload["message"], true
+}
+
+// answerDMQuestion whispers the orchestrator's answer back to the asker.
+func (a *AutoDM) answerDMQuestion(ctx context.Context, roomID, asker string, resp *Response) {
+	if resp == nil || resp.Message == "" {
+		return
+	}
+	recordPlan(ctx, "dm_question_answer", map[string]string{"to_user_id": asker})
+	a.whisper(roomID, asker, capMessage(resp.Message, a.maxMessageChars))
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func whisperEvent(actor string, payload map[string]string) types.Event {
+	raw, _ := json.Marshal(payload)
+	return types.Event{RoomID: "room-1", Seq: 5, EventType: "whisper.sent", ActorUserID: actor, Payload: raw}
+}
+
+func TestWhisperToAutoDMBecomesRulesQuestion(t *testing.T) {
+	a := &AutoDM{}
+	ev := whisperEvent("p1", map[string]string{
+		"to_user_id": types.AutoDMActorID, "to_dm": "true", "message": "Can the dead vote?", "sender_name": "Alice",
+	})
+	event := a.convertEvent(ev)
+	if event.Type != "question" || !strings.Contains(event.Description, "Can the dead vote?") {
+		t.Fatalf("expected a question carrying the whisper, got %q %q", event.Type, event.Description)
+	}
+
+	for name, other := range map[string]types.Event{
+		"player to player": whisperEvent("p1", map[string]string{"to_user_id": "p2", "message": "psst"}),
+		"to human DM":      whisperEvent("p1", map[string]string{"to_user_id": "host", "to_dm": "true", "message": "hi"}),
+		"Auto-DM reply":    whisperEvent(types.AutoDMActorID, map[string]string{"to_user_id": "p1", "message": "yes"}),
+	} {
+		if got := a.convertEvent(other).Type; got == "question" {
+			t.Fatalf("%s: expected no question event", name)
+		}
+	}
+}
+
+func TestDMQuestionAnswerIsWhisperedToAsker(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true})
+	dispatcher := &recordingDispatcher{}
+	a.SetDispatcher(dispatcher, nil)
+
+	a.answerDMQuestion(context.Background(), "room-1", "p1", &Response{Message: "Only once per game.", ShouldSpeak: true})
+	if len(dispatcher.cmds) != 1 || dispatcher.cmds[0].Type != "whisper" {
+		t.Fatalf("expected one whisper back, got %+v", dispatcher.cmds)
+	}
+	var p map[string]string
+	_ = json.Unmarshal(dispatcher.cmds[0].Payload, &p)
+	if p["to_user_id"] != "p1" || p["message"] != "Only once per game." {
+		t.Fatalf("expected the answer whispered to p1, got %v", p)
+	}
+}
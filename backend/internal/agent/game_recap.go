@@ -0,0 +1,187 @@
+// Package agent 对局结束的全局复盘
+//
+// OnEvent 在对局进行中记下每个房间的关键节点 (夜晚死亡、处决、无人处决)。game.ended 时
+// publishGameRecap 以揭晓后的魔典 (真实身份，酒鬼标注其以为的身份) 与这些节点请摘要子代理写复盘，
+// 失败时退回本地拼装的复盘；复盘以 game.recap 事件公开，并写入长期记忆。
+//
+// [IN]  internal/engine（魔典与事件发生时的天数）
+// [IN]  agent/core（RecapGame / RememberRecap）
+// [OUT] autodm.go（OnEvent 记录节点、publishGameRecap 生成复盘）
+// [POS] Auto-DM 的对局收尾
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/subagent"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// turningPoint is one death or execution decision worth retelling in the recap.
+type turningPoint struct {
+	Day    int
+	Night  bool
+	Player string // "Name (role)"; empty for a day without execution
+	Cause  string
+}
+
+// dayDeathCauses are player.died causes that happen during the day.
+var dayDeathCauses = map[string]bool{"execution": true, "slayer": true, "virgin_ability": true}
+
+// recordTurningPoint remembers ev if it is a turning point; game.started resets the room's log.
+func (a *AutoDM) recordTurningPoint(ev types.Event, raw interface{}) {
+	state, ok := raw.(engine.State)
+	if !ok || ev.RoomID == "" {
+		return
+	}
+	var payload map[string]string
+	_ = json.Unmarshal(ev.Payload, &payload)
+
+	var tp turningPoint
+	switch ev.EventType {
+	case "game.started":
+		a.mu.Lock()
+		delete(a.turningPoints, ev.RoomID)
+		a.mu.Unlock()
+		return
+	case "player.died":
+		cause := payload["cause"]
+		tp = turningPoint{Day: state.DayCount, Player: recapPlayerLabel(state, payload["user_id"]), Cause: cause}
+		if !dayDeathCauses[cause] {
+			tp.Day, tp.Night = state.NightCount, true
+		}
+	case "day.no_execution":
+		tp = turningPoint{Day: state.DayCount}
+	default:
+		return
+	}
+	a.mu.Lock()
+	a.turningPoints[ev.RoomID] = append(a.turningPoints[ev.RoomID], tp)
+	a.mu.Unlock()
+}
+
+// takeTurningPoints returns and forgets the room's turning points.
+func (a *AutoDM) takeTurningPoints(roomID string) []turningPoint {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	points := a.turningPoints[roomID]
+	delete(a.turningPoints, roomID)
+	return points
+}
+
+// recapPlayerLabel names a player with their true role.
+func recapPlayerLabel(state engine.State, userID string) string {
+	p, ok := state.Players[userID]
+	if !ok {
+		return userID
+	}
+	return fmt.Sprintf("%s (%s)", p.Name, recapRole(p))
+}
+
+// recapRole is p's true role, noting what a drunk believed they were.
+func recapRole(p engine.Player) string {
+	if p.TrueRole != "" && p.TrueRole != p.Role {
+		return fmt.Sprintf("%s, believed %s", p.TrueRole, p.Role)
+	}
+	return p.Role
+}
+
+// formatTurningPoint renders tp for the recap prompt (en) or the local fallback (zh).
+func formatTurningPoint(tp turningPoint, zh bool) string {
+	switch {
+	case tp.Player == "" && zh:
+		return fmt.Sprintf("第%d天：无人被处决", tp.Day)
+	case tp.Player == "":
+		return fmt.Sprintf("Day %d: no one was executed", tp.Day)
+	case zh && tp.Cause == "execution":
+		return fmt.Sprintf("第%d天：%s 被处决", tp.Day, tp.Player)
+	case zh && tp.Night:
+		return fmt.Sprintf("第%d夜：%s 死亡 (%s)", tp.Day, tp.Player, tp.Cause)
+	case zh:
+		return fmt.Sprintf("第%d天：%s 死亡 (%s)", tp.Day, tp.Player, tp.Cause)
+	case tp.Night:
+		return fmt.Sprintf("Night %d: %s died (%s)", tp.Day, tp.Player, tp.Cause)
+	case tp.Cause == "execution":
+		return fmt.Sprintf("Day %d: %s was executed", tp.Day, tp.Player)
+	default:
+		return fmt.Sprintf("Day %d: %s died (%s)", tp.Day, tp.Player, tp.Cause)
+	}
+}
+
+func formatTurningPoints(points []turningPoint, zh bool) []string {
+	lines := make([]string, len(points))
+	for i, tp := range points {
+		lines[i] = formatTurningPoint(tp, zh)
+	}
+	return lines
+}
+
+// recapStateView is the revealed grimoire: every seated player with their true role.
+func recapStateView(state engine.State) subagent.GameStateView {
+	gs := subagent.GameStateView{
+		RoomID:    state.RoomID,
+		Phase:     string(state.Phase),
+		DayNumber: state.DayCount,
+		Edition:   state.Edition,
+	}
+	for _, userID := range state.SeatOrder {
+		p, ok := state.Players[userID]
+		if !ok || p.IsDM {
+			continue
+		}
+		gs.Players = append(gs.Players, subagent.PlayerView{
+			ID:      userID,
+			Name:    fmt.Sprintf("%d. %s", p.SeatNumber, p.Name),
+			Role:    recapRole(p),
+			IsAlive: p.Alive,
+		})
+	}
+	return gs
+}
+
+// composeGameRecap asks the summarizer for the recap of the room that just ended.
+func (a *AutoDM) composeGameRecap(ctx context.Context, ev types.Event, points []turningPoint) (string, error) {
+	state := a.currentEngineState()
+	if state == nil {
+		return "", fmt.Errorf("agent.composeGameRecap: no game state for room %s", ev.RoomID)
+	}
+	winner, reason := parseWinnerAndReason(ev.Payload)
+	recap, err := a.orchestrator.RecapGame(ctx, recapStateView(*state), formatTurningPoints(points, false), winner, reason)
+	if err != nil {
+		return "", fmt.Errorf("agent.composeGameRecap: %w", err)
+	}
+	return recap, nil
+}
+
+// fallbackRecapDetails lists true roles and turning points for the local recap.
+func fallbackRecapDetails(state *engine.State, points []turningPoint) []string {
+	var roles []string
+	for _, userID := range state.SeatOrder {
+		if p, ok := state.Players[userID]; ok && !p.IsDM {
+			roles = append(roles, fmt.Sprintf("%d号 %s", p.SeatNumber, recapPlayerLabel(*state, userID)))
+		}
+	}
+	var parts []string
+	if len(roles) > 0 {
+		parts = append(parts, fmt.Sprintf("身份揭晓：%s。", strings.Join(roles, "、")))
+	}
+	if len(points) > 0 {
+		parts = append(parts, fmt.Sprintf("关键节点：%s。", strings.Join(formatTurningPoints(points, true), "；")))
+	}
+	return parts
+}
+
+// rememberGameRecap keeps the published recap in long-term memory.
+func (a *AutoDM) rememberGameRecap(ctx context.Context, roomID, recap string) {
+	day := 0
+	if state := a.currentEngineState(); state != nil {
+		day = state.DayCount
+	}
+	if err := a.orchestrator.RememberRecap(ctx, roomID, day, recap); err != nil {
+		a.logger.Warn("AutoDM failed to persist game recap", "error", err, "room_id", roomID)
+	}
+}
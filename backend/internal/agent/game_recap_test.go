@@ -0,0 +1,124 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/memory"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+type recordingMemoryStore struct {
+	mu      sync.Mutex
+	entries []memory.Entry
+}
+
+func (s *recordingMemoryStore) SaveEntries(_ context.Context, entries []memory.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func endedRoom() engine.State {
+	state := engine.NewState("room-1")
+	state.Phase = engine.PhaseEnded
+	state.DayCount, state.NightCount = 2, 2
+	state.SeatOrder = []string{"p1", "p2", "p3"}
+	state.Players["p1"] = engine.Player{UserID: "p1", Name: "Alice", SeatNumber: 1, Role: "imp", Team: "evil"}
+	state.Players["p2"] = engine.Player{UserID: "p2", Name: "Bob", SeatNumber: 2, Role: "chef", Team: "good", Alive: true}
+	state.Players["p3"] = engine.Player{UserID: "p3", Name: "Cat", SeatNumber: 3, Role: "empath", TrueRole: "drunk", Team: "good", Alive: true}
+	return state
+}
+
+// recapLLM serves status for every chat request, answering "Final recap." and recording the last prompt.
+func recapLLM(t *testing.T, status int, lastPrompt *string) LLMRoutingConfig {
+	t.Helper()
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llm.ChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		*lastPrompt = req.Messages[len(req.Messages)-1].Content
+		mu.Unlock()
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": "Final recap."}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return LLMRoutingConfig{Default: LLMClientConfig{BaseURL: srv.URL, Model: "m"}}
+}
+
+// endGame records an execution, ends the game and returns the published recap.
+func endGame(t *testing.T, a *AutoDM, state engine.State) string {
+	t.Helper()
+	dispatcher := &recordingDispatcher{}
+	a.SetDispatcher(dispatcher, func() interface{} { return state })
+
+	died, _ := json.Marshal(map[string]string{"user_id": "p1", "cause": "execution"})
+	a.recordTurningPoint(types.Event{RoomID: "room-1", EventType: "player.died", Payload: died}, state)
+	ended, _ := json.Marshal(map[string]string{"winner": "good", "reason": "demon_died"})
+	_ = a.ProcessQueuedEvent(context.Background(), types.Event{RoomID: "room-1", EventType: "game.ended", Payload: ended})
+
+	for _, cmd := range dispatcher.cmds {
+		var p struct {
+			EventType string            `json:"event_type"`
+			Data      map[string]string `json:"data"`
+		}
+		_ = json.Unmarshal(cmd.Payload, &p)
+		if cmd.Type == "write_event" && p.EventType == "game.recap" {
+			return p.Data["summary"]
+		}
+	}
+	t.Fatal("expected game.ended to publish a game.recap event")
+	return ""
+}
+
+func TestGameEndedPublishesRecapAndRemembersIt(t *testing.T) {
+	var prompt string
+	store := &recordingMemoryStore{}
+	a := NewAutoDM(Config{Enabled: true, LLM: recapLLM(t, http.StatusOK, &prompt), Memory: MemoryConfig{Store: store}})
+
+	recap := endGame(t, a, endedRoom())
+
+	if recap != "Final recap." {
+		t.Fatalf("expected the summarizer's recap, got %q", recap)
+	}
+	if !strings.Contains(prompt, "Day 2: Alice (imp) was executed") || !strings.Contains(prompt, "good won") {
+		t.Fatalf("expected the recap prompt to carry the turning points and winner, got %q", prompt)
+	}
+	var remembered bool
+	for _, e := range store.entries {
+		remembered = remembered || (e.Content == recap && len(e.Metadata.Tags) == 1 && e.Metadata.Tags[0] == "game_recap")
+	}
+	if !remembered {
+		t.Fatalf("expected the recap to be persisted to memory, got %+v", store.entries)
+	}
+	if len(a.takeTurningPoints("room-1")) != 0 {
+		t.Fatal("expected the room's turning points to be cleared after the recap")
+	}
+}
+
+func TestGameRecapFallsBackToRevealedGrimoire(t *testing.T) {
+	var prompt string
+	a := NewAutoDM(Config{Enabled: true, LLM: recapLLM(t, http.StatusInternalServerError, &prompt)})
+
+	recap := endGame(t, a, endedRoom())
+
+	for _, want := range []string{"Alice (imp)", "Cat (drunk, believed empath)", "第2天：Alice (imp) 被处决"} {
+		if !strings.Contains(recap, want) {
+			t.Fatalf("expected the fallback recap to contain %q, got %q", want, recap)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Package agent 默认主持消息的多语言表
+//
+// LLM 失败时按事件类型发送的兜底消息与讨论冷场提醒，按房间叙事语言选表 (zh/en)，
+// 未知语言回退中文。房间叙事语言取房间设置 narration_language (OnEvent 时从引擎状态快照)，
+// 未设置时用 LLMRoutingConfig.Language (AUTODM_LANGUAGE)；ProcessQueuedEvent 以 llm.WithLanguage
+// 把它带入 ctx，使 llm.Router 注入系统提示词的语言与兜底消息一致。快照在对局结束或停止时清除。
+//
+// [IN]  internal/engine（Config.NarrationLanguage）
+// [OUT] autodm.go（ProcessQueuedEvent 兜底消息）
+// [OUT] discussion_nudge.go（讨论冷场提醒文案）
+// [POS] Auto-DM 的本地化文案
+package agent
+
+import "github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+
+// Supported narration languages.
+const (
+	LanguageChinese = "zh"
+	LanguageEnglish = "en"
+)
+
+// defaultMessages holds the per-language fallback line for each event type.
+var defaultMessages = map[string]map[string]string{
+	LanguageChinese: {
+		"phase.day":          "☀️ 天亮了，开始讨论并寻找隐藏的邪恶吧。",
+		"phase.night":        "🌙 夜幕降临，请等待夜晚行动结算。",
+		"nomination.created": "📣 提名已发起，请进行陈述与投票。",
+		"game.started":       "🎲 游戏开始，愿好运站在你这边。",
+		"game.ended":         "🏁 对局结束，感谢各位参与。",
+	},
+	LanguageEnglish: {
+		"phase.day":          "☀️ Dawn breaks. Discuss and hunt for the hidden evil.",
+		"phase.night":        "🌙 Night falls. Please wait while night actions resolve.",
+		"nomination.created": "📣 A nomination has been made. Make your case, then vote.",
+		"game.started":       "🎲 The game begins. May fortune favour you.",
+		"game.ended":         "🏁 The game is over. Thank you all for playing.",
+	},
+}
+
+// defaultNudges holds the per-language discussion nudges, gentle → direct → about to advance.
+var defaultNudges = map[string][]string{
+	LanguageChinese: {
+		"💬 大家可以继续讨论，说说你们掌握的信息吧。",
+		"⏳ 讨论有些冷场了，请有想法的玩家直接发言，或准备发起提名。",
+		"⏰ 如果仍然无人发言，我将很快推进到提名阶段。",
+	},
+	LanguageEnglish: {
+		"💬 Feel free to keep talking. What have you learned so far?",
+		"⏳ Things have gone quiet. Speak up now or get ready to nominate.",
+		"⏰ If nobody speaks, I'll advance to nominations soon.",
+	},
+}
+
+// nudgeLevels returns the nudge ladder for lang, with custom replacing the gentle level.
+func nudgeLevels(lang, custom string) []string {
+	levels, ok := defaultNudges[lang]
+	if !ok {
+		levels = defaultNudges[LanguageChinese]
+	}
+	levels = append([]string(nil), levels...)
+	if custom != "" {
+		levels[0] = custom
+	}
+	return levels
+}
+
+// defaultMessageForEvent returns the fallback line for eventType in lang, or "".
+func defaultMessageForEvent(lang, eventType string) string {
+	table, ok := defaultMessages[lang]
+	if !ok {
+		table = defaultMessages[LanguageChinese]
+	}
+	return table[eventType]
+}
+
+// rememberRoomLanguage snapshots the room's narration_language setting from engine state.
+func (a *AutoDM) rememberRoomLanguage(raw interface{}) {
+	state, ok := raw.(engine.State)
+	if !ok || state.RoomID == "" {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if state.Config.NarrationLanguage == "" {
+		delete(a.roomLanguages, state.RoomID)
+		return
+	}
+	a.roomLanguages[state.RoomID] = state.Config.NarrationLanguage
+}
+
+// languageFor returns the room's narration language, or the configured default.
+func (a *AutoDM) languageFor(roomID string) string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if lang, ok := a.roomLanguages[roomID]; ok {
+		return lang
+	}
+	return a.language
+}
+
+// forgetRoomLanguage drops the room's narration language snapshot.
+func (a *AutoDM) forgetRoomLanguage(roomID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.roomLanguages, roomID)
+}
+
+// forgetRoomLanguages drops every room's narration language snapshot.
+func (a *AutoDM) forgetRoomLanguages() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.roomLanguages = make(map[string]string)
+}
@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestDefaultDawnMessageFollowsLanguage(t *testing.T) {
+	en := defaultMessageForEvent(LanguageEnglish, "phase.day")
+	if !strings.Contains(en, "Dawn breaks") {
+		t.Fatalf("expected English dawn message, got %q", en)
+	}
+	if zh := defaultMessageForEvent(LanguageChinese, "phase.day"); !strings.Contains(zh, "天亮了") {
+		t.Fatalf("expected Chinese dawn message, got %q", zh)
+	}
+	if got := defaultMessageForEvent("fr", "phase.day"); got != defaultMessageForEvent(LanguageChinese, "phase.day") {
+		t.Fatalf("unknown language should fall back to Chinese, got %q", got)
+	}
+}
+
+func TestRoomNarrationLanguageOverridesDefault(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true, LLM: LLMRoutingConfig{Language: LanguageChinese}})
+	dispatcher := &recordingDispatcher{}
+	a.SetDispatcher(dispatcher, nil)
+	english := engine.NewState("room-en")
+	english.Config.NarrationLanguage = LanguageEnglish
+	a.rememberRoomLanguage(english)
+
+	tally := []byte(`{"votes_for":"3","threshold":"3","result":"on_the_block"}`)
+	for _, roomID := range []string{"room-en", "room-zh"} {
+		ev := types.Event{RoomID: roomID, EventType: "nomination.resolved", Payload: tally}
+		if err := a.ProcessQueuedEvent(context.Background(), ev); err != nil {
+			t.Fatalf("%s: %v", roomID, err)
+		}
+	}
+	got := map[string]string{}
+	for _, cmd := range dispatcher.cmds {
+		var p map[string]string
+		_ = json.Unmarshal(cmd.Payload, &p)
+		got[cmd.RoomID] = p["message"]
+	}
+	if !strings.Contains(got["room-en"], "Vote tally") || !strings.Contains(got["room-zh"], "计票结果") {
+		t.Fatalf("expected each room's tally in its own language, got %v", got)
+	}
+
+	english.Config.NarrationLanguage = ""
+	a.rememberRoomLanguage(english)
+	if lang := a.languageFor("room-en"); lang != LanguageChinese {
+		t.Fatalf("expected clearing the setting to restore the default, got %q", lang)
+	}
+}
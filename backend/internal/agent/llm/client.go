@@ -17,6 +17,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/outbound"
 )
 
 // Config holds LLM client configuration.
@@ -62,10 +64,8 @@ func NewClient(cfg Config) Provider {
 	}
 
 	return &Client{
-		cfg: cfg,
-		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
-		},
+		cfg:        cfg,
+		httpClient: outbound.Client(cfg.HTTPSProxy, cfg.Timeout),
 	}
 }
 
@@ -135,9 +135,10 @@ type ChatResponse struct {
 // Chat sends a chat completion request.
 func (c *Client) Chat(ctx context.Context, messages []Message, tools []Tool) (*ChatResponse, error) {
 	req := ChatRequest{
-		Model:    c.cfg.Model,
-		Messages: messages,
-		Tools:    tools,
+		Model:     c.cfg.Model,
+		Messages:  messages,
+		Tools:     tools,
+		MaxTokens: maxTokensFromContext(ctx), // max_tokens.go
 	}
 
 	body, err := json.Marshal(req)
@@ -12,8 +12,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/outbound"
 )
 
 // GeminiClient provides Google Gemini API access.
@@ -41,22 +45,10 @@ func NewGeminiClient(cfg GeminiConfig) *GeminiClient {
 		cfg.Model = "gemini-3-flash-preview" // FIX-9a: default to Gemini 3 Flash
 	}
 
-	httpClient := &http.Client{
-		Timeout: cfg.Timeout,
-	}
-
-	if cfg.HTTPSProxy != "" {
-		if u, err := url.Parse(cfg.HTTPSProxy); err == nil {
-			httpClient.Transport = &http.Transport{
-				Proxy: http.ProxyURL(u),
-			}
-		}
-	}
-
 	return &GeminiClient{
 		apiKey:     cfg.APIKey,
 		model:      cfg.Model,
-		httpClient: httpClient,
+		httpClient: outbound.Client(cfg.HTTPSProxy, cfg.Timeout),
 		baseURL:    "https://generativelanguage.googleapis.com/v1beta",
 	}
 }
@@ -181,7 +173,7 @@ func (c *GeminiClient) Chat(ctx context.Context, messages []Message, tools []Too
 		if msg.ToolCallID != "" {
 			content.Parts = []GeminiPart{{
 				FunctionResp: &GeminiFuncResult{
-					Name:     msg.ToolCallID,
+					Name:     geminiFuncName(msg.ToolCallID),
 					Response: map[string]interface{}{"result": msg.Content},
 				},
 			}}
@@ -210,7 +202,7 @@ func (c *GeminiClient) Chat(ctx context.Context, messages []Message, tools []Too
 		SystemInstruct: systemContent,
 		GenerationConfig: &GeminiGenerationCfg{
 			Temperature:     0.7,
-			MaxOutputTokens: 4096,
+			MaxOutputTokens: geminiMaxOutputTokens(ctx),
 		},
 		// FIX-9b: Add safety settings to avoid filtering game-related content
 		SafetySettings: []GeminiSafetySetting{
@@ -312,7 +304,7 @@ func (c *GeminiClient) convertResponse(resp GeminiResponse) (*ChatResponse, erro
 		if part.FunctionCall != nil {
 			argsJSON, _ := json.Marshal(part.FunctionCall.Args)
 			msg.ToolCalls = append(msg.ToolCalls, ToolCall{
-				ID:   part.FunctionCall.Name,
+				ID:   geminiCallID(part.FunctionCall.Name),
 				Type: "function",
 				Function: FunctionCall{
 					Name:      part.FunctionCall.Name,
@@ -358,3 +350,23 @@ func (c *GeminiClient) SimpleChat(ctx context.Context, systemPrompt, userMessage
 func (c *GeminiClient) Model() string {
 	return c.model
 }
+
+// geminiMaxOutputTokens is the task's limit from ctx (max_tokens.go), else 4096.
+func geminiMaxOutputTokens(ctx context.Context) int {
+	if n := maxTokensFromContext(ctx); n > 0 {
+		return n
+	}
+	return 4096
+}
+
+// geminiCallID gives a Gemini function call a unique ID; Gemini has none of its own and
+// tool-call deduplication (mcp idempotency) must not merge separate calls to one function.
+func geminiCallID(name string) string {
+	return name + "#" + uuid.NewString()
+}
+
+// geminiFuncName recovers the function name a tool response answers from its call ID.
+func geminiFuncName(callID string) string {
+	name, _, _ := strings.Cut(callID, "#")
+	return name
+}
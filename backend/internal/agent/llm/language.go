@@ -0,0 +1,75 @@
+// Package llm 回复语言注入
+//
+// Router 配置语言后，在每次调用的系统提示词末尾追加 "Respond in <language>."，
+// 所有子代理 (主持/叙事/规则/摘要/配板) 因此共用同一叙事语言，无需各自修改提示词。
+// 调用方可用 WithLanguage 为单次调用 (如某个房间) 覆盖该语言，空串表示不追加指令。
+//
+// [OUT] router.go（SimpleChat/Chat 调用前注入）、subagent/prompts.go（按单次调用语言选模板）
+// [POS] LLM 路由层的本地化钩子
+package llm
+
+import "context"
+
+// languageNames maps language codes to the name used in the instruction.
+var languageNames = map[string]string{
+	"zh": "Simplified Chinese (简体中文)",
+	"en": "English",
+}
+
+// LanguageName returns the prompt-facing name for a language code; unknown codes pass through.
+func LanguageName(lang string) string {
+	if name, ok := languageNames[lang]; ok {
+		return name
+	}
+	return lang
+}
+
+// SetLanguage makes every system prompt ask for replies in lang ("" disables).
+func (r *Router) SetLanguage(lang string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.language = lang
+}
+
+type languageContextKey struct{}
+
+// WithLanguage overrides the reply language for calls made with ctx ("" disables the instruction).
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageContextKey{}, lang)
+}
+
+// LanguageFromContext returns the WithLanguage override carried by ctx; ok is false without one.
+func LanguageFromContext(ctx context.Context) (lang string, ok bool) {
+	lang, ok = ctx.Value(languageContextKey{}).(string)
+	return lang, ok
+}
+
+// languageInstruction returns the sentence appended to system prompts, or "".
+func (r *Router) languageInstruction(ctx context.Context) string {
+	lang, ok := LanguageFromContext(ctx)
+	if !ok {
+		r.mu.RLock()
+		lang = r.language
+		r.mu.RUnlock()
+	}
+	if lang == "" {
+		return ""
+	}
+	return "\n\nRespond in " + LanguageName(lang) + "."
+}
+
+// localizeMessages appends the instruction to the first system message, copying the slice.
+func (r *Router) localizeMessages(ctx context.Context, messages []Message) []Message {
+	instruction := r.languageInstruction(ctx)
+	if instruction == "" {
+		return messages
+	}
+	out := append([]Message(nil), messages...)
+	for i := range out {
+		if out[i].Role == "system" {
+			out[i].Content += instruction
+			return out
+		}
+	}
+	return append([]Message{{Role: "system", Content: instruction[2:]}}, out...)
+}
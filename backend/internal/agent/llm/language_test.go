@@ -0,0 +1,22 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestContextLanguageOverridesRouterDefault(t *testing.T) {
+	r := NewRouter(Config{})
+	r.SetLanguage("zh")
+
+	if got := r.languageInstruction(context.Background()); !strings.Contains(got, "Simplified Chinese") {
+		t.Fatalf("expected the router default language, got %q", got)
+	}
+	if got := r.languageInstruction(WithLanguage(context.Background(), "en")); !strings.Contains(got, "English") {
+		t.Fatalf("expected the ctx language to win, got %q", got)
+	}
+	if got := r.languageInstruction(WithLanguage(context.Background(), "")); got != "" {
+		t.Fatalf("expected an empty ctx language to disable the instruction, got %q", got)
+	}
+}
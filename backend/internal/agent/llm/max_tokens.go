@@ -0,0 +1,49 @@
+// Package llm 按任务角色限制模型输出长度
+//
+// RoutingConfig.MaxTokens 按任务名 (narration/rules/quick/…，"default" 作用于未列出的任务)
+// 配置最大输出 token 数。Router.Chat/SimpleChat 把该任务的上限放入 ctx，OpenAI 兼容客户端
+// 写入 max_tokens，Gemini 客户端写入 generationConfig.maxOutputTokens；未配置时沿用各客户端默认值。
+// 房间模型覆盖同样受限，因为上限随 ctx 传递而不是绑定在客户端上。
+//
+// [IN]  RoutingConfig.MaxTokens（cmd/server 由 AUTODM_LLM_MAX_TOKENS 配置）
+// [OUT] router.go（调用前注入）、client.go / gemini.go（请求体）
+// [POS] LLM 路由层的输出长度闸门
+package llm
+
+import "context"
+
+type maxTokensContextKey struct{}
+
+// withMaxTokens tags ctx with the output-token limit for the next request; n <= 0 leaves ctx as is.
+func withMaxTokens(ctx context.Context, n int) context.Context {
+	if n <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, maxTokensContextKey{}, n)
+}
+
+// maxTokensFromContext returns the request's output-token limit, 0 when unset.
+func maxTokensFromContext(ctx context.Context) int {
+	n, _ := ctx.Value(maxTokensContextKey{}).(int)
+	return n
+}
+
+// SetMaxTokens replaces the per-task output limits; the "default" entry covers unlisted tasks.
+func (r *Router) SetMaxTokens(limits map[string]int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxTokens = make(map[TaskType]int, len(limits))
+	for task, n := range limits {
+		r.maxTokens[TaskType(task)] = n
+	}
+}
+
+// maxTokensFor returns taskType's output limit, falling back to the default entry.
+func (r *Router) maxTokensFor(taskType TaskType) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if n, ok := r.maxTokens[taskType]; ok {
+		return n
+	}
+	return r.maxTokens[TaskDefault]
+}
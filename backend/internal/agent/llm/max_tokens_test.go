@@ -0,0 +1,42 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTaskMaxTokensSentInRequest(t *testing.T) {
+	var mu sync.Mutex
+	var got []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		got = append(got, req.MaxTokens)
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": "ok"}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	r := NewRouterFromConfig(RoutingConfig{
+		Default:   Config{BaseURL: srv.URL, Model: "base-model"},
+		MaxTokens: map[string]int{"narration": 8, "default": 256},
+	})
+	ctx := context.Background()
+	if _, err := r.Chat(ctx, TaskNarration, []Message{{Role: "user", Content: "narrate"}}, nil); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if _, err := r.SimpleChat(ctx, TaskRules, "system", "rules?"); err != nil {
+		t.Fatalf("simple chat: %v", err)
+	}
+
+	if len(got) != 2 || got[0] != 8 || got[1] != 256 {
+		t.Fatalf("expected max_tokens 8 for narration and the default 256 for rules, got %v", got)
+	}
+}
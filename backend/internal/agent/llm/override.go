@@ -0,0 +1,130 @@
+// Package llm 按房间的运行时模型覆盖
+//
+// DM 可为某个房间的 AutoDM 切换模型/Base URL：SetRoomOverride 先按白名单校验
+// (ModelAllowlist.Models 中的模型名，非默认 Base URL 须在 BaseURLs 中)，
+// 再以默认配置的密钥、超时与代理新建客户端。提供方不可单独指定，与 NewClient 一样由 Base URL 决定，
+// 因此默认密钥只会发往运维列入白名单的端点。调用方用 WithRoom 把房间 ID 放入 ctx，
+// Chat/SimpleChat 对有覆盖的房间忽略任务路由、全部使用覆盖模型；无覆盖时行为不变。
+// 未配置白名单时只允许默认模型。覆盖的持久化由调用方负责 (store.autodm_model_overrides)。
+//
+// [OUT] agent（AutoDM.SetModelOverride、ProcessQueuedEvent 注入房间）
+// [POS] LLM 路由层的按房间模型切换
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrModelNotAllowed rejects an override outside the allowlist.
+var ErrModelNotAllowed = errors.New("model not in allowlist")
+
+// ModelOverride replaces a room's model; empty BaseURL keeps the default endpoint.
+// The provider follows BaseURL (see NewClient) and cannot be chosen separately.
+type ModelOverride struct {
+	Model   string `json:"model"`
+	BaseURL string `json:"base_url,omitempty"`
+}
+
+// ModelAllowlist lists what overrides may select.
+type ModelAllowlist struct {
+	Models   []string
+	BaseURLs []string
+}
+
+type roomContextKey struct{}
+
+// WithRoom tags ctx with the room whose model override should apply.
+func WithRoom(ctx context.Context, roomID string) context.Context {
+	return context.WithValue(ctx, roomContextKey{}, roomID)
+}
+
+func roomFromContext(ctx context.Context) string {
+	roomID, _ := ctx.Value(roomContextKey{}).(string)
+	return roomID
+}
+
+type roomModel struct {
+	override ModelOverride
+	client   Provider
+}
+
+// SetAllowlist replaces the models and base URLs overrides may select.
+func (r *Router) SetAllowlist(list ModelAllowlist) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.allowlist = list
+}
+
+// SetRoomOverride validates o against the allowlist and routes the room's calls to it.
+func (r *Router) SetRoomOverride(roomID string, o ModelOverride) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkAllowed(o); err != nil {
+		return fmt.Errorf("llm.SetRoomOverride: %w", err)
+	}
+	cfg := r.base
+	cfg.Model = o.Model
+	if o.BaseURL != "" {
+		cfg.BaseURL = o.BaseURL
+	}
+	if r.overrides == nil {
+		r.overrides = make(map[string]roomModel)
+	}
+	r.overrides[roomID] = roomModel{override: o, client: NewClient(cfg)}
+	return nil
+}
+
+// ClearRoomOverride returns the room to the configured routing.
+func (r *Router) ClearRoomOverride(roomID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.overrides, roomID)
+}
+
+// RoomOverride returns the room's active override, if any.
+func (r *Router) RoomOverride(roomID string) (ModelOverride, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.overrides[roomID]
+	return m.override, ok
+}
+
+// checkAllowed reports whether o's model and endpoint are allowlisted; callers hold r.mu.
+func (r *Router) checkAllowed(o ModelOverride) error {
+	if o.Model == "" {
+		return fmt.Errorf("%w: model is required", ErrModelNotAllowed)
+	}
+	modelOK := len(r.allowlist.Models) == 0 && o.Model == r.base.Model
+	for _, m := range r.allowlist.Models {
+		if m == o.Model {
+			modelOK = true
+		}
+	}
+	if !modelOK {
+		return fmt.Errorf("%w: %q", ErrModelNotAllowed, o.Model)
+	}
+	if o.BaseURL == "" || o.BaseURL == r.base.BaseURL {
+		return nil
+	}
+	for _, u := range r.allowlist.BaseURLs {
+		if u == o.BaseURL {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: base URL %q", ErrModelNotAllowed, o.BaseURL)
+}
+
+// clientFor picks the room override for ctx, else the task's routed client.
+func (r *Router) clientFor(ctx context.Context, taskType TaskType) Provider {
+	if roomID := roomFromContext(ctx); roomID != "" {
+		r.mu.RLock()
+		m, ok := r.overrides[roomID]
+		r.mu.RUnlock()
+		if ok {
+			return m.client
+		}
+	}
+	return r.GetClient(taskType)
+}
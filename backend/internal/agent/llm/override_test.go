@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// echoModelServer answers chat completions with the model name it was asked for.
+func echoModelServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": req.Model}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func replyModel(t *testing.T, ctx context.Context, r *Router) string {
+	t.Helper()
+	resp, err := r.Chat(ctx, TaskReasoning, []Message{{Role: "user", Content: "hi"}}, nil)
+	if err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	return resp.Choices[0].Message.Content
+}
+
+func TestRoomModelOverrideRoutesNextChat(t *testing.T) {
+	def, alt := echoModelServer(t), echoModelServer(t)
+	r := NewRouterFromConfig(RoutingConfig{
+		Default:   Config{BaseURL: def.URL, Model: "base-model"},
+		Allowlist: ModelAllowlist{Models: []string{"alt-model"}, BaseURLs: []string{alt.URL}},
+	})
+	ctx := WithRoom(context.Background(), "room-1")
+
+	if err := r.SetRoomOverride("room-1", ModelOverride{Model: "not-listed"}); !errors.Is(err, ErrModelNotAllowed) {
+		t.Fatalf("expected a model outside the allowlist to be refused, got %v", err)
+	}
+	if err := r.SetRoomOverride("room-1", ModelOverride{Model: "alt-model", BaseURL: "http://elsewhere"}); !errors.Is(err, ErrModelNotAllowed) {
+		t.Fatalf("expected an unlisted base URL to be refused, got %v", err)
+	}
+	if err := r.SetRoomOverride("room-1", ModelOverride{Model: "alt-model", BaseURL: alt.URL}); err != nil {
+		t.Fatalf("set override: %v", err)
+	}
+
+	if got := replyModel(t, ctx, r); got != "alt-model" {
+		t.Fatalf("expected the room's next chat on the override, got %q", got)
+	}
+	if got := replyModel(t, WithRoom(context.Background(), "room-2"), r); got != "base-model" {
+		t.Fatalf("expected other rooms on the default model, got %q", got)
+	}
+	r.ClearRoomOverride("room-1")
+	if got := replyModel(t, ctx, r); got != "base-model" {
+		t.Fatalf("expected clearing to restore the default model, got %q", got)
+	}
+}
@@ -22,6 +22,8 @@ const (
 	TaskSummarize TaskType = "summarize"
 	TaskQuick     TaskType = "quick"
 	TaskDefault   TaskType = "default"
+	TaskTranslate TaskType = "translate"
+	TaskBotChat   TaskType = "bot_chat"
 )
 
 // Router routes requests to appropriate models based on task type.
@@ -29,6 +31,17 @@ type Router struct {
 	mu       sync.RWMutex
 	models   map[TaskType]Provider
 	fallback Provider
+
+	// language is appended to system prompts as a reply-language instruction (language.go)
+	language string
+
+	// base, allowlist and overrides drive per-room model overrides (override.go)
+	base      Config
+	allowlist ModelAllowlist
+	overrides map[string]roomModel
+
+	// maxTokens caps output tokens per task (max_tokens.go)
+	maxTokens map[TaskType]int
 }
 
 // NewRouter creates a new model router.
@@ -36,6 +49,7 @@ func NewRouter(defaultCfg Config) *Router {
 	return &Router{
 		models:   make(map[TaskType]Provider),
 		fallback: NewClient(defaultCfg),
+		base:     defaultCfg,
 	}
 }
 
@@ -57,16 +71,18 @@ func (r *Router) GetClient(taskType TaskType) Provider {
 	return r.fallback
 }
 
-// Chat routes a chat request to the appropriate model.
+// Chat routes a chat request to the appropriate model (the room override when ctx carries one).
 func (r *Router) Chat(ctx context.Context, taskType TaskType, messages []Message, tools []Tool) (*ChatResponse, error) {
-	client := r.GetClient(taskType)
-	return client.Chat(ctx, messages, tools)
+	client := r.clientFor(ctx, taskType)
+	ctx = withMaxTokens(ctx, r.maxTokensFor(taskType))
+	return client.Chat(ctx, r.localizeMessages(ctx, messages), tools)
 }
 
 // SimpleChat routes a simple chat to the appropriate model.
 func (r *Router) SimpleChat(ctx context.Context, taskType TaskType, systemPrompt, userMessage string) (string, error) {
-	client := r.GetClient(taskType)
-	return client.SimpleChat(ctx, systemPrompt, userMessage)
+	client := r.clientFor(ctx, taskType)
+	ctx = withMaxTokens(ctx, r.maxTokensFor(taskType))
+	return client.SimpleChat(ctx, systemPrompt+r.languageInstruction(ctx), userMessage)
 }
 
 // ModelInfo returns info about which model is used for a task.
@@ -81,11 +97,26 @@ type RoutingConfig struct {
 	Reasoning Config
 	Narration Config
 	Quick     Config
+
+	// Language is the reply language for every system prompt ("zh", "en"; "" leaves prompts as is)
+	Language string
+
+	// Translator is the model for the translator role; empty falls back to Default
+	Translator Config
+
+	// Allowlist bounds per-room model overrides (override.go); empty allows only Default.Model
+	Allowlist ModelAllowlist
+
+	// MaxTokens caps output tokens by task name, "default" for unlisted tasks (max_tokens.go)
+	MaxTokens map[string]int
 }
 
 // NewRouterFromConfig creates a router with full configuration.
 func NewRouterFromConfig(cfg RoutingConfig) *Router {
 	router := NewRouter(cfg.Default)
+	router.SetLanguage(cfg.Language)
+	router.SetAllowlist(cfg.Allowlist)
+	router.SetMaxTokens(cfg.MaxTokens)
 
 	if cfg.Reasoning.Model != "" {
 		router.RegisterModel(TaskReasoning, cfg.Reasoning)
@@ -97,6 +128,10 @@ func NewRouterFromConfig(cfg RoutingConfig) *Router {
 		router.RegisterModel(TaskQuick, cfg.Quick)
 		router.RegisterModel(TaskSummarize, cfg.Quick)
 		router.RegisterModel(TaskRules, cfg.Quick)
+		router.RegisterModel(TaskBotChat, cfg.Quick)
+	}
+	if cfg.Translator.Model != "" {
+		router.RegisterModel(TaskTranslate, cfg.Translator)
 	}
 
 	return router
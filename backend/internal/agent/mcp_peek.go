@@ -0,0 +1,88 @@
+// Package agent 魔典单人查询 MCP 工具 (peek_player)
+//
+// 只注册在 AutoDM 自己的 MCP 注册表中 (玩家无法调用)，按 user_id 或座位号返回一名玩家的
+// 真实角色、阵营、提醒标记与状态，避免为查一个人而导出整本魔典。数据来自 SetDispatcher
+// 注入的房间状态获取器；状态不属于请求的房间或玩家不存在时拒绝。
+//
+// [IN]  internal/engine（State、Player）
+// [IN]  internal/mcp（工具注册）
+// [OUT] autodm.go（initMCPRegistry 注册）
+// [POS] DM/AutoDM 的隐藏信息查询工具
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/mcp"
+)
+
+// ErrPeekPlayerNotFound means the peeked user or seat is not in the room.
+var ErrPeekPlayerNotFound = errors.New("peek_player: player not found")
+
+// PlayerPeek is one player's grimoire entry as returned by peek_player.
+type PlayerPeek struct {
+	UserID       string   `json:"user_id"`
+	Name         string   `json:"name"`
+	SeatNumber   int      `json:"seat_number"`
+	TrueRole     string   `json:"true_role"`
+	ShownRole    string   `json:"shown_role"`
+	Team         string   `json:"team"`
+	Alive        bool     `json:"alive"`
+	IsPoisoned   bool     `json:"is_poisoned"`
+	IsProtected  bool     `json:"is_protected"`
+	HasGhostVote bool     `json:"has_ghost_vote"`
+	Reminders    []string `json:"reminders"`
+}
+
+// peekPlayer finds a player by user id, or by seat number when userID is empty.
+func peekPlayer(state engine.State, userID string, seat int) (PlayerPeek, error) {
+	for _, p := range state.Players {
+		if p.IsDM || (userID != "" && p.UserID != userID) || (userID == "" && p.SeatNumber != seat) {
+			continue
+		}
+		return PlayerPeek{
+			UserID: p.UserID, Name: p.Name, SeatNumber: p.SeatNumber,
+			TrueRole: p.TrueRole, ShownRole: p.Role, Team: p.Team,
+			Alive: p.Alive, IsPoisoned: p.IsPoisoned, IsProtected: p.IsProtected,
+			HasGhostVote: p.HasGhostVote, Reminders: append([]string(nil), p.Reminders...),
+		}, nil
+	}
+	return PlayerPeek{}, ErrPeekPlayerNotFound
+}
+
+// registerPeekTool adds peek_player to the AutoDM registry.
+func (a *AutoDM) registerPeekTool(registry *mcp.Registry) {
+	minLen, minSeat := 1, 1.0
+	_ = registry.Register(mcp.ToolDefinition{
+		Name:        "peek_player",
+		Description: "DM only: look up one player's true role, team, reminders and status by user_id or seat_number",
+		Category:    mcp.CategoryInformation,
+		Parameters: map[string]mcp.ParamSchema{
+			"room_id":     {Type: "string", MinLength: &minLen},
+			"user_id":     {Type: "string"},
+			"seat_number": {Type: "integer", Minimum: &minSeat},
+		},
+		Required: []string{"room_id"},
+	}, func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			RoomID     string `json:"room_id"`
+			UserID     string `json:"user_id"`
+			SeatNumber int    `json:"seat_number"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		if p.UserID == "" && p.SeatNumber == 0 {
+			return nil, fmt.Errorf("peek_player: user_id or seat_number is required")
+		}
+		state := a.currentEngineState()
+		if state == nil || state.RoomID != p.RoomID {
+			return nil, fmt.Errorf("peek_player: no state available for room %s", p.RoomID)
+		}
+		return peekPlayer(*state, p.UserID, p.SeatNumber)
+	})
+}
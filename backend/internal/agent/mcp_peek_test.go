@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/mcp"
+)
+
+func peekAutoDM() *AutoDM {
+	state := engine.NewState("room-1")
+	state.Players["p1"] = engine.Player{UserID: "p1", SeatNumber: 1, Role: "washerwoman", TrueRole: "washerwoman", Team: "good", Alive: true}
+	state.Players["p2"] = engine.Player{UserID: "p2", SeatNumber: 2, Role: "soldier", TrueRole: "drunk", Team: "good", Alive: true, Reminders: []string{"drunk"}}
+	a := NewAutoDM(Config{Enabled: true})
+	a.SetDispatcher(&recordingDispatcher{}, func() interface{} { return state })
+	return a
+}
+
+func invokePeek(a *AutoDM, params map[string]interface{}) *mcp.ToolResult {
+	raw, _ := json.Marshal(params)
+	return a.mcpRegistry.Invoke(context.Background(), mcp.ToolCall{ID: "call-1", ToolName: "peek_player", Parameters: raw, Caller: mcp.CallerAutoDM})
+}
+
+func TestPeekPlayerReturnsTrueRoleForSeat(t *testing.T) {
+	res := invokePeek(peekAutoDM(), map[string]interface{}{"room_id": "room-1", "seat_number": 2})
+	if !res.Success {
+		t.Fatalf("peek failed: %s", res.Error)
+	}
+	peek, ok := res.Result.(PlayerPeek)
+	if !ok || peek.UserID != "p2" || peek.TrueRole != "drunk" || peek.ShownRole != "soldier" {
+		t.Fatalf("unexpected peek result %+v", res.Result)
+	}
+	if len(peek.Reminders) != 1 || peek.Reminders[0] != "drunk" {
+		t.Fatalf("expected reminders in peek, got %v", peek.Reminders)
+	}
+}
+
+func TestPeekPlayerRefusesUnknownUsers(t *testing.T) {
+	a := peekAutoDM()
+	for _, params := range []map[string]interface{}{
+		{"room_id": "room-1", "user_id": "stranger"},
+		{"room_id": "room-1", "seat_number": 9},
+		{"room_id": "room-2", "user_id": "p1"},
+		{"room_id": "room-1"},
+	} {
+		res := invokePeek(a, params)
+		if res.Success || !strings.Contains(res.Error, "peek_player") {
+			t.Fatalf("expected %v to be refused, got %+v", params, res)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Package memory 反思教训的长期记忆
+//
+// 反思得到的教训 (如 "上次推进过快让玩家困惑") 不随短期记忆淘汰，跨房间保留最近 maxLessons 条，
+// 重复内容只刷新时间；有 Store 时与其他条目一起落盘。RelevantLessons 按与查询的词重叠打分、
+// 同分取较新者，只返回少量条目以免提示词膨胀。
+//
+// [OUT] agent/core（记录反思教训，规划前注入系统提示词）
+// [POS] AI 记忆层的反馈环：把过去的失误带入后续决策
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxLessons bounds the lessons kept in memory.
+const maxLessons = 20
+
+// DefaultLessonLimit is how many lessons a prompt receives.
+const DefaultLessonLimit = 3
+
+// AddLesson remembers a reflection lesson; a repeated lesson is moved to the newest slot.
+func (m *Manager) AddLesson(ctx context.Context, roomID, phase string, dayNum int, lesson string) error {
+	lesson = strings.TrimSpace(lesson)
+	if lesson == "" {
+		return nil
+	}
+	entry := Entry{
+		ID:        fmt.Sprintf("%s-%d", EntryLesson, time.Now().UnixNano()),
+		Type:      EntryLesson,
+		Content:   lesson,
+		Metadata:  Metadata{RoomID: roomID, Phase: phase, DayNumber: dayNum},
+		Timestamp: time.Now(),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.lessons[:0]
+	for _, l := range m.lessons {
+		if !strings.EqualFold(l.Content, lesson) {
+			kept = append(kept, l)
+		}
+	}
+	m.lessons = append(kept, entry)
+	if len(m.lessons) > maxLessons {
+		m.lessons = m.lessons[len(m.lessons)-maxLessons:]
+	}
+	m.queuePending(entry)
+	return nil
+}
+
+// RelevantLessons returns up to limit lessons, best word overlap with query first, newer first on ties.
+func (m *Manager) RelevantLessons(query string, limit int) []Entry {
+	m.mu.RLock()
+	lessons := make([]Entry, len(m.lessons))
+	copy(lessons, m.lessons)
+	m.mu.RUnlock()
+
+	words := lessonWords(query)
+	scores := make([]int, len(lessons))
+	order := make([]int, len(lessons))
+	for i, l := range lessons {
+		order[i] = len(lessons) - 1 - i // newest first
+		for w := range lessonWords(l.Content) {
+			if words[w] {
+				scores[i]++
+			}
+		}
+	}
+	// A stable sort by score keeps recency as the tiebreak.
+	sort.SliceStable(order, func(i, j int) bool { return scores[order[i]] > scores[order[j]] })
+	ranked := make([]Entry, len(order))
+	for i, idx := range order {
+		ranked[i] = lessons[idx]
+	}
+	lessons = ranked
+	if limit > 0 && len(lessons) > limit {
+		lessons = lessons[:limit]
+	}
+	return lessons
+}
+
+// lessonWords lowercases text into a set of words of three or more letters.
+func lessonWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127)
+	}) {
+		if len([]rune(w)) >= 3 {
+			words[w] = true
+		}
+	}
+	return words
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestLessonsAreBoundedDedupedAndPersisted(t *testing.T) {
+	st := &fakeStore{}
+	m := NewManager(Config{Store: st})
+	ctx := context.Background()
+
+	for i := 0; i < maxLessons+5; i++ {
+		_ = m.AddLesson(ctx, "room-1", "day", 1, fmt.Sprintf("lesson %d", i))
+	}
+	_ = m.AddLesson(ctx, "room-1", "day", 1, "LESSON 10")
+	if got := m.RelevantLessons("", 0); len(got) != maxLessons {
+		t.Fatalf("expected %d lessons kept, got %d", maxLessons, len(got))
+	}
+	if got := m.RelevantLessons("", 1); got[0].Content != "LESSON 10" {
+		t.Fatalf("expected a repeated lesson to become the newest, got %q", got[0].Content)
+	}
+	if err := m.Flush(ctx); err != nil || len(st.saved) != maxLessons+6 || st.saved[0].Type != EntryLesson {
+		t.Fatalf("expected every lesson to be persisted, got %d (%v)", len(st.saved), err)
+	}
+}
+
+func TestRelevantLessonsPreferWordOverlap(t *testing.T) {
+	m := NewManager(Config{})
+	ctx := context.Background()
+	_ = m.AddLesson(ctx, "room-1", "day", 1, "Voting announcements need the threshold")
+	_ = m.AddLesson(ctx, "room-1", "night", 1, "Keep night narration brief")
+
+	got := m.RelevantLessons("nomination voting started", 1)
+	if len(got) != 1 || got[0].Content != "Voting announcements need the threshold" {
+		t.Fatalf("expected the voting lesson, got %+v", got)
+	}
+	mc, _ := m.GetContext(ctx, "room-1", "night falls")
+	if len(mc.Lessons) != 2 || mc.Lessons[0].Content != "Keep night narration brief" {
+		t.Fatalf("expected the night lesson first in the memory context, got %+v", mc.Lessons)
+	}
+}
@@ -1,6 +1,6 @@
 // Package memory 短期记忆管理，事件追踪与上下文构建
 //
-// [OUT] agent/autodm（记忆初始化）
+// [OUT] agent/autodm（记忆初始化与关停落盘）
 // [OUT] agent/core（事件记忆与上下文检索）
 // [POS] AI 记忆层，维护游戏事件的短期记忆供 LLM 上下文使用
 
@@ -24,6 +24,7 @@ const (
 	EntryNarration EntryType = "narration"
 	EntryPlayer    EntryType = "player"
 	EntryRules     EntryType = "rules"
+	EntryLesson    EntryType = "lesson"
 )
 
 // Entry represents a memory entry.
@@ -46,10 +47,20 @@ type Metadata struct {
 	Extra     map[string]string `json:"extra,omitempty"`
 }
 
+// maxPendingEntries bounds the unflushed backlog; the oldest entries are dropped
+// first when the store stays unavailable.
+const maxPendingEntries = 2000
+
+// Store persists memory entries beyond the process lifetime.
+type Store interface {
+	SaveEntries(ctx context.Context, entries []Entry) error
+}
+
 // Config for memory manager.
 type Config struct {
 	ShortTermCapacity int
 	LongTermEnabled   bool
+	Store             Store // optional; nil disables persistence
 }
 
 // Manager manages short-term and long-term memory.
@@ -57,6 +68,11 @@ type Manager struct {
 	mu        sync.RWMutex
 	shortTerm []Entry
 	capacity  int
+	store     Store
+	pending   []Entry // entries added since the last successful Flush
+
+	// lessons are reflection lessons kept apart from short-term eviction (lessons.go)
+	lessons []Entry
 }
 
 // NewManager creates a new memory manager.
@@ -67,6 +83,7 @@ func NewManager(cfg Config) *Manager {
 	return &Manager{
 		shortTerm: make([]Entry, 0, cfg.ShortTermCapacity),
 		capacity:  cfg.ShortTermCapacity,
+		store:     cfg.Store,
 	}
 }
 
@@ -86,10 +103,45 @@ func (m *Manager) Add(ctx context.Context, entry Entry) error {
 	if len(m.shortTerm) > m.capacity {
 		m.shortTerm = m.shortTerm[1:]
 	}
+	m.queuePending(entry)
 
 	return nil
 }
 
+// queuePending records entry for the next Flush; the caller holds m.mu.
+func (m *Manager) queuePending(entries ...Entry) {
+	if m.store == nil {
+		return
+	}
+	m.pending = append(m.pending, entries...)
+	if over := len(m.pending) - maxPendingEntries; over > 0 {
+		m.pending = append([]Entry(nil), m.pending[over:]...)
+	}
+}
+
+// Flush persists entries added since the last flush. On failure the
+// entries stay pending (up to maxPendingEntries) so a later Flush can retry.
+func (m *Manager) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	if m.store == nil || len(m.pending) == 0 {
+		m.mu.Unlock()
+		return nil
+	}
+	batch := m.pending
+	m.pending = nil
+	m.mu.Unlock()
+
+	if err := m.store.SaveEntries(ctx, batch); err != nil {
+		m.mu.Lock()
+		newer := m.pending
+		m.pending = nil
+		m.queuePending(append(batch, newer...)...)
+		m.mu.Unlock()
+		return fmt.Errorf("memory.Flush: %w", err)
+	}
+	return nil
+}
+
 // AddEvent is a convenience method for adding game events.
 func (m *Manager) AddEvent(ctx context.Context, roomID, phase string, dayNum int, content string) error {
 	return m.Add(ctx, Entry{
@@ -136,6 +188,7 @@ func (m *Manager) RecentForRoom(roomID string, limit int) []Entry {
 func (m *Manager) GetContext(ctx context.Context, roomID string, query string) (MemoryContext, error) {
 	return MemoryContext{
 		RecentEvents: m.RecentForRoom(roomID, 20),
+		Lessons:      m.RelevantLessons(query, DefaultLessonLimit),
 	}, nil
 }
 
@@ -143,6 +196,9 @@ func (m *Manager) GetContext(ctx context.Context, roomID string, query string) (
 type MemoryContext struct {
 	RecentEvents    []Entry
 	RelevantHistory []Entry
+
+	// Lessons are past reflection lessons relevant to the query
+	Lessons []Entry
 }
 
 // Format formats memory context as a string.
@@ -154,6 +210,12 @@ func (mc MemoryContext) Format() string {
 			sb.WriteString(fmt.Sprintf("- [%s] %s\n", e.Type, e.Content))
 		}
 	}
+	if len(mc.Lessons) > 0 {
+		sb.WriteString("## Lessons From Earlier Runs\n")
+		for _, e := range mc.Lessons {
+			sb.WriteString(fmt.Sprintf("- %s\n", e.Content))
+		}
+	}
 	return sb.String()
 }
 
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type fakeStore struct {
+	saved []Entry
+	err   error
+}
+
+func (f *fakeStore) SaveEntries(ctx context.Context, entries []Entry) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.saved = append(f.saved, entries...)
+	return nil
+}
+
+func TestFlushPersistsPendingEntries(t *testing.T) {
+	st := &fakeStore{}
+	m := NewManager(Config{Store: st})
+	ctx := context.Background()
+
+	_ = m.AddEvent(ctx, "room-1", "day", 2, "Alice nominated Bob")
+	_ = m.AddEvent(ctx, "room-1", "day", 2, "Bob was executed")
+
+	if err := m.Flush(ctx); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+	if len(st.saved) != 2 {
+		t.Fatalf("expected 2 persisted entries, got %d", len(st.saved))
+	}
+	if st.saved[1].Content != "Bob was executed" || st.saved[1].Metadata.RoomID != "room-1" {
+		t.Fatalf("unexpected persisted entry: %+v", st.saved[1])
+	}
+
+	// A second flush without new entries must not duplicate writes
+	if err := m.Flush(ctx); err != nil {
+		t.Fatalf("second Flush returned error: %v", err)
+	}
+	if len(st.saved) != 2 {
+		t.Fatalf("expected no duplicate writes, got %d entries", len(st.saved))
+	}
+}
+
+func TestFlushKeepsEntriesPendingOnStoreError(t *testing.T) {
+	st := &fakeStore{err: errors.New("db down")}
+	m := NewManager(Config{Store: st})
+	ctx := context.Background()
+
+	_ = m.AddEvent(ctx, "room-1", "night", 1, "night falls")
+	if err := m.Flush(ctx); err == nil {
+		t.Fatal("expected Flush to surface store error")
+	}
+
+	st.err = nil
+	if err := m.Flush(ctx); err != nil {
+		t.Fatalf("retry Flush returned error: %v", err)
+	}
+	if len(st.saved) != 1 {
+		t.Fatalf("expected pending entry persisted on retry, got %d", len(st.saved))
+	}
+}
+
+func TestFlushWithoutStoreIsNoop(t *testing.T) {
+	m := NewManager(Config{})
+	_ = m.AddEvent(context.Background(), "room-1", "day", 1, "hello")
+	if err := m.Flush(context.Background()); err != nil {
+		t.Fatalf("expected nil error without store, got %v", err)
+	}
+}
+
+func TestPendingEntriesAreBoundedWhileStoreIsDown(t *testing.T) {
+	st := &fakeStore{err: errors.New("db down")}
+	m := NewManager(Config{Store: st})
+	ctx := context.Background()
+
+	for i := 0; i < maxPendingEntries+10; i++ {
+		_ = m.AddEvent(ctx, "room-1", "day", 1, fmt.Sprintf("event %d", i))
+	}
+	if err := m.Flush(ctx); err == nil {
+		t.Fatal("expected Flush to surface store error")
+	}
+	_ = m.AddEvent(ctx, "room-1", "day", 1, "latest")
+
+	st.err = nil
+	if err := m.Flush(ctx); err != nil {
+		t.Fatalf("Flush after recovery: %v", err)
+	}
+	if len(st.saved) != maxPendingEntries {
+		t.Fatalf("expected %d persisted entries, got %d", maxPendingEntries, len(st.saved))
+	}
+	if st.saved[0].Content != "event 11" || st.saved[len(st.saved)-1].Content != "latest" {
+		t.Fatalf("expected the oldest entries dropped, kept %q..%q", st.saved[0].Content, st.saved[len(st.saved)-1].Content)
+	}
+}
@@ -0,0 +1,29 @@
+// Package agent 广播前的消息长度硬上限
+//
+// max_tokens (llm.RoutingConfig.MaxTokens) 只是请求模型少说，模型仍可能超长。sendMessage
+// 广播前用 capMessage 截断到 Config.MaxMessageChars 个字符：优先截在上限内最后一个句末标点
+// (。！？.!?) 之后，找不到或句子过短时硬截并补 "…"。0 表示不限制。
+//
+// [IN]  Config.MaxMessageChars（cmd/server 由 AUTODM_MAX_MESSAGE_CHARS 配置）
+// [OUT] autodm.go（sendMessage 广播前截断）、dm_whisper.go（私聊回答）
+// [POS] AutoDM 输出的最终长度闸门
+package agent
+
+import "strings"
+
+// capMessage truncates message to maxChars runes, preferring a sentence boundary.
+func capMessage(message string, maxChars int) string {
+	runes := []rune(message)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return message
+	}
+	cut := runes[:maxChars-1]
+	if i := strings.LastIndexAny(string(cut), "。！？.!?"); i >= 0 {
+		kept := []rune(string(cut)[:i])
+		// Only keep the sentence boundary if it saves at least half the allowance
+		if len(kept)+1 >= maxChars/2 {
+			return string(runes[:len(kept)+1])
+		}
+	}
+	return strings.TrimSpace(string(cut)) + "…"
+}
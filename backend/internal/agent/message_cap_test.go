@@ -0,0 +1,24 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCapMessage(t *testing.T) {
+	if got := capMessage("short.", 100); got != "short." {
+		t.Fatalf("expected short messages untouched, got %q", got)
+	}
+	if got := capMessage("天亮了。昨晚无人死亡。请开始讨论今天的提名与投票安排", 12); got != "天亮了。昨晚无人死亡。" {
+		t.Fatalf("expected a cut at the last sentence end, got %q", got)
+	}
+	long := strings.Repeat("word ", 100)
+	got := capMessage(long, 40)
+	if utf8.RuneCountInString(got) > 40 || !strings.HasSuffix(got, "…") {
+		t.Fatalf("expected a hard cut within 40 runes ending in an ellipsis, got %q", got)
+	}
+	if capMessage(long, 0) != long {
+		t.Fatal("expected 0 to disable the cap")
+	}
+}
@@ -0,0 +1,39 @@
+// Package agent 按房间切换 AutoDM 模型
+//
+// SetModelOverride 按白名单 (LLMRoutingConfig.Allowlist) 校验后，让该房间后续的编排器调用
+// (ProcessQueuedEvent 以 llm.WithRoom 标记 ctx) 改用指定模型/Base URL；ClearModelOverride 恢复配置路由。
+// 持久化由调用方 (api 与启动时加载) 负责，这里只维护内存中的路由。
+//
+// [IN]  internal/agent/llm（Router 房间覆盖）
+// [OUT] api（PUT/DELETE /v1/rooms/{room_id}/autodm/model）
+// [OUT] cmd/server（启动时恢复已保存的覆盖）
+// [POS] Auto-DM 的运行时模型选择
+package agent
+
+import (
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
+)
+
+// ErrModelNotAllowed rejects an override outside the configured allowlist.
+var ErrModelNotAllowed = llm.ErrModelNotAllowed
+
+// SetModelOverride routes the room's AutoDM calls to o; it fails when o is not allowlisted.
+func (a *AutoDM) SetModelOverride(roomID string, o ModelOverride) error {
+	if err := a.orchestrator.Router().SetRoomOverride(roomID, o); err != nil {
+		return fmt.Errorf("agent.SetModelOverride: %w", err)
+	}
+	a.logger.Info("autodm: model override set", "room", roomID, "model", o.Model, "base_url", o.BaseURL)
+	return nil
+}
+
+// ClearModelOverride returns the room to the configured models.
+func (a *AutoDM) ClearModelOverride(roomID string) {
+	a.orchestrator.Router().ClearRoomOverride(roomID)
+}
+
+// ModelOverride returns the room's active override, if any.
+func (a *AutoDM) ModelOverride(roomID string) (ModelOverride, bool) {
+	return a.orchestrator.Router().RoomOverride(roomID)
+}
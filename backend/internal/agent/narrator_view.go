@@ -0,0 +1,61 @@
+// narrator_view.go — Narrator 公开视图投影
+//
+// [IN]  internal/projection（非 DM 视角的事件过滤与脱敏）
+// [IN]  internal/engine（投影所需的空 State）
+// [IN]  internal/types（事件类型）
+// [OUT] autodm.go（ProcessQueuedEvent 构建编排器事件）
+// [POS] 保证叙事子代理只能接触公开信息，物理上无法在旁白中泄露真实角色或中毒状态
+package agent
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/projection"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// narratorViewer 叙事者以普通非 DM 观察者身份读取事件
+var narratorViewer = types.Viewer{UserID: "narrator"}
+
+// isNarratorEventType 判断转换后的事件是否会路由到 Narrator 子代理
+func isNarratorEventType(eventType string) bool {
+	return eventType == "phase_change" || eventType == "death"
+}
+
+// projectForNarrator 用玩家投影层过滤事件。
+// 空 State 意味着依赖身份的事件（邪恶聊天、伪装角色等）一律不可见。
+func projectForNarrator(ev types.Event) (types.Event, bool) {
+	projected := projection.ProjectEvent(ev, engine.NewState(ev.RoomID), narratorViewer)
+	if projected == nil {
+		return types.Event{}, false
+	}
+	public := ev
+	public.Payload = projected.Data
+	return public, true
+}
+
+// buildOrchestratorEvent 转换引擎事件；若目标是 Narrator 则只使用公开视图。
+// 返回 false 表示该事件不应交给编排器处理。
+func (a *AutoDM) buildOrchestratorEvent(ev types.Event) (Event, bool) {
+	event := a.convertEvent(ev)
+	if !isNarratorEventType(event.Type) {
+		return event, true
+	}
+	public, ok := projectForNarrator(ev)
+	if !ok {
+		return Event{}, false
+	}
+	return a.convertEvent(public), true
+}
+
+// formatDawnNames 将 dawn.summary 的 names（JSON 数组）拼成旁白可读的名单
+func formatDawnNames(raw interface{}) string {
+	encoded, _ := raw.(string)
+	var names []string
+	if err := json.Unmarshal([]byte(encoded), &names); err != nil || len(names) == 0 {
+		return "no one"
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestBuildOrchestratorEventStripsSecretsFromDeathNarration(t *testing.T) {
+	payload, _ := json.Marshal(map[string]string{
+		"user_id":   "p3",
+		"cause":     "slayer",
+		"true_role": "imp",
+		"poisoned":  "true",
+	})
+	ev := types.Event{RoomID: "room-1", Seq: 12, EventType: "player.died", Payload: payload}
+
+	a := &AutoDM{}
+	event, ok := a.buildOrchestratorEvent(ev)
+	if !ok {
+		t.Fatal("expected player.died to reach the narrator")
+	}
+	if event.Type != "death" {
+		t.Fatalf("expected death event, got %q", event.Type)
+	}
+	if event.Data["cause"] != "slayer" {
+		t.Fatalf("expected public cause slayer, got %v", event.Data["cause"])
+	}
+
+	narration := fmt.Sprintf("%v %s", event.Data, event.Description)
+	for _, secret := range []string{"imp", "true_role", "poison"} {
+		if strings.Contains(narration, secret) {
+			t.Fatalf("narrator input leaks %q: %s", secret, narration)
+		}
+	}
+}
+
+func TestBuildOrchestratorEventNarratesDawnSummaryOnce(t *testing.T) {
+	payload, _ := json.Marshal(map[string]string{
+		"deaths": `["p1","p3"]`,
+		"names":  `["Alice","Carol"]`,
+		"count":  "2",
+	})
+	ev := types.Event{RoomID: "room-1", Seq: 20, EventType: "dawn.summary", Payload: payload}
+
+	event, ok := (&AutoDM{}).buildOrchestratorEvent(ev)
+	if !ok {
+		t.Fatal("expected dawn.summary to reach the narrator")
+	}
+	if event.Type != "death" || event.Data["player_name"] != "Alice, Carol" {
+		t.Fatalf("expected one death narration for Alice, Carol, got %q %v", event.Type, event.Data["player_name"])
+	}
+
+	nightKill, _ := json.Marshal(map[string]string{"user_id": "p1", "cause": "demon"})
+	ev = types.Event{RoomID: "room-1", Seq: 18, EventType: "player.died", Payload: nightKill}
+	event, _ = (&AutoDM{}).buildOrchestratorEvent(ev)
+	if event.Type == "death" {
+		t.Fatal("expected night kill to be batched into dawn.summary, not narrated alone")
+	}
+}
+
+func TestBuildOrchestratorEventOnlyProjectsNarratorEvents(t *testing.T) {
+	payload, _ := json.Marshal(map[string]string{"user_id": "p1", "content": "secret"})
+	ev := types.Event{RoomID: "room-1", Seq: 4, EventType: "night.info", Payload: payload}
+
+	event, ok := (&AutoDM{}).buildOrchestratorEvent(ev)
+	if !ok {
+		t.Fatal("expected non-narrator events to pass through to the moderator")
+	}
+	if event.Data["content"] != "secret" {
+		t.Fatalf("expected moderator to keep full payload, got %v", event.Data)
+	}
+}
@@ -0,0 +1,57 @@
+// night_result_whisper.go — 夜晚信息私聊
+//
+// 信息角色的结果 (night.info 的 message，或带 result 的 night.action.completed)
+// 先以行动玩家本人视角经 projection 投影 (去掉 is_false 等说书人字段)，
+// 再由 AutoDM 私聊给该玩家本人，其他玩家只能看到 whisper.sent 被投影过滤后的空结果。
+//
+// [IN]  internal/projection（行动者私有视角）
+// [IN]  internal/types（Event）
+// [OUT] autodm.go（ProcessQueuedEvent 在编排前拦截，结果不进入 LLM）
+// [POS] AutoDM 的夜晚结果私密投递
+package agent
+
+import (
+	"encoding/json"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/projection"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// nightResultPrefixes introduces the whispered result per language.
+var nightResultPrefixes = map[string]string{
+	LanguageChinese: "🌙 你的夜晚信息：",
+	LanguageEnglish: "🌙 Your night information: ",
+}
+
+// nightResultWhisper returns the acting player and the private message for an
+// information result, as that player is allowed to see it.
+func nightResultWhisper(lang string, ev types.Event) (string, string, bool) {
+	if ev.EventType != "night.info" && ev.EventType != "night.action.completed" {
+		return "", "", false
+	}
+	var raw map[string]string
+	_ = json.Unmarshal(ev.Payload, &raw)
+	actor := raw["user_id"]
+	if actor == "" {
+		return "", "", false
+	}
+	projected := projection.ProjectEvent(ev, engine.NewState(ev.RoomID), types.Viewer{UserID: actor})
+	if projected == nil {
+		return "", "", false
+	}
+	var visible map[string]string
+	_ = json.Unmarshal(projected.Data, &visible)
+	result := visible["message"]
+	if ev.EventType == "night.action.completed" {
+		result = visible["result"]
+	}
+	if result == "" {
+		return "", "", false
+	}
+	prefix, ok := nightResultPrefixes[lang]
+	if !ok {
+		prefix = nightResultPrefixes[LanguageChinese]
+	}
+	return actor, prefix + result, true
+}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestFortuneTellerReadIsWhisperedToTheFortuneTellerOnly(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true})
+	dispatcher := &recordingDispatcher{}
+	a.SetDispatcher(dispatcher, nil)
+	a.SetTranslator(nil)
+
+	payload, _ := json.Marshal(map[string]string{
+		"user_id":   "ft",
+		"role_id":   "fortuneteller",
+		"info_type": "demon_check",
+		"message":   "是的，其中有一位是恶魔",
+		"is_false":  "true",
+	})
+	ev := types.Event{RoomID: "room-1", EventType: "night.info", ActorUserID: "autodm", Payload: payload}
+	if err := a.ProcessQueuedEvent(context.Background(), ev); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+
+	if len(dispatcher.cmds) != 1 || dispatcher.cmds[0].Type != "whisper" {
+		t.Fatalf("expected exactly one whisper, got %+v", dispatcher.cmds)
+	}
+	var p map[string]string
+	_ = json.Unmarshal(dispatcher.cmds[0].Payload, &p)
+	if p["to_user_id"] != "ft" {
+		t.Fatalf("whisper sent to %q, want the fortune teller", p["to_user_id"])
+	}
+	if !strings.Contains(p["message"], "其中有一位是恶魔") || strings.Contains(p["message"], "true") {
+		t.Fatalf("unexpected whisper text %q", p["message"])
+	}
+}
+
+func TestCompletedActionResultIsWhisperedToActor(t *testing.T) {
+	payload, _ := json.Marshal(map[string]string{"user_id": "ft", "role_id": "fortuneteller", "result": "no"})
+	ev := types.Event{RoomID: "room-1", EventType: "night.action.completed", Payload: payload}
+	to, msg, ok := nightResultWhisper(LanguageEnglish, ev)
+	if !ok || to != "ft" || msg != "🌙 Your night information: no" {
+		t.Fatalf("unexpected whisper %q to %q (ok=%v)", msg, to, ok)
+	}
+
+	payload, _ = json.Marshal(map[string]string{"user_id": "monk", "role_id": "monk", "targets": `["p2"]`})
+	if _, _, ok := nightResultWhisper(LanguageEnglish, types.Event{EventType: "night.action.completed", Payload: payload}); ok {
+		t.Fatal("expected no whisper for an action without a result")
+	}
+}
@@ -0,0 +1,30 @@
+// night_timeout_notice.go — 夜晚行动超时的温和旁白
+//
+// 房间计时器代玩家完成夜晚行动后（night.action.completed 带 reason=timeout），
+// AutoDM 发一条不点名、不透露角色的公开消息，安抚等待的玩家，不经过 LLM。
+//
+// [IN]  internal/types（Event）
+// [OUT] autodm.go（ProcessQueuedEvent 在编排前拦截）
+// [POS] AutoDM 对超时行动的固定旁白
+package agent
+
+import (
+	"encoding/json"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+const nightTimeoutNoticeMessage = "🌙 有位玩家似乎睡得太沉了，说书人已替其完成今晚的行动，夜晚继续。"
+
+// nightTimeoutNotice returns the public notice for a timed-out night action.
+func nightTimeoutNotice(ev types.Event) (string, bool) {
+	if ev.EventType != "night.action.completed" {
+		return "", false
+	}
+	var payload map[string]string
+	_ = json.Unmarshal(ev.Payload, &payload)
+	if payload["reason"] != "timeout" {
+		return "", false
+	}
+	return nightTimeoutNoticeMessage, true
+}
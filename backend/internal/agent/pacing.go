@@ -0,0 +1,99 @@
+// Package agent 整局节奏：按事件时间戳调整白天讨论时长
+//
+// OnEvent 记录每个房间的阶段切换、提名与公开发言时间戳 (每局开始时清空)。每个白天开始
+// (phase.day) 且房间开启了讨论自动推进 (Config.DiscussionDurationSec > 0) 时，
+// subagent.ComputePacing 给出节奏信号，Moderator.DiscussionBudget 算出本次讨论预算；
+// 与配置时长不同时以 set_timer (timer_type=discussion) 改写讨论截止时间，房间据此重新计时。
+// 人类 DM 接管时只记录不调整。
+//
+// [IN]  internal/engine（阶段与讨论时长配置）
+// [IN]  internal/agent/subagent（ComputePacing / DiscussionBudget）
+// [OUT] autodm.go（OnEvent 记录与调整）
+// [POS] Auto-DM 的整局节奏控制，与 discussion_nudge.go 的冷场提醒互补
+package agent
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/subagent"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// maxPacingSamples bounds each room's pacing log; older samples are dropped.
+const maxPacingSamples = 1000
+
+// pacingEventTypes are the events pacing is computed from.
+var pacingEventTypes = map[string]bool{
+	"phase.first_night":  true,
+	"phase.night":        true,
+	"phase.day":          true,
+	"nomination.created": true,
+	"public.chat":        true,
+}
+
+// trackPacing records ev's timestamp and, when a day starts, sets its discussion budget.
+func (a *AutoDM) trackPacing(ev types.Event, raw interface{}) {
+	state, ok := raw.(engine.State)
+	if !ok || ev.RoomID == "" {
+		return
+	}
+	if ev.EventType == "game.started" {
+		a.mu.Lock()
+		delete(a.pacing, ev.RoomID)
+		a.mu.Unlock()
+		return
+	}
+	if !pacingEventTypes[ev.EventType] {
+		return
+	}
+	at := time.UnixMilli(ev.ServerTimestampMs)
+	if ev.ServerTimestampMs == 0 {
+		at = time.Now()
+	}
+	a.mu.Lock()
+	samples := append(a.pacing[ev.RoomID], subagent.PacingSample{Type: ev.EventType, At: at})
+	if len(samples) > maxPacingSamples {
+		samples = samples[len(samples)-maxPacingSamples:]
+	}
+	a.pacing[ev.RoomID] = samples
+	a.mu.Unlock()
+
+	if ev.EventType == "phase.day" && !state.AutoDMPaused && state.Config.DiscussionDurationSec > 0 {
+		a.setDiscussionBudget(ev.RoomID, at, samples, time.Duration(state.Config.DiscussionDurationSec)*time.Second)
+	}
+}
+
+// discussionBudget is the pacing-adjusted discussion time for the room's current day.
+func (a *AutoDM) discussionBudget(samples []subagent.PacingSample, base time.Duration) (time.Duration, subagent.Pacing) {
+	pacing := subagent.ComputePacing(samples, base)
+	return a.orchestrator.Moderator().DiscussionBudget(base, pacing), pacing
+}
+
+// setDiscussionBudget moves the discussion deadline when pacing changes the budget.
+func (a *AutoDM) setDiscussionBudget(roomID string, dayStart time.Time, samples []subagent.PacingSample, base time.Duration) {
+	budget, pacing := a.discussionBudget(samples, base)
+	if budget == base {
+		return
+	}
+	a.logger.Info("AutoDM adjusted discussion time", "room_id", roomID, "signal", pacing.Signal,
+		"base", base, "budget", budget, "avg_day", pacing.AvgDay, "nomination_gap", pacing.NominationGap)
+	payload, _ := json.Marshal(map[string]string{
+		"timer_type": "discussion",
+		"deadline":   strconv.FormatInt(dayStart.Add(budget).UnixMilli(), 10),
+	})
+	cmdID := generateCommandID()
+	cmd := types.CommandEnvelope{
+		CommandID:      cmdID,
+		IdempotencyKey: cmdID,
+		RoomID:         roomID,
+		Type:           "set_timer",
+		ActorUserID:    "autodm",
+		Payload:        payload,
+	}
+	if err := a.dispatchCommand(cmd); err != nil {
+		a.logger.Error("Failed to set AutoDM discussion timer", "error", err, "room_id", roomID)
+	}
+}
@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestNewDayAfterRapidNominationsShortensDiscussionTimer(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true})
+	dispatcher := &recordingDispatcher{}
+	a.SetDispatcher(dispatcher, nil)
+	state := engine.NewState("room-1")
+	state.Phase = engine.PhaseDay
+	state.Config.DiscussionDurationSec = 300
+
+	start := time.Now()
+	feed := func(eventType string, at time.Duration) {
+		a.trackPacing(types.Event{RoomID: "room-1", EventType: eventType, ServerTimestampMs: start.Add(at).UnixMilli()}, state)
+	}
+	feed("phase.day", 0)
+	if len(dispatcher.cmds) != 0 {
+		t.Fatalf("expected the first day to keep the configured timer, got %v", dispatcher.cmds)
+	}
+	feed("nomination.created", 5*time.Minute)
+	feed("nomination.created", 5*time.Minute+15*time.Second)
+	feed("phase.night", 7*time.Minute)
+	feed("phase.day", 10*time.Minute)
+
+	if len(dispatcher.cmds) != 1 || dispatcher.cmds[0].Type != "set_timer" {
+		t.Fatalf("expected one set_timer command, got %v", dispatcher.cmds)
+	}
+	var payload map[string]string
+	_ = json.Unmarshal(dispatcher.cmds[0].Payload, &payload)
+	want := strconv.FormatInt(start.Add(10*time.Minute+200*time.Second).UnixMilli(), 10)
+	if payload["timer_type"] != "discussion" || payload["deadline"] != want {
+		t.Fatalf("expected a discussion deadline 200s into the day (%s), got %v", want, payload)
+	}
+}
@@ -0,0 +1,46 @@
+// Package agent 事件的同步/异步处理策略
+//
+// 配置了任务队列时 OnEvent 默认把事件交给队列异步处理；InlineEventTypes 列出的事件类型
+// 即使队列可用也在当前调用中同步处理 (如阶段切换要求低延迟，聊天/摘要仍走异步)。
+// 条目为精确事件类型，或以 ".*" 结尾的前缀 (如 "phase.*" 匹配 phase.day、phase.night)。
+//
+// [IN]  Config.InlineEventTypes（启动时由 AUTODM_INLINE_EVENT_TYPES 配置）
+// [OUT] autodm.go（OnEvent 决定是否入队）
+// [POS] AutoDM 事件分发策略
+package agent
+
+import "strings"
+
+// inlinePolicy matches event types that must be processed inline.
+type inlinePolicy struct {
+	exact    map[string]bool
+	prefixes []string
+}
+
+func newInlinePolicy(eventTypes []string) inlinePolicy {
+	p := inlinePolicy{exact: make(map[string]bool)}
+	for _, t := range eventTypes {
+		t = strings.TrimSpace(t)
+		switch {
+		case t == "":
+		case strings.HasSuffix(t, ".*"):
+			p.prefixes = append(p.prefixes, strings.TrimSuffix(t, "*"))
+		default:
+			p.exact[t] = true
+		}
+	}
+	return p
+}
+
+// inline reports whether eventType skips the task queue.
+func (p inlinePolicy) inline(eventType string) bool {
+	if p.exact[eventType] {
+		return true
+	}
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestInlinePolicyKeepsPhaseChangesOffTheQueue(t *testing.T) {
+	queue := &recordingQueue{}
+	dispatcher := &recordingDispatcher{}
+	a := NewAutoDM(Config{Enabled: true, TaskQueue: queue, InlineEventTypes: []string{"phase.*"}})
+	a.SetDispatcher(dispatcher, nil)
+	a.SetTranslator(nil)
+
+	a.OnEvent(context.Background(), types.Event{RoomID: "room-1", EventType: "phase.night", Payload: []byte(`{}`)}, nil)
+	if len(queue.tasks) != 0 {
+		t.Fatalf("expected phase.night to bypass the queue, got %d tasks", len(queue.tasks))
+	}
+	if len(dispatcher.cmds) == 0 {
+		t.Fatal("expected phase.night to be narrated inline")
+	}
+
+	a.OnEvent(context.Background(), types.Event{RoomID: "room-1", EventType: "public.chat", ActorUserID: "p1", Payload: []byte(`{"message":"hi"}`)}, nil)
+	if len(queue.tasks) != 1 {
+		t.Fatalf("expected public.chat to be enqueued, got %d tasks", len(queue.tasks))
+	}
+}
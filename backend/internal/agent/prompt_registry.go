@@ -0,0 +1,27 @@
+// Package agent 子代理系统提示词覆盖的加载入口
+//
+// cmd/server 启动时用 LoadPromptRegistry 读取 AUTODM_PROMPT_TEMPLATES 指定的 JSON 文件，
+// 结果经 Config.Prompts 交给编排器的各子代理，经 NewComposer 交给 AI 组合器。
+//
+// [IN]  subagent/prompts.go（模板注册表）
+// [OUT] cmd/server（main.go 初始化 AutoDM 与 Composer）
+// [POS] 提示词模板的外部配置入口，隔离 subagent 内部依赖
+package agent
+
+import (
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/subagent"
+)
+
+// LoadPromptRegistry builds the registry for persona and language, applying path's overrides when set.
+func LoadPromptRegistry(path, persona, language string) (*PromptRegistry, error) {
+	prompts := subagent.NewPromptRegistry(persona, language)
+	if path == "" {
+		return prompts, nil
+	}
+	if err := prompts.LoadFile(path); err != nil {
+		return nil, fmt.Errorf("agent.LoadPromptRegistry: %w", err)
+	}
+	return prompts, nil
+}
@@ -0,0 +1,43 @@
+// Package agent 反思与教训回写
+//
+// Reflect 把 Reflection.Lessons 交给编排器写入记忆，之后处理相关事件时会被注入系统提示词。
+// ProcessQueuedEvent 处理失败时由 reflectOnFailure 自动生成一条教训 (事件类型 + 按字符截断的错误，同 capMessage)。
+//
+// [IN]  internal/agent/core（RecordLessons）
+// [OUT] autodm.go（ProcessQueuedEvent 失败后反思）
+// [POS] Auto-DM 的反思反馈环入口
+package agent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// maxLessonErrorLen caps how many characters of an error text a lesson quotes (capMessage).
+const maxLessonErrorLen = 120
+
+// Reflect stores a reflection's lessons so later prompts can take them into account.
+func (a *AutoDM) Reflect(ctx context.Context, r Reflection) {
+	if len(r.Lessons) == 0 {
+		return
+	}
+	a.orchestrator.RecordLessons(ctx, r.Lessons)
+	a.logger.Info("AutoDM recorded reflection lessons", "room_id", r.RoomID, "count", len(r.Lessons))
+}
+
+// reflectOnFailure turns a failed event into a lesson for the next similar event.
+func (a *AutoDM) reflectOnFailure(ctx context.Context, ev types.Event, err error) {
+	if err == nil {
+		return
+	}
+	reason := capMessage(err.Error(), maxLessonErrorLen)
+	a.Reflect(ctx, Reflection{
+		RoomID:    ev.RoomID,
+		Summary:   fmt.Sprintf("handling %s failed", ev.EventType),
+		Lessons:   []string{fmt.Sprintf("Handling %s failed last time (%s); keep the response to it short and rule-safe.", ev.EventType, reason)},
+		CreatedAt: time.Now().UTC(),
+	})
+}
@@ -0,0 +1,46 @@
+// rule_context.go — 规则检索的角色定向
+//
+// 事件涉及具体角色时（如杀手开枪、贞洁者触发），RAG 检索应优先返回该角色的规则块。
+// ruleRoleFilter 从事件数据推断角色，生成 role_name 过滤条件供 buildRuleQuery 使用。
+//
+// [IN]  types.go（Event）
+// [OUT] autodm.go（buildRuleQuery / injectRuleContext）
+// [POS] AutoDM 规则上下文注入的角色偏置层
+package agent
+
+import "strings"
+
+// ruleRoleByCause 由死亡原因推断相关角色
+var ruleRoleByCause = map[string]string{
+	"slayer":         "slayer",
+	"virgin_ability": "virgin",
+}
+
+// ruleRoleByEventType 由事件类型推断相关角色
+var ruleRoleByEventType = map[string]string{
+	"slayer.shot": "slayer",
+}
+
+// ruleRoleFilter 返回事件相关角色的 role_name 过滤条件；无法确定角色时返回 nil。
+func ruleRoleFilter(event Event) map[string]string {
+	role := ""
+	for _, key := range []string{"role_id", "role"} {
+		if v, ok := event.Data[key].(string); ok && v != "" {
+			role = v
+			break
+		}
+	}
+	if role == "" {
+		if cause, ok := event.Data["cause"].(string); ok {
+			role = ruleRoleByCause[cause]
+		}
+	}
+	if role == "" {
+		role = ruleRoleByEventType[event.Type]
+	}
+	if role == "" {
+		return nil
+	}
+	role = strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(role))
+	return map[string]string{"role_name": role}
+}
@@ -0,0 +1,29 @@
+package agent
+
+import "testing"
+
+func TestBuildRuleQueryPassesRoleFilterForSlayerDeath(t *testing.T) {
+	event := Event{Type: "death", Data: map[string]interface{}{"cause": "slayer", "user_id": "p3"}}
+
+	query, filter := buildRuleQuery(event)
+	if query == "" {
+		t.Fatal("expected a rule query for death events")
+	}
+	if filter["role_name"] != "slayer" {
+		t.Fatalf("expected slayer role filter, got %v", filter)
+	}
+}
+
+func TestBuildRuleQueryUsesRoleForOtherwiseUnqueriedEvents(t *testing.T) {
+	query, filter := buildRuleQuery(Event{Type: "night.info", Data: map[string]interface{}{"role_id": "Fortune Teller"}})
+	if filter["role_name"] != "fortuneteller" {
+		t.Fatalf("expected normalized role filter, got %v", filter)
+	}
+	if query == "" {
+		t.Fatal("expected a role-based query when the event names a role")
+	}
+
+	if query, filter := buildRuleQuery(Event{Type: "public.chat", Data: map[string]interface{}{}}); query != "" || filter != nil {
+		t.Fatalf("expected no query for role-less chat, got %q %v", query, filter)
+	}
+}
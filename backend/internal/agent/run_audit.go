@@ -0,0 +1,131 @@
+// Package agent AutoDM 运行与工具调用审计
+//
+// ProcessQueuedEvent 开始时 startRun 创建 AgentRun (输入摘要为事件载荷的 sha256) 并放入 ctx；
+// 处理路径用 recordPlan 记下所选动作 (固定公告/私聊或编排器响应)，输出摘要为计划的 sha256；
+// invokeTool 在该 ctx 下调用 MCP 工具时记录 ToolCallAudit；finishRun 以 ok/error 与耗时落盘。
+// 未产生计划且未出错的事件 (AutoDM 不处理的类型) 不落盘。未配置 AgentRunStore 时全部跳过，
+// 审计写入失败只记日志，不影响游戏。
+//
+// [IN]  internal/mcp（ToolCall/ToolResult）
+// [OUT] autodm.go（ProcessQueuedEvent、sendMessage）
+// [POS] Auto-DM 行为的审计钩子
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/mcp"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// autoDMAgentName names AutoDM runs in the run store.
+const autoDMAgentName = "autodm"
+
+type runContextKey struct{}
+
+// runFromContext returns the run the ctx belongs to, if any.
+func runFromContext(ctx context.Context) *AgentRun {
+	run, _ := ctx.Value(runContextKey{}).(*AgentRun)
+	return run
+}
+
+// SetRunStore enables run and tool-call recording (nil disables it).
+func (a *AutoDM) SetRunStore(store AgentRunStore) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.runStore = store
+}
+
+func (a *AutoDM) currentRunStore() AgentRunStore {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.runStore
+}
+
+// startRun creates an AgentRun for ev and attaches it to ctx.
+func (a *AutoDM) startRun(ctx context.Context, ev types.Event) (context.Context, *AgentRun) {
+	store := a.currentRunStore()
+	if store == nil {
+		return ctx, nil
+	}
+	digest := sha256.Sum256(ev.Payload)
+	run := &AgentRun{
+		ID:          uuid.NewString(),
+		RoomID:      ev.RoomID,
+		AgentName:   autoDMAgentName,
+		SeqFrom:     ev.Seq,
+		SeqTo:       ev.Seq,
+		InputDigest: hex.EncodeToString(digest[:]),
+		Status:      "running",
+		CreatedAt:   time.Now().UTC(),
+	}
+	return context.WithValue(ctx, runContextKey{}, run), run
+}
+
+// recordPlan notes what the run decided to do; kind names the handling path.
+func recordPlan(ctx context.Context, kind string, detail interface{}) {
+	run := runFromContext(ctx)
+	if run == nil {
+		return
+	}
+	run.PlanJSON, _ = json.Marshal(map[string]interface{}{"kind": kind, "detail": detail})
+	digest := sha256.Sum256(run.PlanJSON)
+	run.OutputDigest = hex.EncodeToString(digest[:])
+}
+
+// finishRun stores the run's final status and latency if it planned something or failed.
+func (a *AutoDM) finishRun(ctx context.Context, run *AgentRun, runErr error) {
+	store := a.currentRunStore()
+	if store == nil || run == nil || (run.PlanJSON == nil && runErr == nil) {
+		return
+	}
+	run.Status = "ok"
+	if runErr != nil {
+		run.Status = "error"
+		run.ErrorText = runErr.Error()
+	}
+	run.LatencyMs = time.Since(run.CreatedAt).Milliseconds()
+	if err := store.SaveRun(context.WithoutCancel(ctx), *run); err != nil {
+		a.logger.Warn("failed to save agent run", "error", err, "run_id", run.ID)
+	}
+}
+
+// invokeTool calls an MCP tool and audits it when ctx belongs to a run.
+func (a *AutoDM) invokeTool(ctx context.Context, registry *mcp.Registry, call mcp.ToolCall) *mcp.ToolResult {
+	started := time.Now()
+	if call.Caller == "" {
+		call.Caller = mcp.CallerAutoDM
+	}
+	run := runFromContext(ctx)
+	if call.RoomID == "" && run != nil {
+		call.RoomID = run.RoomID
+	}
+	result := registry.Invoke(ctx, call)
+	store := a.currentRunStore()
+	if run == nil || store == nil {
+		return result
+	}
+	audit := ToolCallAudit{
+		ID:         call.ID,
+		RunID:      run.ID,
+		ToolName:   call.ToolName,
+		Args:       call.Parameters,
+		Error:      result.Error,
+		DurationMs: time.Since(started).Milliseconds(),
+		CreatedAt:  started.UTC(),
+		RoomID:     run.RoomID,
+	}
+	if result.Result != nil {
+		audit.Result, _ = json.Marshal(result.Result)
+	}
+	if err := store.SaveToolCall(context.WithoutCancel(ctx), audit); err != nil {
+		a.logger.Warn("failed to save tool call audit", "error", err, "tool", call.ToolName)
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+// Package agent 跨房间的 AutoDM 并发运行上限
+//
+// 所有房间共享一个信号量：编排器运行与 Bot 发言 (LLM 调用) 前须取得槽位，超出 MaxConcurrentRuns 的运行
+// 排队等待，最多等 RunQueueTimeout，超时则放弃本次运行 (走兜底消息)。槽位占用与排队数
+// 通过 Prometheus 指标暴露。MaxConcurrentRuns<=0 时不限流。
+//
+// [IN]  internal/observability（槽位占用、排队与超时指标）
+// [OUT] autodm.go（ProcessQueuedEvent 调用编排器前取槽）、bot_chat_model.go（Bot 发言前取槽）
+// [POS] Auto-DM 对 LLM 提供方的全局背压
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/observability"
+)
+
+// defaultRunQueueTimeout bounds how long a run waits for a slot when none is configured.
+const defaultRunQueueTimeout = 30 * time.Second
+
+// ErrRunQueueTimeout is returned when no run slot frees up in time.
+var ErrRunQueueTimeout = errors.New("timed out waiting for an AutoDM run slot")
+
+// runLimiter is a counting semaphore shared by all rooms.
+type runLimiter struct {
+	slots   chan struct{}
+	wait    time.Duration
+	metrics *observability.Metrics
+}
+
+// newRunLimiter returns nil (unlimited) when max <= 0.
+func newRunLimiter(max int, wait time.Duration, metrics *observability.Metrics) *runLimiter {
+	if max <= 0 {
+		return nil
+	}
+	if wait <= 0 {
+		wait = defaultRunQueueTimeout
+	}
+	return &runLimiter{slots: make(chan struct{}, max), wait: wait, metrics: metrics}
+}
+
+// acquire waits for a slot until the queue deadline or ctx ends; call release when done.
+func (l *runLimiter) acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	if l.metrics != nil {
+		l.metrics.AgentRunsQueued.Inc()
+		defer l.metrics.AgentRunsQueued.Dec()
+	}
+	timer := time.NewTimer(l.wait)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+	case <-timer.C:
+		if l.metrics != nil {
+			l.metrics.AgentRunQueueTimeouts.Inc()
+		}
+		return nil, ErrRunQueueTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if l.metrics != nil {
+		l.metrics.AgentRunSlotsInUse.Inc()
+	}
+	return func() {
+		<-l.slots
+		if l.metrics != nil {
+			l.metrics.AgentRunSlotsInUse.Dec()
+		}
+	}, nil
+}
+
+// processLimited runs the orchestrator for event once a run slot is free. The event
+// timeout starts after the slot is acquired.
+func (a *AutoDM) processLimited(ctx context.Context, event Event) (*Response, error) {
+	release, err := a.runSlots.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("agent.processLimited: %w", err)
+	}
+	defer release()
+
+	processCtx, cancel := context.WithTimeout(ctx, a.eventTimeout)
+	defer cancel()
+	return a.ProcessEvent(processCtx, event)
+}
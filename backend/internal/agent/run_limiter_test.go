@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/observability"
+)
+
+func gaugeValue(g prometheus.Gauge) float64 {
+	var m dto.Metric
+	_ = g.Write(&m)
+	return m.GetGauge().GetValue()
+}
+
+func TestRunLimiterSerializesRoomsAtLimitOne(t *testing.T) {
+	metrics := observability.NewMetrics(prometheus.NewRegistry())
+	a := NewAutoDM(Config{Enabled: true, MaxConcurrentRuns: 1, Metrics: metrics})
+
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	for _, room := range []string{"room-a", "room-b"} {
+		wg.Add(1)
+		go func(room string) {
+			defer wg.Done()
+			release, err := a.runSlots.acquire(context.Background())
+			if err != nil {
+				t.Errorf("%s: acquire: %v", room, err)
+				return
+			}
+			defer release()
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			if got := gaugeValue(metrics.AgentRunSlotsInUse); got != 1 {
+				t.Errorf("%s: expected one slot in use, got %v", room, got)
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}(room)
+	}
+	wg.Wait()
+
+	if maxRunning != 1 {
+		t.Fatalf("expected runs to serialize, saw %d at once", maxRunning)
+	}
+	if got := gaugeValue(metrics.AgentRunSlotsInUse); got != 0 {
+		t.Fatalf("expected all slots released, got %v in use", got)
+	}
+}
+
+func TestRunLimiterGivesUpAfterQueueTimeout(t *testing.T) {
+	l := newRunLimiter(1, 10*time.Millisecond, nil)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+	defer release()
+
+	if _, err := l.acquire(context.Background()); !errors.Is(err, ErrRunQueueTimeout) {
+		t.Fatalf("expected ErrRunQueueTimeout while the slot is held, got %v", err)
+	}
+}
+
+func TestBotChatWaitsForARunSlot(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true, MaxConcurrentRuns: 1, RunQueueTimeout: 20 * time.Millisecond})
+	release, err := a.runSlots.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer release()
+
+	if _, err := a.BotChatModel().BotChat(context.Background(), "system", "hi"); !errors.Is(err, ErrRunQueueTimeout) {
+		t.Fatalf("expected bot chat to queue behind the busy run slot, got %v", err)
+	}
+}
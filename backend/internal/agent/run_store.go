@@ -0,0 +1,144 @@
+// Package agent AutoDM 运行记录存储
+//
+// 每次 ProcessQueuedEvent 记为一次 AgentRun，运行期间经 MCP 注册表发起的工具调用记为
+// ToolCallAudit (参数、结果、耗时)，供 DM 调试查询。AgentRunStore 可替换：
+// MemoryRunStore 为进程内有界实现 (只保留最近的记录，重启即丢失)；
+// 生产环境由 cmd/server 适配 store 的 agent_runs/agent_tool_calls 表持久化。
+//
+// [OUT] run_audit.go（写入运行与工具调用）
+// [OUT] api（GET /v1/rooms/{room_id}/agent/runs、/agent/tool-calls）
+// [POS] Auto-DM 的可观测性存储层
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrRunNotFound is returned by GetRun for unknown run IDs.
+var ErrRunNotFound = errors.New("agent run not found")
+
+// AgentRunStore persists AutoDM runs and the tool calls made during them.
+type AgentRunStore interface {
+	// SaveRun inserts or replaces the run with the same ID.
+	SaveRun(ctx context.Context, run AgentRun) error
+	SaveToolCall(ctx context.Context, call ToolCallAudit) error
+	// ListRuns returns a room's runs, newest first, without their tool calls.
+	ListRuns(ctx context.Context, roomID string, limit, offset int) ([]AgentRun, error)
+	// GetRun returns one run with its tool calls, or ErrRunNotFound.
+	GetRun(ctx context.Context, id string) (*AgentRun, error)
+	// ListToolCalls returns a room's tool calls, newest first.
+	ListToolCalls(ctx context.Context, roomID string, q ToolCallQuery) ([]ToolCallAudit, error)
+}
+
+// ToolCallQuery filters and pages ListToolCalls.
+type ToolCallQuery struct {
+	Tool   string // exact tool name; empty matches all
+	Limit  int
+	Offset int
+}
+
+// defaultMemoryRunEntries bounds MemoryRunStore when no size is given.
+const defaultMemoryRunEntries = 1000
+
+// MemoryRunStore keeps the most recent runs and tool calls in memory.
+type MemoryRunStore struct {
+	mu       sync.RWMutex
+	max      int
+	runs     map[string]AgentRun
+	runOrder []string
+	calls    []ToolCallAudit
+}
+
+// NewMemoryRunStore keeps at most maxEntries runs and maxEntries tool calls (<=0 uses 1000).
+func NewMemoryRunStore(maxEntries int) *MemoryRunStore {
+	if maxEntries <= 0 {
+		maxEntries = defaultMemoryRunEntries
+	}
+	return &MemoryRunStore{max: maxEntries, runs: make(map[string]AgentRun)}
+}
+
+func (m *MemoryRunStore) SaveRun(_ context.Context, run AgentRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.runs[run.ID]; !exists {
+		m.runOrder = append(m.runOrder, run.ID)
+	}
+	m.runs[run.ID] = run
+	for len(m.runOrder) > m.max {
+		delete(m.runs, m.runOrder[0])
+		m.runOrder = m.runOrder[1:]
+	}
+	return nil
+}
+
+func (m *MemoryRunStore) SaveToolCall(_ context.Context, call ToolCallAudit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, call)
+	if len(m.calls) > m.max {
+		m.calls = m.calls[len(m.calls)-m.max:]
+	}
+	return nil
+}
+
+func (m *MemoryRunStore) ListToolCalls(_ context.Context, roomID string, q ToolCallQuery) ([]ToolCallAudit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []ToolCallAudit{}
+	skipped := 0
+	for i := len(m.calls) - 1; i >= 0; i-- {
+		c := m.calls[i]
+		if c.RoomID != roomID || (q.Tool != "" && c.ToolName != q.Tool) {
+			continue
+		}
+		if skipped < q.Offset {
+			skipped++
+			continue
+		}
+		if q.Limit > 0 && len(out) >= q.Limit {
+			break
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func (m *MemoryRunStore) ListRuns(_ context.Context, roomID string, limit, offset int) ([]AgentRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []AgentRun{}
+	skipped := 0
+	for i := len(m.runOrder) - 1; i >= 0; i-- {
+		run := m.runs[m.runOrder[i]]
+		if run.RoomID != roomID {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		out = append(out, run)
+	}
+	return out, nil
+}
+
+func (m *MemoryRunStore) GetRun(_ context.Context, id string) (*AgentRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	run, ok := m.runs[id]
+	if !ok {
+		return nil, ErrRunNotFound
+	}
+	run.ToolCalls = nil
+	for _, c := range m.calls {
+		if c.RunID == id {
+			run.ToolCalls = append(run.ToolCalls, c)
+		}
+	}
+	return &run, nil
+}
@@ -37,12 +37,13 @@ Respond with ONLY a JSON object, no explanation:
 
 // AIComposer uses LLM to compose game roles.
 type AIComposer struct {
-	router *llm.Router
+	router  *llm.Router
+	prompts *PromptRegistry
 }
 
-// NewAIComposer creates a new AI-powered composer.
-func NewAIComposer(router *llm.Router) *AIComposer {
-	return &AIComposer{router: router}
+// NewAIComposer creates a new AI-powered composer; nil prompts uses the built-in templates.
+func NewAIComposer(router *llm.Router, prompts *PromptRegistry) *AIComposer {
+	return &AIComposer{router: router, prompts: prompts}
 }
 
 // ComposeRoles asks the LLM to compose a balanced role set.
@@ -53,7 +54,8 @@ func (c *AIComposer) ComposeRoles(ctx context.Context, req game.ComposeRequest)
 	}
 
 	userMsg := buildComposePrompt(req.PlayerCount, dist)
-	response, err := c.router.SimpleChat(ctx, llm.TaskReasoning, composerSystemPrompt, userMsg)
+	systemPrompt := c.prompts.Render(ctx, AgentComposer, PromptVars{PlayerCount: req.PlayerCount, Edition: req.Edition})
+	response, err := c.router.SimpleChat(ctx, llm.TaskReasoning, systemPrompt, userMsg)
 	if err != nil {
 		return nil, fmt.Errorf("subagent.AIComposer: llm call failed: %w", err)
 	}
@@ -8,29 +8,24 @@ package subagent
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
 )
 
-const moderatorPrompt = `You are the Moderator Agent for Blood on the Clocktower.
-Manage game flow, phases, nominations, and voting. Be impartial and follow rules precisely.
-Current game state: %s`
-
 // Moderator manages game flow and player interactions.
 type Moderator struct {
-	router *llm.Router
+	router  *llm.Router
+	prompts *PromptRegistry
 }
 
-// NewModerator creates a new Moderator agent.
-func NewModerator(router *llm.Router) *Moderator {
-	return &Moderator{router: router}
+// NewModerator creates a new Moderator agent; nil prompts uses the built-in templates.
+func NewModerator(router *llm.Router, prompts *PromptRegistry) *Moderator {
+	return &Moderator{router: router, prompts: prompts}
 }
 
 // Process handles moderator requests.
 func (m *Moderator) Process(ctx context.Context, gs GameStateView, query string) (string, error) {
-	systemPrompt := fmt.Sprintf(moderatorPrompt, FormatGameState(gs))
-	return m.router.SimpleChat(ctx, llm.TaskReasoning, systemPrompt, query)
+	return m.router.SimpleChat(ctx, llm.TaskReasoning, m.prompts.Render(ctx, AgentModerator, promptVars(gs)), query)
 }
 
 // ValidateNomination checks if a nomination is valid.
@@ -0,0 +1,31 @@
+// Package subagent 主持子代理：白天讨论冷场提醒节奏
+//
+// 讨论阶段每沉默一个 Interval 发一次提醒，按 Levels 逐级升级
+// (温和 → 直接 → "即将推进")，全部用完后不再提醒。是否处于讨论阶段、
+// 何时重置沉默计时由调用方 (agent/discussion_nudge.go) 决定。
+//
+// [OUT] agent（讨论冷场提醒）
+// [POS] 主持人的讨论节奏控制
+package subagent
+
+import "time"
+
+// NudgeConfig controls discussion-phase nudges: one per Interval of silence,
+// escalating through Levels.
+type NudgeConfig struct {
+	Interval time.Duration
+	Levels   []string
+}
+
+// DiscussionNudge returns the nudge due after silence, or false when none is due
+// (cadence disabled, not yet silent for a full interval, or every level used).
+func (m *Moderator) DiscussionNudge(cfg NudgeConfig, silence time.Duration) (string, bool) {
+	if cfg.Interval <= 0 {
+		return "", false
+	}
+	n := int(silence / cfg.Interval)
+	if n < 1 || n > len(cfg.Levels) {
+		return "", false
+	}
+	return cfg.Levels[n-1], true
+}
@@ -0,0 +1,26 @@
+package subagent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiscussionNudgeEscalatesWithSilence(t *testing.T) {
+	m := NewModerator(nil, nil)
+	cfg := NudgeConfig{Interval: 10 * time.Second, Levels: []string{"gentle", "direct", "advancing soon"}}
+
+	if _, ok := m.DiscussionNudge(cfg, 9*time.Second); ok {
+		t.Fatal("expected no nudge before the first interval")
+	}
+	first, ok := m.DiscussionNudge(cfg, 10*time.Second)
+	if !ok || first != "gentle" {
+		t.Fatalf("expected gentle first nudge, got %q", first)
+	}
+	second, ok := m.DiscussionNudge(cfg, 20*time.Second)
+	if !ok || second == first {
+		t.Fatalf("expected second nudge to escalate, got %q after %q", second, first)
+	}
+	if _, ok := m.DiscussionNudge(cfg, 40*time.Second); ok {
+		t.Fatal("expected nudges to stop after the last level")
+	}
+}
@@ -0,0 +1,175 @@
+// Package subagent 主持子代理：整局节奏信号
+//
+// 由事件时间戳算出已完成的白天/夜晚平均时长、最近白天的提名间隔与公开发言速率，
+// 给出节奏信号：白天拖沓 (平均白天远超讨论时长) 或提名接连而至时加速，发言活跃时放缓，
+// 否则保持。DiscussionBudget 按信号缩短或延长下一次白天讨论的时长。
+// 事件的收集与预算的下发由调用方 (agent/pacing.go) 负责。
+//
+// [OUT] agent（白天开始时计算讨论预算）
+// [POS] 主持人的整局节奏控制，与 moderator_nudge.go 的冷场提醒互补
+package subagent
+
+import "time"
+
+// PacingSample is one timestamped game event pacing is computed from.
+type PacingSample struct {
+	Type string
+	At   time.Time
+}
+
+// PacingSignal tells the Moderator which way to push the next discussion.
+type PacingSignal string
+
+const (
+	PacingSpeedUp  PacingSignal = "speed_up"
+	PacingSteady   PacingSignal = "steady"
+	PacingSlowDown PacingSignal = "slow_down"
+)
+
+// Pacing summarizes how fast a game is moving.
+type Pacing struct {
+	AvgDay   time.Duration // mean completed day, phase.day → next night
+	AvgNight time.Duration // mean completed night, night → phase.day
+	// NominationGap is the mean gap between nominations on the latest day with two or more (0 = none)
+	NominationGap time.Duration
+	// ChatPerMinute is the public chat rate of the latest completed day (or the running one if none)
+	ChatPerMinute float64
+	Signal        PacingSignal
+}
+
+const (
+	rapidNominationGap  = 45 * time.Second
+	dragDayFactor       = 3 // a day longer than 3× the discussion time drags
+	activeChatPerMinute = 6.0
+)
+
+// dayWindow is one day's span and activity; end is zero while the day is running.
+type dayWindow struct {
+	start, end  time.Time
+	nominations []time.Time
+	chats       int
+}
+
+// ComputePacing derives the pacing signal from samples in time order; discussion is
+// the room's configured discussion length, the yardstick for a dragging day.
+func ComputePacing(samples []PacingSample, discussion time.Duration) Pacing {
+	days, nights := splitPhases(samples)
+	p := Pacing{AvgDay: meanDaySpan(days), AvgNight: meanSpan(nights), Signal: PacingSteady}
+	for i := len(days) - 1; i >= 0; i-- {
+		if gap := meanGap(days[i].nominations); gap > 0 {
+			p.NominationGap = gap
+			break
+		}
+	}
+	if d, ok := latestDay(days); ok {
+		p.ChatPerMinute = chatRate(d, samples[len(samples)-1].At)
+	}
+	switch {
+	case p.NominationGap > 0 && p.NominationGap <= rapidNominationGap:
+		p.Signal = PacingSpeedUp
+	case discussion > 0 && p.AvgDay > dragDayFactor*discussion:
+		p.Signal = PacingSpeedUp
+	case p.ChatPerMinute >= activeChatPerMinute:
+		p.Signal = PacingSlowDown
+	}
+	return p
+}
+
+// DiscussionBudget scales the base discussion time by the pacing signal:
+// two thirds when speeding up, four thirds when slowing down.
+func (m *Moderator) DiscussionBudget(base time.Duration, p Pacing) time.Duration {
+	switch p.Signal {
+	case PacingSpeedUp:
+		return base * 2 / 3
+	case PacingSlowDown:
+		return base * 4 / 3
+	default:
+		return base
+	}
+}
+
+// splitPhases cuts samples into day windows and completed night spans.
+func splitPhases(samples []PacingSample) ([]dayWindow, [][2]time.Time) {
+	var days []dayWindow
+	var nights [][2]time.Time
+	var nightStart time.Time
+	for _, s := range samples {
+		switch s.Type {
+		case "phase.day":
+			if !nightStart.IsZero() {
+				nights = append(nights, [2]time.Time{nightStart, s.At})
+				nightStart = time.Time{}
+			}
+			days = append(days, dayWindow{start: s.At})
+		case "phase.night", "phase.first_night":
+			if n := len(days); n > 0 && days[n-1].end.IsZero() {
+				days[n-1].end = s.At
+			}
+			nightStart = s.At
+		case "nomination.created":
+			if n := len(days); n > 0 && days[n-1].end.IsZero() {
+				days[n-1].nominations = append(days[n-1].nominations, s.At)
+			}
+		case "public.chat":
+			if n := len(days); n > 0 && days[n-1].end.IsZero() {
+				days[n-1].chats++
+			}
+		}
+	}
+	return days, nights
+}
+
+// latestDay is the last completed day, or the running day when none has completed.
+func latestDay(days []dayWindow) (dayWindow, bool) {
+	for i := len(days) - 1; i >= 0; i-- {
+		if !days[i].end.IsZero() {
+			return days[i], true
+		}
+	}
+	if len(days) == 0 {
+		return dayWindow{}, false
+	}
+	return days[len(days)-1], true
+}
+
+func meanDaySpan(days []dayWindow) time.Duration {
+	spans := make([][2]time.Time, 0, len(days))
+	for _, d := range days {
+		if !d.end.IsZero() {
+			spans = append(spans, [2]time.Time{d.start, d.end})
+		}
+	}
+	return meanSpan(spans)
+}
+
+func meanSpan(spans [][2]time.Time) time.Duration {
+	if len(spans) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, s := range spans {
+		total += s[1].Sub(s[0])
+	}
+	return total / time.Duration(len(spans))
+}
+
+// meanGap is the mean interval between consecutive times, 0 for fewer than two.
+func meanGap(times []time.Time) time.Duration {
+	if len(times) < 2 {
+		return 0
+	}
+	return times[len(times)-1].Sub(times[0]) / time.Duration(len(times)-1)
+}
+
+// chatRate is d's chats per minute, measured up to now while d is still running.
+func chatRate(d dayWindow, now time.Time) float64 {
+	end := d.end
+	if end.IsZero() {
+		end = now
+	}
+	minutes := end.Sub(d.start).Minutes()
+	if minutes < 1 {
+		return 0
+	}
+	return float64(d.chats) / minutes
+}
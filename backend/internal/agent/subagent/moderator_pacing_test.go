@@ -0,0 +1,60 @@
+package subagent
+
+import (
+	"testing"
+	"time"
+)
+
+// pacingDay returns samples for one finished day starting at start, with events at the given offsets.
+func pacingDay(start time.Time, length time.Duration, eventType string, offsets ...time.Duration) []PacingSample {
+	samples := []PacingSample{{Type: "phase.day", At: start}}
+	for _, off := range offsets {
+		samples = append(samples, PacingSample{Type: eventType, At: start.Add(off)})
+	}
+	return append(samples, PacingSample{Type: "phase.night", At: start.Add(length)})
+}
+
+func TestRapidNominationsShortenDiscussionBudget(t *testing.T) {
+	m := NewModerator(nil, nil)
+	base := 5 * time.Minute
+	start := time.Unix(0, 0)
+
+	slow := pacingDay(start, 8*time.Minute, "nomination.created", 3*time.Minute, 6*time.Minute)
+	if got := m.DiscussionBudget(base, ComputePacing(slow, base)); got != base {
+		t.Fatalf("expected spaced-out nominations to keep the %v budget, got %v", base, got)
+	}
+
+	rapid := pacingDay(start, 8*time.Minute, "nomination.created", 5*time.Minute, 5*time.Minute+20*time.Second, 5*time.Minute+40*time.Second)
+	p := ComputePacing(rapid, base)
+	if p.Signal != PacingSpeedUp || p.NominationGap != 20*time.Second {
+		t.Fatalf("expected a speed-up signal with a 20s nomination gap, got %+v", p)
+	}
+	if got := m.DiscussionBudget(base, p); got >= base {
+		t.Fatalf("expected rapid nominations to shorten the %v budget, got %v", base, got)
+	}
+}
+
+func TestPacingSignals(t *testing.T) {
+	base := 5 * time.Minute
+	start := time.Unix(0, 0)
+	var chatty []time.Duration
+	for i := 0; i < 60; i++ {
+		chatty = append(chatty, time.Duration(i)*5*time.Second)
+	}
+
+	tests := []struct {
+		name    string
+		samples []PacingSample
+		want    PacingSignal
+	}{
+		{"dragging day", pacingDay(start, 20*time.Minute, "public.chat"), PacingSpeedUp},
+		{"active chat", pacingDay(start, 6*time.Minute, "public.chat", chatty...), PacingSlowDown},
+		{"quiet day", pacingDay(start, 6*time.Minute, "public.chat", time.Minute), PacingSteady},
+		{"no days yet", []PacingSample{{Type: "phase.first_night", At: start}}, PacingSteady},
+	}
+	for _, tt := range tests {
+		if got := ComputePacing(tt.samples, base).Signal; got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
@@ -13,30 +13,48 @@ import (
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
 )
 
-const narratorPrompt = `You are the Narrator for Blood on the Clocktower.
-Create immersive, atmospheric narration. Keep it concise but evocative.
-Current game state: %s`
-
 // Narrator generates atmospheric game narration.
 type Narrator struct {
-	router *llm.Router
+	router  *llm.Router
+	prompts *PromptRegistry
 }
 
-// NewNarrator creates a new Narrator agent.
-func NewNarrator(router *llm.Router) *Narrator {
-	return &Narrator{router: router}
+// NewNarrator creates a new Narrator agent; nil prompts uses the built-in templates.
+func NewNarrator(router *llm.Router, prompts *PromptRegistry) *Narrator {
+	return &Narrator{router: router, prompts: prompts}
 }
 
 // NarratePhaseChange creates narration for phase transitions.
 func (n *Narrator) NarratePhaseChange(ctx context.Context, gs GameStateView, oldPhase, newPhase string) (string, error) {
-	prompt := fmt.Sprintf("Create a brief atmospheric narration for phase change from %s to %s. Day %d, %d alive.",
-		oldPhase, newPhase, gs.DayNumber, CountLiving(gs.Players))
-	return n.router.SimpleChat(ctx, llm.TaskNarration, narratorPrompt, prompt)
+	public := publicStateView(gs)
+	prompt := buildPhaseChangePrompt(public, oldPhase, newPhase)
+	return n.router.SimpleChat(ctx, llm.TaskNarration, n.prompts.Render(ctx, AgentNarrator, promptVars(public)), prompt)
 }
 
 // NarrateDeath creates narration for a player's death.
 func (n *Narrator) NarrateDeath(ctx context.Context, gs GameStateView, playerName, cause string) (string, error) {
-	prompt := fmt.Sprintf("Create a brief death announcement for %s. Cause: %s. Day %d.",
+	public := publicStateView(gs)
+	prompt := buildDeathPrompt(public, playerName, cause)
+	return n.router.SimpleChat(ctx, llm.TaskNarration, n.prompts.Render(ctx, AgentNarrator, promptVars(public)), prompt)
+}
+
+func buildPhaseChangePrompt(gs GameStateView, oldPhase, newPhase string) string {
+	return fmt.Sprintf("Create a brief atmospheric narration for phase change from %s to %s. Day %d, %d alive.",
+		oldPhase, newPhase, gs.DayNumber, CountLiving(gs.Players))
+}
+
+func buildDeathPrompt(gs GameStateView, playerName, cause string) string {
+	return fmt.Sprintf("Create a brief death announcement for %s. Cause: %s. Day %d.",
 		playerName, cause, gs.DayNumber)
-	return n.router.SimpleChat(ctx, llm.TaskNarration, narratorPrompt, prompt)
+}
+
+// publicStateView drops roles so the Narrator never sees hidden identities.
+func publicStateView(gs GameStateView) GameStateView {
+	players := make([]PlayerView, len(gs.Players))
+	for i, p := range gs.Players {
+		p.Role = ""
+		players[i] = p
+	}
+	gs.Players = players
+	return gs
 }
@@ -0,0 +1,39 @@
+package subagent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildDeathPromptOmitsHiddenRoles(t *testing.T) {
+	gs := GameStateView{
+		DayNumber: 2,
+		Players: []PlayerView{
+			{ID: "p1", Name: "Alice", Role: "poisoner", IsAlive: true},
+			{ID: "p2", Name: "Bob", Role: "imp", IsAlive: true},
+			{ID: "p3", Name: "Carol", Role: "mayor", IsAlive: false},
+		},
+	}
+
+	prompt := buildDeathPrompt(publicStateView(gs), "Carol", "night")
+	for _, secret := range []string{"poisoner", "imp", "mayor", "poison"} {
+		if strings.Contains(prompt, secret) {
+			t.Fatalf("death prompt leaks %q: %s", secret, prompt)
+		}
+	}
+	if !strings.Contains(prompt, "Carol") {
+		t.Fatalf("expected prompt to name the dead player, got %s", prompt)
+	}
+}
+
+func TestPublicStateViewClearsRolesWithoutMutatingInput(t *testing.T) {
+	gs := GameStateView{Players: []PlayerView{{ID: "p1", Role: "imp", IsAlive: true}}}
+
+	public := publicStateView(gs)
+	if public.Players[0].Role != "" {
+		t.Fatalf("expected role cleared, got %q", public.Players[0].Role)
+	}
+	if gs.Players[0].Role != "imp" {
+		t.Fatal("expected original view to keep its role")
+	}
+}
@@ -15,13 +15,11 @@ import (
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
 )
 
-const playerModelerPrompt = `You are the Player Modeler for Blood on the Clocktower.
-Analyze player behavior to help the DM understand dynamics. This is DM-only information.`
-
 // PlayerModeler analyzes player behavior.
 type PlayerModeler struct {
 	mu           sync.RWMutex
 	router       *llm.Router
+	prompts      *PromptRegistry
 	observations map[string]*PlayerProfile
 }
 
@@ -36,10 +34,11 @@ type PlayerProfile struct {
 	Notes       []string
 }
 
-// NewPlayerModeler creates a new PlayerModeler agent.
-func NewPlayerModeler(router *llm.Router) *PlayerModeler {
+// NewPlayerModeler creates a new PlayerModeler agent; nil prompts uses the built-in templates.
+func NewPlayerModeler(router *llm.Router, prompts *PromptRegistry) *PlayerModeler {
 	return &PlayerModeler{
 		router:       router,
+		prompts:      prompts,
 		observations: make(map[string]*PlayerProfile),
 	}
 }
@@ -68,7 +67,7 @@ func (p *PlayerModeler) RecordAccusation(accuserID, accuserName, targetID, targe
 func (p *PlayerModeler) IdentifySuspects(ctx context.Context, gs GameStateView) (string, error) {
 	history := p.formatHistory()
 	prompt := fmt.Sprintf("%s\n\nPlayer history:\n%s\n\nIdentify the most suspicious players.",
-		playerModelerPrompt, history)
+		p.prompts.Render(ctx, AgentPlayerModeler, promptVars(gs)), history)
 	return p.router.SimpleChat(ctx, llm.TaskReasoning, prompt, "Rank suspects with reasoning.")
 }
 
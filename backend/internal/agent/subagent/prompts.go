@@ -0,0 +1,220 @@
+// Package subagent 子代理系统提示词模板注册表
+//
+// 各子代理的系统提示词按 (agent, persona, language) 登记为 text/template 模板，可由配置文件
+// 覆盖而无需改代码。查找顺序：精确匹配 → 同人设任意语言 → 同语言默认人设 → 内置默认模板。
+// 语言取调用 ctx 上的 llm.WithLanguage 覆盖 (房间语言)，没有时用注册表的默认语言。
+// 覆盖模板渲染失败时记日志并改用内置默认模板。
+// 模板可引用 PromptVars 中的变量，如 {{.PlayerCount}}、{{.Edition}}、{{.GameState}}。
+// nil 注册表等同只含内置默认模板，子代理构造时可不传。
+//
+// [IN]  AUTODM_PROMPT_TEMPLATES（cmd/server 读取的 JSON 覆盖文件）
+// [IN]  agent/llm（LanguageFromContext）
+// [OUT] narrator/rules/summarizer/moderator/player_modeler/composer（系统提示词）
+// [POS] 子代理提示词的唯一来源
+package subagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
+)
+
+// Sub-agent names used as template keys.
+const (
+	AgentNarrator      = "narrator"
+	AgentRules         = "rules"
+	AgentSummarizer    = "summarizer"
+	AgentModerator     = "moderator"
+	AgentPlayerModeler = "player_modeler"
+	AgentComposer      = "composer"
+)
+
+// PromptKey identifies a template; empty Persona/Language match any.
+type PromptKey struct {
+	Agent    string `json:"agent"`
+	Persona  string `json:"persona"`
+	Language string `json:"language"`
+}
+
+// PromptTemplate is one override entry in a templates file.
+type PromptTemplate struct {
+	PromptKey
+	Template string `json:"template"`
+}
+
+// PromptVars are the variables a template may reference.
+type PromptVars struct {
+	PlayerCount int
+	AliveCount  int
+	Edition     string
+	Phase       string
+	DayNumber   int
+	// GameState is FormatGameState of the view the sub-agent is allowed to see
+	GameState string
+}
+
+// PromptRegistry resolves sub-agent system prompts for one persona and language.
+type PromptRegistry struct {
+	mu        sync.RWMutex
+	persona   string
+	language  string
+	templates map[PromptKey]*template.Template
+}
+
+// NewPromptRegistry creates a registry holding the built-in templates.
+func NewPromptRegistry(persona, language string) *PromptRegistry {
+	r := &PromptRegistry{persona: persona, language: language, templates: make(map[PromptKey]*template.Template, len(builtinTemplates))}
+	for agent, tmpl := range builtinTemplates {
+		r.templates[PromptKey{Agent: agent}] = tmpl
+	}
+	return r
+}
+
+// Set registers text for key, rejecting templates that fail to parse or render.
+func (r *PromptRegistry) Set(key PromptKey, text string) error {
+	if key.Agent == "" {
+		return fmt.Errorf("subagent.PromptRegistry.Set: agent is required")
+	}
+	tmpl, err := parsePrompt(key.Agent, text)
+	if err != nil {
+		return fmt.Errorf("subagent.PromptRegistry.Set: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[key] = tmpl
+	return nil
+}
+
+// LoadFile registers every entry of a JSON array of PromptTemplate.
+func (r *PromptRegistry) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("subagent.PromptRegistry.LoadFile: %w", err)
+	}
+	var entries []PromptTemplate
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("subagent.PromptRegistry.LoadFile: %w", err)
+	}
+	for _, e := range entries {
+		if err := r.Set(e.PromptKey, e.Template); err != nil {
+			return fmt.Errorf("subagent.PromptRegistry.LoadFile: %w", err)
+		}
+	}
+	return nil
+}
+
+// Render returns agent's system prompt in ctx's language (llm.WithLanguage) or the
+// registry's; a nil registry renders the built-in templates.
+func (r *PromptRegistry) Render(ctx context.Context, agent string, vars PromptVars) string {
+	if r == nil {
+		return defaultRegistry.Render(ctx, agent, vars)
+	}
+	lang, ok := llm.LanguageFromContext(ctx)
+	if !ok {
+		lang = r.language
+	}
+	tmpl := r.lookup(agent, lang)
+	if tmpl == nil {
+		return ""
+	}
+	var b strings.Builder
+	err := tmpl.Execute(&b, vars)
+	if err == nil {
+		return b.String()
+	}
+	builtin, ok := builtinTemplates[agent]
+	if !ok || tmpl == builtin {
+		slog.Warn("sub-agent prompt render failed", "agent", agent, "error", err)
+		return b.String()
+	}
+	slog.Warn("sub-agent prompt override failed; using the built-in prompt", "agent", agent, "language", lang, "error", err)
+	b.Reset()
+	_ = builtin.Execute(&b, vars) // built-ins are checked at init
+	return b.String()
+}
+
+// lookup picks the most specific template for the registry's persona and lang.
+func (r *PromptRegistry) lookup(agent, lang string) *template.Template {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, key := range []PromptKey{
+		{agent, r.persona, lang},
+		{agent, r.persona, ""},
+		{agent, "", lang},
+		{agent, "", ""},
+	} {
+		if tmpl, ok := r.templates[key]; ok {
+			return tmpl
+		}
+	}
+	return nil
+}
+
+// parsePrompt parses text and renders it once with empty vars to catch unknown variables.
+func parsePrompt(agent, text string) (*template.Template, error) {
+	tmpl, err := template.New(agent).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", agent, err)
+	}
+	if err := tmpl.Execute(&strings.Builder{}, PromptVars{}); err != nil {
+		return nil, fmt.Errorf("%s: %w", agent, err)
+	}
+	return tmpl, nil
+}
+
+// promptVars builds the template variables for gs.
+func promptVars(gs GameStateView) PromptVars {
+	return PromptVars{
+		PlayerCount: len(gs.Players),
+		AliveCount:  CountLiving(gs.Players),
+		Edition:     gs.Edition,
+		Phase:       gs.Phase,
+		DayNumber:   gs.DayNumber,
+		GameState:   FormatGameState(gs),
+	}
+}
+
+var defaultPrompts = map[string]string{
+	AgentNarrator: `You are the Narrator for Blood on the Clocktower.
+Create immersive, atmospheric narration. Keep it concise but evocative.
+Current game state: {{.GameState}}`,
+	AgentRules: `You are the Rules Agent for Blood on the Clocktower.
+Provide accurate answers about game rules and mechanics.`,
+	AgentSummarizer: `You are the Summarizer for Blood on the Clocktower.
+Create clear, concise summaries of game events and status.
+
+Current state:
+{{.GameState}}`,
+	AgentModerator: `You are the Moderator Agent for Blood on the Clocktower.
+Manage game flow, phases, nominations, and voting. Be impartial and follow rules precisely.
+Current game state: {{.GameState}}`,
+	AgentPlayerModeler: `You are the Player Modeler for Blood on the Clocktower.
+Analyze player behavior to help the DM understand dynamics. This is DM-only information.`,
+	AgentComposer: composerSystemPrompt,
+}
+
+var (
+	// builtinTemplates are defaultPrompts parsed once at startup; templates are
+	// read-only after parsing, so registries share them.
+	builtinTemplates map[string]*template.Template
+	defaultRegistry  *PromptRegistry
+)
+
+func init() {
+	builtinTemplates = make(map[string]*template.Template, len(defaultPrompts))
+	for agent, text := range defaultPrompts {
+		tmpl, err := parsePrompt(agent, text)
+		if err != nil {
+			panic(fmt.Sprintf("subagent: built-in prompt %v", err))
+		}
+		builtinTemplates[agent] = tmpl
+	}
+	defaultRegistry = NewPromptRegistry("", "")
+}
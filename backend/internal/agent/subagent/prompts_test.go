@@ -0,0 +1,103 @@
+package subagent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
+)
+
+// systemCapture returns a router whose every request records its system message into got.
+func systemCapture(t *testing.T, got *string) *llm.Router {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llm.ChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if len(req.Messages) > 0 && req.Messages[0].Role == "system" {
+			*got = req.Messages[0].Content
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": "ok"}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return llm.NewRouterFromConfig(llm.RoutingConfig{Default: llm.Config{BaseURL: srv.URL, Model: "m"}})
+}
+
+func TestNarratorTemplateOverrideChangesSystemMessage(t *testing.T) {
+	var system string
+	router := systemCapture(t, &system)
+	gs := GameStateView{Edition: "tb", DayNumber: 2, Players: []PlayerView{
+		{ID: "p1", Name: "Alice", Role: "imp", IsAlive: true},
+		{ID: "p2", Name: "Bob", Role: "chef", IsAlive: true},
+	}}
+
+	if _, err := NewNarrator(router, nil).NarrateDeath(context.Background(), gs, "Bob", "night"); err != nil {
+		t.Fatalf("narrate: %v", err)
+	}
+	if !strings.HasPrefix(system, "You are the Narrator") {
+		t.Fatalf("expected the built-in narrator prompt, got %q", system)
+	}
+
+	prompts := NewPromptRegistry("gothic", "en")
+	if err := prompts.Set(PromptKey{Agent: AgentNarrator, Persona: "gothic"},
+		"Gothic narrator for {{.PlayerCount}} souls in {{.Edition}}."); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, err := NewNarrator(router, prompts).NarrateDeath(context.Background(), gs, "Bob", "night"); err != nil {
+		t.Fatalf("narrate: %v", err)
+	}
+	if system != "Gothic narrator for 2 souls in tb." {
+		t.Fatalf("expected the overridden narrator prompt, got %q", system)
+	}
+}
+
+func TestPromptRegistryFallsBackToDefaultPersona(t *testing.T) {
+	prompts := NewPromptRegistry("gothic", "zh")
+	if err := prompts.Set(PromptKey{Agent: AgentRules, Language: "zh"}, "规则裁判"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got := prompts.Render(context.Background(), AgentRules, PromptVars{}); got != "规则裁判" {
+		t.Fatalf("expected the language-only override, got %q", got)
+	}
+	if got := prompts.Render(context.Background(), AgentSummarizer, PromptVars{GameState: "Day 1"}); !strings.HasSuffix(got, "Day 1") {
+		t.Fatalf("expected the built-in summarizer prompt with state, got %q", got)
+	}
+}
+
+func TestPromptRegistryRejectsUnknownVariable(t *testing.T) {
+	prompts := NewPromptRegistry("", "")
+	if err := prompts.Set(PromptKey{Agent: AgentNarrator}, "{{.Script}}"); err == nil {
+		t.Fatal("expected an unknown variable to be rejected")
+	}
+}
+
+func TestPromptRegistryUsesLanguageFromContext(t *testing.T) {
+	prompts := NewPromptRegistry("", "en")
+	if err := prompts.Set(PromptKey{Agent: AgentRules, Language: "zh"}, "规则裁判"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got := prompts.Render(context.Background(), AgentRules, PromptVars{}); strings.Contains(got, "规则裁判") {
+		t.Fatalf("expected the registry language (en) without a room override, got %q", got)
+	}
+	ctx := llm.WithLanguage(context.Background(), "zh")
+	if got := prompts.Render(ctx, AgentRules, PromptVars{}); got != "规则裁判" {
+		t.Fatalf("expected the room's zh template, got %q", got)
+	}
+}
+
+func TestPromptRegistryFallsBackWhenOverrideFailsToRender(t *testing.T) {
+	prompts := NewPromptRegistry("", "")
+	// Passes Set (PlayerCount is 0 there) but fails once the branch runs.
+	if err := prompts.Set(PromptKey{Agent: AgentSummarizer}, "{{if .PlayerCount}}{{.PlayerCount.Missing}}{{end}}"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	got := prompts.Render(context.Background(), AgentSummarizer, PromptVars{PlayerCount: 5, GameState: "Day 1"})
+	if !strings.HasPrefix(got, "You are the Summarizer") || !strings.HasSuffix(got, "Day 1") {
+		t.Fatalf("expected the built-in summarizer prompt, got %q", got)
+	}
+}
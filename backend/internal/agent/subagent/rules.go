@@ -14,12 +14,10 @@ import (
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
 )
 
-const rulesPrompt = `You are the Rules Agent for Blood on the Clocktower.
-Provide accurate answers about game rules and mechanics.`
-
 // Rules answers questions about game rules.
 type Rules struct {
 	router   *llm.Router
+	prompts  *PromptRegistry
 	roleData map[string]RoleInfo
 }
 
@@ -32,10 +30,11 @@ type RoleInfo struct {
 	OtherNights int
 }
 
-// NewRules creates a new Rules agent.
-func NewRules(router *llm.Router) *Rules {
+// NewRules creates a new Rules agent; nil prompts uses the built-in templates.
+func NewRules(router *llm.Router, prompts *PromptRegistry) *Rules {
 	return &Rules{
 		router:   router,
+		prompts:  prompts,
 		roleData: defaultRoleData(),
 	}
 }
@@ -47,7 +46,7 @@ func (r *Rules) Process(ctx context.Context, gs GameStateView, query string) (st
 	if roleContext != "" {
 		fullQuery = query + "\n\nRelevant roles:\n" + roleContext
 	}
-	return r.router.SimpleChat(ctx, llm.TaskRules, rulesPrompt, fullQuery)
+	return r.router.SimpleChat(ctx, llm.TaskRules, r.prompts.Render(ctx, AgentRules, promptVars(gs)), fullQuery)
 }
 
 // GetRoleInfo returns information about a specific role.
@@ -9,21 +9,20 @@ package subagent
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
 )
 
-const summarizerPrompt = `You are the Summarizer for Blood on the Clocktower.
-Create clear, concise summaries of game events and status.`
-
 // Summarizer creates summaries of game state and events.
 type Summarizer struct {
-	router *llm.Router
+	router  *llm.Router
+	prompts *PromptRegistry
 }
 
-// NewSummarizer creates a new Summarizer agent.
-func NewSummarizer(router *llm.Router) *Summarizer {
-	return &Summarizer{router: router}
+// NewSummarizer creates a new Summarizer agent; nil prompts uses the built-in templates.
+func NewSummarizer(router *llm.Router, prompts *PromptRegistry) *Summarizer {
+	return &Summarizer{router: router, prompts: prompts}
 }
 
 // SummarizeGameState creates a summary of current game state.
@@ -32,8 +31,24 @@ func (s *Summarizer) SummarizeGameState(ctx context.Context, gs GameStateView, f
 	if forDM {
 		prompt = "Create a comprehensive game state summary for the Storyteller."
 	}
-	systemPrompt := fmt.Sprintf("%s\n\nCurrent state:\n%s", summarizerPrompt, FormatGameState(gs))
-	return s.router.SimpleChat(ctx, llm.TaskSummarize, systemPrompt, prompt)
+	return s.router.SimpleChat(ctx, llm.TaskSummarize, s.prompts.Render(ctx, AgentSummarizer, promptVars(gs)), prompt)
+}
+
+// RecapGame writes the end-of-game recap from the revealed grimoire in gs and the game's turning points.
+func (s *Summarizer) RecapGame(ctx context.Context, gs GameStateView, turningPoints []string, winner, reason string) (string, error) {
+	return s.router.SimpleChat(ctx, llm.TaskSummarize, s.prompts.Render(ctx, AgentSummarizer, promptVars(gs)),
+		buildRecapPrompt(turningPoints, winner, reason))
+}
+
+func buildRecapPrompt(turningPoints []string, winner, reason string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The game is over: %s won (%s).\n", winner, reason)
+	if len(turningPoints) > 0 {
+		b.WriteString("Turning points:\n- " + strings.Join(turningPoints, "\n- ") + "\n")
+	}
+	b.WriteString("Write the public end-of-game recap: reveal who was what, walk through the key turning points, " +
+		"and explain the play that won the game.")
+	return b.String()
 }
 
 // QuickStatus returns a one-line status.
@@ -21,6 +21,9 @@ type GameStateView struct {
 	Nominations []NominationView
 	Edition     string
 	Script      []string
+
+	// Lessons are past reflection lessons to keep in mind while planning
+	Lessons []string
 }
 
 // PlayerView is a read-only view of a player.
@@ -58,6 +61,12 @@ func FormatGameState(gs GameStateView) string {
 		}
 		result += fmt.Sprintf("  - %s (%s): %s\n", p.Name, role, status)
 	}
+	if len(gs.Lessons) > 0 {
+		result += "Lessons from earlier runs:\n"
+		for _, l := range gs.Lessons {
+			result += fmt.Sprintf("  - %s\n", l)
+		}
+	}
 
 	return result
 }
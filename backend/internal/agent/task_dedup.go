@@ -0,0 +1,73 @@
+// Package agent 异步事件任务的入队去重
+//
+// OnEvent 可能对同一事件被重复调用 (如同步兜底后又被重新投递)。publishAsyncTask 入队前以
+// "房间:事件 ID" 认领去重键，认领失败说明该事件已入队，直接跳过。去重键短期有效
+// (asyncTaskDedupTTL)。Config.TaskDedup 可接入存储 (store.ClaimTaskKey，多实例共享)；
+// 未配置时使用进程内集合。认领出错时放行入队，宁可重复也不丢事件。
+//
+// [IN]  Config.TaskDedup（cmd/server 接入 store.Store）
+// [OUT] autodm.go（publishAsyncTask）
+// [POS] AutoDM 任务队列的幂等入队
+package agent
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// asyncTaskDedupTTL is how long an enqueued event's key blocks re-enqueueing.
+const asyncTaskDedupTTL = 10 * time.Minute
+
+// TaskDeduper claims a key for ttl; false means it was already claimed.
+type TaskDeduper interface {
+	ClaimTaskKey(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// asyncTaskKey identifies the source event of an async task.
+func asyncTaskKey(ev types.Event) string {
+	id := ev.EventID
+	if id == "" {
+		id = strconv.FormatInt(ev.Seq, 10)
+	}
+	return ev.RoomID + ":" + id
+}
+
+// memoryTaskDeduper is the in-process TaskDeduper used when none is configured.
+type memoryTaskDeduper struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+}
+
+func newMemoryTaskDeduper() *memoryTaskDeduper {
+	return &memoryTaskDeduper{expires: make(map[string]time.Time)}
+}
+
+func (d *memoryTaskDeduper) ClaimTaskKey(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	for k, exp := range d.expires {
+		if now.After(exp) {
+			delete(d.expires, k)
+		}
+	}
+	if _, ok := d.expires[key]; ok {
+		return false, nil
+	}
+	d.expires[key] = now.Add(ttl)
+	return true, nil
+}
+
+// claimAsyncTask reports whether ev may be enqueued; dedup errors let it through.
+func (a *AutoDM) claimAsyncTask(ctx context.Context, ev types.Event) bool {
+	ok, err := a.taskDedup.ClaimTaskKey(ctx, asyncTaskKey(ev), asyncTaskDedupTTL)
+	if err != nil {
+		a.logger.Warn("AutoDM task dedup failed, enqueueing anyway", "error", err, "event_type", ev.EventType)
+		return true
+	}
+	return ok
+}
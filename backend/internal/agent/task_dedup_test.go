@@ -0,0 +1,27 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestRedundantOnEventPublishesOneTask(t *testing.T) {
+	queue := &recordingQueue{}
+	a := NewAutoDM(Config{Enabled: true, TaskQueue: queue})
+	ev := types.Event{RoomID: "room-1", Seq: 12, EventID: "ev-12", EventType: "nomination.created", Payload: []byte(`{}`)}
+
+	a.OnEvent(context.Background(), ev, nil)
+	a.OnEvent(context.Background(), ev, nil)
+	if len(queue.tasks) != 1 {
+		t.Fatalf("expected one task for a repeated event, got %d", len(queue.tasks))
+	}
+
+	other := ev
+	other.Seq, other.EventID = 13, "ev-13"
+	a.OnEvent(context.Background(), other, nil)
+	if len(queue.tasks) != 2 {
+		t.Fatalf("expected a different event to enqueue, got %d tasks", len(queue.tasks))
+	}
+}
@@ -0,0 +1,89 @@
+// Package agent 模型工具调用的校验护栏
+//
+// 模型返回的 tool_calls 在执行前先按 MCP ParamSchema 校验 (mcp.Registry.Validate：
+// JSON 格式、必填、枚举、长度与数值范围)。任一调用不合法时，把本轮 assistant 消息与
+// 每个调用的校验结果 (tool 消息) 追加进对话并要求模型重发，最多 maxToolCallRetries 次；
+// 仍不合法则返回 ErrInvalidToolCalls，一个调用也不执行，绝不把非法参数变成游戏命令。
+//
+// [IN]  internal/agent/llm（Router.Chat、ToolCall）
+// [IN]  internal/mcp（Registry.Validate、ToolDefinition）
+// [OUT] 模型工具调用路径（guardedToolCalls：返回可交给 invokeTool 执行的调用）
+// [POS] LLM 输出与工具执行之间的校验层
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/mcp"
+)
+
+// maxToolCallRetries is how many times the model is re-asked after invalid tool calls.
+const maxToolCallRetries = 2
+
+// ErrInvalidToolCalls means the model kept producing tool calls that fail their schemas.
+var ErrInvalidToolCalls = errors.New("model tool calls failed validation")
+
+// chatModel is the routed chat used for tool calling (llm.Router).
+type chatModel interface {
+	Chat(ctx context.Context, taskType llm.TaskType, messages []llm.Message, tools []llm.Tool) (*llm.ChatResponse, error)
+}
+
+// toolCallValidator checks a call before it runs (mcp.Registry).
+type toolCallValidator interface {
+	Validate(call mcp.ToolCall) error
+}
+
+// guardedToolCalls asks the model for tool calls and re-asks with the validation errors
+// until every call passes its schema; it returns the calls ready to invoke.
+func guardedToolCalls(ctx context.Context, model chatModel, validator toolCallValidator, task llm.TaskType, messages []llm.Message, tools []llm.Tool) ([]mcp.ToolCall, error) {
+	convo := append([]llm.Message(nil), messages...)
+	for attempt := 0; ; attempt++ {
+		resp, err := model.Chat(ctx, task, convo, tools)
+		if err != nil {
+			return nil, fmt.Errorf("agent.guardedToolCalls: %w", err)
+		}
+		if len(resp.Choices) == 0 {
+			return nil, nil
+		}
+		msg := resp.Choices[0].Message
+		calls, feedback := validateToolCalls(validator, msg.ToolCalls)
+		if feedback == nil {
+			return calls, nil
+		}
+		if attempt >= maxToolCallRetries {
+			return nil, fmt.Errorf("agent.guardedToolCalls: %w after %d attempts", ErrInvalidToolCalls, attempt+1)
+		}
+		convo = append(append(convo, msg), feedback...)
+	}
+}
+
+// validateToolCalls converts the model's calls; feedback is nil when all of them are valid.
+func validateToolCalls(validator toolCallValidator, raw []llm.ToolCall) ([]mcp.ToolCall, []llm.Message) {
+	calls := make([]mcp.ToolCall, 0, len(raw))
+	var feedback []llm.Message
+	invalid := false
+	for _, tc := range raw {
+		call := mcp.ToolCall{
+			ID:         tc.ID,
+			ToolName:   tc.Function.Name,
+			Parameters: json.RawMessage(tc.Function.Arguments),
+			Timestamp:  time.Now().UnixMilli(),
+		}
+		content := "valid; resend it unchanged"
+		if err := validator.Validate(call); err != nil {
+			invalid = true
+			content = fmt.Sprintf("invalid arguments: %v. Call %s again with arguments that match its schema.", err, call.ToolName)
+		}
+		calls = append(calls, call)
+		feedback = append(feedback, llm.Message{Role: "tool", ToolCallID: tc.ID, Content: content})
+	}
+	if !invalid {
+		return calls, nil
+	}
+	return nil, feedback
+}
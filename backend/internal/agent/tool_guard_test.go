@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/mcp"
+)
+
+// scriptedModel replies with one queued advance_phase call per Chat and records each conversation.
+type scriptedModel struct {
+	phases []string
+	convos [][]llm.Message
+}
+
+func (m *scriptedModel) Chat(_ context.Context, _ llm.TaskType, messages []llm.Message, _ []llm.Tool) (*llm.ChatResponse, error) {
+	m.convos = append(m.convos, messages)
+	args, _ := json.Marshal(map[string]string{"room_id": "room-1", "phase": m.phases[0]})
+	m.phases = m.phases[1:]
+	call := llm.ToolCall{ID: fmt.Sprintf("call-%d", len(m.convos)), Type: "function",
+		Function: llm.FunctionCall{Name: "advance_phase", Arguments: string(args)}}
+	raw, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{{"message": llm.Message{Role: "assistant", ToolCalls: []llm.ToolCall{call}}}},
+	})
+	var resp llm.ChatResponse
+	err := json.Unmarshal(raw, &resp)
+	return &resp, err
+}
+
+// guardAndInvoke runs the guarded calls through the Auto-DM's MCP registry.
+func guardAndInvoke(a *AutoDM, model chatModel, messages []llm.Message) ([]*mcp.ToolResult, error) {
+	calls, err := guardedToolCalls(context.Background(), model, a.mcpRegistry, llm.TaskReasoning, messages, nil)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*mcp.ToolResult, 0, len(calls))
+	for _, call := range calls {
+		results = append(results, a.invokeTool(context.Background(), a.mcpRegistry, call))
+	}
+	return results, nil
+}
+
+func TestOutOfEnumPhaseIsReaskedNotDispatched(t *testing.T) {
+	dispatcher := &recordingDispatcher{}
+	a := NewAutoDM(Config{Enabled: true})
+	a.SetDispatcher(dispatcher, nil)
+	model := &scriptedModel{phases: []string{"midnight", "day"}}
+
+	results, err := guardAndInvoke(a, model, []llm.Message{{Role: "user", Content: "advance"}})
+	if err != nil {
+		t.Fatalf("guardAndInvoke: %v", err)
+	}
+	if len(model.convos) != 2 {
+		t.Fatalf("expected one re-ask after the invalid phase, got %d chats", len(model.convos))
+	}
+	retry := model.convos[1]
+	if last := retry[len(retry)-1]; last.Role != "tool" || last.ToolCallID == "" {
+		t.Fatalf("expected the re-ask to carry the validation error, got %+v", last)
+	}
+	if len(results) != 1 || !results[0].Success {
+		t.Fatalf("expected only the corrected call to run, got %+v", results)
+	}
+	if len(dispatcher.cmds) != 1 || dispatcher.cmds[0].Type != "advance_phase" || string(dispatcher.cmds[0].Payload) != `{"phase":"day","reason":""}` {
+		t.Fatalf("expected a single advance_phase to day, got %+v", dispatcher.cmds)
+	}
+}
+
+func TestPersistentlyInvalidToolCallsRunNothing(t *testing.T) {
+	dispatcher := &recordingDispatcher{}
+	a := NewAutoDM(Config{Enabled: true})
+	a.SetDispatcher(dispatcher, nil)
+	model := &scriptedModel{phases: []string{"midnight", "dusk", "noon"}}
+
+	_, err := guardAndInvoke(a, model, []llm.Message{{Role: "user", Content: "advance"}})
+	if !errors.Is(err, ErrInvalidToolCalls) {
+		t.Fatalf("expected ErrInvalidToolCalls, got %v", err)
+	}
+	if len(model.convos) != maxToolCallRetries+1 || len(dispatcher.cmds) != 0 {
+		t.Fatalf("expected %d attempts and no commands, got %d chats and %v", maxToolCallRetries+1, len(model.convos), dispatcher.cmds)
+	}
+}
@@ -0,0 +1,205 @@
+// Package agent 跨语言房间的公告翻译钩子
+//
+// 房间开启 translate_announcements 后，Auto-DM 每发出一条公开公告，按玩家偏好语言
+// (engine.Player.Language) 分组，每种与叙事语言不同的语言只调用一次 translator 角色
+// (llm.TaskTranslate)，再把译文私聊给该语言的每位玩家。翻译在公开消息发出后另起 goroutine
+// 进行 (带超时、recover，Stop 时取消)，公告不等待翻译延迟；失败只记录日志，不影响公开消息。
+// 房间开关与偏好在 OnEvent 时从引擎状态快照，仅保存开启翻译的房间；对局结束 (发布复盘时)
+// 或 Auto-DM 停止时清除。
+//
+// [IN]  internal/engine（Player.Language、Config.TranslateAnnouncements）
+// [IN]  internal/agent/llm（translator 路由）
+// [OUT] autodm.go（sendMessage 后发送本地化私聊）
+// [POS] Auto-DM 输出路径上的可插拔本地化层
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// Translator renders an announcement in a target language.
+type Translator interface {
+	Translate(ctx context.Context, text, language string) (string, error)
+}
+
+const translatorPrompt = "You translate announcements from the storyteller of a Blood on the Clocktower game. " +
+	"Translate the user's message into %s. Keep emoji, player names and seat numbers unchanged. " +
+	"Reply with the translation only."
+
+// routerTranslator asks the LLM router's translator role for translations.
+type routerTranslator struct {
+	router *llm.Router
+}
+
+// newRouterTranslator builds a translator whose prompts carry no narration-language instruction.
+func newRouterTranslator(cfg LLMRoutingConfig) Translator {
+	cfg.Language = ""
+	return routerTranslator{router: llm.NewRouterFromConfig(cfg)}
+}
+
+func (t routerTranslator) Translate(ctx context.Context, text, language string) (string, error) {
+	ctx = llm.WithLanguage(ctx, "") // drop the room's narration language carried by ctx
+	out, err := t.router.SimpleChat(ctx, llm.TaskTranslate, fmt.Sprintf(translatorPrompt, llm.LanguageName(language)), text)
+	if err != nil {
+		return "", fmt.Errorf("agent.routerTranslator.Translate: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// translationPrefs is a room's translation switch and per-player language preferences.
+type translationPrefs struct {
+	enabled   bool
+	languages map[string]string // user id -> language
+}
+
+// SetTranslator replaces the translation hook (nil disables translation).
+func (a *AutoDM) SetTranslator(t Translator) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.translator = t
+}
+
+// rememberTranslationPrefs snapshots the room's translation settings from engine state;
+// ended games are forgotten so events after game.ended do not bring them back.
+func (a *AutoDM) rememberTranslationPrefs(raw interface{}) {
+	state, ok := raw.(engine.State)
+	if !ok || state.RoomID == "" {
+		return
+	}
+	if !state.Config.TranslateAnnouncements || state.Phase == engine.PhaseEnded {
+		a.forgetTranslationPrefs(state.RoomID)
+		return
+	}
+	prefs := translationPrefs{enabled: true, languages: make(map[string]string)}
+	for uid, p := range state.Players {
+		if p.Language != "" {
+			prefs.languages[uid] = p.Language
+		}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.translationPrefs == nil {
+		a.translationPrefs = make(map[string]translationPrefs)
+	}
+	a.translationPrefs[state.RoomID] = prefs
+}
+
+// forgetTranslationPrefs drops the room's translation settings.
+func (a *AutoDM) forgetTranslationPrefs(roomID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.translationPrefs, roomID)
+}
+
+// stopTranslating cancels running translations and forgets every room's settings.
+func (a *AutoDM) stopTranslating() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stopTranslations()
+	a.translationPrefs = make(map[string]translationPrefs)
+}
+
+// resumeTranslations gives translations a fresh context after a Stop.
+func (a *AutoDM) resumeTranslations() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.translateCtx.Err() != nil {
+		a.translateCtx, a.stopTranslations = context.WithCancel(context.Background())
+	}
+}
+
+// recipientsByLanguage groups players whose preference differs from the narration language.
+func (a *AutoDM) recipientsByLanguage(roomID string) map[string][]string {
+	a.mu.RLock()
+	prefs := a.translationPrefs[roomID]
+	a.mu.RUnlock()
+	if !prefs.enabled {
+		return nil
+	}
+	narration := a.languageFor(roomID)
+	if narration == "" {
+		narration = LanguageChinese
+	}
+	groups := make(map[string][]string)
+	for uid, lang := range prefs.languages {
+		if !strings.EqualFold(lang, narration) {
+			groups[lang] = append(groups[lang], uid)
+		}
+	}
+	return groups
+}
+
+// translateAsync whispers localized copies of message without holding up the announcement.
+func (a *AutoDM) translateAsync(ctx context.Context, roomID, message string) {
+	a.mu.RLock()
+	translator, lifecycle := a.translator, a.translateCtx
+	a.mu.RUnlock()
+	groups := a.recipientsByLanguage(roomID)
+	if translator == nil || len(groups) == 0 {
+		return
+	}
+	a.inflight.Add(1)
+	go func() {
+		defer a.inflight.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				a.logger.Error("AutoDM announcement translation panicked", "room_id", roomID, "panic", r)
+			}
+		}()
+		// Outlive the announcing run (keeping ctx values such as the room's model override) but not Stop
+		tctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.eventTimeout)
+		defer cancel()
+		defer context.AfterFunc(lifecycle, cancel)()
+		a.whisperTranslations(tctx, roomID, message, translator, groups)
+	}()
+}
+
+// whisperTranslations sends each language group a localized copy of message.
+func (a *AutoDM) whisperTranslations(ctx context.Context, roomID, message string, translator Translator, groups map[string][]string) {
+	langs := make([]string, 0, len(groups))
+	for lang := range groups {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		translated, err := translator.Translate(ctx, message, lang)
+		if err != nil || translated == "" {
+			a.logger.Warn("AutoDM announcement translation failed", "room_id", roomID, "language", lang, "error", err)
+			continue
+		}
+		recipients := groups[lang]
+		sort.Strings(recipients)
+		for _, uid := range recipients {
+			a.whisper(roomID, uid, translated)
+		}
+	}
+}
+
+// whisper dispatches a private Auto-DM message to one player.
+func (a *AutoDM) whisper(roomID, toUserID, message string) {
+	payload, _ := json.Marshal(map[string]string{
+		"to_user_id": toUserID,
+		"message":    message,
+		"from":       "auto-dm",
+	})
+	cmdID := generateCommandID()
+	cmd := types.CommandEnvelope{
+		CommandID:      cmdID,
+		IdempotencyKey: cmdID,
+		RoomID:         roomID,
+		Type:           "whisper",
+		ActorUserID:    "autodm",
+		Payload:        payload,
+	}
+	if err := a.dispatchCommand(cmd); err != nil {
+		a.logger.Error("Failed to send AutoDM translation whisper", "error", err, "to_user_id", toUserID)
+	}
+}
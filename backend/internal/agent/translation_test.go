@@ -0,0 +1,148 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+type recordingDispatcher struct {
+	mu   sync.Mutex
+	cmds []types.CommandEnvelope
+}
+
+func (d *recordingDispatcher) DispatchAsync(cmd types.CommandEnvelope) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cmds = append(d.cmds, cmd)
+	return nil
+}
+
+type prefixTranslator struct{}
+
+func (prefixTranslator) Translate(_ context.Context, text, language string) (string, error) {
+	return "[" + language + "] " + text, nil
+}
+
+func translationRoom(enabled bool) engine.State {
+	state := engine.NewState("room-1")
+	state.Config.TranslateAnnouncements = enabled
+	state.Players["p1"] = engine.Player{UserID: "p1", Language: "en"}
+	state.Players["p2"] = engine.Player{UserID: "p2", Language: "ja"}
+	state.Players["p3"] = engine.Player{UserID: "p3", Language: "zh"}
+	state.Players["p4"] = engine.Player{UserID: "p4"}
+	return state
+}
+
+func TestAnnouncementGetsOneLocalizedWhisperPerLanguagePref(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true, LLM: LLMRoutingConfig{Language: LanguageChinese}})
+	dispatcher := &recordingDispatcher{}
+	a.SetDispatcher(dispatcher, nil)
+	a.SetTranslator(prefixTranslator{})
+	a.rememberTranslationPrefs(translationRoom(true))
+
+	a.sendMessage(context.Background(), "room-1", "天亮了")
+	if err := a.waitInflight(context.Background()); err != nil {
+		t.Fatalf("wait translations: %v", err)
+	}
+
+	var public int
+	whispers := map[string]string{}
+	for _, cmd := range dispatcher.cmds {
+		var p map[string]string
+		_ = json.Unmarshal(cmd.Payload, &p)
+		switch cmd.Type {
+		case "public_chat":
+			public++
+		case "whisper":
+			whispers[p["to_user_id"]] = p["message"]
+		}
+	}
+	if public != 1 {
+		t.Fatalf("expected one public message, got %d", public)
+	}
+	if len(whispers) != 2 || whispers["p1"] != "[en] 天亮了" || whispers["p2"] != "[ja] 天亮了" {
+		t.Fatalf("expected en and ja whispers only, got %v", whispers)
+	}
+}
+
+func TestAnnouncementNotTranslatedWhenRoomFlagOff(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true})
+	dispatcher := &recordingDispatcher{}
+	a.SetDispatcher(dispatcher, nil)
+	a.SetTranslator(prefixTranslator{})
+	a.rememberTranslationPrefs(translationRoom(false))
+
+	a.sendMessage(context.Background(), "room-1", "天亮了")
+	if err := a.waitInflight(context.Background()); err != nil {
+		t.Fatalf("wait translations: %v", err)
+	}
+
+	for _, cmd := range dispatcher.cmds {
+		if cmd.Type == "whisper" {
+			t.Fatalf("unexpected translation whisper with the room flag off: %s", cmd.Payload)
+		}
+	}
+}
+
+// blockingTranslator holds every translation until ctx ends.
+type blockingTranslator struct {
+	started chan struct{}
+}
+
+func (b blockingTranslator) Translate(ctx context.Context, _, _ string) (string, error) {
+	b.started <- struct{}{}
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestAnnouncementDoesNotWaitForTranslation(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true, LLM: LLMRoutingConfig{Language: LanguageChinese}})
+	dispatcher := &recordingDispatcher{}
+	a.SetDispatcher(dispatcher, nil)
+	translator := blockingTranslator{started: make(chan struct{}, 2)}
+	a.SetTranslator(translator)
+	a.rememberTranslationPrefs(translationRoom(true))
+
+	a.sendMessage(context.Background(), "room-1", "天亮了")
+	dispatcher.mu.Lock()
+	sent := len(dispatcher.cmds)
+	dispatcher.mu.Unlock()
+	if sent != 1 {
+		t.Fatalf("expected the public message before any translation finished, got %d commands", sent)
+	}
+
+	<-translator.started
+	a.Stop()
+	if err := a.waitInflight(context.Background()); err != nil {
+		t.Fatalf("expected Stop to cancel running translations: %v", err)
+	}
+	if len(a.recipientsByLanguage("room-1")) != 0 {
+		t.Fatal("expected Stop to forget the room's translation prefs")
+	}
+}
+
+func TestTranslationPrefsForgottenAtGameEndOrWhenOff(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true})
+	a.SetDispatcher(&recordingDispatcher{}, nil)
+	a.rememberTranslationPrefs(translationRoom(true))
+	a.publishGameRecap(context.Background(), types.Event{RoomID: "room-1", EventType: "game.ended", Payload: []byte(`{"winner":"good"}`)})
+	if _, ok := a.translationPrefs["room-1"]; ok {
+		t.Fatal("expected the room's translation prefs to be dropped at game end")
+	}
+	ended := translationRoom(true)
+	ended.Phase = engine.PhaseEnded
+	a.rememberTranslationPrefs(ended)
+	if _, ok := a.translationPrefs["room-1"]; ok {
+		t.Fatal("expected events after game end not to remember the prefs again")
+	}
+
+	a.rememberTranslationPrefs(translationRoom(false))
+	if _, ok := a.translationPrefs["room-1"]; ok {
+		t.Fatal("expected rooms without translation not to be remembered")
+	}
+}
@@ -224,6 +224,9 @@ type ToolCallAudit struct {
 	Error      string          `json:"error,omitempty"`
 	DurationMs int64           `json:"duration_ms"`
 	CreatedAt  time.Time       `json:"created_at"`
+
+	// RoomID scopes the call for per-room audit queries
+	RoomID string `json:"room_id"`
 }
 
 // SubAgent defines the interface for all sub-agents in the AutoDM system
@@ -0,0 +1,68 @@
+// vote_tally_notice.go — 提名结算后的计票公告
+//
+// nomination.resolved 后 AutoDM 用固定模板公布可读的计票结果
+// ("存活 7 人，需要 4 票，5 票赞成处决")，不经过 LLM。只使用事件载荷中的
+// 票数合计、阈值与存活人数，不涉及谁投了什么票。被圣女取消的提名没有票数，不公告。
+//
+// [IN]  internal/types（Event）
+// [OUT] autodm.go（ProcessQueuedEvent 在编排前拦截）
+// [POS] AutoDM 的投票结果播报
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// voteTallyTemplates holds per-language tally lines and outcome sentences.
+var voteTallyTemplates = map[string]struct {
+	alive    string
+	tally    string
+	outcomes map[string]string
+}{
+	LanguageChinese: {
+		alive: "存活 %s 人，",
+		tally: "🗳️ 计票结果：%s需要 %s 票，%s 票赞成处决，%s 票反对。",
+		outcomes: map[string]string{
+			"on_the_block":     "被提名者被送上处决台。",
+			"not_on_the_block": "票数不足，被提名者未被送上处决台。",
+			"tied":             "与当前最高票数持平，处决台被清空。",
+		},
+	},
+	LanguageEnglish: {
+		alive: "%s alive, ",
+		tally: "🗳️ Vote tally: %s%s needed, %s voted to execute, %s against.",
+		outcomes: map[string]string{
+			"on_the_block":     " The nominee is now on the block.",
+			"not_on_the_block": " Not enough votes; the nominee is not on the block.",
+			"tied":             " That ties the highest count today, so the block is cleared.",
+		},
+	},
+}
+
+// voteTallyNotice returns the public tally for a resolved nomination.
+func voteTallyNotice(lang string, ev types.Event) (string, bool) {
+	if ev.EventType != "nomination.resolved" {
+		return "", false
+	}
+	var p map[string]string
+	_ = json.Unmarshal(ev.Payload, &p)
+	if p["votes_for"] == "" || p["threshold"] == "" {
+		return "", false
+	}
+	tmpl, ok := voteTallyTemplates[lang]
+	if !ok {
+		tmpl = voteTallyTemplates[LanguageChinese]
+	}
+	alive := ""
+	if p["alive_count"] != "" {
+		alive = fmt.Sprintf(tmpl.alive, p["alive_count"])
+	}
+	against := p["votes_against"]
+	if against == "" {
+		against = "0"
+	}
+	return fmt.Sprintf(tmpl.tally, alive, p["threshold"], p["votes_for"], against) + tmpl.outcomes[p["result"]], true
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func resolvedEvent(payload map[string]string) types.Event {
+	raw, _ := json.Marshal(payload)
+	return types.Event{RoomID: "room-1", EventType: "nomination.resolved", ActorUserID: "p1", Payload: raw}
+}
+
+func TestVoteTallyAnnouncesThresholdAndCounts(t *testing.T) {
+	a := NewAutoDM(Config{Enabled: true, LLM: LLMRoutingConfig{Language: LanguageEnglish}})
+	dispatcher := &recordingDispatcher{}
+	a.SetDispatcher(dispatcher, nil)
+	a.SetTranslator(nil)
+
+	ev := resolvedEvent(map[string]string{
+		"result": "on_the_block", "votes_for": "5", "votes_against": "2", "threshold": "4", "alive_count": "7",
+	})
+	if err := a.ProcessQueuedEvent(context.Background(), ev); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	msgs := publicMessages(dispatcher)
+	if len(msgs) != 1 {
+		t.Fatalf("expected one tally announcement, got %q", msgs)
+	}
+	for _, want := range []string{"7 alive", "4 needed", "5 voted to execute", "2 against", "on the block"} {
+		if !strings.Contains(msgs[0], want) {
+			t.Fatalf("tally %q missing %q", msgs[0], want)
+		}
+	}
+	if strings.Contains(msgs[0], "p1") {
+		t.Fatalf("tally names a player: %q", msgs[0])
+	}
+}
+
+func TestVoteTallySkipsCancelledNominations(t *testing.T) {
+	ev := resolvedEvent(map[string]string{"result": "cancelled", "reason": "virgin_triggered"})
+	if _, ok := voteTallyNotice(LanguageChinese, ev); ok {
+		t.Fatal("expected no tally for a cancelled nomination")
+	}
+	zh, ok := voteTallyNotice(LanguageChinese, resolvedEvent(map[string]string{"result": "tied", "votes_for": "3", "threshold": "3"}))
+	if !ok || !strings.Contains(zh, "需要 3 票") || !strings.Contains(zh, "3 票赞成") {
+		t.Fatalf("unexpected Chinese tally %q", zh)
+	}
+}
@@ -0,0 +1,113 @@
+// Package agent 私聊 Auto-DM 的问题分类
+//
+// 发给 Auto-DM 的私聊不都是规则问题，只有规则类才值得做一次 RAG 检索。tagWhisperKind 给
+// question 事件打上 whisper_kind (rules/social)：先用关键词启发式 (规则词、角色名、中文常用问法)，
+// 命中即为 rules，既无关键词也不是问句即为 social；只有"像问题但没有关键词"的模糊情况才询问
+// 可选的 WhisperClassifier (LLM)，未配置或出错时按 social 处理。buildRuleQuery 仅对 rules 检索。
+//
+// [IN]  Config.WhisperClassifier（可选，NewLLMWhisperClassifier 基于 LLM 路由）
+// [OUT] autodm.go（ProcessQueuedEvent 在规则注入前打标签、buildRuleQuery 按标签检索）
+// [POS] AutoDM 私聊提问的 RAG 闸门
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent/llm"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+)
+
+// WhisperKind tags a DM-directed whisper.
+type WhisperKind string
+
+const (
+	WhisperRules  WhisperKind = "rules"
+	WhisperSocial WhisperKind = "social"
+)
+
+// WhisperClassifier decides whisper kinds the keyword heuristic is unsure about.
+type WhisperClassifier interface {
+	ClassifyWhisper(ctx context.Context, text string) (WhisperKind, error)
+}
+
+// rulesKeywords mark a whisper as a rules question.
+var rulesKeywords = []string{
+	"rule", "ability", "abilities", "power", "how does", "how do", "can i", "can the", "can a",
+	"when does", "what happens", "allowed", "legal", "nominat", "vote", "execut", "ghost",
+	"poison", "drunk", "wake", "alignment",
+	"规则", "能力", "技能", "怎么", "如何", "可以", "能不能", "投票", "提名", "处决", "中毒", "醉酒", "阵营",
+}
+
+// classifyWhisperHeuristic returns the kind and whether the keywords settle it.
+func classifyWhisperHeuristic(text string) (WhisperKind, bool) {
+	q := strings.ToLower(strings.TrimSpace(text))
+	for _, kw := range rulesKeywords {
+		if strings.Contains(q, kw) {
+			return WhisperRules, true
+		}
+	}
+	// Role names match whole words so "imp" does not fire on "simple"
+	words := " " + strings.Join(strings.FieldsFunc(q, func(c rune) bool { return !unicode.IsLetter(c) }), " ") + " "
+	for _, r := range game.GetAllRoles() {
+		if strings.Contains(words, " "+strings.ToLower(r.Name)+" ") || (r.NameCN != "" && strings.Contains(q, r.NameCN)) {
+			return WhisperRules, true
+		}
+	}
+	isQuestion := strings.HasSuffix(q, "?") || strings.HasSuffix(q, "？") || strings.HasSuffix(q, "吗")
+	return WhisperSocial, !isQuestion
+}
+
+// classifyWhisper applies the heuristic, asking the optional classifier only when unsure.
+func (a *AutoDM) classifyWhisper(ctx context.Context, text string) WhisperKind {
+	kind, sure := classifyWhisperHeuristic(text)
+	a.mu.RLock()
+	classifier := a.whisperClassifier
+	a.mu.RUnlock()
+	if sure || classifier == nil {
+		return kind
+	}
+	llmKind, err := classifier.ClassifyWhisper(ctx, text)
+	if err != nil {
+		a.logger.Warn("whisper classification failed, treating as social", "error", err)
+		return WhisperSocial
+	}
+	return llmKind
+}
+
+// tagWhisperKind tags question events so buildRuleQuery only retrieves rules for rules questions.
+func (a *AutoDM) tagWhisperKind(ctx context.Context, event *Event) {
+	if event == nil || event.Type != "question" {
+		return
+	}
+	question, _ := event.Data["question"].(string)
+	event.Data["whisper_kind"] = string(a.classifyWhisper(ctx, question))
+}
+
+const whisperClassifierPrompt = "Classify a player's private message to the Blood on the Clocktower storyteller. " +
+	"Answer with exactly one word: \"rules\" if it asks how the game, a role or an ability works, otherwise \"social\"."
+
+// routerWhisperClassifier asks the LLM router's quick model.
+type routerWhisperClassifier struct {
+	router *llm.Router
+}
+
+// NewLLMWhisperClassifier builds a WhisperClassifier on the LLM routing config.
+func NewLLMWhisperClassifier(cfg LLMRoutingConfig) WhisperClassifier {
+	cfg.Language = ""
+	return routerWhisperClassifier{router: llm.NewRouterFromConfig(cfg)}
+}
+
+func (c routerWhisperClassifier) ClassifyWhisper(ctx context.Context, text string) (WhisperKind, error) {
+	ctx = llm.WithLanguage(ctx, "") // drop the room's narration language carried by ctx
+	out, err := c.router.SimpleChat(ctx, llm.TaskQuick, whisperClassifierPrompt, text)
+	if err != nil {
+		return "", fmt.Errorf("agent.routerWhisperClassifier.ClassifyWhisper: %w", err)
+	}
+	if strings.Contains(strings.ToLower(out), string(WhisperRules)) {
+		return WhisperRules, nil
+	}
+	return WhisperSocial, nil
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"context"
+	"testing"
+)
+
+type countingClassifier struct {
+	n    int
+	kind WhisperKind
+}
+
+func (c *countingClassifier) ClassifyWhisper(context.Context, string) (WhisperKind, error) {
+	c.n++
+	return c.kind, nil
+}
+
+func TestClassifyWhisperHeuristic(t *testing.T) {
+	for text, want := range map[string]WhisperKind{
+		"how does the Monk work?":   WhisperRules,
+		"hi there":                  WhisperSocial,
+		"Is the imp awake tonight?": WhisperRules,
+		"that was a simple plan":    WhisperSocial,
+		"占卜师每晚都能查验吗":                WhisperRules,
+	} {
+		if got, _ := classifyWhisperHeuristic(text); got != want {
+			t.Errorf("%q: expected %s, got %s", text, want, got)
+		}
+	}
+}
+
+func TestWhisperClassifierOnlyAskedWhenUnsure(t *testing.T) {
+	classifier := &countingClassifier{kind: WhisperRules}
+	a := NewAutoDM(Config{WhisperClassifier: classifier})
+	ctx := context.Background()
+
+	if a.classifyWhisper(ctx, "hi there") != WhisperSocial || a.classifyWhisper(ctx, "how does the Monk work?") != WhisperRules {
+		t.Fatal("expected keyword-settled whispers to skip the classifier")
+	}
+	if classifier.n != 0 {
+		t.Fatalf("expected no classifier calls, got %d", classifier.n)
+	}
+	if a.classifyWhisper(ctx, "is this normal?") != WhisperRules || classifier.n != 1 {
+		t.Fatalf("expected an ambiguous question to consult the classifier once, calls=%d", classifier.n)
+	}
+}
+
+func TestOnlyRulesWhispersBuildRuleQuery(t *testing.T) {
+	a := &AutoDM{}
+	rules := Event{Type: "question", Data: map[string]interface{}{"question": "how does the Monk work?"}}
+	a.tagWhisperKind(context.Background(), &rules)
+	if query, _ := buildRuleQuery(rules); query != "how does the Monk work?" {
+		t.Fatalf("expected the rules question to be the RAG query, got %q", query)
+	}
+
+	social := Event{Type: "question", Data: map[string]interface{}{"question": "hi there"}}
+	a.tagWhisperKind(context.Background(), &social)
+	if query, _ := buildRuleQuery(social); query != "" {
+		t.Fatalf("expected no RAG query for a social whisper, got %q", query)
+	}
+}
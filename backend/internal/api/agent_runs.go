@@ -0,0 +1,188 @@
+// Package api AutoDM 运行审计查询（仅 DM）
+//
+// GET /v1/rooms/{room_id}/agent/runs 按时间倒序返回该房间最近的 AutoDM 运行 (状态、耗时、计划)，
+// GET .../agent/runs/{run_id} 返回单次运行的完整记录 (含工具调用)；
+// GET /v1/rooms/{room_id}/agent/tool-calls 按时间倒序返回该房间 AutoDM 的工具调用审计
+// (参数、结果、错误、耗时)，支持 ?tool= 精确过滤。列表接口均支持 limit/offset 分页。
+// 未配置 AgentRunStore (WithAgentRunStore) 时返回 503。
+//
+// [IN]  internal/agent（AgentRunStore、ToolCallAudit）
+// [OUT] api.go（路由注册）
+// [POS] HTTP 接口层的 Auto-DM 调试视图
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent"
+)
+
+const (
+	defaultAgentAuditLimit = 50
+	maxAgentAuditLimit     = 500
+)
+
+// WithAgentRunStore exposes recorded AutoDM runs to the DM inspection endpoints.
+func WithAgentRunStore(runs agent.AgentRunStore) ServerOption {
+	return func(s *Server) {
+		s.agentRuns = runs
+	}
+}
+
+// RoomMembers answers room membership (implemented by *store.Store).
+type RoomMembers interface {
+	IsMember(ctx context.Context, roomID, userID string) (bool, string, error)
+}
+
+// requireRoomDM writes 403 and returns false unless the caller is the room's DM.
+func (s *Server) requireRoomDM(w http.ResponseWriter, r *http.Request) bool {
+	userID := r.Context().Value(userIDKey).(string)
+	ok, role, _ := s.members.IsMember(r.Context(), chi.URLParam(r, "room_id"), userID)
+	if !ok || role != "dm" {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// auditPage reads limit/offset query parameters with defaults and caps.
+func auditPage(r *http.Request) (limit, offset int) {
+	limit, _ = strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = defaultAgentAuditLimit
+	}
+	if limit > maxAgentAuditLimit {
+		limit = maxAgentAuditLimit
+	}
+	offset, _ = strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// fetchToolCalls godoc
+// @Summary List AutoDM tool calls (DM only)
+// @Description Audited tool calls made by the AutoDM in this room, newest first, with arguments, results and durations
+// @Tags Agent
+// @Security BearerAuth
+// @Produce json
+// @Param room_id path string true "Room ID"
+// @Param tool query string false "Exact tool name filter"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param offset query int false "Records to skip"
+// @Success 200 {array} agent.ToolCallAudit
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 503 {string} string "agent runs not recorded"
+// @Router /v1/rooms/{room_id}/agent/tool-calls [get]
+func (s *Server) fetchToolCalls(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRoomDM(w, r) {
+		return
+	}
+	s.serveToolCalls(w, r)
+}
+
+// serveToolCalls writes the room's tool-call page; access is checked by the caller.
+func (s *Server) serveToolCalls(w http.ResponseWriter, r *http.Request) {
+	if s.agentRuns == nil {
+		http.Error(w, "agent runs not recorded", http.StatusServiceUnavailable)
+		return
+	}
+	limit, offset := auditPage(r)
+	q := agent.ToolCallQuery{Tool: r.URL.Query().Get("tool"), Limit: limit, Offset: offset}
+	calls, err := s.agentRuns.ListToolCalls(r.Context(), chi.URLParam(r, "room_id"), q)
+	if err != nil {
+		s.logger.Error("list tool calls failed", zap.Error(err))
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(calls)
+}
+
+// fetchAgentRuns godoc
+// @Summary List AutoDM runs (DM only)
+// @Description Recent AutoDM runs in this room, newest first, with status, latency and plan
+// @Tags Agent
+// @Security BearerAuth
+// @Produce json
+// @Param room_id path string true "Room ID"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param offset query int false "Records to skip"
+// @Success 200 {array} agent.AgentRun
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 503 {string} string "agent runs not recorded"
+// @Router /v1/rooms/{room_id}/agent/runs [get]
+func (s *Server) fetchAgentRuns(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRoomDM(w, r) {
+		return
+	}
+	s.serveAgentRuns(w, r)
+}
+
+// serveAgentRuns writes the room's run page; access is checked by the caller.
+func (s *Server) serveAgentRuns(w http.ResponseWriter, r *http.Request) {
+	if s.agentRuns == nil {
+		http.Error(w, "agent runs not recorded", http.StatusServiceUnavailable)
+		return
+	}
+	limit, offset := auditPage(r)
+	runs, err := s.agentRuns.ListRuns(r.Context(), chi.URLParam(r, "room_id"), limit, offset)
+	if err != nil {
+		s.logger.Error("list agent runs failed", zap.Error(err))
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
+// fetchAgentRun godoc
+// @Summary Get one AutoDM run (DM only)
+// @Description Full record of one AutoDM run, including its plan and tool calls
+// @Tags Agent
+// @Security BearerAuth
+// @Produce json
+// @Param room_id path string true "Room ID"
+// @Param run_id path string true "Run ID"
+// @Success 200 {object} agent.AgentRun
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "run not found"
+// @Failure 503 {string} string "agent runs not recorded"
+// @Router /v1/rooms/{room_id}/agent/runs/{run_id} [get]
+func (s *Server) fetchAgentRun(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRoomDM(w, r) {
+		return
+	}
+	s.serveAgentRun(w, r)
+}
+
+// serveAgentRun writes one run of the room; access is checked by the caller.
+func (s *Server) serveAgentRun(w http.ResponseWriter, r *http.Request) {
+	if s.agentRuns == nil {
+		http.Error(w, "agent runs not recorded", http.StatusServiceUnavailable)
+		return
+	}
+	run, err := s.agentRuns.GetRun(r.Context(), chi.URLParam(r, "run_id"))
+	if errors.Is(err, agent.ErrRunNotFound) || (err == nil && run.RoomID != chi.URLParam(r, "room_id")) {
+		http.Error(w, "run not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		s.logger.Error("get agent run failed", zap.Error(err))
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
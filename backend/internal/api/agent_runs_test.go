@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+type discardDispatcher struct{}
+
+func (discardDispatcher) DispatchAsync(types.CommandEnvelope) error { return nil }
+
+// roomRequest builds a GET request with the room_id route parameter set.
+func roomRequest(target, roomID string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("room_id", roomID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+// runAutoDM processes one resolved nomination, which announces a tally through an MCP tool.
+func runAutoDM(t *testing.T, runs agent.AgentRunStore) {
+	t.Helper()
+	a := agent.NewAutoDM(agent.Config{Enabled: true, RunStore: runs})
+	a.SetDispatcher(discardDispatcher{}, nil)
+	a.SetTranslator(nil)
+	payload, _ := json.Marshal(map[string]string{"result": "not_on_the_block", "votes_for": "1", "threshold": "3"})
+	ev := types.Event{RoomID: "room-1", Seq: 7, EventType: "nomination.resolved", Payload: payload}
+	if err := a.ProcessQueuedEvent(context.Background(), ev); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+}
+
+func TestToolCallsEndpointListsAutoDMToolCalls(t *testing.T) {
+	runs := agent.NewMemoryRunStore(0)
+	runAutoDM(t, runs)
+	s := &Server{agentRuns: runs, logger: zap.NewNop()}
+
+	rec := httptest.NewRecorder()
+	s.serveToolCalls(rec, roomRequest("/v1/rooms/room-1/agent/tool-calls?tool=send_public_message", "room-1"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var calls []agent.ToolCallAudit
+	if err := json.Unmarshal(rec.Body.Bytes(), &calls); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(calls) == 0 || calls[0].ToolName != "send_public_message" || calls[0].RunID == "" || len(calls[0].Args) == 0 {
+		t.Fatalf("expected an audited send_public_message call, got %+v", calls)
+	}
+
+	rec = httptest.NewRecorder()
+	s.serveToolCalls(rec, roomRequest("/v1/rooms/room-1/agent/tool-calls?tool=advance_phase", "room-1"))
+	if rec.Body.String() != "[]\n" {
+		t.Fatalf("expected the tool filter to exclude other tools, got %s", rec.Body.String())
+	}
+}
+
+func TestAgentRunEndpointsReturnCompletedRunWithPlan(t *testing.T) {
+	runs := agent.NewMemoryRunStore(0)
+	runAutoDM(t, runs)
+	s := &Server{agentRuns: runs, logger: zap.NewNop()}
+
+	rec := httptest.NewRecorder()
+	s.serveAgentRuns(rec, roomRequest("/v1/rooms/room-1/agent/runs", "room-1"))
+	var list []agent.AgentRun
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v (%s)", err, rec.Body.String())
+	}
+	if len(list) != 1 || list[0].Status != "ok" {
+		t.Fatalf("expected one completed run, got %+v", list)
+	}
+
+	req := roomRequest("/v1/rooms/room-1/agent/runs/"+list[0].ID, "room-1")
+	chi.RouteContext(req.Context()).URLParams.Add("run_id", list[0].ID)
+	rec = httptest.NewRecorder()
+	s.serveAgentRun(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var run agent.AgentRun
+	if err := json.Unmarshal(rec.Body.Bytes(), &run); err != nil {
+		t.Fatalf("decode run: %v", err)
+	}
+	var plan struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(run.PlanJSON, &plan); err != nil || plan.Kind != "vote_tally" {
+		t.Fatalf("expected a vote_tally plan, got %s (%v)", run.PlanJSON, err)
+	}
+	if run.OutputDigest == "" || len(run.ToolCalls) == 0 {
+		t.Fatalf("expected output digest and tool calls on the full record, got %+v", run)
+	}
+
+	req = roomRequest("/v1/rooms/room-2/agent/runs/"+run.ID, "room-2")
+	chi.RouteContext(req.Context()).URLParams.Add("run_id", run.ID)
+	rec = httptest.NewRecorder()
+	s.serveAgentRun(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for another room's run, got %d", rec.Code)
+	}
+}
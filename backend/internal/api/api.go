@@ -1,5 +1,6 @@
 // Package api HTTP REST API 路由与处理器
 //
+// [IN]  internal/agent（AutoDM 运行审计存储）
 // [IN]  internal/auth（JWT 验证与密码哈希）
 // [IN]  internal/bot（Bot 管理）
 // [IN]  internal/engine（游戏状态与事件结构）
@@ -26,6 +27,7 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.uber.org/zap"
 
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/auth"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/bot"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
@@ -48,6 +50,25 @@ type Server struct {
 	logger  *zap.Logger
 	llmInfo *LLMInfo
 	botMgr  *bot.Manager
+
+	allowedOrigins []string // CORS 白名单，空则允许任意来源
+	authLimiter    *ipRateLimiter
+	passwordPolicy auth.PasswordPolicy
+
+	// agentRuns backs the DM-only AutoDM audit endpoints (agent_runs.go); nil disables them
+	agentRuns agent.AgentRunStore
+
+	// modelOverrides backs the DM AutoDM model endpoints (autodm_model.go); nil disables them
+	modelOverrides ModelOverrider
+
+	// notes backs the DM-only storyteller notes (storyteller_notes.go); nil disables them
+	notes NotesStore
+
+	// members answers requireRoomDM (agent_runs.go); set to the store by NewServer
+	members RoomMembers
+
+	// archiveKey signs DM room exports and verifies imports (room_export.go)
+	archiveKey []byte
 }
 
 // LLMInfo holds LLM provider information for the health endpoint.
@@ -63,7 +84,6 @@ func NewServer(st *store.Store, jwt *auth.JWTManager, roomMgr *room.RoomManager,
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(corsMiddleware)
 
 	s := &Server{
 		Router:  r,
@@ -73,9 +93,15 @@ func NewServer(st *store.Store, jwt *auth.JWTManager, roomMgr *room.RoomManager,
 		logger:  logger,
 	}
 
+	if st != nil {
+		s.notes = st
+		s.members = st
+	}
+
 	for _, opt := range opts {
 		opt(s)
 	}
+	r.Use(s.corsMiddleware)
 
 	// Health & Metrics
 	r.Get("/health", s.health)
@@ -87,19 +113,36 @@ func NewServer(st *store.Store, jwt *auth.JWTManager, roomMgr *room.RoomManager,
 		httpSwagger.URL("/swagger/doc.json"),
 	))
 
-	// Auth endpoints
-	r.Post("/v1/auth/register", s.register)
-	r.Post("/v1/auth/login", s.login)
-	r.Post("/v1/auth/quick", s.quickLogin)
+	// Auth endpoints (rate limited per IP when WithAuthRateLimit is set)
+	r.Group(func(r chi.Router) {
+		r.Use(s.authLimiter.middleware)
+		r.Post("/v1/auth/register", s.register)
+		r.Post("/v1/auth/login", s.login)
+		r.Post("/v1/auth/quick", s.quickLogin)
+	})
 
 	// Room endpoints (protected)
 	r.Route("/v1/rooms", func(r chi.Router) {
 		r.Use(s.authMiddleware)
 		r.Post("/", s.createRoom)
+		r.Post("/import", s.importRoom)
 		r.Post("/{room_id}/join", s.joinRoom)
 		r.Get("/{room_id}/events", s.fetchEvents)
 		r.Get("/{room_id}/state", s.fetchState)
 		r.Get("/{room_id}/replay", s.replay)
+		r.Get("/{room_id}/export", s.exportRoom)
+		r.Get("/{room_id}/timeline", s.fetchTimeline)
+		r.Get("/{room_id}/visibility", s.fetchVisibility)
+		r.Get("/{room_id}/agent/runs", s.fetchAgentRuns)
+		r.Get("/{room_id}/agent/runs/{run_id}", s.fetchAgentRun)
+		r.Get("/{room_id}/agent/tool-calls", s.fetchToolCalls)
+		r.Post("/{room_id}/setup/preview", s.previewSetup)
+		r.Get("/{room_id}/night-sheet", s.fetchNightSheet)
+		r.Get("/{room_id}/autodm/model", s.autoDMModel)
+		r.Put("/{room_id}/autodm/model", s.autoDMModel)
+		r.Delete("/{room_id}/autodm/model", s.autoDMModel)
+		r.Get("/{room_id}/notes", s.storytellerNotes)
+		r.Post("/{room_id}/notes", s.storytellerNotes)
 		r.Post("/{room_id}/bots", s.addBots)
 	})
 
@@ -108,20 +151,6 @@ func NewServer(st *store.Store, jwt *auth.JWTManager, roomMgr *room.RoomManager,
 	return s
 }
 
-// corsMiddleware handles CORS for all requests.
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-Request-ID")
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
-}
-
 // health godoc
 // @Summary Health check endpoint
 // @Description Returns server health status
@@ -153,7 +182,7 @@ type AuthResponse struct {
 // @Produce json
 // @Param request body RegisterRequest true "Registration details"
 // @Success 200 {object} AuthResponse
-// @Failure 400 {string} string "invalid json"
+// @Failure 400 {string} string "invalid json, password too weak or too long (over 72 bytes)"
 // @Failure 409 {string} string "user exists or db error"
 // @Router /v1/auth/register [post]
 func (s *Server) register(w http.ResponseWriter, r *http.Request) {
@@ -162,6 +191,10 @@ func (s *Server) register(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid json", http.StatusBadRequest)
 		return
 	}
+	if err := s.passwordPolicy.Validate(req.Password); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	hash, err := auth.HashPassword(req.Password)
 	if err != nil {
 		http.Error(w, "hash error", http.StatusInternalServerError)
@@ -292,7 +325,7 @@ type JoinRoomResponse struct {
 
 // joinRoom godoc
 // @Summary Join an existing game room
-// @Description Join a Blood on the Clocktower game room as a player
+// @Description Join a Blood on the Clocktower game room as a player. Idempotent: re-joining keeps the existing membership and seat.
 // @Tags Rooms
 // @Security BearerAuth
 // @Produce json
@@ -300,26 +333,41 @@ type JoinRoomResponse struct {
 // @Success 200 {object} JoinRoomResponse
 // @Failure 401 {string} string "unauthorized"
 // @Failure 404 {string} string "room not found"
+// @Failure 500 {string} string "db error"
 // @Router /v1/rooms/{room_id}/join [post]
 func (s *Server) joinRoom(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value(userIDKey).(string)
 	roomID := chi.URLParam(r, "room_id")
-	if err := s.store.AddRoomMember(r.Context(), store.RoomMember{RoomID: roomID, UserID: userID, Role: "player", Joined: time.Now().UTC()}); err != nil {
-		http.Error(w, "failed to join room", http.StatusInternalServerError)
+	ok, role, err := s.store.IsMember(r.Context(), roomID, userID)
+	if err != nil {
+		s.logger.Error("join membership lookup failed", zap.String("room_id", roomID), zap.Error(err))
+		http.Error(w, "db error", http.StatusInternalServerError)
 		return
 	}
+	if !ok {
+		if err := s.store.AddRoomMember(r.Context(), store.RoomMember{RoomID: roomID, UserID: userID, Role: "player", Joined: time.Now().UTC()}); err != nil {
+			http.Error(w, "failed to join room", http.StatusInternalServerError)
+			return
+		}
+		role = "player"
+	}
+	if role != "dm" {
+		s.joinGame(r.Context(), roomID, userID)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(JoinRoomResponse{Status: "joined"})
 }
 
 // fetchEvents godoc
 // @Summary Fetch room events
-// @Description Retrieve events from a room for state synchronization (supports last_seq incremental sync)
+// @Description Retrieve events from a room for state synchronization (supports last_seq incremental sync). Each event carries PrevHash/Hash for client-side tamper checks (store.EventHash).
 // @Tags Events
 // @Security BearerAuth
 // @Produce json
 // @Param room_id path string true "Room ID"
 // @Param after_seq query integer false "Fetch events after this sequence number"
+// @Param type query string false "Only events of this type, in seq order (private types are DM-only)"
+// @Param limit query integer false "Maximum events returned with type (default 200)"
 // @Success 200 {array} store.StoredEvent
 // @Failure 401 {string} string "unauthorized"
 // @Failure 403 {string} string "forbidden"
@@ -331,11 +379,15 @@ func (s *Server) fetchEvents(w http.ResponseWriter, r *http.Request) {
 	if q := r.URL.Query().Get("after_seq"); q != "" {
 		afterSeq, _ = strconv.ParseInt(q, 10, 64)
 	}
-	ok, _, _ := s.store.IsMember(r.Context(), roomID, userID)
+	ok, role, _ := s.store.IsMember(r.Context(), roomID, userID)
 	if !ok {
 		http.Error(w, "forbidden", http.StatusForbidden)
 		return
 	}
+	if eventType := r.URL.Query().Get("type"); eventType != "" {
+		s.fetchEventsByType(w, r, eventType, types.Viewer{UserID: userID, IsDM: role == "dm"})
+		return
+	}
 	events, _ := s.store.LoadEventsAfter(r.Context(), roomID, afterSeq, 200)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(events)
@@ -404,12 +456,13 @@ func (s *Server) replay(w http.ResponseWriter, r *http.Request) {
 		viewerParam = userID
 	}
 	events, _ := s.store.LoadEventsUpTo(r.Context(), roomID, toSeq)
-	state := engine.NewState(roomID)
+	payloads := make([]engine.EventPayload, 0, len(events))
 	for _, e := range events {
 		var p map[string]string
 		_ = json.Unmarshal([]byte(e.PayloadJSON), &p)
-		state.Reduce(engine.EventPayload{Seq: e.Seq, Type: e.EventType, Actor: e.ActorUserID, Payload: p})
+		payloads = append(payloads, engine.EventPayload{Seq: e.Seq, EventID: e.EventID, Type: e.EventType, Actor: e.ActorUserID, Payload: p})
 	}
+	state := engine.Replay(roomID, payloads)
 	viewer := types.Viewer{UserID: viewerParam, IsDM: isDM}
 	projected := projection.ProjectedState(state, viewer)
 	w.Header().Set("Content-Type", "application/json")
@@ -426,6 +479,13 @@ func WithLLMInfo(info *LLMInfo) ServerOption {
 	}
 }
 
+// WithPasswordPolicy sets the password requirements enforced on register.
+func WithPasswordPolicy(policy auth.PasswordPolicy) ServerOption {
+	return func(s *Server) {
+		s.passwordPolicy = policy
+	}
+}
+
 // WithBotManager sets the bot manager for bot endpoints.
 func WithBotManager(mgr *bot.Manager) ServerOption {
 	return func(s *Server) {
@@ -444,6 +504,8 @@ func WithBotManager(mgr *bot.Manager) ServerOption {
 type AddBotsRequest struct {
 	Count       int    `json:"count" example:"6"`
 	Personality string `json:"personality,omitempty" example:"random"`
+	TargetTotal int    `json:"target_total,omitempty" example:"7"` // fill the room to this many players; overrides count
+	LLMChat     bool   `json:"llm_chat,omitempty"`                 // bots chat through the LLM (BOT_LLM_CHAT)
 }
 
 // AddBotsResponse is the response after adding bots.
@@ -477,7 +539,7 @@ func (s *Server) addBots(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid json", http.StatusBadRequest)
 		return
 	}
-	if req.Count <= 0 {
+	if req.Count <= 0 && req.TargetTotal <= 0 {
 		req.Count = 6 // Default for a 7-player game (1 human + 6 bots)
 	}
 
@@ -491,6 +553,9 @@ func (s *Server) addBots(w http.ResponseWriter, r *http.Request) {
 		RoomID:      roomID,
 		Count:       req.Count,
 		Personality: bot.Personality(req.Personality),
+		TargetTotal: req.TargetTotal,
+		TakenSeats:  takenSeats(ra.GetState()),
+		LLMChat:     req.LLMChat,
 	}, ra)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -0,0 +1,99 @@
+// auth_ratelimit.go — 认证接口按 IP 限流
+//
+// 登录/注册/快速登录共用一组按客户端 IP 的令牌桶 (复用 realtime.TokenBucket)，
+// 超限返回 429 并带 Retry-After。默认关闭，只有 WithAuthRateLimit 给出正的突发与速率时启用。IP 取 middleware.RealIP 改写后的 RemoteAddr。
+// 长时间未访问的 IP 在表过大时清理，防止内存随来源无限增长。
+//
+// [IN]  internal/realtime（TokenBucket）
+// [OUT] api.go（/v1/auth/* 路由组）、cmd/server（WithAuthRateLimit）
+// [POS] HTTP 接口层的暴力破解防护
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/realtime"
+)
+
+const (
+	// authLimiterMaxIPs triggers pruning of idle IPs once exceeded.
+	authLimiterMaxIPs = 10000
+	authLimiterIdle   = 10 * time.Minute
+)
+
+type ipBucket struct {
+	bucket   *realtime.TokenBucket
+	lastSeen time.Time
+}
+
+// ipRateLimiter keeps one token bucket per client IP.
+type ipRateLimiter struct {
+	mu      sync.Mutex
+	burst   float64
+	perSec  float64
+	buckets map[string]*ipBucket
+}
+
+func newIPRateLimiter(burst int, perMinute float64) *ipRateLimiter {
+	return &ipRateLimiter{burst: float64(burst), perSec: perMinute / 60, buckets: make(map[string]*ipBucket)}
+}
+
+// WithAuthRateLimit sets the per-IP auth limit (burst, refill per minute); burst <= 0 disables it.
+func WithAuthRateLimit(burst int, perMinute float64) ServerOption {
+	return func(s *Server) {
+		if burst <= 0 || perMinute <= 0 {
+			s.authLimiter = nil
+			return
+		}
+		s.authLimiter = newIPRateLimiter(burst, perMinute)
+	}
+}
+
+func (l *ipRateLimiter) allow(ip string) bool {
+	l.mu.Lock()
+	now := time.Now()
+	if len(l.buckets) > authLimiterMaxIPs {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) > authLimiterIdle {
+				delete(l.buckets, k)
+			}
+		}
+	}
+	b, ok := l.buckets[ip]
+	if !ok {
+		b = &ipBucket{bucket: realtime.NewTokenBucket(l.burst, l.perSec)}
+		l.buckets[ip] = b
+	}
+	b.lastSeen = now
+	l.mu.Unlock()
+	return b.bucket.Allow()
+}
+
+// middleware rejects requests over the limit with 429 and Retry-After.
+func (l *ipRateLimiter) middleware(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	retryAfter := strconv.Itoa(int(math.Ceil(1 / l.perSec)))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.allow(clientIP(r)) {
+			w.Header().Set("Retry-After", retryAfter)
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP strips the port RemoteAddr keeps when RealIP found no proxy header.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
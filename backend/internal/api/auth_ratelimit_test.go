@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRapidLoginsFromOneIPAreThrottled(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := newIPRateLimiter(3, 6).middleware(ok)
+
+	login := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/login", nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 3; i++ {
+		if rec := login("10.0.0.1:5000"); rec.Code != http.StatusOK {
+			t.Fatalf("attempt %d: expected 200 within burst, got %d", i+1, rec.Code)
+		}
+	}
+	rec := login("10.0.0.1:5001")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after burst, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "10" {
+		t.Fatalf("expected Retry-After 10s at 6/min, got %q", rec.Header().Get("Retry-After"))
+	}
+	if rec := login("10.0.0.2:5000"); rec.Code != http.StatusOK {
+		t.Fatalf("expected another IP to be unaffected, got %d", rec.Code)
+	}
+}
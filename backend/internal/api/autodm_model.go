@@ -0,0 +1,127 @@
+// Package api 按房间切换 AutoDM 模型（仅 DM）
+//
+// GET /v1/rooms/{room_id}/autodm/model 返回当前覆盖 (无覆盖时 404)；PUT 同路径以
+// {"model","base_url"} 切换该房间 AutoDM 的模型，须在白名单内 (否则 400)；提供方由 base_url
+// 决定，请求携带 provider 时 400 (避免看似切换提供方、实则把默认密钥发往另一端点)，
+// 成功后写入 autodm_model_overrides，重启时恢复；DELETE 清除覆盖，恢复配置的模型。未配置 ModelOverrider (WithModelOverrides) 时返回 503。
+//
+// [IN]  internal/agent（ModelOverride、ErrModelNotAllowed）
+// [IN]  internal/store（autodm_model_overrides）
+// [OUT] api.go（路由注册）
+// [POS] HTTP 接口层的 AutoDM 运行时配置
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// ModelOverrider switches a room's AutoDM model (implemented by agent.AutoDM).
+type ModelOverrider interface {
+	SetModelOverride(roomID string, o agent.ModelOverride) error
+	ClearModelOverride(roomID string)
+	ModelOverride(roomID string) (agent.ModelOverride, bool)
+}
+
+// WithModelOverrides enables the DM model override endpoints.
+func WithModelOverrides(m ModelOverrider) ServerOption {
+	return func(s *Server) {
+		s.modelOverrides = m
+	}
+}
+
+// autoDMModel godoc
+// @Summary Get, set or clear the room's AutoDM model override (DM only)
+// @Description PUT switches the AutoDM model/base URL for this room (allowlisted only) and persists it; the provider follows base_url and may not be set; DELETE restores the configured model
+// @Tags Agent
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param room_id path string true "Room ID"
+// @Param body body agent.ModelOverride false "Override (PUT only)"
+// @Success 200 {object} agent.ModelOverride
+// @Failure 400 {string} string "model not allowed or provider set"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "no override"
+// @Failure 503 {string} string "model overrides unavailable"
+// @Router /v1/rooms/{room_id}/autodm/model [put]
+func (s *Server) autoDMModel(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRoomDM(w, r) {
+		return
+	}
+	s.serveAutoDMModel(w, r)
+}
+
+// serveAutoDMModel handles the override methods; access is checked by the caller.
+func (s *Server) serveAutoDMModel(w http.ResponseWriter, r *http.Request) {
+	if s.modelOverrides == nil {
+		http.Error(w, "model overrides unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	roomID := chi.URLParam(r, "room_id")
+	switch r.Method {
+	case http.MethodPut:
+		s.putAutoDMModel(w, r, roomID)
+	case http.MethodDelete:
+		s.modelOverrides.ClearModelOverride(roomID)
+		if s.store != nil {
+			if err := s.store.DeleteModelOverride(r.Context(), roomID); err != nil {
+				s.logger.Error("delete model override failed", zap.Error(err))
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		o, ok := s.modelOverrides.ModelOverride(roomID)
+		if !ok {
+			http.Error(w, "no override", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(o)
+	}
+}
+
+// modelOverrideRequest is the PUT body; Provider is only decoded to reject it.
+type modelOverrideRequest struct {
+	agent.ModelOverride
+	Provider string `json:"provider"`
+}
+
+func (s *Server) putAutoDMModel(w http.ResponseWriter, r *http.Request, roomID string) {
+	var req modelOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	if req.Provider != "" {
+		http.Error(w, "provider cannot be set; it follows base_url", http.StatusBadRequest)
+		return
+	}
+	o := req.ModelOverride
+	if err := s.modelOverrides.SetModelOverride(roomID, o); err != nil {
+		if errors.Is(err, agent.ErrModelNotAllowed) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "override failed", http.StatusInternalServerError)
+		return
+	}
+	if s.store != nil {
+		userID, _ := r.Context().Value(userIDKey).(string)
+		row := store.ModelOverride{RoomID: roomID, Model: o.Model, BaseURL: o.BaseURL, UpdatedBy: userID}
+		if err := s.store.SaveModelOverride(r.Context(), row); err != nil {
+			s.logger.Error("save model override failed", zap.Error(err))
+			http.Error(w, "db error", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(o)
+}
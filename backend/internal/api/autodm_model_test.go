@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/agent"
+)
+
+// recordingOverrider remembers the last override it was given.
+type recordingOverrider struct {
+	set map[string]agent.ModelOverride
+}
+
+func (r *recordingOverrider) SetModelOverride(roomID string, o agent.ModelOverride) error {
+	r.set[roomID] = o
+	return nil
+}
+
+func (r *recordingOverrider) ClearModelOverride(roomID string) { delete(r.set, roomID) }
+
+func (r *recordingOverrider) ModelOverride(roomID string) (agent.ModelOverride, bool) {
+	o, ok := r.set[roomID]
+	return o, ok
+}
+
+func putModelRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, "/v1/rooms/room-1/autodm/model", strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("room_id", "room-1")
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestAutoDMModelRejectsProvider(t *testing.T) {
+	overrides := &recordingOverrider{set: map[string]agent.ModelOverride{}}
+	s := &Server{modelOverrides: overrides, logger: zap.NewNop()}
+
+	rec := httptest.NewRecorder()
+	s.serveAutoDMModel(rec, putModelRequest(`{"provider":"anthropic","model":"alt-model"}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a provider, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, ok := overrides.set["room-1"]; ok {
+		t.Fatal("a rejected request must not set an override")
+	}
+
+	rec = httptest.NewRecorder()
+	s.serveAutoDMModel(rec, putModelRequest(`{"model":"alt-model","base_url":"http://alt"}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := overrides.set["room-1"]; got.Model != "alt-model" || got.BaseURL != "http://alt" {
+		t.Fatalf("expected the override to be applied, got %+v", got)
+	}
+}
@@ -0,0 +1,22 @@
+// Package api Bot 补位的座位来源
+//
+// POST /v1/rooms/{room_id}/bots 的 target_total 需要房间已入座玩家的座位号：
+// takenSeats 从房间状态取出非 DM、非旅行者玩家的座位，交给 bot.Manager 计算补位数量与空座位。
+//
+// [IN]  internal/engine（State.Players）
+// [OUT] api.go（addBots）
+// [POS] HTTP 接口层与 Bot 管理器之间的座位换算
+package api
+
+import "github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+
+// takenSeats returns the seats held by the room's players (humans and bots alike).
+func takenSeats(state engine.State) []int {
+	seats := []int{}
+	for _, p := range state.Players {
+		if !p.IsDM && !p.Traveller && p.SeatNumber > 0 {
+			seats = append(seats, p.SeatNumber)
+		}
+	}
+	return seats
+}
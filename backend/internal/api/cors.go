@@ -0,0 +1,41 @@
+// cors.go — CORS 中间件与来源白名单
+//
+// 白名单为空时保持开发期行为 (Access-Control-Allow-Origin: *)；配置后仅回显白名单内的 Origin，
+// WebSocket 握手通过 realtime.OriginAllowed 使用同一规则。
+//
+// [IN]  internal/realtime（OriginAllowed）
+// [OUT] api.go（NewServer 挂载）、cmd/server（WithAllowedOrigins）
+// [POS] HTTP 接口层的跨域控制
+package api
+
+import (
+	"net/http"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/realtime"
+)
+
+// WithAllowedOrigins restricts CORS to the given origins; empty allows any origin.
+func WithAllowedOrigins(origins []string) ServerOption {
+	return func(s *Server) {
+		s.allowedOrigins = origins
+	}
+}
+
+// corsMiddleware handles CORS for all requests.
+func (s *Server) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.allowedOrigins) == 0 {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else if origin := r.Header.Get("Origin"); origin != "" && realtime.OriginAllowed(s.allowedOrigins, origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		}
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-Request-ID")
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
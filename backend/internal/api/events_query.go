@@ -0,0 +1,62 @@
+// Package api 按事件类型查询事件（分析与调试）
+//
+// GET /v1/rooms/{room_id}/events?type=execution.resolved 返回该类型全部事件（按 seq 升序）。
+// 可能对部分玩家不可见的私密类型（projection.IsPrivateEventType）仅 DM 可查；
+// 其余类型逐条经 projection.ProjectEvent 按请求者投影，公开事件的 payload 同样脱敏
+// (如他人的 night.action.completed 结果、player.died 的真实死因)。
+//
+// [IN]  internal/projection（私密事件类型判定、ProjectEvent）
+// [IN]  internal/room（投影所需的当前状态）
+// [IN]  internal/store（LoadEventsByType）
+// [OUT] api.go（fetchEvents 的 type 分支）
+// [POS] HTTP 接口层的事件分析查询
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/projection"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// maxEventsByTypeLimit caps the limit query parameter.
+const maxEventsByTypeLimit = 1000
+
+// fetchEventsByType serves /events?type=; membership is checked by fetchEvents.
+func (s *Server) fetchEventsByType(w http.ResponseWriter, r *http.Request, eventType string, viewer types.Viewer) {
+	if !viewer.IsDM && projection.IsPrivateEventType(eventType) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit > maxEventsByTypeLimit {
+		limit = maxEventsByTypeLimit
+	}
+	roomID := chi.URLParam(r, "room_id")
+	events, err := s.store.LoadEventsByType(r.Context(), roomID, eventType, limit)
+	if err != nil {
+		s.logger.Error("load events by type failed", zap.String("event_type", eventType), zap.Error(err))
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return
+	}
+	ra, err := s.roomMgr.GetOrCreate(r.Context(), roomID)
+	if err != nil {
+		http.Error(w, "room error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(projectStoredEvents(events, ra.GetState(), viewer))
+}
+
+// projectStoredEvents projects stored rows for viewer, dropping the ones it may not see.
+func projectStoredEvents(stored []store.StoredEvent, state engine.State, viewer types.Viewer) []types.ProjectedEvent {
+	_, projected := projection.Project(state, storedToEvents(stored), viewer)
+	return projected
+}
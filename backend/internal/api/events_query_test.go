@@ -0,0 +1,33 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestEventsByTypeHidesOtherPlayersNightResults(t *testing.T) {
+	stored := []store.StoredEvent{
+		{RoomID: "room-1", Seq: 1, EventType: "night.action.completed", ActorUserID: "p2", PayloadJSON: `{"user_id":"p2","role_id":"fortune_teller","result":"yes"}`},
+		{RoomID: "room-1", Seq: 2, EventType: "night.action.completed", ActorUserID: "p1", PayloadJSON: `{"user_id":"p1","role_id":"empath","result":"1"}`},
+	}
+	state := engine.NewState("room-1")
+
+	got := projectStoredEvents(stored, state, types.Viewer{UserID: "p1"})
+	if len(got) != 2 {
+		t.Fatalf("expected both events listed, got %d", len(got))
+	}
+	if string(got[0].Data) != `{}` {
+		t.Fatalf("expected another player's night result blanked, got %s", got[0].Data)
+	}
+	if string(got[1].Data) == `{}` {
+		t.Fatal("expected the viewer's own night result kept")
+	}
+
+	dm := projectStoredEvents(stored, state, types.Viewer{UserID: "dm", IsDM: true})
+	if string(dm[0].Data) == `{}` {
+		t.Fatal("expected the DM to see every night result")
+	}
+}
@@ -0,0 +1,42 @@
+// Package api 说书人夜晚顺序表（仅 DM）
+//
+// GET /v1/rooms/{room_id}/night-sheet 返回本夜 (或最近一夜) 按角色目录顺序排列的行动表：
+// 角色、玩家座位、存活与完成状态，区分首夜与其他夜晚。只读。
+//
+// [IN]  internal/engine（BuildNightSheet）
+// [OUT] api.go（路由注册）
+// [POS] HTTP 接口层的 DM 夜晚总览
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+)
+
+// fetchNightSheet godoc
+// @Summary Fetch the night-order sheet (DM only)
+// @Description The night's queued wakes in catalog order with each player's seat and completion status
+// @Tags Rooms
+// @Security BearerAuth
+// @Produce json
+// @Param room_id path string true "Room ID"
+// @Success 200 {object} engine.NightSheet
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "room error"
+// @Router /v1/rooms/{room_id}/night-sheet [get]
+func (s *Server) fetchNightSheet(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRoomDM(w, r) {
+		return
+	}
+	ra, err := s.roomMgr.GetOrCreate(r.Context(), chi.URLParam(r, "room_id"))
+	if err != nil {
+		http.Error(w, "room error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(engine.BuildNightSheet(ra.GetState()))
+}
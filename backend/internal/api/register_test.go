@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/auth"
+)
+
+func TestRegisterRejectsWeakPasswordWith400(t *testing.T) {
+	s := &Server{passwordPolicy: auth.PasswordPolicy{MinLength: 8}}
+	req := httptest.NewRequest(http.MethodPost, "/v1/auth/register", strings.NewReader(`{"email":"a@b.c","password":"short1"}`))
+	rec := httptest.NewRecorder()
+
+	s.register(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a weak password, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "at least 8 characters") {
+		t.Fatalf("expected a clear reason, got %q", rec.Body.String())
+	}
+}
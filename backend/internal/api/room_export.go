@@ -0,0 +1,119 @@
+// Package api 房间归档导出与导入
+//
+// GET /v1/rooms/{room_id}/export 返回可移植 JSON 文档：DM 得到以服务端密钥签名的完整事件流 (可重新导入)，
+// 其他成员得到按自身视角过滤的公开视图。POST /v1/rooms/import 将 DM 视角文档重放进新房间，
+// 导入者成为新房间的 DM。
+//
+// [IN]  internal/archive（Export、Import、Document）
+// [IN]  internal/store（GetRoom、LoadEventsUpTo、CreateRoom、AppendEvents）
+// [OUT] api.go（路由注册）
+// [POS] HTTP 接口层的对局归档
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/archive"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// WithArchiveSigningKey sets the key that signs DM exports and verifies imports.
+func WithArchiveSigningKey(key []byte) ServerOption {
+	return func(s *Server) {
+		s.archiveKey = key
+	}
+}
+
+// maxImportBytes caps the import request body.
+const maxImportBytes = 16 << 20
+
+// ImportRoomResponse is returned after a successful import.
+type ImportRoomResponse struct {
+	RoomID string `json:"room_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Events int    `json:"events" example:"128"`
+}
+
+// exportRoom godoc
+// @Summary Export a room as portable JSON
+// @Description Room metadata, ordered event stream and final state. The DM gets the full re-importable stream; other members get their projected view.
+// @Tags Rooms
+// @Security BearerAuth
+// @Produce json
+// @Param room_id path string true "Room ID"
+// @Success 200 {object} archive.Document
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "db error"
+// @Router /v1/rooms/{room_id}/export [get]
+func (s *Server) exportRoom(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+	roomID := chi.URLParam(r, "room_id")
+	ok, role, _ := s.store.IsMember(r.Context(), roomID, userID)
+	if !ok {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	rm, err := s.store.GetRoom(r.Context(), roomID)
+	if err != nil {
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return
+	}
+	events, err := s.store.LoadEventsUpTo(r.Context(), roomID, 0)
+	if err != nil {
+		s.logger.Error("export load events failed", zap.String("room_id", roomID), zap.Error(err))
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return
+	}
+	viewer := types.Viewer{UserID: userID, IsDM: role == "dm"}
+	doc := archive.Export(*rm, events, viewer, time.Now().UTC(), s.archiveKey)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="room-`+roomID+`.json"`)
+	json.NewEncoder(w).Encode(doc)
+}
+
+// importRoom godoc
+// @Summary Import an exported room
+// @Description Verify a DM-view export (contiguous seqs, causation commands, chain hashes, server signature) and replay it into a new room owned by the caller
+// @Tags Rooms
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body archive.Document true "DM-view export document"
+// @Success 200 {object} ImportRoomResponse
+// @Failure 400 {string} string "invalid document or integrity check failed"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "db error"
+// @Router /v1/rooms/import [post]
+func (s *Server) importRoom(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+	var doc archive.Document
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBytes)).Decode(&doc); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	rm := store.Room{ID: uuid.NewString(), CreatedBy: userID, DMUserID: userID, Status: "imported", CreatedAt: time.Now().UTC()}
+	events, _, err := archive.Import(doc, rm.ID, s.archiveKey)
+	if err != nil {
+		http.Error(w, "invalid document: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.store.CreateRoom(r.Context(), rm); err != nil {
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return
+	}
+	_ = s.store.AddRoomMember(r.Context(), store.RoomMember{RoomID: rm.ID, UserID: userID, Role: "dm", Joined: time.Now().UTC()})
+	if err := s.store.AppendEvents(r.Context(), rm.ID, events, nil, nil); err != nil {
+		s.logger.Error("import append failed", zap.String("room_id", rm.ID), zap.Error(err))
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ImportRoomResponse{RoomID: rm.ID, Events: len(events)})
+}
@@ -0,0 +1,42 @@
+// Package api 房间加入与游戏状态的同步
+//
+// POST /v1/rooms/{room_id}/join 写入成员行后向房间 Actor 派发 engine 的 join 命令，
+// 使成员表与游戏状态的玩家列表一致；engine 对已入座的玩家返回成功且不产生事件，
+// 因此重连、HTTP 加入后再经 WebSocket 加入都不会重复 player.joined 或成员行。
+//
+// [IN]  internal/room（RoomManager）
+// [OUT] api.go（joinRoom）
+// [POS] HTTP 接口层的幂等加入
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// joinGame seats a member in the room's game state. A join refused because the game
+// already started leaves the member spectating.
+func (s *Server) joinGame(ctx context.Context, roomID, userID string) {
+	ra, err := s.roomMgr.GetOrCreate(ctx, roomID)
+	if err != nil {
+		s.logger.Warn("join: room unavailable", zap.String("room_id", roomID), zap.Error(err))
+		return
+	}
+	payload, _ := json.Marshal(map[string]string{})
+	resp := ra.Dispatch(types.CommandEnvelope{
+		CommandID:      uuid.NewString(),
+		IdempotencyKey: uuid.NewString(),
+		RoomID:         roomID,
+		Type:           "join",
+		ActorUserID:    userID,
+		Payload:        payload,
+	})
+	if resp.Err != nil {
+		s.logger.Info("join: not seated", zap.String("room_id", roomID), zap.String("user_id", userID), zap.Error(resp.Err))
+	}
+}
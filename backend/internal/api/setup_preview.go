@@ -0,0 +1,67 @@
+// Package api 开局前配板预览（仅 DM、仅大厅）
+//
+// POST /v1/rooms/{room_id}/setup/preview 以可选种子 ({"seed": n}，缺省随机) 试生成角色分配，
+// 返回角色分布 (按类型与按角色计数，不含玩家对应关系) 与种子；不产生事件、不修改房间状态。
+// DM 重掷到满意的配板后，以 start_game 载荷 {"seed": "<n>"} 开局即得到同一组角色。
+//
+// [IN]  internal/engine（PreviewSetup）
+// [OUT] api.go（路由注册）
+// [POS] HTTP 接口层的只读配板试算
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+)
+
+type setupPreviewRequest struct {
+	Seed int64 `json:"seed"`
+}
+
+// previewSetup godoc
+// @Summary Preview a setup (DM only, lobby only)
+// @Description Roll role assignments without starting the game; returns role counts and the seed to start with
+// @Tags Rooms
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param room_id path string true "Room ID"
+// @Param body body setupPreviewRequest false "Optional seed (omit to roll a new one)"
+// @Success 200 {object} engine.SetupPreview
+// @Failure 400 {string} string "invalid request"
+// @Failure 403 {string} string "forbidden"
+// @Failure 409 {string} string "setup unavailable"
+// @Router /v1/rooms/{room_id}/setup/preview [post]
+func (s *Server) previewSetup(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRoomDM(w, r) {
+		return
+	}
+	ra, err := s.roomMgr.GetOrCreate(r.Context(), chi.URLParam(r, "room_id"))
+	if err != nil {
+		http.Error(w, "room error", http.StatusInternalServerError)
+		return
+	}
+	serveSetupPreview(w, r, ra.GetState())
+}
+
+// serveSetupPreview writes the preview for state; access is checked by the caller.
+func serveSetupPreview(w http.ResponseWriter, r *http.Request, state engine.State) {
+	var req setupPreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	preview, err := engine.PreviewSetup(state, req.Seed)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(preview)
+}
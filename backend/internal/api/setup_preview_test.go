@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+)
+
+func previewRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/v1/rooms/room-1/setup/preview", strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("room_id", "room-1")
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestSetupPreviewReturnsValidCountsWithoutMutatingState(t *testing.T) {
+	state := engine.NewState("room-1")
+	state.Players["dm"] = engine.Player{UserID: "dm", IsDM: true}
+	for i := 1; i <= 7; i++ {
+		uid := fmt.Sprintf("p%d", i)
+		state.Players[uid] = engine.Player{UserID: uid, SeatNumber: i, Alive: true}
+	}
+	before, _ := engine.MarshalState(state)
+
+	rec := httptest.NewRecorder()
+	serveSetupPreview(rec, previewRequest(`{"seed": 42}`), state)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var preview engine.SetupPreview
+	if err := json.Unmarshal(rec.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	dist := game.GetDistribution(7)
+	outsiders := dist.Outsiders
+	if preview.BaronModified {
+		outsiders += 2
+	}
+	c := preview.Counts
+	if preview.Seed != 42 || preview.PlayerCount != 7 || c["demon"] != dist.Demons || c["minion"] != dist.Minions ||
+		c["outsider"] != outsiders || c["townsfolk"] != 7-dist.Demons-dist.Minions-outsiders {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+	if after, _ := engine.MarshalState(state); after != before {
+		t.Fatal("preview must not mutate room state")
+	}
+
+	again := httptest.NewRecorder()
+	serveSetupPreview(again, previewRequest(`{"seed": 42}`), state)
+	var repeat engine.SetupPreview
+	_ = json.Unmarshal(again.Body.Bytes(), &repeat)
+	if fmt.Sprint(repeat.Roles) != fmt.Sprint(preview.Roles) {
+		t.Fatalf("the same seed must preview the same roles: %v vs %v", repeat.Roles, preview.Roles)
+	}
+}
+
+func TestSetupPreviewRejectedOutsideLobby(t *testing.T) {
+	state := engine.NewState("room-1")
+	state.Phase = engine.PhaseDay
+
+	rec := httptest.NewRecorder()
+	serveSetupPreview(rec, previewRequest(""), state)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 outside the lobby, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,103 @@
+// Package api 说书人笔记 (仅 DM)
+//
+// POST /v1/rooms/{room_id}/notes 以 {"body"} 保存一条自由文本笔记 (下一步毒谁、怀疑对象等)；
+// GET 同路径按时间顺序返回该房间全部笔记。笔记存于 storyteller_notes 表，不是游戏事件：
+// 不进事件流、不参与回放、不投影给玩家。非 DM 成员由 requireRoomDM 拒绝 (403)，未配置存储时 503。
+//
+// [IN]  internal/store（storyteller_notes）、agent_runs.go（requireRoomDM）
+// [OUT] api.go（路由注册）
+// [POS] HTTP 接口层的 DM 私有草稿
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// maxStorytellerNoteBytes caps a single note body.
+const maxStorytellerNoteBytes = 4000
+
+// NotesStore persists storyteller notes (implemented by *store.Store).
+type NotesStore interface {
+	SaveStorytellerNote(ctx context.Context, n store.StorytellerNote) error
+	ListStorytellerNotes(ctx context.Context, roomID string) ([]store.StorytellerNote, error)
+}
+
+type storytellerNoteRequest struct {
+	Body string `json:"body"`
+}
+
+// storytellerNotes godoc
+// @Summary Add or list the room's storyteller notes (DM only)
+// @Description Private DM scratch notes; never part of the event stream or any player projection
+// @Tags Rooms
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param room_id path string true "Room ID"
+// @Param body body storytellerNoteRequest false "Note (POST only)"
+// @Success 200 {array} store.StorytellerNote
+// @Success 201 {object} store.StorytellerNote
+// @Failure 400 {string} string "invalid note"
+// @Failure 403 {string} string "forbidden"
+// @Failure 503 {string} string "notes unavailable"
+// @Router /v1/rooms/{room_id}/notes [post]
+func (s *Server) storytellerNotes(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRoomDM(w, r) {
+		return
+	}
+	s.serveStorytellerNotes(w, r)
+}
+
+// serveStorytellerNotes handles both methods; access is checked by the caller.
+func (s *Server) serveStorytellerNotes(w http.ResponseWriter, r *http.Request) {
+	if s.notes == nil {
+		http.Error(w, "notes unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	roomID := chi.URLParam(r, "room_id")
+	if r.Method == http.MethodPost {
+		s.addStorytellerNote(w, r, roomID)
+		return
+	}
+	notes, err := s.notes.ListStorytellerNotes(r.Context(), roomID)
+	if err != nil {
+		s.logger.Error("list storyteller notes failed", zap.Error(err))
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notes)
+}
+
+func (s *Server) addStorytellerNote(w http.ResponseWriter, r *http.Request, roomID string) {
+	var req storytellerNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" || len(body) > maxStorytellerNoteBytes {
+		http.Error(w, "invalid note", http.StatusBadRequest)
+		return
+	}
+	userID, _ := r.Context().Value(userIDKey).(string)
+	note := store.StorytellerNote{ID: uuid.NewString(), RoomID: roomID, AuthorID: userID, Body: body, CreatedAt: time.Now().UTC()}
+	if err := s.notes.SaveStorytellerNote(r.Context(), note); err != nil {
+		s.logger.Error("save storyteller note failed", zap.Error(err))
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(note)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+type memNotes struct {
+	notes []store.StorytellerNote
+}
+
+func (m *memNotes) SaveStorytellerNote(_ context.Context, n store.StorytellerNote) error {
+	m.notes = append(m.notes, n)
+	return nil
+}
+
+func (m *memNotes) ListStorytellerNotes(_ context.Context, roomID string) ([]store.StorytellerNote, error) {
+	res := []store.StorytellerNote{}
+	for _, n := range m.notes {
+		if n.RoomID == roomID {
+			res = append(res, n)
+		}
+	}
+	return res, nil
+}
+
+// memMembers maps user IDs to their role in every room.
+type memMembers map[string]string
+
+func (m memMembers) IsMember(_ context.Context, _, userID string) (bool, string, error) {
+	role, ok := m[userID]
+	return ok, role, nil
+}
+
+func notesRequest(method, userID, body string) *http.Request {
+	req := httptest.NewRequest(method, "/v1/rooms/room-1/notes", strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("room_id", "room-1")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(context.WithValue(ctx, userIDKey, userID))
+}
+
+func TestStorytellerNotesRetrievableByDM(t *testing.T) {
+	notes := &memNotes{}
+	s := &Server{notes: notes, logger: zap.NewNop()}
+
+	rec := httptest.NewRecorder()
+	s.serveStorytellerNotes(rec, notesRequest(http.MethodPost, "dm-1", `{"body":"poison seat 3 tonight"}`))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	s.serveStorytellerNotes(rec, notesRequest(http.MethodGet, "dm-1", ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var got []store.StorytellerNote
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got) != 1 || got[0].Body != "poison seat 3 tonight" || got[0].AuthorID != "dm-1" || got[0].RoomID != "room-1" {
+		t.Fatalf("unexpected notes %+v", got)
+	}
+
+	rec = httptest.NewRecorder()
+	s.serveStorytellerNotes(rec, notesRequest(http.MethodPost, "dm-1", `{"body":"   "}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an empty note, got %d", rec.Code)
+	}
+}
+
+func TestStorytellerNotesForbiddenToPlayers(t *testing.T) {
+	notes := &memNotes{notes: []store.StorytellerNote{{ID: "n1", RoomID: "room-1", AuthorID: "dm-1", Body: "the imp is seat 5"}}}
+	s := &Server{notes: notes, members: memMembers{"dm-1": "dm", "p1": "player"}, logger: zap.NewNop()}
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		for _, userID := range []string{"p1", "stranger"} {
+			rec := httptest.NewRecorder()
+			s.storytellerNotes(rec, notesRequest(method, userID, `{"body":"sneaky"}`))
+			if rec.Code != http.StatusForbidden {
+				t.Fatalf("%s by %s: expected 403, got %d", method, userID, rec.Code)
+			}
+			if strings.Contains(rec.Body.String(), "imp") {
+				t.Fatalf("%s by %s: response leaked a note: %s", method, userID, rec.Body.String())
+			}
+		}
+	}
+	if len(notes.notes) != 1 {
+		t.Fatalf("a player POST must not store a note, have %d", len(notes.notes))
+	}
+
+	rec := httptest.NewRecorder()
+	s.storytellerNotes(rec, notesRequest(http.MethodGet, "dm-1", ""))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "imp") {
+		t.Fatalf("expected the DM to read the note, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
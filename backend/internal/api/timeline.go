@@ -0,0 +1,69 @@
+// Package api 旁观者公开时间线
+//
+// GET /v1/rooms/{room_id}/timeline 返回按 seq 排序的公开事件摘要 (类型、行动者名、摘要、时间)，
+// 由 projection.Timeline 以旁观者视角生成，与请求者身份无关，DM 与玩家看到相同内容。
+// 目前房间没有公开标记，仍要求房间成员身份。
+//
+// [IN]  internal/projection（Timeline）
+// [IN]  internal/store（LoadEventsUpTo、StoredEvent）
+// [OUT] api.go（路由注册）
+// [POS] HTTP 接口层的观战视图
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/projection"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// fetchTimeline godoc
+// @Summary Public room timeline
+// @Description Spectator-safe chronological feed (chat, phases, nominations, executions, deaths) built with the public projection
+// @Tags Events
+// @Security BearerAuth
+// @Produce json
+// @Param room_id path string true "Room ID"
+// @Success 200 {array} projection.TimelineEntry
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "db error"
+// @Router /v1/rooms/{room_id}/timeline [get]
+func (s *Server) fetchTimeline(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+	roomID := chi.URLParam(r, "room_id")
+	if ok, _, _ := s.store.IsMember(r.Context(), roomID, userID); !ok {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	stored, err := s.store.LoadEventsUpTo(r.Context(), roomID, 0)
+	if err != nil {
+		s.logger.Error("timeline load events failed", zap.String("room_id", roomID), zap.Error(err))
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(projection.Timeline(storedToEvents(stored)))
+}
+
+// storedToEvents converts stored rows to the events projection works on.
+func storedToEvents(stored []store.StoredEvent) []types.Event {
+	events := make([]types.Event, 0, len(stored))
+	for _, e := range stored {
+		events = append(events, types.Event{
+			RoomID:            e.RoomID,
+			Seq:               e.Seq,
+			EventID:           e.EventID,
+			EventType:         e.EventType,
+			ActorUserID:       e.ActorUserID,
+			Payload:           json.RawMessage(e.PayloadJSON),
+			ServerTimestampMs: e.ServerTime.UnixMilli(),
+		})
+	}
+	return events
+}
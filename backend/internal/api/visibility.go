@@ -0,0 +1,83 @@
+// Package api 可见性矩阵诊断（仅 DM）
+//
+// GET /v1/rooms/{room_id}/visibility 对整条事件流 (或 ?seq= 指定的单个事件) 按当前房间状态
+// 调用 projection.VisibilityMatrix，列出 DM、每位入座玩家与旁观者能否看到每个事件，
+// 用于排查投影规则导致的信息泄露。只读，不产生事件。
+//
+// [IN]  internal/projection（VisibilityMatrix）
+// [IN]  internal/store（LoadEventsUpTo、LoadEventsAfter）
+// [OUT] api.go（路由注册）
+// [POS] HTTP 接口层的 DM 投影诊断
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/projection"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// fetchVisibility godoc
+// @Summary Visibility matrix (DM only)
+// @Description For each event (or the one at ?seq=), whether the DM, each seated player and a spectator can see it under the current projection rules
+// @Tags Events
+// @Security BearerAuth
+// @Produce json
+// @Param room_id path string true "Room ID"
+// @Param seq query int false "Single event seq (default: whole stream)"
+// @Success 200 {array} projection.VisibilityRow
+// @Failure 400 {string} string "invalid seq"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "event not found"
+// @Failure 500 {string} string "db error"
+// @Router /v1/rooms/{room_id}/visibility [get]
+func (s *Server) fetchVisibility(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRoomDM(w, r) {
+		return
+	}
+	roomID := chi.URLParam(r, "room_id")
+	stored, ok := s.loadVisibilityEvents(w, r, roomID)
+	if !ok {
+		return
+	}
+	ra, err := s.roomMgr.GetOrCreate(r.Context(), roomID)
+	if err != nil {
+		http.Error(w, "room error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(projection.VisibilityMatrix(storedToEvents(stored), ra.GetState()))
+}
+
+// loadVisibilityEvents loads the whole stream, or the single event named by ?seq=;
+// on failure it writes the error response and returns false.
+func (s *Server) loadVisibilityEvents(w http.ResponseWriter, r *http.Request, roomID string) ([]store.StoredEvent, bool) {
+	var stored []store.StoredEvent
+	var err error
+	seqParam := r.URL.Query().Get("seq")
+	seq, parseErr := strconv.ParseInt(seqParam, 10, 64)
+	switch {
+	case seqParam == "":
+		stored, err = s.store.LoadEventsUpTo(r.Context(), roomID, 0)
+	case parseErr != nil || seq < 1:
+		http.Error(w, "invalid seq", http.StatusBadRequest)
+		return nil, false
+	default:
+		stored, err = s.store.LoadEventsAfter(r.Context(), roomID, seq-1, 1)
+	}
+	if err != nil {
+		s.logger.Error("visibility load events failed", zap.String("room_id", roomID), zap.Error(err))
+		http.Error(w, "db error", http.StatusInternalServerError)
+		return nil, false
+	}
+	if seqParam != "" && (len(stored) == 0 || stored[0].Seq != seq) {
+		http.Error(w, "event not found", http.StatusNotFound)
+		return nil, false
+	}
+	return stored, true
+}
@@ -0,0 +1,218 @@
+// Package archive 房间归档：可移植 JSON 导出与重新导入
+//
+// 导出文档包含房间元数据、按 seq 排序的事件流与终局状态。DM 视角为完整事件流
+// (含撤回事件，可原样重放)；玩家视角先去掉被撤回事件，再按 projection 过滤与脱敏，仅供查看。
+// DM 视角带哈希链并以服务端密钥签名；导入仅接受通过完整性校验 (integrity.go) 的 DM 视角文档：
+// 事件换新 event_id (event.retracted 的引用同步改写)，
+// seq 从 1 连续编号并为新房间重建存储哈希链，写入新房间后重放得到与导出一致的终局状态。
+//
+// [IN]  internal/engine（Replay、State）
+// [IN]  internal/projection（Project、ProjectedState、WithoutRetracted）
+// [IN]  internal/store（Room、StoredEvent、ChainEvents）
+// [IN]  internal/types（Event、Viewer）
+// [OUT] api（GET /v1/rooms/{room_id}/export、POST /v1/rooms/import）
+// [POS] 事件溯源之上的归档格式层
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/projection"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// FormatVersion is bumped on incompatible document changes.
+const FormatVersion = 1
+
+// Export views: the DM view is complete and re-importable, the player view is not.
+const (
+	ViewDM     = "dm"
+	ViewPlayer = "player"
+)
+
+// ErrNotImportable means the document cannot be replayed into a new room.
+var ErrNotImportable = errors.New("archive: document not importable")
+
+// Document is the portable export of one room.
+type Document struct {
+	Version    int          `json:"version"`
+	View       string       `json:"view"`
+	ExportedAt time.Time    `json:"exported_at"`
+	Room       RoomMeta     `json:"room"`
+	Events     []Event      `json:"events"`
+	FinalState engine.State `json:"final_state"`
+	// Signature is the HMAC of the DM view's chain head (integrity.go); empty in the player view
+	Signature string `json:"signature,omitempty"`
+}
+
+// RoomMeta is the exported room row.
+type RoomMeta struct {
+	ID        string    `json:"id"`
+	CreatedBy string    `json:"created_by"`
+	DMUserID  string    `json:"dm_user_id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Event is one exported event; Payload is sanitized in the player view.
+type Event struct {
+	Seq              int64           `json:"seq"`
+	EventID          string          `json:"event_id"`
+	EventType        string          `json:"event_type"`
+	ActorUserID      string          `json:"actor_user_id"`
+	CausationCommand string          `json:"causation_command_id,omitempty"`
+	CorrelationID    string          `json:"correlation_id,omitempty"`
+	Payload          json.RawMessage `json:"payload"`
+	ServerTime       time.Time       `json:"server_ts"`
+	ChainHash        string          `json:"chain_hash,omitempty"`
+}
+
+// Export builds the document for viewer: DMs get every event, signed with key, others
+// the projected stream.
+func Export(room store.Room, stored []store.StoredEvent, viewer types.Viewer, now time.Time, key []byte) Document {
+	final := engine.Replay(room.ID, toPayloads(stored))
+	doc := Document{
+		Version:    FormatVersion,
+		View:       ViewDM,
+		ExportedAt: now,
+		Room: RoomMeta{
+			ID:        room.ID,
+			CreatedBy: room.CreatedBy,
+			DMUserID:  room.DMUserID,
+			Status:    room.Status,
+			CreatedAt: room.CreatedAt,
+		},
+		Events:     make([]Event, 0, len(stored)),
+		FinalState: projection.ProjectedState(final, viewer),
+	}
+	if viewer.IsDM {
+		for _, e := range stored {
+			doc.Events = append(doc.Events, fromStored(e))
+		}
+		doc.Signature = sealChain(room.ID, doc.Events, key)
+		return doc
+	}
+	doc.View = ViewPlayer
+	for _, ev := range projection.WithoutRetracted(toTypesEvents(stored)) {
+		pe := projection.ProjectEvent(ev, final, viewer)
+		if pe == nil {
+			continue
+		}
+		doc.Events = append(doc.Events, Event{
+			Seq:         ev.Seq,
+			EventID:     ev.EventID,
+			EventType:   ev.EventType,
+			ActorUserID: pe.ActorUserID,
+			Payload:     pe.Data,
+			ServerTime:  time.UnixMilli(ev.ServerTimestampMs).UTC(),
+		})
+	}
+	return doc
+}
+
+// Import verifies a DM-view document against key (see verifyEvents) and rewrites its
+// events for roomID: fresh event IDs (retraction references follow) and contiguous
+// seqs from 1. It returns the events and their replayed state.
+func Import(doc Document, roomID string, key []byte) ([]store.StoredEvent, engine.State, error) {
+	if doc.Version != FormatVersion {
+		return nil, engine.State{}, fmt.Errorf("archive.Import: version %d: %w", doc.Version, ErrNotImportable)
+	}
+	if doc.View != ViewDM {
+		return nil, engine.State{}, fmt.Errorf("archive.Import: %s view: %w", doc.View, ErrNotImportable)
+	}
+	if err := verifyEvents(doc, key); err != nil {
+		return nil, engine.State{}, fmt.Errorf("archive.Import: %w", err)
+	}
+	newIDs := make(map[string]string, len(doc.Events))
+	for _, e := range doc.Events {
+		newIDs[e.EventID] = uuid.NewString()
+	}
+	events := make([]store.StoredEvent, 0, len(doc.Events))
+	for i, e := range doc.Events {
+		payload, err := remapRetraction(e, newIDs)
+		if err != nil {
+			return nil, engine.State{}, fmt.Errorf("archive.Import: event %d: %w", e.Seq, err)
+		}
+		events = append(events, store.StoredEvent{
+			RoomID:           roomID,
+			Seq:              int64(i + 1),
+			EventID:          newIDs[e.EventID],
+			EventType:        e.EventType,
+			ActorUserID:      e.ActorUserID,
+			CausationCommand: e.CausationCommand,
+			PayloadJSON:      payload,
+			ServerTime:       e.ServerTime,
+			CorrelationID:    e.CorrelationID,
+		})
+	}
+	store.ChainEvents(events, "")
+	return events, engine.Replay(roomID, toPayloads(events)), nil
+}
+
+// remapRetraction points an event.retracted payload at the retracted event's new ID.
+func remapRetraction(e Event, newIDs map[string]string) (string, error) {
+	if e.EventType != "event.retracted" {
+		return string(e.Payload), nil
+	}
+	var p map[string]string
+	if err := json.Unmarshal(e.Payload, &p); err != nil {
+		return "", err
+	}
+	if id, ok := newIDs[p["event_id"]]; ok {
+		p["event_id"] = id
+	}
+	b, err := json.Marshal(p)
+	return string(b), err
+}
+
+func fromStored(e store.StoredEvent) Event {
+	payload := json.RawMessage(e.PayloadJSON)
+	if len(payload) == 0 {
+		payload = json.RawMessage(`{}`)
+	}
+	return Event{
+		Seq:              e.Seq,
+		EventID:          e.EventID,
+		EventType:        e.EventType,
+		ActorUserID:      e.ActorUserID,
+		CausationCommand: e.CausationCommand,
+		CorrelationID:    e.CorrelationID,
+		Payload:          payload,
+		ServerTime:       e.ServerTime.UTC(),
+	}
+}
+
+func toPayloads(stored []store.StoredEvent) []engine.EventPayload {
+	payloads := make([]engine.EventPayload, 0, len(stored))
+	for _, e := range stored {
+		var p map[string]string
+		_ = json.Unmarshal([]byte(e.PayloadJSON), &p)
+		payloads = append(payloads, engine.EventPayload{Seq: e.Seq, EventID: e.EventID, Type: e.EventType, Actor: e.ActorUserID, Payload: p})
+	}
+	return payloads
+}
+
+func toTypesEvents(stored []store.StoredEvent) []types.Event {
+	events := make([]types.Event, 0, len(stored))
+	for _, e := range stored {
+		events = append(events, types.Event{
+			RoomID:            e.RoomID,
+			Seq:               e.Seq,
+			EventID:           e.EventID,
+			EventType:         e.EventType,
+			ActorUserID:       e.ActorUserID,
+			CausationCommand:  e.CausationCommand,
+			Payload:           json.RawMessage(e.PayloadJSON),
+			ServerTimestampMs: e.ServerTime.UnixMilli(),
+			CorrelationID:     e.CorrelationID,
+		})
+	}
+	return events
+}
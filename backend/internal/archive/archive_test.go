@@ -0,0 +1,125 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// recordGame runs commands through the engine and stores their events the way the
+// room actor does, rebuilding state by full replay so retractions take effect.
+func recordGame(t *testing.T, roomID string, cmds []types.CommandEnvelope) []store.StoredEvent {
+	t.Helper()
+	t.Cleanup(game.SetRandomizer(func(int) (int, error) { return 0, nil }))
+	var stored []store.StoredEvent
+	state := engine.NewState(roomID)
+	for i, cmd := range cmds {
+		cmd.CommandID, cmd.RoomID = fmt.Sprintf("cmd-%d", i), roomID
+		events, _, err := engine.HandleCommand(state, cmd)
+		if err != nil {
+			t.Fatalf("%s by %s rejected: %v", cmd.Type, cmd.ActorUserID, err)
+		}
+		for _, e := range events {
+			stored = append(stored, store.StoredEvent{
+				RoomID: roomID, Seq: int64(len(stored) + 1), EventID: e.EventID, EventType: e.EventType,
+				ActorUserID: e.ActorUserID, CausationCommand: cmd.CommandID, PayloadJSON: string(e.Payload),
+				ServerTime: time.Unix(1700000000+int64(len(stored)), 0).UTC(), CorrelationID: cmd.CommandID,
+			})
+		}
+		state = engine.Replay(roomID, toPayloads(stored))
+	}
+	return stored
+}
+
+func command(actor, cmdType string, payload map[string]string) types.CommandEnvelope {
+	raw, _ := json.Marshal(payload)
+	return types.CommandEnvelope{ActorUserID: actor, Type: cmdType, Payload: raw}
+}
+
+func sampleGame(t *testing.T) []store.StoredEvent {
+	cmds := []types.CommandEnvelope{}
+	for i := 1; i <= 6; i++ {
+		cmds = append(cmds, command(fmt.Sprintf("p%d", i), "join", map[string]string{"name": fmt.Sprintf("P%d", i)}))
+	}
+	roles, _ := json.Marshal([]string{"washerwoman", "chef", "empath", "poisoner", "imp"})
+	cmds = append(cmds,
+		command("autodm", "undo_last_event", nil),
+		command("p1", "start_game", map[string]string{"custom_roles": string(roles)}),
+	)
+	return recordGame(t, "room-src", cmds)
+}
+
+func TestExportImportRoundTripReproducesFinalState(t *testing.T) {
+	stored := sampleGame(t)
+	rm := store.Room{ID: "room-src", CreatedBy: "p1", DMUserID: "p1", Status: "lobby", CreatedAt: time.Unix(1700000000, 0).UTC()}
+	doc := Export(rm, stored, types.Viewer{UserID: "p1", IsDM: true}, time.Now().UTC(), testKey)
+	if doc.FinalState.Phase != engine.PhaseFirstNight || len(doc.FinalState.Players) != 5 {
+		t.Fatalf("unexpected exported state: phase %s, %d players", doc.FinalState.Phase, len(doc.FinalState.Players))
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded Document
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	events, state, err := Import(decoded, "room-copy", testKey)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(events) != len(stored) {
+		t.Fatalf("expected %d imported events, got %d", len(stored), len(events))
+	}
+	for i, e := range events {
+		if e.RoomID != "room-copy" || e.Seq != int64(i+1) || e.EventID == stored[i].EventID {
+			t.Fatalf("event %d not rewritten for the new room: %+v", i, e)
+		}
+	}
+
+	// Room id and undo target follow the new room and Reduce stamps phase starts with
+	// the wall clock; everything else must match.
+	state.RoomID, state.LastEventID = doc.FinalState.RoomID, doc.FinalState.LastEventID
+	state.PhaseStartedAt = doc.FinalState.PhaseStartedAt
+	want, _ := engine.MarshalState(doc.FinalState)
+	got, _ := engine.MarshalState(state)
+	if got != want {
+		t.Fatalf("imported state differs from exported final state\nwant %s\ngot  %s", want, got)
+	}
+}
+
+func TestPlayerExportIsProjectedAndNotImportable(t *testing.T) {
+	stored := sampleGame(t)
+	rm := store.Room{ID: "room-src", CreatedBy: "p1", DMUserID: "p1"}
+	doc := Export(rm, stored, types.Viewer{UserID: "p2"}, time.Now().UTC(), testKey)
+	if doc.View != ViewPlayer {
+		t.Fatalf("expected player view, got %s", doc.View)
+	}
+	for _, e := range doc.Events {
+		if e.ActorUserID == "p6" {
+			t.Fatalf("player export kept the retracted join: %+v", e)
+		}
+		if e.EventType == "role.assigned" {
+			var p map[string]string
+			_ = json.Unmarshal(e.Payload, &p)
+			if p["user_id"] != "" && p["user_id"] != "p2" {
+				t.Fatalf("player export leaked another player's role: %s", e.Payload)
+			}
+		}
+	}
+	for uid, p := range doc.FinalState.Players {
+		if uid != "p2" && p.TrueRole != "" {
+			t.Fatalf("player export leaked %s's true role", uid)
+		}
+	}
+	if _, _, err := Import(doc, "room-copy", testKey); err == nil {
+		t.Fatalf("expected player view import to be rejected")
+	}
+}
@@ -0,0 +1,84 @@
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+var testKey = []byte("archive-test-key")
+
+func dmExport(t *testing.T) Document {
+	t.Helper()
+	rm := store.Room{ID: "room-src", CreatedBy: "p1", DMUserID: "p1"}
+	doc := Export(rm, sampleGame(t), types.Viewer{UserID: "p1", IsDM: true}, time.Now().UTC(), testKey)
+	raw, _ := json.Marshal(doc)
+	var decoded Document
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return decoded
+}
+
+func TestImportAcceptsCleanChainedExport(t *testing.T) {
+	doc := dmExport(t)
+	for _, e := range doc.Events {
+		if e.ChainHash == "" {
+			t.Fatalf("seq %d exported without chain hash", e.Seq)
+		}
+	}
+	if doc.Signature == "" {
+		t.Fatal("DM export not signed")
+	}
+	if _, _, err := Import(doc, "room-copy", testKey); err != nil {
+		t.Fatalf("clean import rejected: %v", err)
+	}
+}
+
+func TestImportRejectsTamperedStream(t *testing.T) {
+	cases := map[string]func(doc *Document){
+		"payload edited": func(doc *Document) {
+			doc.Events[2].Payload = json.RawMessage(`{"user_id":"p3","name":"Mallory"}`)
+		},
+		"event dropped": func(doc *Document) {
+			doc.Events = append(doc.Events[:3], doc.Events[4:]...)
+			for i := range doc.Events {
+				doc.Events[i].Seq = int64(i + 1)
+			}
+		},
+		"seq gap": func(doc *Document) {
+			doc.Events[3].Seq = 99
+		},
+		"causation missing": func(doc *Document) {
+			doc.Events[1].CausationCommand = ""
+		},
+		"hashes partly stripped": func(doc *Document) {
+			doc.Events[len(doc.Events)-1].ChainHash = ""
+		},
+		"hashes fully stripped": func(doc *Document) {
+			for i := range doc.Events {
+				doc.Events[i].ChainHash = ""
+			}
+		},
+		"signature missing": func(doc *Document) {
+			doc.Signature = ""
+		},
+		"chain rebuilt without the key": func(doc *Document) {
+			doc.Events[2].Payload = json.RawMessage(`{"user_id":"p3","name":"Mallory"}`)
+			doc.Signature = sealChain(doc.Room.ID, doc.Events, []byte("forged-key"))
+		},
+	}
+	for name, tamper := range cases {
+		t.Run(name, func(t *testing.T) {
+			doc := dmExport(t)
+			tamper(&doc)
+			if _, _, err := Import(doc, "room-copy", testKey); !errors.Is(err, ErrIntegrity) {
+				t.Fatalf("expected ErrIntegrity, got %v", err)
+			}
+		})
+	}
+}
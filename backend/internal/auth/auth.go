@@ -57,7 +57,7 @@ func (m *JWTManager) Parse(tokenStr string) (*Claims, error) {
 }
 
 func HashPassword(pw string) (string, error) {
-	b, err := bcrypt.GenerateFromPassword([]byte(pw), bcrypt.DefaultCost)
+	b, err := bcrypt.GenerateFromPassword([]byte(pw), hashCost)
 	if err != nil {
 		return "", err
 	}
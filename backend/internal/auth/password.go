@@ -0,0 +1,70 @@
+// Package auth 密码强度策略与哈希成本配置
+//
+// 注册时按 PasswordPolicy 校验最小长度与字符组成（至少一个字母和一个数字），
+// 并拒绝超过 bcrypt 上限 72 字节的密码 (bcrypt 会报错而非静默截断)；
+// bcrypt 成本可经 SetHashCost 调整（越高越抗暴力破解、登录越慢），已有哈希自带成本，调整不影响校验。
+//
+// [IN]  config（PASSWORD_MIN_LENGTH / PASSWORD_HASH_COST）
+// [OUT] api（register 校验）、cmd/server（启动时设置成本）
+// [POS] 认证基础设施的密码安全参数
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrWeakPassword is returned when a password fails the policy.
+var ErrWeakPassword = errors.New("password too weak")
+
+// ErrPasswordTooLong is returned for passwords bcrypt cannot hash.
+var ErrPasswordTooLong = errors.New("password too long")
+
+// DefaultPasswordMinLength is the minimum length when none is configured.
+const DefaultPasswordMinLength = 8
+
+// MaxPasswordBytes is bcrypt's input limit.
+const MaxPasswordBytes = 72
+
+// hashCost is the bcrypt cost HashPassword uses.
+var hashCost = bcrypt.DefaultCost
+
+// PasswordPolicy describes the minimum password requirements.
+type PasswordPolicy struct {
+	MinLength int
+}
+
+// Validate checks pw against the policy, wrapping ErrWeakPassword or ErrPasswordTooLong
+// with the reason.
+func (p PasswordPolicy) Validate(pw string) error {
+	if len(pw) > MaxPasswordBytes {
+		return fmt.Errorf("%w: must be at most %d bytes", ErrPasswordTooLong, MaxPasswordBytes)
+	}
+	minLen := p.MinLength
+	if minLen <= 0 {
+		minLen = DefaultPasswordMinLength
+	}
+	if len([]rune(pw)) < minLen {
+		return fmt.Errorf("%w: must be at least %d characters", ErrWeakPassword, minLen)
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range pw {
+		hasLetter = hasLetter || unicode.IsLetter(r)
+		hasDigit = hasDigit || unicode.IsDigit(r)
+	}
+	if !hasLetter || !hasDigit {
+		return fmt.Errorf("%w: must contain both letters and digits", ErrWeakPassword)
+	}
+	return nil
+}
+
+// SetHashCost sets the bcrypt cost for new hashes; out-of-range values restore the default.
+func SetHashCost(cost int) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	hashCost = cost
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicyRejectsWeakAndAcceptsStrong(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 8}
+	for _, weak := range []string{"", "abc123", "abcdefghij", "1234567890"} {
+		if err := policy.Validate(weak); !errors.Is(err, ErrWeakPassword) {
+			t.Errorf("expected %q to be rejected as weak, got %v", weak, err)
+		}
+	}
+	if err := policy.Validate("clocktower42"); err != nil {
+		t.Fatalf("expected a strong password to pass, got %v", err)
+	}
+}
+
+func TestPasswordPolicyRejectsPasswordsBcryptCannotHash(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 8}
+	if err := policy.Validate(strings.Repeat("a1", 36)); err != nil {
+		t.Fatalf("expected a 72-byte password to pass, got %v", err)
+	}
+	if err := policy.Validate(strings.Repeat("a1", 36) + "x"); !errors.Is(err, ErrPasswordTooLong) {
+		t.Fatalf("expected a 73-byte password to be rejected, got %v", err)
+	}
+	// 25 characters, but 73 bytes
+	if err := policy.Validate(strings.Repeat("钟", 24) + "1"); !errors.Is(err, ErrPasswordTooLong) {
+		t.Fatalf("expected the byte length to count, got %v", err)
+	}
+}
+
+func TestHashCostIsConfigurable(t *testing.T) {
+	SetHashCost(5)
+	defer SetHashCost(0)
+
+	hash, err := HashPassword("clocktower42")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	if hash[4:6] != "05" {
+		t.Fatalf("expected bcrypt cost 05 in %q", hash)
+	}
+	if err := CheckPassword(hash, "clocktower42"); err != nil {
+		t.Fatalf("expected the password to verify: %v", err)
+	}
+}
@@ -27,6 +27,7 @@ const (
 	PersonalityCautious   Personality = "cautious"   // Rarely nominates, careful voter
 	PersonalityRandom     Personality = "random"     // 50/50 on most decisions
 	PersonalitySmart      Personality = "smart"      // Uses role info to make better decisions
+	// PersonalityDeceptive (bot_deceptive.go): bluffs a good role and protects its demon when evil
 )
 
 // BotConfig configures a bot player.
@@ -35,6 +36,9 @@ type BotConfig struct {
 	Name        string
 	Personality Personality
 	Logger      *slog.Logger
+
+	// LLM 发言 (bot_chat.go)：Model 为空时使用模板发言
+	Chat ChatConfig
 }
 
 // Bot represents a bot player in a game.
@@ -62,6 +66,21 @@ type Bot struct {
 	// Current nomination context (stored on nomination.created, used on defense.ended)
 	lastNominee   string
 	lastVoteOrder []string // sequential user_id order from vote_order seats
+
+	// LLM 发言 (bot_chat.go)：chatsToday 每个白天重置，lastChat 为最近一条他人公开发言
+	chat       ChatConfig
+	chatsToday int
+	lastChat   string
+
+	// 事件订阅与监督 (supervisor.go)：conn/stop 由 connMu 保护；lastSeq/seen 仅由监督协程读写，用于补齐与去重
+	connMu  sync.Mutex
+	conn    *botConn
+	stop    context.CancelFunc
+	lastSeq int64
+	seen    map[int64]bool
+
+	// 欺骗型伪装 (bot_deceptive.go)：邪恶时固定声称的善良角色 ID
+	claim string
 }
 
 // CommandDispatcher sends commands to the game engine.
@@ -83,6 +102,7 @@ func NewBot(cfg BotConfig) *Bot {
 		personality: cfg.Personality,
 		logger:      cfg.Logger,
 		alive:       true,
+		chat:        cfg.Chat,
 	}
 }
 
@@ -131,6 +151,7 @@ func (b *Bot) OnEvent(ctx context.Context, ev types.Event) {
 		b.phase = "day"
 		b.dayCount++
 		b.hasVoted = false
+		b.chatsToday = 0
 		// Maybe chat after a delay
 		go b.maybeChatAfterDelay(ctx)
 
@@ -139,6 +160,15 @@ func (b *Bot) OnEvent(ctx context.Context, ev types.Event) {
 		// Maybe nominate after a delay
 		go b.maybeNominateAfterDelay(ctx)
 
+	case "team.recognition":
+		b.onTeamRecognition(payload)
+
+	case "demon.changed":
+		b.onDemonChanged(payload)
+
+	case "public.chat":
+		b.onPublicChat(ctx, ev.ActorUserID, payload)
+
 	case "nomination.created":
 		// Store nominee for later voting (defense phase must end first)
 		b.lastNominee = payload["nominee"]
@@ -220,33 +250,7 @@ func (b *Bot) maybeChatAfterDelay(ctx context.Context) {
 	case <-ctx.Done():
 		return
 	}
-
-	b.mu.RLock()
-	alive := b.alive
-	dispatcher := b.dispatcher
-	roomID := b.roomID
-	b.mu.RUnlock()
-
-	if !alive || dispatcher == nil {
-		return
-	}
-
-	msg := b.generateChat()
-	if msg == "" {
-		return
-	}
-
-	payload, _ := json.Marshal(map[string]string{
-		"message": msg,
-		"from":    b.name,
-	})
-	_ = dispatcher.DispatchAsync(types.CommandEnvelope{
-		CommandID:   fmt.Sprintf("bot-%s-%d", b.userID, time.Now().UnixMilli()),
-		RoomID:      roomID,
-		Type:        "public_chat",
-		ActorUserID: b.userID,
-		Payload:     payload,
-	})
+	b.postChat(ctx)
 }
 
 func (b *Bot) maybeNominateAfterDelay(ctx context.Context) {
@@ -358,6 +362,8 @@ func (b *Bot) decideVote(personality Personality, team, nominee string) bool {
 			return randomChance(60)
 		}
 		return randomChance(45)
+	case PersonalityDeceptive:
+		return b.decideDeceptiveVote(team, nominee)
 	default:
 		return randomChance(50)
 	}
@@ -370,6 +376,9 @@ func (b *Bot) generateChat() string {
 	dayCount := b.dayCount
 	b.mu.RUnlock()
 
+	if personality == PersonalityDeceptive && team == "evil" {
+		return b.bluffChat(dayCount)
+	}
 	if dayCount <= 1 {
 		// First day: introductions
 		msgs := []string{
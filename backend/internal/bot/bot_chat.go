@@ -0,0 +1,163 @@
+// Package bot Bot 的 LLM 发言与成本控制
+//
+// BotConfig.Chat 配置 ChatModel 时，Bot 在白天开始与他人公开发言后 (按性格概率) 用 LLM 生成一句
+// 角色内的短发言。提示词只含 Bot 自己知道的信息 (被告知的角色、阵营、恶魔的伪装角色与最近的公开发言)，
+// 不含真实角色 (酒鬼不知道自己是酒鬼) 等隐藏信息。成本控制：每个白天最多 MaxPerDay 条 (先占额度再调用)，
+// 输出截断到 MaxChars 字符；模型走 llm.Router 的 bot_chat 任务，受 AUTODM_LLM_MAX_TOKENS 的 bot_chat 上限约束。
+// 未配置模型时使用模板发言 (generateChat)，LLM 失败时回退模板。
+//
+// [IN]  ChatModel（cmd/server 以 AutoDM.BotChatModel 注入）
+// [OUT] bot.go（白天与公开发言时触发）
+// [POS] Bot 个体的发言生成与限额
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+const (
+	defaultChatsPerDay  = 3
+	defaultChatMaxChars = 200
+)
+
+// ChatModel generates a bot's public message.
+type ChatModel interface {
+	BotChat(ctx context.Context, systemPrompt, userMessage string) (string, error)
+}
+
+// ChatConfig enables LLM chat; a nil Model keeps the template messages.
+type ChatConfig struct {
+	Model     ChatModel
+	MaxPerDay int // messages per day, 0 = defaultChatsPerDay
+	MaxChars  int // runes per message, 0 = defaultChatMaxChars
+}
+
+func (c ChatConfig) maxPerDay() int {
+	if c.MaxPerDay > 0 {
+		return c.MaxPerDay
+	}
+	return defaultChatsPerDay
+}
+
+func (c ChatConfig) maxChars() int {
+	if c.MaxChars > 0 {
+		return c.MaxChars
+	}
+	return defaultChatMaxChars
+}
+
+// onPublicChat remembers the latest public message and may reply to it. Caller holds b.mu.
+func (b *Bot) onPublicChat(ctx context.Context, actor string, payload map[string]string) {
+	if actor == b.userID {
+		return
+	}
+	b.lastChat = payload["message"]
+	if b.chat.Model == nil || b.phase != "day" || !b.alive {
+		return
+	}
+	if randomChance(chatReplyChance(b.personality)) {
+		go b.maybeChatAfterDelay(ctx)
+	}
+}
+
+// chatReplyChance is the percent chance a bot answers someone else's message.
+func chatReplyChance(p Personality) int {
+	switch p {
+	case PersonalityAggressive:
+		return 40
+	case PersonalityCautious:
+		return 10
+	default:
+		return 25
+	}
+}
+
+// postChat sends one public message if the bot is alive and has chat budget left today.
+func (b *Bot) postChat(ctx context.Context) {
+	b.mu.RLock()
+	alive := b.alive
+	dispatcher := b.dispatcher
+	roomID := b.roomID
+	b.mu.RUnlock()
+
+	if !alive || dispatcher == nil {
+		return
+	}
+
+	msg := b.nextChat(ctx)
+	if msg == "" {
+		return
+	}
+
+	payload, _ := json.Marshal(map[string]string{
+		"message": msg,
+		"from":    b.name,
+	})
+	_ = dispatcher.DispatchAsync(types.CommandEnvelope{
+		CommandID:   fmt.Sprintf("bot-%s-%d", b.userID, time.Now().UnixNano()),
+		RoomID:      roomID,
+		Type:        "public_chat",
+		ActorUserID: b.userID,
+		Payload:     payload,
+	})
+}
+
+// nextChat returns the next message: LLM-written within today's cap, otherwise a template.
+func (b *Bot) nextChat(ctx context.Context) string {
+	b.mu.Lock()
+	chat := b.chat
+	if chat.Model == nil {
+		b.mu.Unlock()
+		return b.generateChat()
+	}
+	if b.chatsToday >= chat.maxPerDay() {
+		b.mu.Unlock()
+		return ""
+	}
+	b.chatsToday++
+	system, user := b.chatPrompt()
+	b.mu.Unlock()
+
+	msg, err := chat.Model.BotChat(ctx, system, user)
+	msg = strings.TrimSpace(msg)
+	if err != nil || msg == "" {
+		b.logger.Warn("bot chat generation failed, using template", "bot", b.name, "error", err)
+		return b.generateChat()
+	}
+	if runes := []rune(msg); len(runes) > chat.maxChars() {
+		msg = string(runes[:chat.maxChars()]) + "…"
+	}
+	return msg
+}
+
+// chatPrompt builds the prompt from what the bot itself knows. Caller holds b.mu.
+func (b *Bot) chatPrompt() (system, user string) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "You are %s, a player in a game of Blood on the Clocktower, speaking in the public day discussion. ", b.name)
+	fmt.Fprintf(&sb, "Your personality is %s. Write ONE short in-character message of at most two sentences. ", b.personality)
+	sb.WriteString("Never mention being a bot or an AI and never quote the storyteller's private messages word for word. ")
+	if b.team == "evil" {
+		sb.WriteString("You are secretly on the evil team: never admit it or name your teammates. ")
+		if b.personality == PersonalityDeceptive {
+			fmt.Fprintf(&sb, "Claim to be the %s and stay consistent with that claim. ", b.claimedRole())
+		} else if len(b.bluffs) > 0 {
+			fmt.Fprintf(&sb, "If you claim a role, claim one of: %s. ", strings.Join(b.bluffs, ", "))
+		} else {
+			sb.WriteString("If you claim a role, claim a plausible good role. ")
+		}
+	} else if b.role != "" {
+		fmt.Fprintf(&sb, "You believe your role is %s; decide for yourself whether to reveal it. ", b.role)
+	}
+
+	user = fmt.Sprintf("Day %d has just begun. Say something to the town.", b.dayCount)
+	if b.lastChat != "" {
+		user = fmt.Sprintf("Day %d. The last thing said in public was: %q. Respond to the town.", b.dayCount, b.lastChat)
+	}
+	return strings.TrimSpace(sb.String()), user
+}
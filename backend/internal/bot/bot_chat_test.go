@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+type stubChatModel struct {
+	calls   int
+	systems []string
+}
+
+func (m *stubChatModel) BotChat(_ context.Context, systemPrompt, _ string) (string, error) {
+	m.calls++
+	m.systems = append(m.systems, systemPrompt)
+	return "I trust nobody today.", nil
+}
+
+func dayEvent(t *testing.T, typ string, payload map[string]string) types.Event {
+	t.Helper()
+	raw, _ := json.Marshal(payload)
+	return types.Event{RoomID: "room-1", EventType: typ, Payload: raw}
+}
+
+func TestBotLLMChatCappedPerDay(t *testing.T) {
+	model := &stubChatModel{}
+	d := &recordingDispatcher{}
+	b := NewBot(BotConfig{UserID: "bot-1", Name: "Alice", Chat: ChatConfig{Model: model, MaxPerDay: 2}})
+	b.SetDispatcher(d, "room-1")
+
+	// A cancelled context stops the delayed goroutines OnEvent starts.
+	done, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.OnEvent(done, dayEvent(t, "role.assigned", map[string]string{"user_id": "bot-1", "role": "chef", "true_role": "drunk", "team": "good"}))
+	b.OnEvent(done, dayEvent(t, "phase.day", nil))
+
+	for i := 0; i < 5; i++ {
+		b.postChat(context.Background())
+	}
+	if len(d.cmds) != 2 || model.calls != 2 {
+		t.Fatalf("expected 2 messages on day 1, got %d (model calls %d)", len(d.cmds), model.calls)
+	}
+	for _, cmd := range d.cmds {
+		if cmd.Type != "public_chat" || !strings.Contains(string(cmd.Payload), "trust nobody") {
+			t.Fatalf("unexpected chat command %+v", cmd)
+		}
+	}
+	if !strings.Contains(model.systems[0], "chef") || strings.Contains(model.systems[0], "drunk") {
+		t.Fatalf("prompt must use the shown role, not the true one: %s", model.systems[0])
+	}
+
+	b.OnEvent(done, dayEvent(t, "phase.day", nil))
+	for i := 0; i < 5; i++ {
+		b.postChat(context.Background())
+	}
+	if len(d.cmds) != 4 {
+		t.Fatalf("expected the cap to reset on a new day, got %d messages", len(d.cmds))
+	}
+}
@@ -0,0 +1,112 @@
+// Package bot 欺骗型 Bot：邪恶时伪装善良角色并策略性投票
+//
+// PersonalityDeceptive 的 Bot 只依据自己合法得知的信息行动：role.assigned (本人角色与阵营)、
+// team.recognition (本人收到的恶魔/爪牙身份与恶魔的伪装角色)、demon.changed (本人成为新恶魔)。
+// 邪恶时在发言中固定声称一个善良角色 (优先恶魔伪装角色，否则随机村民)，投票绝不支持处决己方恶魔、
+// 很少支持处决队友、倾向处决善良玩家；善良时按 smart 性格行动。
+//
+// [IN]  internal/game（角色目录：伪装角色的显示名与村民列表）
+// [OUT] bot.go（事件处理、投票决策与发言生成）
+// [POS] Bot 个体的邪恶阵营伪装策略
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+)
+
+// PersonalityDeceptive bluffs a good role and protects its team when evil.
+const PersonalityDeceptive Personality = "deceptive"
+
+// onTeamRecognition stores the evil team info told to this bot. Caller holds b.mu.
+func (b *Bot) onTeamRecognition(payload map[string]string) {
+	if payload["user_id"] != b.userID {
+		return
+	}
+	b.team = "evil"
+	b.demonID = payload["demon_id"]
+	var minions []string
+	_ = json.Unmarshal([]byte(payload["minion_ids"]), &minions)
+	b.teammates = minions
+	if raw := payload["bluffs"]; raw != "" {
+		var bluffs []string
+		_ = json.Unmarshal([]byte(raw), &bluffs)
+		b.bluffs = bluffs
+	}
+}
+
+// onDemonChanged follows the demon when this bot becomes it. Caller holds b.mu.
+func (b *Bot) onDemonChanged(payload map[string]string) {
+	if payload["new_demon"] == b.userID {
+		b.demonID = b.userID
+	}
+}
+
+// decideDeceptiveVote votes to protect the evil team; good deceptive bots vote like smart ones.
+func (b *Bot) decideDeceptiveVote(team, nominee string) bool {
+	if team != "evil" {
+		return randomChance(45)
+	}
+	b.mu.RLock()
+	demonID := b.demonID
+	teammates := b.teammates
+	b.mu.RUnlock()
+
+	if nominee == demonID || nominee == b.userID {
+		return false
+	}
+	for _, mate := range teammates {
+		if nominee == mate {
+			return randomChance(15)
+		}
+	}
+	return randomChance(70)
+}
+
+// claimedRole returns the good role this bot claims, choosing it once. Caller holds b.mu.
+func (b *Bot) claimedRole() string {
+	if b.claim != "" {
+		return b.claim
+	}
+	if len(b.bluffs) > 0 {
+		b.claim = b.bluffs[randomInt(len(b.bluffs))]
+		return b.claim
+	}
+	townsfolk := game.GetRolesByType(game.RoleTownsfolk)
+	if len(townsfolk) > 0 {
+		b.claim = townsfolk[randomInt(len(townsfolk))].ID
+	}
+	return b.claim
+}
+
+// claimedRoleName is the claim's display name (Chinese, falling back to the ID). Caller holds b.mu.
+func (b *Bot) claimedRoleName() string {
+	id := b.claimedRole()
+	if r := game.GetRoleByID(id); r != nil {
+		return r.NameCN
+	}
+	return id
+}
+
+// bluffChat is an evil deceptive bot's template message, consistent with its claim.
+func (b *Bot) bluffChat(dayCount int) string {
+	b.mu.Lock()
+	claim := b.claimedRoleName()
+	b.mu.Unlock()
+
+	if dayCount <= 1 {
+		msgs := []string{
+			fmt.Sprintf("大家好，我是%s，我的角色是%s。", b.name, claim),
+			fmt.Sprintf("我是%s，可以告诉大家我是%s，我站在善良这边。", b.name, claim),
+		}
+		return msgs[randomInt(len(msgs))]
+	}
+	msgs := []string{
+		fmt.Sprintf("作为%s，我的信息和大家对得上，我们再想想谁最可疑。", claim),
+		fmt.Sprintf("我是%s，我不建议今天乱处决，先听听其他人的信息。", claim),
+		"有人的说法前后矛盾，我更怀疑那边。",
+	}
+	return msgs[randomInt(len(msgs))]
+}
@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+)
+
+// newEvilDeceptiveBot is a poisoner minion that was told the demon and the other minion.
+func newEvilDeceptiveBot(t *testing.T, d *recordingDispatcher) *Bot {
+	t.Helper()
+	b := NewBot(BotConfig{UserID: "bot-1", Name: "Alice", Personality: PersonalityDeceptive})
+	b.SetDispatcher(d, "room-1")
+	done, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.OnEvent(done, seqEvent(1, "role.assigned", map[string]string{"user_id": "bot-1", "role": "poisoner", "true_role": "poisoner", "team": "evil"}))
+	b.OnEvent(done, seqEvent(2, "team.recognition", map[string]string{"user_id": "bot-1", "team": "evil", "role": "poisoner", "demon_id": "demon-1", "minion_ids": `["bot-1","spy-1"]`}))
+	// Another player's recognition must not change what this bot knows.
+	b.OnEvent(done, seqEvent(3, "team.recognition", map[string]string{"user_id": "demon-1", "demon_id": "demon-1", "bluffs": `["chef"]`}))
+	return b
+}
+
+func TestDeceptiveEvilBotNeverVotesForItsDemon(t *testing.T) {
+	d := &recordingDispatcher{}
+	b := newEvilDeceptiveBot(t, d)
+
+	for i := 0; i < 200; i++ {
+		if b.decideVote(PersonalityDeceptive, "evil", "demon-1") {
+			t.Fatal("an evil deceptive bot voted to execute its own demon")
+		}
+	}
+
+	done, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.OnEvent(done, seqEvent(4, "nomination.created", map[string]string{"nominee": "demon-1"}))
+	b.maybeVoteAfterDelay(context.Background(), "demon-1")
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.cmds) != 1 || d.cmds[0].Type != "vote" {
+		t.Fatalf("expected one vote command, got %+v", d.cmds)
+	}
+	var payload map[string]string
+	_ = json.Unmarshal(d.cmds[0].Payload, &payload)
+	if payload["vote"] != "no" {
+		t.Fatalf("expected a no vote on the demon, got %q", payload["vote"])
+	}
+}
+
+func TestDeceptiveEvilBotClaimsAGoodRole(t *testing.T) {
+	b := newEvilDeceptiveBot(t, &recordingDispatcher{})
+	if len(b.bluffs) != 0 {
+		t.Fatalf("a minion must not learn the demon's bluffs, got %v", b.bluffs)
+	}
+
+	b.mu.Lock()
+	claim := b.claimedRole()
+	b.mu.Unlock()
+	role := game.GetRoleByID(claim)
+	if role == nil || role.Team != game.TeamGood {
+		t.Fatalf("expected a good role claim, got %q", claim)
+	}
+	for i := 0; i < 5; i++ {
+		if msg := b.generateChat(); !strings.Contains(msg, role.NameCN) {
+			t.Fatalf("day-one chat does not state the claim %s: %q", role.NameCN, msg)
+		}
+	}
+}
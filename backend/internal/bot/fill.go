@@ -0,0 +1,55 @@
+// Package bot 按目标人数补位：计算需要添加的 Bot 数量与空座位
+//
+// AddBotsRequest.TargetTotal > 0 时忽略 Count，按房间已入座玩家 (TakenSeats，含真人与已有 Bot)
+// 计算补到目标人数所需的 Bot 数；新 Bot 依次坐进编号最小的空座位，因此真人坐在前排时 Bot 座位连续。
+//
+// [OUT] manager.go（AddBots）
+// [POS] Bot 添加前的数量与座位计算
+package bot
+
+import "fmt"
+
+// maxBotsPerRoom caps the bots one room can hold.
+const maxBotsPerRoom = 14
+
+// botsToAdd returns how many bots req asks for, given existing bots in the room.
+func botsToAdd(req AddBotsRequest, existing int) (int, error) {
+	count := req.Count
+	if req.TargetTotal > 0 {
+		count = req.TargetTotal - len(req.TakenSeats)
+		if count <= 0 {
+			return 0, fmt.Errorf("room already has %d players, target is %d", len(req.TakenSeats), req.TargetTotal)
+		}
+	}
+	if count <= 0 {
+		return 0, fmt.Errorf("count must be positive")
+	}
+	if count > maxBotsPerRoom {
+		return 0, fmt.Errorf("cannot add more than %d bots", maxBotsPerRoom)
+	}
+	if existing+count > maxBotsPerRoom {
+		return 0, fmt.Errorf("too many bots: have %d, adding %d, max %d", existing, count, maxBotsPerRoom)
+	}
+	return count, nil
+}
+
+// openSeats returns the n lowest seat numbers (from 1) not in taken.
+// A nil taken keeps the legacy layout of seating after the room's existing bots.
+func openSeats(taken []int, existing, n int) []int {
+	used := make(map[int]bool, len(taken))
+	if taken == nil {
+		for seat := 1; seat <= existing; seat++ {
+			used[seat] = true
+		}
+	}
+	for _, seat := range taken {
+		used[seat] = true
+	}
+	seats := make([]int, 0, n)
+	for seat := 1; len(seats) < n; seat++ {
+		if !used[seat] {
+			seats = append(seats, seat)
+		}
+	}
+	return seats
+}
@@ -0,0 +1,51 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+type recordingDispatcher struct {
+	mu   sync.Mutex
+	cmds []types.CommandEnvelope
+}
+
+func (d *recordingDispatcher) DispatchAsync(cmd types.CommandEnvelope) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cmds = append(d.cmds, cmd)
+	return nil
+}
+
+func TestAddBotsFillsToTargetTotal(t *testing.T) {
+	m := NewManager(nil)
+	d := &recordingDispatcher{}
+
+	ids, err := m.AddBots(context.Background(), AddBotsRequest{RoomID: "room-1", TargetTotal: 7, TakenSeats: []int{1, 2}}, d)
+	if err != nil {
+		t.Fatalf("AddBots: %v", err)
+	}
+	if len(ids) != 5 || m.BotCount("room-1") != 5 {
+		t.Fatalf("expected 5 bots for 2 humans and target 7, got %d", len(ids))
+	}
+	for i, cmd := range d.cmds {
+		var payload map[string]string
+		_ = json.Unmarshal(cmd.Payload, &payload)
+		if want := i + 3; payload["seat_number"] != strconv.Itoa(want) {
+			t.Fatalf("bot %d: expected seat %d, got %q", i, want, payload["seat_number"])
+		}
+	}
+}
+
+func TestAddBotsTargetAlreadyReached(t *testing.T) {
+	m := NewManager(nil)
+	_, err := m.AddBots(context.Background(), AddBotsRequest{RoomID: "room-1", TargetTotal: 2, TakenSeats: []int{1, 2}}, &recordingDispatcher{})
+	if err == nil {
+		t.Fatal("expected an error when the room already has target_total players")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -30,6 +31,14 @@ type Manager struct {
 	mu     sync.RWMutex
 	bots   map[string][]*Bot // roomID -> bots
 	logger *slog.Logger
+
+	// chat 为 LLM 发言配置 (SetChat)，仅对 AddBotsRequest.LLMChat 的 Bot 生效
+	chat ChatConfig
+
+	// 监督与重连 (supervisor.go)：source 为断线补齐的事件来源，超过 maxRestarts 次重启后移除 Bot
+	source       EventSource
+	maxRestarts  int
+	restartDelay time.Duration
 }
 
 // NewManager creates a new bot manager.
@@ -40,6 +49,9 @@ func NewManager(logger *slog.Logger) *Manager {
 	return &Manager{
 		bots:   make(map[string][]*Bot),
 		logger: logger,
+
+		maxRestarts:  defaultMaxRestarts,
+		restartDelay: defaultRestartDelay,
 	}
 }
 
@@ -48,25 +60,38 @@ type AddBotsRequest struct {
 	RoomID      string      `json:"room_id"`
 	Count       int         `json:"count"`
 	Personality Personality `json:"personality,omitempty"`
+
+	// 按目标人数补位：TargetTotal > 0 时忽略 Count，补到 TargetTotal 名玩家；TakenSeats 为已入座座位号
+	TargetTotal int   `json:"target_total,omitempty"`
+	TakenSeats  []int `json:"taken_seats,omitempty"`
+
+	// LLMChat 让新 Bot 用 LLM 发言 (需先 SetChat 配置模型)
+	LLMChat bool `json:"llm_chat,omitempty"`
 }
 
-// AddBots creates and adds bot players to a room.
+// SetChat configures the LLM chat used by bots added with LLMChat.
+func (m *Manager) SetChat(cfg ChatConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chat = cfg
+}
+
+// AddBots creates and adds bot players to a room, seating them in open seats.
 // Returns the list of bot user IDs created.
 func (m *Manager) AddBots(ctx context.Context, req AddBotsRequest, dispatcher CommandDispatcher) ([]string, error) {
-	if req.Count <= 0 {
-		return nil, fmt.Errorf("count must be positive")
-	}
-	if req.Count > 14 {
-		return nil, fmt.Errorf("cannot add more than 14 bots")
-	}
-
 	m.mu.Lock()
 	existing := len(m.bots[req.RoomID])
+	var chat ChatConfig
+	if req.LLMChat {
+		chat = m.chat
+	}
 	m.mu.Unlock()
 
-	if existing+req.Count > 14 {
-		return nil, fmt.Errorf("too many bots: have %d, adding %d, max 14", existing, req.Count)
+	count, err := botsToAdd(req, existing)
+	if err != nil {
+		return nil, err
 	}
+	seats := openSeats(req.TakenSeats, existing, count)
 
 	personality := req.Personality
 	if personality == "" {
@@ -76,7 +101,7 @@ func (m *Manager) AddBots(ctx context.Context, req AddBotsRequest, dispatcher Co
 	var botIDs []string
 	var newBots []*Bot
 
-	for i := 0; i < req.Count; i++ {
+	for i := 0; i < count; i++ {
 		nameIdx := existing + i
 		name := BotNames[nameIdx%len(BotNames)]
 		if nameIdx >= len(BotNames) {
@@ -89,45 +114,54 @@ func (m *Manager) AddBots(ctx context.Context, req AddBotsRequest, dispatcher Co
 			Name:        name,
 			Personality: personality,
 			Logger:      m.logger,
+			Chat:        chat,
 		})
 		b.SetDispatcher(dispatcher, req.RoomID)
 
 		newBots = append(newBots, b)
 		botIDs = append(botIDs, botID)
-
-		// Join the room as a player
-		joinPayload, _ := json.Marshal(map[string]string{
-			"name":        name,
-			"seat_number": fmt.Sprintf("%d", existing+i+1),
-			"role":        "player",
-		})
-		if err := dispatcher.DispatchAsync(types.CommandEnvelope{
-			CommandID:   fmt.Sprintf("bot-join-%s", botID),
-			RoomID:      req.RoomID,
-			Type:        "join",
-			ActorUserID: botID,
-			Payload:     joinPayload,
-		}); err != nil {
-			m.logger.Error("bot failed to join", "bot", name, "error", err)
-		}
+		m.joinBot(dispatcher, req.RoomID, b, seats[i])
 	}
 
 	m.mu.Lock()
 	m.bots[req.RoomID] = append(m.bots[req.RoomID], newBots...)
 	m.mu.Unlock()
 
-	m.logger.Info("bots added", "room", req.RoomID, "count", req.Count, "total", existing+req.Count)
+	// Bots outlive the request that added them
+	for _, b := range newBots {
+		m.startBot(context.WithoutCancel(ctx), req.RoomID, b)
+	}
+
+	m.logger.Info("bots added", "room", req.RoomID, "count", count, "total", existing+count)
 	return botIDs, nil
 }
 
-// OnEvent broadcasts an event to all bots in a room.
+// joinBot dispatches the bot's join command for seat.
+func (m *Manager) joinBot(dispatcher CommandDispatcher, roomID string, b *Bot, seat int) {
+	joinPayload, _ := json.Marshal(map[string]string{
+		"name":        b.Name(),
+		"seat_number": fmt.Sprintf("%d", seat),
+		"role":        "player",
+	})
+	if err := dispatcher.DispatchAsync(types.CommandEnvelope{
+		CommandID:   fmt.Sprintf("bot-join-%s", b.UserID()),
+		RoomID:      roomID,
+		Type:        "join",
+		ActorUserID: b.UserID(),
+		Payload:     joinPayload,
+	}); err != nil {
+		m.logger.Error("bot failed to join", "bot", b.Name(), "error", err)
+	}
+}
+
+// OnEvent delivers an event to all bots in a room; each bot handles it on its own loop.
 func (m *Manager) OnEvent(ctx context.Context, roomID string, ev types.Event) {
 	m.mu.RLock()
 	bots := m.bots[roomID]
 	m.mu.RUnlock()
 
 	for _, b := range bots {
-		b.OnEvent(ctx, ev)
+		b.deliver(ev)
 	}
 }
 
@@ -138,11 +172,20 @@ func (m *Manager) GetBots(roomID string) []*Bot {
 	return m.bots[roomID]
 }
 
-// RemoveBots removes all bots from a room.
+// RemoveBots removes all bots from a room and stops their loops.
 func (m *Manager) RemoveBots(roomID string) {
 	m.mu.Lock()
+	bots := m.bots[roomID]
 	delete(m.bots, roomID)
 	m.mu.Unlock()
+
+	for _, b := range bots {
+		b.connMu.Lock()
+		if b.stop != nil {
+			b.stop()
+		}
+		b.connMu.Unlock()
+	}
 }
 
 // BotCount returns the number of bots in a room.
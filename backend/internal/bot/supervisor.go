@@ -0,0 +1,197 @@
+// Package bot Bot 事件订阅的监督与断线重连
+//
+// AddBots 为每个 Bot 建立一个事件订阅 (botConn，带缓冲通道) 并启动监督协程：Manager.OnEvent 只把事件
+// 投递进订阅，Bot 在自己的循环里逐个处理。处理时 panic、订阅被关闭 (缓冲写满视为连接掉线并主动关闭)
+// 时循环退出，监督者等待 restartDelay 后建立新订阅，并以 last_seq 从 EventSource 补齐断线期间的事件
+// (按 seq 去重) 再继续处理实时事件。重启次数超过 maxRestarts 后放弃并把 Bot 移出房间；RemoveBots 停止监督。
+//
+// [IN]  EventSource（cmd/server 以事件存储 LoadEventsAfter 实现）
+// [OUT] manager.go（AddBots 启动、OnEvent 投递、RemoveBots 停止）
+// [POS] Bot 生命周期的容错层
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+const (
+	defaultMaxRestarts  = 5
+	defaultRestartDelay = 500 * time.Millisecond
+	botConnBuffer       = 128
+	seenWindow          = 512 // seqs kept for dedup below the newest one
+)
+
+var errConnClosed = errors.New("bot connection closed")
+
+// EventSource replays a room's events after a sequence number.
+type EventSource interface {
+	EventsAfter(ctx context.Context, roomID string, afterSeq int64) ([]types.Event, error)
+}
+
+// botConn is one bot's event subscription.
+type botConn struct {
+	events chan types.Event
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newBotConn() *botConn {
+	return &botConn{events: make(chan types.Event, botConnBuffer), closed: make(chan struct{})}
+}
+
+// deliver queues ev; a full buffer drops the connection so the bot catches up after reconnecting.
+func (c *botConn) deliver(ev types.Event) {
+	select {
+	case <-c.closed:
+	case c.events <- ev:
+	default:
+		c.close()
+	}
+}
+
+func (c *botConn) close() {
+	c.once.Do(func() { close(c.closed) })
+}
+
+// SetEventSource sets where restarted bots catch up missed events.
+func (m *Manager) SetEventSource(src EventSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.source = src
+}
+
+// startBot connects b and supervises its event loop until ctx ends.
+func (m *Manager) startBot(ctx context.Context, roomID string, b *Bot) {
+	ctx, cancel := context.WithCancel(ctx)
+	b.connMu.Lock()
+	b.conn = newBotConn()
+	b.stop = cancel
+	b.connMu.Unlock()
+	go m.supervise(ctx, roomID, b)
+}
+
+func (m *Manager) supervise(ctx context.Context, roomID string, b *Bot) {
+	var backlog []types.Event
+	for restarts := 0; ; restarts++ {
+		err := b.run(ctx, backlog)
+		if ctx.Err() != nil {
+			return
+		}
+		if restarts >= m.maxRestarts {
+			m.logger.Error("bot exceeded max restarts, removing", "bot", b.name, "room", roomID, "error", err)
+			m.removeBot(roomID, b)
+			return
+		}
+		m.logger.Warn("bot loop stopped, reconnecting", "bot", b.name, "room", roomID, "restart", restarts+1, "error", err)
+		select {
+		case <-time.After(m.restartDelay):
+		case <-ctx.Done():
+			return
+		}
+		backlog = m.reconnect(ctx, roomID, b)
+	}
+}
+
+// reconnect opens a new subscription and returns the events missed since the bot's last seq.
+func (m *Manager) reconnect(ctx context.Context, roomID string, b *Bot) []types.Event {
+	b.connMu.Lock()
+	b.conn = newBotConn()
+	b.connMu.Unlock()
+
+	m.mu.RLock()
+	src := m.source
+	m.mu.RUnlock()
+	if src == nil {
+		return nil
+	}
+	events, err := src.EventsAfter(ctx, roomID, b.lastSeq)
+	if err != nil {
+		m.logger.Warn("bot catch-up failed", "bot", b.name, "room", roomID, "error", err)
+		return nil
+	}
+	return events
+}
+
+// deliver hands ev to the bot's current subscription.
+func (b *Bot) deliver(ev types.Event) {
+	b.connMu.Lock()
+	conn := b.conn
+	b.connMu.Unlock()
+	if conn != nil {
+		conn.deliver(ev)
+	}
+}
+
+// run handles backlog then live events until the subscription closes, ctx ends, or a handler panics.
+func (b *Bot) run(ctx context.Context, backlog []types.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("bot.run: panic: %v", r)
+		}
+	}()
+	b.connMu.Lock()
+	conn := b.conn
+	b.connMu.Unlock()
+
+	for _, ev := range backlog {
+		b.handle(ctx, ev)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-conn.closed:
+			return errConnClosed
+		case ev := <-conn.events:
+			b.handle(ctx, ev)
+		}
+	}
+}
+
+// handle processes ev once per seq; it is marked seen first so a panicking event is not replayed.
+func (b *Bot) handle(ctx context.Context, ev types.Event) {
+	if ev.Seq > 0 {
+		if b.seen[ev.Seq] {
+			return
+		}
+		b.markSeen(ev.Seq)
+	}
+	b.OnEvent(ctx, ev)
+}
+
+// markSeen records seq, advancing lastSeq and pruning seqs far below it.
+func (b *Bot) markSeen(seq int64) {
+	if b.seen == nil {
+		b.seen = make(map[int64]bool)
+	}
+	b.seen[seq] = true
+	if seq > b.lastSeq {
+		b.lastSeq = seq
+	}
+	if len(b.seen) > 2*seenWindow {
+		for s := range b.seen {
+			if s < b.lastSeq-seenWindow {
+				delete(b.seen, s)
+			}
+		}
+	}
+}
+
+// removeBot drops b from the room's bots.
+func (m *Manager) removeBot(roomID string, b *Bot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bots := m.bots[roomID]
+	for i, other := range bots {
+		if other == b {
+			m.bots[roomID] = append(bots[:i:i], bots[i+1:]...)
+			return
+		}
+	}
+}
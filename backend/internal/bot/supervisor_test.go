@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// memEventSource serves a room's events from memory.
+type memEventSource struct {
+	mu     sync.Mutex
+	events []types.Event
+}
+
+func (s *memEventSource) append(ev types.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, ev)
+}
+
+func (s *memEventSource) EventsAfter(_ context.Context, _ string, afterSeq int64) ([]types.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []types.Event
+	for _, ev := range s.events {
+		if ev.Seq > afterSeq {
+			res = append(res, ev)
+		}
+	}
+	return res, nil
+}
+
+func seqEvent(seq int64, typ string, payload map[string]string) types.Event {
+	raw, _ := json.Marshal(payload)
+	return types.Event{RoomID: "room-1", Seq: seq, EventType: typ, Payload: raw}
+}
+
+func (d *recordingDispatcher) waitFor(t *testing.T, typ, actor string, timeout time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		d.mu.Lock()
+		for _, cmd := range d.cmds {
+			if cmd.Type == typ && cmd.ActorUserID == actor {
+				d.mu.Unlock()
+				return
+			}
+		}
+		d.mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s from %s", typ, actor)
+}
+
+func TestBotReconnectsAfterConnectionClosed(t *testing.T) {
+	src := &memEventSource{}
+	m := NewManager(nil)
+	m.SetEventSource(src)
+	m.restartDelay = time.Millisecond
+	d := &recordingDispatcher{}
+
+	ids, err := m.AddBots(context.Background(), AddBotsRequest{RoomID: "room-1", Count: 1}, d)
+	if err != nil {
+		t.Fatalf("AddBots: %v", err)
+	}
+	defer m.RemoveBots("room-1")
+	botID := ids[0]
+	b := m.GetBots("room-1")[0]
+
+	assigned := seqEvent(1, "role.assigned", map[string]string{"user_id": botID, "role": "monk", "true_role": "monk", "team": "good"})
+	src.append(assigned)
+	m.OnEvent(context.Background(), "room-1", assigned)
+
+	// Drop the connection; the prompt is only reachable through catch-up by last_seq.
+	b.connMu.Lock()
+	b.conn.close()
+	b.connMu.Unlock()
+	src.append(seqEvent(2, "night.action.prompt", map[string]string{"user_id": botID, "action_type": "select_one"}))
+
+	d.waitFor(t, "ability.use", botID, 5*time.Second)
+	if got := m.GetBots("room-1"); len(got) != 1 || got[0] != b {
+		t.Fatalf("the reconnected bot must stay in the room, have %d bots", len(got))
+	}
+}
+
+func TestBotRemovedAfterMaxRestarts(t *testing.T) {
+	m := NewManager(nil)
+	m.restartDelay = time.Millisecond
+	m.maxRestarts = 2
+
+	if _, err := m.AddBots(context.Background(), AddBotsRequest{RoomID: "room-1", Count: 1}, &recordingDispatcher{}); err != nil {
+		t.Fatalf("AddBots: %v", err)
+	}
+	b := m.GetBots("room-1")[0]
+	deadline := time.Now().Add(2 * time.Second)
+	for m.BotCount("room-1") > 0 && time.Now().Before(deadline) {
+		b.connMu.Lock()
+		b.conn.close()
+		b.connMu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	if m.BotCount("room-1") != 0 {
+		t.Fatal("a bot that keeps failing must be removed after max restarts")
+	}
+}
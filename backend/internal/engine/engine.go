@@ -41,6 +41,18 @@ func HandleCommand(state State, cmd types.CommandEnvelope) ([]types.Event, *type
 		return handleJoin(state, cmd)
 	case "leave":
 		return handleLeave(state, cmd)
+	case "disconnect":
+		return handleDisconnect(state, cmd)
+	case "reconnect":
+		return handleReconnect(state, cmd)
+	case "remove_player":
+		return handleRemovePlayer(state, cmd)
+	case "exile":
+		return handleExile(state, cmd)
+	case "exile_vote":
+		return handleExileVote(state, cmd)
+	case "resolve_exile":
+		return handleResolveExile(state, cmd)
 	case "claim_seat":
 		return handleClaimSeat(state, cmd)
 	case "room_settings":
@@ -62,7 +74,7 @@ func HandleCommand(state State, cmd types.CommandEnvelope) ([]types.Event, *type
 	case "resolve_nomination":
 		return handleResolveNomination(state, cmd)
 	case "ability.use":
-		return handleAbility(state, cmd)
+		return handleAbilityUse(state, cmd)
 	case "advance_phase":
 		return handleAdvancePhase(state, cmd)
 	case "write_event":
@@ -80,6 +92,22 @@ func HandleCommand(state State, cmd types.CommandEnvelope) ([]types.Event, *type
 		return handleExtendTime(state, cmd)
 	case "night_timeout":
 		return handleNightTimeout(state, cmd)
+	case "queue_night_action":
+		return handleQueueNightAction(state, cmd)
+	case "resolve_tie":
+		return handleResolveTie(state, cmd)
+	case "night_action_timeout":
+		return handleNightActionTimeout(state, cmd)
+	case "undo_last_event":
+		return handleUndoLastEvent(state, cmd)
+	case "debug_set_phase":
+		return handleDebugSetPhase(state, cmd)
+	case "force_assignments":
+		return handleForceAssignments(state, cmd)
+	case "set_language":
+		return handleSetLanguage(state, cmd)
+	case "resume_autodm":
+		return handleResumeAutoDM(state, cmd)
 	default:
 		return nil, nil, fmt.Errorf("unknown command type: %s", cmd.Type)
 	}
@@ -87,14 +115,16 @@ func HandleCommand(state State, cmd types.CommandEnvelope) ([]types.Event, *type
 
 func handleJoin(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
 	if _, exists := state.Players[cmd.ActorUserID]; exists {
-		return nil, nil, fmt.Errorf("player already joined")
+		// Re-joins (reconnects, HTTP join followed by the WebSocket join) only clear a disconnect
+		return handleReconnect(state, cmd)
 	}
-	if state.Phase != PhaseLobby {
-		return nil, nil, fmt.Errorf("cannot join after game started")
-	}
-
 	var payload map[string]string
 	_ = json.Unmarshal(cmd.Payload, &payload)
+	// Travellers may take a seat mid-game; everyone else joins in the lobby
+	traveller := payload["traveller"] == "true"
+	if state.Phase != PhaseLobby && !traveller {
+		return nil, nil, fmt.Errorf("cannot join after game started")
+	}
 
 	name := payload["name"]
 	if name == "" {
@@ -106,6 +136,9 @@ func handleJoin(state State, cmd types.CommandEnvelope) ([]types.Event, *types.C
 		"name":        name,
 		"seat_number": fmt.Sprintf("%d", len(state.Players)+1),
 	}
+	if traveller {
+		eventPayload["traveller"] = "true"
+	}
 
 	return []types.Event{newEvent(cmd, "player.joined", eventPayload)}, acceptedResult(cmd.CommandID), nil
 }
@@ -115,7 +148,8 @@ func handleLeave(state State, cmd types.CommandEnvelope) ([]types.Event, *types.
 		return nil, nil, fmt.Errorf("player not in room")
 	}
 	if state.Phase != PhaseLobby {
-		return nil, nil, fmt.Errorf("cannot leave after game started")
+		// Players stay seated mid-game; leaving only marks them disconnected
+		return handleDisconnect(state, cmd)
 	}
 	return []types.Event{newEvent(cmd, "player.left", nil)}, acceptedResult(cmd.CommandID), nil
 }
@@ -151,6 +185,23 @@ func handleRoomSettings(state State, cmd types.CommandEnvelope) ([]types.Event,
 	if mp, ok := payload["max_players"]; ok {
 		eventPayload["max_players"] = mp
 	}
+	if ta, ok := payload["translate_announcements"]; ok {
+		eventPayload["translate_announcements"] = ta
+	}
+	for _, key := range []string{"discussion_nudge_sec", "discussion_nudge_message", "demon_sees_minion_roles", "min_discussion_sec", "max_discussion_sec", "script", "reveal_on_death", "earliest_nomination_wins_ties", "dm_resolves_ties", "forbid_self_poison", "announce_deaths_at_dawn", "narration_language"} {
+		if v, ok := payload[key]; ok {
+			eventPayload[key] = v
+		}
+	}
+	if err := validateDiscussionBounds(state.Config, eventPayload); err != nil {
+		return nil, nil, err
+	}
+	if err := validateScriptSetting(eventPayload); err != nil {
+		return nil, nil, err
+	}
+	if err := validateNarrationLanguage(eventPayload); err != nil {
+		return nil, nil, err
+	}
 
 	return []types.Event{newEvent(cmd, "room.settings.changed", eventPayload)}, acceptedResult(cmd.CommandID), nil
 }
@@ -161,17 +212,8 @@ func handleStartGame(state State, cmd types.CommandEnvelope) ([]types.Event, *ty
 	}
 
 	// Count non-DM players
-	playerCount := 0
-	var userIDs []string
-	var seatOrder []int
-
-	for uid, p := range state.Players {
-		if !p.IsDM {
-			playerCount++
-			userIDs = append(userIDs, uid)
-			seatOrder = append(seatOrder, p.SeatNumber)
-		}
-	}
+	userIDs, seatOrder := lobbyPlayers(state)
+	playerCount := len(userIDs)
 
 	if playerCount < 5 {
 		return nil, nil, fmt.Errorf("need at least 5 players, have %d", playerCount)
@@ -188,11 +230,17 @@ func handleStartGame(state State, cmd types.CommandEnvelope) ([]types.Event, *ty
 		_ = json.Unmarshal([]byte(cr), &customRoles)
 	}
 
-	// Use SetupAgent to assign roles
+	// Use SetupAgent to assign roles, drawing only from the room's script when one is set
+	script, err := setupScript(state)
+	if err != nil {
+		return nil, nil, err
+	}
 	setupConfig := game.SetupConfig{
+		Script:      script,
 		PlayerCount: playerCount,
 		Edition:     state.Edition,
 		CustomRoles: customRoles,
+		Seed:        setupSeed(payload),
 	}
 	setupAgent := game.NewSetupAgent(setupConfig)
 	result, err := setupAgent.GenerateAssignments(userIDs, seatOrder)
@@ -200,98 +248,7 @@ func handleStartGame(state State, cmd types.CommandEnvelope) ([]types.Event, *ty
 		return nil, nil, fmt.Errorf("role assignment failed: %w", err)
 	}
 
-	events := []types.Event{newEvent(cmd, "game.started", nil)}
-
-	// Create role assignment events
-	for userID, assignment := range result.Assignments {
-		role := game.GetRoleByID(assignment.Role)
-		teamStr := "good"
-		if role != nil && role.Team == game.TeamEvil {
-			teamStr = "evil"
-		}
-
-		payload := map[string]string{
-			"user_id":   userID,
-			"role":      assignment.PerceivedRole,
-			"true_role": assignment.TrueRole,
-			"team":      teamStr,
-		}
-
-		if assignment.TrueRole == "imp" {
-			payload["is_demon"] = "true"
-		}
-		if role != nil && role.Type == game.RoleMinion {
-			payload["is_minion"] = "true"
-		}
-
-		// Spy: emit apparent role for info resolution
-		if assignment.SpyApparentRole != "" {
-			payload["spy_apparent_role"] = assignment.SpyApparentRole
-		}
-
-		events = append(events, newEvent(cmd, "role.assigned", payload))
-	}
-
-	// Assign bluffs to demon
-	if len(result.BluffRoles) > 0 {
-		bluffsJSON, _ := json.Marshal(result.BluffRoles)
-		events = append(events, newEvent(cmd, "bluffs.assigned", map[string]string{
-			"bluffs": string(bluffsJSON),
-		}))
-	}
-
-	// Assign red herring for fortune teller (a good player who isn't the fortune teller)
-	var fortuneTellerID string
-	var goodPlayerIDs []string
-	for userID, assignment := range result.Assignments {
-		if assignment.TrueRole == "fortuneteller" {
-			fortuneTellerID = userID
-		}
-		if assignment.Team == game.TeamGood && assignment.TrueRole != "fortuneteller" {
-			goodPlayerIDs = append(goodPlayerIDs, userID)
-		}
-	}
-	if fortuneTellerID != "" && len(goodPlayerIDs) > 0 {
-		rhIdx, _ := rand.Int(rand.Reader, big.NewInt(int64(len(goodPlayerIDs))))
-		events = append(events, newEvent(cmd, "red_herring.assigned", map[string]string{
-			"user_id": goodPlayerIDs[rhIdx.Int64()],
-		}))
-	}
-
-	// Queue first night actions
-	for _, action := range result.NightOrder {
-		actionType := ""
-		if r := game.GetRoleByID(action.RoleID); r != nil {
-			actionType = string(r.FirstNightActionType)
-		}
-		events = append(events, newEvent(cmd, "night.action.queued", map[string]string{
-			"user_id":     action.UserID,
-			"role_id":     action.RoleID,
-			"order":       fmt.Sprintf("%d", action.Order),
-			"action_type": actionType,
-		}))
-	}
-	// Auto-complete no_action roles (e.g. Imp first night)
-	events = append(events, buildNoActionCompletions(cmd, result.NightOrder)...)
-
-	// Transition to first night
-	events = append(events, newEvent(cmd, "phase.first_night", map[string]string{}))
-
-	// 首夜开始时：邪恶阵营互认（爪牙认恶魔、恶魔认爪牙+伪装角色）
-	events = append(events, buildTeamRecognitionFromSetup(cmd, result)...)
-
-	// Prompt the first actionable player (sequential night actions)
-	// Build NightAction slice matching engine state format for prompt helper
-	queuedActions := buildEngineNightActions(result.NightOrder, true)
-	autoCompleted := buildNoActionSet(result.NightOrder)
-	for i := range queuedActions {
-		if autoCompleted[queuedActions[i].UserID] {
-			queuedActions[i].Completed = true
-		}
-	}
-	events = append(events, buildFirstPrompt(cmd, queuedActions)...)
-
-	return events, acceptedResult(cmd.CommandID), nil
+	return buildStartGameEvents(state, cmd, result), acceptedResult(cmd.CommandID), nil
 }
 
 func handlePublicChat(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
@@ -343,9 +300,14 @@ func handleWhisper(state State, cmd types.CommandEnvelope) ([]types.Event, *type
 	if payload == nil || payload["to_user_id"] == "" || payload["message"] == "" {
 		return nil, nil, fmt.Errorf("invalid whisper payload")
 	}
-	if _, ok := state.Players[payload["to_user_id"]]; !ok {
+	to, toDM, ok := whisperRecipient(state, payload["to_user_id"])
+	if !ok {
 		return nil, nil, fmt.Errorf("recipient not in room")
 	}
+	payload["to_user_id"] = to
+	if toDM {
+		payload["to_dm"] = "true"
+	}
 
 	sender := state.Players[cmd.ActorUserID]
 	payload["sender_name"] = sender.Name
@@ -366,7 +328,7 @@ func handleNomination(state State, cmd types.CommandEnvelope) ([]types.Event, *t
 	// the actual nominator comes from the payload "nominator" field. If absent,
 	// pick the first alive player who hasn't nominated yet.
 	actorID := cmd.ActorUserID
-	if actorID == "autodm" {
+	if types.IsAutoDMActor(actorID) {
 		var payload map[string]string
 		_ = json.Unmarshal(cmd.Payload, &payload)
 		if nominatorID, ok := payload["nominator"]; ok && nominatorID != "" {
@@ -496,7 +458,7 @@ func handleEndDefense(state State, cmd types.CommandEnvelope) ([]types.Event, *t
 	isNominator := cmd.ActorUserID == state.Nomination.Nominator
 	isNominee := cmd.ActorUserID == state.Nomination.Nominee
 	isDM := state.Players[cmd.ActorUserID].IsDM
-	isAutoDM := cmd.ActorUserID == "autodm" || cmd.ActorUserID == "auto-dm"
+	isAutoDM := types.IsAutoDMActor(cmd.ActorUserID)
 
 	if !isNominator && !isNominee && !isDM && !isAutoDM {
 		return nil, nil, fmt.Errorf("only nominator, nominee, DM, or autodm can end defense")
@@ -664,12 +626,9 @@ func handleAbility(state State, cmd types.CommandEnvelope) ([]types.Event, *type
 	var payload map[string]string
 	_ = json.Unmarshal(cmd.Payload, &payload)
 
-	var targetIDs []string
-	if targets := payload["targets"]; targets != "" {
-		_ = json.Unmarshal([]byte(targets), &targetIDs)
-	}
-	if target := payload["target"]; target != "" {
-		targetIDs = []string{target}
+	targetIDs, err := poisonerTargets(state, cmd.ActorUserID, abilityTargets(payload))
+	if err != nil {
+		return nil, nil, err
 	}
 
 	events := []types.Event{}
@@ -704,7 +663,7 @@ func handleAbility(state State, cmd types.CommandEnvelope) ([]types.Event, *type
 		applyEventsToState(&workingState, []types.Event{completionEvent})
 
 		// 所有行动收集完毕 → 统一结算 → 信息分发 → 天亮
-		resolveEvents := resolveNight(workingState, cmd)
+		resolveEvents := orderDawnDeaths(workingState, resolveNight(workingState, cmd))
 		events = append(events, resolveEvents...)
 
 		// 应用结算效果到 state 副本，用于信息分发
@@ -714,19 +673,19 @@ func handleAbility(state State, cmd types.CommandEnvelope) ([]types.Event, *type
 		infoEvents := distributeNightInfo(stateCopy, cmd)
 		events = append(events, infoEvents...)
 
-		events = append(events, newEvent(cmd, "phase.day", buildPhaseDayPayload(stateCopy, resolveEvents)))
+		events = append(events, buildDawnEvents(cmd, stateCopy, resolveEvents)...)
 
 		// 胜负检查
 		winEvents := checkWinCondition(stateCopy, cmd)
 		events = append(events, winEvents...)
 	}
 
-	return events, acceptedResult(cmd.CommandID), nil
+	return autoCompleteDeadPoisoner(state, cmd, events), acceptedResult(cmd.CommandID), nil
 }
 
 func handleAdvancePhase(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
 	// Permission: only autodm, room owner, or DM may advance phase
-	isAutoDM := cmd.ActorUserID == "autodm" || cmd.ActorUserID == "auto-dm"
+	isAutoDM := types.IsAutoDMActor(cmd.ActorUserID)
 	isOwner := cmd.ActorUserID == state.OwnerID
 	isDM := false
 	if p, ok := state.Players[cmd.ActorUserID]; ok {
@@ -754,6 +713,9 @@ func handleAdvancePhase(state State, cmd types.CommandEnvelope) ([]types.Event,
 		events = append(events, finalizeNightFromCompletions(state, cmd, timeoutEvents)...)
 
 	case "night":
+		var tieEvents []types.Event
+		state, tieEvents = breakTieByEarliestNomination(state, cmd)
+		events = append(events, tieEvents...)
 		// Execute on-the-block player before entering night (only if no execution yet)
 		if state.OnTheBlock != nil && state.ExecutedToday == "" {
 			events = append(events, newEvent(cmd, "execution.resolved", map[string]string{
@@ -769,6 +731,12 @@ func handleAdvancePhase(state State, cmd types.CommandEnvelope) ([]types.Event,
 				state.Players[state.OnTheBlock.UserID] = p
 			}
 			state.ExecutedToday = state.OnTheBlock.UserID
+		} else if state.ExecutedToday == "" {
+			// 明确记录今日无人处决，供市长胜利与送葬者使用
+			events = append(events, newEvent(cmd, "day.no_execution", map[string]string{
+				"day": fmt.Sprintf("%d", state.DayCount),
+			}))
+			state.NoExecutionToday = true
 		}
 
 		preNightWinEvents := checkWinCondition(state, cmd)
@@ -829,7 +797,7 @@ func handleAdvancePhase(state State, cmd types.CommandEnvelope) ([]types.Event,
 }
 
 func handleWriteEvent(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
-	if cmd.ActorUserID != "autodm" && cmd.ActorUserID != "auto-dm" {
+	if !types.IsAutoDMActor(cmd.ActorUserID) {
 		player, ok := state.Players[cmd.ActorUserID]
 		if !ok || !player.IsDM {
 			return nil, nil, fmt.Errorf("only DM or AutoDM can write custom events")
@@ -951,7 +919,7 @@ func handleSlayerShot(state State, cmd types.CommandEnvelope) ([]types.Event, *t
 // handleCloseVote resolves an active nomination via the unified vote settlement path.
 // Only autodm may call this (timeout-driven force close).
 func handleCloseVote(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
-	if cmd.ActorUserID != "autodm" {
+	if !types.IsAutoDMActor(cmd.ActorUserID) {
 		return nil, nil, fmt.Errorf("only autodm can close votes")
 	}
 	if state.Nomination == nil || state.Nomination.Resolved {
@@ -964,7 +932,7 @@ func handleCloseVote(state State, cmd types.CommandEnvelope) ([]types.Event, *ty
 
 // FIX-13: handleRequestAction emits an event prompting a player to act.
 func handleRequestAction(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
-	if cmd.ActorUserID != "autodm" {
+	if !types.IsAutoDMActor(cmd.ActorUserID) {
 		return nil, nil, fmt.Errorf("only autodm can request actions")
 	}
 
@@ -990,7 +958,7 @@ func handleRequestAction(state State, cmd types.CommandEnvelope) ([]types.Event,
 
 // FIX-14: handleSetTimer emits a timer event for phase deadlines.
 func handleSetTimer(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
-	if cmd.ActorUserID != "autodm" {
+	if !types.IsAutoDMActor(cmd.ActorUserID) {
 		return nil, nil, fmt.Errorf("only autodm can set timers")
 	}
 
@@ -1023,7 +991,7 @@ func checkWinCondition(state State, cmd types.CommandEnvelope) []types.Event {
 	if demon, ok := stateCopy.Players[stateCopy.DemonID]; ok && !demon.Alive {
 		for uid, p := range stateCopy.Players {
 			if p.TrueRole == "scarletwoman" && p.Alive {
-				if stateCopy.GetAliveCount() >= 5 {
+				if stateCopy.GetAliveResidentCount() >= 5 {
 					return []types.Event{
 						newEvent(cmd, "demon.changed", map[string]string{
 							"old_demon": stateCopy.DemonID,
@@ -1057,7 +1025,9 @@ func buildGameContext(state State) *game.GameContext {
 		NightNumber:         state.NightCount,
 		RedHerringID:        state.RedHerringID,
 		ExecutedToday:       state.ExecutedToday,
+		NoExecutionToday:    state.NoExecutionToday,
 		RecluseRegisterEvil: recluseEvil,
+		ForbidSelfPoison:    state.Config.ForbidSelfPoison,
 	}
 
 	for uid, p := range state.Players {
@@ -0,0 +1,135 @@
+// engine_ability_check.go — 夜晚行动与真实角色的匹配校验
+//
+// ability.use 可携带 action_type；它必须属于行动者自认角色 (Role，酒鬼为其以为的角色) 的能力集合：
+// 角色目录中的首夜/其他夜晚行动类型 (info/select_one/select_two/no_action)，
+// 外加角色元数据的效果 Role.ActionType (如僧侣 protect、小恶魔 kill)。不匹配时返回 *AbilityMismatchError，
+// 防止客户端以共情者身份提交僧侣的保护。未携带 action_type 时不校验。
+// 按真实角色校验会让酒鬼从拒绝结果里试探出自己是酒鬼，因此错误信息不含任何角色名。
+// 目标数按本次排队行动校验：选人类行动取角色元数据的 Role.TargetCount，
+// info/no_action 为 0 个；不符时返回 *TargetCountError，不产生任何事件 (不会记录残缺行动)。
+// 两项校验只针对玩家提交的 ability.use；超时与死亡投毒者的自动完成直接走 handleAbility。
+//
+// [IN]  internal/game（角色目录）
+// [OUT] engine.go（ability.use 入口 handleAbilityUse）
+// [POS] 夜晚收集层对提交动作的角色校验
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// AbilityMismatchError rejects an ability whose action type the actor's perceived role lacks.
+// RoleID is for server-side inspection only; Error() reaches the client and names no role.
+type AbilityMismatchError struct {
+	RoleID     string
+	ActionType string
+}
+
+func (e *AbilityMismatchError) Error() string {
+	return fmt.Sprintf("engine.validateAbilityAction: your role cannot perform %q", e.ActionType)
+}
+
+// TargetCountError rejects an ability submitted with the wrong number of targets.
+// RoleID is the perceived role and, like AbilityMismatchError's, stays out of Error().
+type TargetCountError struct {
+	RoleID string
+	Want   int
+	Got    int
+}
+
+func (e *TargetCountError) Error() string {
+	return fmt.Sprintf("engine.validateTargetCount: expected %d target(s), got %d", e.Want, e.Got)
+}
+
+// expectedTargetCount is how many players a queued night action selects.
+func expectedTargetCount(a NightAction) int {
+	kind := game.ActionType(nightActionType(a))
+	if kind != game.ActionSelectOne && kind != game.ActionSelectTwo {
+		return 0
+	}
+	if role := game.GetRoleByID(a.RoleID); role != nil && role.TargetCount > 0 {
+		return role.TargetCount
+	}
+	if kind == game.ActionSelectTwo {
+		return 2
+	}
+	return 1
+}
+
+// handleAbilityUse validates a player's ability.use submission before collecting it.
+func handleAbilityUse(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	var payload map[string]string
+	_ = json.Unmarshal(cmd.Payload, &payload)
+	player := state.Players[cmd.ActorUserID]
+	if err := validateAbilityAction(player, payload["action_type"]); err != nil {
+		return nil, nil, err
+	}
+	if err := validateTargetCount(state, player, abilityTargets(payload)); err != nil {
+		return nil, nil, err
+	}
+	return handleAbility(state, cmd)
+}
+
+// abilityTargets reads the submitted targets: a JSON "targets" list, or a single "target".
+func abilityTargets(payload map[string]string) []string {
+	var targetIDs []string
+	if targets := payload["targets"]; targets != "" {
+		_ = json.Unmarshal([]byte(targets), &targetIDs)
+	}
+	if target := payload["target"]; target != "" {
+		targetIDs = []string{target}
+	}
+	return targetIDs
+}
+
+// validateTargetCount checks submitted targets against the actor's queued night action.
+// Submissions out of turn are left to handleAbility's sequencing check.
+func validateTargetCount(state State, player Player, targetIDs []string) error {
+	a, ok := state.NextPendingNightAction()
+	if !ok || a.UserID != player.UserID {
+		return nil
+	}
+	if want := expectedTargetCount(a); len(targetIDs) != want {
+		return &TargetCountError{RoleID: perceivedRole(player), Want: want, Got: len(targetIDs)}
+	}
+	return nil
+}
+
+// roleAbilityActions returns the action types a role may submit.
+func roleAbilityActions(roleID string) map[string]bool {
+	actions := make(map[string]bool)
+	role := game.GetRoleByID(roleID)
+	if role == nil {
+		return actions
+	}
+	for _, t := range []game.ActionType{role.FirstNightActionType, role.NightActionType} {
+		if t != "" {
+			actions[string(t)] = true
+		}
+	}
+	if role.ActionType != "" && role.ActionType != "none" {
+		actions[role.ActionType] = true
+	}
+	return actions
+}
+
+// validateAbilityAction checks a submitted action type against the actor's perceived role.
+func validateAbilityAction(player Player, actionType string) error {
+	role := perceivedRole(player)
+	if actionType == "" || roleAbilityActions(role)[actionType] {
+		return nil
+	}
+	return &AbilityMismatchError{RoleID: role, ActionType: actionType}
+}
+
+// perceivedRole is the role the player believes they hold (a Drunk's fake role).
+func perceivedRole(player Player) string {
+	if player.Role != "" {
+		return player.Role
+	}
+	return player.TrueRole
+}
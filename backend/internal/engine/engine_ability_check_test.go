@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func typedAbilityCommand(actor, actionType, target string) types.CommandEnvelope {
+	payload, _ := json.Marshal(map[string]string{"action_type": actionType, "target": target})
+	return types.CommandEnvelope{CommandID: "cmd-" + actor, RoomID: "room-1", Type: "ability.use", ActorUserID: actor, Payload: payload}
+}
+
+func TestEmpathSubmittingProtectIsRejected(t *testing.T) {
+	state := newStalledNightState()
+	state.Players["empath"] = Player{UserID: "empath", TrueRole: "empath", Team: "good", Alive: true, SeatNumber: 4}
+	state.NightActions = []NightAction{{UserID: "empath", RoleID: "empath", Order: 1, ActionType: "info"}}
+
+	_, _, err := HandleCommand(state, typedAbilityCommand("empath", "protect", "chef"))
+	var mismatch *AbilityMismatchError
+	if !errors.As(err, &mismatch) || mismatch.RoleID != "empath" || mismatch.ActionType != "protect" {
+		t.Fatalf("expected an ability mismatch for the empath, got %v", err)
+	}
+}
+
+func TestMonkSubmittingProtectIsAccepted(t *testing.T) {
+	state := newStalledNightState()
+
+	if _, _, err := HandleCommand(state, typedAbilityCommand("monk", "protect", "chef")); err != nil {
+		t.Fatalf("expected the monk's protect to be accepted, got %v", err)
+	}
+}
+
+func TestWrongTargetCountsAreRejectedPerRole(t *testing.T) {
+	cases := []struct {
+		role    string
+		action  string
+		targets []string
+		want    int
+	}{
+		{"fortuneteller", "select_two", []string{"chef"}, 2},
+		{"fortuneteller", "select_two", []string{"chef", "imp", "monk"}, 2},
+		{"monk", "select_one", nil, 1},
+		{"monk", "select_one", []string{"chef", "imp"}, 1},
+		{"poisoner", "select_one", nil, 1},
+		{"empath", "info", []string{"chef"}, 0},
+	}
+	for _, tc := range cases {
+		state := newStalledNightState()
+		state.Players[tc.role] = Player{UserID: tc.role, TrueRole: tc.role, Alive: true, SeatNumber: 9}
+		state.NightActions = []NightAction{{UserID: tc.role, RoleID: tc.role, Order: 1, ActionType: tc.action}}
+
+		events, _, err := HandleCommand(state, abilityCommand(tc.role, tc.targets...))
+		var count *TargetCountError
+		if !errors.As(err, &count) || count.Want != tc.want || count.Got != len(tc.targets) {
+			t.Fatalf("%s with %v: expected a target count error wanting %d, got %v", tc.role, tc.targets, tc.want, err)
+		}
+		if len(events) != 0 {
+			t.Fatalf("%s with %v: a rejected action must not queue events, got %d", tc.role, tc.targets, len(events))
+		}
+	}
+}
+
+func TestFortuneTellerWithTwoTargetsIsAccepted(t *testing.T) {
+	state := newStalledNightState()
+	state.Players["ft"] = Player{UserID: "ft", TrueRole: "fortuneteller", Alive: true, SeatNumber: 4}
+	state.NightActions = []NightAction{{UserID: "ft", RoleID: "fortuneteller", Order: 1, ActionType: "select_two"}}
+
+	if _, _, err := HandleCommand(state, abilityCommand("ft", "chef", "imp")); err != nil {
+		t.Fatalf("expected two fortune teller targets to be accepted, got %v", err)
+	}
+}
+
+func TestDrunkAbilityMismatchDoesNotRevealTrueRole(t *testing.T) {
+	state := newStalledNightState()
+	state.Players["drunk"] = Player{UserID: "drunk", Role: "empath", TrueRole: "drunk", Team: "good", Alive: true, SeatNumber: 4}
+
+	_, _, err := HandleCommand(state, typedAbilityCommand("drunk", "protect", "chef"))
+	var mismatch *AbilityMismatchError
+	if !errors.As(err, &mismatch) || mismatch.RoleID != "empath" {
+		t.Fatalf("expected a mismatch checked against the perceived empath role, got %v", err)
+	}
+	if strings.Contains(err.Error(), "drunk") || strings.Contains(err.Error(), "empath") {
+		t.Fatalf("mismatch error must not name a role, got %q", err.Error())
+	}
+}
+
+func TestDrunkBelievingMonkMayProtect(t *testing.T) {
+	state := newStalledNightState()
+	state.Players["monk"] = Player{UserID: "monk", Role: "monk", TrueRole: "drunk", Team: "good", Alive: true, SeatNumber: 1}
+
+	if _, _, err := HandleCommand(state, typedAbilityCommand("monk", "protect", "chef")); err != nil {
+		t.Fatalf("expected the drunk's perceived monk protect to be accepted, got %v", err)
+	}
+}
+
+func TestDrunkTargetCountErrorDoesNotRevealTrueRole(t *testing.T) {
+	state := newStalledNightState()
+	state.Players["monk"] = Player{UserID: "monk", Role: "monk", TrueRole: "drunk", Team: "good", Alive: true, SeatNumber: 1}
+
+	_, _, err := HandleCommand(state, abilityCommand("monk"))
+	var count *TargetCountError
+	if !errors.As(err, &count) || count.RoleID != "monk" {
+		t.Fatalf("expected a target count error for the perceived monk, got %v", err)
+	}
+	if strings.Contains(err.Error(), "drunk") || strings.Contains(err.Error(), "monk") {
+		t.Fatalf("target count error must not name a role, got %q", err.Error())
+	}
+}
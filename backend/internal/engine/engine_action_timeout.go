@@ -0,0 +1,119 @@
+// engine_action_timeout.go — 单个夜晚行动超时自动完成
+//
+// 与整夜超时（已禁用）不同，这里只处理当前轮到的一个行动：玩家掉线或长时间未选择时，
+// 房间 Actor 的行动计时器以 autodm 身份发送 night_action_timeout，
+// 按角色策略代选目标（或不选），产生带 reason=timeout 的 night.action.completed，
+// 之后的提示与结算沿用 ability.use 的正常流程。
+//
+// [IN]  internal/game（角色行动类型）
+// [IN]  internal/types（Command/Event 类型）
+// [OUT] engine.go（HandleCommand 路由 night_action_timeout）
+// [POS] 夜晚行动顺序的掉线兜底
+package engine
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// timeoutRandomTargetRoles pick random targets on timeout; other roles complete with no target
+// (no protection, no poison). The Imp still kills so nights keep their stakes.
+var timeoutRandomTargetRoles = map[string]bool{
+	"imp":           true,
+	"fortuneteller": true,
+	"butler":        true,
+	"ravenkeeper":   true,
+}
+
+// handleNightActionTimeout auto-completes the pending action named by user_id/order.
+// A stale timeout (the player already acted) is rejected.
+func handleNightActionTimeout(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	if !types.IsAutoDMActor(cmd.ActorUserID) {
+		return nil, nil, fmt.Errorf("engine.handleNightActionTimeout: only autodm can time out night actions")
+	}
+	if state.Phase != PhaseNight && state.Phase != PhaseFirstNight {
+		return nil, nil, fmt.Errorf("engine.handleNightActionTimeout: %w", ErrInvalidPhase)
+	}
+
+	var payload map[string]string
+	_ = json.Unmarshal(cmd.Payload, &payload)
+	action, ok := state.NextPendingNightAction()
+	if !ok || action.UserID != payload["user_id"] || strconv.Itoa(action.Order) != payload["order"] {
+		return nil, nil, fmt.Errorf("engine.handleNightActionTimeout: action already completed")
+	}
+
+	targets, _ := json.Marshal(timeoutTargets(state, action))
+	abilityCmd := cmd
+	abilityCmd.ActorUserID = action.UserID
+	abilityCmd.Payload, _ = json.Marshal(map[string]string{"targets": string(targets)})
+
+	events, result, err := handleAbility(state, abilityCmd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("engine.handleNightActionTimeout: %w", err)
+	}
+	for i := range events {
+		if events[i].EventType == "night.action.completed" {
+			events[i] = withPayloadField(events[i], "reason", "timeout")
+			break
+		}
+	}
+	return events, result, nil
+}
+
+// timeoutTargets picks targets for a timed-out action per timeoutRandomTargetRoles.
+func timeoutTargets(state State, action NightAction) []string {
+	if !timeoutRandomTargetRoles[action.RoleID] {
+		return []string{}
+	}
+	count := 1
+	if nightActionType(action) == string(game.ActionSelectTwo) {
+		count = 2
+	}
+
+	var candidates []string
+	for _, uid := range state.SeatOrder {
+		if p := state.Players[uid]; uid != action.UserID && p.Alive && !p.IsDM {
+			candidates = append(candidates, uid)
+		}
+	}
+	picked := make([]string, 0, count)
+	for len(picked) < count && len(candidates) > 0 {
+		idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(candidates))))
+		if err != nil {
+			break
+		}
+		i := int(idx.Int64())
+		picked = append(picked, candidates[i])
+		candidates = append(candidates[:i], candidates[i+1:]...)
+	}
+	return picked
+}
+
+// nightActionType returns the action's type, falling back to the role's night action type.
+func nightActionType(action NightAction) string {
+	if action.ActionType != "" {
+		return action.ActionType
+	}
+	if r := game.GetRoleByID(action.RoleID); r != nil {
+		return string(r.NightActionType)
+	}
+	return ""
+}
+
+// withPayloadField returns ev with key set in its JSON payload.
+func withPayloadField(ev types.Event, key, value string) types.Event {
+	var payload map[string]string
+	_ = json.Unmarshal(ev.Payload, &payload)
+	if payload == nil {
+		payload = map[string]string{}
+	}
+	payload[key] = value
+	ev.Payload, _ = json.Marshal(payload)
+	return ev
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// newStalledNightState has the monk then the imp still to act on night 2.
+func newStalledNightState() State {
+	state := NewState("room-1")
+	state.Phase = PhaseNight
+	state.NightCount = 2
+	state.DemonID = "imp"
+	for i, p := range []Player{
+		{UserID: "monk", TrueRole: "monk", Team: "good"},
+		{UserID: "imp", TrueRole: "imp", Team: "evil"},
+		{UserID: "chef", TrueRole: "chef", Team: "good"},
+	} {
+		p.Alive = true
+		p.SeatNumber = i + 1
+		state.Players[p.UserID] = p
+		state.SeatOrder = append(state.SeatOrder, p.UserID)
+	}
+	state.NightActions = []NightAction{
+		{UserID: "monk", RoleID: "monk", Order: 1, ActionType: "select_one"},
+		{UserID: "imp", RoleID: "imp", Order: 2, ActionType: "select_one"},
+	}
+	return state
+}
+
+func timeoutCommand(userID, order string) types.CommandEnvelope {
+	payload, _ := json.Marshal(map[string]string{"user_id": userID, "order": order})
+	return types.CommandEnvelope{CommandID: "cmd-timeout", RoomID: "room-1", Type: "night_action_timeout", ActorUserID: "autodm", Payload: payload}
+}
+
+func TestNightActionTimeoutCompletesWithoutTargetAndPromptsNext(t *testing.T) {
+	state := newStalledNightState()
+
+	events, _, err := HandleCommand(state, timeoutCommand("monk", "1"))
+	if err != nil {
+		t.Fatalf("timeout failed: %v", err)
+	}
+	completed := findEventPayload(t, events, "night.action.completed")
+	if completed["user_id"] != "monk" || completed["reason"] != "timeout" {
+		t.Fatalf("unexpected completion payload: %v", completed)
+	}
+	if completed["targets"] != "[]" {
+		t.Fatalf("expected monk to protect nobody, got targets %s", completed["targets"])
+	}
+	if prompt := findEventPayload(t, events, "night.action.prompt"); prompt["user_id"] != "imp" {
+		t.Fatalf("expected imp to be prompted next, got %v", prompt)
+	}
+}
+
+func TestNightActionTimeoutImpPicksAnotherLivingPlayer(t *testing.T) {
+	state := newStalledNightState()
+	state.NightActions[0].Completed = true
+
+	events, _, err := HandleCommand(state, timeoutCommand("imp", "2"))
+	if err != nil {
+		t.Fatalf("timeout failed: %v", err)
+	}
+	var targets []string
+	_ = json.Unmarshal([]byte(findEventPayload(t, events, "night.action.completed")["targets"]), &targets)
+	if len(targets) != 1 || targets[0] == "imp" {
+		t.Fatalf("expected one non-self target, got %v", targets)
+	}
+	if !hasTestEventType(events, "phase.day") {
+		t.Fatal("expected the last timed-out action to resolve the night")
+	}
+}
+
+func TestNightActionTimeoutRejectsStaleTimer(t *testing.T) {
+	state := newStalledNightState()
+	state.NightActions[0].Completed = true
+
+	if _, _, err := HandleCommand(state, timeoutCommand("monk", "1")); err == nil {
+		t.Fatal("expected timeout for an already completed action to be rejected")
+	}
+	cmd := timeoutCommand("imp", "2")
+	cmd.ActorUserID = "chef"
+	if _, _, err := HandleCommand(state, cmd); err == nil {
+		t.Fatal("expected players to be unable to time out actions")
+	}
+}
@@ -0,0 +1,66 @@
+// Package engine 人类 DM 接管时暂停 Auto-DM
+//
+// 房间内的人类 DM (Players[uid].IsDM，非 autodm 账号) 发出推进流程类命令 (如 advance_phase)
+// 并成功产生事件时，WithAutoDMTakeover 在这些事件前追加一条 autodm.paused，AutoDMPaused 置位：
+// Auto-DM 继续观察状态但不再发言或发命令。DM 或房主发送 resume_autodm 产生 autodm.resumed 恢复。
+// 已暂停时不重复追加。
+//
+// [OUT] room（handleCommand 在引擎处理后追加暂停事件）
+// [OUT] agent（暂停期间只观察不行动）
+// [POS] 人类 DM 与 Auto-DM 的控制权交接
+package engine
+
+import (
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// autoDMTakeoverCommands are the storyteller commands that signal a human DM is running the game.
+var autoDMTakeoverCommands = map[string]bool{
+	"advance_phase":        true,
+	"end_defense":          true,
+	"resolve_nomination":   true,
+	"close_vote":           true,
+	"write_event":          true,
+	"request_action":       true,
+	"set_timer":            true,
+	"extend_time":          true,
+	"night_timeout":        true,
+	"queue_night_action":   true,
+	"resolve_tie":          true,
+	"night_action_timeout": true,
+	"undo_last_event":      true,
+}
+
+// IsHumanDM reports whether userID is a room DM other than the Auto-DM itself.
+func IsHumanDM(state State, userID string) bool {
+	if types.IsAutoDMActor(userID) {
+		return false
+	}
+	return state.Players[userID].IsDM
+}
+
+// WithAutoDMTakeover prepends autodm.paused when a human DM's storyteller command produced events.
+func WithAutoDMTakeover(state State, cmd types.CommandEnvelope, events []types.Event) []types.Event {
+	if state.AutoDMPaused || len(events) == 0 || !autoDMTakeoverCommands[cmd.Type] || !IsHumanDM(state, cmd.ActorUserID) {
+		return events
+	}
+	paused := newEvent(cmd, "autodm.paused", map[string]string{
+		"by_user_id":   cmd.ActorUserID,
+		"command_type": cmd.Type,
+	})
+	return append([]types.Event{paused}, events...)
+}
+
+// handleResumeAutoDM hands control back to the Auto-DM.
+func handleResumeAutoDM(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	if !IsHumanDM(state, cmd.ActorUserID) && cmd.ActorUserID != state.OwnerID {
+		return nil, nil, fmt.Errorf("engine.handleResumeAutoDM: only the DM or room owner can resume the Auto-DM")
+	}
+	if !state.AutoDMPaused {
+		return nil, acceptedResult(cmd.CommandID), nil
+	}
+	event := newEvent(cmd, "autodm.resumed", map[string]string{"by_user_id": cmd.ActorUserID})
+	return []types.Event{event}, acceptedResult(cmd.CommandID), nil
+}
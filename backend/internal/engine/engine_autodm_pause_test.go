@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// takeoverCommand runs cmd through the engine and the takeover hook, reducing the events into state.
+func takeoverCommand(t *testing.T, state *State, actor, cmdType string, payload map[string]string) []types.Event {
+	t.Helper()
+	raw, _ := json.Marshal(payload)
+	cmd := types.CommandEnvelope{CommandID: "c-" + cmdType, RoomID: "room-1", Type: cmdType, ActorUserID: actor, Payload: raw}
+	events, _, err := HandleCommand(*state, cmd)
+	if err != nil {
+		t.Fatalf("%s by %s rejected: %v", cmdType, actor, err)
+	}
+	events = WithAutoDMTakeover(*state, cmd, events)
+	for _, e := range events {
+		var p map[string]string
+		_ = json.Unmarshal(e.Payload, &p)
+		state.Reduce(EventPayload{Seq: state.LastSeq + 1, EventID: e.EventID, Type: e.EventType, Actor: e.ActorUserID, Payload: p})
+	}
+	return events
+}
+
+func TestHumanDMAdvancePhasePausesAutoDMUntilResumed(t *testing.T) {
+	state := NewState("room-1")
+	state.Phase = PhaseDay
+	state.Players["dm"] = Player{UserID: "dm", IsDM: true}
+
+	events := takeoverCommand(t, &state, "autodm", "advance_phase", map[string]string{"phase": "nomination"})
+	if state.AutoDMPaused || events[0].EventType == "autodm.paused" {
+		t.Fatal("expected the Auto-DM's own commands not to pause it")
+	}
+
+	state.Phase = PhaseDay
+	events = takeoverCommand(t, &state, "dm", "advance_phase", map[string]string{"phase": "nomination"})
+	if events[0].EventType != "autodm.paused" || !state.AutoDMPaused {
+		t.Fatalf("expected autodm.paused before the phase change, got %s (paused=%v)", events[0].EventType, state.AutoDMPaused)
+	}
+
+	state.Phase = PhaseDay
+	events = takeoverCommand(t, &state, "dm", "advance_phase", map[string]string{"phase": "nomination"})
+	if events[0].EventType == "autodm.paused" {
+		t.Fatal("expected no second autodm.paused while already paused")
+	}
+
+	state.Phase = PhaseDay
+	takeoverCommand(t, &state, "dm", "resume_autodm", nil)
+	if state.AutoDMPaused {
+		t.Fatal("expected resume_autodm to hand control back")
+	}
+}
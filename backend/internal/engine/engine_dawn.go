@@ -0,0 +1,95 @@
+// engine_dawn.go — 天亮时的死亡公布
+//
+// 夜晚结算产生的 player.died 按座位号稳定排序，避免事件顺序暴露击杀机制
+// （如镇长转移、恶魔传递）；可选地追加一条 dawn.summary 供叙事者一次性公布
+// (Config.AnnounceDeathsAtDawn，默认开启，room_settings 的 announce_deaths_at_dawn 可关闭)。
+//
+// [IN]  internal/types（Event 类型）
+// [OUT] engine.go / engine_night_timeout.go（夜晚结束进入白天）
+// [POS] 夜晚 → 白天过渡的死亡公布层
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// orderDawnDeaths 将 player.died 事件按座位号排序，其余事件位置不变。
+func orderDawnDeaths(state State, events []types.Event) []types.Event {
+	var positions []int
+	var deaths []types.Event
+	for i, event := range events {
+		if event.EventType == "player.died" {
+			positions = append(positions, i)
+			deaths = append(deaths, event)
+		}
+	}
+	if len(deaths) < 2 {
+		return events
+	}
+
+	sort.SliceStable(deaths, func(i, j int) bool {
+		return deathSeat(state, deaths[i]) < deathSeat(state, deaths[j])
+	})
+
+	ordered := append([]types.Event{}, events...)
+	for i, pos := range positions {
+		ordered[pos] = deaths[i]
+	}
+	return ordered
+}
+
+// deathSeat 返回死亡事件对应玩家的座位号；未知玩家排在最后。
+func deathSeat(state State, event types.Event) int {
+	var payload map[string]string
+	_ = json.Unmarshal(event.Payload, &payload)
+	if p, ok := state.Players[payload["user_id"]]; ok && p.SeatNumber > 0 {
+		return p.SeatNumber
+	}
+	return math.MaxInt
+}
+
+// buildDawnEvents 生成天亮事件：可选的 dawn.summary 与 phase.day。
+// deathEvents 必须已经过 orderDawnDeaths 排序。
+func buildDawnEvents(cmd types.CommandEnvelope, state State, deathEvents []types.Event) []types.Event {
+	events := []types.Event{}
+	if state.Config.AnnounceDeathsAtDawn {
+		events = append(events, newEvent(cmd, "dawn.summary", buildDawnSummaryPayload(state, deathEvents)))
+	}
+	return append(events, newEvent(cmd, "phase.day", buildPhaseDayPayload(state, deathEvents)))
+}
+
+// buildDawnSummaryPayload 列出夜间死亡玩家（按座位号，去重）。
+func buildDawnSummaryPayload(state State, deathEvents []types.Event) map[string]string {
+	userIDs := []string{}
+	names := []string{}
+	seen := make(map[string]bool)
+	for _, event := range deathEvents {
+		if event.EventType != "player.died" {
+			continue
+		}
+		var payload map[string]string
+		_ = json.Unmarshal(event.Payload, &payload)
+		uid := payload["user_id"]
+		if uid == "" || seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		userIDs = append(userIDs, uid)
+		names = append(names, state.Players[uid].Name)
+	}
+
+	deathsJSON, _ := json.Marshal(userIDs)
+	namesJSON, _ := json.Marshal(names)
+	seatsJSON, _ := json.Marshal(collectNightDeathSeatNumbers(state, deathEvents))
+	return map[string]string{
+		"deaths":       string(deathsJSON),
+		"names":        string(namesJSON),
+		"seat_numbers": string(seatsJSON),
+		"count":        fmt.Sprintf("%d", len(userIDs)),
+	}
+}
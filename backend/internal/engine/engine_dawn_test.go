@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func newDawnDeathState() State {
+	state := NewState("room-1")
+	state.Players["seat1"] = Player{UserID: "seat1", Name: "Alice", Alive: true, SeatNumber: 1}
+	state.Players["seat3"] = Player{UserID: "seat3", Name: "Carol", Alive: true, SeatNumber: 3}
+	state.Players["seat5"] = Player{UserID: "seat5", Name: "Eve", Alive: true, SeatNumber: 5}
+	return state
+}
+
+func TestOrderDawnDeathsSortsBySeatNumber(t *testing.T) {
+	state := newDawnDeathState()
+	cmd := types.CommandEnvelope{CommandID: "cmd-1", RoomID: state.RoomID}
+	events := []types.Event{
+		newEvent(cmd, "player.died", map[string]string{"user_id": "seat5", "cause": "demon"}),
+		newEvent(cmd, "poison.rollback", nil),
+		newEvent(cmd, "player.died", map[string]string{"user_id": "seat1", "cause": "demon_mayor_bounce"}),
+		newEvent(cmd, "player.died", map[string]string{"user_id": "seat3", "cause": "starpass"}),
+	}
+
+	// 多次运行确认顺序稳定
+	for i := 0; i < 5; i++ {
+		ordered := orderDawnDeaths(state, events)
+		var got []string
+		for _, event := range ordered {
+			if event.EventType != "player.died" {
+				continue
+			}
+			var payload map[string]string
+			if err := json.Unmarshal(event.Payload, &payload); err != nil {
+				t.Fatalf("unmarshal player.died payload: %v", err)
+			}
+			got = append(got, payload["user_id"])
+		}
+		want := []string{"seat1", "seat3", "seat5"}
+		for j := range want {
+			if got[j] != want[j] {
+				t.Fatalf("expected seat-ordered deaths %v, got %v", want, got)
+			}
+		}
+		if ordered[1].EventType != "poison.rollback" {
+			t.Fatalf("expected non-death events to keep their position, got %s", ordered[1].EventType)
+		}
+	}
+}
+
+func TestBuildDawnEventsEmitsSummaryBeforePhaseDay(t *testing.T) {
+	state := newDawnDeathState()
+	cmd := types.CommandEnvelope{CommandID: "cmd-2", RoomID: state.RoomID}
+	deaths := orderDawnDeaths(state, []types.Event{
+		newEvent(cmd, "player.died", map[string]string{"user_id": "seat3", "cause": "demon"}),
+		newEvent(cmd, "player.died", map[string]string{"user_id": "seat1", "cause": "demon"}),
+	})
+
+	events := buildDawnEvents(cmd, state, deaths)
+	if len(events) != 2 || events[0].EventType != "dawn.summary" || events[1].EventType != "phase.day" {
+		t.Fatalf("expected dawn.summary then phase.day, got %v", events)
+	}
+
+	summary := findEventPayload(t, events, "dawn.summary")
+	if summary["deaths"] != `["seat1","seat3"]` {
+		t.Fatalf("expected seat-ordered deaths, got %s", summary["deaths"])
+	}
+	if summary["names"] != `["Alice","Carol"]` {
+		t.Fatalf("expected seat-ordered names, got %s", summary["names"])
+	}
+	if summary["count"] != "2" {
+		t.Fatalf("expected count 2, got %s", summary["count"])
+	}
+}
+
+func TestBuildDawnEventsSkipsSummaryWhenDisabled(t *testing.T) {
+	state := newDawnDeathState()
+	state.Config.AnnounceDeathsAtDawn = false
+	cmd := types.CommandEnvelope{CommandID: "cmd-3", RoomID: state.RoomID}
+
+	events := buildDawnEvents(cmd, state, nil)
+	if hasTestEventType(events, "dawn.summary") {
+		t.Fatal("expected no dawn.summary when batching is disabled")
+	}
+	if !hasTestEventType(events, "phase.day") {
+		t.Fatal("expected phase.day")
+	}
+}
+
+func TestRoomSettingsDisableDawnSummary(t *testing.T) {
+	events, _, err := HandleCommand(NewState("room-1"), presenceCommand("room_settings", "p1", map[string]string{"announce_deaths_at_dawn": "false"}))
+	if err != nil {
+		t.Fatalf("room_settings: %v", err)
+	}
+	state := newDawnDeathState()
+	applyEventsToState(&state, events)
+	if state.Config.AnnounceDeathsAtDawn {
+		t.Fatal("expected room_settings to disable announce_deaths_at_dawn")
+	}
+
+	out := buildDawnEvents(types.CommandEnvelope{CommandID: "cmd-4", RoomID: state.RoomID}, state, nil)
+	if hasTestEventType(out, "dawn.summary") {
+		t.Fatal("expected no dawn.summary after room_settings disabled it")
+	}
+}
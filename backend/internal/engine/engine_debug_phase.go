@@ -0,0 +1,155 @@
+// engine_debug_phase.go — 调试命令：快进到指定阶段/天数
+//
+// debug_set_phase {phase, day} 供 QA 直接跳到某一阶段而无需完整对局。命令只产生普通阶段事件，
+// 状态仍由 Reduce 归约，回放结果与正常推进一致：大厅中先按 start_game 同样的流程分配角色
+// (可带 custom_roles/seed)；跳过的夜晚行动记为 timed_out，不结算死亡；跳过的白天记为无人处决。
+// day 为目标状态的 DayCount (夜晚沿用前一个白天的编号，首夜为 0)；目标为夜晚时最后一步走
+// advance_phase 的入夜流程，排好夜晚行动以便继续游戏。仅 DM/AutoDM 且服务器调试模式
+// (State.DebugMode) 可用，只能向前快进。
+//
+// [IN]  internal/types（Command/Event 类型）
+// [OUT] engine.go（HandleCommand 路由 debug_set_phase）
+// [POS] 测试房间的阶段快进入口
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// maxDebugSteps bounds how many phase changes one fast-forward may emit.
+const maxDebugSteps = 60
+
+// handleDebugSetPhase fast-forwards the game to payload phase/day. DM or autodm, debug mode only.
+func handleDebugSetPhase(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	if !types.IsAutoDMActor(cmd.ActorUserID) && !state.Players[cmd.ActorUserID].IsDM {
+		return nil, nil, fmt.Errorf("engine.handleDebugSetPhase: only DM or autodm can fast-forward")
+	}
+	if !state.DebugMode {
+		return nil, nil, fmt.Errorf("engine.handleDebugSetPhase: debug commands are disabled")
+	}
+	var payload map[string]string
+	_ = json.Unmarshal(cmd.Payload, &payload)
+	target, err := debugPhaseTarget(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("engine.handleDebugSetPhase: %w", err)
+	}
+	if state.Phase == PhaseEnded || target <= phasePosition(state) {
+		return nil, nil, fmt.Errorf("engine.handleDebugSetPhase: can only fast-forward an ongoing game")
+	}
+
+	working := state.Copy()
+	var events []types.Event
+	for steps := 0; phasePosition(working) < target; steps++ {
+		if steps >= maxDebugSteps {
+			return nil, nil, fmt.Errorf("engine.handleDebugSetPhase: target is more than %d phases away", maxDebugSteps)
+		}
+		next, err := debugPhaseStep(working, cmd, phasePosition(working)+1 == target)
+		if err != nil {
+			return nil, nil, fmt.Errorf("engine.handleDebugSetPhase: %w", err)
+		}
+		applyEventsToState(&working, next)
+		events = append(events, next...)
+		if working.Phase == PhaseEnded {
+			break
+		}
+	}
+	return events, acceptedResult(cmd.CommandID), nil
+}
+
+// debugPhaseTarget maps the payload to a phasePosition.
+func debugPhaseTarget(payload map[string]string) (int, error) {
+	day := 0
+	if v := payload["day"]; v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("invalid day %q", v)
+		}
+		day = d
+	}
+	switch Phase(payload["phase"]) {
+	case PhaseFirstNight:
+		if day != 0 {
+			return 0, fmt.Errorf("first_night is day 0")
+		}
+		return 0, nil
+	case PhaseDay, PhaseNomination, PhaseNight:
+		if day < 1 {
+			return 0, fmt.Errorf("%s needs day >= 1", payload["phase"])
+		}
+		return dayPosition(Phase(payload["phase"]), day), nil
+	default:
+		return 0, fmt.Errorf("invalid target phase %q", payload["phase"])
+	}
+}
+
+// phasePosition orders game phases: lobby -1, first night 0, then day/nomination/night per day.
+func phasePosition(state State) int {
+	switch state.Phase {
+	case PhaseLobby:
+		return -1
+	case PhaseFirstNight:
+		return 0
+	case PhaseVoting:
+		return dayPosition(PhaseNomination, state.DayCount)
+	default:
+		return dayPosition(state.Phase, state.DayCount)
+	}
+}
+
+func dayPosition(phase Phase, day int) int {
+	switch phase {
+	case PhaseDay:
+		return 3*day - 2
+	case PhaseNomination:
+		return 3*day - 1
+	default: // the night after day
+		return 3 * day
+	}
+}
+
+// debugPhaseStep returns the events moving state one phase forward.
+func debugPhaseStep(state State, cmd types.CommandEnvelope, last bool) ([]types.Event, error) {
+	switch state.Phase {
+	case PhaseLobby:
+		events, _, err := handleStartGame(state, cmd)
+		return events, err
+	case PhaseFirstNight, PhaseNight:
+		events := skipNightActions(state, cmd)
+		return append(events, newEvent(cmd, "phase.day", nil)), nil
+	case PhaseDay:
+		return []types.Event{newEvent(cmd, "phase.nomination", nil)}, nil
+	default:
+		if last {
+			advance := cmd
+			advance.Payload, _ = json.Marshal(map[string]string{"phase": "night"})
+			events, _, err := handleAdvancePhase(state, advance)
+			return events, err
+		}
+		return []types.Event{
+			newEvent(cmd, "day.no_execution", map[string]string{"day": strconv.Itoa(state.DayCount)}),
+			newEvent(cmd, "poison.cleared", nil),
+			newEvent(cmd, "phase.night", nil),
+		}, nil
+	}
+}
+
+// skipNightActions completes every pending night action as timed_out, evil ones included.
+func skipNightActions(state State, cmd types.CommandEnvelope) []types.Event {
+	var events []types.Event
+	for _, a := range state.NightActions {
+		if a.Completed {
+			continue
+		}
+		events = append(events, newEvent(cmd, "night.action.completed", map[string]string{
+			"user_id": a.UserID,
+			"role_id": a.RoleID,
+			"targets": "[]",
+			"result":  "timed_out",
+		}))
+	}
+	return events
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func newDebugLobby(t *testing.T) *gameHarness {
+	h := newGameHarness(t)
+	h.state.Reduce(EventPayload{Seq: 1, EventID: "ev-dm", Type: "player.joined", Actor: "dm", Payload: map[string]string{"role": "dm", "name": "DM"}})
+	for i := 1; i <= 5; i++ {
+		h.do(fmt.Sprintf("p%d", i), "join", map[string]string{"name": fmt.Sprintf("P%d", i)})
+	}
+	h.state.DebugMode = true
+	return h
+}
+
+func TestDebugSetPhaseFastForwardsLobbyToDayTwo(t *testing.T) {
+	h := newDebugLobby(t)
+	roles, _ := json.Marshal([]string{"washerwoman", "chef", "empath", "poisoner", "imp"})
+	h.do("dm", "debug_set_phase", map[string]string{"phase": "day", "day": "2", "custom_roles": string(roles)})
+
+	if h.state.Phase != PhaseDay || h.state.DayCount != 2 || h.state.NightCount != 2 {
+		t.Fatalf("expected day 2 after night 2, got phase=%s day=%d night=%d", h.state.Phase, h.state.DayCount, h.state.NightCount)
+	}
+	if h.state.SubPhase != SubPhaseDiscussion || h.state.GetAliveCount() != 5 {
+		t.Fatalf("expected discussion with everyone alive, sub=%s alive=%d", h.state.SubPhase, h.state.GetAliveCount())
+	}
+	if h.state.DemonID != h.playerWithRole("imp") {
+		t.Fatalf("expected synthesized assignments to set the demon, got %q", h.state.DemonID)
+	}
+	for uid, p := range h.state.Players {
+		if !p.IsDM && (p.TrueRole == "" || p.Team == "") {
+			t.Fatalf("player %s has no role after fast-forward: %+v", uid, p)
+		}
+	}
+	for _, a := range h.state.NightActions {
+		if !a.Completed {
+			t.Fatalf("expected skipped night actions to be completed, %s is pending", a.UserID)
+		}
+	}
+
+	// A night target goes through the regular dusk flow, so the night is playable.
+	h.do("dm", "debug_set_phase", map[string]string{"phase": "night", "day": "2"})
+	if h.state.Phase != PhaseNight || h.state.NightCount != 3 || len(h.state.NightActions) == 0 {
+		t.Fatalf("expected a playable night 3, phase=%s night=%d actions=%d", h.state.Phase, h.state.NightCount, len(h.state.NightActions))
+	}
+}
+
+func TestDebugSetPhaseRestrictions(t *testing.T) {
+	h := newDebugLobby(t)
+	dayTwo := map[string]string{"phase": "day", "day": "2"}
+	for name, tc := range map[string]struct {
+		actor   string
+		payload map[string]string
+		debug   bool
+	}{
+		"player":         {actor: "p1", payload: dayTwo, debug: true},
+		"debug disabled": {actor: "dm", payload: dayTwo, debug: false},
+		"unknown phase":  {actor: "dm", payload: map[string]string{"phase": "voting", "day": "1"}, debug: true},
+		"day zero":       {actor: "dm", payload: map[string]string{"phase": "day"}, debug: true},
+	} {
+		state := h.state.Copy()
+		state.DebugMode = tc.debug
+		raw, _ := json.Marshal(tc.payload)
+		cmd := undoCommand(tc.actor)
+		cmd.Type, cmd.Payload = "debug_set_phase", raw
+		if _, _, err := HandleCommand(state, cmd); err == nil {
+			t.Fatalf("%s: expected debug_set_phase to be rejected", name)
+		}
+	}
+
+	h.do("dm", "debug_set_phase", dayTwo)
+	state := h.state.Copy()
+	raw, _ := json.Marshal(map[string]string{"phase": "night", "day": "1"})
+	cmd := undoCommand("dm")
+	cmd.Type, cmd.Payload = "debug_set_phase", raw
+	if _, _, err := HandleCommand(state, cmd); err == nil {
+		t.Fatal("expected fast-forwarding backwards to be rejected")
+	}
+}
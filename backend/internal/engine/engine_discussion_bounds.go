@@ -0,0 +1,83 @@
+// engine_discussion_bounds.go — 白天讨论时长上下限
+//
+// 房间设置 min_discussion_sec / max_discussion_sec (0 不限) 把固定的 DiscussionDurationSec
+// 扩展为区间：白天 (含提名阶段，自 DayStartedAt 起算) 满 Min 之后任一提名结算即可提前入夜，
+// 未满 Min 的提名结算不推进；满 Max 时无论进行到哪里都强制入夜。计时由 room 包调度，
+// 这里只负责设置校验与时间判定。
+//
+// [IN]  engine.go（handleRoomSettings 校验）、state_reduce.go（room.settings.changed 写入 Config）
+// [OUT] room（nomination.resolved 提前入夜判定与 Min 到期入夜、Max 强制入夜计时）
+// [POS] 白天节奏的时长边界
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DiscussionBounded reports whether the day uses Min/MaxDiscussionSec bounds.
+func (c GameConfig) DiscussionBounded() bool {
+	return c.MinDiscussionSec > 0 || c.MaxDiscussionSec > 0
+}
+
+// CanEndDayEarly reports whether the day has lasted at least MinDiscussionSec at now.
+func (s State) CanEndDayEarly(now time.Time) bool {
+	if s.Config.MinDiscussionSec <= 0 || s.DayStartedAt == 0 {
+		return true
+	}
+	earliest := time.UnixMilli(s.DayStartedAt).Add(time.Duration(s.Config.MinDiscussionSec) * time.Second)
+	return !now.Before(earliest)
+}
+
+// DayMinRemaining is how long until MinDiscussionSec lets the day end; 0 once it has passed.
+func (s State) DayMinRemaining(now time.Time) time.Duration {
+	if s.Config.MinDiscussionSec <= 0 || s.DayStartedAt == 0 {
+		return 0
+	}
+	earliest := time.UnixMilli(s.DayStartedAt).Add(time.Duration(s.Config.MinDiscussionSec) * time.Second)
+	return max(earliest.Sub(now), 0)
+}
+
+// DayMaxRemaining is how long until MaxDiscussionSec ends the day; ok is false without a max.
+func (s State) DayMaxRemaining(now time.Time) (time.Duration, bool) {
+	if s.Config.MaxDiscussionSec <= 0 || s.DayStartedAt == 0 {
+		return 0, false
+	}
+	end := time.UnixMilli(s.DayStartedAt).Add(time.Duration(s.Config.MaxDiscussionSec) * time.Second)
+	return max(end.Sub(now), 0), true
+}
+
+// validateDiscussionBounds checks the bounds a settings change would leave in place.
+func validateDiscussionBounds(cfg GameConfig, change map[string]string) error {
+	minSec, maxSec := cfg.MinDiscussionSec, cfg.MaxDiscussionSec
+	for key, dst := range map[string]*int{"min_discussion_sec": &minSec, "max_discussion_sec": &maxSec} {
+		v, ok := change[key]
+		if !ok {
+			continue
+		}
+		n, err := json.Number(v).Int64()
+		if err != nil || n < 0 {
+			return fmt.Errorf("%s must be a non-negative number of seconds", key)
+		}
+		*dst = int(n)
+	}
+	if minSec > 0 && maxSec > 0 && minSec > maxSec {
+		return fmt.Errorf("min_discussion_sec (%d) cannot exceed max_discussion_sec (%d)", minSec, maxSec)
+	}
+	return nil
+}
+
+// reduceDiscussionBounds applies validated bounds from a room.settings.changed payload.
+func (s *State) reduceDiscussionBounds(payload map[string]string) {
+	if v, ok := payload["min_discussion_sec"]; ok {
+		if n, err := json.Number(v).Int64(); err == nil && n >= 0 {
+			s.Config.MinDiscussionSec = int(n)
+		}
+	}
+	if v, ok := payload["max_discussion_sec"]; ok {
+		if n, err := json.Number(v).Int64(); err == nil && n >= 0 {
+			s.Config.MaxDiscussionSec = int(n)
+		}
+	}
+}
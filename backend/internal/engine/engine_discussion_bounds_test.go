@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoomSettingsDiscussionBounds(t *testing.T) {
+	state := NewState("room-1")
+	events, _, err := HandleCommand(state, presenceCommand("room_settings", "p1", map[string]string{
+		"min_discussion_sec": "60", "max_discussion_sec": "300",
+	}))
+	if err != nil {
+		t.Fatalf("room_settings: %v", err)
+	}
+	applyEventsToState(&state, events)
+	if state.Config.MinDiscussionSec != 60 || state.Config.MaxDiscussionSec != 300 {
+		t.Fatalf("bounds = %d/%d, want 60/300", state.Config.MinDiscussionSec, state.Config.MaxDiscussionSec)
+	}
+
+	if _, _, err := HandleCommand(state, presenceCommand("room_settings", "p1", map[string]string{"min_discussion_sec": "600"})); err == nil {
+		t.Fatal("expected min above the existing max to be rejected")
+	}
+	if _, _, err := HandleCommand(state, presenceCommand("room_settings", "p1", map[string]string{"max_discussion_sec": "-1"})); err == nil {
+		t.Fatal("expected a negative max to be rejected")
+	}
+}
+
+func TestCanEndDayEarlyAndMaxRemaining(t *testing.T) {
+	now := time.Now()
+	state := NewState("room-1")
+	state.Config.MinDiscussionSec, state.Config.MaxDiscussionSec = 60, 300
+	state.DayStartedAt = now.Add(-30 * time.Second).UnixMilli()
+
+	if state.CanEndDayEarly(now) {
+		t.Fatal("expected the day to run until min discussion")
+	}
+	if !state.CanEndDayEarly(now.Add(30 * time.Second)) {
+		t.Fatal("expected the day to end early once min discussion passed")
+	}
+	if got := state.DayMinRemaining(now); got < 29*time.Second || got > 30*time.Second {
+		t.Fatalf("DayMinRemaining = %v; want ~30s", got)
+	}
+	if got := state.DayMinRemaining(now.Add(time.Minute)); got != 0 {
+		t.Fatalf("expected no min remaining after min discussion, got %v", got)
+	}
+	remaining, ok := state.DayMaxRemaining(now)
+	if !ok || remaining < 269*time.Second || remaining > 270*time.Second {
+		t.Fatalf("DayMaxRemaining = %v, %v; want ~270s", remaining, ok)
+	}
+	if remaining, _ := state.DayMaxRemaining(now.Add(time.Hour)); remaining != 0 {
+		t.Fatalf("expected an overdue day to end immediately, got %v", remaining)
+	}
+}
@@ -0,0 +1,57 @@
+// engine_force_assign.go — 调试命令：按指定角色布局开局
+//
+// force_assignments {assignments: JSON {user_id: role_id}} 用于复现 bug 报告中的具体布局：
+// 不经过 SetupAgent 选角，由 game.ForceAssignments 校验布局为合法剧本后，产生与 start_game
+// 相同的开局事件 (role.assigned、伪装、首夜行动等)。布局必须恰好覆盖大厅中全部非 DM 玩家。
+// 仅 DM/AutoDM、大厅阶段且服务器调试模式 (State.DebugMode) 可用。
+//
+// [IN]  internal/game（ForceAssignments 布局校验与 SetupResult）
+// [OUT] engine.go（HandleCommand 路由 force_assignments）
+// [POS] 测试房间的确定性开局入口
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// handleForceAssignments starts the game with a fixed role layout. DM or autodm, debug mode only.
+func handleForceAssignments(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	if !types.IsAutoDMActor(cmd.ActorUserID) && !state.Players[cmd.ActorUserID].IsDM {
+		return nil, nil, fmt.Errorf("engine.handleForceAssignments: only DM or autodm can force assignments")
+	}
+	if !state.DebugMode {
+		return nil, nil, fmt.Errorf("engine.handleForceAssignments: debug commands are disabled")
+	}
+	if state.Phase != PhaseLobby {
+		return nil, nil, fmt.Errorf("engine.handleForceAssignments: %w", ErrInvalidPhase)
+	}
+
+	var payload map[string]string
+	_ = json.Unmarshal(cmd.Payload, &payload)
+	var roles map[string]string
+	if err := json.Unmarshal([]byte(payload["assignments"]), &roles); err != nil {
+		return nil, nil, fmt.Errorf("engine.handleForceAssignments: invalid assignments: %w", err)
+	}
+
+	userIDs, seatOrder := lobbyPlayers(state)
+	if len(roles) != len(userIDs) {
+		return nil, nil, fmt.Errorf("engine.handleForceAssignments: %d roles for %d players", len(roles), len(userIDs))
+	}
+	seats := make(map[string]int, len(userIDs))
+	for i, uid := range userIDs {
+		if _, ok := roles[uid]; !ok {
+			return nil, nil, fmt.Errorf("engine.handleForceAssignments: no role for player %s", uid)
+		}
+		seats[uid] = seatOrder[i]
+	}
+
+	result, err := game.ForceAssignments(roles, seats)
+	if err != nil {
+		return nil, nil, fmt.Errorf("engine.handleForceAssignments: %w", err)
+	}
+	return buildStartGameEvents(state, cmd, result), acceptedResult(cmd.CommandID), nil
+}
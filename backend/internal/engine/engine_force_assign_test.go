@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func forceCommand(t *testing.T, layout map[string]string) map[string]string {
+	t.Helper()
+	raw, err := json.Marshal(layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]string{"assignments": string(raw)}
+}
+
+func TestForceAssignmentsStartsWithExactLayout(t *testing.T) {
+	h := newDebugLobby(t)
+	layout := map[string]string{"p1": "imp", "p2": "poisoner", "p3": "empath", "p4": "chef", "p5": "washerwoman"}
+	events := h.do("dm", "force_assignments", forceCommand(t, layout))
+
+	assigned := map[string]map[string]string{}
+	for _, e := range events {
+		if e.EventType != "role.assigned" {
+			continue
+		}
+		var p map[string]string
+		_ = json.Unmarshal(e.Payload, &p)
+		assigned[p["user_id"]] = p
+	}
+	if len(assigned) != len(layout) {
+		t.Fatalf("expected %d role.assigned events, got %d", len(layout), len(assigned))
+	}
+	for uid, role := range layout {
+		if assigned[uid]["true_role"] != role || h.state.Players[uid].TrueRole != role {
+			t.Fatalf("expected %s to be %s, got event %v state %q", uid, role, assigned[uid], h.state.Players[uid].TrueRole)
+		}
+	}
+	if assigned["p1"]["is_demon"] != "true" || assigned["p2"]["is_minion"] != "true" || assigned["p2"]["team"] != "evil" {
+		t.Fatalf("expected evil flags on the imp and poisoner: %v %v", assigned["p1"], assigned["p2"])
+	}
+	if h.state.Phase != PhaseFirstNight || h.state.DemonID != "p1" || len(h.state.NightActions) == 0 {
+		t.Fatalf("expected a valid first night with p1 as demon, phase=%s demon=%s actions=%d",
+			h.state.Phase, h.state.DemonID, len(h.state.NightActions))
+	}
+}
+
+func TestForceAssignmentsRejectsIllegalLayouts(t *testing.T) {
+	h := newDebugLobby(t)
+	for name, layout := range map[string]map[string]string{
+		"two demons":     {"p1": "imp", "p2": "imp", "p3": "empath", "p4": "chef", "p5": "washerwoman"},
+		"no minion":      {"p1": "imp", "p2": "monk", "p3": "empath", "p4": "chef", "p5": "washerwoman"},
+		"unknown role":   {"p1": "imp", "p2": "poisoner", "p3": "empath", "p4": "chef", "p5": "jester"},
+		"missing player": {"p1": "imp", "p2": "poisoner", "p3": "empath", "p4": "chef", "dm": "washerwoman"},
+		"short layout":   {"p1": "imp", "p2": "poisoner", "p3": "empath", "p4": "chef"},
+	} {
+		raw, _ := json.Marshal(forceCommand(t, layout))
+		cmd := undoCommand("dm")
+		cmd.Type, cmd.Payload = "force_assignments", raw
+		if _, _, err := HandleCommand(h.state, cmd); err == nil {
+			t.Fatalf("%s: expected force_assignments to be rejected", name)
+		}
+	}
+
+	state := h.state.Copy()
+	state.DebugMode = false
+	raw, _ := json.Marshal(forceCommand(t, map[string]string{"p1": "imp", "p2": "poisoner", "p3": "empath", "p4": "chef", "p5": "washerwoman"}))
+	cmd := undoCommand("dm")
+	cmd.Type, cmd.Payload = "force_assignments", raw
+	if _, _, err := HandleCommand(state, cmd); err == nil {
+		t.Fatal("expected force_assignments without debug mode to be rejected")
+	}
+}
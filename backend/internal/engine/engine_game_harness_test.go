@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// gameHarness drives HandleCommand and Reduce the way the room actor does:
+// every accepted command's events get the next seqs and are reduced into state.
+type gameHarness struct {
+	t     *testing.T
+	state State
+	n     int
+}
+
+func newGameHarness(t *testing.T) *gameHarness {
+	t.Helper()
+	t.Cleanup(game.SetRandomizer(func(int) (int, error) { return 0, nil }))
+	return &gameHarness{t: t, state: NewState("room-1")}
+}
+
+// do applies cmd and fails the test if the engine rejects it.
+func (h *gameHarness) do(actor, cmdType string, payload map[string]string) []types.Event {
+	h.t.Helper()
+	h.n++
+	raw, _ := json.Marshal(payload)
+	cmd := types.CommandEnvelope{
+		CommandID: fmt.Sprintf("cmd-%d", h.n), RoomID: "room-1", Type: cmdType,
+		ActorUserID: actor, Payload: raw,
+	}
+	events, _, err := HandleCommand(h.state, cmd)
+	if err != nil {
+		h.t.Fatalf("%s by %s rejected in phase %s: %v", cmdType, actor, h.state.Phase, err)
+	}
+	for _, e := range events {
+		var p map[string]string
+		_ = json.Unmarshal(e.Payload, &p)
+		h.state.Reduce(EventPayload{Seq: h.state.LastSeq + 1, EventID: e.EventID, Type: e.EventType, Actor: e.ActorUserID, Payload: p})
+	}
+	return events
+}
+
+// playerWithRole returns the user holding roleID.
+func (h *gameHarness) playerWithRole(roleID string) string {
+	h.t.Helper()
+	for uid, p := range h.state.Players {
+		if p.TrueRole == roleID {
+			return uid
+		}
+	}
+	h.t.Fatalf("no player holds %s", roleID)
+	return ""
+}
+
+// runNight submits every queued action in order until dawn; the imp kills victim.
+func (h *gameHarness) runNight(victim string) {
+	h.t.Helper()
+	for i := 0; h.state.Phase == PhaseFirstNight || h.state.Phase == PhaseNight; i++ {
+		action, ok := h.state.NextPendingNightAction()
+		if !ok || i > len(h.state.Players) {
+			h.t.Fatalf("night stalled with actions %+v", h.state.NightActions)
+		}
+		targets := []string{}
+		switch action.RoleID {
+		case "poisoner":
+			targets = []string{h.playerWithRole("chef")}
+		case "imp":
+			targets = []string{victim}
+		}
+		encoded, _ := json.Marshal(targets)
+		h.do(action.UserID, "ability.use", map[string]string{"targets": string(encoded)})
+	}
+}
+
+// executeByVote nominates nominee and has every eligible voter vote yes.
+func (h *gameHarness) executeByVote(nominator, nominee string) {
+	h.t.Helper()
+	h.do(nominator, "nominate", map[string]string{"nominee": nominee})
+	h.do("autodm", "end_defense", nil)
+	for _, voter := range h.state.Nomination.VoteOrder {
+		h.do(voter, "vote", map[string]string{"vote": "yes"})
+	}
+	if h.state.OnTheBlock == nil || h.state.OnTheBlock.UserID != nominee {
+		h.t.Fatalf("expected %s on the block, got %+v", nominee, h.state.OnTheBlock)
+	}
+	h.do("autodm", "advance_phase", map[string]string{"phase": "night"})
+}
+
+func TestFullGameEndsInGoodWinWhenImpIsExecuted(t *testing.T) {
+	h := newGameHarness(t)
+	for i := 1; i <= 5; i++ {
+		h.do(fmt.Sprintf("p%d", i), "join", map[string]string{"name": fmt.Sprintf("P%d", i)})
+	}
+	roles, _ := json.Marshal([]string{"washerwoman", "chef", "empath", "poisoner", "imp"})
+	h.do("p1", "start_game", map[string]string{"custom_roles": string(roles)})
+	if h.state.Phase != PhaseFirstNight {
+		t.Fatalf("expected first night after start, got %s", h.state.Phase)
+	}
+
+	imp, empath, washer := h.playerWithRole("imp"), h.playerWithRole("empath"), h.playerWithRole("washerwoman")
+	if h.state.DemonID != imp {
+		t.Fatalf("expected DemonID %s, got %s", imp, h.state.DemonID)
+	}
+	h.runNight("")
+	if h.state.Phase != PhaseDay || h.state.GetAliveCount() != 5 {
+		t.Fatalf("expected a peaceful first dawn, phase=%s alive=%d", h.state.Phase, h.state.GetAliveCount())
+	}
+
+	h.do("autodm", "advance_phase", map[string]string{"phase": "night"})
+	if !h.state.NoExecutionToday || h.state.Phase != PhaseNight {
+		t.Fatalf("expected a night after a day without execution, phase=%s", h.state.Phase)
+	}
+	h.runNight(empath)
+	if h.state.Phase != PhaseDay || h.state.Players[empath].Alive {
+		t.Fatalf("expected the empath killed overnight, phase=%s", h.state.Phase)
+	}
+
+	h.executeByVote(washer, imp)
+	if h.state.Phase != PhaseEnded || h.state.Winner != "good" {
+		t.Fatalf("expected good to win, phase=%s winner=%s reason=%s", h.state.Phase, h.state.Winner, h.state.WinReason)
+	}
+	if h.state.Players[imp].Alive || h.state.ExecutedToday != imp {
+		t.Fatal("expected the imp executed")
+	}
+}
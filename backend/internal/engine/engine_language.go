@@ -0,0 +1,62 @@
+// Package engine 玩家语言偏好
+//
+// 房间内任何成员可随时发送 set_language 记录自己的偏好语言 (如 "en")，空串表示清除。
+// 房间设置 translate_announcements 开启后，Auto-DM 为偏好语言与叙事语言不同的玩家
+// 私聊发送公开公告的译文 (见 agent/translation.go)。房间设置 narration_language 覆盖该房间的
+// 叙事语言，空串恢复服务端默认 (AUTODM_LANGUAGE)。
+//
+// [OUT] agent（按玩家偏好发送本地化私聊）
+// [POS] 跨语言房间的状态数据
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// maxLanguageLen bounds a language tag such as "en" or "pt-BR".
+const maxLanguageLen = 16
+
+// handleSetLanguage records the actor's preferred language.
+func handleSetLanguage(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	if _, ok := state.Players[cmd.ActorUserID]; !ok {
+		return nil, nil, fmt.Errorf("engine.handleSetLanguage: %w", ErrPlayerNotFound)
+	}
+	var payload map[string]string
+	_ = json.Unmarshal(cmd.Payload, &payload)
+	lang := strings.TrimSpace(payload["language"])
+	if len(lang) > maxLanguageLen {
+		return nil, nil, fmt.Errorf("engine.handleSetLanguage: language tag too long")
+	}
+	event := newEvent(cmd, "player.language_set", map[string]string{
+		"user_id":  cmd.ActorUserID,
+		"language": lang,
+	})
+	return []types.Event{event}, acceptedResult(cmd.CommandID), nil
+}
+
+// validateNarrationLanguage trims a room_settings narration_language and bounds its length.
+func validateNarrationLanguage(change map[string]string) error {
+	lang, ok := change["narration_language"]
+	if !ok {
+		return nil
+	}
+	lang = strings.TrimSpace(lang)
+	if len(lang) > maxLanguageLen {
+		return fmt.Errorf("engine.validateNarrationLanguage: language tag too long")
+	}
+	change["narration_language"] = lang
+	return nil
+}
+
+// reduceLanguageSet applies a player.language_set event.
+func (s *State) reduceLanguageSet(event EventPayload) {
+	userID := event.Payload["user_id"]
+	if p, ok := s.Players[userID]; ok {
+		p.Language = event.Payload["language"]
+		s.Players[userID] = p
+	}
+}
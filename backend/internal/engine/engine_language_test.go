@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestSetLanguageAndTranslateFlagReduceIntoState(t *testing.T) {
+	h := newGameHarness(t)
+	h.do("p1", "join", map[string]string{"name": "P1"})
+	h.do("p1", "set_language", map[string]string{"language": "en"})
+	h.do("p1", "room_settings", map[string]string{"translate_announcements": "true"})
+
+	if got := h.state.Players["p1"].Language; got != "en" {
+		t.Fatalf("expected language en, got %q", got)
+	}
+	if !h.state.Config.TranslateAnnouncements {
+		t.Fatal("expected translate_announcements to be enabled")
+	}
+}
+
+func TestRoomSettingsSetNarrationLanguage(t *testing.T) {
+	h := newGameHarness(t)
+	h.do("p1", "join", map[string]string{"name": "P1"})
+	h.do("p1", "room_settings", map[string]string{"narration_language": " en "})
+	if got := h.state.Config.NarrationLanguage; got != "en" {
+		t.Fatalf("expected narration language en, got %q", got)
+	}
+
+	h.do("p1", "room_settings", map[string]string{"narration_language": ""})
+	if got := h.state.Config.NarrationLanguage; got != "" {
+		t.Fatalf("expected an empty setting to restore the server default, got %q", got)
+	}
+
+	raw, _ := json.Marshal(map[string]string{"narration_language": "a-very-long-language-tag"})
+	cmd := types.CommandEnvelope{CommandID: "c9", RoomID: "room-1", Type: "room_settings", ActorUserID: "p1", Payload: raw}
+	if _, _, err := HandleCommand(h.state, cmd); err == nil {
+		t.Fatal("expected an over-long narration language to be rejected")
+	}
+}
+
+func TestSetLanguageRejectsNonMembers(t *testing.T) {
+	raw, _ := json.Marshal(map[string]string{"language": "en"})
+	cmd := types.CommandEnvelope{CommandID: "c1", RoomID: "room-1", Type: "set_language", ActorUserID: "stranger", Payload: raw}
+	if _, _, err := HandleCommand(NewState("room-1"), cmd); err == nil {
+		t.Fatal("expected set_language from a non-member to be rejected")
+	}
+}
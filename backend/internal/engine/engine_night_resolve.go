@@ -30,7 +30,7 @@ func resolveNight(state State, cmd types.CommandEnvelope) []types.Event {
 	// === 第一步：投毒者结算 ===
 	poisonTargetID := ""
 	poisonerID := ""
-	if intent, ok := intentByRole["poisoner"]; ok && len(intent.TargetIDs) > 0 {
+	if intent, ok := intentByRole["poisoner"]; ok && len(intent.TargetIDs) > 0 && state.Players[intent.UserID].Alive {
 		poisonTargetID = intent.TargetIDs[0]
 		poisonerID = intent.UserID
 		if target, exists := state.Players[poisonTargetID]; exists && target.Alive {
@@ -50,13 +50,19 @@ func resolveNight(state State, cmd types.CommandEnvelope) []types.Event {
 	monkID := ""
 	if !isFirstNight {
 		if intent, ok := intentByRole["monk"]; ok && len(intent.TargetIDs) > 0 {
-			protectTargetID = intent.TargetIDs[0]
 			monkID = intent.UserID
-			events = append(events, newEvent(cmd, "player.protected", map[string]string{
-				"user_id": protectTargetID,
-			}))
-			slog.Info("night.resolve: monk protected",
-				"target", protectTargetID, "monk", monkID)
+			// 中毒/醉酒的僧侣保护无效：不产生 player.protected，也不参与击杀结算
+			if isMalfunctioning(state, monkID, poisonTargetID) {
+				slog.Info("night.resolve: monk malfunctioning, protection negated",
+					"target", intent.TargetIDs[0], "monk", monkID)
+			} else {
+				protectTargetID = intent.TargetIDs[0]
+				events = append(events, newEvent(cmd, "player.protected", map[string]string{
+					"user_id": protectTargetID,
+				}))
+				slog.Info("night.resolve: monk protected",
+					"target", protectTargetID, "monk", monkID)
+			}
 		}
 	}
 
@@ -78,17 +84,20 @@ func resolveNight(state State, cmd types.CommandEnvelope) []types.Event {
 		killTargetID := intent.TargetIDs[0]
 		demonID := intent.UserID
 
-		// 恶魔被毒 → 刀无效（官方规则：中毒的恶魔无法杀人）
-		demonPoisoned := demonID == poisonTargetID
-		if demon, ok := state.Players[demonID]; ok && (demonPoisoned || demon.IsPoisoned) {
-			slog.Info("night.resolve: imp poisoned, kill negated",
-				"demon", demonID, "target", killTargetID)
-		} else {
-			slog.Info("night.resolve: imp attacks", "target", killTargetID, "demon", demonID)
-			killEvents := resolveDemonKill(killTargetID, demonID, poisonTargetID,
-				protectTargetID, monkID, state, cmd)
-			events = append(events, killEvents...)
+		// 恶魔被毒 → 能力失效（官方规则：中毒的恶魔无法杀人）
+		kill := demonKill{
+			TargetID:        killTargetID,
+			DemonID:         demonID,
+			PoisonTargetID:  poisonTargetID,
+			ProtectTargetID: protectTargetID,
+			MonkID:          monkID,
+			Malfunctioning:  isMalfunctioning(state, demonID, poisonTargetID),
 		}
+		slog.Info("night.resolve: imp attacks", "target", killTargetID, "demon", demonID,
+			"malfunctioning", kill.Malfunctioning)
+		events = append(events, resolveDemonKill(kill, state, cmd)...)
+		// 无论结果如何，恶魔只收到统一的"你袭击了 X"
+		events = append(events, buildDemonAttackInfo(kill, state, cmd))
 	}
 
 	// === 第五步：投毒者死亡回滚 ===
@@ -108,16 +117,56 @@ func resolveNight(state State, cmd types.CommandEnvelope) []types.Event {
 	return events
 }
 
-// resolveDemonKill 处理恶魔击杀的完整优先级链。
-func resolveDemonKill(targetID, demonID, poisonTargetID, protectTargetID, monkID string,
-	state State, cmd types.CommandEnvelope) []types.Event {
+// demonKill 描述一次恶魔击杀及其结算所需的夜间上下文。
+type demonKill struct {
+	TargetID        string
+	DemonID         string
+	PoisonTargetID  string
+	ProtectTargetID string
+	MonkID          string
+	Malfunctioning  bool // 恶魔中毒/醉酒：能力失效，不产生任何效果
+}
+
+// isMalfunctioning 判断玩家今晚能力是否失效（今晚被投毒或已处于中毒状态）。
+func isMalfunctioning(state State, userID, poisonTargetID string) bool {
+	if userID == poisonTargetID {
+		return true
+	}
+	p, ok := state.Players[userID]
+	return ok && p.IsPoisoned
+}
+
+// buildDemonAttackInfo 生成恶魔视角的结算信息。
+// 内容与击杀是否成功无关，避免泄露中毒、士兵或僧侣等失败原因。
+func buildDemonAttackInfo(kill demonKill, state State, cmd types.CommandEnvelope) types.Event {
+	name := kill.TargetID
+	if target, ok := state.Players[kill.TargetID]; ok && target.Name != "" {
+		name = target.Name
+	}
+	content, _ := json.Marshal(map[string]string{"target": kill.TargetID})
+	return newEvent(cmd, "night.info", map[string]string{
+		"user_id":   kill.DemonID,
+		"role_id":   "imp",
+		"info_type": "demon_attack",
+		"content":   string(content),
+		"message":   fmt.Sprintf("你袭击了 %s", name),
+	})
+}
 
+// resolveDemonKill 处理恶魔击杀的完整优先级链。
+func resolveDemonKill(kill demonKill, state State, cmd types.CommandEnvelope) []types.Event {
 	events := []types.Event{}
+	targetID, demonID := kill.TargetID, kill.DemonID
 	target, exists := state.Players[targetID]
 	if !exists {
 		return events
 	}
 
+	if kill.Malfunctioning {
+		slog.Info("night.resolve: imp malfunctioning, kill negated", "demon", demonID, "target", targetID)
+		return events
+	}
+
 	// 自杀：触发红唇女郎继承检查
 	if targetID == demonID {
 		events = append(events, resolveStarpass(demonID, state, cmd)...)
@@ -130,23 +179,20 @@ func resolveDemonKill(targetID, demonID, poisonTargetID, protectTargetID, monkID
 	}
 
 	// 优先级 1：士兵免疫（中毒时失效）
-	targetPoisoned := targetID == poisonTargetID
-	if target.TrueRole == "soldier" && !targetPoisoned && !target.IsPoisoned {
+	targetPoisoned := isMalfunctioning(state, targetID, kill.PoisonTargetID)
+	if target.TrueRole == "soldier" && !targetPoisoned {
 		slog.Info("night.resolve: soldier immune", "target", targetID)
 		return events
 	}
 
-	// 优先级 2：僧侣保护（僧侣自身中毒时保护无效）
-	monkPoisoned := monkID == poisonTargetID
-	if targetID == protectTargetID && monkID != "" {
-		if monk, ok := state.Players[monkID]; ok && !monkPoisoned && !monk.IsPoisoned {
-			slog.Info("night.resolve: monk protection effective", "target", targetID)
-			return events
-		}
+	// 优先级 2：僧侣保护（中毒僧侣在第二步已被排除，ProtectTargetID 为空）
+	if targetID == kill.ProtectTargetID && kill.MonkID != "" {
+		slog.Info("night.resolve: monk protection effective", "target", targetID, "monk", kill.MonkID)
+		return events
 	}
 
 	// 优先级 3：镇长转移
-	if target.TrueRole == "mayor" && !targetPoisoned && !target.IsPoisoned {
+	if target.TrueRole == "mayor" && !targetPoisoned {
 		bounceTarget := selectMayorBounceTarget(targetID, demonID, state)
 		if bounceTarget != "" {
 			slog.Info("night.resolve: mayor bounce", "from", targetID, "to", bounceTarget)
@@ -182,7 +228,7 @@ func resolveStarpass(demonID string, state State, cmd types.CommandEnvelope) []t
 		return events
 	}
 
-	aliveCount := state.GetAliveCount()
+	aliveCount := state.GetAliveResidentCount()
 	// 自杀后存活数要减 1
 	aliveCount--
 	if aliveCount < 5 {
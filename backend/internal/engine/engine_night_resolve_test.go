@@ -73,3 +73,111 @@ func TestResolveNightPoisonerSelectingDeadTargetHasNoEffect(t *testing.T) {
 		t.Fatal("expected dead poison target to behave like a skipped poison")
 	}
 }
+
+func newImpAttackState(targetRole string) State {
+	state := NewState("room-1")
+	state.Phase = PhaseNight
+	state.DemonID = "imp"
+	state.Players["imp"] = Player{UserID: "imp", Name: "Imp", TrueRole: "imp", Alive: true, SeatNumber: 1, Team: "evil"}
+	state.Players["victim"] = Player{UserID: "victim", Name: "Victim", TrueRole: targetRole, Alive: true, SeatNumber: 2, Team: "good"}
+	state.NightActions = []NightAction{{
+		UserID:     "imp",
+		RoleID:     "imp",
+		Completed:  true,
+		TargetIDs:  []string{"victim"},
+		ActionType: "select_one",
+	}}
+	return state
+}
+
+func TestResolveNightPoisonedImpTargetSurvives(t *testing.T) {
+	state := newImpAttackState("chef")
+	state.Players["poisoner"] = Player{UserID: "poisoner", TrueRole: "poisoner", Alive: true, SeatNumber: 3, Team: "evil"}
+	state.NightActions = append([]NightAction{{
+		UserID:     "poisoner",
+		RoleID:     "poisoner",
+		Completed:  true,
+		TargetIDs:  []string{"imp"},
+		ActionType: "select_one",
+	}}, state.NightActions...)
+
+	events := resolveNight(state, types.CommandEnvelope{CommandID: "cmd-4", ActorUserID: "imp", RoomID: state.RoomID})
+	if isPlayerDiedInEvents("victim", events) {
+		t.Fatal("expected poisoned imp's target to survive")
+	}
+	info := findEventPayload(t, events, "night.info")
+	if info["user_id"] != "imp" || info["message"] != "你袭击了 Victim" {
+		t.Fatalf("expected generic attack message for imp, got %v", info)
+	}
+}
+
+func TestResolveNightImpAttackMessageIndependentOfOutcome(t *testing.T) {
+	poisoned := newImpAttackState("chef")
+	imp := poisoned.Players["imp"]
+	imp.IsPoisoned = true
+	poisoned.Players["imp"] = imp
+
+	cmd := types.CommandEnvelope{CommandID: "cmd-5", ActorUserID: "imp", RoomID: "room-1"}
+	failed := resolveNight(poisoned, cmd)
+	succeeded := resolveNight(newImpAttackState("chef"), cmd)
+
+	if isPlayerDiedInEvents("victim", failed) {
+		t.Fatal("expected already-poisoned imp's kill to fail")
+	}
+	if !isPlayerDiedInEvents("victim", succeeded) {
+		t.Fatal("expected healthy imp's kill to succeed")
+	}
+	failedInfo := findEventPayload(t, failed, "night.info")
+	succeededInfo := findEventPayload(t, succeeded, "night.info")
+	if failedInfo["message"] != succeededInfo["message"] || failedInfo["content"] != succeededInfo["content"] {
+		t.Fatalf("expected identical demon info, got %v vs %v", failedInfo, succeededInfo)
+	}
+}
+
+func withMonkProtecting(state State, targetID string, monkPoisoned bool) State {
+	state.Players["monk"] = Player{UserID: "monk", TrueRole: "monk", Alive: true, SeatNumber: 4, Team: "good", IsPoisoned: monkPoisoned}
+	state.NightActions = append([]NightAction{{
+		UserID:     "monk",
+		RoleID:     "monk",
+		Completed:  true,
+		TargetIDs:  []string{targetID},
+		ActionType: "select_one",
+	}}, state.NightActions...)
+	return state
+}
+
+func TestResolveNightMonkAndSoldierInteractions(t *testing.T) {
+	cases := []struct {
+		name          string
+		targetRole    string
+		monkPoisoned  bool
+		wantSurvive   bool
+		wantProtected bool
+	}{
+		{name: "poisoned monk cannot save townsfolk", targetRole: "chef", monkPoisoned: true, wantSurvive: false, wantProtected: false},
+		{name: "poisoned monk target soldier still immune", targetRole: "soldier", monkPoisoned: true, wantSurvive: true, wantProtected: false},
+		{name: "monk protected soldier survives", targetRole: "soldier", monkPoisoned: false, wantSurvive: true, wantProtected: true},
+		{name: "monk protected townsfolk survives", targetRole: "chef", monkPoisoned: false, wantSurvive: true, wantProtected: true},
+	}
+
+	cmd := types.CommandEnvelope{CommandID: "cmd-6", ActorUserID: "imp", RoomID: "room-1"}
+	baseline := findEventPayload(t, resolveNight(newImpAttackState("chef"), cmd), "night.info")
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := withMonkProtecting(newImpAttackState(tc.targetRole), "victim", tc.monkPoisoned)
+			events := resolveNight(state, cmd)
+
+			if survived := !isPlayerDiedInEvents("victim", events); survived != tc.wantSurvive {
+				t.Fatalf("expected survive=%v, got %v", tc.wantSurvive, survived)
+			}
+			if got := hasTestEventType(events, "player.protected"); got != tc.wantProtected {
+				t.Fatalf("expected player.protected=%v, got %v", tc.wantProtected, got)
+			}
+			info := findEventPayload(t, events, "night.info")
+			if info["message"] != baseline["message"] || info["content"] != baseline["content"] {
+				t.Fatalf("expected demon info to match baseline %v, got %v", baseline, info)
+			}
+		})
+	}
+}
@@ -35,18 +35,15 @@ func buildFirstPrompt(cmd types.CommandEnvelope, nightActions []NightAction) []t
 // buildNextPrompt emits a night.action.prompt for the next uncompleted
 // action after the just-completed one. Returns nil if all done.
 func buildNextPrompt(cmd types.CommandEnvelope, nightActions []NightAction, justCompletedUserID string) []types.Event {
-	passed := false
-	for _, a := range nightActions {
-		if a.UserID == justCompletedUserID && !passed {
-			passed = true
-			continue
-		}
-		if !passed {
-			continue
-		}
-		if a.Completed {
-			continue
+	remaining := make([]NightAction, len(nightActions))
+	copy(remaining, nightActions)
+	for i, a := range remaining {
+		if a.UserID == justCompletedUserID && !a.Completed {
+			remaining[i].Completed = true
+			break
 		}
+	}
+	if a, ok := nextPendingAction(remaining); ok {
 		slog.Info("night.seq: buildNextPrompt",
 			"justCompleted", justCompletedUserID,
 			"nextUser", a.UserID, "nextRole", a.RoleID, "order", a.Order)
@@ -56,6 +53,28 @@ func buildNextPrompt(cmd types.CommandEnvelope, nightActions []NightAction, just
 	return nil
 }
 
+// NextPendingNightAction returns the uncompleted night action with the lowest order
+// (queue position breaks ties). ok is false when every action is done.
+func (s State) NextPendingNightAction() (NightAction, bool) {
+	return nextPendingAction(s.NightActions)
+}
+
+func nextPendingAction(actions []NightAction) (NightAction, bool) {
+	best := -1
+	for i, a := range actions {
+		if a.Completed {
+			continue
+		}
+		if best < 0 || a.Order < actions[best].Order {
+			best = i
+		}
+	}
+	if best < 0 {
+		return NightAction{}, false
+	}
+	return actions[best], true
+}
+
 // buildPromptEvent creates a night.action.prompt event for a specific
 // player, telling the frontend to open their ability panel.
 func buildPromptEvent(cmd types.CommandEnvelope, a NightAction) types.Event {
@@ -70,23 +89,20 @@ func buildPromptEvent(cmd types.CommandEnvelope, a NightAction) types.Event {
 		"role_id":     a.RoleID,
 		"order":       fmt.Sprintf("%d", a.Order),
 		"action_type": actionType,
+		"prompt":      game.NightPromptCN(a.RoleID),
 	})
 }
 
 // validateCurrentNightAction strictly enforces that only the next
-// uncompleted action's player may submit an ability. Scans the
-// NightActions array to find the first uncompleted action rather than
-// trusting CurrentAction index (which can desync with auto-completions).
+// uncompleted action's player may submit an ability. Uses
+// NextPendingNightAction rather than trusting CurrentAction index
+// (which can desync with auto-completions).
 func validateCurrentNightAction(state State, actorID string) error {
 	if len(state.NightActions) == 0 {
 		return nil // No actions queued; allow (shouldn't happen in practice)
 	}
-	// Find first uncompleted action by scanning
-	for _, a := range state.NightActions {
-		if a.Completed {
-			continue
-		}
-		// Found the first uncompleted action — must be the actor
+	if a, ok := state.NextPendingNightAction(); ok {
+		// The lowest-order uncompleted action must be the actor's
 		if a.UserID != actorID {
 			slog.Warn("night.seq: validateCurrentNightAction rejected",
 				"actor", actorID, "expected", a.UserID, "role", a.RoleID, "order", a.Order)
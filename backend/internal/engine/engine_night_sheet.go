@@ -0,0 +1,77 @@
+// engine_night_sheet.go — 说书人夜晚顺序表
+//
+// BuildNightSheet 把本夜 (或最近一夜) 的 NightActions 按角色目录的夜晚顺序排列：
+// 首夜用 Role.FirstNightOrder，其他夜晚用 Role.OtherNightOrder (目录缺失时退回 NightAction.Order)，
+// 每行带玩家座位、存活与完成状态。只读，不产生事件。
+//
+// [IN]  internal/game（角色目录的夜晚顺序）
+// [OUT] api（GET /v1/rooms/{room_id}/night-sheet）
+// [POS] 面向 DM 的夜晚行动总览
+package engine
+
+import (
+	"sort"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+)
+
+// NightSheet is the storyteller's night-order sheet.
+type NightSheet struct {
+	Night      int               `json:"night"`
+	FirstNight bool              `json:"first_night"`
+	Entries    []NightSheetEntry `json:"entries"`
+}
+
+// NightSheetEntry is one wake on the sheet.
+type NightSheetEntry struct {
+	Order      int    `json:"order"`
+	RoleID     string `json:"role_id"`
+	RoleName   string `json:"role_name"`
+	UserID     string `json:"user_id"`
+	PlayerName string `json:"player_name"`
+	Seat       int    `json:"seat"`
+	Alive      bool   `json:"alive"`
+	ActionType string `json:"action_type,omitempty"`
+	Completed  bool   `json:"completed"`
+}
+
+// BuildNightSheet lists the night's queued actions in the catalog's wake order.
+func BuildNightSheet(state State) NightSheet {
+	sheet := NightSheet{Night: state.NightCount, FirstNight: state.NightCount <= 1, Entries: []NightSheetEntry{}}
+	for _, a := range state.NightActions {
+		p := state.Players[a.UserID]
+		entry := NightSheetEntry{
+			Order:      nightSheetOrder(a, sheet.FirstNight),
+			RoleID:     a.RoleID,
+			RoleName:   a.RoleID,
+			UserID:     a.UserID,
+			PlayerName: p.Name,
+			Seat:       p.SeatNumber,
+			Alive:      p.Alive,
+			ActionType: nightActionType(a),
+			Completed:  a.Completed,
+		}
+		if role := game.GetRoleByID(a.RoleID); role != nil {
+			entry.RoleName = role.Name
+		}
+		sheet.Entries = append(sheet.Entries, entry)
+	}
+	sort.SliceStable(sheet.Entries, func(i, j int) bool { return sheet.Entries[i].Order < sheet.Entries[j].Order })
+	return sheet
+}
+
+// nightSheetOrder is the catalog's wake order for the action's role on this kind of night.
+func nightSheetOrder(a NightAction, firstNight bool) int {
+	role := game.GetRoleByID(a.RoleID)
+	if role == nil {
+		return a.Order
+	}
+	order := role.OtherNightOrder
+	if firstNight {
+		order = role.FirstNightOrder
+	}
+	if order == 0 {
+		return a.Order
+	}
+	return order
+}
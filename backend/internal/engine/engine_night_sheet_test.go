@@ -0,0 +1,31 @@
+package engine
+
+import "testing"
+
+func TestNightSheetOrdersOtherNightsByCatalog(t *testing.T) {
+	state := NewState("room-1")
+	state.Phase = PhaseNight
+	state.NightCount = 3
+	for i, uid := range []string{"fortuneteller", "poisoner", "imp"} {
+		state.Players[uid] = Player{UserID: uid, Name: uid, TrueRole: uid, SeatNumber: i + 1, Alive: true}
+	}
+	// Queued out of catalog order on purpose
+	state.NightActions = []NightAction{
+		{UserID: "fortuneteller", RoleID: "fortuneteller", Order: 1, Completed: true},
+		{UserID: "imp", RoleID: "imp", Order: 2},
+		{UserID: "poisoner", RoleID: "poisoner", Order: 3},
+	}
+
+	sheet := BuildNightSheet(state)
+	if sheet.FirstNight || sheet.Night != 3 || len(sheet.Entries) != 3 {
+		t.Fatalf("unexpected sheet %+v", sheet)
+	}
+	got := []string{sheet.Entries[0].RoleID, sheet.Entries[1].RoleID, sheet.Entries[2].RoleID}
+	if got[0] != "poisoner" || got[2] != "fortuneteller" {
+		t.Fatalf("expected the Poisoner to wake before the Fortune Teller, got %v", got)
+	}
+	ft := sheet.Entries[2]
+	if !ft.Completed || ft.Seat != 1 || ft.ActionType != "select_two" {
+		t.Fatalf("expected the Fortune Teller row to carry seat and status, got %+v", ft)
+	}
+}
@@ -25,7 +25,7 @@ func finalizeNightFromCompletions(state State, cmd types.CommandEnvelope,
 	workingState := state.Copy()
 	applyEventsToState(&workingState, completionEvents)
 
-	resolveEvents := resolveNight(workingState, cmd)
+	resolveEvents := orderDawnDeaths(workingState, resolveNight(workingState, cmd))
 	events := append([]types.Event{}, resolveEvents...)
 
 	resolvedState := workingState.Copy()
@@ -33,7 +33,7 @@ func finalizeNightFromCompletions(state State, cmd types.CommandEnvelope,
 
 	infoEvents := distributeNightInfo(resolvedState, cmd)
 	events = append(events, infoEvents...)
-	events = append(events, newEvent(cmd, "phase.day", buildPhaseDayPayload(resolvedState, resolveEvents)))
+	events = append(events, buildDawnEvents(cmd, resolvedState, resolveEvents)...)
 
 	winEvents := checkWinCondition(resolvedState, cmd)
 	events = append(events, winEvents...)
@@ -49,7 +49,9 @@ func applyEventsToState(state *State, events []types.Event) {
 		}
 		state.Reduce(EventPayload{
 			Seq:     event.Seq,
+			EventID: event.EventID,
 			Type:    event.EventType,
+			Actor:   event.ActorUserID,
 			Payload: payload,
 		})
 	}
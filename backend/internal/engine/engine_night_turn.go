@@ -0,0 +1,37 @@
+// engine_night_turn.go — 夜晚"下一个行动者"通知
+//
+// 每当一个夜晚行动完成，若夜晚仍在进行，按行动顺序找出下一个未完成的行动，
+// 生成 night.turn {user_id, role_id, order}，仅投影给该玩家，提示轮到其行动。
+//
+// [IN]  internal/types（Command/Event 类型）
+// [OUT] room（handleCommand 在持久化前追加 night.turn）
+// [POS] 夜晚行动顺序的玩家侧提示
+package engine
+
+import (
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// NightTurnEvent returns a night.turn naming the next actor when events complete a night
+// action and the night is still running. ok is false otherwise.
+func NightTurnEvent(state State, cmd types.CommandEnvelope, events []types.Event) (types.Event, bool) {
+	if !hasEventType(events, "night.action.completed") {
+		return types.Event{}, false
+	}
+	next := state.Copy()
+	applyEventsToState(&next, events)
+	if next.Phase != PhaseNight && next.Phase != PhaseFirstNight {
+		return types.Event{}, false
+	}
+	action, ok := next.NextPendingNightAction()
+	if !ok {
+		return types.Event{}, false
+	}
+	return newEvent(cmd, "night.turn", map[string]string{
+		"user_id": action.UserID,
+		"role_id": action.RoleID,
+		"order":   fmt.Sprintf("%d", action.Order),
+	}), true
+}
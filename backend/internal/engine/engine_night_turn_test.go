@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestNextPendingNightActionUsesLowestOrder(t *testing.T) {
+	state := newStalledNightState()
+	state.NightActions = []NightAction{
+		{UserID: "imp", RoleID: "imp", Order: 2, ActionType: "select_one"},
+		{UserID: "monk", RoleID: "monk", Order: 1, ActionType: "select_one"},
+	}
+	if a, ok := state.NextPendingNightAction(); !ok || a.UserID != "monk" {
+		t.Fatalf("expected monk (order 1) to act first, got %v ok=%v", a, ok)
+	}
+	state.NightActions[1].Completed = true
+	if a, ok := state.NextPendingNightAction(); !ok || a.UserID != "imp" {
+		t.Fatalf("expected imp next, got %v ok=%v", a, ok)
+	}
+	state.NightActions[0].Completed = true
+	if _, ok := state.NextPendingNightAction(); ok {
+		t.Fatal("expected no pending action once all are completed")
+	}
+}
+
+func TestCompletingFirstActionSurfacesSecondAsNightTurn(t *testing.T) {
+	state := newStalledNightState()
+	payload, _ := json.Marshal(map[string]string{"targets": `["chef"]`})
+	cmd := types.CommandEnvelope{CommandID: "cmd-monk", RoomID: "room-1", Type: "ability.use", ActorUserID: "monk", Payload: payload}
+
+	events, _, err := HandleCommand(state, cmd)
+	if err != nil {
+		t.Fatalf("monk ability failed: %v", err)
+	}
+	turn, ok := NightTurnEvent(state, cmd, events)
+	if !ok {
+		t.Fatal("expected a night.turn after the monk acted")
+	}
+	var p map[string]string
+	_ = json.Unmarshal(turn.Payload, &p)
+	if turn.EventType != "night.turn" || p["user_id"] != "imp" || p["order"] != "2" {
+		t.Fatalf("expected night.turn for imp order 2, got %s %v", turn.EventType, p)
+	}
+
+	applyEventsToState(&state, events)
+	if a, _ := state.NextPendingNightAction(); a.UserID != "imp" {
+		t.Fatalf("expected imp to be next pending, got %v", a)
+	}
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func newQuietDayState(roles map[string]string) State {
+	state := NewState("room-1")
+	state.Phase = PhaseDay
+	state.DayCount = 2
+	state.NightCount = 1
+	state.DemonID = "imp"
+	seat := 1
+	for uid, role := range roles {
+		team := "good"
+		if role == "imp" {
+			team = "evil"
+		}
+		state.Players[uid] = Player{UserID: uid, TrueRole: role, Team: team, Alive: true, SeatNumber: seat}
+		state.SeatOrder = append(state.SeatOrder, uid)
+		seat++
+	}
+	return state
+}
+
+func advanceToNight(t *testing.T, state State) []types.Event {
+	t.Helper()
+	events, _, err := handleAdvancePhase(state, types.CommandEnvelope{
+		CommandID: "cmd-night", ActorUserID: "autodm", RoomID: state.RoomID,
+		Payload: json.RawMessage(`{"phase":"night"}`),
+	})
+	if err != nil {
+		t.Fatalf("advance phase: %v", err)
+	}
+	return events
+}
+
+func TestQuietDayEmitsNoExecutionAndUndertakerSeesIt(t *testing.T) {
+	state := newQuietDayState(map[string]string{
+		"imp": "imp", "undertaker": "undertaker", "chef": "chef", "monk": "monk", "empath": "empath",
+	})
+
+	events := advanceToNight(t, state)
+	if findEventPayload(t, events, "day.no_execution")["day"] != "2" {
+		t.Fatal("expected day.no_execution for day 2")
+	}
+	applyEventsToState(&state, events)
+	if !state.NoExecutionToday || state.ExecutedToday != "" {
+		t.Fatalf("expected reducer to record no execution, got %v %q", state.NoExecutionToday, state.ExecutedToday)
+	}
+
+	state.NightActions = []NightAction{{UserID: "undertaker", RoleID: "undertaker", ActionType: "info", Completed: true}}
+	info := findEventPayload(t, distributeNightInfo(state, types.CommandEnvelope{CommandID: "cmd-info"}), "night.info")
+	if info["user_id"] != "undertaker" || info["message"] != "今天没有玩家被处决" {
+		t.Fatalf("expected undertaker to learn there was no execution, got %v", info)
+	}
+}
+
+func TestExecutionDaySkipsNoExecutionEvent(t *testing.T) {
+	state := newQuietDayState(map[string]string{
+		"imp": "imp", "chef": "chef", "monk": "monk", "empath": "empath", "undertaker": "undertaker",
+	})
+	state.OnTheBlock = &OnTheBlockInfo{UserID: "chef", VotesFor: 3}
+
+	events := advanceToNight(t, state)
+	if hasTestEventType(events, "day.no_execution") {
+		t.Fatal("expected no day.no_execution when someone is executed")
+	}
+}
+
+func TestQuietDayWithThreeAliveGivesMayorWin(t *testing.T) {
+	state := newQuietDayState(map[string]string{"imp": "imp", "mayor": "mayor", "chef": "chef"})
+
+	ended := findEventPayload(t, advanceToNight(t, state), "game.ended")
+	if ended["winner"] != "good" {
+		t.Fatalf("expected mayor win for good, got %v", ended)
+	}
+}
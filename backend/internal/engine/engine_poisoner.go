@@ -0,0 +1,81 @@
+// engine_poisoner.go — 投毒者边界情况
+//
+// 死亡的投毒者不再下毒：提交的目标被忽略，排队中的行动轮到时自动以空目标完成
+// (reason=dead)，不阻塞夜晚。Config.ForbidSelfPoison (room_settings 的 forbid_self_poison) 开启时拒绝对自己下毒。
+//
+// [IN]  internal/types（Command/Event 类型）
+// [OUT] engine.go（handleAbility 前置校验与收尾）
+// [POS] 夜晚收集层对投毒者的前置规则
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// poisonerTargets applies the poisoner rules to submitted targets: a dead poisoner's
+// targets are dropped, and self-poison is rejected when Config.ForbidSelfPoison is set.
+func poisonerTargets(state State, actorID string, targetIDs []string) ([]string, error) {
+	player := state.Players[actorID]
+	if player.TrueRole != "poisoner" {
+		return targetIDs, nil
+	}
+	if !player.Alive {
+		return []string{}, nil
+	}
+	if state.Config.ForbidSelfPoison {
+		for _, t := range targetIDs {
+			if t == actorID {
+				return nil, fmt.Errorf("engine.poisonerTargets: poisoner cannot target themselves")
+			}
+		}
+	}
+	return targetIDs, nil
+}
+
+// autoCompleteDeadPoisoner completes the next action as a no-op when it belongs to a dead
+// poisoner, dropping the prompt that would have woken them.
+func autoCompleteDeadPoisoner(state State, cmd types.CommandEnvelope, events []types.Event) []types.Event {
+	working := state.Copy()
+	applyEventsToState(&working, events)
+	if working.Phase != PhaseNight && working.Phase != PhaseFirstNight {
+		return events
+	}
+	next, ok := working.NextPendingNightAction()
+	if !ok || next.RoleID != "poisoner" || working.Players[next.UserID].Alive {
+		return events
+	}
+
+	skipCmd := cmd
+	skipCmd.ActorUserID = next.UserID
+	skipCmd.Payload, _ = json.Marshal(map[string]string{"targets": "[]"})
+	more, _, err := handleAbility(working, skipCmd)
+	if err != nil {
+		return events
+	}
+	for i := range more {
+		if more[i].EventType == "night.action.completed" {
+			more[i] = withPayloadField(more[i], "reason", "dead")
+			break
+		}
+	}
+	return append(withoutPromptFor(events, next.UserID), more...)
+}
+
+// withoutPromptFor removes night.action.prompt events addressed to userID.
+func withoutPromptFor(events []types.Event, userID string) []types.Event {
+	kept := make([]types.Event, 0, len(events))
+	for _, e := range events {
+		if e.EventType == "night.action.prompt" {
+			var payload map[string]string
+			_ = json.Unmarshal(e.Payload, &payload)
+			if payload["user_id"] == userID {
+				continue
+			}
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
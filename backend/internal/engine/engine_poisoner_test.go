@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// newPoisonerNightState queues monk, poisoner, imp on night 2.
+func newPoisonerNightState() State {
+	state := newStalledNightState()
+	state.Players["poisoner"] = Player{UserID: "poisoner", TrueRole: "poisoner", Team: "evil", Alive: true, SeatNumber: 4}
+	state.SeatOrder = append(state.SeatOrder, "poisoner")
+	state.NightActions = []NightAction{
+		{UserID: "monk", RoleID: "monk", Order: 1, ActionType: "select_one"},
+		{UserID: "poisoner", RoleID: "poisoner", Order: 2, ActionType: "select_one"},
+		{UserID: "imp", RoleID: "imp", Order: 3, ActionType: "select_one"},
+	}
+	return state
+}
+
+func abilityCommand(actor string, targets ...string) types.CommandEnvelope {
+	t, _ := json.Marshal(targets)
+	payload, _ := json.Marshal(map[string]string{"targets": string(t)})
+	return types.CommandEnvelope{CommandID: "cmd-" + actor, RoomID: "room-1", Type: "ability.use", ActorUserID: actor, Payload: payload}
+}
+
+func TestDeadPoisonerActionIsSkippedAsNoOp(t *testing.T) {
+	state := newPoisonerNightState()
+	p := state.Players["poisoner"]
+	p.Alive = false
+	state.Players["poisoner"] = p
+
+	events, _, err := HandleCommand(state, abilityCommand("monk", "chef"))
+	if err != nil {
+		t.Fatalf("monk ability failed: %v", err)
+	}
+	var skipped map[string]string
+	for _, e := range events {
+		var payload map[string]string
+		_ = json.Unmarshal(e.Payload, &payload)
+		if e.EventType == "night.action.prompt" && payload["user_id"] == "poisoner" {
+			t.Fatal("a dead poisoner must not be prompted")
+		}
+		if e.EventType == "night.action.completed" && payload["user_id"] == "poisoner" {
+			skipped = payload
+		}
+	}
+	if skipped == nil || skipped["reason"] != "dead" || skipped["targets"] != "[]" {
+		t.Fatalf("expected the dead poisoner's action auto-completed as a no-op, got %v", skipped)
+	}
+
+	applyEventsToState(&state, events)
+	if next, ok := state.NextPendingNightAction(); !ok || next.UserID != "imp" {
+		t.Fatalf("expected imp to act next, got %v ok=%v", next, ok)
+	}
+}
+
+func TestDeadPoisonerSubmissionPoisonsNobody(t *testing.T) {
+	state := newPoisonerNightState()
+	state.NightActions[0].Completed = true
+	state.NightActions[2].Completed = true
+	p := state.Players["poisoner"]
+	p.Alive = false
+	state.Players["poisoner"] = p
+
+	events, _, err := HandleCommand(state, abilityCommand("poisoner", "chef"))
+	if err != nil {
+		t.Fatalf("dead poisoner submission failed: %v", err)
+	}
+	if hasTestEventType(events, "player.poisoned") {
+		t.Fatal("a dead poisoner must not poison anyone")
+	}
+}
+
+func TestPoisonerSelfTargetFollowsConfig(t *testing.T) {
+	state := newPoisonerNightState()
+	state.NightActions[0].Completed = true
+
+	if _, _, err := HandleCommand(state, abilityCommand("poisoner", "poisoner")); err != nil {
+		t.Fatalf("self-poison should be allowed by default: %v", err)
+	}
+	state.Config.ForbidSelfPoison = true
+	if _, _, err := HandleCommand(state, abilityCommand("poisoner", "poisoner")); err == nil {
+		t.Fatal("expected self-poison to be rejected when ForbidSelfPoison is set")
+	}
+}
+
+func TestRoomSettingsToggleForbidSelfPoison(t *testing.T) {
+	for _, tt := range []struct {
+		setting string
+		reject  bool
+	}{
+		{"true", true},
+		{"false", false},
+	} {
+		t.Run("forbid_self_poison="+tt.setting, func(t *testing.T) {
+			events, _, err := HandleCommand(NewState("room-1"), presenceCommand("room_settings", "p1", map[string]string{"forbid_self_poison": tt.setting}))
+			if err != nil {
+				t.Fatalf("room_settings: %v", err)
+			}
+			state := newPoisonerNightState()
+			state.Config.ForbidSelfPoison = !tt.reject // the setting must override the prior value
+			applyEventsToState(&state, events)
+			if state.Config.ForbidSelfPoison != tt.reject {
+				t.Fatalf("ForbidSelfPoison = %v, want %v", state.Config.ForbidSelfPoison, tt.reject)
+			}
+			state.NightActions[0].Completed = true
+
+			_, _, err = HandleCommand(state, abilityCommand("poisoner", "poisoner"))
+			if tt.reject && err == nil {
+				t.Fatal("expected self-poison to be rejected with forbid_self_poison on")
+			}
+			if !tt.reject && err != nil {
+				t.Fatalf("expected self-poison to be allowed with forbid_self_poison off, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Package engine 对局中玩家的断线、重连与离场
+//
+// 血染钟楼的玩家断线后仍然在座：disconnect (或对局中的 leave) 产生 player.disconnected，
+// 只置 Player.Disconnected，不移出 SeatOrder、不改存活与角色；join/reconnect 对断线玩家
+// 产生 player.reconnected，对在线玩家为无事件的成功。DM 用 remove_player 显式标记玩家
+// 离场 (Player.Departed = traveling/removed)：与旅行者被放逐一样按死亡处理 (不再存活、
+// 没有幽灵票)，投票、夜晚行动队列与胜负判定因此都不再计入；座位与角色保留供魔典与复盘使用。
+// WebSocket 最后一个订阅断开/重新订阅时由 realtime 派发 disconnect/reconnect。
+//
+// [OUT] engine.go（join/leave/disconnect/reconnect/remove_player 路由）
+// [OUT] state_reduce.go（reducePresence）
+// [POS] 命令层的玩家在场状态
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// departureStatuses are the explicit departures a DM may record.
+var departureStatuses = map[string]bool{"traveling": true, "removed": true}
+
+// handleDisconnect marks a seated player absent for the rest of their connection loss.
+func handleDisconnect(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	p, ok := state.Players[cmd.ActorUserID]
+	if !ok {
+		return nil, nil, fmt.Errorf("engine.handleDisconnect: %w", ErrPlayerNotFound)
+	}
+	if p.Disconnected {
+		return nil, acceptedResult(cmd.CommandID), nil
+	}
+	return []types.Event{newEvent(cmd, "player.disconnected", map[string]string{"user_id": p.UserID})}, acceptedResult(cmd.CommandID), nil
+}
+
+// handleReconnect marks a disconnected player present again; present players are a no-op.
+func handleReconnect(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	p, ok := state.Players[cmd.ActorUserID]
+	if !ok {
+		return nil, nil, fmt.Errorf("engine.handleReconnect: %w", ErrPlayerNotFound)
+	}
+	if !p.Disconnected {
+		return nil, acceptedResult(cmd.CommandID), nil
+	}
+	return []types.Event{newEvent(cmd, "player.reconnected", map[string]string{"user_id": p.UserID})}, acceptedResult(cmd.CommandID), nil
+}
+
+// handleRemovePlayer lets the DM record that a player has left the game for good.
+func handleRemovePlayer(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	if !IsHumanDM(state, cmd.ActorUserID) && cmd.ActorUserID != state.OwnerID {
+		return nil, nil, fmt.Errorf("engine.handleRemovePlayer: only the DM or room owner can remove players")
+	}
+	var payload map[string]string
+	_ = json.Unmarshal(cmd.Payload, &payload)
+	target, ok := state.Players[payload["user_id"]]
+	if !ok || target.IsDM {
+		return nil, nil, fmt.Errorf("engine.handleRemovePlayer: %w", ErrPlayerNotFound)
+	}
+	status := payload["status"]
+	if status == "" {
+		status = "removed"
+	}
+	if !departureStatuses[status] {
+		return nil, nil, fmt.Errorf("engine.handleRemovePlayer: unknown status %q", status)
+	}
+	return []types.Event{newEvent(cmd, "player.removed", map[string]string{
+		"user_id": target.UserID,
+		"status":  status,
+	})}, acceptedResult(cmd.CommandID), nil
+}
+
+// reducePresence applies presence events; the seat and role are untouched, and only a
+// removal ends the player's life and ghost vote.
+func (s *State) reducePresence(event EventPayload) {
+	uid := event.Payload["user_id"]
+	p, ok := s.Players[uid]
+	if !ok {
+		return
+	}
+	switch event.Type {
+	case "player.disconnected":
+		p.Disconnected = true
+	case "player.reconnected":
+		p.Disconnected = false
+	case "player.removed":
+		p.Departed = event.Payload["status"]
+		p.Alive = false
+		p.HasGhostVote = false
+	}
+	s.Players[uid] = p
+}
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func presenceCommand(cmdType, actor string, payload map[string]string) types.CommandEnvelope {
+	raw, _ := json.Marshal(payload)
+	return types.CommandEnvelope{CommandID: cmdType + "-" + actor, RoomID: "room-1", Type: cmdType, ActorUserID: actor, Payload: raw}
+}
+
+func TestMidGameDisconnectKeepsSeatAndRole(t *testing.T) {
+	state := newStalledNightState()
+	seats := append([]string(nil), state.SeatOrder...)
+
+	events, _, err := HandleCommand(state, presenceCommand("leave", "chef", nil))
+	if err != nil {
+		t.Fatalf("mid-game leave: %v", err)
+	}
+	applyEventsToState(&state, events)
+
+	chef := state.Players["chef"]
+	if !chef.Disconnected || !chef.Alive || chef.TrueRole != "chef" || chef.SeatNumber != 3 {
+		t.Fatalf("expected the chef seated, alive and disconnected, got %+v", chef)
+	}
+	if len(state.SeatOrder) != len(seats) || state.SeatOrder[2] != "chef" {
+		t.Fatalf("expected the seat order unchanged, got %v", state.SeatOrder)
+	}
+
+	events, _, err = HandleCommand(state, presenceCommand("join", "chef", nil))
+	if err != nil {
+		t.Fatalf("rejoin: %v", err)
+	}
+	applyEventsToState(&state, events)
+	if state.Players["chef"].Disconnected || len(events) != 1 || events[0].EventType != "player.reconnected" {
+		t.Fatalf("expected a rejoin to reconnect the chef, got %v", events)
+	}
+}
+
+func TestDMMarksPlayerTraveling(t *testing.T) {
+	state := newStalledNightState()
+	state.Players["dm"] = Player{UserID: "dm", IsDM: true}
+
+	if _, _, err := HandleCommand(state, presenceCommand("remove_player", "monk", map[string]string{"user_id": "chef"})); err == nil {
+		t.Fatal("expected a player to be refused removing others")
+	}
+	events, _, err := HandleCommand(state, presenceCommand("remove_player", "dm", map[string]string{"user_id": "chef", "status": "traveling"}))
+	if err != nil {
+		t.Fatalf("remove_player: %v", err)
+	}
+	applyEventsToState(&state, events)
+	if chef := state.Players["chef"]; chef.Departed != "traveling" || chef.TrueRole != "chef" {
+		t.Fatalf("expected the chef marked traveling with their role kept, got %+v", chef)
+	}
+	if chef := state.Players["chef"]; chef.Alive || chef.HasGhostVote {
+		t.Fatalf("expected a departed player out of votes, night actions and win checks, got %+v", chef)
+	}
+}
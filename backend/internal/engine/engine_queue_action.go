@@ -0,0 +1,73 @@
+// engine_queue_action.go — DM 手动插入夜晚行动
+//
+// 自定义角色或特殊情况下，说书人需要追加 setup 未排入的夜晚行动。
+// queue_night_action 仅 DM / AutoDM 可用，产生与开局相同的 night.action.queued 事件，
+// 追加到 NightActions 末尾，按既有顺序逻辑在轮到时提示玩家。
+//
+// [IN]  internal/game（角色默认行动类型）
+// [IN]  internal/types（Command/Event 类型）
+// [POS] 夜晚行动队列的 DM 覆盖入口
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// handleQueueNightAction appends a night action for user_id with role_id.
+// Optional payload: action_type (defaults to the role's night action type), order.
+func handleQueueNightAction(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	isAutoDM := types.IsAutoDMActor(cmd.ActorUserID)
+	isDM := state.Players[cmd.ActorUserID].IsDM
+	if !isAutoDM && !isDM {
+		return nil, nil, fmt.Errorf("engine.handleQueueNightAction: only DM or autodm can queue night actions")
+	}
+	if state.Phase != PhaseNight && state.Phase != PhaseFirstNight {
+		return nil, nil, fmt.Errorf("engine.handleQueueNightAction: %w", ErrInvalidPhase)
+	}
+
+	var payload map[string]string
+	_ = json.Unmarshal(cmd.Payload, &payload)
+
+	roleID := payload["role_id"]
+	if roleID == "" {
+		return nil, nil, fmt.Errorf("engine.handleQueueNightAction: role_id is required")
+	}
+	userID := payload["user_id"]
+	if _, ok := state.Players[userID]; !ok || userID == "" {
+		return nil, nil, fmt.Errorf("engine.handleQueueNightAction: %w", ErrPlayerNotFound)
+	}
+
+	actionType := payload["action_type"]
+	if actionType == "" {
+		if r := game.GetRoleByID(roleID); r != nil {
+			actionType = string(r.NightActionType)
+		}
+	}
+	order := payload["order"]
+	if order == "" {
+		order = fmt.Sprintf("%d", nextNightActionOrder(state))
+	}
+
+	event := newEvent(cmd, "night.action.queued", map[string]string{
+		"user_id":     userID,
+		"role_id":     roleID,
+		"order":       order,
+		"action_type": actionType,
+	})
+	return []types.Event{event}, acceptedResult(cmd.CommandID), nil
+}
+
+// nextNightActionOrder returns an order value after every queued action.
+func nextNightActionOrder(state State) int {
+	maxOrder := 0
+	for _, a := range state.NightActions {
+		if a.Order > maxOrder {
+			maxOrder = a.Order
+		}
+	}
+	return maxOrder + 1
+}
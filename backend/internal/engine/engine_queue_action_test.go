@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func newQueueActionState() State {
+	state := NewState("room-1")
+	state.Phase = PhaseNight
+	state.NightCount = 2
+	state.Players["dm"] = Player{UserID: "dm", IsDM: true}
+	state.Players["p1"] = Player{UserID: "p1", TrueRole: "empath", Team: "good", Alive: true, SeatNumber: 1}
+	state.Players["p2"] = Player{UserID: "p2", TrueRole: "imp", Team: "evil", Alive: true, SeatNumber: 2}
+	state.NightActions = []NightAction{{UserID: "p2", RoleID: "imp", Order: 24, ActionType: "select_one"}}
+	return state
+}
+
+func queueActionCmd(actor string, payload map[string]string) types.CommandEnvelope {
+	raw, _ := json.Marshal(payload)
+	return types.CommandEnvelope{CommandID: "cmd-q", RoomID: "room-1", Type: "queue_night_action", ActorUserID: actor, Payload: raw}
+}
+
+func TestQueueNightActionAppendsToNightActions(t *testing.T) {
+	state := newQueueActionState()
+
+	events, _, err := HandleCommand(state, queueActionCmd("dm", map[string]string{"user_id": "p1", "role_id": "fortuneteller"}))
+	if err != nil {
+		t.Fatalf("queue_night_action returned error: %v", err)
+	}
+	payload := findEventPayload(t, events, "night.action.queued")
+	if payload["action_type"] != "select_two" {
+		t.Fatalf("expected role default action_type select_two, got %q", payload["action_type"])
+	}
+
+	applyEventsToState(&state, events)
+	if len(state.NightActions) != 2 {
+		t.Fatalf("expected 2 night actions, got %d", len(state.NightActions))
+	}
+	added := state.NightActions[1]
+	if added.UserID != "p1" || added.RoleID != "fortuneteller" || added.Order != 25 {
+		t.Fatalf("unexpected queued action: %+v", added)
+	}
+}
+
+func TestQueueNightActionAllowsAutoDM(t *testing.T) {
+	state := newQueueActionState()
+	if _, _, err := HandleCommand(state, queueActionCmd("autodm", map[string]string{"user_id": "p1", "role_id": "monk"})); err != nil {
+		t.Fatalf("expected autodm to queue night action, got %v", err)
+	}
+}
+
+func TestQueueNightActionRejectsNonDM(t *testing.T) {
+	state := newQueueActionState()
+	if _, _, err := HandleCommand(state, queueActionCmd("p1", map[string]string{"user_id": "p1", "role_id": "monk"})); err == nil {
+		t.Fatal("expected non-DM to be rejected")
+	}
+}
+
+func TestQueueNightActionRequiresRoleAndTarget(t *testing.T) {
+	state := newQueueActionState()
+	if _, _, err := HandleCommand(state, queueActionCmd("dm", map[string]string{"user_id": "p1"})); err == nil {
+		t.Fatal("expected missing role_id to be rejected")
+	}
+	if _, _, err := HandleCommand(state, queueActionCmd("dm", map[string]string{"role_id": "monk"})); err == nil {
+		t.Fatal("expected missing user_id to be rejected")
+	}
+	if _, _, err := HandleCommand(state, queueActionCmd("dm", map[string]string{"user_id": "ghost", "role_id": "monk"})); err == nil {
+		t.Fatal("expected unknown user_id to be rejected")
+	}
+}
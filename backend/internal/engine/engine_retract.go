@@ -0,0 +1,74 @@
+// engine_retract.go — DM 撤回最近一个事件
+//
+// 事件不可变，撤回通过补偿事件 event.retracted {event_id, seq} 表达。
+// Replay 先收集被撤回的事件 ID，再跳过它们重建状态；客户端收到 event.retracted 后
+// 移除对应消息。为防止对局中滥用，仅大厅阶段或服务器调试模式 (State.DebugMode) 可用。
+//
+// [IN]  internal/types（Command/Event 类型）
+// [OUT] engine.go（HandleCommand 路由 undo_last_event）
+// [OUT] room（撤回后全量重放）、api（replay 接口）
+// [POS] 大厅/测试房间的纠错入口
+package engine
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// handleUndoLastEvent retracts the most recent event. DM or autodm only.
+func handleUndoLastEvent(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	isAutoDM := types.IsAutoDMActor(cmd.ActorUserID)
+	if !isAutoDM && !state.Players[cmd.ActorUserID].IsDM {
+		return nil, nil, fmt.Errorf("engine.handleUndoLastEvent: only DM or autodm can undo events")
+	}
+	if state.Phase != PhaseLobby && !state.DebugMode {
+		return nil, nil, fmt.Errorf("engine.handleUndoLastEvent: %w", ErrInvalidPhase)
+	}
+	if state.LastEventID == "" {
+		return nil, nil, fmt.Errorf("engine.handleUndoLastEvent: nothing to undo")
+	}
+
+	event := newEvent(cmd, "event.retracted", map[string]string{
+		"event_id": state.LastEventID,
+		"seq":      strconv.FormatInt(state.LastSeq, 10),
+	})
+	return []types.Event{event}, acceptedResult(cmd.CommandID), nil
+}
+
+// trackRetractable remembers the latest event as the undo target; a retraction clears it
+// so undo never reaches further back than one event.
+func (s *State) trackRetractable(event EventPayload) {
+	if event.Type == "event.retracted" {
+		s.LastEventID = ""
+		s.LastEventType = ""
+		return
+	}
+	s.LastEventID = event.EventID
+	s.LastEventType = event.Type
+}
+
+// RetractedEventIDs returns the IDs named by event.retracted events.
+func RetractedEventIDs(events []EventPayload) map[string]bool {
+	retracted := make(map[string]bool)
+	for _, e := range events {
+		if e.Type == "event.retracted" && e.Payload["event_id"] != "" {
+			retracted[e.Payload["event_id"]] = true
+		}
+	}
+	return retracted
+}
+
+// Replay rebuilds a room's state from its full event log, skipping retracted events.
+func Replay(roomID string, events []EventPayload) State {
+	state := NewState(roomID)
+	retracted := RetractedEventIDs(events)
+	for _, e := range events {
+		if retracted[e.EventID] {
+			continue
+		}
+		state.Reduce(e)
+	}
+	return state
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func newLobbyWithChat() State {
+	state := NewState("room-1")
+	state.Reduce(EventPayload{Seq: 1, EventID: "ev-dm", Type: "player.joined", Actor: "dm", Payload: map[string]string{"role": "dm", "name": "DM"}})
+	state.Reduce(EventPayload{Seq: 2, EventID: "ev-chat", Type: "public.chat", Actor: "dm", Payload: map[string]string{"message": "oops"}})
+	return state
+}
+
+func undoCommand(actor string) types.CommandEnvelope {
+	return types.CommandEnvelope{CommandID: "cmd-undo", RoomID: "room-1", Type: "undo_last_event", ActorUserID: actor}
+}
+
+func TestUndoLastEventRetractsMostRecentEvent(t *testing.T) {
+	state := newLobbyWithChat()
+
+	events, _, err := HandleCommand(state, undoCommand("dm"))
+	if err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	payload := findEventPayload(t, events, "event.retracted")
+	if payload["event_id"] != "ev-chat" || payload["seq"] != "2" {
+		t.Fatalf("unexpected retraction payload: %v", payload)
+	}
+
+	// A retraction cannot itself be undone, so a second undo has nothing to target.
+	events[0].Seq = 3
+	applyEventsToState(&state, events)
+	if _, _, err := HandleCommand(state, undoCommand("dm")); err == nil {
+		t.Fatal("expected second undo to be rejected")
+	}
+}
+
+func TestUndoLastEventRestrictions(t *testing.T) {
+	state := newLobbyWithChat()
+	state.Players["p1"] = Player{UserID: "p1"}
+	if _, _, err := HandleCommand(state, undoCommand("p1")); err == nil {
+		t.Fatal("expected players to be unable to undo")
+	}
+
+	state.Phase = PhaseDay
+	if _, _, err := HandleCommand(state, undoCommand("dm")); err == nil {
+		t.Fatal("expected undo outside the lobby to be rejected")
+	}
+	state.DebugMode = true
+	if _, _, err := HandleCommand(state, undoCommand("dm")); err != nil {
+		t.Fatalf("expected undo in debug mode to succeed: %v", err)
+	}
+}
+
+func TestReplaySkipsRetractedEvents(t *testing.T) {
+	events := []EventPayload{
+		{Seq: 1, EventID: "ev-1", Type: "player.joined", Actor: "alice", Payload: map[string]string{"name": "Alice"}},
+		{Seq: 2, EventID: "ev-2", Type: "player.joined", Actor: "bob", Payload: map[string]string{"name": "Bob"}},
+		{Seq: 3, EventID: "ev-3", Type: "event.retracted", Actor: "autodm", Payload: map[string]string{"event_id": "ev-2", "seq": "2"}},
+	}
+	state := Replay("room-1", events)
+	if _, ok := state.Players["bob"]; ok {
+		t.Fatal("expected retracted join to be skipped on replay")
+	}
+	if _, ok := state.Players["alice"]; !ok {
+		t.Fatal("expected alice to remain")
+	}
+	if state.LastSeq != 3 || state.LastEventID != "" {
+		t.Fatalf("expected LastSeq 3 and no undo target, got %d %q", state.LastSeq, state.LastEventID)
+	}
+}
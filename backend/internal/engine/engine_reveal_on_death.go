@@ -0,0 +1,62 @@
+// engine_reveal_on_death.go — 死亡公开角色 (房规)
+//
+// 基础规则下死亡玩家的角色保持隐藏。room_settings 的 reveal_on_death 开启后，
+// WithDeathReveals 在每个 player.died 之后紧跟一条公开的 role.revealed {user_id, role}
+// (真实角色：酒鬼公开为酒鬼)，归约到 Player.RevealedRole，投影对所有人保留该字段；
+// 关闭时不产生该事件，角色照旧只有 DM 可见。
+//
+// [IN]  internal/types（Event 类型）
+// [OUT] room（持久化前追加 role.revealed）、state_reduce.go（RevealedRole 归约）
+// [OUT] projection（RevealedRole 对非 DM 可见）
+// [POS] 死亡事件的可选公开层
+package engine
+
+import (
+	"encoding/json"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// WithDeathReveals follows each player.died with a public role.revealed when the room reveals roles on death.
+func WithDeathReveals(state State, cmd types.CommandEnvelope, events []types.Event) []types.Event {
+	if !state.Config.RevealOnDeath {
+		return events
+	}
+	out := make([]types.Event, 0, len(events))
+	for _, ev := range events {
+		out = append(out, ev)
+		if ev.EventType != "player.died" {
+			continue
+		}
+		var payload map[string]string
+		_ = json.Unmarshal(ev.Payload, &payload)
+		if reveal, ok := roleRevealFor(state, cmd, payload["user_id"]); ok {
+			out = append(out, reveal)
+		}
+	}
+	return out
+}
+
+// roleRevealFor builds the role.revealed event for a player who just died.
+func roleRevealFor(state State, cmd types.CommandEnvelope, userID string) (types.Event, bool) {
+	p, ok := state.Players[userID]
+	if !ok {
+		return types.Event{}, false
+	}
+	role := p.TrueRole
+	if role == "" {
+		role = p.Role
+	}
+	if role == "" {
+		return types.Event{}, false
+	}
+	return newEvent(cmd, "role.revealed", map[string]string{"user_id": userID, "role": role}), true
+}
+
+// reduceRoleRevealed records a publicly revealed role.
+func (s *State) reduceRoleRevealed(event EventPayload) {
+	if p, ok := s.Players[event.Payload["user_id"]]; ok {
+		p.RevealedRole = event.Payload["role"]
+		s.Players[p.UserID] = p
+	}
+}
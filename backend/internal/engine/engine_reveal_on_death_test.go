@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestRevealOnDeathFollowsEachDeath(t *testing.T) {
+	state := NewState("room-1")
+	events, _, err := HandleCommand(state, presenceCommand("room_settings", "p1", map[string]string{"reveal_on_death": "true"}))
+	if err != nil {
+		t.Fatalf("room_settings: %v", err)
+	}
+	applyEventsToState(&state, events)
+	if !state.Config.RevealOnDeath {
+		t.Fatal("expected room_settings to enable reveal_on_death")
+	}
+	state.Players["d1"] = Player{UserID: "d1", Role: "chef", TrueRole: "drunk", Alive: true}
+	state.Players["d2"] = Player{UserID: "d2", Role: "imp", TrueRole: "imp", Alive: true}
+
+	cmd := types.CommandEnvelope{CommandID: "c1", RoomID: "room-1"}
+	in := []types.Event{
+		newEvent(cmd, "player.died", map[string]string{"user_id": "d1", "cause": "demon"}),
+		newEvent(cmd, "player.died", map[string]string{"user_id": "d2", "cause": "execution"}),
+		newEvent(cmd, "phase.day", nil),
+	}
+	out := WithDeathReveals(state, cmd, in)
+	wantTypes := []string{"player.died", "role.revealed", "player.died", "role.revealed", "phase.day"}
+	if len(out) != len(wantTypes) {
+		t.Fatalf("got %d events, want %v", len(out), wantTypes)
+	}
+	for i, want := range wantTypes {
+		if out[i].EventType != want {
+			t.Fatalf("event %d = %s, want %s", i, out[i].EventType, want)
+		}
+	}
+	applyEventsToState(&state, out)
+	if got := state.Players["d1"].RevealedRole; got != "drunk" {
+		t.Fatalf("expected the drunk to be revealed as drunk, got %q", got)
+	}
+}
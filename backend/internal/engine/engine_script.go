@@ -0,0 +1,65 @@
+// engine_script.go — 房间自定义剧本
+//
+// room_settings 的 script 键为角色 ID 的 JSON 数组 (空串或 [] 清除)，经 game.ValidateScript
+// 校验后归约到 State.Script。开局 (start_game) 与配板预览把剧本交给 SetupAgent，
+// 随机选角、恶魔 bluff 与酒鬼/间谍的假身份只从剧本角色中抽取。
+//
+// [IN]  internal/game（ValidateScript、NewScript）
+// [OUT] engine.go（handleRoomSettings 校验、handleStartGame 选角）、engine_setup_preview.go、state_reduce.go
+// [POS] 大厅阶段的剧本设置
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+)
+
+// parseScriptSetting decodes a room_settings script value; "" clears the script.
+func parseScriptSetting(v string) ([]string, error) {
+	if v == "" {
+		return nil, nil
+	}
+	var roles []string
+	if err := json.Unmarshal([]byte(v), &roles); err != nil {
+		return nil, fmt.Errorf("script must be a JSON array of role IDs: %w", err)
+	}
+	return roles, nil
+}
+
+// validateScriptSetting checks the script a settings change would set, if any.
+func validateScriptSetting(change map[string]string) error {
+	v, ok := change["script"]
+	if !ok {
+		return nil
+	}
+	roles, err := parseScriptSetting(v)
+	if err != nil || len(roles) == 0 {
+		return err
+	}
+	return game.ValidateScript(roles)
+}
+
+// reduceScript applies a validated script from a room.settings.changed payload.
+func (s *State) reduceScript(payload map[string]string) {
+	v, ok := payload["script"]
+	if !ok {
+		return
+	}
+	if roles, err := parseScriptSetting(v); err == nil {
+		s.Script = roles
+	}
+}
+
+// setupScript resolves the room's script for role assignment; nil draws from the whole edition.
+func setupScript(state State) (*game.Script, error) {
+	if len(state.Script) == 0 {
+		return nil, nil
+	}
+	script, err := game.NewScript(game.Edition(state.Edition), state.Script)
+	if err != nil {
+		return nil, fmt.Errorf("engine.setupScript: %w", err)
+	}
+	return script, nil
+}
@@ -0,0 +1,60 @@
+// engine_setup_preview.go — 开局前的配板预览
+//
+// PreviewSetup 在大厅阶段用 game.SetupAgent 按种子 (与房间剧本) 试生成一次分配，只返回角色分布
+// (按类型与按角色的数量，不含玩家对应关系) 与种子，不产生事件、不修改状态。
+// DM 满意后以 start_game 的 seed 载荷开局，handleStartGame 以同一种子选出同一组角色。
+//
+// [IN]  internal/game（SetupAgent、NewSetupSeed）
+// [OUT] api（POST /v1/rooms/{room_id}/setup/preview）
+// [POS] 游戏启动前的只读配板试算
+package engine
+
+import (
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+)
+
+// SetupPreview is a role distribution without player assignments.
+type SetupPreview struct {
+	Seed          int64          `json:"seed"`
+	PlayerCount   int            `json:"player_count"`
+	Counts        map[string]int `json:"counts"` // role type -> count
+	Roles         map[string]int `json:"roles"`  // role ID -> count
+	BaronModified bool           `json:"baron_modified"`
+}
+
+// PreviewSetup generates the lobby's setup for seed (0 picks a new seed) without touching state.
+func PreviewSetup(state State, seed int64) (*SetupPreview, error) {
+	if state.Phase != PhaseLobby {
+		return nil, fmt.Errorf("engine.PreviewSetup: setup can only be previewed in the lobby")
+	}
+	userIDs, seatOrder := lobbyPlayers(state)
+	if seed == 0 {
+		seed = game.NewSetupSeed()
+	}
+	script, err := setupScript(state)
+	if err != nil {
+		return nil, fmt.Errorf("engine.PreviewSetup: %w", err)
+	}
+	agent := game.NewSetupAgent(game.SetupConfig{Script: script, PlayerCount: len(userIDs), Edition: state.Edition, Seed: seed})
+	result, err := agent.GenerateAssignments(userIDs, seatOrder)
+	if err != nil {
+		return nil, fmt.Errorf("engine.PreviewSetup: %w", err)
+	}
+
+	preview := &SetupPreview{
+		Seed:          seed,
+		PlayerCount:   len(userIDs),
+		Counts:        make(map[string]int),
+		Roles:         make(map[string]int),
+		BaronModified: result.BaronModified,
+	}
+	for _, a := range result.Assignments {
+		preview.Roles[a.TrueRole]++
+		if role := game.GetRoleByID(a.TrueRole); role != nil {
+			preview.Counts[string(role.Type)]++
+		}
+	}
+	return preview, nil
+}
@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestStartGameWithPreviewSeedDealsPreviewedRoles(t *testing.T) {
+	state := NewState("room-1")
+	for i := 1; i <= 8; i++ {
+		uid := fmt.Sprintf("p%d", i)
+		state.Players[uid] = Player{UserID: uid, SeatNumber: i, Alive: true}
+	}
+	preview, err := PreviewSetup(state, 0)
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+
+	payload, _ := json.Marshal(map[string]string{"seed": fmt.Sprint(preview.Seed)})
+	events, _, err := HandleCommand(state, types.CommandEnvelope{CommandID: "start", RoomID: "room-1", Type: "start_game", ActorUserID: "p1", Payload: payload})
+	if err != nil {
+		t.Fatalf("start_game: %v", err)
+	}
+	dealt := make(map[string]int)
+	for _, e := range events {
+		if e.EventType == "role.assigned" {
+			var p map[string]string
+			_ = json.Unmarshal(e.Payload, &p)
+			dealt[p["true_role"]]++
+		}
+	}
+	if fmt.Sprint(dealt) != fmt.Sprint(preview.Roles) {
+		t.Fatalf("expected the previewed roles %v, got %v", preview.Roles, dealt)
+	}
+}
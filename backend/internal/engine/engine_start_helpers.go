@@ -1,12 +1,16 @@
 // engine_start_helpers.go — handleStartGame 的辅助函数
 //
 // [IN]  game (角色定义, NightAction)
-// [POS] 从 handleStartGame 提取的 custom_roles 解析与首夜 no_action 自动完成逻辑
+// [POS] 从 handleStartGame 提取的 custom_roles/seed 解析、大厅玩家列表、开局事件生成 (start_game 与
+// force_assignments 共用) 与首夜 no_action 自动完成逻辑
 package engine
 
 import (
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"strconv"
 
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
@@ -59,10 +63,12 @@ func buildNoActionCompletions(cmd types.CommandEnvelope, nightOrder []game.Night
 }
 
 // buildTeamRecognitionFromSetup 根据 SetupResult 生成首夜邪恶阵营互认事件。
-// 爪牙得知恶魔身份 + 其他爪牙，恶魔得知爪牙身份 + 伪装角色。
-func buildTeamRecognitionFromSetup(cmd types.CommandEnvelope, result *game.SetupResult) []types.Event {
+// 爪牙得知恶魔身份 + 其他爪牙及其角色，恶魔得知爪牙身份 + 伪装角色；
+// 爪牙角色 (minion_roles) 只在 demonSeesRoles (Config.DemonSeesMinionRoles) 开启时告知恶魔。
+func buildTeamRecognitionFromSetup(cmd types.CommandEnvelope, result *game.SetupResult, demonSeesRoles bool) []types.Event {
 	var demonID string
 	var minionIDs []string
+	minionRoles := make(map[string]string)
 
 	for uid, a := range result.Assignments {
 		if a.TrueRole == "imp" {
@@ -71,6 +77,7 @@ func buildTeamRecognitionFromSetup(cmd types.CommandEnvelope, result *game.Setup
 		r := game.GetRoleByID(a.TrueRole)
 		if r != nil && r.Type == game.RoleMinion {
 			minionIDs = append(minionIDs, uid)
+			minionRoles[uid] = a.TrueRole
 		}
 	}
 	if demonID == "" {
@@ -79,25 +86,154 @@ func buildTeamRecognitionFromSetup(cmd types.CommandEnvelope, result *game.Setup
 
 	minionIDsJSON, _ := json.Marshal(minionIDs)
 	bluffsJSON, _ := json.Marshal(result.BluffRoles)
+	minionRolesJSON, _ := json.Marshal(minionRoles)
 
 	var events []types.Event
 	for _, mid := range minionIDs {
 		a := result.Assignments[mid]
 		events = append(events, newEvent(cmd, "team.recognition", map[string]string{
-			"user_id":    mid,
-			"team":       "evil",
-			"role":       a.TrueRole,
-			"demon_id":   demonID,
-			"minion_ids": string(minionIDsJSON),
+			"user_id":      mid,
+			"team":         "evil",
+			"role":         a.TrueRole,
+			"demon_id":     demonID,
+			"minion_ids":   string(minionIDsJSON),
+			"minion_roles": string(minionRolesJSON),
 		}))
 	}
-	events = append(events, newEvent(cmd, "team.recognition", map[string]string{
+	demonReveal := map[string]string{
 		"user_id":    demonID,
 		"team":       "evil",
 		"role":       "imp",
 		"demon_id":   demonID,
 		"minion_ids": string(minionIDsJSON),
 		"bluffs":     string(bluffsJSON),
-	}))
+	}
+	if demonSeesRoles {
+		demonReveal["minion_roles"] = string(minionRolesJSON)
+	}
+	return append(events, newEvent(cmd, "team.recognition", demonReveal))
+}
+
+// lobbyPlayers lists the non-DM, non-Traveller players and their seats.
+func lobbyPlayers(state State) (userIDs []string, seatOrder []int) {
+	for uid, p := range state.Players {
+		if !p.IsDM && !p.Traveller {
+			userIDs = append(userIDs, uid)
+			seatOrder = append(seatOrder, p.SeatNumber)
+		}
+	}
+	return userIDs, seatOrder
+}
+
+// setupSeed reads start_game's optional "seed" payload (0 when absent or invalid).
+func setupSeed(payload map[string]string) int64 {
+	seed, _ := strconv.ParseInt(payload["seed"], 10, 64)
+	return seed
+}
+
+// buildStartGameEvents turns a SetupResult into the game start: role assignments, bluffs,
+// red herring and the first night. Shared by start_game and force_assignments.
+func buildStartGameEvents(state State, cmd types.CommandEnvelope, result *game.SetupResult) []types.Event {
+	events := []types.Event{newEvent(cmd, "game.started", nil)}
+	events = append(events, buildRoleAssignedEvents(cmd, result)...)
+
+	// Assign bluffs to demon
+	if len(result.BluffRoles) > 0 {
+		bluffsJSON, _ := json.Marshal(result.BluffRoles)
+		events = append(events, newEvent(cmd, "bluffs.assigned", map[string]string{
+			"bluffs": string(bluffsJSON),
+		}))
+	}
+	events = append(events, buildRedHerringEvents(cmd, result)...)
+	return append(events, buildFirstNightEvents(state, cmd, result)...)
+}
+
+// buildRoleAssignedEvents emits one role.assigned per assignment.
+func buildRoleAssignedEvents(cmd types.CommandEnvelope, result *game.SetupResult) []types.Event {
+	var events []types.Event
+	for userID, assignment := range result.Assignments {
+		role := game.GetRoleByID(assignment.Role)
+		teamStr := "good"
+		if role != nil && role.Team == game.TeamEvil {
+			teamStr = "evil"
+		}
+
+		payload := map[string]string{
+			"user_id":   userID,
+			"role":      assignment.PerceivedRole,
+			"true_role": assignment.TrueRole,
+			"team":      teamStr,
+		}
+
+		if assignment.TrueRole == "imp" {
+			payload["is_demon"] = "true"
+		}
+		if role != nil && role.Type == game.RoleMinion {
+			payload["is_minion"] = "true"
+		}
+
+		// Spy: emit apparent role for info resolution
+		if assignment.SpyApparentRole != "" {
+			payload["spy_apparent_role"] = assignment.SpyApparentRole
+		}
+
+		events = append(events, newEvent(cmd, "role.assigned", payload))
+	}
 	return events
 }
+
+// buildRedHerringEvents picks the fortune teller's red herring (a good player who isn't the fortune teller).
+func buildRedHerringEvents(cmd types.CommandEnvelope, result *game.SetupResult) []types.Event {
+	var fortuneTellerID string
+	var goodPlayerIDs []string
+	for userID, assignment := range result.Assignments {
+		if assignment.TrueRole == "fortuneteller" {
+			fortuneTellerID = userID
+		}
+		if assignment.Team == game.TeamGood && assignment.TrueRole != "fortuneteller" {
+			goodPlayerIDs = append(goodPlayerIDs, userID)
+		}
+	}
+	if fortuneTellerID == "" || len(goodPlayerIDs) == 0 {
+		return nil
+	}
+	rhIdx, _ := rand.Int(rand.Reader, big.NewInt(int64(len(goodPlayerIDs))))
+	return []types.Event{newEvent(cmd, "red_herring.assigned", map[string]string{
+		"user_id": goodPlayerIDs[rhIdx.Int64()],
+	})}
+}
+
+// buildFirstNightEvents queues the first night, enters it, reveals the evil team and prompts the first actor.
+func buildFirstNightEvents(state State, cmd types.CommandEnvelope, result *game.SetupResult) []types.Event {
+	var events []types.Event
+	for _, action := range result.NightOrder {
+		actionType := ""
+		if r := game.GetRoleByID(action.RoleID); r != nil {
+			actionType = string(r.FirstNightActionType)
+		}
+		events = append(events, newEvent(cmd, "night.action.queued", map[string]string{
+			"user_id":     action.UserID,
+			"role_id":     action.RoleID,
+			"order":       fmt.Sprintf("%d", action.Order),
+			"action_type": actionType,
+		}))
+	}
+	// Auto-complete no_action roles (e.g. Imp first night)
+	events = append(events, buildNoActionCompletions(cmd, result.NightOrder)...)
+
+	// Transition to first night
+	events = append(events, newEvent(cmd, "phase.first_night", map[string]string{}))
+
+	// 首夜开始时：邪恶阵营互认（爪牙认恶魔、恶魔认爪牙+伪装角色）
+	events = append(events, buildTeamRecognitionFromSetup(cmd, result, state.Config.DemonSeesMinionRoles)...)
+
+	// Prompt the first actionable player (sequential night actions)
+	queuedActions := buildEngineNightActions(result.NightOrder, true)
+	autoCompleted := buildNoActionSet(result.NightOrder)
+	for i := range queuedActions {
+		if autoCompleted[queuedActions[i].UserID] {
+			queuedActions[i].Completed = true
+		}
+	}
+	return append(events, buildFirstPrompt(cmd, queuedActions)...)
+}
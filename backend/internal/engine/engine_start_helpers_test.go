@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func evilRevealSetup() *game.SetupResult {
+	return &game.SetupResult{
+		Assignments: map[string]game.Assignment{
+			"imp":      {UserID: "imp", TrueRole: "imp"},
+			"poisoner": {UserID: "poisoner", TrueRole: "poisoner"},
+			"baron":    {UserID: "baron", TrueRole: "baron"},
+			"chef":     {UserID: "chef", TrueRole: "chef"},
+		},
+		BluffRoles: []string{"monk", "mayor", "saint"},
+	}
+}
+
+func revealsByUser(t *testing.T, events []types.Event) map[string]map[string]string {
+	t.Helper()
+	reveals := make(map[string]map[string]string)
+	for _, e := range events {
+		var payload map[string]string
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			t.Fatalf("decode %s: %v", e.EventType, err)
+		}
+		reveals[payload["user_id"]] = payload
+	}
+	return reveals
+}
+
+func TestEvilRevealPolicy(t *testing.T) {
+	cmd := types.CommandEnvelope{CommandID: "cmd-start", RoomID: "room-1", Type: "start_game"}
+	for _, demonSeesRoles := range []bool{false, true} {
+		reveals := revealsByUser(t, buildTeamRecognitionFromSetup(cmd, evilRevealSetup(), demonSeesRoles))
+
+		var minionRoles map[string]string
+		_ = json.Unmarshal([]byte(reveals["poisoner"]["minion_roles"]), &minionRoles)
+		if minionRoles["baron"] != "baron" || reveals["poisoner"]["demon_id"] != "imp" {
+			t.Fatalf("policy %v: expected minions to see the demon and each other's roles, got %v", demonSeesRoles, reveals["poisoner"])
+		}
+
+		demon := reveals["imp"]
+		var minionIDs []string
+		_ = json.Unmarshal([]byte(demon["minion_ids"]), &minionIDs)
+		if len(minionIDs) != 2 || demon["bluffs"] == "" {
+			t.Fatalf("policy %v: expected the demon to learn both minions and the bluffs, got %v", demonSeesRoles, demon)
+		}
+		if _, sawRoles := demon["minion_roles"]; sawRoles != demonSeesRoles {
+			t.Fatalf("policy %v: demon minion_roles present=%v", demonSeesRoles, sawRoles)
+		}
+	}
+}
+
+func TestRoomSettingsTogglesDemonSeesMinionRoles(t *testing.T) {
+	state := NewState("room-1")
+	if state.Config.DemonSeesMinionRoles {
+		t.Fatal("expected the demon to see minion identities only by default")
+	}
+	events, _, err := HandleCommand(state, presenceCommand("room_settings", "p1", map[string]string{"demon_sees_minion_roles": "true"}))
+	if err != nil {
+		t.Fatalf("room_settings: %v", err)
+	}
+	applyEventsToState(&state, events)
+	if !state.Config.DemonSeesMinionRoles {
+		t.Fatal("expected room_settings to enable the demon role reveal")
+	}
+}
+
+func TestRoomSettingsScript(t *testing.T) {
+	state := NewState("room-1")
+	events, _, err := HandleCommand(state, presenceCommand("room_settings", "p1", map[string]string{"script": `["imp","poisoner","chef"]`}))
+	if err != nil {
+		t.Fatalf("room_settings: %v", err)
+	}
+	applyEventsToState(&state, events)
+	if len(state.Script) != 3 || state.Script[0] != "imp" {
+		t.Fatalf("Script = %v, want the three listed roles", state.Script)
+	}
+	if _, _, err := HandleCommand(state, presenceCommand("room_settings", "p1", map[string]string{"script": `["chef"]`})); err == nil {
+		t.Fatal("expected a script without a demon to be rejected")
+	}
+
+	events, _, err = HandleCommand(state, presenceCommand("room_settings", "p1", map[string]string{"script": ""}))
+	if err != nil {
+		t.Fatalf("clearing script: %v", err)
+	}
+	applyEventsToState(&state, events)
+	if state.Script != nil {
+		t.Fatalf("expected an empty script to clear, got %v", state.Script)
+	}
+}
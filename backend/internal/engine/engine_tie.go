@@ -0,0 +1,127 @@
+// engine_tie.go — 处决平票追踪与 DM 裁决
+//
+// 官方规则：两名被提名者以相同的最高票数达到门槛时，当天无人被处决。
+// State.TiedVotes / TiedNominees 记录当天平票，之后的提名必须严格超过平票票数才能上处决台。
+// 开启 Config.DMResolvesTies (room_settings 的 dm_resolves_ties) 时，DM 可用 resolve_tie 从平票者中指定一人上处决台。
+// 开启 Config.EarliestNominationWinsTies (房规) 时，白天结束仍未打破的平票由最早被提名
+// (Nomination.StartedAt) 的平票者上处决台，同样以 tie.resolved 记录。
+//
+// [IN]  internal/types（Command/Event 类型）
+// [OUT] engine.go（resolve_tie 命令分发、入夜前的最早提名裁决）、vote_resolve.go（最高票数）
+// [POS] 白天处决结算的平票策略层
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// highestBlockVotes 返回今日处决台或平票的最高票数；都没有时为 0。
+func (s State) highestBlockVotes() int {
+	if s.OnTheBlock != nil && s.OnTheBlock.VotesFor > s.TiedVotes {
+		return s.OnTheBlock.VotesFor
+	}
+	return s.TiedVotes
+}
+
+// putOnTheBlock 将玩家放上处决台，并清除平票记录。
+func (s *State) putOnTheBlock(userID string, votesFor int) {
+	s.OnTheBlock = &OnTheBlockInfo{
+		UserID:     userID,
+		VotesFor:   votesFor,
+		SeatNumber: s.Players[userID].SeatNumber,
+	}
+	s.clearTie()
+}
+
+// recordTie 记录平票：处决台上的玩家与新被提名者一起进入平票名单，处决台清空。
+func (s *State) recordTie(nominee string, votesFor int) {
+	if s.OnTheBlock != nil {
+		s.TiedNominees = []string{s.OnTheBlock.UserID}
+	}
+	s.TiedVotes = votesFor
+	s.TiedNominees = append(s.TiedNominees, nominee)
+	s.OnTheBlock = nil
+}
+
+// clearTie 清除当日平票记录。
+func (s *State) clearTie() {
+	s.TiedVotes = 0
+	s.TiedNominees = nil
+}
+
+// handleResolveTie lets the DM break today's tie by naming one tied nominee.
+// Payload: user_id (must be in TiedNominees). Requires Config.DMResolvesTies.
+func handleResolveTie(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	isAutoDM := types.IsAutoDMActor(cmd.ActorUserID)
+	if !isAutoDM && !state.Players[cmd.ActorUserID].IsDM {
+		return nil, nil, fmt.Errorf("engine.handleResolveTie: only DM or autodm can resolve ties")
+	}
+	if !state.Config.DMResolvesTies {
+		return nil, nil, fmt.Errorf("engine.handleResolveTie: tie resolution by DM is disabled")
+	}
+	if state.Phase != PhaseDay && state.Phase != PhaseNomination {
+		return nil, nil, fmt.Errorf("engine.handleResolveTie: %w", ErrInvalidPhase)
+	}
+	if state.TiedVotes == 0 || state.OnTheBlock != nil {
+		return nil, nil, fmt.Errorf("engine.handleResolveTie: no unresolved tie today")
+	}
+
+	var payload map[string]string
+	_ = json.Unmarshal(cmd.Payload, &payload)
+	userID := payload["user_id"]
+	if !containsString(state.TiedNominees, userID) {
+		return nil, nil, fmt.Errorf("engine.handleResolveTie: %s is not a tied nominee", userID)
+	}
+
+	event := newEvent(cmd, "tie.resolved", map[string]string{
+		"user_id":   userID,
+		"votes_for": fmt.Sprintf("%d", state.TiedVotes),
+	})
+	return []types.Event{event}, acceptedResult(cmd.CommandID), nil
+}
+
+// breakTieByEarliestNomination puts the earliest-nominated tied nominee on the block at the end
+// of the day when Config.EarliestNominationWinsTies is set. The returned state reflects the tie.resolved event.
+func breakTieByEarliestNomination(state State, cmd types.CommandEnvelope) (State, []types.Event) {
+	if !state.Config.EarliestNominationWinsTies || state.OnTheBlock != nil || state.TiedVotes == 0 || state.ExecutedToday != "" {
+		return state, nil
+	}
+	userID, ok := state.earliestTiedNominee()
+	if !ok {
+		return state, nil
+	}
+	event := newEvent(cmd, "tie.resolved", map[string]string{
+		"user_id":   userID,
+		"votes_for": fmt.Sprintf("%d", state.TiedVotes),
+		"rule":      "earliest_nomination",
+	})
+	state.putOnTheBlock(userID, state.TiedVotes)
+	return state, []types.Event{event}
+}
+
+// earliestTiedNominee 返回今日平票者中被提名时间 (StartedAt) 最早的一人；同时刻按提名顺序。
+func (s State) earliestTiedNominee() (string, bool) {
+	best, bestAt := "", int64(0)
+	for _, n := range s.NominationQueue {
+		if !containsString(s.TiedNominees, n.Nominee) {
+			continue
+		}
+		if best == "" || n.StartedAt < bestAt {
+			best, bestAt = n.Nominee, n.StartedAt
+		}
+	}
+	return best, best != ""
+}
+
+// containsString 判断切片是否包含给定值。
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func newTieTestState() State {
+	state := NewState("room-1")
+	state.Phase = PhaseDay
+	state.DayCount = 1
+	state.DemonID = "imp"
+	state.Players["imp"] = Player{UserID: "imp", TrueRole: "imp", Team: "evil", Alive: true, SeatNumber: 1}
+	for i, uid := range []string{"a", "b", "c", "d"} {
+		state.Players[uid] = Player{UserID: uid, TrueRole: "chef", Team: "good", Alive: true, SeatNumber: i + 2}
+	}
+	return state
+}
+
+// nominateWithVotes resolves a nomination of nominee with yes votes and applies the result.
+func nominateWithVotes(t *testing.T, state *State, nominee string, yes int) string {
+	t.Helper()
+	votes := map[string]bool{}
+	voters := []string{"imp", "a", "b", "c", "d"}
+	for i, uid := range voters {
+		votes[uid] = i < yes
+	}
+	state.Nomination = &Nomination{Nominator: "imp", Nominee: nominee, Votes: votes}
+	result, events := resolveNomination(*state, types.CommandEnvelope{CommandID: "cmd-nom", RoomID: state.RoomID})
+	applyEventsToState(state, events)
+	return result
+}
+
+func TestUnbrokenTieExecutesNobody(t *testing.T) {
+	state := newTieTestState()
+	if got := nominateWithVotes(t, &state, "a", 3); got != "on_the_block" {
+		t.Fatalf("expected first nominee on the block, got %s", got)
+	}
+	if got := nominateWithVotes(t, &state, "b", 3); got != "tied" {
+		t.Fatalf("expected tie, got %s", got)
+	}
+	if got := nominateWithVotes(t, &state, "c", 3); got != "tied" {
+		t.Fatalf("expected a later equal vote to join the tie, got %s", got)
+	}
+
+	events, _, err := handleAdvancePhase(state, types.CommandEnvelope{
+		CommandID: "cmd-night", ActorUserID: "autodm", RoomID: state.RoomID,
+		Payload: json.RawMessage(`{"phase":"night"}`),
+	})
+	if err != nil {
+		t.Fatalf("advance phase: %v", err)
+	}
+	if hasTestEventType(events, "execution.resolved") {
+		t.Fatal("expected no execution after an unbroken tie")
+	}
+}
+
+func TestLaterNomineeMustBeatTie(t *testing.T) {
+	state := newTieTestState()
+	nominateWithVotes(t, &state, "a", 4)
+	nominateWithVotes(t, &state, "b", 4)
+
+	if got := nominateWithVotes(t, &state, "c", 3); got != "not_on_the_block" {
+		t.Fatalf("expected fewer votes than the tie to stay off the block, got %s", got)
+	}
+	if got := nominateWithVotes(t, &state, "d", 5); got != "on_the_block" {
+		t.Fatalf("expected more votes than the tie to go on the block, got %s", got)
+	}
+	if state.TiedVotes != 0 || len(state.TiedNominees) != 0 {
+		t.Fatalf("expected tie cleared, got %d %v", state.TiedVotes, state.TiedNominees)
+	}
+}
+
+func TestDMBrokenTieExecutesChosenNominee(t *testing.T) {
+	state := newTieTestState()
+	state.Config.DMResolvesTies = true
+	nominateWithVotes(t, &state, "a", 3)
+	nominateWithVotes(t, &state, "b", 3)
+
+	cmd := types.CommandEnvelope{
+		CommandID: "cmd-tie", ActorUserID: "autodm", RoomID: state.RoomID,
+		Payload: json.RawMessage(`{"user_id":"b"}`),
+	}
+	events, _, err := handleResolveTie(state, cmd)
+	if err != nil {
+		t.Fatalf("resolve tie: %v", err)
+	}
+	applyEventsToState(&state, events)
+	if state.OnTheBlock == nil || state.OnTheBlock.UserID != "b" {
+		t.Fatalf("expected b on the block, got %+v", state.OnTheBlock)
+	}
+
+	events, _, err = handleAdvancePhase(state, types.CommandEnvelope{
+		CommandID: "cmd-night", ActorUserID: "autodm", RoomID: state.RoomID,
+		Payload: json.RawMessage(`{"phase":"night"}`),
+	})
+	if err != nil {
+		t.Fatalf("advance phase: %v", err)
+	}
+	if findEventPayload(t, events, "execution.resolved")["executed"] != "b" {
+		t.Fatal("expected DM-chosen nominee to be executed")
+	}
+}
+
+func TestResolveTieRejectedWhenDisabledOrNotTied(t *testing.T) {
+	state := newTieTestState()
+	nominateWithVotes(t, &state, "a", 3)
+	nominateWithVotes(t, &state, "b", 3)
+
+	cmd := types.CommandEnvelope{
+		CommandID: "cmd-tie", ActorUserID: "autodm", RoomID: state.RoomID,
+		Payload: json.RawMessage(`{"user_id":"b"}`),
+	}
+	if _, _, err := handleResolveTie(state, cmd); err == nil {
+		t.Fatal("expected resolve_tie to fail when DMResolvesTies is off")
+	}
+
+	state.Config.DMResolvesTies = true
+	cmd.Payload = json.RawMessage(`{"user_id":"c"}`)
+	if _, _, err := handleResolveTie(state, cmd); err == nil {
+		t.Fatal("expected resolve_tie to reject a nominee outside the tie")
+	}
+}
+
+func TestEarliestNominationTiebreak(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		houseOn  bool
+		executed string
+	}{
+		{"default executes nobody", false, ""},
+		{"house rule executes earliest nominee", true, "b"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newTieTestState()
+			state.Config.EarliestNominationWinsTies = tt.houseOn
+			nominateWithVotes(t, &state, "a", 2) // below threshold, never tied
+			nominateWithVotes(t, &state, "b", 3)
+			nominateWithVotes(t, &state, "c", 3)
+			for i, at := range []int64{1000, 2000, 3000} {
+				state.NominationQueue[i].StartedAt = at
+			}
+
+			events, _, err := handleAdvancePhase(state, types.CommandEnvelope{
+				CommandID: "cmd-night", ActorUserID: "autodm", RoomID: state.RoomID,
+				Payload: json.RawMessage(`{"phase":"night"}`),
+			})
+			if err != nil {
+				t.Fatalf("advance phase: %v", err)
+			}
+			if tt.executed == "" {
+				if hasTestEventType(events, "execution.resolved") || !hasTestEventType(events, "day.no_execution") {
+					t.Fatal("expected no execution after a tie by default")
+				}
+				return
+			}
+			if got := findEventPayload(t, events, "execution.resolved")["executed"]; got != tt.executed {
+				t.Fatalf("executed %q, want %q", got, tt.executed)
+			}
+			applyEventsToState(&state, events)
+			if state.ExecutedToday != tt.executed {
+				t.Fatalf("replayed ExecutedToday = %q, want %q", state.ExecutedToday, tt.executed)
+			}
+		})
+	}
+}
+
+func TestRoomSettingsEnableDMResolvesTies(t *testing.T) {
+	state := newTieTestState()
+	nominateWithVotes(t, &state, "a", 3)
+	nominateWithVotes(t, &state, "b", 3)
+	cmd := types.CommandEnvelope{
+		CommandID: "cmd-tie", ActorUserID: "autodm", RoomID: state.RoomID,
+		Payload: json.RawMessage(`{"user_id":"b"}`),
+	}
+	if _, _, err := handleResolveTie(state, cmd); err == nil {
+		t.Fatal("expected resolve_tie to be rejected before the setting is enabled")
+	}
+
+	settings := NewState(state.RoomID)
+	events, _, err := HandleCommand(settings, presenceCommand("room_settings", "p1", map[string]string{"dm_resolves_ties": "true"}))
+	if err != nil {
+		t.Fatalf("room_settings: %v", err)
+	}
+	applyEventsToState(&state, events)
+	if !state.Config.DMResolvesTies {
+		t.Fatal("expected room_settings to enable dm_resolves_ties")
+	}
+	if _, _, err := handleResolveTie(state, cmd); err != nil {
+		t.Fatalf("expected resolve_tie to be accepted once enabled, got %v", err)
+	}
+}
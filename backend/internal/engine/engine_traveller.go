@@ -0,0 +1,164 @@
+// Package engine 旅行者与流放投票
+//
+// 旅行者 (Player.Traveller) 以 join 载荷 traveller=true 入座，开局前后均可加入，
+// 不占配板人数 (lobbyPlayers 排除旅行者，开局不分配角色)。白天任何在座玩家或 DM 可对旅行者
+// 发起 exile：全体在座玩家 (含死亡玩家，不消耗幽灵票) 投票，全部投完或 DM resolve_exile 时
+// 结算，赞成票不少于存活人数 (含旅行者) 的一半即流放：player.exiled 令旅行者死亡并离场
+// (Departed = exiled)，但不是处决 (不记 ExecutedToday，不触发圣徒等处决规则)。
+// 胜负相关的存活人数 (GetAliveResidentCount) 不计旅行者。
+//
+// [OUT] engine.go（exile/exile_vote/resolve_exile 路由、handleJoin 旅行者入座）
+// [OUT] state_reduce.go（reduceExile）
+// [POS] 命令层的旅行者规则
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// ExileVote is an open call to exile a Traveller.
+type ExileVote struct {
+	Target    string          `json:"target"`
+	Nominator string          `json:"nominator"`
+	Votes     map[string]bool `json:"votes"`
+}
+
+func (e *ExileVote) copy() *ExileVote {
+	cp := *e
+	cp.Votes = make(map[string]bool, len(e.Votes))
+	for k, v := range e.Votes {
+		cp.Votes[k] = v
+	}
+	return &cp
+}
+
+// GetAliveResidentCount counts alive non-DM players other than Travellers, as win conditions do.
+func (s *State) GetAliveResidentCount() int {
+	count := 0
+	for _, p := range s.Players {
+		if p.Alive && !p.IsDM && !p.Traveller {
+			count++
+		}
+	}
+	return count
+}
+
+// exileVoters are the players who vote on an exile: every seated player, dead or alive.
+func exileVoters(state State) []string {
+	var voters []string
+	for _, uid := range state.SeatOrder {
+		if p, ok := state.Players[uid]; ok && !p.IsDM && p.Departed == "" {
+			voters = append(voters, uid)
+		}
+	}
+	return voters
+}
+
+func handleExile(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	if state.Phase != PhaseDay && state.Phase != PhaseNomination {
+		return nil, nil, fmt.Errorf("engine.handleExile: %w", ErrInvalidPhase)
+	}
+	if state.Exile != nil {
+		return nil, nil, fmt.Errorf("engine.handleExile: an exile vote is already open")
+	}
+	if _, seated := state.Players[cmd.ActorUserID]; !seated {
+		return nil, nil, fmt.Errorf("engine.handleExile: %w", ErrPlayerNotFound)
+	}
+	var payload map[string]string
+	_ = json.Unmarshal(cmd.Payload, &payload)
+	target, ok := state.Players[payload["target"]]
+	if !ok || !target.Traveller || target.Departed != "" {
+		return nil, nil, fmt.Errorf("engine.handleExile: only a seated Traveller can be exiled: %w", ErrInvalidTarget)
+	}
+	return []types.Event{newEvent(cmd, "exile.started", map[string]string{
+		"target":    target.UserID,
+		"nominator": cmd.ActorUserID,
+	})}, acceptedResult(cmd.CommandID), nil
+}
+
+func handleExileVote(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	if state.Exile == nil {
+		return nil, nil, fmt.Errorf("engine.handleExileVote: no exile vote is open")
+	}
+	voter, ok := state.Players[cmd.ActorUserID]
+	if !ok || voter.IsDM || voter.Departed != "" {
+		return nil, nil, fmt.Errorf("engine.handleExileVote: %w", ErrPlayerNotFound)
+	}
+	if _, voted := state.Exile.Votes[cmd.ActorUserID]; voted {
+		return nil, nil, fmt.Errorf("engine.handleExileVote: %w", ErrAlreadyVoted)
+	}
+	var payload map[string]string
+	_ = json.Unmarshal(cmd.Payload, &payload)
+	vote := "no"
+	if payload["vote"] == "yes" {
+		vote = "yes"
+	}
+
+	events := []types.Event{newEvent(cmd, "exile.voted", map[string]string{"voter": cmd.ActorUserID, "vote": vote})}
+	working := state.Copy()
+	working.Exile.Votes[cmd.ActorUserID] = vote == "yes"
+	if len(working.Exile.Votes) >= len(exileVoters(working)) {
+		events = append(events, resolveExile(working, cmd)...)
+	}
+	return events, acceptedResult(cmd.CommandID), nil
+}
+
+// handleResolveExile lets the storyteller close an exile vote before everyone has voted.
+func handleResolveExile(state State, cmd types.CommandEnvelope) ([]types.Event, *types.CommandResult, error) {
+	isAutoDM := types.IsAutoDMActor(cmd.ActorUserID)
+	if !isAutoDM && !IsHumanDM(state, cmd.ActorUserID) && cmd.ActorUserID != state.OwnerID {
+		return nil, nil, fmt.Errorf("engine.handleResolveExile: only the DM can close an exile vote")
+	}
+	if state.Exile == nil {
+		return nil, nil, fmt.Errorf("engine.handleResolveExile: no exile vote is open")
+	}
+	return resolveExile(state, cmd), acceptedResult(cmd.CommandID), nil
+}
+
+// resolveExile exiles the Traveller when yes votes reach half the living players.
+func resolveExile(state State, cmd types.CommandEnvelope) []types.Event {
+	yes := 0
+	for _, v := range state.Exile.Votes {
+		if v {
+			yes++
+		}
+	}
+	threshold := (state.GetAliveCount() + 1) / 2
+	result := "not_exiled"
+	if yes >= threshold {
+		result = "exiled"
+	}
+	events := []types.Event{newEvent(cmd, "exile.resolved", map[string]string{
+		"target":    state.Exile.Target,
+		"result":    result,
+		"votes_for": fmt.Sprintf("%d", yes),
+		"threshold": fmt.Sprintf("%d", threshold),
+	})}
+	if result == "exiled" {
+		events = append(events, newEvent(cmd, "player.exiled", map[string]string{"user_id": state.Exile.Target}))
+	}
+	return events
+}
+
+// reduceExile applies exile events; an exiled Traveller dies and leaves without an execution.
+func (s *State) reduceExile(event EventPayload) {
+	switch event.Type {
+	case "exile.started":
+		s.Exile = &ExileVote{Target: event.Payload["target"], Nominator: event.Payload["nominator"], Votes: map[string]bool{}}
+	case "exile.voted":
+		if s.Exile != nil {
+			s.Exile.Votes[event.Payload["voter"]] = event.Payload["vote"] == "yes"
+		}
+	case "exile.resolved":
+		s.Exile = nil
+	case "player.exiled":
+		if p, ok := s.Players[event.Payload["user_id"]]; ok {
+			p.Alive = false
+			p.Departed = "exiled"
+			s.Players[p.UserID] = p
+		}
+	}
+}
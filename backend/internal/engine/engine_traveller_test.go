@@ -0,0 +1,58 @@
+package engine
+
+import "testing"
+
+func TestExileVoteRemovesTraveller(t *testing.T) {
+	state := newStalledNightState()
+	state.Phase = PhaseDay
+	state.NightActions = nil
+
+	events, _, err := HandleCommand(state, presenceCommand("join", "bard", map[string]string{"name": "Bard", "traveller": "true"}))
+	if err != nil {
+		t.Fatalf("mid-game traveller join: %v", err)
+	}
+	applyEventsToState(&state, events)
+	if bard := state.Players["bard"]; !bard.Traveller || !bard.Alive {
+		t.Fatalf("expected an alive traveller seated mid-game, got %+v", bard)
+	}
+	if _, _, err := HandleCommand(state, presenceCommand("exile", "monk", map[string]string{"target": "chef"})); err == nil {
+		t.Fatal("expected exile to refuse a non-traveller")
+	}
+
+	events, _, err = HandleCommand(state, presenceCommand("exile", "monk", map[string]string{"target": "bard"}))
+	if err != nil {
+		t.Fatalf("exile: %v", err)
+	}
+	applyEventsToState(&state, events)
+	for _, voter := range []string{"monk", "imp", "chef", "bard"} {
+		events, _, err = HandleCommand(state, presenceCommand("exile_vote", voter, map[string]string{"vote": "yes"}))
+		if err != nil {
+			t.Fatalf("exile_vote by %s: %v", voter, err)
+		}
+		applyEventsToState(&state, events)
+	}
+
+	if !hasTestEventType(events, "player.exiled") {
+		t.Fatalf("expected the last vote to exile the traveller, got %v", events)
+	}
+	bard := state.Players["bard"]
+	if bard.Alive || bard.Departed != "exiled" || state.Exile != nil {
+		t.Fatalf("expected the traveller exiled and the vote closed, got %+v exile=%v", bard, state.Exile)
+	}
+	if state.ExecutedToday != "" || hasTestEventType(events, "game.ended") {
+		t.Fatalf("expected no execution and no game end, executed=%q events=%v", state.ExecutedToday, events)
+	}
+	if got := state.GetAliveResidentCount(); got != 3 {
+		t.Fatalf("expected 3 alive residents, got %d", got)
+	}
+}
+
+func TestLobbyTravellersSitOutsideSetupCount(t *testing.T) {
+	state := NewState("room-1")
+	state.Players["a"] = Player{UserID: "a", SeatNumber: 1}
+	state.Players["t"] = Player{UserID: "t", SeatNumber: 2, Traveller: true}
+
+	if userIDs, _ := lobbyPlayers(state); len(userIDs) != 1 || userIDs[0] != "a" {
+		t.Fatalf("expected only the base player in the setup count, got %v", userIDs)
+	}
+}
@@ -0,0 +1,50 @@
+// engine_whisper_dm.go — 私聊说书人的收件人解析
+//
+// 玩家私聊说书人时 DM 不一定在 Players 中 (Auto-DM 从不入座)。whisper 的 to_user_id 可写
+// WhisperToDM ("dm")：有人类 DM 时投递给该 DM，否则投递给 Auto-DM (types.AutoDMActorID)。
+// 发给 DM 的私聊 payload 带 to_dm=true，投影层据此保证 DM 连接可见，AutoDM 据此作为规则提问处理。
+//
+// [IN]  internal/types（AutoDMActorID）
+// [OUT] engine.go（handleWhisper 解析收件人）
+// [OUT] projection（to_dm 私聊对 DM 可见）、agent（发给 Auto-DM 的私聊转为 question）
+// [POS] 私聊路由
+package engine
+
+import (
+	"sort"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// WhisperToDM is the to_user_id alias addressing the room's storyteller.
+const WhisperToDM = "dm"
+
+// whisperRecipient resolves a whisper's to_user_id; toDM reports a storyteller recipient.
+func whisperRecipient(state State, to string) (recipient string, toDM bool, ok bool) {
+	switch {
+	case to == WhisperToDM:
+		if dm := humanDMID(state); dm != "" {
+			return dm, true, true
+		}
+		return types.AutoDMActorID, true, true
+	case types.IsAutoDMActor(to):
+		return to, true, true
+	}
+	p, exists := state.Players[to]
+	return to, exists && p.IsDM, exists
+}
+
+// humanDMID returns the seated human DM, the lowest user ID if there are several.
+func humanDMID(state State) string {
+	var ids []string
+	for uid, p := range state.Players {
+		if p.IsDM && !types.IsAutoDMActor(uid) {
+			ids = append(ids, uid)
+		}
+	}
+	if len(ids) == 0 {
+		return ""
+	}
+	sort.Strings(ids)
+	return ids[0]
+}
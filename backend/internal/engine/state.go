@@ -51,6 +51,19 @@ type Player struct {
 	SpyApparentRole string            `json:"spy_apparent_role,omitempty"` // 间谍在信息角色面前显示的假身份
 	Reminders       []string          `json:"reminders"`
 	NightInfo       map[string]string `json:"night_info,omitempty"`
+
+	// Language 玩家偏好语言 (set_language)，空串表示跟随房间叙事语言
+	Language string `json:"language,omitempty"`
+
+	// 在场状态 (engine_presence.go)：断线玩家仍在座；Departed 为 DM 标记的离场 (traveling/removed)
+	Disconnected bool   `json:"disconnected,omitempty"`
+	Departed     string `json:"departed,omitempty"`
+
+	// Traveller 旅行者 (engine_traveller.go)：不占配板人数，可被流放，不计入胜负存活人数
+	Traveller bool `json:"traveller,omitempty"`
+
+	// RevealedRole 死亡时公开的角色 (reveal_on_death 房规，engine_reveal_on_death.go)，对所有人可见
+	RevealedRole string `json:"revealed_role,omitempty"`
 }
 
 type Nomination struct {
@@ -110,8 +123,10 @@ type State struct {
 	Players               map[string]Player `json:"players"`
 	SeatOrder             []string          `json:"seat_order"` // UserIDs in seat order
 	Nomination            *Nomination       `json:"nomination,omitempty"`
-	NominationQueue       []Nomination      `json:"nomination_queue"`       // Past nominations today
-	OnTheBlock            *OnTheBlockInfo   `json:"on_the_block,omitempty"` // Player about to die
+	NominationQueue       []Nomination      `json:"nomination_queue"`        // Past nominations today
+	OnTheBlock            *OnTheBlockInfo   `json:"on_the_block,omitempty"`  // Player about to die
+	TiedVotes             int               `json:"tied_votes,omitempty"`    // 今日平票的最高票数（0 表示无平票）
+	TiedNominees          []string          `json:"tied_nominees,omitempty"` // 今日以 TiedVotes 平票的被提名者
 	NightActions          []NightAction     `json:"night_actions"`
 	CurrentAction         int               `json:"current_action"` // Index in night actions
 	PendingDeaths         []PendingDeath    `json:"pending_deaths"`
@@ -119,6 +134,7 @@ type State struct {
 	MinionIDs             []string          `json:"minion_ids"`
 	BluffRoles            []string          `json:"bluff_roles"`             // 3 bluffs for demon
 	ExecutedToday         string            `json:"executed_today"`          // UserID of player executed today (for undertaker)
+	NoExecutionToday      bool              `json:"no_execution_today"`      // day.no_execution 已记录：今日结束时无人被处决
 	RedHerringID          string            `json:"red_herring_id"`          // Good player that registers as demon to fortune teller
 	ScarletWomanTriggered bool              `json:"scarlet_woman_triggered"` // 红唇女郎是否已继承，防重复触发
 	AwaitingRavenkeeper   bool              `json:"awaiting_ravenkeeper"`    // 结算层等待守鸦人选择目标
@@ -133,6 +149,24 @@ type State struct {
 	ExtensionsUsed        int               `json:"extensions_used"`
 	Config                GameConfig        `json:"config"`
 	AIDecisionLog         []AIDecisionEntry `json:"ai_decision_log"`
+
+	// LastEventID / LastEventType 记录最近一个可撤回的事件（undo_last_event 的目标）
+	LastEventID   string `json:"last_event_id,omitempty"`
+	LastEventType string `json:"last_event_type,omitempty"`
+	// DebugMode 由服务器调试开关注入（不来自事件），开启后对局中也可使用调试命令
+	DebugMode bool `json:"debug_mode,omitempty"`
+
+	// AutoDMPaused 人类 DM 接管后为 true：Auto-DM 只观察不行动，resume_autodm 恢复
+	AutoDMPaused bool `json:"autodm_paused,omitempty"`
+
+	// Exile 进行中的旅行者流放投票 (engine_traveller.go)，nil 表示没有
+	Exile *ExileVote `json:"exile,omitempty"`
+
+	// DayStartedAt 本白天开始的毫秒时间戳 (提名阶段不重置)，用于讨论时长上下限
+	DayStartedAt int64 `json:"day_started_at,omitempty"`
+
+	// Script 房间自定义剧本 (角色 ID)，非空时开局只从中选角 (engine_script.go)
+	Script []string `json:"script,omitempty"`
 }
 
 type AIDecisionEntry struct {
@@ -157,6 +191,36 @@ type GameConfig struct {
 	ExtensionDurationSec       int `json:"extension_duration_sec"`
 	MaxExtensions              int `json:"max_extensions"`
 	NominationPhaseDurationSec int `json:"nomination_phase_duration_sec"`
+	// AnnounceDeathsAtDawn 为 true 时 (默认；room_settings 的 announce_deaths_at_dawn) 夜晚结束追加 dawn.summary，叙事者一次性公布全部死亡
+	AnnounceDeathsAtDawn bool `json:"announce_deaths_at_dawn"`
+	// DMResolvesTies 为 true 时 (room_settings 的 dm_resolves_ties) 平票不直接作废，DM 可通过 resolve_tie 指定处决对象
+	DMResolvesTies bool `json:"dm_resolves_ties"`
+
+	// ForbidSelfPoison 为 true 时 (room_settings 的 forbid_self_poison) 投毒者不可选择自己
+	ForbidSelfPoison bool `json:"forbid_self_poison"`
+
+	// TranslateAnnouncements 为 true 时 Auto-DM 按玩家偏好语言私聊发送公开公告译文 (额外 LLM 开销)
+	TranslateAnnouncements bool `json:"translate_announcements"`
+	// NarrationLanguage 为 room_settings 的 narration_language：Auto-DM 在本房间的叙事语言 ("zh"、"en")，空串用服务端默认 AUTODM_LANGUAGE
+	NarrationLanguage string `json:"narration_language,omitempty"`
+
+	// DiscussionNudgeSec 白天讨论每沉默多少秒由 Auto-DM 提醒一次 (逐级升级，0 关闭)
+	DiscussionNudgeSec int `json:"discussion_nudge_sec"`
+	// DiscussionNudgeMessage 替换第一级 (温和) 提醒文案，空串用默认
+	DiscussionNudgeMessage string `json:"discussion_nudge_message,omitempty"`
+
+	// DemonSeesMinionRoles 为 true 时首夜互认告知恶魔各爪牙的具体角色；默认只告知爪牙是谁 (爪牙之间总能看到彼此角色)
+	DemonSeesMinionRoles bool `json:"demon_sees_minion_roles"`
+
+	// MinDiscussionSec / MaxDiscussionSec 限定白天时长 (0 不限)：满 Min 后提名结算即可提前入夜，满 Max 强制入夜
+	MinDiscussionSec int `json:"min_discussion_sec,omitempty"`
+	MaxDiscussionSec int `json:"max_discussion_sec,omitempty"`
+
+	// RevealOnDeath 为 true 时玩家死亡后公开其角色 (role.revealed)；基础规则默认隐藏
+	RevealOnDeath bool `json:"reveal_on_death,omitempty"`
+
+	// EarliestNominationWinsTies 为 true 时 (房规) 白天结束仍平票则处决最早被提名的平票者，默认无人处决
+	EarliestNominationWinsTies bool `json:"earliest_nomination_wins_ties,omitempty"`
 }
 
 func DefaultGameConfig() GameConfig {
@@ -169,9 +233,26 @@ func DefaultGameConfig() GameConfig {
 		ExtensionDurationSec:       0,
 		MaxExtensions:              0,
 		NominationPhaseDurationSec: 0,
+		AnnounceDeathsAtDawn:       true,
+		DiscussionNudgeSec:         30,
 	}
 }
 
+// WithDefaultTimeouts returns c with the phase timeouts reset to DefaultGameConfig.
+// Room settings (house rules, nudges, discussion bounds) are kept.
+func (c GameConfig) WithDefaultTimeouts() GameConfig {
+	d := DefaultGameConfig()
+	c.DiscussionDurationSec = d.DiscussionDurationSec
+	c.NominationTimeoutSec = d.NominationTimeoutSec
+	c.DefenseDurationSec = d.DefenseDurationSec
+	c.VotingDurationSec = d.VotingDurationSec
+	c.NightActionTimeoutSec = d.NightActionTimeoutSec
+	c.ExtensionDurationSec = d.ExtensionDurationSec
+	c.MaxExtensions = d.MaxExtensions
+	c.NominationPhaseDurationSec = d.NominationPhaseDurationSec
+	return c
+}
+
 func NewState(roomID string) State {
 	return State{
 		RoomID:          roomID,
@@ -223,6 +304,15 @@ func (s State) Copy() State {
 		otb := *s.OnTheBlock
 		cp.OnTheBlock = &otb
 	}
+	if s.Exile != nil {
+		cp.Exile = s.Exile.copy()
+	}
+	if s.TiedNominees != nil {
+		cp.TiedNominees = append([]string{}, s.TiedNominees...)
+	}
+	if s.Script != nil {
+		cp.Script = append([]string{}, s.Script...)
+	}
 
 	cp.NightActions = make([]NightAction, len(s.NightActions))
 	copy(cp.NightActions, s.NightActions)
@@ -265,6 +355,7 @@ func (s State) Copy() State {
 
 type EventPayload struct {
 	Seq     int64
+	EventID string
 	Type    string
 	Actor   string
 	Payload map[string]string
@@ -279,7 +370,8 @@ func MarshalState(s State) (string, error) {
 }
 
 func UnmarshalState(raw string) (State, error) {
-	var s State
+	// Settings missing from older snapshots keep their defaults
+	s := State{Config: DefaultGameConfig()}
 	if err := json.Unmarshal([]byte(raw), &s); err != nil {
 		return State{}, err
 	}
@@ -345,7 +437,7 @@ func (s *State) CheckWinCondition() (ended bool, winner, reason string) {
 	// Check if demon is dead
 	if demon, ok := s.Players[s.DemonID]; ok && !demon.Alive {
 		// Check for Scarlet Woman takeover (5+ players alive)
-		aliveCount := s.GetAliveCount()
+		aliveCount := s.GetAliveResidentCount()
 		hasScarletWoman := false
 		for _, p := range s.Players {
 			if p.TrueRole == "scarletwoman" && p.Alive {
@@ -358,9 +450,11 @@ func (s *State) CheckWinCondition() (ended bool, winner, reason string) {
 		}
 	}
 
-	// Mayor win: exactly 3 alive, no execution today, mayor alive and not poisoned
-	aliveCount := s.GetAliveCount()
-	if aliveCount == 3 && s.ExecutedToday == "" {
+	// Mayor win: exactly 3 alive, day ended with no execution, mayor alive and not poisoned.
+	// NoExecutionToday survives into the night for the Undertaker, so only check it by day.
+	aliveCount := s.GetAliveResidentCount()
+	isNight := s.Phase == PhaseNight || s.Phase == PhaseFirstNight
+	if aliveCount == 3 && s.NoExecutionToday && !isNight {
 		for _, p := range s.Players {
 			if p.TrueRole == "mayor" && p.Alive && !p.IsPoisoned {
 				return true, "good", "市长在最后三人时达成胜利条件"
@@ -13,6 +13,7 @@ import (
 func (s *State) Reduce(event EventPayload) {
 	s.LastSeq = event.Seq
 	s.ChatSeq++
+	s.trackRetractable(event)
 
 	switch event.Type {
 	case "player.joined":
@@ -60,6 +61,8 @@ func (s *State) Reduce(event EventPayload) {
 		s.reduceExecutionResolved(event)
 	case "player.died":
 		s.reducePlayerDied(event.Payload["user_id"])
+	case "role.revealed":
+		s.reduceRoleRevealed(event)
 	case "player.protected":
 		s.reducePlayerFlag(event.Payload["user_id"], "protected")
 	case "player.poisoned":
@@ -85,6 +88,8 @@ func (s *State) Reduce(event EventPayload) {
 		s.reducePlayerUnpoison(event.Payload["user_id"])
 	case "demon.changed":
 		s.reduceDemonChanged(event)
+	case "tie.resolved":
+		s.putOnTheBlock(event.Payload["user_id"], s.TiedVotes)
 	case "public.chat", "whisper.sent", "evil_team.chat":
 		// Just increment chat seq
 	case "ai.decision":
@@ -97,9 +102,13 @@ func (s *State) Reduce(event EventPayload) {
 		s.WinReason = event.Payload["reason"]
 	case "game.recap":
 		s.GameRecap = event.Payload["summary"]
+	case "day.no_execution":
+		s.ExecutedToday = ""
+		s.NoExecutionToday = true
 	case "player.executed":
 		executedID := event.Payload["user_id"]
 		s.ExecutedToday = executedID
+		s.NoExecutionToday = false
 		s.reducePlayerDied(executedID)
 	case "action.requested":
 		// informational, no state mutation
@@ -118,10 +127,23 @@ func (s *State) Reduce(event EventPayload) {
 		}
 	case "slayer.shot":
 		// informational, death handled by player.died
+	case "player.language_set":
+		s.reduceLanguageSet(event)
+	case "autodm.paused":
+		s.AutoDMPaused = true
+	case "autodm.resumed":
+		s.AutoDMPaused = false
+	case "player.disconnected", "player.reconnected", "player.removed":
+		s.reducePresence(event)
+	case "exile.started", "exile.voted", "exile.resolved", "player.exiled":
+		s.reduceExile(event)
 	}
 }
 
 func (s *State) reducePlayerJoined(event EventPayload) {
+	if _, exists := s.Players[event.Actor]; exists {
+		return
+	}
 	seatNum := len(s.Players) + 1
 	if sn, ok := event.Payload["seat_number"]; ok {
 		if parsed, err := json.Number(sn).Int64(); err == nil {
@@ -134,6 +156,7 @@ func (s *State) reducePlayerJoined(event EventPayload) {
 		SeatNumber:   seatNum,
 		Alive:        true,
 		IsDM:         event.Payload["role"] == "dm",
+		Traveller:    event.Payload["traveller"] == "true",
 		HasGhostVote: true,
 		Reminders:    []string{},
 	}
@@ -183,6 +206,40 @@ func (s *State) reduceRoomSettings(event EventPayload) {
 			s.MaxPlayers = int(parsed)
 		}
 	}
+	if ta, ok := event.Payload["translate_announcements"]; ok {
+		s.Config.TranslateAnnouncements = ta == "true"
+	}
+	if lang, ok := event.Payload["narration_language"]; ok {
+		s.Config.NarrationLanguage = lang
+	}
+	if sec, ok := event.Payload["discussion_nudge_sec"]; ok {
+		if parsed, err := json.Number(sec).Int64(); err == nil && parsed >= 0 {
+			s.Config.DiscussionNudgeSec = int(parsed)
+		}
+	}
+	if msg, ok := event.Payload["discussion_nudge_message"]; ok {
+		s.Config.DiscussionNudgeMessage = msg
+	}
+	if v, ok := event.Payload["demon_sees_minion_roles"]; ok {
+		s.Config.DemonSeesMinionRoles = v == "true"
+	}
+	if v, ok := event.Payload["reveal_on_death"]; ok {
+		s.Config.RevealOnDeath = v == "true"
+	}
+	if v, ok := event.Payload["earliest_nomination_wins_ties"]; ok {
+		s.Config.EarliestNominationWinsTies = v == "true"
+	}
+	if v, ok := event.Payload["dm_resolves_ties"]; ok {
+		s.Config.DMResolvesTies = v == "true"
+	}
+	if v, ok := event.Payload["forbid_self_poison"]; ok {
+		s.Config.ForbidSelfPoison = v == "true"
+	}
+	if v, ok := event.Payload["announce_deaths_at_dawn"]; ok {
+		s.Config.AnnounceDeathsAtDawn = v == "true"
+	}
+	s.reduceDiscussionBounds(event.Payload)
+	s.reduceScript(event.Payload)
 }
 
 func (s *State) reduceRoleAssigned(event EventPayload) {
@@ -241,11 +298,14 @@ func (s *State) reducePhaseDay() {
 	s.DayCount++
 	s.SubPhase = SubPhaseDiscussion
 	s.PhaseStartedAt = time.Now().UnixMilli()
+	s.DayStartedAt = s.PhaseStartedAt
 	s.PhaseEndsAt = time.Now().Add(time.Duration(s.Config.DiscussionDurationSec) * time.Second).UnixMilli()
 	s.Nomination = nil
 	s.NominationQueue = []Nomination{}
 	s.OnTheBlock = nil
+	s.clearTie()
 	s.ExecutedToday = ""
+	s.NoExecutionToday = false
 	s.ExtensionsUsed = 0
 }
 
@@ -384,14 +444,9 @@ func (s *State) reduceNominationResolved(event EventPayload) {
 	}
 	switch result {
 	case "on_the_block":
-		nominee := s.Players[s.Nomination.Nominee]
-		s.OnTheBlock = &OnTheBlockInfo{
-			UserID:     s.Nomination.Nominee,
-			VotesFor:   votesFor,
-			SeatNumber: nominee.SeatNumber,
-		}
+		s.putOnTheBlock(s.Nomination.Nominee, votesFor)
 	case "tied":
-		s.OnTheBlock = nil // Tie clears the block — no execution
+		s.recordTie(s.Nomination.Nominee, votesFor) // Tie clears the block — no execution
 	}
 }
 
@@ -401,6 +456,7 @@ func (s *State) reduceExecutionResolved(event EventPayload) {
 	}
 	executedID := event.Payload["executed"]
 	s.ExecutedToday = executedID
+	s.NoExecutionToday = false
 	s.reducePlayerDied(executedID)
 }
 
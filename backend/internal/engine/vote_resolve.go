@@ -3,7 +3,7 @@
 // resolveVoteAndCheckWin 为 handleVote（全票自动结算）和
 // handleCloseVote（autodm 强制结算）提供唯一结算路径，保证：
 //   - 阈值计算一致：(aliveCount+1)/2
-//   - 事件字段一致：nomination.resolved(votes_for, votes_against, threshold)
+//   - 事件字段一致：nomination.resolved(votes_for, votes_against, threshold, alive_count)
 //   - "待处决"(on_the_block) 延迟处决：投票达标不立即处决，
 //     而是记录到 OnTheBlock，白天结束时统一处决得票最高者
 package engine
@@ -40,7 +40,7 @@ func resolveNomination(state State, cmd types.CommandEnvelope) (string, []types.
 	aliveCount := state.GetAliveCount()
 	threshold := (aliveCount + 1) / 2
 
-	result := determineBlockResult(yesVotes, threshold, state.OnTheBlock)
+	result := determineBlockResult(yesVotes, threshold, state.highestBlockVotes())
 
 	events := []types.Event{
 		newEvent(cmd, "nomination.resolved", map[string]string{
@@ -48,6 +48,7 @@ func resolveNomination(state State, cmd types.CommandEnvelope) (string, []types.
 			"votes_for":     fmt.Sprintf("%d", yesVotes),
 			"votes_against": fmt.Sprintf("%d", len(nom.Votes)-yesVotes),
 			"threshold":     fmt.Sprintf("%d", threshold),
+			"alive_count":   fmt.Sprintf("%d", aliveCount),
 		}),
 	}
 
@@ -55,17 +56,16 @@ func resolveNomination(state State, cmd types.CommandEnvelope) (string, []types.
 }
 
 // determineBlockResult decides the nomination outcome per official BotC rules.
-func determineBlockResult(yesVotes, threshold int, current *OnTheBlockInfo) string {
+// highest is today's top vote count so far (on the block or tied; 0 if none),
+// so a later nominee must strictly beat an earlier tie to go on the block.
+func determineBlockResult(yesVotes, threshold, highest int) string {
 	if yesVotes < threshold {
 		return "not_on_the_block"
 	}
-	if current == nil {
+	if yesVotes > highest {
 		return "on_the_block"
 	}
-	if yesVotes > current.VotesFor {
-		return "on_the_block"
-	}
-	if yesVotes == current.VotesFor {
+	if yesVotes == highest {
 		return "tied"
 	}
 	return "not_on_the_block"
@@ -57,7 +57,7 @@ func (rc *RandomComposer) ComposeRoles(_ context.Context, req ComposeRequest) (*
 		return nil, fmt.Errorf("compose.ComposeRoles: no distribution for %d players", req.PlayerCount)
 	}
 
-	roles, _, err := selectRolesRandomly(dist, req.PlayerCount)
+	roles, _, err := selectRolesRandomly(dist, req.PlayerCount, nil, randInt)
 	if err != nil {
 		return nil, fmt.Errorf("compose.ComposeRoles: %w", err)
 	}
@@ -0,0 +1,45 @@
+// Package game 自定义模式的角色数量分配
+//
+// SetupConfig.Distribution 非 nil 时以其镇民/外来者/爪牙/恶魔数量代替官方分配表
+// (例如多爪牙模式)。ValidateDistribution 要求各数量非负、至少一个恶魔、合计恰为玩家数；
+// 男爵 +2 外来者的规则照常生效。
+//
+// [OUT] setup.go（GenerateAssignments 选取分配表）
+// [POS] 配板阶段的数量分配覆盖
+package game
+
+import "fmt"
+
+// ValidateDistribution checks that d is a consistent distribution for playerCount players.
+func ValidateDistribution(d PlayerDistribution, playerCount int) error {
+	if d.Townsfolk < 0 || d.Outsiders < 0 || d.Minions < 0 || d.Demons < 0 {
+		return fmt.Errorf("game.ValidateDistribution: role counts must not be negative")
+	}
+	if d.Demons < 1 {
+		return fmt.Errorf("game.ValidateDistribution: distribution needs at least one demon")
+	}
+	if d.PlayerCount != 0 && d.PlayerCount != playerCount {
+		return fmt.Errorf("game.ValidateDistribution: distribution is for %d players, have %d", d.PlayerCount, playerCount)
+	}
+	if total := d.Townsfolk + d.Outsiders + d.Minions + d.Demons; total != playerCount {
+		return fmt.Errorf("game.ValidateDistribution: role counts add up to %d, have %d players", total, playerCount)
+	}
+	return nil
+}
+
+// distribution is the override when set, otherwise the official table for playerCount.
+func (sa *SetupAgent) distribution(playerCount int) (*PlayerDistribution, error) {
+	if sa.config.Distribution == nil {
+		dist := GetDistribution(playerCount)
+		if dist == nil {
+			return nil, fmt.Errorf("no distribution for %d players", playerCount)
+		}
+		return dist, nil
+	}
+	if err := ValidateDistribution(*sa.config.Distribution, playerCount); err != nil {
+		return nil, err
+	}
+	dist := *sa.config.Distribution
+	dist.PlayerCount = playerCount
+	return &dist, nil
+}
@@ -0,0 +1,154 @@
+// Package game 强制角色分配：按指定的 玩家→角色 布局建立 SetupResult
+//
+// 复现 bug 报告时需要固定的角色布局，ForceAssignments 不经过 SetupAgent 的选角与洗牌，
+// 直接使用给定映射，但仍校验布局是合法剧本：角色存在且不重复、恰好一个恶魔，
+// 爪牙/外来者/镇民数量符合人数分配表 (男爵在场时外来者 +2)。酒鬼的自认角色、间谍假身份、
+// 恶魔伪装与首夜顺序沿用随机配板的同一套规则。
+//
+// [OUT] engine（force_assignments 调试命令）
+// [POS] 游戏初始化的确定性入口
+package game
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ForceAssignments builds a SetupResult from a fixed userID → roleID layout; seats maps
+// userID → seat number. The layout must be a legal script for its player count.
+func ForceAssignments(roles map[string]string, seats map[string]int) (*SetupResult, error) {
+	userIDs := make([]string, 0, len(roles))
+	for uid := range roles {
+		userIDs = append(userIDs, uid)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return seats[userIDs[i]] < seats[userIDs[j]] })
+
+	inPlay := make([]Role, 0, len(userIDs))
+	for _, uid := range userIDs {
+		role := GetRoleByID(roles[uid])
+		if role == nil {
+			return nil, fmt.Errorf("game.ForceAssignments: unknown role ID %q for %s", roles[uid], uid)
+		}
+		inPlay = append(inPlay, *role)
+	}
+	if err := ValidateLayout(inPlay); err != nil {
+		return nil, fmt.Errorf("game.ForceAssignments: %w", err)
+	}
+
+	assignments := make(map[string]Assignment, len(userIDs))
+	for i, uid := range userIDs {
+		assignments[uid] = Assignment{
+			UserID:        uid,
+			SeatNumber:    seats[uid],
+			Role:          inPlay[i].ID,
+			TrueRole:      inPlay[i].ID,
+			PerceivedRole: inPlay[i].ID,
+			Team:          inPlay[i].Team,
+		}
+	}
+	drunkRole := assignDrunkPerception(inPlay, assignments)
+	linkEvilTeam(assignments)
+
+	townsfolk, outsiders := GetRolesByType(RoleTownsfolk), GetRolesByType(RoleOutsider)
+	assignSpyApparentRole(inPlay, assignments, townsfolk, outsiders)
+	return &SetupResult{
+		Assignments:   assignments,
+		BluffRoles:    generateBluffs(inPlay, townsfolk, outsiders),
+		NightOrder:    GenerateNightOrder(inPlay, assignments, true),
+		DrunkRole:     drunkRole,
+		BaronModified: hasRole(inPlay, "baron"),
+	}, nil
+}
+
+// ValidateLayout checks that roles form a legal script for len(roles) players.
+func ValidateLayout(roles []Role) error {
+	dist := GetDistribution(len(roles))
+	if dist == nil {
+		return fmt.Errorf("no distribution for %d players", len(roles))
+	}
+	seen := make(map[string]bool, len(roles))
+	counts := make(map[RoleType]int)
+	for _, r := range roles {
+		if seen[r.ID] {
+			return fmt.Errorf("role %s assigned twice", r.ID)
+		}
+		seen[r.ID] = true
+		counts[r.Type]++
+	}
+	want := map[RoleType]int{
+		RoleDemon:     dist.Demons,
+		RoleMinion:    dist.Minions,
+		RoleOutsider:  dist.Outsiders,
+		RoleTownsfolk: dist.Townsfolk,
+	}
+	if hasRole(roles, "baron") {
+		want[RoleOutsider] += 2
+		want[RoleTownsfolk] -= 2
+	}
+	for _, t := range []RoleType{RoleDemon, RoleMinion, RoleOutsider, RoleTownsfolk} {
+		if counts[t] != want[t] {
+			return fmt.Errorf("%d players need %d %s, got %d", len(roles), want[t], t, counts[t])
+		}
+	}
+	return nil
+}
+
+// assignDrunkPerception gives the drunk a random not-in-play townsfolk to believe in.
+func assignDrunkPerception(inPlay []Role, assignments map[string]Assignment) string {
+	var candidates []Role
+	for _, t := range GetRolesByType(RoleTownsfolk) {
+		if !hasRole(inPlay, t.ID) {
+			candidates = append(candidates, t)
+		}
+	}
+	for uid, a := range assignments {
+		if a.TrueRole != "drunk" || len(candidates) == 0 {
+			continue
+		}
+		idx, _ := randInt(len(candidates))
+		a.PerceivedRole = candidates[idx].ID
+		assignments[uid] = a
+		return a.PerceivedRole
+	}
+	return ""
+}
+
+// linkEvilTeam fills Teammates and DemonID for every evil assignment.
+func linkEvilTeam(assignments map[string]Assignment) {
+	var demonID string
+	var minionIDs []string
+	for uid, a := range assignments {
+		if r := GetRoleByID(a.TrueRole); r != nil && r.Type == RoleDemon {
+			demonID = uid
+		} else if r != nil && r.Type == RoleMinion {
+			minionIDs = append(minionIDs, uid)
+		}
+	}
+	sort.Strings(minionIDs)
+	for uid, a := range assignments {
+		if a.Team != TeamEvil {
+			continue
+		}
+		if uid == demonID {
+			a.Teammates = minionIDs
+		} else {
+			a.Teammates = []string{demonID}
+			for _, mid := range minionIDs {
+				if mid != uid {
+					a.Teammates = append(a.Teammates, mid)
+				}
+			}
+		}
+		a.DemonID = demonID
+		assignments[uid] = a
+	}
+}
+
+func hasRole(roles []Role, id string) bool {
+	for _, r := range roles {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package game
+
+import "testing"
+
+func TestForceAssignmentsHonoursBaronAndDrunk(t *testing.T) {
+	layout := map[string]string{
+		"u1": "imp", "u2": "baron", "u3": "drunk", "u4": "saint",
+		"u5": "empath", "u6": "chef", "u7": "monk",
+	}
+	seats := map[string]int{"u1": 1, "u2": 2, "u3": 3, "u4": 4, "u5": 5, "u6": 6, "u7": 7}
+	result, err := ForceAssignments(layout, seats)
+	if err != nil {
+		t.Fatalf("expected baron layout with two outsiders to be legal: %v", err)
+	}
+	if !result.BaronModified || result.Assignments["u2"].DemonID != "u1" {
+		t.Fatalf("expected baron linked to the demon, got %+v", result.Assignments["u2"])
+	}
+	drunk := result.Assignments["u3"]
+	if drunk.TrueRole != "drunk" || drunk.PerceivedRole == "drunk" || GetRoleByID(drunk.PerceivedRole).Type != RoleTownsfolk {
+		t.Fatalf("expected the drunk to believe in a townsfolk, got %+v", drunk)
+	}
+
+	layout["u4"] = "butler"
+	layout["u3"] = "librarian"
+	if _, err := ForceAssignments(layout, seats); err == nil {
+		t.Fatal("expected baron layout with one outsider to be rejected")
+	}
+}
@@ -59,7 +59,10 @@ type GameContext struct {
 	NightNumber         int
 	RedHerringID        string // For fortune teller
 	ExecutedToday       string // UserID of player executed today (for undertaker)
+	NoExecutionToday    bool   // day.no_execution recorded: the previous day ended without an execution
 	RecluseRegisterEvil bool   // Whether recluse registers as evil this night (storyteller decision)
+
+	ForbidSelfPoison bool // 房间规则：投毒者不可对自己下毒
 }
 
 // PlayerState represents a player's current state.
@@ -538,7 +541,7 @@ func (na *NightAgent) resolveUndertaker(req AbilityRequest, malfunctioning bool)
 	}
 
 	executedID := na.ctx.ExecutedToday
-	if executedID == "" {
+	if na.ctx.NoExecutionToday || executedID == "" {
 		return &AbilityResult{
 			Success:    true,
 			Message:    "今天没有玩家被处决",
@@ -689,6 +692,12 @@ func (na *NightAgent) resolvePoisoner(req AbilityRequest, malfunctioning bool) (
 	}
 
 	targetID := req.TargetIDs[0]
+	if p := na.ctx.Players[req.UserID]; p != nil && !p.IsAlive {
+		return &AbilityResult{Success: true, Message: "你已死亡，今晚无法下毒"}, nil
+	}
+	if na.ctx.ForbidSelfPoison && targetID == req.UserID {
+		return &AbilityResult{Success: false, Message: "投毒者不能对自己下毒"}, nil
+	}
 
 	result := &AbilityResult{
 		Success: true,
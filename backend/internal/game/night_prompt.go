@@ -0,0 +1,81 @@
+// Package game 角色化夜晚行动提示
+//
+// 按角色给出带目标约束的行动说明（如占卜师"选择两名玩家"、僧侣"不能选自己"），
+// 未登记的角色按 ActionType 回退到通用说明。
+//
+// [OUT] engine（night.action.prompt 的 prompt 字段）
+// [POS] 夜晚行动提示文案的唯一来源
+package game
+
+import "fmt"
+
+// nightPrompt is a role's night instruction in English and Chinese.
+type nightPrompt struct {
+	EN string
+	CN string
+}
+
+// nightPrompts holds role-specific instructions with their target constraints.
+var nightPrompts = map[string]nightPrompt{
+	"fortuneteller": {
+		EN: "Choose two players (alive or dead, you may include yourself): you learn whether either is the Demon.",
+		CN: "请选择两名玩家（存活或死亡均可，可以包括自己）：你会得知他们中是否有恶魔。",
+	},
+	"monk": {
+		EN: "Choose a player to protect (not yourself): they are safe from the Demon tonight.",
+		CN: "请选择一名玩家进行保护（不能选自己）：他们今晚免受恶魔伤害。",
+	},
+	"butler": {
+		EN: "Choose your master (not yourself): tomorrow you may only vote if they are voting too.",
+		CN: "请选择你的主人（不能选自己）：明天只有当主人投票时你才能投票。",
+	},
+	"poisoner": {
+		EN: "Choose a player to poison: they are poisoned tonight and tomorrow day.",
+		CN: "请选择一名玩家下毒：他们今晚和明天白天中毒。",
+	},
+	"imp": {
+		EN: "Choose a player to kill. Choosing yourself passes the Demon to a living Minion.",
+		CN: "请选择一名玩家击杀。选择自己会把恶魔身份传给一名存活的爪牙。",
+	},
+	"ravenkeeper": {
+		EN: "You died tonight. Choose a player: you learn their character.",
+		CN: "你在今晚死亡。请选择一名玩家：你会得知他们的角色。",
+	},
+}
+
+// NightPrompt returns the English night instruction for a role.
+func NightPrompt(roleID string) string {
+	return lookupNightPrompt(roleID).EN
+}
+
+// NightPromptCN returns the Chinese night instruction for a role.
+func NightPromptCN(roleID string) string {
+	return lookupNightPrompt(roleID).CN
+}
+
+func lookupNightPrompt(roleID string) nightPrompt {
+	if p, ok := nightPrompts[roleID]; ok {
+		return p
+	}
+	role := GetRoleByID(roleID)
+	if role == nil {
+		return nightPrompt{EN: "Wait for the storyteller.", CN: "请等待说书人。"}
+	}
+	switch role.NightActionType {
+	case ActionSelectOne:
+		return nightPrompt{
+			EN: fmt.Sprintf("Choose one player for your %s ability.", role.Name),
+			CN: fmt.Sprintf("请为你的%s能力选择一名玩家。", role.NameCN),
+		}
+	case ActionSelectTwo:
+		return nightPrompt{
+			EN: fmt.Sprintf("Choose two players for your %s ability.", role.Name),
+			CN: fmt.Sprintf("请为你的%s能力选择两名玩家。", role.NameCN),
+		}
+	default:
+		return nightPrompt{
+			EN: fmt.Sprintf("Stay still: the storyteller will tell you what your %s learns tonight.", role.Name),
+			CN: fmt.Sprintf("请稍候：说书人会告诉你%s今晚得知的信息。", role.NameCN),
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNightPromptIsRoleAware(t *testing.T) {
+	if p := NightPrompt("fortuneteller"); !strings.Contains(p, "two players") {
+		t.Fatalf("expected fortune teller prompt to ask for two players, got %q", p)
+	}
+	if p := NightPrompt("monk"); !strings.Contains(p, "not yourself") {
+		t.Fatalf("expected monk prompt to forbid self-protection, got %q", p)
+	}
+	if p := NightPrompt("butler"); !strings.Contains(p, "master") {
+		t.Fatalf("expected butler prompt to ask for a master, got %q", p)
+	}
+	if p := NightPrompt("empath"); !strings.Contains(p, "Empath") {
+		t.Fatalf("expected info roles to fall back to a named prompt, got %q", p)
+	}
+	if p := NightPromptCN("monk"); !strings.Contains(p, "不能选自己") {
+		t.Fatalf("expected the Chinese monk prompt to forbid self-protection, got %q", p)
+	}
+}
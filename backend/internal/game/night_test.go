@@ -101,3 +101,25 @@ func TestGetRoleDisplayNamePrefersChineseName(t *testing.T) {
 		t.Fatalf("expected Chinese role name, got %q", got)
 	}
 }
+
+func TestResolvePoisonerDeadOrForbiddenSelfHasNoEffect(t *testing.T) {
+	ctx := &GameContext{
+		Players: map[string]*PlayerState{
+			"poisoner": {UserID: "poisoner", TrueRole: "poisoner", IsAlive: true},
+			"chef":     {UserID: "chef", TrueRole: "chef", IsAlive: true},
+		},
+		ForbidSelfPoison: true,
+	}
+	agent := NewNightAgent(ctx)
+
+	result, _ := agent.ResolveAbility(AbilityRequest{UserID: "poisoner", RoleID: "poisoner", TargetIDs: []string{"poisoner"}})
+	if result.Success || len(result.Effects) != 0 {
+		t.Fatalf("expected self-poison to be rejected, got %+v", result)
+	}
+
+	ctx.Players["poisoner"].IsAlive = false
+	result, _ = agent.ResolveAbility(AbilityRequest{UserID: "poisoner", RoleID: "poisoner", TargetIDs: []string{"chef"}})
+	if len(result.Effects) != 0 {
+		t.Fatalf("expected a dead poisoner to poison nobody, got %+v", result.Effects)
+	}
+}
@@ -0,0 +1,60 @@
+// Package game 可注入的随机源
+//
+// 配板洗牌、恶魔伪装、说书人随机选择统一经 randInt 取数；默认使用 crypto/rand，
+// 测试可用 SetRandomizer 替换为确定性序列。SetupConfig.Seed 非零时选角改用
+// seededRandInt，同一种子总是选出同一组角色 (配板预览后按所选种子开局)。
+//
+// [OUT] setup.go / night.go（randInt）、setup.go（seededRandInt）
+// [POS] 游戏规则层的随机性注入点
+package game
+
+import (
+	"crypto/rand"
+	"math/big"
+	mrand "math/rand/v2"
+)
+
+// randSource draws from [0, n); SetRandomizer swaps it for deterministic games.
+var randSource = cryptoRandInt
+
+// randInt returns a random int in [0, n).
+func randInt(n int) (int, error) {
+	if n <= 0 {
+		return 0, nil
+	}
+	return randSource(n)
+}
+
+func cryptoRandInt(n int) (int, error) {
+	nBig, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(nBig.Int64()), nil
+}
+
+// SetRandomizer replaces the source behind role shuffles, bluffs and storyteller picks
+// (n > 0 always) and returns a func restoring the previous one. Intended for tests.
+func SetRandomizer(fn func(n int) (int, error)) (restore func()) {
+	prev := randSource
+	randSource = fn
+	return func() { randSource = prev }
+}
+
+// seededRandInt returns a deterministic source: one seed always draws the same sequence.
+func seededRandInt(seed int64) func(n int) (int, error) {
+	r := mrand.New(mrand.NewPCG(uint64(seed), 0))
+	return func(n int) (int, error) {
+		return r.IntN(n), nil
+	}
+}
+
+// NewSetupSeed returns a random non-zero setup seed that survives a round trip through
+// a JSON number (below 2^53).
+func NewSetupSeed() int64 {
+	n, err := cryptoRandInt(1<<53 - 1)
+	if err != nil {
+		return 1
+	}
+	return int64(n) + 1
+}
@@ -0,0 +1,27 @@
+package game
+
+import "testing"
+
+func TestFortuneTellerMetadataSelectsTwoEveryNight(t *testing.T) {
+	ft := GetRoleByID("fortuneteller")
+	if ft == nil {
+		t.Fatal("fortune teller missing from the catalog")
+	}
+	if ft.TargetCount != 2 || !ft.ActsFirstNight || !ft.ActsOtherNights || ft.ActionType != "check" {
+		t.Fatalf("expected a two-target check every night, got %+v", ft)
+	}
+}
+
+func TestEveryRoleHasAbilityMetadata(t *testing.T) {
+	for _, r := range GetAllRoles() {
+		if r.ActionType == "" {
+			t.Errorf("%s has no ActionType", r.ID)
+		}
+		if r.ActsFirstNight != (r.FirstNightOrder > 0 && r.FirstNightActionType != ActionNoAction) {
+			t.Errorf("%s: ActsFirstNight disagrees with its first-night order", r.ID)
+		}
+		if r.NightActionType == ActionSelectTwo && r.TargetCount != 2 || r.NightActionType == ActionSelectOne && r.TargetCount != 1 {
+			t.Errorf("%s: TargetCount %d disagrees with %s", r.ID, r.TargetCount, r.NightActionType)
+		}
+	}
+}
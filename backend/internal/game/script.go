@@ -0,0 +1,71 @@
+// Package game 自定义剧本：限定本局可出现的角色
+//
+// 房间可设置剧本 (角色 ID 列表) 代替整个版本的角色池。ValidateScript 校验角色已知、不重复
+// 且至少含一个恶魔；NewScript 解析为 Script 交给 SetupConfig，随机选角、恶魔 bluff、
+// 酒鬼自认角色与间谍假身份都只从剧本中抽取。剧本角色不足以凑齐该人数的分配表时开局报错。
+//
+// [OUT] engine（room_settings 校验、开局与配板预览时限定角色池）
+// [POS] 配板阶段的角色池约束
+package game
+
+import "fmt"
+
+// ValidateScript checks that roleIDs form a usable script: known, unique, with a demon.
+func ValidateScript(roleIDs []string) error {
+	seen := make(map[string]bool, len(roleIDs))
+	hasDemon := false
+	for _, id := range roleIDs {
+		role := GetRoleByID(id)
+		if role == nil {
+			return fmt.Errorf("game.ValidateScript: unknown role ID: %s", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("game.ValidateScript: duplicate role: %s", id)
+		}
+		seen[id] = true
+		hasDemon = hasDemon || role.Type == RoleDemon
+	}
+	if !hasDemon {
+		return fmt.Errorf("game.ValidateScript: script needs at least one demon")
+	}
+	return nil
+}
+
+// NewScript validates roleIDs and resolves them into a Script for edition.
+func NewScript(edition Edition, roleIDs []string) (*Script, error) {
+	if err := ValidateScript(roleIDs); err != nil {
+		return nil, err
+	}
+	script := &Script{Edition: edition, RolesPool: make([]Role, 0, len(roleIDs))}
+	for _, id := range roleIDs {
+		script.RolesPool = append(script.RolesPool, *GetRoleByID(id))
+	}
+	return script, nil
+}
+
+// RolesByType returns the script's roles of roleType; a nil script offers the whole edition.
+func (sc *Script) RolesByType(roleType RoleType) []Role {
+	if sc == nil {
+		return GetRolesByType(roleType)
+	}
+	var roles []Role
+	for _, r := range sc.RolesPool {
+		if r.Type == roleType {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+// checkDraw reports a random draw that the script could not fill to the distribution.
+func checkDraw(selected []Role, dist *PlayerDistribution, playerCount int) error {
+	counts := make(map[RoleType]int)
+	for _, r := range selected {
+		counts[r.Type]++
+	}
+	if len(selected) != playerCount || counts[RoleDemon] != dist.Demons || counts[RoleMinion] != dist.Minions {
+		return fmt.Errorf("role pool has too few roles for %d players (%d demon, %d minion, %d good needed)",
+			playerCount, dist.Demons, dist.Minions, playerCount-dist.Demons-dist.Minions)
+	}
+	return nil
+}
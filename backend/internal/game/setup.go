@@ -5,9 +5,7 @@
 package game
 
 import (
-	"crypto/rand"
 	"fmt"
-	"math/big"
 )
 
 // Edition represents a game edition.
@@ -27,12 +25,16 @@ type Script struct {
 
 // SetupConfig holds configuration for game setup.
 type SetupConfig struct {
-	Script      *Script
-	Edition     string // Edition ID (tb, bmr, snv)
+	Script      *Script // Restricts the draw, bluffs and drunk/spy pretences (nil = whole edition)
+	Edition     string  // Edition ID (tb, bmr, snv)
 	PlayerCount int
 	CustomRoles []string // Override automatic role selection
 	BaronActive bool     // Add +2 outsiders
 	DrunkTarget string   // Role that drunk thinks they are
+	Seed        int64    // Non-zero: deterministic random role selection
+
+	// Distribution overrides the official role counts for custom modes (nil = official table)
+	Distribution *PlayerDistribution
 }
 
 // SetupResult holds the result of role assignment.
@@ -83,17 +85,16 @@ func (sa *SetupAgent) GenerateAssignments(userIDs []string, seatOrder []int) (*S
 		return nil, fmt.Errorf("player count must be between 5 and 15, got %d", playerCount)
 	}
 
-	dist := GetDistribution(playerCount)
-	if dist == nil {
-		return nil, fmt.Errorf("no distribution for %d players", playerCount)
+	dist, err := sa.distribution(playerCount)
+	if err != nil {
+		return nil, fmt.Errorf("setup.GenerateAssignments: %w", err)
 	}
 
-	var err error
 	baronInPlay := false
 
 	// Get available roles by type (needed for bluffs even with CustomRoles)
-	availableTownsfolk := GetRolesByType(RoleTownsfolk)
-	availableOutsiders := GetRolesByType(RoleOutsider)
+	availableTownsfolk := sa.config.Script.RolesByType(RoleTownsfolk)
+	availableOutsiders := sa.config.Script.RolesByType(RoleOutsider)
 
 	var selectedRoles []Role
 
@@ -111,7 +112,11 @@ func (sa *SetupAgent) GenerateAssignments(userIDs []string, seatOrder []int) (*S
 		}
 	} else {
 		// Random role selection
-		selectedRoles, baronInPlay, err = selectRolesRandomly(dist, playerCount)
+		randn := randInt
+		if sa.config.Seed != 0 {
+			randn = seededRandInt(sa.config.Seed)
+		}
+		selectedRoles, baronInPlay, err = selectRolesRandomly(dist, playerCount, sa.config.Script, randn)
 		if err != nil {
 			return nil, fmt.Errorf("setup.GenerateAssignments: %w", err)
 		}
@@ -235,7 +240,7 @@ func (sa *SetupAgent) GenerateAssignments(userIDs []string, seatOrder []int) (*S
 }
 
 // selectRandomRoles selects n random roles from the available pool.
-func selectRandomRoles(pool []Role, count int) ([]Role, error) {
+func selectRandomRoles(pool []Role, count int, randn func(int) (int, error)) ([]Role, error) {
 	if count > len(pool) {
 		count = len(pool)
 	}
@@ -249,7 +254,7 @@ func selectRandomRoles(pool []Role, count int) ([]Role, error) {
 
 	selected := make([]Role, 0, count)
 	for i := 0; i < count; i++ {
-		idx, err := randInt(len(poolCopy))
+		idx, err := randn(len(poolCopy))
 		if err != nil {
 			return nil, err
 		}
@@ -275,18 +280,6 @@ func shuffleRoles(roles []Role) ([]Role, error) {
 	return shuffled, nil
 }
 
-// randInt returns a random int in [0, n).
-func randInt(n int) (int, error) {
-	if n <= 0 {
-		return 0, nil
-	}
-	nBig, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
-	if err != nil {
-		return 0, err
-	}
-	return int(nBig.Int64()), nil
-}
-
 // generateBluffs generates 3 safe bluff roles for the demon.
 func generateBluffs(inPlay []Role, townsfolk, outsiders []Role) []string {
 	inPlayIDs := make(map[string]bool)
@@ -389,23 +382,24 @@ func resolveCustomRoles(roleIDs []string, playerCount int) ([]Role, error) {
 	return roles, nil
 }
 
-// selectRolesRandomly picks roles randomly according to distribution with Baron auto-detection.
-func selectRolesRandomly(dist *PlayerDistribution, playerCount int) ([]Role, bool, error) {
-	availableDemons := GetRolesByType(RoleDemon)
-	availableMinions := GetRolesByType(RoleMinion)
-	availableOutsiders := GetRolesByType(RoleOutsider)
-	availableTownsfolk := GetRolesByType(RoleTownsfolk)
+// selectRolesRandomly picks roles from script (nil = whole edition) with randn according to
+// distribution with Baron auto-detection.
+func selectRolesRandomly(dist *PlayerDistribution, playerCount int, script *Script, randn func(int) (int, error)) ([]Role, bool, error) {
+	availableDemons := script.RolesByType(RoleDemon)
+	availableMinions := script.RolesByType(RoleMinion)
+	availableOutsiders := script.RolesByType(RoleOutsider)
+	availableTownsfolk := script.RolesByType(RoleTownsfolk)
 
 	selected := make([]Role, 0, playerCount)
 	baronInPlay := false
 
-	demons, err := selectRandomRoles(availableDemons, dist.Demons)
+	demons, err := selectRandomRoles(availableDemons, dist.Demons, randn)
 	if err != nil {
 		return nil, false, fmt.Errorf("selecting demons: %w", err)
 	}
 	selected = append(selected, demons...)
 
-	minions, err := selectRandomRoles(availableMinions, dist.Minions)
+	minions, err := selectRandomRoles(availableMinions, dist.Minions, randn)
 	if err != nil {
 		return nil, false, fmt.Errorf("selecting minions: %w", err)
 	}
@@ -420,18 +414,21 @@ func selectRolesRandomly(dist *PlayerDistribution, playerCount int) ([]Role, boo
 		}
 	}
 
-	outsiders, err := selectRandomRoles(availableOutsiders, outsiderCount)
+	outsiders, err := selectRandomRoles(availableOutsiders, outsiderCount, randn)
 	if err != nil {
 		return nil, false, fmt.Errorf("selecting outsiders: %w", err)
 	}
 	selected = append(selected, outsiders...)
 
 	remaining := playerCount - len(selected)
-	townsfolk, err := selectRandomRoles(availableTownsfolk, remaining)
+	townsfolk, err := selectRandomRoles(availableTownsfolk, remaining, randn)
 	if err != nil {
 		return nil, false, fmt.Errorf("selecting townsfolk: %w", err)
 	}
 	selected = append(selected, townsfolk...)
+	if err := checkDraw(selected, dist, playerCount); err != nil {
+		return nil, false, err
+	}
 
 	return selected, baronInPlay, nil
 }
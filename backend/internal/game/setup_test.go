@@ -66,3 +66,97 @@ func TestDrunkPerceivedRoleExcludesInPlayTownsfolk(t *testing.T) {
 		t.Fatalf("expected drunk role to exclude in-play townsfolk, got %q", result.DrunkRole)
 	}
 }
+
+func TestScriptRestrictsAssignedRoles(t *testing.T) {
+	roleIDs := []string{"imp", "poisoner", "baron", "washerwoman", "librarian", "chef", "empath", "monk", "soldier", "butler", "saint"}
+	script, err := NewScript(EditionTroubleBrewing, roleIDs)
+	if err != nil {
+		t.Fatalf("NewScript: %v", err)
+	}
+	inScript := make(map[string]bool, len(roleIDs))
+	for _, id := range roleIDs {
+		inScript[id] = true
+	}
+	users := []string{"u1", "u2", "u3", "u4", "u5", "u6", "u7"}
+
+	for seed := int64(1); seed <= 50; seed++ {
+		result, err := NewSetupAgent(SetupConfig{Script: script, PlayerCount: len(users), Seed: seed}).GenerateAssignments(users, nil)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		for uid, a := range result.Assignments {
+			if !inScript[a.TrueRole] {
+				t.Fatalf("seed %d: %s assigned %s outside the script", seed, uid, a.TrueRole)
+			}
+		}
+		for _, bluff := range result.BluffRoles {
+			if !inScript[bluff] {
+				t.Fatalf("seed %d: bluff %s outside the script", seed, bluff)
+			}
+		}
+	}
+}
+
+func TestScriptTooSmallForPlayerCountFails(t *testing.T) {
+	script, err := NewScript(EditionTroubleBrewing, []string{"imp", "poisoner", "chef", "empath", "monk"})
+	if err != nil {
+		t.Fatalf("NewScript: %v", err)
+	}
+	users := []string{"u1", "u2", "u3", "u4", "u5", "u6", "u7"}
+	if _, err := NewSetupAgent(SetupConfig{Script: script, PlayerCount: len(users)}).GenerateAssignments(users, nil); err == nil {
+		t.Fatal("expected a 5-role script to be rejected for 7 players")
+	}
+}
+
+func TestValidateScript(t *testing.T) {
+	for name, roles := range map[string][]string{
+		"unknown role": {"imp", "not_a_role"},
+		"duplicate":    {"imp", "chef", "chef"},
+		"no demon":     {"poisoner", "chef"},
+	} {
+		if err := ValidateScript(roles); err == nil {
+			t.Errorf("%s: expected ValidateScript to fail", name)
+		}
+	}
+	if err := ValidateScript([]string{"imp", "poisoner", "chef"}); err != nil {
+		t.Errorf("valid script rejected: %v", err)
+	}
+}
+
+func TestDistributionOverrideTwoMinions(t *testing.T) {
+	users := []string{"u1", "u2", "u3", "u4", "u5", "u6", "u7"}
+	override := &PlayerDistribution{Townsfolk: 4, Outsiders: 0, Minions: 2, Demons: 1}
+
+	for seed := int64(1); seed <= 20; seed++ {
+		result, err := NewSetupAgent(SetupConfig{PlayerCount: len(users), Seed: seed, Distribution: override}).GenerateAssignments(users, nil)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		minions := 0
+		for _, a := range result.Assignments {
+			if role := GetRoleByID(a.TrueRole); role != nil && role.Type == RoleMinion {
+				minions++
+			}
+		}
+		if minions != 2 {
+			t.Fatalf("seed %d: got %d minions, want 2", seed, minions)
+		}
+	}
+}
+
+func TestValidateDistribution(t *testing.T) {
+	for name, d := range map[string]PlayerDistribution{
+		"exceeds players":  {Townsfolk: 5, Minions: 2, Demons: 1},
+		"short of players": {Townsfolk: 3, Minions: 1, Demons: 1},
+		"no demon":         {Townsfolk: 6, Minions: 1},
+		"negative":         {Townsfolk: 7, Outsiders: -1, Minions: 0, Demons: 1},
+		"other count":      {PlayerCount: 8, Townsfolk: 5, Minions: 1, Demons: 1},
+	} {
+		if err := ValidateDistribution(d, 7); err == nil {
+			t.Errorf("%s: expected ValidateDistribution to fail", name)
+		}
+	}
+	if err := ValidateDistribution(PlayerDistribution{Townsfolk: 4, Minions: 2, Demons: 1}, 7); err != nil {
+		t.Errorf("consistent override rejected: %v", err)
+	}
+}
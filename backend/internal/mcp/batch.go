@@ -0,0 +1,105 @@
+// Package mcp 批量工具调用 (batch)
+//
+// AutoDM 常需一次完成多件事 (旁白 + 推进阶段 + 提醒)。batch 工具接收有序的 steps，
+// 每一步经 Registry.Invoke 以 batch 的调用方角色执行 (照常鉴权与参数校验)，
+// 遇到第一个失败即停止，返回每一步的结果。已执行的步骤不回滚；batch 不可嵌套。
+//
+// [OUT] agent（initMCPRegistry 注册 batch）
+// [POS] 减少模型往返、把相关操作放在一次调用里
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BatchToolName is the name the batch tool registers under.
+const BatchToolName = "batch"
+
+// maxBatchSteps bounds how many calls one batch may carry.
+const maxBatchSteps = 10
+
+// BatchStep is one tool call inside a batch.
+type BatchStep struct {
+	Tool       string          `json:"tool"`
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+}
+
+// BatchResult reports each executed step; FailedStep is -1 when every step succeeded.
+type BatchResult struct {
+	Steps      []*ToolResult `json:"steps"`
+	FailedStep int           `json:"failed_step"`
+}
+
+// RegisterBatchTool adds the batch tool to r.
+func RegisterBatchTool(r *Registry) error {
+	minLen := 1
+	return r.Register(ToolDefinition{
+		Name:        BatchToolName,
+		Description: "Run several tool calls in order as one unit; stops at the first failure and reports each step",
+		Category:    CategoryGameControl,
+		Parameters: map[string]ParamSchema{
+			"steps": {
+				Type: "array",
+				Items: &ParamSchema{
+					Type: "object",
+					Properties: map[string]ParamSchema{
+						"tool":       {Type: "string", MinLength: &minLen},
+						"parameters": {Type: "object"},
+					},
+					Required: []string{"tool"},
+				},
+			},
+		},
+		Required: []string{"steps"},
+	}, func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Steps []BatchStep `json:"steps"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		result, err := r.runBatch(ctx, p.Steps)
+		if result == nil {
+			return nil, err
+		}
+		return result, err
+	})
+}
+
+// runBatch invokes steps in order and halts on the first failure.
+func (r *Registry) runBatch(ctx context.Context, steps []BatchStep) (*BatchResult, error) {
+	if len(steps) == 0 || len(steps) > maxBatchSteps {
+		return nil, fmt.Errorf("batch: expected 1-%d steps, got %d", maxBatchSteps, len(steps))
+	}
+	for i, step := range steps {
+		if step.Tool == BatchToolName {
+			return nil, fmt.Errorf("batch: step %d: batches cannot be nested", i)
+		}
+	}
+	caller := CallerFromContext(ctx)
+	result := &BatchResult{FailedStep: -1}
+	for i, step := range steps {
+		params := step.Parameters
+		if len(params) == 0 {
+			params = json.RawMessage("{}")
+		}
+		res := r.Invoke(ctx, ToolCall{
+			ID:         uuid.NewString(),
+			ToolName:   step.Tool,
+			Parameters: params,
+			Timestamp:  time.Now().UnixMilli(),
+			Caller:     caller,
+		})
+		result.Steps = append(result.Steps, res)
+		if !res.Success {
+			result.FailedStep = i
+			return result, fmt.Errorf("batch: step %d (%s) failed: %s", i, step.Tool, res.Error)
+		}
+	}
+	return result, nil
+}
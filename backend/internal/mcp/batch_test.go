@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// batchRegistry registers narrate and advance tools that append to *order.
+func batchRegistry(t *testing.T, order *[]string, narrateErr error) *Registry {
+	t.Helper()
+	r := NewRegistry()
+	if err := RegisterBatchTool(r); err != nil {
+		t.Fatalf("register batch: %v", err)
+	}
+	record := func(name string, err error) ToolHandler {
+		return func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+			*order = append(*order, name)
+			return nil, err
+		}
+	}
+	_ = r.Register(ToolDefinition{Name: "narrate", Category: CategoryCommunication}, record("narrate", narrateErr))
+	_ = r.Register(ToolDefinition{Name: "advance", Category: CategoryGameControl}, record("advance", nil))
+	return r
+}
+
+func invokeBatch(r *Registry) *ToolResult {
+	raw, _ := json.Marshal(map[string]interface{}{"steps": []map[string]interface{}{
+		{"tool": "narrate", "parameters": map[string]string{"text": "夜幕降临"}},
+		{"tool": "advance"},
+	}})
+	return r.Invoke(context.Background(), ToolCall{ID: "b1", ToolName: BatchToolName, Parameters: raw, Caller: CallerAutoDM})
+}
+
+func TestBatchRunsStepsInOrder(t *testing.T) {
+	var order []string
+	res := invokeBatch(batchRegistry(t, &order, nil))
+	if !res.Success {
+		t.Fatalf("batch failed: %s", res.Error)
+	}
+	if len(order) != 2 || order[0] != "narrate" || order[1] != "advance" {
+		t.Fatalf("expected narrate then advance, got %v", order)
+	}
+	br := res.Result.(*BatchResult)
+	if br.FailedStep != -1 || len(br.Steps) != 2 {
+		t.Fatalf("unexpected batch result %+v", br)
+	}
+}
+
+func TestBatchHaltsOnFirstFailure(t *testing.T) {
+	var order []string
+	res := invokeBatch(batchRegistry(t, &order, errors.New("narrator offline")))
+	if res.Success {
+		t.Fatalf("expected the batch to fail")
+	}
+	if len(order) != 1 || order[0] != "narrate" {
+		t.Fatalf("expected advance to be skipped, ran %v", order)
+	}
+	br, ok := res.Result.(*BatchResult)
+	if !ok || br.FailedStep != 0 || len(br.Steps) != 1 || br.Steps[0].Error != "narrator offline" {
+		t.Fatalf("expected per-step results up to the failure, got %+v", res.Result)
+	}
+}
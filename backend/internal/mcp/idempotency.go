@@ -0,0 +1,106 @@
+// Package mcp 按调用 ID 的幂等执行
+//
+// LLM 循环可能重发同一个 tool call。Invoke 以 (房间, 调用方, 工具, ToolCall.ID) 为键：同键并发
+// 到达时后来者等待首次执行的结果 (ctx 取消即放弃等待)；执行成功的结果被记住，之后同键且参数
+// 相同的调用直接返回它而不再运行处理函数，参数不同则拒绝 (ID 被复用于另一次调用)。
+// 失败的结果不记住 (处理函数未生效，允许重试)。记住的结果 rememberCallTTL 后过期，
+// 且只保留最近 maxRememberedCalls 个。空 ID 不参与去重。
+//
+// [OUT] registry.go（Invoke）
+// [POS] 工具执行的去重层
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxRememberedCalls bounds how many call IDs the registry remembers.
+const maxRememberedCalls = 1024
+
+// rememberCallTTL is how long a successful result answers retries of its call.
+const rememberCallTTL = 10 * time.Minute
+
+// callEntry is one call's execution; done closes once result is set.
+type callEntry struct {
+	done     chan struct{}
+	result   *ToolResult
+	params   []byte
+	finished time.Time
+}
+
+// callKey scopes a call ID to its room, caller and tool so unrelated calls never collide.
+func callKey(call ToolCall) string {
+	return strings.Join([]string{call.RoomID, string(call.Caller), call.ToolName, call.ID}, "\x00")
+}
+
+// compactParams normalises parameters so whitespace alone does not count as a change.
+func compactParams(params json.RawMessage) []byte {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, params); err != nil {
+		return params
+	}
+	return buf.Bytes()
+}
+
+// claimCall returns the entry for key; owner is true when the caller must run the call.
+func (r *Registry) claimCall(key string, params []byte) (*callEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if entry, ok := r.calls[key]; ok {
+		if entry.finished.IsZero() || time.Since(entry.finished) < rememberCallTTL {
+			return entry, false
+		}
+		delete(r.calls, key)
+	}
+	entry := &callEntry{done: make(chan struct{}), params: params}
+	r.calls[key] = entry
+	return entry, true
+}
+
+// awaitCall waits for another invocation of the same call, or for ctx to end.
+func awaitCall(ctx context.Context, call ToolCall, entry *callEntry, params []byte) *ToolResult {
+	if !bytes.Equal(entry.params, params) {
+		return failedCall(call, fmt.Sprintf("call id %q was already used with different parameters", call.ID))
+	}
+	select {
+	case <-entry.done:
+		return entry.result
+	case <-ctx.Done():
+		return failedCall(call, ctx.Err().Error())
+	}
+}
+
+// finishCall publishes result to waiters and remembers it only when it succeeded.
+func (r *Registry) finishCall(key string, entry *callEntry, result *ToolResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.result = result
+	entry.finished = time.Now()
+	close(entry.done)
+	if !result.Success {
+		delete(r.calls, key)
+		return
+	}
+	r.callOrder = append(r.callOrder, key)
+	if len(r.callOrder) > maxRememberedCalls {
+		if old, ok := r.calls[r.callOrder[0]]; ok && !old.finished.IsZero() {
+			delete(r.calls, r.callOrder[0])
+		}
+		r.callOrder = r.callOrder[1:]
+	}
+}
+
+func failedCall(call ToolCall, reason string) *ToolResult {
+	return &ToolResult{
+		CallID:    call.ID,
+		ToolName:  call.ToolName,
+		Success:   false,
+		Error:     reason,
+		Timestamp: time.Now().UnixMilli(),
+	}
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvokeSameIDDispatchesOnce(t *testing.T) {
+	d := &countingDispatcher{}
+	r := NewRegistry()
+	if err := RegisterGameTools(r, GameToolsConfig{Dispatcher: d, RoomID: "room-1"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	raw, _ := json.Marshal(map[string]string{"message": "天亮了"})
+	call := ToolCall{ID: "call-7", ToolName: "send_public_message", Parameters: raw, Caller: CallerAutoDM}
+
+	first := r.Invoke(context.Background(), call)
+	second := r.Invoke(context.Background(), call)
+	if !first.Success || second != first {
+		t.Fatalf("expected the retry to return the first result, got %+v then %+v", first, second)
+	}
+	if d.n != 1 {
+		t.Fatalf("expected one dispatched command, got %d", d.n)
+	}
+
+	call.ID = "call-8"
+	if res := r.Invoke(context.Background(), call); !res.Success || d.n != 2 {
+		t.Fatalf("expected a new id to run again, got %+v with %d dispatches", res, d.n)
+	}
+}
+
+func TestInvokeReusedIDWithDifferentParamsIsRejected(t *testing.T) {
+	d := &countingDispatcher{}
+	r := NewRegistry()
+	if err := RegisterGameTools(r, GameToolsConfig{Dispatcher: d, RoomID: "room-1"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	first, _ := json.Marshal(map[string]string{"message": "天亮了"})
+	other, _ := json.Marshal(map[string]string{"message": "入夜了"})
+	call := ToolCall{ID: "send_public_message", ToolName: "send_public_message", Parameters: first, Caller: CallerAutoDM}
+
+	if res := r.Invoke(context.Background(), call); !res.Success {
+		t.Fatalf("first call: %+v", res)
+	}
+	call.Parameters = other
+	res := r.Invoke(context.Background(), call)
+	if res.Success || !strings.Contains(res.Error, "different parameters") || d.n != 1 {
+		t.Fatalf("expected the reused id to be refused, got %+v with %d dispatches", res, d.n)
+	}
+
+	call.RoomID = "room-2"
+	if res := r.Invoke(context.Background(), call); !res.Success || d.n != 2 {
+		t.Fatalf("expected the same id in another room to run, got %+v with %d dispatches", res, d.n)
+	}
+}
+
+func TestInvokeForgetsResultsAfterTTL(t *testing.T) {
+	d := &countingDispatcher{}
+	r := NewRegistry()
+	if err := RegisterGameTools(r, GameToolsConfig{Dispatcher: d, RoomID: "room-1"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	raw, _ := json.Marshal(map[string]string{"message": "天亮了"})
+	call := ToolCall{ID: "call-7", ToolName: "send_public_message", Parameters: raw, Caller: CallerAutoDM}
+	r.Invoke(context.Background(), call)
+
+	r.calls[callKey(call)].finished = time.Now().Add(-rememberCallTTL)
+	if res := r.Invoke(context.Background(), call); !res.Success || d.n != 2 {
+		t.Fatalf("expected an expired id to run again, got %+v with %d dispatches", res, d.n)
+	}
+}
+
+func TestInvokeWaiterGivesUpWhenContextEnds(t *testing.T) {
+	r := NewRegistry()
+	call := ToolCall{ID: "call-7", ToolName: "slow", Parameters: json.RawMessage(`{}`)}
+	entry, owner := r.claimCall(callKey(call), compactParams(call.Parameters))
+	if !owner {
+		t.Fatal("expected the first claim to own the call")
+	}
+	defer r.finishCall(callKey(call), entry, &ToolResult{Success: true})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if res := r.Invoke(ctx, call); res.Success || res.Error != context.Canceled.Error() {
+		t.Fatalf("expected the waiter to stop on cancel, got %+v", res)
+	}
+}
@@ -0,0 +1,78 @@
+// Package mcp 按调用方角色的工具类别权限
+//
+// 每个 ToolCall 携带调用方角色 (Caller)，Invoke 在校验参数之前先按 Permissions 判断该角色
+// 能否调用工具所属类别；不允许时返回 ErrPermissionDenied 文本的失败结果，处理函数不执行。
+// 默认策略：DM 与 AutoDM 可调用全部类别，玩家一个都不能调用；未声明角色的调用一律拒绝。
+// 处理函数可用 CallerFromContext 取得当前调用方 (batch 以此让每一步沿用同一角色)。
+//
+// [OUT] registry.go（Invoke 的权限检查）
+// [OUT] agent（invokeTool 以 CallerAutoDM 身份调用）
+// [POS] 工具调用入口的授权层
+package mcp
+
+import (
+	"context"
+	"errors"
+)
+
+// CallerRole identifies who invokes a tool.
+type CallerRole string
+
+const (
+	CallerDM     CallerRole = "dm"
+	CallerAutoDM CallerRole = "autodm"
+	CallerPlayer CallerRole = "player"
+)
+
+type callerContextKey struct{}
+
+func withCaller(ctx context.Context, caller CallerRole) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, caller)
+}
+
+// CallerFromContext returns the role Invoke is running a handler for.
+func CallerFromContext(ctx context.Context) CallerRole {
+	caller, _ := ctx.Value(callerContextKey{}).(CallerRole)
+	return caller
+}
+
+// ErrPermissionDenied is reported when a caller may not invoke a tool's category.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Permissions lists the tool categories each caller role may invoke.
+type Permissions map[CallerRole][]ToolCategory
+
+var allCategories = []ToolCategory{
+	CategoryGameControl,
+	CategoryCommunication,
+	CategoryInformation,
+	CategoryModeration,
+}
+
+// DefaultPermissions lets the DM and AutoDM invoke every category and players none.
+func DefaultPermissions() Permissions {
+	return Permissions{
+		CallerDM:     append([]ToolCategory(nil), allCategories...),
+		CallerAutoDM: append([]ToolCategory(nil), allCategories...),
+		CallerPlayer: nil,
+	}
+}
+
+// SetPermissions replaces the registry's permission model.
+func (r *Registry) SetPermissions(p Permissions) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.permissions = p
+}
+
+// Allowed reports whether caller may invoke tools in category.
+func (r *Registry) Allowed(caller CallerRole, category ToolCategory) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, c := range r.permissions[caller] {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
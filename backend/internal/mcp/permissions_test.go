@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPlayerDeniedEveryToolWhileAutoDMAllowed(t *testing.T) {
+	d := &countingDispatcher{}
+	r := NewRegistry()
+	if err := RegisterGameTools(r, GameToolsConfig{Dispatcher: d, RoomID: "room-1"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	advance, _ := json.Marshal(map[string]string{"phase": "day"})
+
+	res := r.Invoke(context.Background(), ToolCall{ID: "p1", ToolName: "advance_phase", Parameters: advance, Caller: CallerPlayer})
+	if res.Success || !strings.Contains(res.Error, ErrPermissionDenied.Error()) {
+		t.Fatalf("expected a player to be denied advance_phase, got %+v", res)
+	}
+	for _, def := range r.ListTools() {
+		if r.Allowed(CallerPlayer, def.Category) {
+			t.Fatalf("expected players to be allowed no tools, %s (%s) is allowed", def.Name, def.Category)
+		}
+	}
+	if res := r.Invoke(context.Background(), ToolCall{ID: "u1", ToolName: "advance_phase", Parameters: advance}); res.Success {
+		t.Fatalf("expected a call without a caller role to be denied, got %+v", res)
+	}
+	if d.n != 0 {
+		t.Fatalf("denied calls must not reach the handler, dispatched %d", d.n)
+	}
+
+	res = r.Invoke(context.Background(), ToolCall{ID: "a1", ToolName: "advance_phase", Parameters: advance, Caller: CallerAutoDM})
+	if !res.Success || d.n != 1 {
+		t.Fatalf("expected AutoDM to advance the phase, got %+v", res)
+	}
+}
@@ -57,6 +57,12 @@ type ToolCall struct {
 	ToolName   string          `json:"tool_name"`
 	Parameters json.RawMessage `json:"parameters"`
 	Timestamp  int64           `json:"timestamp"`
+
+	// Caller is who is invoking; Invoke checks it against the tool's category (permissions.go)
+	Caller CallerRole `json:"caller,omitempty"`
+
+	// RoomID scopes ID deduplication to one room (idempotency.go)
+	RoomID string `json:"room_id,omitempty"`
 }
 
 // ToolResult represents the result of a tool invocation.
@@ -68,6 +74,9 @@ type ToolResult struct {
 	Error     string      `json:"error,omitempty"`
 	Timestamp int64       `json:"timestamp"`
 	TaskID    string      `json:"task_id,omitempty"`
+
+	// Validation is set when the call was rejected by its parameter schema (validate.go)
+	Validation *ValidationError `json:"validation,omitempty"`
 }
 
 // AsyncTask represents a long-running task.
@@ -102,6 +111,13 @@ type Registry struct {
 	handlers map[string]ToolHandler
 	tasks    map[string]*AsyncTask
 	taskCh   chan *AsyncTask
+
+	// permissions maps caller roles to the tool categories they may invoke
+	permissions Permissions
+
+	// calls remembers recent results by room, caller, tool and ID so retries do not re-run (idempotency.go)
+	calls     map[string]*callEntry
+	callOrder []string
 }
 
 // NewRegistry creates a new tool registry.
@@ -111,6 +127,9 @@ func NewRegistry() *Registry {
 		handlers: make(map[string]ToolHandler),
 		tasks:    make(map[string]*AsyncTask),
 		taskCh:   make(chan *AsyncTask, 100),
+
+		permissions: DefaultPermissions(),
+		calls:       make(map[string]*callEntry),
 	}
 }
 
@@ -158,8 +177,23 @@ func (r *Registry) ListToolsByCategory(category ToolCategory) []ToolDefinition {
 	return tools
 }
 
-// Invoke calls a tool with the given parameters.
+// Invoke calls a tool with the given parameters. A call whose ID already succeeded
+// with the same parameters returns the earlier result instead of running again.
 func (r *Registry) Invoke(ctx context.Context, call ToolCall) *ToolResult {
+	if call.ID == "" {
+		return r.invoke(ctx, call)
+	}
+	key, params := callKey(call), compactParams(call.Parameters)
+	entry, owner := r.claimCall(key, params)
+	if !owner {
+		return awaitCall(ctx, call, entry, params)
+	}
+	result := r.invoke(ctx, call)
+	r.finishCall(key, entry, result)
+	return result
+}
+
+func (r *Registry) invoke(ctx context.Context, call ToolCall) *ToolResult {
 	r.mu.RLock()
 	def, defOk := r.tools[call.ToolName]
 	handler, handlerOk := r.handlers[call.ToolName]
@@ -175,26 +209,38 @@ func (r *Registry) Invoke(ctx context.Context, call ToolCall) *ToolResult {
 		}
 	}
 
-	if err := r.validateParams(def, call.Parameters); err != nil {
+	if !r.Allowed(call.Caller, def.Category) {
 		return &ToolResult{
 			CallID:    call.ID,
 			ToolName:  call.ToolName,
 			Success:   false,
-			Error:     fmt.Sprintf("parameter validation failed: %v", err),
+			Error:     fmt.Sprintf("%v: %q may not invoke %s tools", ErrPermissionDenied, call.Caller, def.Category),
 			Timestamp: time.Now().UnixMilli(),
 		}
 	}
 
+	if err := validateParams(def, call.Parameters); err != nil {
+		return &ToolResult{
+			CallID:     call.ID,
+			ToolName:   call.ToolName,
+			Success:    false,
+			Error:      fmt.Sprintf("parameter validation failed: %v", err),
+			Validation: err,
+			Timestamp:  time.Now().UnixMilli(),
+		}
+	}
+
 	if def.Async {
-		return r.invokeAsync(ctx, call, handler)
+		return r.invokeAsync(withCaller(ctx, call.Caller), call, handler)
 	}
 
-	result, err := handler(ctx, call.Parameters)
+	result, err := handler(withCaller(ctx, call.Caller), call.Parameters)
 	if err != nil {
 		return &ToolResult{
 			CallID:    call.ID,
 			ToolName:  call.ToolName,
 			Success:   false,
+			Result:    result,
 			Error:     err.Error(),
 			Timestamp: time.Now().UnixMilli(),
 		}
@@ -269,100 +315,16 @@ func (r *Registry) TaskChannel() <-chan *AsyncTask {
 	return r.taskCh
 }
 
-func (r *Registry) validateParams(def ToolDefinition, params json.RawMessage) error {
-	var paramMap map[string]interface{}
-	if err := json.Unmarshal(params, &paramMap); err != nil {
-		return fmt.Errorf("invalid JSON: %w", err)
-	}
-
-	for _, req := range def.Required {
-		if _, ok := paramMap[req]; !ok {
-			return fmt.Errorf("missing required parameter: %s", req)
-		}
-	}
-
-	for name, schema := range def.Parameters {
-		if val, ok := paramMap[name]; ok {
-			if err := validateValue(name, val, schema); err != nil {
-				return err
-			}
-		}
+// Validate checks a call against its tool's parameter schema without running it.
+func (r *Registry) Validate(call ToolCall) error {
+	r.mu.RLock()
+	def, ok := r.tools[call.ToolName]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("tool not found: %s", call.ToolName)
 	}
-
-	return nil
-}
-
-func validateValue(name string, val interface{}, schema ParamSchema) error {
-	switch schema.Type {
-	case "string":
-		s, ok := val.(string)
-		if !ok {
-			return fmt.Errorf("%s: expected string", name)
-		}
-		if len(schema.Enum) > 0 {
-			found := false
-			for _, e := range schema.Enum {
-				if s == e {
-					found = true
-					break
-				}
-			}
-			if !found {
-				return fmt.Errorf("%s: value not in enum", name)
-			}
-		}
-		if schema.MinLength != nil && len(s) < *schema.MinLength {
-			return fmt.Errorf("%s: string too short", name)
-		}
-		if schema.MaxLength != nil && len(s) > *schema.MaxLength {
-			return fmt.Errorf("%s: string too long", name)
-		}
-	case "number", "integer":
-		var n float64
-		switch v := val.(type) {
-		case float64:
-			n = v
-		case int:
-			n = float64(v)
-		case int64:
-			n = float64(v)
-		default:
-			return fmt.Errorf("%s: expected number", name)
-		}
-		if schema.Minimum != nil && n < *schema.Minimum {
-			return fmt.Errorf("%s: value below minimum", name)
-		}
-		if schema.Maximum != nil && n > *schema.Maximum {
-			return fmt.Errorf("%s: value above maximum", name)
-		}
-	case "boolean":
-		if _, ok := val.(bool); !ok {
-			return fmt.Errorf("%s: expected boolean", name)
-		}
-	case "array":
-		arr, ok := val.([]interface{})
-		if !ok {
-			return fmt.Errorf("%s: expected array", name)
-		}
-		if schema.Items != nil {
-			for i, item := range arr {
-				if err := validateValue(fmt.Sprintf("%s[%d]", name, i), item, *schema.Items); err != nil {
-					return err
-				}
-			}
-		}
-	case "object":
-		obj, ok := val.(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("%s: expected object", name)
-		}
-		for propName, propSchema := range schema.Properties {
-			if propVal, exists := obj[propName]; exists {
-				if err := validateValue(propName, propVal, propSchema); err != nil {
-					return err
-				}
-			}
-		}
+	if err := validateParams(def, call.Parameters); err != nil {
+		return err
 	}
 	return nil
 }
@@ -0,0 +1,159 @@
+// Package mcp 工具参数的 schema 校验
+//
+// Invoke 与 Validate 在处理函数运行前按 ToolDefinition 校验参数：JSON 对象格式、必填字段
+// (含嵌套对象的 Required)、类型 (integer 须为整数)、枚举、字符串长度 (按字符计，中文不按字节)、
+// 正则 Pattern 与数值范围。第一个不合格的参数以 *ValidationError 返回 (Param 为点号路径，
+// Rule 为违反的规则名)，处理函数不会被调用。
+//
+// [OUT] registry.go（Invoke、Validate）
+// [POS] 工具调用入口的参数守卫
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Validation rules reported in ValidationError.Rule.
+const (
+	RuleJSON      = "json"
+	RuleRequired  = "required"
+	RuleType      = "type"
+	RuleEnum      = "enum"
+	RuleMinLength = "min_length"
+	RuleMaxLength = "max_length"
+	RulePattern   = "pattern"
+	RuleMinimum   = "minimum"
+	RuleMaximum   = "maximum"
+)
+
+// ValidationError describes the first parameter that failed its schema.
+type ValidationError struct {
+	Param  string `json:"param,omitempty"`
+	Rule   string `json:"rule"`
+	Detail string `json:"detail"`
+}
+
+func (e *ValidationError) Error() string {
+	if e.Param == "" {
+		return e.Detail
+	}
+	return fmt.Sprintf("%s: %s", e.Param, e.Detail)
+}
+
+func validationErr(param, rule, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Param: param, Rule: rule, Detail: fmt.Sprintf(format, args...)}
+}
+
+// validateParams checks raw call parameters against the tool definition.
+func validateParams(def ToolDefinition, params json.RawMessage) *ValidationError {
+	var paramMap map[string]interface{}
+	if err := json.Unmarshal(params, &paramMap); err != nil || paramMap == nil {
+		return validationErr("", RuleJSON, "parameters must be a JSON object")
+	}
+	return validateObject("", paramMap, def.Parameters, def.Required)
+}
+
+func validateObject(prefix string, obj map[string]interface{}, props map[string]ParamSchema, required []string) *ValidationError {
+	for _, req := range required {
+		if v, ok := obj[req]; !ok || v == nil {
+			return validationErr(joinParam(prefix, req), RuleRequired, "missing required parameter")
+		}
+	}
+	for name, schema := range props {
+		if val, ok := obj[name]; ok && val != nil {
+			if err := validateValue(joinParam(prefix, name), val, schema); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinParam(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func validateValue(name string, val interface{}, schema ParamSchema) *ValidationError {
+	switch schema.Type {
+	case "string":
+		s, ok := val.(string)
+		if !ok {
+			return validationErr(name, RuleType, "expected string")
+		}
+		return validateString(name, s, schema)
+	case "number", "integer":
+		n, ok := val.(float64)
+		if !ok {
+			return validationErr(name, RuleType, "expected %s", schema.Type)
+		}
+		if schema.Type == "integer" && n != math.Trunc(n) {
+			return validationErr(name, RuleType, "expected integer")
+		}
+		if schema.Minimum != nil && n < *schema.Minimum {
+			return validationErr(name, RuleMinimum, "value below minimum %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			return validationErr(name, RuleMaximum, "value above maximum %v", *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := val.(bool); !ok {
+			return validationErr(name, RuleType, "expected boolean")
+		}
+	case "array":
+		arr, ok := val.([]interface{})
+		if !ok {
+			return validationErr(name, RuleType, "expected array")
+		}
+		if schema.Items != nil {
+			for i, item := range arr {
+				if err := validateValue(fmt.Sprintf("%s[%d]", name, i), item, *schema.Items); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			return validationErr(name, RuleType, "expected object")
+		}
+		return validateObject(name, obj, schema.Properties, schema.Required)
+	}
+	return nil
+}
+
+// validateString checks enum, length (in characters) and pattern.
+func validateString(name, s string, schema ParamSchema) *ValidationError {
+	if len(schema.Enum) > 0 && !containsString(schema.Enum, s) {
+		return validationErr(name, RuleEnum, "value %q not in %v", s, schema.Enum)
+	}
+	length := utf8.RuneCountInString(s)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		return validationErr(name, RuleMinLength, "shorter than %d characters", *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		return validationErr(name, RuleMaxLength, "longer than %d characters", *schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		re, err := regexp.Compile(schema.Pattern)
+		if err != nil || !re.MatchString(s) {
+			return validationErr(name, RulePattern, "does not match %q", schema.Pattern)
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
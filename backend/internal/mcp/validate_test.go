@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+type countingDispatcher struct{ n int }
+
+func (d *countingDispatcher) DispatchAsync(types.CommandEnvelope) error {
+	d.n++
+	return nil
+}
+
+func invokePublicMessage(t *testing.T, params map[string]interface{}) (*ToolResult, *countingDispatcher) {
+	t.Helper()
+	d := &countingDispatcher{}
+	r := NewRegistry()
+	if err := RegisterGameTools(r, GameToolsConfig{Dispatcher: d, RoomID: "room-1"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	raw, _ := json.Marshal(params)
+	return r.Invoke(context.Background(), ToolCall{ID: "c1", ToolName: "send_public_message", Parameters: raw, Caller: CallerAutoDM}), d
+}
+
+func TestInvokeRejectsMissingRequiredField(t *testing.T) {
+	res, d := invokePublicMessage(t, map[string]interface{}{})
+	if res.Success || res.Validation == nil {
+		t.Fatalf("expected a validation failure, got %+v", res)
+	}
+	if res.Validation.Rule != RuleRequired || res.Validation.Param != "message" {
+		t.Fatalf("expected required/message, got %+v", res.Validation)
+	}
+	if d.n != 0 {
+		t.Fatalf("handler must not run on invalid params, dispatched %d", d.n)
+	}
+}
+
+func TestInvokeRejectsOverLengthMessage(t *testing.T) {
+	res, d := invokePublicMessage(t, map[string]interface{}{"message": strings.Repeat("夜", 501)})
+	if res.Success || res.Validation == nil || res.Validation.Rule != RuleMaxLength {
+		t.Fatalf("expected max_length failure, got %+v", res)
+	}
+	if d.n != 0 {
+		t.Fatalf("handler must not run on invalid params, dispatched %d", d.n)
+	}
+
+	// 500 characters of multi-byte text is still within bounds.
+	res, d = invokePublicMessage(t, map[string]interface{}{"message": strings.Repeat("夜", 500)})
+	if !res.Success || d.n != 1 {
+		t.Fatalf("expected a 500-character message to pass, got %+v", res)
+	}
+}
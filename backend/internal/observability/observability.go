@@ -31,6 +31,14 @@ type Metrics struct {
 	ResyncEvents      prometheus.Counter
 	AgentLatency      prometheus.Observer
 	AgentErrorTotal   prometheus.Counter
+	// WSCompressionBytesSaved 累计 permessage-deflate 压缩节省的字节数（抽样估算）
+	WSCompressionBytesSaved prometheus.Counter
+	// EventsPurged 累计保留期清理删除的事件与快照行数
+	EventsPurged prometheus.Counter
+	// AgentRunSlotsInUse / AgentRunsQueued / AgentRunQueueTimeouts 反映 AutoDM 全局并发槽位的占用、排队与等待超时
+	AgentRunSlotsInUse    prometheus.Gauge
+	AgentRunsQueued       prometheus.Gauge
+	AgentRunQueueTimeouts prometheus.Counter
 }
 
 func NewMetrics(reg *prometheus.Registry) *Metrics {
@@ -82,6 +90,26 @@ func NewMetrics(reg *prometheus.Registry) *Metrics {
 			Name: "agent_error_total",
 			Help: "Agent run errors",
 		}),
+		WSCompressionBytesSaved: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "ws_compression_bytes_saved_total",
+			Help: "Estimated bytes saved by websocket permessage-deflate",
+		}),
+		EventsPurged: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "event_retention_purged_rows_total",
+			Help: "Event and snapshot rows deleted by the retention purge",
+		}),
+		AgentRunSlotsInUse: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "agent_run_slots_in_use",
+			Help: "AutoDM orchestrator runs currently holding a concurrency slot",
+		}),
+		AgentRunsQueued: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "agent_runs_queued",
+			Help: "AutoDM orchestrator runs waiting for a concurrency slot",
+		}),
+		AgentRunQueueTimeouts: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "agent_run_queue_timeouts_total",
+			Help: "AutoDM orchestrator runs abandoned after waiting too long for a slot",
+		}),
 	}
 }
 
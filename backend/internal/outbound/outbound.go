@@ -0,0 +1,64 @@
+// Package outbound 出站 HTTP 客户端的共享传输层
+//
+// 所有访问外部服务的 HTTP 客户端 (LLM 提供方、Embedding、Qdrant) 都从这里构造，
+// 使 HTTPS_PROXY 配置统一生效。相同代理地址共享一个 *http.Transport (复用连接池)。
+// 代理地址为空或无法解析时按环境变量 (HTTP_PROXY/HTTPS_PROXY/NO_PROXY) 决定；
+// 指定了代理时本机回环地址 (localhost、127.0.0.1、::1) 仍直连，避免本地 Qdrant 走代理。
+//
+// [OUT] agent/llm（OpenAI 兼容与 Gemini 客户端）
+// [OUT] rag（Embedding 与 Qdrant 客户端）
+// [POS] 出站网络的最底层工具包
+package outbound
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	mu         sync.Mutex
+	transports = make(map[string]*http.Transport)
+)
+
+// Transport returns the shared transport for proxyURL ("" uses the environment's proxy settings).
+func Transport(proxyURL string) *http.Transport {
+	mu.Lock()
+	defer mu.Unlock()
+	if t, ok := transports[proxyURL]; ok {
+		return t
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = proxyFunc(proxyURL)
+	transports[proxyURL] = t
+	return t
+}
+
+// Client returns an HTTP client with timeout on the shared transport for proxyURL.
+func Client(proxyURL string, timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: Transport(proxyURL)}
+}
+
+func proxyFunc(proxyURL string) func(*http.Request) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(proxyURL))
+	if proxyURL == "" || err != nil || u.Host == "" {
+		return http.ProxyFromEnvironment
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if isLoopback(req.URL.Hostname()) {
+			return nil, nil
+		}
+		return u, nil
+	}
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
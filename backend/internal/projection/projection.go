@@ -1,5 +1,10 @@
 // Package projection 事件可见性过滤与状态投影
 //
+// Project 是纯函数：(state, events, viewer) → (脱敏 state, 可见 events)。事件可见性由
+// eventVisibility 表按事件类型声明，payload 脱敏由 payloadRedactions 表声明，
+// 需对他人隐藏行动者的类型 (夜晚行动顺序会暴露角色) 由 hiddenActorTypes 声明，
+// 新增私密事件类型只需加一条表项。
+//
 // [IN]  internal/engine（State 结构体）
 // [IN]  internal/types（Event、Viewer、ProjectedEvent 类型）
 // [OUT] api（状态脱敏返回前端）
@@ -15,7 +20,21 @@ import (
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
 )
 
-func Project(event types.Event, state engine.State, viewer types.Viewer) *types.ProjectedEvent {
+// Project is the pure projection entry point: it returns the state redacted for
+// viewer and the events viewer may see, each with its payload sanitized.
+// Hidden events are dropped; order is preserved.
+func Project(state engine.State, events []types.Event, viewer types.Viewer) (engine.State, []types.ProjectedEvent) {
+	projected := make([]types.ProjectedEvent, 0, len(events))
+	for _, ev := range events {
+		if pe := ProjectEvent(ev, state, viewer); pe != nil {
+			projected = append(projected, *pe)
+		}
+	}
+	return ProjectedState(state, viewer), projected
+}
+
+// ProjectEvent filters a single event for viewer; nil means not visible.
+func ProjectEvent(event types.Event, state engine.State, viewer types.Viewer) *types.ProjectedEvent {
 	if !allowed(event, state, viewer) {
 		return nil
 	}
@@ -23,108 +42,210 @@ func Project(event types.Event, state engine.State, viewer types.Viewer) *types.
 		RoomID:      event.RoomID,
 		Seq:         event.Seq,
 		EventType:   event.EventType,
-		ActorUserID: event.ActorUserID,
+		ActorUserID: projectedActor(event, viewer),
 		Data:        sanitizePayload(event, viewer),
 		ServerTS:    event.ServerTimestampMs,
 	}
 }
 
+// allowed looks the event type up in eventVisibility; the DM sees everything
+// and unlisted types are public.
 func allowed(event types.Event, state engine.State, viewer types.Viewer) bool {
 	if viewer.IsDM {
 		return true
 	}
-	switch event.EventType {
-	case "player.poisoned", "player.protected", "demon.changed":
-		return false
-	case "poison.rollback":
-		// Internal resolution event; never shown to players
-		return false
-	// FIX-6: Filter evil_team.chat so only evil players can see it
-	case "evil_team.chat":
-		player, ok := state.Players[viewer.UserID]
-		if !ok {
-			return false
-		}
-		return player.Team == "evil"
-	case "night.info":
-		// Only the target player sees their own night info
-		var payload map[string]string
-		_ = json.Unmarshal(event.Payload, &payload)
-		return viewer.UserID == payload["user_id"]
-	case "team.recognition":
-		// Only the target evil player sees their team recognition
-		var payload map[string]string
-		_ = json.Unmarshal(event.Payload, &payload)
-		return viewer.UserID == payload["user_id"]
-	case "night.action.queued":
-		// Internal state-building event; players receive night.action.prompt instead
-		return false
-	case "ai.decision":
-		// Contains sensitive data (roles, results, poison status); DM only
-		return false
-	case "night.action.prompt", "night.action.completed":
-		// Allow players to see their own night action events
-		var payload map[string]string
-		_ = json.Unmarshal(event.Payload, &payload)
-		return viewer.UserID == payload["user_id"]
-	case "bluffs.assigned":
-		// Only the demon should see bluffs
-		return viewer.UserID == state.DemonID
-	case "whisper.sent":
-		var payload map[string]string
-		_ = json.Unmarshal(event.Payload, &payload)
-		sender := event.ActorUserID
-		recipient := payload["to_user_id"]
-		return viewer.UserID == sender || viewer.UserID == recipient
-	case "role.assigned":
-		var payload map[string]string
-		_ = json.Unmarshal(event.Payload, &payload)
-		return viewer.UserID == payload["user_id"]
-	case "ability.resolved":
-		var payload map[string]string
-		_ = json.Unmarshal(event.Payload, &payload)
-		target := payload["target_user_id"]
-		return viewer.UserID == event.ActorUserID || viewer.UserID == target
-	default:
+	rule, ok := eventVisibility[event.EventType]
+	if !ok {
+		return true
+	}
+	return rule(event, state, viewer)
+}
+
+// visibilityRule decides whether a non-DM viewer may see an event.
+type visibilityRule func(event types.Event, state engine.State, viewer types.Viewer) bool
+
+// eventVisibility lists every event type some non-DM viewers may not see.
+// Adding a private event type only needs an entry here.
+var eventVisibility = map[string]visibilityRule{
+	"player.poisoned":  dmOnly,
+	"player.protected": dmOnly,
+	"demon.changed":    dmOnly,
+	// Internal resolution event; never shown to players
+	"poison.rollback": dmOnly,
+	// The Fortune Teller's red herring is storyteller-only knowledge
+	"red_herring.assigned": dmOnly,
+	// Internal state-building event; players receive night.action.prompt instead
+	"night.action.queued": dmOnly,
+	// Contains sensitive data (roles, results, poison status)
+	"ai.decision": dmOnly,
+	// FIX-6: only evil players see the evil team chat
+	"evil_team.chat": evilTeamOnly,
+	// Only the target player sees their own night info, recognition, prompts and role
+	"night.info":          payloadUserOnly,
+	"team.recognition":    payloadUserOnly,
+	"night.action.prompt": payloadUserOnly,
+	"night.turn":          payloadUserOnly,
+	"role.assigned":       payloadUserOnly,
+	// Reminders and storyteller requests name the player and their role
+	"action.reminder":  payloadUserOnly,
+	"action.requested": payloadUserOnly,
+	// Only the demon should see bluffs
+	"bluffs.assigned":  demonOnly,
+	"whisper.sent":     whisperParties,
+	"ability.resolved": actorOrTarget,
+}
+
+func dmOnly(types.Event, engine.State, types.Viewer) bool { return false }
+
+func evilTeamOnly(_ types.Event, state engine.State, viewer types.Viewer) bool {
+	player, ok := state.Players[viewer.UserID]
+	return ok && player.Team == "evil"
+}
+
+func payloadUserOnly(event types.Event, _ engine.State, viewer types.Viewer) bool {
+	return viewer.UserID == payloadField(event, "user_id")
+}
+
+func demonOnly(_ types.Event, state engine.State, viewer types.Viewer) bool {
+	return viewer.UserID == state.DemonID
+}
+
+func whisperParties(event types.Event, state engine.State, viewer types.Viewer) bool {
+	if payloadField(event, "to_dm") == "true" && state.Players[viewer.UserID].IsDM {
+		// Whispers to the storyteller reach every seated DM, whoever resolved as recipient
 		return true
 	}
+	return viewer.UserID == event.ActorUserID || viewer.UserID == payloadField(event, "to_user_id")
+}
+
+func actorOrTarget(event types.Event, _ engine.State, viewer types.Viewer) bool {
+	return viewer.UserID == event.ActorUserID || viewer.UserID == payloadField(event, "target_user_id")
+}
+
+// payloadField reads one string field from the event payload.
+func payloadField(event types.Event, key string) string {
+	var payload map[string]string
+	_ = json.Unmarshal(event.Payload, &payload)
+	return payload[key]
+}
+
+// IsPrivateEventType reports whether some non-DM viewers may not see events of this type.
+func IsPrivateEventType(eventType string) bool {
+	_, ok := eventVisibility[eventType]
+	return ok
+}
+
+// payloadRedaction rewrites a visible event's payload for a non-DM viewer.
+type payloadRedaction func(raw json.RawMessage, viewer types.Viewer) json.RawMessage
+
+// payloadRedactions lists the event types whose payload non-DM viewers see redacted.
+var payloadRedactions = map[string]payloadRedaction{
+	"role.assigned": redactRoleAssigned,
+	// Players should not know if info is real or fake
+	"night.info": stripField("is_false"),
+	// Only the fact that another player's action occurred is public
+	"night.action.completed": redactNightActionCompleted,
+	// Non-DM viewers only learn who died and a public cause
+	"player.died":      publicDeathPayload,
+	"team.recognition": stripMinionBluffs,
+}
+
+// hiddenActorTypes lists event types whose actor only the DM and the actor see;
+// who acted at night, and in what order, gives away roles.
+var hiddenActorTypes = map[string]bool{
+	"night.action.completed": true,
+}
+
+func projectedActor(event types.Event, viewer types.Viewer) string {
+	if hiddenActorTypes[event.EventType] && !viewer.IsDM && viewer.UserID != event.ActorUserID {
+		return ""
+	}
+	return event.ActorUserID
 }
 
 func sanitizePayload(event types.Event, viewer types.Viewer) json.RawMessage {
-	if !viewer.IsDM && event.EventType == "role.assigned" {
-		var payload map[string]string
-		_ = json.Unmarshal(event.Payload, &payload)
-		if viewer.UserID != payload["user_id"] {
-			return []byte(`{}`)
-		}
-		delete(payload, "true_role")
-		delete(payload, "is_demon")
-		delete(payload, "is_minion")
-		delete(payload, "spy_apparent_role")
-		b, _ := json.Marshal(payload)
-		return b
+	if viewer.IsDM {
+		return event.Payload
 	}
-	// Strip is_false from night.info — players should not know if info is real/fake
-	if !viewer.IsDM && event.EventType == "night.info" {
-		var payload map[string]string
-		_ = json.Unmarshal(event.Payload, &payload)
-		delete(payload, "is_false")
-		b, _ := json.Marshal(payload)
-		return b
+	if redact, ok := payloadRedactions[event.EventType]; ok {
+		return redact(event.Payload, viewer)
 	}
-	// Strip bluffs from team.recognition for minions (only demon gets bluffs)
-	if !viewer.IsDM && event.EventType == "team.recognition" {
+	return event.Payload
+}
+
+// redactRoleAssigned keeps only the perceived role, and only for its owner.
+func redactRoleAssigned(raw json.RawMessage, viewer types.Viewer) json.RawMessage {
+	var payload map[string]string
+	_ = json.Unmarshal(raw, &payload)
+	if viewer.UserID != payload["user_id"] {
+		return []byte(`{}`)
+	}
+	delete(payload, "true_role")
+	delete(payload, "is_demon")
+	delete(payload, "is_minion")
+	delete(payload, "spy_apparent_role")
+	b, _ := json.Marshal(payload)
+	return b
+}
+
+// stripField returns a redaction removing key from the payload.
+func stripField(key string) payloadRedaction {
+	return func(raw json.RawMessage, _ types.Viewer) json.RawMessage {
 		var payload map[string]string
-		_ = json.Unmarshal(event.Payload, &payload)
-		if payload["user_id"] != payload["demon_id"] {
-			delete(payload, "bluffs")
-		}
+		_ = json.Unmarshal(raw, &payload)
+		delete(payload, key)
 		b, _ := json.Marshal(payload)
 		return b
 	}
-	return event.Payload
 }
 
+// stripMinionBluffs removes bluffs from team.recognition for minions (only the demon gets bluffs).
+func stripMinionBluffs(raw json.RawMessage, _ types.Viewer) json.RawMessage {
+	var payload map[string]string
+	_ = json.Unmarshal(raw, &payload)
+	if payload["user_id"] != payload["demon_id"] {
+		delete(payload, "bluffs")
+	}
+	b, _ := json.Marshal(payload)
+	return b
+}
+
+// publicDeathCauses are causes witnessed by the whole town; every other
+// cause (demon kill, mayor bounce, starpass) is announced as a plain night death.
+var publicDeathCauses = map[string]bool{
+	"execution":      true,
+	"slayer":         true,
+	"virgin_ability": true,
+}
+
+// publicDeathPayload rebuilds a player.died payload from an allow-list so
+// private resolution details never reach players or the narrator.
+func publicDeathPayload(raw json.RawMessage, _ types.Viewer) json.RawMessage {
+	var payload map[string]string
+	_ = json.Unmarshal(raw, &payload)
+	cause := payload["cause"]
+	if !publicDeathCauses[cause] {
+		cause = "night"
+	}
+	b, _ := json.Marshal(map[string]string{
+		"user_id": payload["user_id"],
+		"cause":   cause,
+	})
+	return b
+}
+
+// redactNightActionCompleted strips result/targets/role_id from another
+// player's night action so the viewer only learns that an action occurred.
+func redactNightActionCompleted(raw json.RawMessage, viewer types.Viewer) json.RawMessage {
+	var payload map[string]string
+	_ = json.Unmarshal(raw, &payload)
+	if viewer.UserID == payload["user_id"] {
+		return raw
+	}
+	return []byte(`{}`)
+}
+
+// ProjectedState returns a copy of state with everything viewer may not know cleared.
 func ProjectedState(state engine.State, viewer types.Viewer) engine.State {
 	cp := state.Copy()
 	if !viewer.IsDM {
@@ -146,7 +267,7 @@ func ProjectedState(state engine.State, viewer types.Viewer) engine.State {
 			}
 			p.NightInfo = nil
 			if id != viewer.UserID {
-				p.Role = ""
+				p.Role = "" // RevealedRole stays: it was announced publicly on death
 			}
 			cp.Players[id] = p
 		}
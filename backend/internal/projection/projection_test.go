@@ -0,0 +1,396 @@
+package projection
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func newEmpathState() engine.State {
+	state := engine.NewState("room-1")
+	state.Phase = engine.PhaseNight
+	state.Players["empath"] = engine.Player{UserID: "empath", TrueRole: "empath", Team: "good", Alive: true, SeatNumber: 1}
+	state.Players["neighbor"] = engine.Player{UserID: "neighbor", TrueRole: "imp", Team: "evil", Alive: true, SeatNumber: 2}
+	return state
+}
+
+func newEmpathCompletedEvent(t *testing.T) types.Event {
+	t.Helper()
+	payload, err := json.Marshal(map[string]string{
+		"user_id": "empath",
+		"role_id": "empath",
+		"targets": `["neighbor"]`,
+		"result":  "1 evil neighbor",
+	})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return types.Event{RoomID: "room-1", Seq: 7, EventType: "night.action.completed", ActorUserID: "empath", Payload: payload}
+}
+
+func decodeProjected(t *testing.T, pe *types.ProjectedEvent) map[string]string {
+	t.Helper()
+	if pe == nil {
+		t.Fatal("expected event to be visible, got nil")
+	}
+	var data map[string]string
+	if err := json.Unmarshal(pe.Data, &data); err != nil {
+		t.Fatalf("unmarshal projected data: %v", err)
+	}
+	return data
+}
+
+func TestProjectHidesEmpathResultFromNeighbor(t *testing.T) {
+	state := newEmpathState()
+	event := newEmpathCompletedEvent(t)
+
+	pe := ProjectEvent(event, state, types.Viewer{UserID: "neighbor"})
+	data := decodeProjected(t, pe)
+	for _, key := range []string{"result", "targets", "role_id", "user_id"} {
+		if _, ok := data[key]; ok {
+			t.Fatalf("expected %q to be redacted for neighbor, got %v", key, data)
+		}
+	}
+	if pe.ActorUserID != "" {
+		t.Fatalf("expected the acting player hidden from neighbor, got %q", pe.ActorUserID)
+	}
+}
+
+func TestProjectShowsEmpathResultToEmpath(t *testing.T) {
+	state := newEmpathState()
+	event := newEmpathCompletedEvent(t)
+
+	pe := ProjectEvent(event, state, types.Viewer{UserID: "empath"})
+	data := decodeProjected(t, pe)
+	if pe.ActorUserID != "empath" {
+		t.Fatalf("expected empath to see itself as actor, got %q", pe.ActorUserID)
+	}
+	if data["result"] != "1 evil neighbor" {
+		t.Fatalf("expected empath to see own result, got %q", data["result"])
+	}
+	if data["targets"] != `["neighbor"]` {
+		t.Fatalf("expected empath to see own targets, got %q", data["targets"])
+	}
+}
+
+func TestProjectShowsEmpathResultToDM(t *testing.T) {
+	state := newEmpathState()
+	event := newEmpathCompletedEvent(t)
+
+	pe := ProjectEvent(event, state, types.Viewer{UserID: "dm", IsDM: true})
+	data := decodeProjected(t, pe)
+	if data["result"] != "1 evil neighbor" {
+		t.Fatalf("expected DM to see empath result, got %q", data["result"])
+	}
+	if pe.ActorUserID != "empath" {
+		t.Fatalf("expected DM to see the actor, got %q", pe.ActorUserID)
+	}
+}
+
+func TestProjectHidesNightInfoFromNeighbor(t *testing.T) {
+	state := newEmpathState()
+	payload, _ := json.Marshal(map[string]string{"user_id": "empath", "role_id": "empath", "content": `{"evil_neighbors":1}`})
+	event := types.Event{RoomID: "room-1", Seq: 8, EventType: "night.info", Payload: payload}
+
+	if pe := ProjectEvent(event, state, types.Viewer{UserID: "neighbor"}); pe != nil {
+		t.Fatalf("expected night.info hidden from neighbor, got %s", pe.Data)
+	}
+	if pe := ProjectEvent(event, state, types.Viewer{UserID: "empath"}); pe == nil {
+		t.Fatal("expected night.info visible to empath")
+	}
+}
+
+func TestPrivateEventTypesAreHiddenFromBystanders(t *testing.T) {
+	state := newEmpathState()
+	bystander := types.Viewer{UserID: "bystander"}
+	for eventType := range eventVisibility {
+		ev := types.Event{RoomID: "room-1", EventType: eventType, ActorUserID: "empath", Payload: []byte(`{"user_id":"empath"}`)}
+		if ProjectEvent(ev, state, bystander) != nil {
+			t.Errorf("%s is listed private but visible to a bystander", eventType)
+		}
+	}
+	if IsPrivateEventType("public.chat") || IsPrivateEventType("player.died") {
+		t.Fatal("public event types must not be private")
+	}
+}
+
+func TestRetractedChatIsRemovedFromProjectedHistory(t *testing.T) {
+	state := newEmpathState()
+	viewer := types.Viewer{UserID: "empath"}
+	history := []types.Event{
+		{RoomID: "room-1", Seq: 1, EventID: "chat-1", EventType: "public.chat", Payload: []byte(`{"message":"hello"}`)},
+		{RoomID: "room-1", Seq: 2, EventID: "chat-2", EventType: "public.chat", Payload: []byte(`{"message":"oops"}`)},
+		{RoomID: "room-1", Seq: 3, EventID: "undo", EventType: "event.retracted", Payload: []byte(`{"event_id":"chat-2","seq":"2"}`)},
+	}
+
+	var seqs []int64
+	for _, ev := range WithoutRetracted(history) {
+		if pe := ProjectEvent(ev, state, viewer); pe != nil {
+			seqs = append(seqs, pe.Seq)
+		}
+	}
+	if len(seqs) != 2 || seqs[0] != 1 || seqs[1] != 3 {
+		t.Fatalf("expected chat 1 and the retraction marker, got seqs %v", seqs)
+	}
+}
+
+func whisperToDM(t *testing.T, state engine.State) types.Event {
+	t.Helper()
+	raw, _ := json.Marshal(map[string]string{"to_user_id": engine.WhisperToDM, "message": "can I nominate myself?"})
+	events, _, err := engine.HandleCommand(state, types.CommandEnvelope{
+		CommandID: "cmd-w", RoomID: "room-1", Type: "whisper", ActorUserID: "empath", Payload: raw,
+	})
+	if err != nil {
+		t.Fatalf("whisper to DM rejected: %v", err)
+	}
+	return events[0]
+}
+
+func TestWhisperToDMReachesDMViewer(t *testing.T) {
+	state := newEmpathState()
+	state.Players["host"] = engine.Player{UserID: "host", IsDM: true}
+	event := whisperToDM(t, state)
+
+	// The seated DM sees it even on a connection not flagged as DM
+	if data := decodeProjected(t, ProjectEvent(event, state, types.Viewer{UserID: "host"})); data["message"] != "can I nominate myself?" {
+		t.Fatalf("expected the DM to see the whisper, got %v", data)
+	}
+	if ProjectEvent(event, state, types.Viewer{UserID: "neighbor"}) != nil {
+		t.Fatal("expected the whisper hidden from other players")
+	}
+
+	// With no human DM seated the Auto-DM is the recipient; DM connections still see it
+	delete(state.Players, "host")
+	event = whisperToDM(t, state)
+	data := decodeProjected(t, ProjectEvent(event, state, types.Viewer{UserID: "owner", IsDM: true}))
+	if data["to_user_id"] != types.AutoDMActorID || data["to_dm"] != "true" {
+		t.Fatalf("expected the whisper routed to the Auto-DM, got %v", data)
+	}
+}
+
+func TestRevealOnDeathVisibility(t *testing.T) {
+	for _, reveal := range []bool{true, false} {
+		state := newEmpathState()
+		state.Phase = engine.PhaseDay
+		state.Config.RevealOnDeath = reveal
+		payload, _ := json.Marshal(map[string]string{"user_id": "neighbor", "cause": "execution"})
+		died := types.Event{RoomID: "room-1", EventType: "player.died", Payload: payload}
+
+		events := engine.WithDeathReveals(state, types.CommandEnvelope{RoomID: "room-1"}, []types.Event{died})
+		for _, ev := range events {
+			var p map[string]string
+			_ = json.Unmarshal(ev.Payload, &p)
+			state.Reduce(engine.EventPayload{Type: ev.EventType, Payload: p})
+		}
+		viewer := types.Viewer{UserID: "empath"}
+		var revealed []map[string]string
+		for _, ev := range events {
+			if pe := ProjectEvent(ev, state, viewer); pe != nil && pe.EventType == "role.revealed" {
+				revealed = append(revealed, decodeProjected(t, pe))
+			}
+		}
+		projected := ProjectedState(state, viewer).Players["neighbor"]
+
+		if !reveal {
+			if len(revealed) != 0 || projected.RevealedRole != "" || projected.TrueRole != "" {
+				t.Fatalf("reveal off: expected the dead player's role to stay hidden, got events %v, state %+v", revealed, projected)
+			}
+			continue
+		}
+		if len(revealed) != 1 || revealed[0]["user_id"] != "neighbor" || revealed[0]["role"] != "imp" {
+			t.Fatalf("reveal on: expected a public role.revealed for the imp, got %v", revealed)
+		}
+		if projected.RevealedRole != "imp" || projected.TrueRole != "" {
+			t.Fatalf("reveal on: expected only RevealedRole in the projected state, got %+v", projected)
+		}
+	}
+}
+
+// visibilityCase is one event type's expected visibility per viewer.
+type visibilityCase struct {
+	eventType string
+	dm        bool
+	owner     bool
+	player    bool
+	spectator bool
+}
+
+func public(eventType string) visibilityCase {
+	return visibilityCase{eventType, true, true, true, true}
+}
+
+func ownerOnly(eventType string) visibilityCase {
+	return visibilityCase{eventType, true, true, false, false}
+}
+
+func storytellerOnly(eventType string) visibilityCase {
+	return visibilityCase{eventType, true, false, false, false}
+}
+
+var visibilityCases = []visibilityCase{
+	ownerOnly("ability.resolved"),
+	ownerOnly("action.reminder"),
+	ownerOnly("action.requested"),
+	storytellerOnly("ai.decision"),
+	public("autodm.paused"),
+	public("autodm.resumed"),
+	ownerOnly("bluffs.assigned"),
+	public("claim.made"),
+	public("dawn.summary"),
+	public("day.no_execution"),
+	public("defense.ended"),
+	public("defense.progress"),
+	storytellerOnly("demon.changed"),
+	public("event.retracted"),
+	ownerOnly("evil_team.chat"),
+	public("execution.resolved"),
+	public("exile.resolved"),
+	public("exile.started"),
+	public("exile.voted"),
+	public("game.ended"),
+	public("game.recap"),
+	public("game.started"),
+	public("night.action.completed"),
+	ownerOnly("night.action.prompt"),
+	storytellerOnly("night.action.queued"),
+	ownerOnly("night.info"),
+	ownerOnly("night.turn"),
+	public("nomination.created"),
+	public("nomination.resolved"),
+	public("phase.day"),
+	public("phase.first_night"),
+	public("phase.night"),
+	public("phase.nomination"),
+	public("player.died"),
+	public("player.disconnected"),
+	public("player.executed"),
+	public("player.exiled"),
+	public("player.joined"),
+	public("player.language_set"),
+	public("player.left"),
+	storytellerOnly("player.poisoned"),
+	storytellerOnly("player.protected"),
+	public("player.reconnected"),
+	public("player.removed"),
+	public("poison.cleared"),
+	storytellerOnly("poison.rollback"),
+	public("public.chat"),
+	storytellerOnly("red_herring.assigned"),
+	public("reminder.added"),
+	ownerOnly("role.assigned"),
+	public("role.revealed"),
+	public("room.settings.changed"),
+	public("seat.claimed"),
+	public("slayer.shot"),
+	ownerOnly("team.recognition"),
+	public("tie.resolved"),
+	public("time.extended"),
+	public("timer.set"),
+	public("vote.cast"),
+	ownerOnly("whisper.sent"),
+}
+
+// newOwnerState seats a DM, the evil demon "owner" every test event is about, and a good player.
+func newOwnerState() engine.State {
+	state := engine.NewState("room-1")
+	state.Phase = engine.PhaseNight
+	state.Players["dm"] = engine.Player{UserID: "dm", IsDM: true}
+	state.Players["owner"] = engine.Player{UserID: "owner", TrueRole: "imp", Team: "evil", Alive: true, SeatNumber: 1}
+	state.Players["player"] = engine.Player{UserID: "player", TrueRole: "chef", Team: "good", Alive: true, SeatNumber: 2}
+	state.DemonID = "owner"
+	return state
+}
+
+func TestEventVisibilityTable(t *testing.T) {
+	state := newOwnerState()
+	payload := []byte(`{"user_id":"owner","to_user_id":"owner","target_user_id":"owner"}`)
+	viewers := []struct {
+		name   string
+		viewer types.Viewer
+		want   func(visibilityCase) bool
+	}{
+		{"dm", types.Viewer{UserID: "dm", IsDM: true}, func(c visibilityCase) bool { return c.dm }},
+		{"owner", types.Viewer{UserID: "owner"}, func(c visibilityCase) bool { return c.owner }},
+		{"player", types.Viewer{UserID: "player"}, func(c visibilityCase) bool { return c.player }},
+		{"spectator", spectatorViewer, func(c visibilityCase) bool { return c.spectator }},
+	}
+	for _, c := range visibilityCases {
+		ev := types.Event{RoomID: "room-1", Seq: 1, EventType: c.eventType, ActorUserID: "owner", Payload: payload}
+		for _, v := range viewers {
+			if got := ProjectEvent(ev, state, v.viewer) != nil; got != v.want(c) {
+				t.Errorf("%s visible to %s = %v, want %v", c.eventType, v.name, got, v.want(c))
+			}
+		}
+	}
+}
+
+func TestEventVisibilityTableIsCovered(t *testing.T) {
+	covered := make(map[string]bool, len(visibilityCases))
+	for _, c := range visibilityCases {
+		covered[c.eventType] = true
+	}
+	for eventType := range eventVisibility {
+		if !covered[eventType] {
+			t.Errorf("%s has a visibility rule but no case in visibilityCases", eventType)
+		}
+	}
+}
+
+func TestProjectReturnsStateAndVisibleEvents(t *testing.T) {
+	state := newOwnerState()
+	events := []types.Event{
+		{RoomID: "room-1", Seq: 1, EventType: "public.chat", Payload: []byte(`{"message":"hi"}`)},
+		{RoomID: "room-1", Seq: 2, EventType: "player.poisoned", Payload: []byte(`{"user_id":"player"}`)},
+		{RoomID: "room-1", Seq: 3, EventType: "night.info", Payload: []byte(`{"user_id":"player","is_false":"true"}`)},
+	}
+
+	projected, visible := Project(state, events, types.Viewer{UserID: "player"})
+	if len(visible) != 2 || visible[0].Seq != 1 || visible[1].Seq != 3 {
+		t.Fatalf("expected seqs 1 and 3, got %+v", visible)
+	}
+	if data := decodeProjected(t, &visible[1]); data["is_false"] != "" {
+		t.Fatalf("night.info must not reveal is_false, got %v", data)
+	}
+	if projected.DemonID != "" || projected.Players["owner"].TrueRole != "" {
+		t.Fatalf("projected state leaks hidden roles: demon=%q owner=%q", projected.DemonID, projected.Players["owner"].TrueRole)
+	}
+}
+
+func TestRedHerringAndActionPromptsStayPrivate(t *testing.T) {
+	state := newEmpathState()
+	state.Players["ft"] = engine.Player{UserID: "ft", TrueRole: "fortuneteller", Team: "good", Alive: true, SeatNumber: 3}
+	cases := []struct {
+		name    string
+		event   types.Event
+		visible map[string]bool // viewer user id -> visible; the DM always sees it
+	}{
+		{
+			name:    "red herring is storyteller-only",
+			event:   types.Event{EventType: "red_herring.assigned", Payload: []byte(`{"user_id":"empath"}`)},
+			visible: map[string]bool{"empath": false, "ft": false, "neighbor": false, "spectator": false},
+		},
+		{
+			name:    "evil reminder reaches only the evil actor",
+			event:   types.Event{EventType: "action.reminder", Payload: []byte(`{"user_id":"neighbor","role_id":"imp","message":"act now"}`)},
+			visible: map[string]bool{"neighbor": true, "empath": false, "ft": false, "spectator": false},
+		},
+		{
+			name:    "action request reaches only its target",
+			event:   types.Event{EventType: "action.requested", Payload: []byte(`{"user_id":"ft","action_type":"select_two","prompt":"choose two players"}`)},
+			visible: map[string]bool{"ft": true, "empath": false, "neighbor": false, "spectator": false},
+		},
+	}
+	for _, tc := range cases {
+		tc.event.RoomID = "room-1"
+		if ProjectEvent(tc.event, state, types.Viewer{UserID: "dm", IsDM: true}) == nil {
+			t.Errorf("%s: expected the DM to see it", tc.name)
+		}
+		for viewer, want := range tc.visible {
+			if got := ProjectEvent(tc.event, state, types.Viewer{UserID: viewer}) != nil; got != want {
+				t.Errorf("%s: visible to %s = %v, want %v", tc.name, viewer, got, want)
+			}
+		}
+	}
+}
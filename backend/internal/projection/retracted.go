@@ -0,0 +1,38 @@
+// Package projection 撤回事件过滤
+//
+// 历史补发时去掉已被 event.retracted 撤回的事件，保留撤回标记本身，
+// 以便已显示旧消息的客户端据 event_id 删除。
+//
+// [IN]  internal/types（Event）
+// [OUT] realtime（订阅时历史补发）
+// [POS] 安全层之前的历史清理
+package projection
+
+import (
+	"encoding/json"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// WithoutRetracted drops events that a later event.retracted in the slice names.
+func WithoutRetracted(events []types.Event) []types.Event {
+	retracted := make(map[string]bool)
+	for _, e := range events {
+		if e.EventType != "event.retracted" {
+			continue
+		}
+		var payload map[string]string
+		_ = json.Unmarshal(e.Payload, &payload)
+		retracted[payload["event_id"]] = true
+	}
+	if len(retracted) == 0 {
+		return events
+	}
+	kept := make([]types.Event, 0, len(events))
+	for _, e := range events {
+		if !retracted[e.EventID] {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
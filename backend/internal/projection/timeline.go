@@ -0,0 +1,100 @@
+// Package projection 旁观者安全的公开时间线
+//
+// 事件先去掉被撤回的，再以旁观者视角 (非 DM、非玩家) 经 ProjectEvent 过滤与脱敏，
+// 最后只保留白名单内的公开类型 (聊天、阶段、提名、处决、死亡、胜负)，
+// 每条生成 {类型、行动者名、摘要、时间}。私聊、夜晚信息等私密事件不会进入时间线。
+//
+// [IN]  internal/types（Event、Viewer）
+// [OUT] api（GET /v1/rooms/{room_id}/timeline）
+// [POS] 安全层之上的观战视图
+package projection
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// spectatorViewer sees only what every seat at the table sees.
+var spectatorViewer = types.Viewer{UserID: "spectator"}
+
+// TimelineEntry is one human-readable line of the public timeline.
+type TimelineEntry struct {
+	Seq       int64  `json:"seq"`
+	Type      string `json:"type"`
+	ActorName string `json:"actor_name,omitempty"`
+	Summary   string `json:"summary"`
+	Timestamp int64  `json:"ts"`
+}
+
+// timelineSummaries renders each public timeline type; names maps user id to display name.
+var timelineSummaries = map[string]func(p map[string]string, names func(string) string) string{
+	"player.joined": func(p map[string]string, _ func(string) string) string {
+		return fmt.Sprintf("%s joined (seat %s)", p["name"], p["seat_number"])
+	},
+	"game.started":      func(map[string]string, func(string) string) string { return "The game begins" },
+	"phase.first_night": func(map[string]string, func(string) string) string { return "The first night falls" },
+	"phase.night":       func(map[string]string, func(string) string) string { return "Night falls" },
+	"phase.day":         func(map[string]string, func(string) string) string { return "Dawn breaks" },
+	"phase.nomination":  func(map[string]string, func(string) string) string { return "Nominations are open" },
+	"public.chat": func(p map[string]string, _ func(string) string) string {
+		return p["message"]
+	},
+	"nomination.created": func(p map[string]string, names func(string) string) string {
+		return fmt.Sprintf("%s nominated %s", names(p["nominator_user_id"]), names(p["nominee"]))
+	},
+	"nomination.resolved": func(p map[string]string, _ func(string) string) string {
+		if p["votes_for"] == "" {
+			return fmt.Sprintf("Nomination %s", p["result"])
+		}
+		return fmt.Sprintf("Nomination %s: %s for, %s against (needed %s)", p["result"], p["votes_for"], p["votes_against"], p["threshold"])
+	},
+	"execution.resolved": func(p map[string]string, names func(string) string) string {
+		return fmt.Sprintf("%s was executed", names(p["executed"]))
+	},
+	"day.no_execution": func(map[string]string, func(string) string) string { return "No one was executed today" },
+	"slayer.shot": func(p map[string]string, names func(string) string) string {
+		return fmt.Sprintf("A Slayer shot was fired at %s: %s", names(p["target"]), p["result"])
+	},
+	"player.died": func(p map[string]string, names func(string) string) string {
+		return fmt.Sprintf("%s died (%s)", names(p["user_id"]), p["cause"])
+	},
+	"game.ended": func(p map[string]string, _ func(string) string) string {
+		return fmt.Sprintf("Game over: %s wins", p["winner"])
+	},
+}
+
+// Timeline builds the public, chronological feed of events.
+func Timeline(events []types.Event) []TimelineEntry {
+	names := make(map[string]string)
+	nameOf := func(userID string) string {
+		if name := names[userID]; name != "" {
+			return name
+		}
+		return userID
+	}
+	state := engine.NewState("")
+	entries := make([]TimelineEntry, 0, len(events))
+	for _, ev := range WithoutRetracted(events) {
+		summarize, ok := timelineSummaries[ev.EventType]
+		pe := ProjectEvent(ev, state, spectatorViewer)
+		if !ok || pe == nil {
+			continue
+		}
+		var payload map[string]string
+		_ = json.Unmarshal(pe.Data, &payload)
+		if ev.EventType == "player.joined" {
+			names[ev.ActorUserID] = payload["name"]
+		}
+		entries = append(entries, TimelineEntry{
+			Seq:       ev.Seq,
+			Type:      ev.EventType,
+			ActorName: nameOf(pe.ActorUserID),
+			Summary:   summarize(payload, nameOf),
+			Timestamp: pe.ServerTS,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,48 @@
+package projection
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func timelineEvent(seq int64, eventType, actor string, payload map[string]string) types.Event {
+	raw, _ := json.Marshal(payload)
+	return types.Event{RoomID: "room-1", Seq: seq, EventID: eventType, EventType: eventType, ActorUserID: actor, Payload: raw, ServerTimestampMs: 1000 * seq}
+}
+
+func TestTimelineOmitsWhispersAndNightInfo(t *testing.T) {
+	events := []types.Event{
+		timelineEvent(1, "player.joined", "alice", map[string]string{"name": "Alice", "seat_number": "1"}),
+		timelineEvent(2, "player.joined", "bob", map[string]string{"name": "Bob", "seat_number": "2"}),
+		timelineEvent(3, "role.assigned", "autodm", map[string]string{"user_id": "alice", "role": "empath", "true_role": "empath"}),
+		timelineEvent(4, "phase.first_night", "autodm", nil),
+		timelineEvent(5, "night.info", "autodm", map[string]string{"user_id": "alice", "content": "one evil neighbor"}),
+		timelineEvent(6, "player.died", "autodm", map[string]string{"user_id": "bob", "cause": "demon"}),
+		timelineEvent(7, "phase.day", "autodm", nil),
+		timelineEvent(8, "whisper.sent", "alice", map[string]string{"to_user_id": "bob", "message": "I am the empath"}),
+		timelineEvent(9, "evil_team.chat", "bob", map[string]string{"message": "kill alice"}),
+		timelineEvent(10, "public.chat", "alice", map[string]string{"message": "good morning"}),
+	}
+
+	entries := Timeline(events)
+	var kinds []string
+	for _, e := range entries {
+		kinds = append(kinds, e.Type)
+		if strings.Contains(e.Summary, "empath") || strings.Contains(e.Summary, "evil") || strings.Contains(e.Summary, "demon") {
+			t.Fatalf("timeline leaked private detail: %+v", e)
+		}
+	}
+	want := "player.joined,player.joined,phase.first_night,player.died,phase.day,public.chat"
+	if got := strings.Join(kinds, ","); got != want {
+		t.Fatalf("timeline types\nwant %s\ngot  %s", want, got)
+	}
+	if death := entries[3]; death.Summary != "Bob died (night)" {
+		t.Fatalf("expected public death summary, got %q", death.Summary)
+	}
+	if chat := entries[5]; chat.ActorName != "Alice" || chat.Summary != "good morning" || chat.Timestamp != 10000 {
+		t.Fatalf("unexpected chat entry %+v", chat)
+	}
+}
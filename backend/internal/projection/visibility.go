@@ -0,0 +1,77 @@
+// Package projection 可见性矩阵 (DM 诊断)
+//
+// VisibilityMatrix 对每个事件分别以 DM、每位入座玩家 (按座位) 与旁观者视角调用 ProjectEvent，
+// 列出谁能看到它，用于排查投影规则导致的信息泄露。纯函数，只读 state。
+//
+// [IN]  internal/engine（State、Player）
+// [IN]  internal/types（Event、Viewer）
+// [OUT] api（GET /v1/rooms/{room_id}/visibility）
+// [POS] 安全层的诊断视图
+package projection
+
+import (
+	"sort"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// dmViewer stands in for any DM connection.
+var dmViewer = types.Viewer{UserID: "dm", IsDM: true}
+
+// VisibilityRow lists who can see one event.
+type VisibilityRow struct {
+	Seq       int64              `json:"seq"`
+	EventType string             `json:"event_type"`
+	DM        bool               `json:"dm"`
+	Spectator bool               `json:"spectator"`
+	Players   []PlayerVisibility `json:"players"`
+}
+
+// PlayerVisibility is one seated player's column of the matrix.
+type PlayerVisibility struct {
+	UserID  string `json:"user_id"`
+	Seat    int    `json:"seat"`
+	Visible bool   `json:"visible"`
+}
+
+// VisibilityMatrix projects each event for the DM, every seated non-DM player and a spectator.
+func VisibilityMatrix(events []types.Event, state engine.State) []VisibilityRow {
+	players := seatedPlayers(state)
+	rows := make([]VisibilityRow, 0, len(events))
+	for _, ev := range events {
+		row := VisibilityRow{
+			Seq:       ev.Seq,
+			EventType: ev.EventType,
+			DM:        ProjectEvent(ev, state, dmViewer) != nil,
+			Spectator: ProjectEvent(ev, state, spectatorViewer) != nil,
+			Players:   make([]PlayerVisibility, 0, len(players)),
+		}
+		for _, p := range players {
+			row.Players = append(row.Players, PlayerVisibility{
+				UserID:  p.UserID,
+				Seat:    p.SeatNumber,
+				Visible: ProjectEvent(ev, state, types.Viewer{UserID: p.UserID}) != nil,
+			})
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// seatedPlayers returns the non-DM players in seat order.
+func seatedPlayers(state engine.State) []engine.Player {
+	players := make([]engine.Player, 0, len(state.Players))
+	for _, p := range state.Players {
+		if !p.IsDM {
+			players = append(players, p)
+		}
+	}
+	sort.Slice(players, func(i, j int) bool {
+		if players[i].SeatNumber != players[j].SeatNumber {
+			return players[i].SeatNumber < players[j].SeatNumber
+		}
+		return players[i].UserID < players[j].UserID
+	})
+	return players
+}
@@ -0,0 +1,61 @@
+package projection
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestVisibilityMatrixWhisper(t *testing.T) {
+	state := newEmpathState()
+	state.Phase = engine.PhaseDay
+	state.Players["chef"] = engine.Player{UserID: "chef", TrueRole: "chef", Team: "good", Alive: true, SeatNumber: 3}
+	state.Players["host"] = engine.Player{UserID: "host", IsDM: true}
+
+	raw, _ := json.Marshal(map[string]string{"to_user_id": "neighbor", "message": "I'm the empath"})
+	events, _, err := engine.HandleCommand(state, types.CommandEnvelope{
+		CommandID: "cmd-w", RoomID: "room-1", Type: "whisper", ActorUserID: "empath", Payload: raw,
+	})
+	if err != nil {
+		t.Fatalf("whisper rejected: %v", err)
+	}
+
+	rows := VisibilityMatrix(events[:1], state)
+	if len(rows) != 1 || rows[0].EventType != "whisper.sent" {
+		t.Fatalf("expected one whisper.sent row, got %+v", rows)
+	}
+	row := rows[0]
+	if !row.DM || row.Spectator {
+		t.Fatalf("expected DM only among non-players, got dm=%v spectator=%v", row.DM, row.Spectator)
+	}
+
+	want := map[string]bool{"empath": true, "neighbor": true, "chef": false}
+	if len(row.Players) != len(want) {
+		t.Fatalf("expected %d seated players (DM excluded), got %+v", len(want), row.Players)
+	}
+	for i, p := range row.Players {
+		if p.Seat != i+1 {
+			t.Fatalf("players not in seat order: %+v", row.Players)
+		}
+		if p.Visible != want[p.UserID] {
+			t.Fatalf("%s visible=%v, want %v", p.UserID, p.Visible, want[p.UserID])
+		}
+	}
+}
+
+func TestVisibilityMatrixPublicChat(t *testing.T) {
+	state := newEmpathState()
+	payload, _ := json.Marshal(map[string]string{"message": "hello"})
+	rows := VisibilityMatrix([]types.Event{{Seq: 3, EventType: "public.chat", ActorUserID: "empath", Payload: payload}}, state)
+
+	if !rows[0].DM || !rows[0].Spectator {
+		t.Fatalf("public chat should reach DM and spectators: %+v", rows[0])
+	}
+	for _, p := range rows[0].Players {
+		if !p.Visible {
+			t.Fatalf("public chat hidden from %s", p.UserID)
+		}
+	}
+}
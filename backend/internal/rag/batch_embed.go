@@ -0,0 +1,67 @@
+// Package rag 规则文档批量向量化
+//
+// 规则索引时按可配置的批大小分组调用 EmbedBatch，减少 Embedding API 往返并避免单次请求过大；
+// 某一批失败（报错或返回数量不符）时退化为逐条 Embed 重试，只有逐条也失败才中止索引。
+//
+// [OUT] retriever.go（indexDocuments 文档向量化）
+// [POS] RAG 索引层的批量 Embedding 策略
+
+package rag
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// DefaultEmbedBatchSize is the default number of chunks embedded per provider call.
+const DefaultEmbedBatchSize = 32
+
+// SetEmbedBatchSize sets how many chunks are embedded per EmbedBatch call. size <= 0 restores the default.
+func (r *RuleRetriever) SetEmbedBatchSize(size int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if size <= 0 {
+		size = DefaultEmbedBatchSize
+	}
+	r.embedBatchSize = size
+}
+
+// embedTexts embeds texts in batches, preserving input order in the result.
+func (r *RuleRetriever) embedTexts(ctx context.Context, texts []string) ([][]float64, error) {
+	batchSize := r.embedBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultEmbedBatchSize
+	}
+
+	embeddings := make([][]float64, 0, len(texts))
+	for start := 0; start < len(texts); start += batchSize {
+		end := min(start+batchSize, len(texts))
+		batch, err := r.embedBatchWithFallback(ctx, texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("rag.embedTexts: chunks %d-%d: %w", start, end-1, err)
+		}
+		embeddings = append(embeddings, batch...)
+	}
+	return embeddings, nil
+}
+
+// embedBatchWithFallback embeds one batch; on failure retries each chunk individually.
+func (r *RuleRetriever) embedBatchWithFallback(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors, err := r.embedder.EmbedBatch(ctx, texts)
+	if err == nil && len(vectors) == len(texts) {
+		return vectors, nil
+	}
+	slog.Warn("rag: batch embedding failed, retrying per chunk",
+		"size", len(texts), "returned", len(vectors), "error", err)
+
+	vectors = make([][]float64, len(texts))
+	for i, text := range texts {
+		vec, err := r.embedder.Embed(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("rag.embedBatchWithFallback: chunk %d: %w", i, err)
+		}
+		vectors[i] = vec
+	}
+	return vectors, nil
+}
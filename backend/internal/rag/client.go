@@ -13,6 +13,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/outbound"
 )
 
 // QdrantClient handles communication with Qdrant vector database.
@@ -29,10 +31,15 @@ func NewQdrantClient(host string, port int, collection string) *QdrantClient {
 		host:       host,
 		port:       port,
 		collection: collection,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: outbound.Client("", 30*time.Second),
 	}
 }
 
+// SetHTTPSProxy routes Qdrant requests through proxyURL; loopback hosts stay direct.
+func (c *QdrantClient) SetHTTPSProxy(proxyURL string) {
+	c.httpClient = outbound.Client(proxyURL, c.httpClient.Timeout)
+}
+
 // Point represents a Qdrant point.
 type Point struct {
 	ID      string                 `json:"id"`
@@ -13,6 +13,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/outbound"
 )
 
 // EmbeddingProvider generates embeddings from text.
@@ -37,6 +39,9 @@ type OpenAIEmbeddingConfig struct {
 	BaseURL    string // For OpenAI-compatible APIs
 	Model      string
 	Dimensions int
+
+	// HTTPSProxy routes requests through this proxy (see internal/outbound)
+	HTTPSProxy string
 }
 
 // NewOpenAIEmbedding creates a new OpenAI embedding provider.
@@ -56,9 +61,7 @@ func NewOpenAIEmbedding(cfg OpenAIEmbeddingConfig) *OpenAIEmbedding {
 		baseURL:    cfg.BaseURL,
 		model:      cfg.Model,
 		dimensions: cfg.Dimensions,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient: outbound.Client(cfg.HTTPSProxy, 30*time.Second),
 	}
 }
 
@@ -150,6 +153,9 @@ type GeminiEmbeddingConfig struct {
 	BaseURL    string // e.g. "https://generativelanguage.googleapis.com/v1beta"
 	Model      string // e.g. "gemini-embedding-001"
 	Dimensions int    // 768 by default
+
+	// HTTPSProxy routes requests through this proxy (see internal/outbound)
+	HTTPSProxy string
 }
 
 // NewGeminiEmbedding creates a new Gemini embedding provider.
@@ -168,7 +174,7 @@ func NewGeminiEmbedding(cfg GeminiEmbeddingConfig) *GeminiEmbedding {
 		baseURL:    cfg.BaseURL,
 		model:      cfg.Model,
 		dimensions: cfg.Dimensions,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: outbound.Client(cfg.HTTPSProxy, 30*time.Second),
 	}
 }
 
@@ -0,0 +1,36 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/outbound"
+)
+
+func TestGeminiEmbeddingUsesConfiguredProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String() // a forward proxy sees the absolute target URL
+		json.NewEncoder(w).Encode(map[string]interface{}{"embedding": map[string]interface{}{"values": []float64{0.5, 0.25}}})
+	}))
+	defer proxy.Close()
+
+	e := NewGeminiEmbedding(GeminiEmbeddingConfig{
+		APIKey:     "k",
+		BaseURL:    "http://embeddings.example.invalid/v1beta",
+		HTTPSProxy: proxy.URL,
+	})
+	if e.httpClient.Transport != outbound.Transport(proxy.URL) {
+		t.Fatal("expected the embedder to use the shared proxy transport")
+	}
+	vec, err := e.Embed(context.Background(), "imp")
+	if err != nil {
+		t.Fatalf("embed: %v", err)
+	}
+	if len(vec) != 2 || proxied == "" {
+		t.Fatalf("expected the request to go through the proxy, got %v via %q", vec, proxied)
+	}
+}
@@ -0,0 +1,123 @@
+// Package rag 本地关键词索引（向量检索降级方案）
+//
+// 索引规则文档时同步建立内存倒排索引。Qdrant 或 Embedding 服务不可用时，
+// Retrieve 退化为关键词匹配（词频 × 逆文档频率），保证 AutoDM 仍有规则依据。
+//
+// [OUT] retriever.go（Initialize 建索引、retrieve 降级检索）
+// [POS] RAG 检索层的离线兜底
+
+package rag
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// keywordIndex is an in-memory inverted index over rule chunks.
+type keywordIndex struct {
+	mu       sync.RWMutex
+	docs     []Document
+	postings map[string]map[int]int // term -> doc index -> term frequency
+	byID     map[string]int
+}
+
+func newKeywordIndex() *keywordIndex {
+	return &keywordIndex{
+		postings: make(map[string]map[int]int),
+		byID:     make(map[string]int),
+	}
+}
+
+// add indexes documents; a document with an existing ID is skipped.
+func (k *keywordIndex) add(docs ...Document) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, doc := range docs {
+		if _, exists := k.byID[doc.ID]; exists {
+			continue
+		}
+		idx := len(k.docs)
+		k.docs = append(k.docs, doc)
+		k.byID[doc.ID] = idx
+		for _, term := range tokenize(doc.Content) {
+			if k.postings[term] == nil {
+				k.postings[term] = make(map[int]int)
+			}
+			k.postings[term][idx]++
+		}
+	}
+}
+
+// size returns the number of indexed documents.
+func (k *keywordIndex) size() int {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return len(k.docs)
+}
+
+// search scores documents by TF-IDF over query terms, honoring exact-match metadata filters.
+func (k *keywordIndex) search(query string, limit int, filter map[string]interface{}) []RetrieveResult {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	scores := make(map[int]float64)
+	total := float64(len(k.docs))
+	for _, term := range tokenize(query) {
+		posting := k.postings[term]
+		idf := math.Log(1 + total/float64(len(posting)+1))
+		for idx, tf := range posting {
+			if matchesFilter(k.docs[idx].Metadata, filter) {
+				scores[idx] += float64(tf) * idf
+			}
+		}
+	}
+
+	ranked := make([]int, 0, len(scores))
+	for idx := range scores {
+		ranked = append(ranked, idx)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if scores[ranked[i]] != scores[ranked[j]] {
+			return scores[ranked[i]] > scores[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	results := make([]RetrieveResult, len(ranked))
+	for i, idx := range ranked {
+		doc := k.docs[idx]
+		results[i] = RetrieveResult{Content: doc.Content, Score: scores[idx], Metadata: doc.Metadata}
+	}
+	return results
+}
+
+// matchesFilter reports whether metadata contains every filter key with an equal value.
+func matchesFilter(metadata, filter map[string]interface{}) bool {
+	for key, want := range filter {
+		if fmt.Sprint(metadata[key]) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize lowercases text and splits it into letter/digit terms of length >= 2.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) >= 2 {
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
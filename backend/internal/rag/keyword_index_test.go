@@ -0,0 +1,50 @@
+package rag
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRetrieveFallsBackToKeywordsWhenQdrantFails(t *testing.T) {
+	rulesDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(rulesDir, "townsfolk_roles.md"), []byte(testRolesDoc), 0o644); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+	qdrant := newFakeQdrant(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "qdrant unavailable", http.StatusServiceUnavailable)
+	})
+	retriever := NewRuleRetriever(qdrant, newCountingEmbedder())
+
+	if err := retriever.Initialize(context.Background(), rulesDir); err == nil {
+		t.Fatal("expected Initialize to report the Qdrant failure")
+	}
+
+	results, err := retriever.Retrieve(context.Background(), "which player is the demon slayer", 1)
+	if err != nil {
+		t.Fatalf("expected keyword fallback instead of error, got %v", err)
+	}
+	if len(results) != 1 || !strings.HasPrefix(results[0].Content, "Slayer") {
+		t.Fatalf("expected Slayer chunk from keyword fallback, got %+v", results)
+	}
+
+	filtered, err := retriever.RetrieveWithFilter(context.Background(), "demon", 5,
+		map[string]interface{}{"role_name": "fortuneteller"})
+	if err != nil || len(filtered) != 1 || !strings.HasPrefix(filtered[0].Content, "Fortune Teller") {
+		t.Fatalf("expected filtered keyword fallback to return Fortune Teller only, got %+v, %v", filtered, err)
+	}
+}
+
+func TestRetrieveErrorsWithoutKeywordIndex(t *testing.T) {
+	qdrant := newFakeQdrant(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "qdrant unavailable", http.StatusServiceUnavailable)
+	})
+	retriever := NewRuleRetriever(qdrant, newCountingEmbedder())
+
+	if _, err := retriever.Retrieve(context.Background(), "slayer", 1); err == nil {
+		t.Fatal("expected error when Qdrant fails and nothing was ingested")
+	}
+}
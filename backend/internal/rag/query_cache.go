@@ -0,0 +1,100 @@
+// Package rag 查询向量 LRU 缓存
+//
+// AutoDM 每个事件都会构造规则查询，相同/近似的查询字符串反复出现
+// （如 "phase transition to day"），缓存其 Embedding 以减少 Embedding API 调用。
+//
+// [OUT] retriever.go（Retrieve / RetrieveWithFilter 查询向量化）
+// [POS] RAG 检索层的查询向量缓存，按容量淘汰最久未用项并带 TTL 过期
+
+package rag
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultQueryCacheSize is the default number of cached query embeddings.
+	DefaultQueryCacheSize = 256
+	// DefaultQueryCacheTTL is how long a cached query embedding stays valid.
+	DefaultQueryCacheTTL = 10 * time.Minute
+)
+
+// queryCacheEntry is one cached query embedding.
+type queryCacheEntry struct {
+	query     string
+	vector    []float64
+	expiresAt time.Time
+}
+
+// queryCache is a concurrency-safe LRU cache of query embeddings with TTL.
+type queryCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List // front = most recently used
+	entries  map[string]*list.Element
+	now      func() time.Time
+}
+
+// newQueryCache creates a cache; capacity <= 0 returns nil (caching disabled).
+func newQueryCache(capacity int, ttl time.Duration) *queryCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &queryCache{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element, capacity),
+		now:      time.Now,
+	}
+}
+
+// get returns the cached embedding for query if present and not expired.
+func (c *queryCache) get(query string) ([]float64, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[query]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*queryCacheEntry)
+	if c.ttl > 0 && c.now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, query)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.vector, true
+}
+
+// put stores an embedding, evicting the least recently used entry when full.
+func (c *queryCache) put(query string, vector []float64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if elem, ok := c.entries[query]; ok {
+		entry := elem.Value.(*queryCacheEntry)
+		entry.vector = vector
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[query] = c.order.PushFront(&queryCacheEntry{query: query, vector: vector, expiresAt: expiresAt})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*queryCacheEntry).query)
+	}
+}
@@ -9,10 +9,12 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 )
 
 // hashToUUID converts a sha256 hash to a valid UUID v4-like string for Qdrant.
@@ -32,24 +34,60 @@ type Document struct {
 
 // RuleRetriever handles rule document retrieval.
 type RuleRetriever struct {
-	qdrant   *QdrantClient
-	embedder EmbeddingProvider
-	mu       sync.RWMutex
+	qdrant         *QdrantClient
+	embedder       EmbeddingProvider
+	queryCache     *queryCache
+	keywords       *keywordIndex // 本地关键词索引，向量检索不可用时降级使用
+	embedBatchSize int
+	mu             sync.RWMutex
 }
 
-// NewRuleRetriever creates a new rule retriever.
+// NewRuleRetriever creates a new rule retriever with the default query embedding cache.
 func NewRuleRetriever(qdrant *QdrantClient, embedder EmbeddingProvider) *RuleRetriever {
 	return &RuleRetriever{
-		qdrant:   qdrant,
-		embedder: embedder,
+		qdrant:         qdrant,
+		embedder:       embedder,
+		queryCache:     newQueryCache(DefaultQueryCacheSize, DefaultQueryCacheTTL),
+		keywords:       newKeywordIndex(),
+		embedBatchSize: DefaultEmbedBatchSize,
 	}
 }
 
-// Initialize sets up the collection and indexes rule documents.
+// SetQueryCache replaces the query embedding cache. capacity <= 0 disables caching.
+func (r *RuleRetriever) SetQueryCache(capacity int, ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queryCache = newQueryCache(capacity, ttl)
+}
+
+// embedQuery returns the query embedding, consulting the cache first.
+func (r *RuleRetriever) embedQuery(ctx context.Context, query string) ([]float64, error) {
+	if vec, ok := r.queryCache.get(query); ok {
+		return vec, nil
+	}
+	vec, err := r.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	r.queryCache.put(query, vec)
+	return vec, nil
+}
+
+// Initialize loads rule documents, builds the local keyword index, then
+// ensures the Qdrant collection exists and indexes the documents into it.
+// The keyword index is built even when Qdrant is unreachable, so Retrieve
+// can still fall back to it.
 func (r *RuleRetriever) Initialize(ctx context.Context, rulesDir string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	// Load rule documents and build the keyword fallback index
+	docs, err := r.loadRuleDocuments(rulesDir)
+	if err != nil {
+		return fmt.Errorf("failed to load rules: %w", err)
+	}
+	r.keywords.add(docs...)
+
 	// Ensure collection exists
 	if err := r.qdrant.EnsureCollection(ctx, r.embedder.Dimensions()); err != nil {
 		return fmt.Errorf("failed to ensure collection: %w", err)
@@ -64,12 +102,6 @@ func (r *RuleRetriever) Initialize(ctx context.Context, rulesDir string) error {
 		return nil // Already indexed
 	}
 
-	// Load and index rule documents
-	docs, err := r.loadRuleDocuments(rulesDir)
-	if err != nil {
-		return fmt.Errorf("failed to load rules: %w", err)
-	}
-
 	return r.indexDocuments(ctx, docs)
 }
 
@@ -115,6 +147,13 @@ func (r *RuleRetriever) splitIntoChunks(content, source string) []Document {
 
 	for i, section := range sections {
 		section = strings.TrimSpace(section)
+
+		// Split out role subsections (### Role) so each role gets its own chunk
+		intro, roles := splitRoleSubsections(section)
+		for _, role := range roles {
+			docs = append(docs, newRoleChunk(source, i, role))
+		}
+		section = strings.TrimSpace(intro)
 		if len(section) < 20 {
 			continue
 		}
@@ -153,13 +192,13 @@ func (r *RuleRetriever) indexDocuments(ctx context.Context, docs []Document) err
 		return nil
 	}
 
-	// Batch embed
+	// Batch embed (grouped by embedBatchSize, per-chunk fallback on failure)
 	texts := make([]string, len(docs))
 	for i, doc := range docs {
 		texts[i] = doc.Content
 	}
 
-	embeddings, err := r.embedder.EmbedBatch(ctx, texts)
+	embeddings, err := r.embedTexts(ctx, texts)
 	if err != nil {
 		return fmt.Errorf("failed to embed documents: %w", err)
 	}
@@ -197,55 +236,44 @@ type RetrieveResult struct {
 
 // Retrieve searches for relevant rule documents.
 func (r *RuleRetriever) Retrieve(ctx context.Context, query string, limit int) ([]RetrieveResult, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	// Embed query
-	queryVec, err := r.embedder.Embed(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to embed query: %w", err)
-	}
-
-	// Search
-	results, err := r.qdrant.Search(ctx, queryVec, limit, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search: %w", err)
-	}
-
-	// Convert results
-	retrieved := make([]RetrieveResult, len(results))
-	for i, r := range results {
-		content := ""
-		if c, ok := r.Payload["content"].(string); ok {
-			content = c
-		}
-		delete(r.Payload, "content")
-		retrieved[i] = RetrieveResult{
-			Content:  content,
-			Score:    r.Score,
-			Metadata: r.Payload,
-		}
-	}
-
-	return retrieved, nil
+	return r.retrieve(ctx, query, limit, nil)
 }
 
 // RetrieveWithFilter searches with metadata filters.
 func (r *RuleRetriever) RetrieveWithFilter(ctx context.Context, query string, limit int, filter map[string]interface{}) ([]RetrieveResult, error) {
+	return r.retrieve(ctx, query, limit, filter)
+}
+
+// retrieve runs the vector search and falls back to the keyword index when
+// the embedding provider or Qdrant fails.
+func (r *RuleRetriever) retrieve(ctx context.Context, query string, limit int, filter map[string]interface{}) ([]RetrieveResult, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	queryVec, err := r.embedder.Embed(ctx, query)
-	if err != nil {
+	results, err := r.vectorSearch(ctx, query, limit, filter)
+	if err == nil {
+		return results, nil
+	}
+	if r.keywords.size() == 0 {
 		return nil, err
 	}
+	slog.Warn("rag: vector search unavailable, using keyword fallback", "error", err, "query", query)
+	return r.keywords.search(query, limit, filter), nil
+}
 
-	// Build Qdrant filter
-	qdrantFilter := buildQdrantFilter(filter)
+// vectorSearch embeds the query and searches Qdrant.
+func (r *RuleRetriever) vectorSearch(ctx context.Context, query string, limit int, filter map[string]interface{}) ([]RetrieveResult, error) {
+	if r.qdrant == nil {
+		return nil, fmt.Errorf("rag.vectorSearch: qdrant not configured")
+	}
+	queryVec, err := r.embedQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
 
-	results, err := r.qdrant.Search(ctx, queryVec, limit, qdrantFilter)
+	results, err := r.qdrant.Search(ctx, queryVec, limit, buildQdrantFilter(filter))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to search: %w", err)
 	}
 
 	retrieved := make([]RetrieveResult, len(results))
@@ -291,22 +319,23 @@ func (r *RuleRetriever) IndexRoleRules(ctx context.Context, roleID, roleName, ru
 
 	hash := sha256.Sum256([]byte(roleID + rules))
 	id := hashToUUID(hash)
+	metadata := map[string]interface{}{
+		"type":      "role",
+		"role_id":   roleID,
+		"role_name": normalizeRoleName(roleName),
+	}
+	r.keywords.add(Document{ID: id, Content: rules, Metadata: metadata})
 
 	embedding, err := r.embedder.Embed(ctx, rules)
 	if err != nil {
 		return err
 	}
 
-	point := Point{
-		ID:     id,
-		Vector: embedding,
-		Payload: map[string]interface{}{
-			"content":   rules,
-			"type":      "role",
-			"role_id":   roleID,
-			"role_name": roleName,
-		},
+	payload := map[string]interface{}{"content": rules}
+	for k, v := range metadata {
+		payload[k] = v
 	}
+	point := Point{ID: id, Vector: embedding, Payload: payload}
 
 	return r.qdrant.Upsert(ctx, []Point{point})
 }
@@ -0,0 +1,181 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingEmbedder wraps LocalEmbedding and counts provider calls.
+type countingEmbedder struct {
+	*LocalEmbedding
+	embedCalls int32
+	batchCalls int32
+}
+
+func newCountingEmbedder() *countingEmbedder {
+	return &countingEmbedder{LocalEmbedding: NewLocalEmbedding(8)}
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	atomic.AddInt32(&e.embedCalls, 1)
+	return e.LocalEmbedding.Embed(ctx, text)
+}
+
+func (e *countingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	atomic.AddInt32(&e.batchCalls, 1)
+	return e.LocalEmbedding.EmbedBatch(ctx, texts)
+}
+
+// newFakeQdrant serves handler as a Qdrant endpoint and returns a client pointed at it.
+func newFakeQdrant(t *testing.T, handler http.HandlerFunc) *QdrantClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	host, portStr, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("split host port: %v", err)
+	}
+	port, _ := strconv.Atoi(portStr)
+	return NewQdrantClient(host, port, "rules")
+}
+
+// searchResponder answers every request with a single fixed search hit.
+func searchResponder(content string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"result": []map[string]interface{}{{
+				"id":      "p1",
+				"score":   0.9,
+				"payload": map[string]interface{}{"content": content},
+			}},
+		})
+	}
+}
+
+func TestRetrieveCachesQueryEmbedding(t *testing.T) {
+	embedder := newCountingEmbedder()
+	retriever := NewRuleRetriever(newFakeQdrant(t, searchResponder("day rules")), embedder)
+
+	for i := 0; i < 2; i++ {
+		results, err := retriever.Retrieve(context.Background(), "phase transition to day", 2)
+		if err != nil {
+			t.Fatalf("retrieve: %v", err)
+		}
+		if len(results) != 1 || results[0].Content != "day rules" {
+			t.Fatalf("unexpected results: %+v", results)
+		}
+	}
+	if got := atomic.LoadInt32(&embedder.embedCalls); got != 1 {
+		t.Fatalf("expected 1 embedding call for repeated query, got %d", got)
+	}
+}
+
+func TestQueryCacheExpiresAndEvicts(t *testing.T) {
+	cache := newQueryCache(2, time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.put("a", []float64{1})
+	cache.put("b", []float64{2})
+	cache.get("a")
+	cache.put("c", []float64{3})
+	if _, ok := cache.get("b"); ok {
+		t.Fatal("expected least recently used entry to be evicted")
+	}
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("expected recently used entry to survive eviction")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.get("a"); ok {
+		t.Fatal("expected entry to expire after TTL")
+	}
+}
+
+// flakyBatchEmbedder fails every EmbedBatch call after the first okBatches.
+type flakyBatchEmbedder struct {
+	*countingEmbedder
+	okBatches int32
+}
+
+func (e *flakyBatchEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	if atomic.AddInt32(&e.batchCalls, 1) > e.okBatches {
+		return nil, context.DeadlineExceeded
+	}
+	return e.LocalEmbedding.EmbedBatch(ctx, texts)
+}
+
+func newTestChunks(n int) []Document {
+	docs := make([]Document, n)
+	for i := range docs {
+		docs[i] = Document{
+			ID:       strconv.Itoa(i),
+			Content:  "rule chunk " + strconv.Itoa(i),
+			Metadata: map[string]interface{}{"section": i},
+		}
+	}
+	return docs
+}
+
+func TestIndexDocumentsBatchesEmbedCalls(t *testing.T) {
+	embedder := newCountingEmbedder()
+	var upserted int32
+	qdrant := newFakeQdrant(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Points []Point `json:"points"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		atomic.AddInt32(&upserted, int32(len(body.Points)))
+		w.WriteHeader(http.StatusOK)
+	})
+	retriever := NewRuleRetriever(qdrant, embedder)
+	retriever.SetEmbedBatchSize(16)
+
+	if err := retriever.indexDocuments(context.Background(), newTestChunks(50)); err != nil {
+		t.Fatalf("index documents: %v", err)
+	}
+	if got := atomic.LoadInt32(&embedder.batchCalls); got != 4 {
+		t.Fatalf("expected 4 batch embed calls for 50 chunks, got %d", got)
+	}
+	if got := atomic.LoadInt32(&embedder.embedCalls); got != 0 {
+		t.Fatalf("expected no per-chunk embed calls, got %d", got)
+	}
+	if got := atomic.LoadInt32(&upserted); got != 50 {
+		t.Fatalf("expected 50 points upserted, got %d", got)
+	}
+}
+
+func TestEmbedTextsFallsBackPerChunkOnBatchFailure(t *testing.T) {
+	embedder := &flakyBatchEmbedder{countingEmbedder: newCountingEmbedder(), okBatches: 1}
+	retriever := NewRuleRetriever(nil, embedder)
+	retriever.SetEmbedBatchSize(10)
+
+	texts := make([]string, 15)
+	for i := range texts {
+		texts[i] = "chunk " + strconv.Itoa(i)
+	}
+	vectors, err := retriever.embedTexts(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("embed texts: %v", err)
+	}
+	if len(vectors) != len(texts) {
+		t.Fatalf("expected %d vectors, got %d", len(texts), len(vectors))
+	}
+	if got := atomic.LoadInt32(&embedder.embedCalls); got != 5 {
+		t.Fatalf("expected only the failed batch (5 chunks) to be retried, got %d", got)
+	}
+	want, _ := embedder.LocalEmbedding.Embed(context.Background(), texts[12])
+	for i := range want {
+		if vectors[12][i] != want[i] {
+			t.Fatal("expected fallback vectors to stay aligned with their chunks")
+		}
+	}
+}
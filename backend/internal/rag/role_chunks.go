@@ -0,0 +1,117 @@
+// Package rag 角色规则分块与角色偏置检索
+//
+// 规则文档中每个 "### 角色名" 小节单独成块，并带上 role_name 元数据（小写、去空格，
+// 与引擎角色 ID 一致，如 "fortuneteller"）。检索时可按 role_name 过滤，
+// 先返回该角色的规则块，不足 limit 时再用通用检索补齐。
+//
+// [OUT] retriever.go（splitIntoChunks 角色分块）
+// [OUT] cmd/server（ruleRetrieverAdapter 带过滤检索）
+// [POS] RAG 检索层的角色定向检索
+
+package rag
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// roleSection is one "### Role" subsection of a rules document.
+type roleSection struct {
+	title   string
+	name    string
+	content string
+}
+
+// splitRoleSubsections separates "### " subsections from the leading intro text.
+func splitRoleSubsections(section string) (string, []roleSection) {
+	parts := strings.Split("\n"+section, "\n### ")
+	if len(parts) == 1 {
+		return section, nil
+	}
+
+	roles := make([]roleSection, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		lines := strings.SplitN(part, "\n", 2)
+		title := strings.TrimSpace(lines[0])
+		body := ""
+		if len(lines) > 1 {
+			body = strings.TrimSpace(lines[1])
+		}
+		if title == "" || body == "" {
+			continue
+		}
+		roles = append(roles, roleSection{title: title, name: normalizeRoleName(title), content: body})
+	}
+	return parts[0], roles
+}
+
+// normalizeRoleName turns a heading like "Fortune Teller" or "Poisoner (as Demon)" into a role ID.
+func normalizeRoleName(heading string) string {
+	if i := strings.Index(heading, "("); i >= 0 {
+		heading = heading[:i]
+	}
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(heading)))
+}
+
+// newRoleChunk builds the document for one role subsection.
+func newRoleChunk(source string, section int, role roleSection) Document {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:role:%s", source, section, role.title)))
+	return Document{
+		ID:      hashToUUID(hash),
+		Content: role.title + "\n" + role.content,
+		Metadata: map[string]interface{}{
+			"source":    filepath.Base(source),
+			"title":     role.title,
+			"section":   section,
+			"role_name": role.name,
+		},
+	}
+}
+
+// RetrieveBiased returns chunks matching filter first (e.g. {"role_name": "slayer"}),
+// then tops up with unfiltered results up to limit. An empty filter behaves like Retrieve.
+func (r *RuleRetriever) RetrieveBiased(ctx context.Context, query string, limit int, filter map[string]string) ([]RetrieveResult, error) {
+	if len(filter) == 0 {
+		return r.Retrieve(ctx, query, limit)
+	}
+
+	qdrantFilter := make(map[string]interface{}, len(filter))
+	for k, v := range filter {
+		qdrantFilter[k] = v
+	}
+	preferred, err := r.RetrieveWithFilter(ctx, query, limit, qdrantFilter)
+	if err != nil {
+		return nil, fmt.Errorf("rag.RetrieveBiased: %w", err)
+	}
+	if len(preferred) >= limit {
+		return preferred, nil
+	}
+
+	general, err := r.Retrieve(ctx, query, limit)
+	if err != nil {
+		return preferred, nil
+	}
+	return mergeRetrieveResults(preferred, general, limit), nil
+}
+
+// mergeRetrieveResults appends general results not already in preferred, up to limit.
+func mergeRetrieveResults(preferred, general []RetrieveResult, limit int) []RetrieveResult {
+	seen := make(map[string]bool, len(preferred))
+	merged := append([]RetrieveResult{}, preferred...)
+	for _, res := range preferred {
+		seen[res.Content] = true
+	}
+	for _, res := range general {
+		if len(merged) >= limit {
+			break
+		}
+		if !seen[res.Content] {
+			seen[res.Content] = true
+			merged = append(merged, res)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,65 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+const testRolesDoc = `# Townsfolk Role Templates
+
+## Action Roles
+
+### Slayer
+Once per game, during the day, publicly choose a player: if they are the Demon, they die.
+
+### Fortune Teller
+Each night, choose 2 players: you learn if either is a Demon.
+`
+
+func TestSplitIntoChunksTagsRoleSubsections(t *testing.T) {
+	retriever := NewRuleRetriever(nil, NewLocalEmbedding(8))
+	docs := retriever.splitIntoChunks(testRolesDoc, "townsfolk_roles.md")
+
+	names := map[string]bool{}
+	for _, doc := range docs {
+		if name, ok := doc.Metadata["role_name"].(string); ok {
+			names[name] = true
+		}
+	}
+	if !names["slayer"] || !names["fortuneteller"] {
+		t.Fatalf("expected slayer and fortuneteller role chunks, got %v", names)
+	}
+}
+
+func TestRetrieveBiasedReturnsRoleChunkFirst(t *testing.T) {
+	qdrant := newFakeQdrant(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Filter map[string]interface{} `json:"filter"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		hits := []map[string]interface{}{
+			{"id": "mayor", "score": 0.95, "payload": map[string]interface{}{"content": "Mayor rules", "role_name": "mayor"}},
+			{"id": "slayer", "score": 0.80, "payload": map[string]interface{}{"content": "Slayer rules", "role_name": "slayer"}},
+		}
+		if body.Filter != nil {
+			hits = hits[1:]
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": hits})
+	})
+	retriever := NewRuleRetriever(qdrant, newCountingEmbedder())
+
+	results, err := retriever.RetrieveBiased(context.Background(), "day ability kills the demon", 2,
+		map[string]string{"role_name": "slayer"})
+	if err != nil {
+		t.Fatalf("retrieve biased: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected role hit plus one general hit, got %+v", results)
+	}
+	if results[0].Content != "Slayer rules" {
+		t.Fatalf("expected Slayer chunk first, got %q", results[0].Content)
+	}
+}
@@ -0,0 +1,95 @@
+// Package realtime WebSocket permessage-deflate 压缩
+//
+// 客户端在握手时声明 permessage-deflate 即协商压缩；状态快照、重放等大帧才压缩，
+// 小于阈值的帧直接发送以免浪费 CPU。压缩由 gorilla/websocket 在写帧时完成一次；节省的字节数
+// 只对每个会话的首帧及此后每 compressionSampleEvery 帧抽样估算，按抽样间隔放大后计入指标。
+//
+// [IN]  internal/observability（ws_compression_bytes_saved_total 指标）
+// [OUT] ws.go（ServeHTTP 协商、writePump 写帧）
+// [POS] 实时通信层的帧压缩策略
+package realtime
+
+import (
+	"compress/flate"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultCompressionThreshold is the minimum frame size (bytes) that gets compressed.
+const DefaultCompressionThreshold = 1024
+
+// compressionLevel matches gorilla/websocket's default write compression level.
+const compressionLevel = 1
+
+// compressionSampleEvery is how many compressed frames share one bytes-saved estimate.
+const compressionSampleEvery = 16
+
+// SetCompressionThreshold sets the minimum frame size to compress; n <= 0 restores the default.
+func (ws *WSServer) SetCompressionThreshold(n int) {
+	if n <= 0 {
+		n = DefaultCompressionThreshold
+	}
+	ws.compressThreshold = n
+}
+
+// clientOffersDeflate reports whether the handshake advertises permessage-deflate.
+func clientOffersDeflate(r *http.Request) bool {
+	for _, ext := range r.Header.Values("Sec-WebSocket-Extensions") {
+		if strings.Contains(ext, "permessage-deflate") {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFrame writes one text frame, compressing it when negotiated and above the threshold.
+func (s *Session) writeFrame(data []byte) error {
+	compress := s.compress && len(data) >= s.compressThreshold
+	s.conn.EnableWriteCompression(compress)
+	if compress && s.metrics != nil {
+		s.sampleBytesSaved(data)
+	}
+	return s.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// sampleBytesSaved estimates the savings of every compressionSampleEvery-th compressed frame
+// and credits it for the whole sample interval, so most frames are deflated only once.
+func (s *Session) sampleBytesSaved(data []byte) {
+	sampled := s.compressedFrames%compressionSampleEvery == 0
+	s.compressedFrames++
+	if !sampled {
+		return
+	}
+	if saved := len(data) - deflatedSize(data); saved > 0 {
+		s.metrics.WSCompressionBytesSaved.Add(float64(saved * compressionSampleEvery))
+	}
+}
+
+// byteCounter counts bytes written to it and discards them.
+type byteCounter int
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+var flateWriterPool = sync.Pool{
+	New: func() any {
+		w, _ := flate.NewWriter(nil, compressionLevel)
+		return w
+	},
+}
+
+// deflatedSize estimates the compressed payload size at the negotiated level.
+func deflatedSize(data []byte) int {
+	var counter byteCounter
+	w := flateWriterPool.Get().(*flate.Writer)
+	defer flateWriterPool.Put(w)
+	w.Reset(&counter)
+	_, _ = w.Write(data)
+	_ = w.Flush()
+	return int(counter)
+}
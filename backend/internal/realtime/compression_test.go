@@ -0,0 +1,129 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/auth"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/observability"
+)
+
+// countingConn counts bytes read off the wire by the client.
+type countingConn struct {
+	net.Conn
+	read *int64
+}
+
+func (c countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	atomic.AddInt64(c.read, int64(n))
+	return n, err
+}
+
+// echoPing connects, sends a ping padded with padRepeat phrases (echoed back as pong)
+// and returns the wire bytes read, the server's bytes-saved counter and the pong size.
+func echoPing(t *testing.T, clientCompression bool, padRepeat int) (int64, float64, int) {
+	t.Helper()
+	jwtMgr := auth.NewJWTManager("test-secret", time.Hour)
+	reg := prometheus.NewRegistry()
+	server := NewWSServer(jwtMgr, nil, nil, zap.NewNop(), observability.NewMetrics(reg))
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	token, err := jwtMgr.Generate("user-1")
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	var wireBytes int64
+	dialer := websocket.Dialer{
+		EnableCompression: clientCompression,
+		NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+			return countingConn{Conn: conn, read: &wireBytes}, err
+		},
+	}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"?token="+token, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	pad := strings.Repeat("night info snapshot ", padRepeat)
+	payload, _ := json.Marshal(map[string]string{"pad": pad})
+	if err := conn.WriteJSON(WSMessage{Type: "ping", Payload: payload}); err != nil {
+		t.Fatalf("write ping: %v", err)
+	}
+	var pong WSMessage
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := conn.ReadJSON(&pong); err != nil {
+		t.Fatalf("read pong: %v", err)
+	}
+
+	saved := 0.0
+	families, _ := reg.Gather()
+	for _, mf := range families {
+		if mf.GetName() == "ws_compression_bytes_saved_total" {
+			saved = mf.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	return atomic.LoadInt64(&wireBytes), saved, len(pong.Payload)
+}
+
+func TestLargeFrameCompressedWhenClientSupportsDeflate(t *testing.T) {
+	wire, saved, size := echoPing(t, true, 1000)
+	if wire >= int64(size)/2 {
+		t.Fatalf("expected compressed frame well below %d bytes on the wire, read %d", size, wire)
+	}
+	if saved <= 0 {
+		t.Fatal("expected bytes-saved metric to increase")
+	}
+}
+
+func TestLargeFrameSentRawWithoutDeflate(t *testing.T) {
+	wire, saved, size := echoPing(t, false, 1000)
+	if wire < int64(size) {
+		t.Fatalf("expected raw frame of at least %d bytes on the wire, read %d", size, wire)
+	}
+	if saved != 0 {
+		t.Fatalf("expected no bytes saved without negotiation, got %v", saved)
+	}
+}
+
+func TestSmallFramesSkipCompression(t *testing.T) {
+	if _, saved, _ := echoPing(t, true, 2); saved != 0 {
+		t.Fatalf("expected frames below the threshold to skip compression, saved %v", saved)
+	}
+}
+
+func TestBytesSavedSampledOncePerInterval(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	s := &Session{metrics: observability.NewMetrics(reg)}
+	frame := []byte(strings.Repeat("night info snapshot ", 100))
+	perFrame := len(frame) - deflatedSize(frame)
+
+	for i := 0; i < compressionSampleEvery+1; i++ {
+		s.sampleBytesSaved(frame)
+	}
+	families, _ := reg.Gather()
+	for _, mf := range families {
+		if mf.GetName() != "ws_compression_bytes_saved_total" {
+			continue
+		}
+		want := float64(2 * perFrame * compressionSampleEvery)
+		if got := mf.GetMetric()[0].GetCounter().GetValue(); got != want {
+			t.Fatalf("expected two sampled estimates totalling %v, got %v", want, got)
+		}
+		return
+	}
+	t.Fatal("bytes-saved metric not registered")
+}
@@ -0,0 +1,79 @@
+// Package realtime 订阅级事件类型过滤
+//
+// subscribe 帧可携带 event_types，服务器只转发匹配的事件（在可见性投影之后过滤），
+// 减少精简客户端的带宽。支持精确类型（"public.chat"）与前缀通配（"phase.*"）；
+// 为空表示接收全部事件。
+//
+// [IN]  internal/room（Subscriber）
+// [IN]  internal/types（ProjectedEvent）
+// [OUT] ws.go（handleSubscribe 注册订阅与历史补发）
+// [POS] 实时通信层的订阅过滤
+package realtime
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/room"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// eventTypeFilter matches event types against exact names and "prefix.*" patterns.
+// A nil filter allows every event.
+type eventTypeFilter struct {
+	exact    map[string]bool
+	prefixes []string
+}
+
+// newEventTypeFilter builds a filter from the subscribe frame; empty input returns nil.
+func newEventTypeFilter(eventTypes []string) *eventTypeFilter {
+	if len(eventTypes) == 0 {
+		return nil
+	}
+	f := &eventTypeFilter{exact: make(map[string]bool, len(eventTypes))}
+	for _, t := range eventTypes {
+		t = strings.TrimSpace(t)
+		switch {
+		case t == "":
+			continue
+		case t == "*":
+			return nil
+		case strings.HasSuffix(t, ".*"):
+			f.prefixes = append(f.prefixes, strings.TrimSuffix(t, "*"))
+		default:
+			f.exact[t] = true
+		}
+	}
+	return f
+}
+
+// allows reports whether eventType passes the filter.
+func (f *eventTypeFilter) allows(eventType string) bool {
+	if f == nil || f.exact[eventType] {
+		return true
+	}
+	for _, prefix := range f.prefixes {
+		if strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// newSubscriber builds the room subscriber that forwards projected events matching filter.
+func (s *Session) newSubscriber(isDM bool, filter *eventTypeFilter) *room.Subscriber {
+	return &room.Subscriber{
+		UserID: s.userID,
+		IsDM:   isDM,
+		Send: func(pe types.ProjectedEvent) {
+			if !filter.allows(pe.EventType) {
+				return
+			}
+			b, _ := json.Marshal(WSMessage{Type: "event", Payload: mustMarshal(pe)})
+			select {
+			case s.send <- b:
+			default:
+			}
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package realtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// drainEventTypes reads every queued frame from the session and returns the event types.
+func drainEventTypes(t *testing.T, s *Session) []string {
+	t.Helper()
+	var got []string
+	for {
+		select {
+		case b := <-s.send:
+			var msg WSMessage
+			var pe types.ProjectedEvent
+			if err := json.Unmarshal(b, &msg); err != nil {
+				t.Fatalf("unmarshal frame: %v", err)
+			}
+			if err := json.Unmarshal(msg.Payload, &pe); err != nil {
+				t.Fatalf("unmarshal event: %v", err)
+			}
+			got = append(got, pe.EventType)
+		default:
+			return got
+		}
+	}
+}
+
+func publishAll(sendTo func(types.ProjectedEvent), eventTypes ...string) {
+	for i, et := range eventTypes {
+		sendTo(types.ProjectedEvent{RoomID: "room-1", Seq: int64(i + 1), EventType: et, Data: json.RawMessage(`{}`)})
+	}
+}
+
+func TestFilteredSubscriberReceivesOnlyRequestedTypes(t *testing.T) {
+	s := &Session{userID: "player-1", send: make(chan []byte, 16)}
+	sub := s.newSubscriber(false, newEventTypeFilter([]string{"public.chat", "phase.*"}))
+
+	publishAll(sub.Send, "public.chat", "vote.cast", "phase.day", "nomination.created", "phase.night")
+
+	got := drainEventTypes(t, s)
+	want := []string{"public.chat", "phase.day", "phase.night"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestUnfilteredSubscriberReceivesEverything(t *testing.T) {
+	s := &Session{userID: "dm", send: make(chan []byte, 16)}
+	sub := s.newSubscriber(true, newEventTypeFilter(nil))
+
+	publishAll(sub.Send, "public.chat", "vote.cast", "phase.day")
+
+	if got := drainEventTypes(t, s); len(got) != 3 {
+		t.Fatalf("expected all 3 events for unfiltered subscriber, got %v", got)
+	}
+}
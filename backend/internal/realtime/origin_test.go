@@ -0,0 +1,69 @@
+package realtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/auth"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/observability"
+)
+
+// dialStatus attempts a handshake and returns the HTTP status (101 on success).
+func dialStatus(t *testing.T, url, origin string) int {
+	t.Helper()
+	header := http.Header{}
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	if err == nil {
+		conn.Close()
+		return http.StatusSwitchingProtocols
+	}
+	if resp == nil {
+		t.Fatalf("dial failed without a response: %v", err)
+	}
+	return resp.StatusCode
+}
+
+func TestHandshakeRequiresValidTokenAndAllowedOrigin(t *testing.T) {
+	jwtMgr := auth.NewJWTManager("test-secret", time.Hour)
+	server := NewWSServer(jwtMgr, nil, nil, zap.NewNop(), observability.NewMetrics(prometheus.NewRegistry()))
+	server.SetAllowedOrigins([]string{"https://botc.example"})
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+	base := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	token, _ := jwtMgr.Generate("user-1")
+	cases := []struct {
+		name, query, origin string
+		want                int
+	}{
+		{"missing token", "", "https://botc.example", http.StatusUnauthorized},
+		{"invalid token", "?token=garbage", "https://botc.example", http.StatusUnauthorized},
+		{"foreign origin", "?token=" + token, "https://evil.example", http.StatusForbidden},
+		{"valid token and origin", "?token=" + token, "https://botc.example", http.StatusSwitchingProtocols},
+		{"valid token without origin", "?token=" + token, "", http.StatusSwitchingProtocols},
+	}
+	for _, tc := range cases {
+		if got := dialStatus(t, base+tc.query, tc.origin); got != tc.want {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestOriginAllowedWithEmptyListAllowsAll(t *testing.T) {
+	if !OriginAllowed(nil, "https://anything.example") {
+		t.Fatal("expected an empty allowlist to allow any origin")
+	}
+	if !OriginAllowed([]string{"https://BOTC.example"}, "https://botc.example") {
+		t.Fatal("expected origin match to be case-insensitive")
+	}
+}
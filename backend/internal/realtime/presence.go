@@ -0,0 +1,54 @@
+// Package realtime 连接生命周期与对局在场状态
+//
+// 玩家在房间的最后一个 WebSocket 订阅断开时，代其向房间派发 disconnect (engine 记
+// player.disconnected，座位、角色与存活不变)；同一用户的其他会话仍在订阅时不派发。
+// 订阅建立时若该玩家被标记为断线，派发 reconnect 清除标记。未入座或已是目标状态时不派发。
+//
+// [IN]  internal/room（RoomActor.HasSubscriber、Dispatch、GetState）
+// [IN]  internal/types（CommandEnvelope）
+// [OUT] ws.go（readPump 退出、handleSubscribe 成功时调用）
+// [POS] 实时通信层与玩家在场状态的衔接
+package realtime
+
+import (
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/room"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// markAbsent dispatches disconnect once the user's last session in the room is gone.
+func (s *Session) markAbsent(ra *room.RoomActor) {
+	if ra.HasSubscriber(s.userID) {
+		return
+	}
+	p, ok := ra.GetState().Players[s.userID]
+	if !ok || p.IsDM || p.Disconnected {
+		return
+	}
+	s.dispatchPresence(ra, "disconnect")
+}
+
+// markPresent dispatches reconnect when a disconnected player subscribes again.
+func (s *Session) markPresent(ra *room.RoomActor) {
+	p, ok := ra.GetState().Players[s.userID]
+	if !ok || !p.Disconnected {
+		return
+	}
+	s.dispatchPresence(ra, "reconnect")
+}
+
+func (s *Session) dispatchPresence(ra *room.RoomActor, cmdType string) {
+	commandID := uuid.NewString()
+	resp := ra.Dispatch(types.CommandEnvelope{
+		CommandID:      commandID,
+		IdempotencyKey: commandID,
+		RoomID:         ra.RoomID,
+		Type:           cmdType,
+		ActorUserID:    s.userID,
+	})
+	if resp.Err != nil {
+		s.logger.Warn("presence dispatch failed", zap.String("room_id", ra.RoomID), zap.String("type", cmdType), zap.Error(resp.Err))
+	}
+}
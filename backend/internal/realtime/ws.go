@@ -36,8 +36,9 @@ type WSMessage struct {
 }
 
 type SubscribePayload struct {
-	RoomID  string `json:"room_id"`
-	LastSeq int64  `json:"last_seq"`
+	RoomID     string   `json:"room_id"`
+	LastSeq    int64    `json:"last_seq"`
+	EventTypes []string `json:"event_types,omitempty"` // 可选：只接收这些事件类型（支持 "phase.*"），为空接收全部
 }
 
 type CommandPayload struct {
@@ -50,27 +51,33 @@ type CommandPayload struct {
 }
 
 type WSServer struct {
-	upgrader websocket.Upgrader
-	jwt      *auth.JWTManager
-	store    *store.Store
-	roomMgr  *room.RoomManager
-	logger   *zap.Logger
-	metrics  *observability.Metrics
+	upgrader          websocket.Upgrader
+	jwt               *auth.JWTManager
+	store             *store.Store
+	roomMgr           *room.RoomManager
+	logger            *zap.Logger
+	metrics           *observability.Metrics
+	compressThreshold int
+
+	allowedOrigins []string // CORS 白名单，空则不限制握手来源
 }
 
 func NewWSServer(jwt *auth.JWTManager, st *store.Store, roomMgr *room.RoomManager, logger *zap.Logger, metrics *observability.Metrics) *WSServer {
-	return &WSServer{
+	ws := &WSServer{
 		upgrader: websocket.Upgrader{
-			ReadBufferSize:  4096,
-			WriteBufferSize: 4096,
-			CheckOrigin:     func(r *http.Request) bool { return true },
+			ReadBufferSize:    4096,
+			WriteBufferSize:   4096,
+			EnableCompression: true,
 		},
-		jwt:     jwt,
-		store:   st,
-		roomMgr: roomMgr,
-		logger:  logger,
-		metrics: metrics,
+		jwt:               jwt,
+		store:             st,
+		roomMgr:           roomMgr,
+		logger:            logger,
+		metrics:           metrics,
+		compressThreshold: DefaultCompressionThreshold,
 	}
+	ws.upgrader.CheckOrigin = ws.checkOrigin
+	return ws
 }
 
 func (ws *WSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -100,6 +107,9 @@ func (ws *WSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		metrics: ws.metrics,
 		send:    make(chan []byte, 64),
 		limiter: NewTokenBucket(10, 2),
+		// gorilla 在客户端声明 permessage-deflate 时自动协商
+		compress:          clientOffersDeflate(r),
+		compressThreshold: ws.compressThreshold,
 	}
 	ws.metrics.ActiveConnections.Inc()
 	go session.writePump()
@@ -120,6 +130,10 @@ type Session struct {
 	subID   string
 	limiter *TokenBucket
 	mu      sync.Mutex
+
+	compress          bool // 握手已协商 permessage-deflate
+	compressThreshold int  // 不小于该字节数的帧才压缩
+	compressedFrames  int  // 已压缩帧计数，仅 writePump 访问
 }
 
 func (s *Session) readPump() {
@@ -128,6 +142,7 @@ func (s *Session) readPump() {
 			ra, _ := s.roomMgr.GetOrCreate(context.Background(), s.subRoom)
 			if ra != nil {
 				ra.Unsubscribe(s.subID)
+				s.markAbsent(ra) // presence.go
 			}
 		}
 		s.conn.Close()
@@ -169,7 +184,7 @@ func (s *Session) writePump() {
 				return
 			}
 			s.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := s.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			if err := s.writeFrame(data); err != nil {
 				return
 			}
 		case <-ticker.C:
@@ -224,22 +239,15 @@ func (s *Session) handleSubscribe(reqID string, payload SubscribePayload) {
 	s.subRoom = payload.RoomID
 	s.subID = s.id
 	isDM := role == "dm"
-	ra.Subscribe(s.subID, &room.Subscriber{
-		UserID: s.userID,
-		IsDM:   isDM,
-		Send: func(pe types.ProjectedEvent) {
-			b, _ := json.Marshal(WSMessage{Type: "event", Payload: mustMarshal(pe)})
-			select {
-			case s.send <- b:
-			default:
-			}
-		},
-	})
+	filter := newEventTypeFilter(payload.EventTypes)
+	ra.Subscribe(s.subID, s.newSubscriber(isDM, filter))
+	s.markPresent(ra) // presence.go
 	events, _ := s.store.LoadEventsAfter(ctx, payload.RoomID, payload.LastSeq, 200)
 	state := ra.GetState()
 	viewer := types.Viewer{UserID: s.userID, IsDM: isDM}
+	history := make([]types.Event, 0, len(events))
 	for _, e := range events {
-		ev := types.Event{
+		history = append(history, types.Event{
 			RoomID:            e.RoomID,
 			Seq:               e.Seq,
 			EventID:           e.EventID,
@@ -248,9 +256,12 @@ func (s *Session) handleSubscribe(reqID string, payload SubscribePayload) {
 			CausationCommand:  e.CausationCommand,
 			Payload:           json.RawMessage(e.PayloadJSON),
 			ServerTimestampMs: e.ServerTime.UnixMilli(),
-		}
-		pe := projection.Project(ev, state, viewer)
-		if pe == nil {
+			CorrelationID:     e.CorrelationID,
+		})
+	}
+	for _, ev := range projection.WithoutRetracted(history) {
+		pe := projection.ProjectEvent(ev, state, viewer)
+		if pe == nil || !filter.allows(pe.EventType) {
 			continue
 		}
 		b, _ := json.Marshal(WSMessage{Type: "event", Payload: mustMarshal(pe)})
@@ -0,0 +1,117 @@
+// Package room Auto-DM 与人类 DM 的命令冲突裁决
+//
+// 同一冲突类 (阶段推进、提名流程、计时) 的命令互相矛盾。Auto-DM (含阶段计时器) 的冲突类命令
+// 由 Actor 循环按关联 ID 登记为待执行；人类 DM 的同类命令落库成功后，人类优先：已登记的待执行
+// Auto-DM 命令被取消，且此后 autoDMConflictWindow 内到达的同类 Auto-DM 命令 (基于旧状态作出的
+// 决定) 也被拒绝 (ErrAutoDMOverridden，计入 command_reject_total{reason="autodm_conflict"})。
+//
+// [IN]  internal/engine（IsHumanDM）
+// [OUT] room.go（handleCommand 登记、裁决，落库后记录人类 DM 命令）
+// [POS] 房间 Actor 内人类 DM 对 Auto-DM 的优先权
+package room
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// autoDMConflictWindow is how long a human DM command overrides same-class Auto-DM commands.
+const autoDMConflictWindow = 10 * time.Second
+
+// ErrAutoDMOverridden rejects an Auto-DM command that contradicts a recent human DM command.
+var ErrAutoDMOverridden = errors.New("auto-dm command overridden by the human DM")
+
+// conflictClasses groups commands that contradict each other when issued by both storytellers.
+var conflictClasses = map[string]string{
+	"advance_phase":      "phase",
+	"night_timeout":      "phase",
+	"end_defense":        "nomination",
+	"close_vote":         "nomination",
+	"resolve_nomination": "nomination",
+	"resolve_tie":        "nomination",
+	"set_timer":          "timer",
+	"extend_time":        "timer",
+}
+
+// autoDMConflicts tracks pending Auto-DM commands and recent human DM overrides. The zero
+// value is ready to use.
+type autoDMConflicts struct {
+	mu       sync.Mutex
+	window   time.Duration
+	pending  map[string]string    // command key -> conflict class
+	canceled map[string]bool      // command keys canceled while pending
+	humanAt  map[string]time.Time // conflict class -> last human DM command
+}
+
+func isAutoDMActor(actor string) bool {
+	return types.IsAutoDMActor(actor)
+}
+
+// conflictKey identifies a command by correlation ID, falling back to its command ID.
+func conflictKey(cmd types.CommandEnvelope) string {
+	if cmd.CorrelationID != "" {
+		return cmd.CorrelationID
+	}
+	return cmd.CommandID
+}
+
+// track registers an Auto-DM conflict-class command as pending when the actor loop picks it up.
+func (c *autoDMConflicts) track(cmd types.CommandEnvelope) {
+	class, ok := conflictClasses[cmd.Type]
+	if !ok || !isAutoDMActor(cmd.ActorUserID) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending == nil {
+		c.pending = make(map[string]string)
+		c.canceled = make(map[string]bool)
+	}
+	c.pending[conflictKey(cmd)] = class
+}
+
+// admit reports whether cmd may run; an Auto-DM command is refused if it was canceled while
+// pending or a human DM issued a same-class command within the window.
+func (c *autoDMConflicts) admit(cmd types.CommandEnvelope, now time.Time) error {
+	class, ok := conflictClasses[cmd.Type]
+	if !ok || !isAutoDMActor(cmd.ActorUserID) {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := conflictKey(cmd)
+	canceled := c.canceled[key]
+	delete(c.pending, key)
+	delete(c.canceled, key)
+	window := c.window
+	if window <= 0 {
+		window = autoDMConflictWindow
+	}
+	if at, seen := c.humanAt[class]; canceled || (seen && now.Sub(at) < window) {
+		return ErrAutoDMOverridden
+	}
+	return nil
+}
+
+// humanIssued records a persisted human DM command and cancels pending same-class Auto-DM commands.
+func (c *autoDMConflicts) humanIssued(state engine.State, cmd types.CommandEnvelope, now time.Time) {
+	class, ok := conflictClasses[cmd.Type]
+	if !ok || !engine.IsHumanDM(state, cmd.ActorUserID) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.humanAt == nil {
+		c.humanAt = make(map[string]time.Time)
+	}
+	c.humanAt[class] = now
+	for key, pendingClass := range c.pending {
+		if pendingClass == class {
+			c.canceled[key] = true
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package room
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func advancePhaseCommand(id, actor, phase string) types.CommandEnvelope {
+	payload, _ := json.Marshal(map[string]string{"phase": phase})
+	return types.CommandEnvelope{
+		CommandID:      id,
+		IdempotencyKey: id,
+		RoomID:         "room-1",
+		Type:           "advance_phase",
+		ActorUserID:    actor,
+		Payload:        payload,
+	}
+}
+
+func TestHumanDMAdvanceCancelsQueuedAutoDMAdvance(t *testing.T) {
+	ra := newIdleTestActor(t, newMemEventLog())
+	ra.state.Phase = engine.PhaseNomination
+	ra.state.Players["dm"] = engine.Player{UserID: "dm", IsDM: true}
+	ra.state.DemonID = "imp"
+	for i, p := range []engine.Player{
+		{UserID: "imp", TrueRole: "imp", Team: "evil"},
+		{UserID: "chef", TrueRole: "chef", Team: "good"},
+		{UserID: "monk", TrueRole: "monk", Team: "good"},
+		{UserID: "empath", TrueRole: "empath", Team: "good"},
+	} {
+		p.Alive, p.SeatNumber = true, i+1
+		ra.state.Players[p.UserID] = p
+		ra.state.SeatOrder = append(ra.state.SeatOrder, p.UserID)
+	}
+
+	// The human command is ahead of the Auto-DM one in the queue, so the
+	// Auto-DM decision was made against the state the human just changed.
+	humanResp := make(chan CommandResponse, 1)
+	ra.cmdCh <- CommandRequest{Cmd: advancePhaseCommand("human-1", "dm", "day"), Response: humanResp}
+	autoResp := make(chan CommandResponse, 1)
+	go func() { autoResp <- ra.Dispatch(advancePhaseCommand("auto-1", "autodm", "nomination")) }()
+	for len(ra.cmdCh) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	go ra.loop(ra.ctx)
+
+	if resp := <-humanResp; resp.Err != nil {
+		t.Fatalf("human advance_phase: %v", resp.Err)
+	}
+	if resp := <-autoResp; !errors.Is(resp.Err, ErrAutoDMOverridden) {
+		t.Fatalf("expected the queued Auto-DM advance_phase to be overridden, got %v", resp.Err)
+	}
+	if ra.state.Phase != engine.PhaseDay {
+		t.Fatalf("expected the human's day phase to stand, got %s", ra.state.Phase)
+	}
+}
+
+func TestAutoDMConflictsOnlyCancelSameClass(t *testing.T) {
+	var c autoDMConflicts
+	state := engine.NewState("room-1")
+	state.Players["dm"] = engine.Player{UserID: "dm", IsDM: true}
+	now := time.Now()
+
+	timer := types.CommandEnvelope{CommandID: "auto-timer", Type: "set_timer", ActorUserID: "autodm"}
+	c.track(timer)
+	c.humanIssued(state, advancePhaseCommand("human-1", "dm", "day"), now)
+	if err := c.admit(timer, now); err != nil {
+		t.Fatalf("expected a different conflict class to run, got %v", err)
+	}
+	later := advancePhaseCommand("auto-2", "autodm", "night")
+	if err := c.admit(later, now.Add(autoDMConflictWindow)); err != nil {
+		t.Fatalf("expected the override to expire after the window, got %v", err)
+	}
+}
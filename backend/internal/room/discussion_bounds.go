@@ -0,0 +1,54 @@
+// Package room 白天讨论时长上下限的调度
+//
+// 房间开启 MinDiscussionSec / MaxDiscussionSec 时：白天开始用独立的 dayMaxTimer 在 Max 到期时
+// 强制入夜 (不受辩护/投票等阶段计时覆盖)；提名结算时若白天已满 Min 立即推进入夜，
+// 未满 Min 则把 nomination.resolved 排的入夜计时改到 Min 到期与提名阶段时长中较晚者，
+// 期间的下一次结算会重新调度；只设 Min 的房间也会在 Min 到期后入夜。
+// 入夜或游戏结束时取消 Max 计时。判定逻辑在 engine (CanEndDayEarly / DayMaxRemaining)。
+//
+// [IN]  internal/engine（GameConfig 上下限与时间判定）
+// [IN]  internal/store（StoredEvent）
+// [OUT] room.go（commit 在 scheduleTimeouts 之后调用）
+// [POS] 白天自动推进的时长边界
+package room
+
+import (
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// scheduleDiscussionBounds applies the day's min/max bounds to freshly persisted events.
+func (ra *RoomActor) scheduleDiscussionBounds(events []store.StoredEvent, state engine.State) {
+	if !state.Config.DiscussionBounded() {
+		return
+	}
+	for _, e := range events {
+		switch e.EventType {
+		case "phase.day":
+			if remaining, ok := state.DayMaxRemaining(time.Now()); ok && ra.dayMaxTimer != nil {
+				ra.dayMaxTimer.Schedule(remaining, "advance_phase", map[string]string{"phase": "night"})
+			}
+		case "nomination.resolved":
+			if state.Phase != engine.PhaseDay && state.Phase != engine.PhaseNomination {
+				continue
+			}
+			ra.phaseTimer.Schedule(nightAfterNomination(state), "advance_phase", map[string]string{"phase": "night"})
+		case "phase.night", "phase.first_night", "game.ended":
+			if ra.dayMaxTimer != nil {
+				ra.dayMaxTimer.Cancel()
+			}
+		}
+	}
+}
+
+// nightAfterNomination is when a resolved nomination moves the day to night: at once
+// after Min, otherwise at the later of Min and the nomination phase duration.
+func nightAfterNomination(state engine.State) time.Duration {
+	minLeft := state.DayMinRemaining(time.Now())
+	if minLeft == 0 {
+		return 0
+	}
+	return max(minLeft, time.Duration(state.Config.NominationPhaseDurationSec)*time.Second)
+}
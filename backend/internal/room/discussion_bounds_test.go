@@ -0,0 +1,86 @@
+package room
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func boundedDayState(dayStarted time.Time) engine.State {
+	state := engine.NewState("room-1")
+	state.Phase = engine.PhaseNomination
+	state.Config.MinDiscussionSec = 60
+	state.Config.MaxDiscussionSec = 600
+	state.DayStartedAt = dayStarted.UnixMilli()
+	return state
+}
+
+func TestNominationResolvedRespectsMinDiscussion(t *testing.T) {
+	tests := []struct {
+		name      string
+		dayAge    time.Duration
+		advancing bool
+	}{
+		{"before min stays in day", 10 * time.Second, false},
+		{"after min advances to night", 2 * time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ra := newIdleTestActor(t, newMemEventLog())
+			fired := make(chan types.CommandEnvelope, 1)
+			ra.phaseTimer = NewPhaseTimer(ra.RoomID, func(cmd types.CommandEnvelope) { fired <- cmd }, ra.logger)
+
+			events := []store.StoredEvent{{RoomID: ra.RoomID, EventType: "nomination.resolved"}}
+			ra.scheduleDiscussionBounds(events, boundedDayState(time.Now().Add(-tt.dayAge)))
+
+			select {
+			case cmd := <-fired:
+				if !tt.advancing {
+					t.Fatalf("unexpected %s before min discussion", cmd.Type)
+				}
+				var data map[string]string
+				_ = json.Unmarshal(cmd.Payload, &data)
+				if cmd.Type != "advance_phase" || data["phase"] != "night" {
+					t.Fatalf("got %s %v, want advance_phase to night", cmd.Type, data)
+				}
+			case <-time.After(200 * time.Millisecond):
+				if tt.advancing {
+					t.Fatal("expected advance to night after min discussion")
+				}
+			}
+		})
+	}
+}
+
+func TestNominationResolvedBeforeMinAdvancesOnceMinPasses(t *testing.T) {
+	ra := newIdleTestActor(t, newMemEventLog())
+	fired := make(chan types.CommandEnvelope, 1)
+	ra.phaseTimer = NewPhaseTimer(ra.RoomID, func(cmd types.CommandEnvelope) { fired <- cmd }, ra.logger)
+
+	// Min only: no max and no nomination phase timer to fall back on.
+	state := engine.NewState("room-1")
+	state.Phase = engine.PhaseNomination
+	state.Config.MinDiscussionSec = 1
+	state.DayStartedAt = time.Now().Add(-700 * time.Millisecond).UnixMilli()
+
+	events := []store.StoredEvent{{RoomID: ra.RoomID, EventType: "nomination.resolved"}}
+	ra.scheduleDiscussionBounds(events, state)
+
+	select {
+	case cmd := <-fired:
+		t.Fatalf("unexpected %s before min discussion", cmd.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+	select {
+	case cmd := <-fired:
+		if cmd.Type != "advance_phase" {
+			t.Fatalf("got %s, want advance_phase", cmd.Type)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the day to end once min discussion passed")
+	}
+}
@@ -0,0 +1,37 @@
+// Package room Auto-DM 调整后的白天讨论截止时间
+//
+// Auto-DM 按整局节奏缩短或延长讨论时，以 set_timer 发出 timer.set (timer_type=discussion，
+// deadline 为毫秒时间戳)。白天讨论中收到时把讨论→提名的自动推进改到该截止时间，
+// 其他计时类型或不在讨论中时忽略；截止时间已过则立即推进。
+//
+// [IN]  internal/store（StoredEvent）
+// [OUT] room_timers.go（scheduleTimeouts 的 timer.set 分支）
+// [POS] 讨论自动推进的节奏覆盖
+package room
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// scheduleDiscussionDeadline moves the discussion → nomination advance to a timer.set deadline.
+func (ra *RoomActor) scheduleDiscussionDeadline(e store.StoredEvent) {
+	var payload map[string]string
+	_ = json.Unmarshal([]byte(e.PayloadJSON), &payload)
+	if payload["timer_type"] != "discussion" {
+		return
+	}
+	deadline, err := strconv.ParseInt(payload["deadline"], 10, 64)
+	if err != nil {
+		return
+	}
+	if ra.state.Phase != engine.PhaseDay || ra.state.Nomination != nil {
+		return
+	}
+	dur := max(time.Until(time.UnixMilli(deadline)), 0)
+	ra.phaseTimer.Schedule(dur, "advance_phase", map[string]string{"phase": "nomination"})
+}
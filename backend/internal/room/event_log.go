@@ -0,0 +1,87 @@
+// Package room 房间事件日志接口、序号分配与关联 ID
+//
+// RoomActor 的命令循环是房间事件的唯一写入者：序号在循环内按 LastSeq 连续分配，
+// 存储层以首个事件的序号作为期望的下一个序号再校验一次，发现并发写入（ErrSeqConflict）时
+// Actor 从存储重载状态，并在重载后的状态上重新执行该命令 (最多 maxSeqConflictRetries 次)。
+// 同一命令产生的所有事件带相同的 correlation_id，便于追踪（如 start_game 的整组事件）。
+// 作为唯一写入者，Actor 同时用 store.ChainEvents 接续上一事件的 Hash 计算哈希链，
+// 追加成功后才推进链头；加载状态时从存储读取链头。
+//
+// [IN]  internal/store（StoredEvent、DedupRecord、Snapshot、ErrSeqConflict、LastEventHash）
+// [IN]  internal/types（CommandEnvelope）
+// [OUT] room.go（RoomActor 持久化与状态加载）
+// [POS] Actor 与存储层之间的持久化边界
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// eventLog is the subset of *store.Store a RoomActor persists through.
+type eventLog interface {
+	GetLatestSnapshot(ctx context.Context, roomID string) (*store.Snapshot, error)
+	LoadEventsAfter(ctx context.Context, roomID string, afterSeq int64, limit int) ([]store.StoredEvent, error)
+	LoadEventsUpTo(ctx context.Context, roomID string, toSeq int64) ([]store.StoredEvent, error)
+	GetDedupRecord(ctx context.Context, roomID, actorUserID, idempotencyKey, commandType string) (*store.DedupRecord, error)
+	AppendEvents(ctx context.Context, roomID string, events []store.StoredEvent, dedup *store.DedupRecord, snap *store.Snapshot) error
+	LastEventHash(ctx context.Context, roomID string) (string, error)
+}
+
+// commandCorrelationID returns the id stamped on every event of cmd, generating one if the
+// caller did not supply it.
+func commandCorrelationID(cmd types.CommandEnvelope) string {
+	if cmd.CorrelationID != "" {
+		return cmd.CorrelationID
+	}
+	return uuid.NewString()
+}
+
+// assignSeqs numbers events contiguously after lastSeq.
+func assignSeqs(events []store.StoredEvent, lastSeq int64) {
+	for i := range events {
+		events[i].Seq = lastSeq + int64(i+1)
+	}
+}
+
+// loadChainHead restores the hash the next appended event chains from.
+func (ra *RoomActor) loadChainHead(ctx context.Context) error {
+	hash, err := ra.store.LastEventHash(ctx, ra.RoomID)
+	if err != nil {
+		return fmt.Errorf("room.loadChainHead: %w", err)
+	}
+	ra.lastHash = hash
+	return nil
+}
+
+// maxSeqConflictRetries bounds how often a command is re-run after another writer appended first.
+const maxSeqConflictRetries = 2
+
+// handleCommandWithRetry re-runs cmd against the reloaded state when its append lost a seq race.
+func (ra *RoomActor) handleCommandWithRetry(ctx context.Context, cmd types.CommandEnvelope) (*types.CommandResult, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := ra.handleCommand(ctx, cmd)
+		if err == nil || !errors.Is(err, store.ErrSeqConflict) || attempt >= maxSeqConflictRetries {
+			return result, err
+		}
+	}
+}
+
+// appendEvents persists events and, if another writer got there first, reloads state
+// so the next command is numbered from the stored sequence.
+func (ra *RoomActor) appendEvents(ctx context.Context, events []store.StoredEvent, dedup *store.DedupRecord, snap *store.Snapshot) error {
+	err := ra.store.AppendEvents(ctx, ra.RoomID, events, dedup, snap)
+	if err == nil || !errors.Is(err, store.ErrSeqConflict) {
+		return err
+	}
+	if reloadErr := ra.loadState(ctx); reloadErr != nil {
+		return fmt.Errorf("room.appendEvents: reload after %v: %w", err, reloadErr)
+	}
+	return fmt.Errorf("room.appendEvents: %w", err)
+}
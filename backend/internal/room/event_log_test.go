@@ -0,0 +1,317 @@
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/observability"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// memEventLog mirrors the store's sequence rules: appends must continue next_seq
+// and (room_id, seq) is unique.
+type memEventLog struct {
+	mu      sync.Mutex
+	nextSeq int64
+	events  []store.StoredEvent
+	seen    map[int64]bool
+}
+
+func newMemEventLog() *memEventLog {
+	return &memEventLog{nextSeq: 1, seen: make(map[int64]bool)}
+}
+
+func (m *memEventLog) GetLatestSnapshot(context.Context, string) (*store.Snapshot, error) {
+	return nil, nil
+}
+
+func (m *memEventLog) LoadEventsAfter(_ context.Context, _ string, afterSeq int64, _ int) ([]store.StoredEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var res []store.StoredEvent
+	for _, e := range m.events {
+		if e.Seq > afterSeq {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+func (m *memEventLog) LoadEventsUpTo(ctx context.Context, roomID string, toSeq int64) ([]store.StoredEvent, error) {
+	events, err := m.LoadEventsAfter(ctx, roomID, 0, 0)
+	if toSeq <= 0 {
+		return events, err
+	}
+	var res []store.StoredEvent
+	for _, e := range events {
+		if e.Seq <= toSeq {
+			res = append(res, e)
+		}
+	}
+	return res, err
+}
+
+func (m *memEventLog) LastEventHash(context.Context, string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.events) == 0 {
+		return "", nil
+	}
+	return m.events[len(m.events)-1].Hash, nil
+}
+
+func (m *memEventLog) GetDedupRecord(context.Context, string, string, string, string) (*store.DedupRecord, error) {
+	return nil, nil
+}
+
+func (m *memEventLog) AppendEvents(_ context.Context, _ string, events []store.StoredEvent, _ *store.DedupRecord, _ *store.Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(events) > 0 && events[0].Seq != m.nextSeq {
+		return fmt.Errorf("expected seq %d, got %d: %w", m.nextSeq, events[0].Seq, store.ErrSeqConflict)
+	}
+	for _, e := range events {
+		if m.seen[e.Seq] {
+			return fmt.Errorf("duplicate seq %d: %w", e.Seq, store.ErrSeqConflict)
+		}
+	}
+	for _, e := range events {
+		m.seen[e.Seq] = true
+		m.events = append(m.events, e)
+	}
+	m.nextSeq += int64(len(events))
+	return nil
+}
+
+func newTestActor(t *testing.T, log eventLog) *RoomActor {
+	t.Helper()
+	ra := newIdleTestActor(t, log)
+	go ra.loop(ra.ctx)
+	return ra
+}
+
+// newIdleTestActor builds a test actor whose command loop has not started yet.
+func newIdleTestActor(t *testing.T, log eventLog) *RoomActor {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	ra := &RoomActor{
+		RoomID:  "room-1",
+		ctx:     ctx,
+		store:   log,
+		logger:  zap.NewNop(),
+		metrics: observability.NewMetrics(prometheus.NewRegistry()),
+		cmdCh:   make(chan CommandRequest, 256),
+		subs:    make(map[string]*Subscriber),
+		state:   engine.NewState("room-1"),
+	}
+	ra.phaseTimer = NewPhaseTimer(ra.RoomID, func(types.CommandEnvelope) {}, ra.logger)
+	return ra
+}
+
+func chatCommand(i int) types.CommandEnvelope {
+	payload, _ := json.Marshal(map[string]string{"message": fmt.Sprintf("msg %d", i)})
+	return types.CommandEnvelope{
+		CommandID:      fmt.Sprintf("cmd-%d", i),
+		IdempotencyKey: fmt.Sprintf("key-%d", i),
+		RoomID:         "room-1",
+		Type:           "public_chat",
+		ActorUserID:    fmt.Sprintf("user-%d", i),
+		Payload:        payload,
+	}
+}
+
+func TestConcurrentCommandsGetGapFreeSeqs(t *testing.T) {
+	log := newMemEventLog()
+	ra := newTestActor(t, log)
+
+	const n = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if resp := ra.Dispatch(chatCommand(i)); resp.Err != nil {
+				errs <- resp.Err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+
+	if len(log.events) != n {
+		t.Fatalf("expected %d events, got %d", n, len(log.events))
+	}
+	for i, e := range log.events {
+		if e.Seq != int64(i+1) {
+			t.Fatalf("event %d has seq %d, want %d", i, e.Seq, i+1)
+		}
+	}
+	if got := ra.GetState().LastSeq; got != n {
+		t.Fatalf("expected state LastSeq %d, got %d", n, got)
+	}
+}
+
+func TestStaleActorRetriesAfterSeqConflict(t *testing.T) {
+	log := newMemEventLog()
+	ra := newTestActor(t, log)
+
+	// Another writer appends seq 1 behind the actor's back.
+	other := []store.StoredEvent{{RoomID: "room-1", Seq: 1, EventID: "e-other", EventType: "public.chat", PayloadJSON: `{}`}}
+	if err := log.AppendEvents(context.Background(), "room-1", other, nil, nil); err != nil {
+		t.Fatalf("seed append: %v", err)
+	}
+
+	// The actor's first append (seq 1) is rejected by the log; it reloads and re-runs the command.
+	resp := ra.Dispatch(chatCommand(1))
+	if resp.Err != nil {
+		t.Fatalf("expected the command to succeed after reload and retry: %v", resp.Err)
+	}
+	if resp.Result.AppliedSeqFrom != 2 {
+		t.Fatalf("expected seq 2 after reload, got %d", resp.Result.AppliedSeqFrom)
+	}
+	if len(log.events) != 2 || log.events[1].Seq != 2 {
+		t.Fatalf("expected the retried command persisted once at seq 2, got %+v", log.events)
+	}
+}
+
+// contendedLog loses every append to another writer.
+type contendedLog struct {
+	*memEventLog
+	appends int
+}
+
+func (c *contendedLog) AppendEvents(context.Context, string, []store.StoredEvent, *store.DedupRecord, *store.Snapshot) error {
+	c.appends++
+	return fmt.Errorf("stale writer: %w", store.ErrSeqConflict)
+}
+
+func TestSeqConflictRetriesAreBounded(t *testing.T) {
+	log := &contendedLog{memEventLog: newMemEventLog()}
+	ra := newTestActor(t, log)
+
+	resp := ra.Dispatch(chatCommand(1))
+	if !errors.Is(resp.Err, store.ErrSeqConflict) {
+		t.Fatalf("expected a persistent conflict to surface ErrSeqConflict, got %v", resp.Err)
+	}
+	if log.appends != maxSeqConflictRetries+1 {
+		t.Fatalf("expected %d append attempts, got %d", maxSeqConflictRetries+1, log.appends)
+	}
+}
+
+func TestStartGameEventsShareCorrelationID(t *testing.T) {
+	log := newMemEventLog()
+	ra := newTestActor(t, log)
+
+	for i := 0; i < 5; i++ {
+		join := types.CommandEnvelope{
+			CommandID:      fmt.Sprintf("join-%d", i),
+			IdempotencyKey: fmt.Sprintf("join-%d", i),
+			RoomID:         "room-1",
+			Type:           "join",
+			ActorUserID:    fmt.Sprintf("user-%d", i),
+		}
+		if resp := ra.Dispatch(join); resp.Err != nil {
+			t.Fatalf("join %d: %v", i, resp.Err)
+		}
+	}
+	start := types.CommandEnvelope{CommandID: "start", IdempotencyKey: "start", RoomID: "room-1", Type: "start_game", ActorUserID: "user-0"}
+	if resp := ra.Dispatch(start); resp.Err != nil {
+		t.Fatalf("start_game: %v", resp.Err)
+	}
+
+	correlation := ""
+	count := 0
+	for _, e := range log.events {
+		if e.CausationCommand != "start" {
+			continue
+		}
+		count++
+		if correlation == "" {
+			correlation = e.CorrelationID
+		}
+		if e.CorrelationID == "" || e.CorrelationID != correlation {
+			t.Fatalf("event %s has correlation %q, want %q", e.EventType, e.CorrelationID, correlation)
+		}
+	}
+	if count < 2 {
+		t.Fatalf("expected start_game to emit several events, got %d", count)
+	}
+	if log.events[0].CorrelationID == correlation {
+		t.Fatal("expected join and start_game to have different correlation ids")
+	}
+}
+
+func TestSuppliedCorrelationIDIsKept(t *testing.T) {
+	log := newMemEventLog()
+	ra := newTestActor(t, log)
+
+	cmd := chatCommand(1)
+	cmd.CorrelationID = "trace-42"
+	if resp := ra.Dispatch(cmd); resp.Err != nil {
+		t.Fatalf("dispatch: %v", resp.Err)
+	}
+	if got := log.events[0].CorrelationID; got != "trace-42" {
+		t.Fatalf("expected supplied correlation id, got %q", got)
+	}
+}
+
+func TestUndoLastEventRebuildsStateWithoutRetractedJoin(t *testing.T) {
+	log := newMemEventLog()
+	ra := newTestActor(t, log)
+
+	for i := 0; i < 2; i++ {
+		join := types.CommandEnvelope{CommandID: fmt.Sprintf("join-%d", i), IdempotencyKey: fmt.Sprintf("join-%d", i), RoomID: "room-1", Type: "join", ActorUserID: fmt.Sprintf("user-%d", i)}
+		if resp := ra.Dispatch(join); resp.Err != nil {
+			t.Fatalf("join %d: %v", i, resp.Err)
+		}
+	}
+	undo := types.CommandEnvelope{CommandID: "undo", IdempotencyKey: "undo", RoomID: "room-1", Type: "undo_last_event", ActorUserID: "autodm"}
+	if resp := ra.Dispatch(undo); resp.Err != nil {
+		t.Fatalf("undo: %v", resp.Err)
+	}
+
+	state := ra.GetState()
+	if _, ok := state.Players["user-1"]; ok {
+		t.Fatal("expected retracted join to be removed from state")
+	}
+	if _, ok := state.Players["user-0"]; !ok || state.LastSeq != 3 {
+		t.Fatalf("expected user-0 to remain at seq 3, got players=%v seq=%d", state.Players, state.LastSeq)
+	}
+}
+
+func TestActorChainsEventHashes(t *testing.T) {
+	log := newMemEventLog()
+	ra := newTestActor(t, log)
+	for i := 0; i < 3; i++ {
+		if resp := ra.Dispatch(chatCommand(i)); resp.Err != nil {
+			t.Fatalf("dispatch %d: %v", i, resp.Err)
+		}
+	}
+
+	events, _ := log.LoadEventsAfter(context.Background(), "room-1", 0, 0)
+	if len(events) != 3 || events[0].PrevHash != "" {
+		t.Fatalf("expected 3 events chained from an empty head, got %+v", events)
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].PrevHash != events[i-1].Hash {
+			t.Fatalf("seq %d does not chain from seq %d", events[i].Seq, events[i-1].Seq)
+		}
+	}
+	if broken := store.VerifyChain(events); len(broken) != 0 {
+		t.Fatalf("expected intact chain, broken at %v", broken)
+	}
+}
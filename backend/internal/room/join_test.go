@@ -0,0 +1,43 @@
+package room
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestJoiningTwiceSeatsOnePlayer(t *testing.T) {
+	log := newMemEventLog()
+	ra := newTestActor(t, log)
+
+	for i := 0; i < 2; i++ {
+		join := types.CommandEnvelope{CommandID: fmt.Sprintf("join-%d", i), IdempotencyKey: fmt.Sprintf("join-%d", i), RoomID: "room-1", Type: "join", ActorUserID: "user-0"}
+		if resp := ra.Dispatch(join); resp.Err != nil {
+			t.Fatalf("join %d: %v", i, resp.Err)
+		}
+	}
+
+	state := ra.GetState()
+	if len(state.Players) != 1 || len(state.SeatOrder) != 1 {
+		t.Fatalf("expected one seated player, got players=%v seats=%v", state.Players, state.SeatOrder)
+	}
+	if len(log.events) != 1 || log.events[0].EventType != "player.joined" {
+		t.Fatalf("expected a single player.joined event, got %d events", len(log.events))
+	}
+}
+
+func TestHasSubscriberUntilLastSessionLeaves(t *testing.T) {
+	ra := newIdleTestActor(t, newMemEventLog())
+	ra.Subscribe("tab-1", &Subscriber{UserID: "user-0", Send: func(types.ProjectedEvent) {}})
+	ra.Subscribe("tab-2", &Subscriber{UserID: "user-0", Send: func(types.ProjectedEvent) {}})
+
+	ra.Unsubscribe("tab-1")
+	if !ra.HasSubscriber("user-0") {
+		t.Fatal("expected the second session to keep the user subscribed")
+	}
+	ra.Unsubscribe("tab-2")
+	if ra.HasSubscriber("user-0") {
+		t.Fatal("expected no subscription after the last session left")
+	}
+}
@@ -0,0 +1,55 @@
+// Package room 夜晚单个行动计时器
+//
+// 每当 night.action.prompt 提示某位玩家行动时重新计时；到期仍未行动则以 autodm 身份
+// 发送 night_action_timeout，引擎按角色代选目标并标记 reason=timeout。
+// 天亮或游戏结束时取消。NightActionTimeout 为 0 时关闭。
+//
+// [IN]  internal/engine（State 夜晚行动队列）
+// [IN]  internal/store（StoredEvent）
+// [OUT] room.go（commit 在广播后调度、重启后恢复）
+// [POS] 夜晚行动顺序的掉线兜底计时
+package room
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// scheduleNightActionTimeout restarts the action timer on each prompt and stops it at dawn.
+func (ra *RoomActor) scheduleNightActionTimeout(events []store.StoredEvent) {
+	if ra.nightActionTimer == nil || ra.nightActionTimeout <= 0 {
+		return
+	}
+	for _, e := range events {
+		switch e.EventType {
+		case "night.action.prompt":
+			var payload map[string]string
+			_ = json.Unmarshal([]byte(e.PayloadJSON), &payload)
+			ra.nightActionTimer.Schedule(ra.nightActionTimeout, "night_action_timeout", map[string]string{
+				"user_id": payload["user_id"],
+				"order":   payload["order"],
+			})
+		case "phase.day", "game.ended":
+			ra.nightActionTimer.Cancel()
+		}
+	}
+}
+
+// recoverNightActionTimeout re-arms the timer for the pending action after a restart.
+func (ra *RoomActor) recoverNightActionTimeout() {
+	if ra.nightActionTimer == nil || ra.nightActionTimeout <= 0 {
+		return
+	}
+	if ra.state.Phase != engine.PhaseNight && ra.state.Phase != engine.PhaseFirstNight {
+		return
+	}
+	if a, ok := ra.state.NextPendingNightAction(); ok {
+		ra.nightActionTimer.Schedule(ra.nightActionTimeout, "night_action_timeout", map[string]string{
+			"user_id": a.UserID,
+			"order":   strconv.Itoa(a.Order),
+		})
+	}
+}
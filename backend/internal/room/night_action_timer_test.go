@@ -0,0 +1,61 @@
+package room
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestStalledNightActionAutoCompletesAfterTimeout(t *testing.T) {
+	log := newMemEventLog()
+	ra := newTestActor(t, log)
+	ra.nightActionTimeout = 20 * time.Millisecond
+	ra.nightActionTimer = NewPhaseTimer(ra.RoomID, func(cmd types.CommandEnvelope) { ra.Dispatch(cmd) }, ra.logger)
+
+	ra.stateMu.Lock()
+	ra.state.Phase = engine.PhaseNight
+	ra.state.NightCount = 2
+	ra.state.DemonID = "imp"
+	for i, uid := range []string{"monk", "imp", "chef"} {
+		team := "good"
+		if uid == "imp" {
+			team = "evil"
+		}
+		ra.state.Players[uid] = engine.Player{UserID: uid, TrueRole: uid, Team: team, Alive: true, SeatNumber: i + 1}
+		ra.state.SeatOrder = append(ra.state.SeatOrder, uid)
+	}
+	ra.state.NightActions = []engine.NightAction{
+		{UserID: "monk", RoleID: "monk", Order: 1, ActionType: "select_one"},
+		{UserID: "imp", RoleID: "imp", Order: 2, ActionType: "select_one"},
+	}
+	ra.stateMu.Unlock()
+	ra.recoverNightActionTimeout()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for ra.GetState().Phase == engine.PhaseNight {
+		if time.Now().After(deadline) {
+			t.Fatalf("night did not resolve; phase=%s", ra.GetState().Phase)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	timedOut := map[string]bool{}
+	for _, e := range log.events {
+		if e.EventType != "night.action.completed" {
+			continue
+		}
+		var payload map[string]string
+		_ = json.Unmarshal([]byte(e.PayloadJSON), &payload)
+		if payload["reason"] == "timeout" {
+			timedOut[payload["user_id"]] = true
+		}
+	}
+	if !timedOut["monk"] || !timedOut["imp"] {
+		t.Fatalf("expected both stalled actions to time out, got %v", timedOut)
+	}
+}
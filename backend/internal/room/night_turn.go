@@ -0,0 +1,22 @@
+// Package room 夜晚轮次通知
+//
+// 引擎产出 night.action.completed 后，由 Actor 追加 night.turn 事件，
+// 与本次命令的事件一起分配序号、持久化并广播（投影层仅对行动者可见）。
+//
+// [IN]  internal/engine（NightTurnEvent）
+// [OUT] room.go（handleCommand 在分配序号前调用）
+// [POS] 夜晚行动顺序的玩家侧提示
+package room
+
+import (
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+// withNightTurn appends a night.turn for the next actor when a night action just completed.
+func withNightTurn(state engine.State, cmd types.CommandEnvelope, events []types.Event) []types.Event {
+	if ev, ok := engine.NightTurnEvent(state, cmd, events); ok {
+		return append(events, ev)
+	}
+	return events
+}
@@ -0,0 +1,56 @@
+package room
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func TestCompletedNightActionEmitsNightTurnForNextActor(t *testing.T) {
+	log := newMemEventLog()
+	ra := newTestActor(t, log)
+
+	ra.stateMu.Lock()
+	ra.state.Phase = engine.PhaseNight
+	ra.state.NightCount = 2
+	ra.state.DemonID = "imp"
+	for i, uid := range []string{"monk", "imp", "chef"} {
+		team := "good"
+		if uid == "imp" {
+			team = "evil"
+		}
+		ra.state.Players[uid] = engine.Player{UserID: uid, TrueRole: uid, Team: team, Alive: true, SeatNumber: i + 1}
+		ra.state.SeatOrder = append(ra.state.SeatOrder, uid)
+	}
+	ra.state.NightActions = []engine.NightAction{
+		{UserID: "monk", RoleID: "monk", Order: 1, ActionType: "select_one"},
+		{UserID: "imp", RoleID: "imp", Order: 2, ActionType: "select_one"},
+	}
+	ra.stateMu.Unlock()
+
+	payload, _ := json.Marshal(map[string]string{"targets": `["chef"]`})
+	resp := ra.Dispatch(types.CommandEnvelope{
+		CommandID: "cmd-monk", RoomID: "room-1", Type: "ability.use",
+		ActorUserID: "monk", IdempotencyKey: "monk-1", Payload: payload,
+	})
+	if resp.Err != nil {
+		t.Fatalf("monk ability failed: %v", resp.Err)
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	for _, e := range log.events {
+		if e.EventType != "night.turn" {
+			continue
+		}
+		var p map[string]string
+		_ = json.Unmarshal([]byte(e.PayloadJSON), &p)
+		if p["user_id"] != "imp" {
+			t.Fatalf("expected night.turn for imp, got %v", p)
+		}
+		return
+	}
+	t.Fatal("expected a persisted night.turn event")
+}
@@ -0,0 +1,149 @@
+// Package room 事件保留期后台清理任务
+//
+// 按间隔扫描结束时间早于保留期的房间，逐房间 (单事务) 删除事件与快照。
+// 清理前先驱逐房间仍在内存中的 Actor (RoomManager.Evict)，避免其按旧序号与链头继续写入；
+// 下次访问时从清理后的存储重新加载。存储层在同一事务内重置房间序号与哈希链头。
+// KeepFinalSnapshot 时先用 engine.Replay 重建终局状态 (含魔典)，随清理一并写入作为归档，
+// 之后 loadState 仍可从该快照恢复房间并接续序号与哈希链。清理行数计入 event_retention_purged_rows_total。
+//
+// [IN]  internal/engine（Replay、MarshalState）
+// [IN]  internal/store（ListEndedRoomsBefore、LoadEventsUpTo、PurgeRoom）
+// [IN]  room_manager.go（RoomManager.Evict）
+// [OUT] cmd/server（EVENT_RETENTION_DAYS > 0 时启动）
+// [POS] 事件存储的保留期管理
+package room
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/observability"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// retentionBatchSize caps how many rooms one purge pass handles.
+const retentionBatchSize = 100
+
+// retentionStore is the subset of *store.Store the purge job uses.
+type retentionStore interface {
+	ListEndedRoomsBefore(ctx context.Context, cutoff time.Time, limit int) ([]string, error)
+	LoadEventsUpTo(ctx context.Context, roomID string, toSeq int64) ([]store.StoredEvent, error)
+	PurgeRoom(ctx context.Context, roomID string, final *store.Snapshot) (int64, error)
+}
+
+// actorEvicter drops a room's live actor (implemented by *RoomManager).
+type actorEvicter interface {
+	Evict(roomID string)
+}
+
+// RetentionConfig controls which ended rooms are purged and what is kept.
+type RetentionConfig struct {
+	Retention         time.Duration // rooms ended longer ago than this are purged
+	KeepFinalSnapshot bool          // archive the replayed end-of-game state
+}
+
+// RetentionPurger deletes the event history of long-ended rooms.
+type RetentionPurger struct {
+	store   retentionStore
+	rooms   actorEvicter
+	cfg     RetentionConfig
+	metrics *observability.Metrics
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+// NewRetentionPurger creates a purger; rooms and metrics may be nil.
+func NewRetentionPurger(st retentionStore, rooms actorEvicter, cfg RetentionConfig, metrics *observability.Metrics, logger *zap.Logger) *RetentionPurger {
+	return &RetentionPurger{store: st, rooms: rooms, cfg: cfg, metrics: metrics, logger: logger, now: time.Now}
+}
+
+// Run purges once per interval (default hourly) until ctx is cancelled.
+func (p *RetentionPurger) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := p.PurgeOnce(ctx); err != nil {
+			p.logger.Warn("event retention purge failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce purges one batch of expired rooms and returns the deleted row count.
+// A failing room is logged and skipped so one bad room cannot stall the rest.
+func (p *RetentionPurger) PurgeOnce(ctx context.Context) (int64, error) {
+	cutoff := p.now().UTC().Add(-p.cfg.Retention)
+	roomIDs, err := p.store.ListEndedRoomsBefore(ctx, cutoff, retentionBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("room.PurgeOnce: %w", err)
+	}
+	var total int64
+	for _, roomID := range roomIDs {
+		n, err := p.purgeRoom(ctx, roomID)
+		if err != nil {
+			p.logger.Warn("purge room failed", zap.String("room_id", roomID), zap.Error(err))
+			continue
+		}
+		total += n
+		if p.metrics != nil {
+			p.metrics.EventsPurged.Add(float64(n))
+		}
+	}
+	if total > 0 {
+		p.logger.Info("event retention purge", zap.Int("rooms", len(roomIDs)), zap.Int64("rows", total))
+	}
+	return total, nil
+}
+
+func (p *RetentionPurger) purgeRoom(ctx context.Context, roomID string) (int64, error) {
+	if p.rooms != nil {
+		p.rooms.Evict(roomID)
+	}
+	var final *store.Snapshot
+	if p.cfg.KeepFinalSnapshot {
+		snap, err := p.finalSnapshot(ctx, roomID)
+		if err != nil {
+			return 0, err
+		}
+		final = snap
+	}
+	return p.store.PurgeRoom(ctx, roomID, final)
+}
+
+// finalSnapshot replays the full log into the end-of-game state.
+func (p *RetentionPurger) finalSnapshot(ctx context.Context, roomID string) (*store.Snapshot, error) {
+	stored, err := p.store.LoadEventsUpTo(ctx, roomID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("room.finalSnapshot: %w", err)
+	}
+	if len(stored) == 0 {
+		return nil, nil
+	}
+	payloads := make([]engine.EventPayload, 0, len(stored))
+	for _, e := range stored {
+		payloads = append(payloads, toEventPayload(e))
+	}
+	final := engine.Replay(roomID, payloads)
+	stateJSON, err := engine.MarshalState(final)
+	if err != nil {
+		return nil, fmt.Errorf("room.finalSnapshot: %w", err)
+	}
+	return &store.Snapshot{
+		RoomID:    roomID,
+		LastSeq:   stored[len(stored)-1].Seq,
+		Phase:     string(final.Phase),
+		StateJSON: stateJSON,
+		CreatedAt: p.now().UTC(),
+	}, nil
+}
@@ -0,0 +1,126 @@
+package room
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/observability"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// memRetentionStore mirrors store.PurgeRoom: a purge drops every event and snapshot
+// of the room and keeps only the supplied final snapshot.
+type memRetentionStore struct {
+	events    map[string][]store.StoredEvent
+	snapshots map[string][]store.Snapshot
+}
+
+func (m *memRetentionStore) ListEndedRoomsBefore(_ context.Context, cutoff time.Time, _ int) ([]string, error) {
+	var ids []string
+	for roomID, events := range m.events {
+		for _, e := range events {
+			if e.EventType == "game.ended" && e.ServerTime.Before(cutoff) {
+				ids = append(ids, roomID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (m *memRetentionStore) LoadEventsUpTo(_ context.Context, roomID string, _ int64) ([]store.StoredEvent, error) {
+	return m.events[roomID], nil
+}
+
+func (m *memRetentionStore) PurgeRoom(_ context.Context, roomID string, final *store.Snapshot) (int64, error) {
+	n := int64(len(m.events[roomID]) + len(m.snapshots[roomID]))
+	delete(m.events, roomID)
+	delete(m.snapshots, roomID)
+	if final != nil {
+		m.snapshots[roomID] = []store.Snapshot{*final}
+	}
+	return n, nil
+}
+
+// orderedEvicter records which rooms still had their events when evicted.
+type orderedEvicter struct {
+	st      *memRetentionStore
+	evicted map[string]bool // room id -> evicted before its purge
+}
+
+func (e *orderedEvicter) Evict(roomID string) {
+	e.evicted[roomID] = len(e.st.events[roomID]) > 0
+}
+
+func endedRoomEvents(roomID string, endedAt time.Time) []store.StoredEvent {
+	types := []string{"player.joined", "game.started", "game.ended"}
+	events := make([]store.StoredEvent, len(types))
+	for i, eventType := range types {
+		events[i] = store.StoredEvent{
+			RoomID:      roomID,
+			Seq:         int64(i + 1),
+			EventID:     roomID + "-" + eventType,
+			EventType:   eventType,
+			ActorUserID: "user-1",
+			PayloadJSON: `{"user_id":"user-1","winner":"good"}`,
+			ServerTime:  endedAt,
+		}
+	}
+	return events
+}
+
+func TestRetentionPurgesOldEndedRoomAndKeepsFreshOne(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	st := &memRetentionStore{
+		events: map[string][]store.StoredEvent{
+			"old-room":   endedRoomEvents("old-room", now.Add(-40*24*time.Hour)),
+			"fresh-room": endedRoomEvents("fresh-room", now.Add(-time.Hour)),
+		},
+		snapshots: map[string][]store.Snapshot{
+			"old-room": {{RoomID: "old-room", LastSeq: 2, StateJSON: `{}`}},
+		},
+	}
+	metrics := observability.NewMetrics(prometheus.NewRegistry())
+	rooms := &orderedEvicter{st: st, evicted: map[string]bool{}}
+	purger := NewRetentionPurger(st, rooms, RetentionConfig{Retention: 30 * 24 * time.Hour, KeepFinalSnapshot: true}, metrics, zap.NewNop())
+	purger.now = func() time.Time { return now }
+
+	purged, err := purger.PurgeOnce(context.Background())
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if purged != 4 {
+		t.Fatalf("expected 3 events + 1 snapshot purged, got %d", purged)
+	}
+	var m dto.Metric
+	if err := metrics.EventsPurged.Write(&m); err != nil || m.GetCounter().GetValue() != 4 {
+		t.Fatalf("expected purged metric 4, got %v (%v)", m.GetCounter().GetValue(), err)
+	}
+	if len(st.events["old-room"]) != 0 {
+		t.Fatalf("old room events should be purged, got %d", len(st.events["old-room"]))
+	}
+	if len(st.events["fresh-room"]) != 3 {
+		t.Fatalf("fresh room events should be retained, got %d", len(st.events["fresh-room"]))
+	}
+	if len(rooms.evicted) != 1 || !rooms.evicted["old-room"] {
+		t.Fatalf("expected only the old room's actor evicted, before its purge: %v", rooms.evicted)
+	}
+
+	snaps := st.snapshots["old-room"]
+	if len(snaps) != 1 || snaps[0].LastSeq != 3 {
+		t.Fatalf("expected one final snapshot at seq 3, got %+v", snaps)
+	}
+	state, err := engine.UnmarshalState(snaps[0].StateJSON)
+	if err != nil {
+		t.Fatalf("unmarshal final snapshot: %v", err)
+	}
+	if state.LastSeq != 3 {
+		t.Fatalf("final snapshot should cover the whole log, got last_seq %d", state.LastSeq)
+	}
+}
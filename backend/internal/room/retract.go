@@ -0,0 +1,46 @@
+// Package room 事件撤回后的状态重建
+//
+// undo_last_event 产生 event.retracted 后，增量 Reduce 无法撤销被撤回事件的效果，
+// 因此 Actor 从存储加载全部事件加上本次新事件，用 engine.Replay 跳过被撤回事件重建状态，
+// 并强制写快照，使重启后的 loadState（快照 + 增量）与重放结果一致。
+//
+// [IN]  internal/engine（Replay）
+// [IN]  internal/store（StoredEvent）
+// [OUT] room.go（handleCommand 撤回分支）
+// [POS] 事件撤回的状态一致性保障
+package room
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// hasRetraction reports whether events contain an event.retracted.
+func hasRetraction(events []store.StoredEvent) bool {
+	for _, e := range events {
+		if e.EventType == "event.retracted" {
+			return true
+		}
+	}
+	return false
+}
+
+// replayWithRetraction rebuilds state from the stored log plus pending events, skipping
+// retracted ones. Server-injected settings (Config, DebugMode) carry over from current.
+func (ra *RoomActor) replayWithRetraction(ctx context.Context, current engine.State, pending []store.StoredEvent) (engine.State, error) {
+	stored, err := ra.store.LoadEventsUpTo(ctx, ra.RoomID, 0)
+	if err != nil {
+		return engine.State{}, fmt.Errorf("room.replayWithRetraction: %w", err)
+	}
+	payloads := make([]engine.EventPayload, 0, len(stored)+len(pending))
+	for _, e := range append(stored, pending...) {
+		payloads = append(payloads, toEventPayload(e))
+	}
+	state := engine.Replay(ra.RoomID, payloads)
+	state.Config = current.Config
+	state.DebugMode = current.DebugMode
+	return state, nil
+}
@@ -3,11 +3,10 @@
 // [IN]  internal/agent（Auto-DM 事件回调）
 // [IN]  internal/engine（HandleCommand 与 State）
 // [IN]  internal/observability（指标采集）
-// [IN]  internal/projection（广播前事件过滤）
 // [IN]  internal/store（事件持久化与快照）
 // [IN]  internal/types（命令与事件类型）
-// [OUT] api（RoomManager 路由命令）
-// [OUT] realtime（订阅/取消订阅与命令转发）
+// [OUT] room_manager.go（RoomManager 创建与路由命令）
+// [OUT] realtime（命令转发与状态读取）
 // [POS] 并发控制核心，每房间一个 Actor 消除竞态条件
 package room
 
@@ -25,7 +24,6 @@ import (
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/observability"
-	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/projection"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
 )
@@ -49,11 +47,12 @@ type Subscriber struct {
 type RoomActor struct {
 	RoomID      string
 	ctx         context.Context
+	cancel      context.CancelFunc // ends the loop when the manager evicts the actor
 	onCrash     func(roomID string)
 	subsMu      sync.RWMutex
 	stateMu     sync.RWMutex
 	state       engine.State
-	store       *store.Store
+	store       eventLog
 	logger      *zap.Logger
 	metrics     *observability.Metrics
 	cmdCh       chan CommandRequest
@@ -63,6 +62,22 @@ type RoomActor struct {
 	composer    game.Composer
 	phaseTimer  *PhaseTimer
 	botNotifier BotEventNotifier
+
+	nightActionTimer   *PhaseTimer
+	nightActionTimeout time.Duration
+	debugMode          bool
+
+	// lastHash is the Hash of the last persisted event; new events chain from it
+	lastHash string
+
+	// conflicts lets a human DM override contradictory Auto-DM commands (autodm_conflict.go)
+	conflicts autoDMConflicts
+
+	// dayMaxTimer ends the day at MaxDiscussionSec, independent of phaseTimer (discussion_bounds.go)
+	dayMaxTimer *PhaseTimer
+
+	// snapshotOnPhase also snapshots after every phase change (snapshot_policy.go)
+	snapshotOnPhase bool
 }
 
 func NewRoomActor(loadCtx context.Context, loopCtx context.Context, roomID string, deps RoomDeps, onCrash func(roomID string)) (*RoomActor, error) {
@@ -72,9 +87,11 @@ func NewRoomActor(loadCtx context.Context, loopCtx context.Context, roomID strin
 	if loadCtx == nil {
 		loadCtx = context.Background()
 	}
+	loopCtx, cancel := context.WithCancel(loopCtx)
 	ra := &RoomActor{
 		RoomID:      roomID,
 		ctx:         loopCtx,
+		cancel:      cancel,
 		onCrash:     onCrash,
 		store:       deps.Store,
 		logger:      deps.Logger,
@@ -85,75 +102,34 @@ func NewRoomActor(loadCtx context.Context, loopCtx context.Context, roomID strin
 		autoDM:      deps.AutoDM,
 		composer:    deps.Composer,
 		botNotifier: deps.BotNotifier,
+
+		nightActionTimeout: deps.NightActionTimeout,
+		debugMode:          deps.DebugCommands,
+
+		snapshotOnPhase: deps.SnapshotOnPhaseChange,
 	}
 	// PhaseTimer dispatches timeout commands through the actor's serial loop.
 	ra.phaseTimer = NewPhaseTimer(roomID, func(cmd types.CommandEnvelope) {
 		ra.Dispatch(cmd)
 	}, deps.Logger)
+	ra.nightActionTimer = NewPhaseTimer(roomID, func(cmd types.CommandEnvelope) {
+		ra.Dispatch(cmd)
+	}, deps.Logger)
+	ra.dayMaxTimer = NewPhaseTimer(roomID, func(cmd types.CommandEnvelope) {
+		ra.Dispatch(cmd)
+	}, deps.Logger)
 
 	if err := ra.loadState(loadCtx); err != nil {
+		cancel()
 		return nil, err
 	}
 	ra.recoverTimeoutFromState()
+	ra.recoverNightActionTimeout()
 
 	go ra.loop(loopCtx)
 	return ra, nil
 }
 
-// recoverTimeoutFromState re-schedules the appropriate phase timer
-// after loading persisted state (e.g., after server restart).
-// Night timeout is explicitly disabled in the current version, so
-// first_night / night phases are intentionally skipped here.
-func (ra *RoomActor) recoverTimeoutFromState() {
-	state := ra.state
-	if state.Phase == "" || state.Phase == engine.PhaseLobby || state.Phase == engine.PhaseEnded {
-		return
-	}
-	cfg := state.Config
-	switch state.Phase {
-	case engine.PhaseFirstNight, engine.PhaseNight:
-		return
-	case engine.PhaseDay:
-		switch state.SubPhase {
-		case engine.SubPhaseDefense:
-			if cfg.DefenseDurationSec <= 0 {
-				return
-			}
-			dur := time.Duration(cfg.DefenseDurationSec) * time.Second
-			ra.phaseTimer.Schedule(dur, "end_defense", nil)
-		case engine.SubPhaseVoting:
-			if cfg.VotingDurationSec <= 0 {
-				return
-			}
-			dur := time.Duration(cfg.VotingDurationSec) * time.Second * time.Duration(len(state.Players))
-			ra.phaseTimer.Schedule(dur, "close_vote", nil)
-		case engine.SubPhaseNominationOpen:
-			if cfg.NominationPhaseDurationSec <= 0 {
-				return
-			}
-			dur := time.Duration(cfg.NominationPhaseDurationSec) * time.Second
-			ra.phaseTimer.Schedule(dur, "advance_phase", map[string]string{"phase": "night"})
-		default:
-			if cfg.DiscussionDurationSec <= 0 {
-				return
-			}
-			dur := time.Duration(cfg.DiscussionDurationSec) * time.Second
-			ra.phaseTimer.Schedule(dur, "advance_phase", map[string]string{"phase": "nomination"})
-		}
-	case engine.PhaseNomination:
-		if cfg.NominationPhaseDurationSec <= 0 {
-			return
-		}
-		dur := time.Duration(cfg.NominationPhaseDurationSec) * time.Second
-		ra.phaseTimer.Schedule(dur, "advance_phase", map[string]string{"phase": "night"})
-	}
-	ra.logger.Info("recovered phase timer from state",
-		zap.String("room_id", ra.RoomID),
-		zap.String("phase", string(state.Phase)),
-		zap.String("sub_phase", string(state.SubPhase)),
-	)
-}
-
 func (ra *RoomActor) loadState(ctx context.Context) error {
 	ra.stateMu.Lock()
 	defer ra.stateMu.Unlock()
@@ -171,9 +147,10 @@ func (ra *RoomActor) loadState(ctx context.Context) error {
 	} else {
 		ra.state = engine.NewState(ra.RoomID)
 	}
-	// Always reset GameConfig to current defaults (old snapshots may
-	// contain non-zero timeout values from before timeouts were disabled).
-	ra.state.Config = engine.DefaultGameConfig()
+	// Reset the timeouts to current defaults (old snapshots may contain
+	// non-zero values from before timeouts were disabled); room settings stay.
+	ra.state.Config = ra.state.Config.WithDefaultTimeouts()
+	ra.state.DebugMode = ra.debugMode
 
 	afterSeq := ra.state.LastSeq
 	events, err := ra.store.LoadEventsAfter(ctx, ra.RoomID, afterSeq, 0)
@@ -184,7 +161,7 @@ func (ra *RoomActor) loadState(ctx context.Context) error {
 		payload := toEventPayload(e)
 		ra.state.Reduce(payload)
 	}
-	return nil
+	return ra.loadChainHead(ctx)
 }
 
 func toEventPayload(e store.StoredEvent) engine.EventPayload {
@@ -192,6 +169,7 @@ func toEventPayload(e store.StoredEvent) engine.EventPayload {
 	_ = json.Unmarshal([]byte(e.PayloadJSON), &p)
 	return engine.EventPayload{
 		Seq:     e.Seq,
+		EventID: e.EventID,
 		Type:    e.EventType,
 		Actor:   e.ActorUserID,
 		Payload: p,
@@ -237,7 +215,7 @@ func (ra *RoomActor) executeCommand(ctx context.Context, cmd types.CommandEnvelo
 			fatal = true
 		}
 	}()
-	result, err = ra.handleCommand(ctx, cmd)
+	result, err = ra.handleCommandWithRetry(ctx, cmd)
 	return result, err, false
 }
 
@@ -245,16 +223,13 @@ func (ra *RoomActor) handleCommand(ctx context.Context, cmd types.CommandEnvelop
 	if cmd.RoomID != ra.RoomID {
 		return nil, fmt.Errorf("room mismatch: actor=%s command=%s", ra.RoomID, cmd.RoomID)
 	}
-
-	dedup, err := ra.store.GetDedupRecord(ctx, cmd.RoomID, cmd.ActorUserID, cmd.IdempotencyKey, cmd.Type)
-	if err != nil {
+	ra.conflicts.track(cmd)
+	if err := ra.conflicts.admit(cmd, time.Now()); err != nil {
+		ra.metrics.CommandReject.WithLabelValues("autodm_conflict").Inc()
 		return nil, err
 	}
-	if dedup != nil {
-		ra.metrics.DedupHitTotal.Inc()
-		var result types.CommandResult
-		_ = json.Unmarshal([]byte(dedup.ResultJSON), &result)
-		return &result, nil
+	if result, hit, err := ra.dedupResult(ctx, cmd); err != nil || hit {
+		return result, err
 	}
 	// Intercept start_game to inject AI-composed roles
 	if cmd.Type == "start_game" {
@@ -268,6 +243,44 @@ func (ra *RoomActor) handleCommand(ctx context.Context, cmd types.CommandEnvelop
 		ra.metrics.CommandReject.WithLabelValues("engine").Inc()
 		return nil, err
 	}
+	events = withNightTurn(currentState, cmd, events)
+	events = engine.WithAutoDMTakeover(currentState, cmd, events)
+	events = engine.WithDeathReveals(currentState, cmd, events)
+	storedEvents := toStoredEvents(events, commandCorrelationID(cmd))
+	retracting := hasRetraction(storedEvents)
+	nextState, chainHead, err := ra.applyEvents(ctx, currentState, storedEvents, retracting)
+	if err != nil {
+		return nil, err
+	}
+	if len(storedEvents) > 0 {
+		result.AppliedSeqFrom = storedEvents[0].Seq
+		result.AppliedSeqTo = storedEvents[len(storedEvents)-1].Seq
+	}
+	dedupRec := dedupRecordFor(cmd, result)
+
+	snap := ra.snapshotFor(storedEvents, nextState, retracting)
+	if err := ra.appendEvents(ctx, storedEvents, &dedupRec, snap); err != nil {
+		return nil, err
+	}
+	ra.conflicts.humanIssued(currentState, cmd, time.Now())
+	ra.commit(ctx, storedEvents, nextState, chainHead)
+	return result, nil
+}
+
+// dedupResult returns the stored result when cmd's idempotency key was already handled.
+func (ra *RoomActor) dedupResult(ctx context.Context, cmd types.CommandEnvelope) (*types.CommandResult, bool, error) {
+	dedup, err := ra.store.GetDedupRecord(ctx, cmd.RoomID, cmd.ActorUserID, cmd.IdempotencyKey, cmd.Type)
+	if err != nil || dedup == nil {
+		return nil, false, err
+	}
+	ra.metrics.DedupHitTotal.Inc()
+	var result types.CommandResult
+	_ = json.Unmarshal([]byte(dedup.ResultJSON), &result)
+	return &result, true, nil
+}
+
+// toStoredEvents converts engine events into store rows sharing the command's correlation ID.
+func toStoredEvents(events []types.Event, correlationID string) []store.StoredEvent {
 	storedEvents := make([]store.StoredEvent, len(events))
 	for i, e := range events {
 		storedEvents[i] = store.StoredEvent{
@@ -278,157 +291,56 @@ func (ra *RoomActor) handleCommand(ctx context.Context, cmd types.CommandEnvelop
 			CausationCommand: e.CausationCommand,
 			PayloadJSON:      string(e.Payload),
 			ServerTime:       time.Now().UTC(),
+			CorrelationID:    correlationID,
+		}
+	}
+	return storedEvents
+}
+
+// applyEvents assigns seqs and chain hashes to storedEvents and returns the state after them.
+func (ra *RoomActor) applyEvents(ctx context.Context, current engine.State, storedEvents []store.StoredEvent, retracting bool) (engine.State, string, error) {
+	nextState := current.Copy()
+	assignSeqs(storedEvents, current.LastSeq)
+	chainHead := store.ChainEvents(storedEvents, ra.lastHash)
+	for i := range storedEvents {
+		nextState.Reduce(toEventPayload(storedEvents[i]))
+	}
+	if retracting {
+		var err error
+		if nextState, err = ra.replayWithRetraction(ctx, current, storedEvents); err != nil {
+			return engine.State{}, "", err
 		}
 	}
-	dedupRec := store.DedupRecord{
+	return nextState, chainHead, nil
+}
+
+// dedupRecordFor records cmd's result under its idempotency key.
+func dedupRecordFor(cmd types.CommandEnvelope, result *types.CommandResult) store.DedupRecord {
+	rj, _ := json.Marshal(result)
+	return store.DedupRecord{
 		RoomID:         cmd.RoomID,
 		ActorUserID:    cmd.ActorUserID,
 		IdempotencyKey: cmd.IdempotencyKey,
 		CommandType:    cmd.Type,
 		CommandID:      cmd.CommandID,
 		Status:         result.Status,
-		ResultJSON:     "",
+		ResultJSON:     string(rj),
 		CreatedAt:      time.Now().UTC(),
 	}
-	nextState := currentState.Copy()
-	for i := range storedEvents {
-		storedEvents[i].Seq = currentState.LastSeq + int64(i+1)
-		payload := toEventPayload(storedEvents[i])
-		nextState.Reduce(payload)
-	}
-
-	if len(storedEvents) > 0 {
-		result.AppliedSeqFrom = storedEvents[0].Seq
-		result.AppliedSeqTo = storedEvents[len(storedEvents)-1].Seq
-	}
-	rj, _ := json.Marshal(result)
-	dedupRec.ResultJSON = string(rj)
-
-	var snap *store.Snapshot
-	if len(storedEvents) > 0 && ra.snapshot > 0 && nextState.LastSeq > 0 && nextState.LastSeq%ra.snapshot == 0 {
-		stateJSON, _ := engine.MarshalState(nextState)
-		snap = &store.Snapshot{
-			RoomID:    ra.RoomID,
-			LastSeq:   nextState.LastSeq,
-			StateJSON: stateJSON,
-			CreatedAt: time.Now().UTC(),
-		}
-	}
-	if err := ra.store.AppendEvents(ctx, ra.RoomID, storedEvents, &dedupRec, snap); err != nil {
-		return nil, err
-	}
+}
 
+// commit publishes persisted events: swaps in the new state, then broadcasts and reschedules timers.
+func (ra *RoomActor) commit(ctx context.Context, storedEvents []store.StoredEvent, nextState engine.State, chainHead string) {
 	ra.stateMu.Lock()
 	ra.state = nextState
+	ra.lastHash = chainHead
 	stateSnapshot := ra.state.Copy()
 	ra.stateMu.Unlock()
 
 	ra.broadcast(ctx, storedEvents, stateSnapshot)
 	ra.scheduleTimeouts(storedEvents, stateSnapshot.Config)
-	return result, nil
-}
-
-func (ra *RoomActor) broadcast(ctx context.Context, events []store.StoredEvent, state engine.State) {
-	ra.subsMu.RLock()
-	defer ra.subsMu.RUnlock()
-
-	for _, e := range events {
-		ev := types.Event{
-			RoomID:            e.RoomID,
-			Seq:               e.Seq,
-			EventID:           e.EventID,
-			EventType:         e.EventType,
-			ActorUserID:       e.ActorUserID,
-			CausationCommand:  e.CausationCommand,
-			Payload:           json.RawMessage(e.PayloadJSON),
-			ServerTimestampMs: e.ServerTime.UnixMilli(),
-		}
-
-		// Notify subscribers (WebSocket clients)
-		for _, sub := range ra.subs {
-			viewer := types.Viewer{UserID: sub.UserID, IsDM: sub.IsDM}
-			projected := projection.Project(ev, state, viewer)
-			if projected != nil {
-				sub.Send(*projected)
-			}
-		}
-
-		// Notify AutoDM to respond to game events
-		if ra.autoDM != nil && ra.autoDM.Enabled() {
-			go ra.autoDM.OnEvent(ctx, ev, state)
-		}
-
-		// Notify bots to respond to game events
-		if ra.botNotifier != nil {
-			go ra.botNotifier.OnEvent(ctx, ra.RoomID, ev)
-		}
-	}
-}
-
-// scheduleTimeouts inspects emitted events and schedules phase timeouts.
-// Each new schedule cancels the previous timer automatically.
-// Night timeout is explicitly disabled in the current version, so only
-// day / nomination related timers are scheduled here.
-func (ra *RoomActor) scheduleTimeouts(events []store.StoredEvent, cfg engine.GameConfig) {
-	for _, e := range events {
-		switch e.EventType {
-		case "phase.first_night", "phase.night":
-			continue
-
-		case "phase.day":
-			if cfg.DiscussionDurationSec <= 0 {
-				continue
-			}
-			dur := time.Duration(cfg.DiscussionDurationSec) * time.Second
-			ra.phaseTimer.Schedule(dur, "advance_phase", map[string]string{"phase": "nomination"})
-
-		case "nomination.created":
-			if cfg.DefenseDurationSec <= 0 {
-				continue
-			}
-			dur := time.Duration(cfg.DefenseDurationSec) * time.Second
-			ra.phaseTimer.Schedule(dur, "end_defense", nil)
-
-		case "defense.ended":
-			if cfg.VotingDurationSec <= 0 {
-				continue
-			}
-			dur := time.Duration(cfg.VotingDurationSec) * time.Second * time.Duration(len(ra.state.Players))
-			ra.phaseTimer.Schedule(dur, "close_vote", nil)
-
-		case "nomination.resolved":
-			if cfg.NominationPhaseDurationSec <= 0 {
-				continue
-			}
-			dur := time.Duration(cfg.NominationPhaseDurationSec) * time.Second
-			ra.phaseTimer.Schedule(dur, "advance_phase", map[string]string{"phase": "night"})
-
-		case "time.extended":
-			if cfg.ExtensionDurationSec <= 0 {
-				continue
-			}
-			dur := time.Duration(cfg.ExtensionDurationSec) * time.Second
-			ra.phaseTimer.Schedule(dur, "advance_phase", map[string]string{"phase": "nomination"})
-
-		case "action.reminder":
-			continue
-
-		case "game.ended":
-			ra.phaseTimer.Cancel()
-		}
-	}
-}
-
-func (ra *RoomActor) Subscribe(id string, s *Subscriber) {
-	ra.subsMu.Lock()
-	defer ra.subsMu.Unlock()
-	ra.subs[id] = s
-}
-
-func (ra *RoomActor) Unsubscribe(id string) {
-	ra.subsMu.Lock()
-	defer ra.subsMu.Unlock()
-	delete(ra.subs, id)
+	ra.scheduleDiscussionBounds(storedEvents, stateSnapshot)
+	ra.scheduleNightActionTimeout(storedEvents)
 }
 
 func (ra *RoomActor) Dispatch(cmd types.CommandEnvelope) CommandResponse {
@@ -459,77 +371,3 @@ func (ra *RoomActor) GetState() engine.State {
 	defer ra.stateMu.RUnlock()
 	return ra.state.Copy()
 }
-
-type RoomManager struct {
-	mu     sync.Mutex
-	ctx    context.Context
-	cancel context.CancelFunc
-	actors map[string]*RoomActor
-	deps   RoomDeps
-}
-
-func NewRoomManager(ctx context.Context, deps RoomDeps) *RoomManager {
-	if ctx == nil {
-		ctx = context.Background()
-	}
-	actorCtx, cancel := context.WithCancel(ctx)
-	return &RoomManager{
-		ctx:    actorCtx,
-		cancel: cancel,
-		actors: make(map[string]*RoomActor),
-		deps:   deps,
-	}
-}
-
-func (m *RoomManager) Close() {
-	m.cancel()
-}
-
-// SetBotNotifier sets the bot event notifier after construction.
-// Must be called before any rooms are created.
-func (m *RoomManager) SetBotNotifier(notifier BotEventNotifier) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.deps.BotNotifier = notifier
-}
-
-func (m *RoomManager) GetOrCreate(ctx context.Context, roomID string) (*RoomActor, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	if ra, ok := m.actors[roomID]; ok {
-		return ra, nil
-	}
-	ra, err := NewRoomActor(ctx, m.ctx, roomID, m.deps, m.handleActorCrash)
-	if err != nil {
-		return nil, err
-	}
-	m.actors[roomID] = ra
-	return ra, nil
-}
-
-func (m *RoomManager) handleActorCrash(roomID string) {
-	reloadCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	ra, err := NewRoomActor(reloadCtx, m.ctx, roomID, m.deps, m.handleActorCrash)
-	if err != nil {
-		m.deps.Logger.Error("failed to restart room actor", zap.String("room_id", roomID), zap.Error(err))
-		return
-	}
-
-	m.mu.Lock()
-	m.actors[roomID] = ra
-	m.mu.Unlock()
-
-	m.deps.Logger.Warn("room actor restarted", zap.String("room_id", roomID))
-}
-
-// DispatchAsync routes a command to the correct room actor by room ID.
-func (m *RoomManager) DispatchAsync(cmd types.CommandEnvelope) error {
-	ra, err := m.GetOrCreate(context.Background(), cmd.RoomID)
-	if err != nil {
-		return err
-	}
-	resp := ra.Dispatch(cmd)
-	return resp.Err
-}
@@ -11,6 +11,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/game"
 	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
 )
@@ -20,18 +21,18 @@ const composeTimeout = 15 * time.Second
 // enrichStartGame calls the Composer before start_game reaches the engine.
 // On success, injects "custom_roles" into cmd.Data.
 // On failure, logs warning and returns original cmd (random fallback).
+// A start_game carrying a previewed "seed" keeps the previewed roles and skips the Composer.
+// So does a room with a custom script: the Composer picks from the whole edition.
 func (ra *RoomActor) enrichStartGame(ctx context.Context, cmd types.CommandEnvelope) types.CommandEnvelope {
-	if ra.composer == nil {
+	if ra.composer == nil || hasSetupSeed(cmd) {
 		return cmd
 	}
 
 	state := ra.GetState()
-	playerCount := 0
-	for _, p := range state.Players {
-		if !p.IsDM {
-			playerCount++
-		}
+	if len(state.Script) > 0 {
+		return cmd
 	}
+	playerCount := seatedPlayerCount(state)
 	if playerCount < 5 {
 		return cmd // Engine will reject anyway
 	}
@@ -58,7 +59,34 @@ func (ra *RoomActor) enrichStartGame(ctx context.Context, cmd types.CommandEnvel
 		return cmd
 	}
 
-	// Merge custom_roles into the command payload
+	merged, ok := ra.withCustomRoles(cmd, rolesJSON)
+	if !ok {
+		return cmd
+	}
+	cmd.Payload = merged
+
+	ra.logger.Info("AI composed roles",
+		zap.String("room_id", ra.RoomID),
+		zap.Int("player_count", playerCount),
+		zap.String("roles", string(rolesJSON)),
+		zap.String("reasoning", result.Reasoning))
+
+	return cmd
+}
+
+// seatedPlayerCount counts the non-DM players the composer sizes the setup for.
+func seatedPlayerCount(state engine.State) int {
+	n := 0
+	for _, p := range state.Players {
+		if !p.IsDM {
+			n++
+		}
+	}
+	return n
+}
+
+// withCustomRoles merges custom_roles into the start_game payload.
+func (ra *RoomActor) withCustomRoles(cmd types.CommandEnvelope, rolesJSON []byte) (json.RawMessage, bool) {
 	var payload map[string]string
 	if cmd.Payload != nil {
 		if err := json.Unmarshal(cmd.Payload, &payload); err != nil {
@@ -74,16 +102,14 @@ func (ra *RoomActor) enrichStartGame(ctx context.Context, cmd types.CommandEnvel
 	if err != nil {
 		ra.logger.Warn("enrichStartGame: failed to marshal payload",
 			zap.String("room_id", ra.RoomID), zap.Error(err))
-		return cmd
+		return nil, false
 	}
-	cmd.Payload = merged
-
-	ra.logger.Info("AI composed roles",
-		zap.String("room_id", ra.RoomID),
-		zap.Int("player_count", playerCount),
-		zap.String("roles", string(rolesJSON)),
-		zap.String("reasoning", result.Reasoning))
-
-	return cmd
+	return merged, true
 }
 
+// hasSetupSeed reports whether start_game asks for a previewed seed.
+func hasSetupSeed(cmd types.CommandEnvelope) bool {
+	var payload map[string]string
+	_ = json.Unmarshal(cmd.Payload, &payload)
+	return payload["seed"] != ""
+}
@@ -6,6 +6,7 @@ package room
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -31,4 +32,12 @@ type RoomDeps struct {
 	AutoDM           *agent.AutoDM
 	Composer         game.Composer
 	BotNotifier      BotEventNotifier
+
+	// NightActionTimeout auto-completes a pending night action after this long; 0 disables.
+	NightActionTimeout time.Duration
+	// DebugCommands enables DM debug commands outside the lobby (State.DebugMode).
+	DebugCommands bool
+
+	// SnapshotOnPhaseChange snapshots after every phase.* event in addition to SnapshotInterval.
+	SnapshotOnPhaseChange bool
 }
@@ -0,0 +1,108 @@
+// Package room 房间 Actor 管理器
+//
+// 按房间 ID 懒加载 RoomActor，Actor 崩溃后从存储重建，Evict 停止 Actor 及其计时器。
+//
+// [OUT] api、realtime（GetOrCreate、DispatchAsync）
+// [OUT] retention.go（清理后 Evict）
+// [POS] 房间 Actor 的生命周期管理
+package room
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+type RoomManager struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	actors map[string]*RoomActor
+	deps   RoomDeps
+}
+
+func NewRoomManager(ctx context.Context, deps RoomDeps) *RoomManager {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	actorCtx, cancel := context.WithCancel(ctx)
+	return &RoomManager{
+		ctx:    actorCtx,
+		cancel: cancel,
+		actors: make(map[string]*RoomActor),
+		deps:   deps,
+	}
+}
+
+func (m *RoomManager) Close() {
+	m.cancel()
+}
+
+// SetBotNotifier sets the bot event notifier after construction.
+// Must be called before any rooms are created.
+func (m *RoomManager) SetBotNotifier(notifier BotEventNotifier) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deps.BotNotifier = notifier
+}
+
+func (m *RoomManager) GetOrCreate(ctx context.Context, roomID string) (*RoomActor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ra, ok := m.actors[roomID]; ok {
+		return ra, nil
+	}
+	ra, err := NewRoomActor(ctx, m.ctx, roomID, m.deps, m.handleActorCrash)
+	if err != nil {
+		return nil, err
+	}
+	m.actors[roomID] = ra
+	return ra, nil
+}
+
+func (m *RoomManager) handleActorCrash(roomID string) {
+	reloadCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ra, err := NewRoomActor(reloadCtx, m.ctx, roomID, m.deps, m.handleActorCrash)
+	if err != nil {
+		m.deps.Logger.Error("failed to restart room actor", zap.String("room_id", roomID), zap.Error(err))
+		return
+	}
+
+	m.mu.Lock()
+	m.actors[roomID] = ra
+	m.mu.Unlock()
+
+	m.deps.Logger.Warn("room actor restarted", zap.String("room_id", roomID))
+}
+
+// Evict stops the room's actor, if any; the next GetOrCreate reloads it from the store.
+func (m *RoomManager) Evict(roomID string) {
+	m.mu.Lock()
+	ra := m.actors[roomID]
+	delete(m.actors, roomID)
+	m.mu.Unlock()
+	if ra == nil {
+		return
+	}
+	ra.cancel()
+	ra.phaseTimer.Cancel()
+	ra.nightActionTimer.Cancel()
+	ra.dayMaxTimer.Cancel()
+	m.deps.Logger.Info("room actor evicted", zap.String("room_id", roomID))
+}
+
+// DispatchAsync routes a command to the correct room actor by room ID.
+func (m *RoomManager) DispatchAsync(cmd types.CommandEnvelope) error {
+	ra, err := m.GetOrCreate(context.Background(), cmd.RoomID)
+	if err != nil {
+		return err
+	}
+	resp := ra.Dispatch(cmd)
+	return resp.Err
+}
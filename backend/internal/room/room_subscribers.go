@@ -0,0 +1,81 @@
+// Package room 房间订阅者与事件广播
+//
+// WebSocket 会话订阅房间；每批落库事件按订阅者视角投影后推送，并通知 Auto-DM 与机器人。
+//
+// [IN]  internal/projection（按视角过滤事件）
+// [OUT] realtime（Subscribe/Unsubscribe/HasSubscriber）
+// [OUT] room.go（commit 广播）
+// [POS] 房间 Actor 的事件出口
+package room
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/projection"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/types"
+)
+
+func (ra *RoomActor) Subscribe(id string, s *Subscriber) {
+	ra.subsMu.Lock()
+	defer ra.subsMu.Unlock()
+	ra.subs[id] = s
+}
+
+func (ra *RoomActor) Unsubscribe(id string) {
+	ra.subsMu.Lock()
+	defer ra.subsMu.Unlock()
+	delete(ra.subs, id)
+}
+
+// HasSubscriber reports whether userID still has a live subscription to the room.
+func (ra *RoomActor) HasSubscriber(userID string) bool {
+	ra.subsMu.RLock()
+	defer ra.subsMu.RUnlock()
+	for _, s := range ra.subs {
+		if s.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (ra *RoomActor) broadcast(ctx context.Context, events []store.StoredEvent, state engine.State) {
+	ra.subsMu.RLock()
+	defer ra.subsMu.RUnlock()
+
+	for _, e := range events {
+		ev := types.Event{
+			RoomID:            e.RoomID,
+			Seq:               e.Seq,
+			EventID:           e.EventID,
+			EventType:         e.EventType,
+			ActorUserID:       e.ActorUserID,
+			CausationCommand:  e.CausationCommand,
+			Payload:           json.RawMessage(e.PayloadJSON),
+			ServerTimestampMs: e.ServerTime.UnixMilli(),
+			CorrelationID:     e.CorrelationID,
+		}
+
+		// Notify subscribers (WebSocket clients)
+		for _, sub := range ra.subs {
+			viewer := types.Viewer{UserID: sub.UserID, IsDM: sub.IsDM}
+			projected := projection.ProjectEvent(ev, state, viewer)
+			if projected != nil {
+				sub.Send(*projected)
+			}
+		}
+
+		// Notify AutoDM to respond to game events
+		if ra.autoDM != nil && ra.autoDM.Enabled() {
+			go ra.autoDM.OnEvent(ctx, ev, state)
+		}
+
+		// Notify bots to respond to game events
+		if ra.botNotifier != nil {
+			go ra.botNotifier.OnEvent(ctx, ra.RoomID, ev)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Package room 阶段计时器接线
+//
+// 命令落库后按新事件调度阶段超时 (讨论、辩护、投票、提名)，Actor 加载后按持久化状态恢复
+// 当前阶段的计时器。夜晚超时在当前版本中关闭，夜晚阶段不调度。
+//
+// [IN]  internal/engine（State 与 GameConfig 时长）
+// [OUT] room.go（NewRoomActor 恢复、commit 调度）
+// [POS] 房间 Actor 与 PhaseTimer 之间的接线
+package room
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// scheduleAfter schedules cmdType after sec seconds; sec <= 0 leaves the timer disabled.
+func (ra *RoomActor) scheduleAfter(sec int, cmdType string, data map[string]string) {
+	if sec <= 0 {
+		return
+	}
+	ra.phaseTimer.Schedule(time.Duration(sec)*time.Second, cmdType, data)
+}
+
+// votingSeconds is the whole vote's duration: VotingDurationSec per player.
+func votingSeconds(cfg engine.GameConfig, players int) int {
+	if cfg.VotingDurationSec <= 0 {
+		return 0
+	}
+	return cfg.VotingDurationSec * players
+}
+
+// recoverTimeoutFromState re-schedules the appropriate phase timer
+// after loading persisted state (e.g., after server restart).
+func (ra *RoomActor) recoverTimeoutFromState() {
+	state := ra.state
+	sec, cmdType, data := recoveryTimeout(state)
+	if sec <= 0 {
+		return
+	}
+	ra.scheduleAfter(sec, cmdType, data)
+	ra.logger.Info("recovered phase timer from state",
+		zap.String("room_id", ra.RoomID),
+		zap.String("phase", string(state.Phase)),
+		zap.String("sub_phase", string(state.SubPhase)),
+	)
+}
+
+// recoveryTimeout picks the timer the persisted phase was waiting on; sec is 0 when none.
+// Night timeout is explicitly disabled in the current version, so
+// first_night / night phases are intentionally skipped here.
+func recoveryTimeout(state engine.State) (int, string, map[string]string) {
+	cfg := state.Config
+	switch {
+	case state.Phase == engine.PhaseDay && state.SubPhase == engine.SubPhaseDefense:
+		return cfg.DefenseDurationSec, "end_defense", nil
+	case state.Phase == engine.PhaseDay && state.SubPhase == engine.SubPhaseVoting:
+		return votingSeconds(cfg, len(state.Players)), "close_vote", nil
+	case state.Phase == engine.PhaseDay && state.SubPhase == engine.SubPhaseNominationOpen,
+		state.Phase == engine.PhaseNomination:
+		return cfg.NominationPhaseDurationSec, "advance_phase", map[string]string{"phase": "night"}
+	case state.Phase == engine.PhaseDay:
+		return cfg.DiscussionDurationSec, "advance_phase", map[string]string{"phase": "nomination"}
+	}
+	return 0, "", nil
+}
+
+// scheduleTimeouts inspects emitted events and schedules phase timeouts.
+// Each new schedule cancels the previous timer automatically.
+// Night timeout is explicitly disabled in the current version, so only
+// day / nomination related timers are scheduled here.
+func (ra *RoomActor) scheduleTimeouts(events []store.StoredEvent, cfg engine.GameConfig) {
+	for _, e := range events {
+		switch e.EventType {
+		case "phase.day":
+			ra.scheduleAfter(cfg.DiscussionDurationSec, "advance_phase", map[string]string{"phase": "nomination"})
+		case "nomination.created":
+			ra.scheduleAfter(cfg.DefenseDurationSec, "end_defense", nil)
+		case "defense.ended":
+			ra.scheduleAfter(votingSeconds(cfg, len(ra.state.Players)), "close_vote", nil)
+		case "nomination.resolved":
+			ra.scheduleAfter(cfg.NominationPhaseDurationSec, "advance_phase", map[string]string{"phase": "night"})
+		case "time.extended":
+			ra.scheduleAfter(cfg.ExtensionDurationSec, "advance_phase", map[string]string{"phase": "nomination"})
+		case "timer.set":
+			ra.scheduleDiscussionDeadline(e) // discussion_timer.go
+		case "game.ended":
+			ra.phaseTimer.Cancel()
+		}
+	}
+}
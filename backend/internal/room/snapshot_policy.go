@@ -0,0 +1,46 @@
+// Package room 快照触发策略
+//
+// 命令产生事件后按以下任一条件写快照：撤回重建 (强制)、LastSeq 到达 SnapshotInterval 的整数倍、
+// 或开启 SnapshotOnPhaseChange 时本批事件含阶段切换 (phase.*)。快照记录生成时的阶段，
+// 阶段边界的快照便于回放、排查与恢复。
+//
+// [IN]  RoomDeps.SnapshotInterval、RoomDeps.SnapshotOnPhaseChange
+// [OUT] room.go（handleCommand 随事件一起追加快照）
+// [POS] Actor 的快照决策
+package room
+
+import (
+	"strings"
+	"time"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+// snapshotFor returns the snapshot to persist with events, or nil when none is due.
+func (ra *RoomActor) snapshotFor(events []store.StoredEvent, next engine.State, force bool) *store.Snapshot {
+	if !force && !ra.intervalSnapshotDue(events, next) && !(ra.snapshotOnPhase && hasPhaseChange(events)) {
+		return nil
+	}
+	stateJSON, _ := engine.MarshalState(next)
+	return &store.Snapshot{
+		RoomID:    ra.RoomID,
+		LastSeq:   next.LastSeq,
+		Phase:     string(next.Phase),
+		StateJSON: stateJSON,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (ra *RoomActor) intervalSnapshotDue(events []store.StoredEvent, next engine.State) bool {
+	return len(events) > 0 && ra.snapshot > 0 && next.LastSeq > 0 && next.LastSeq%ra.snapshot == 0
+}
+
+func hasPhaseChange(events []store.StoredEvent) bool {
+	for _, e := range events {
+		if strings.HasPrefix(e.EventType, "phase.") {
+			return true
+		}
+	}
+	return false
+}
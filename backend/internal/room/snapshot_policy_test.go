@@ -0,0 +1,82 @@
+package room
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/engine"
+	"github.com/qingchang/Blood-on-the-Clocktower-auto-dm/internal/store"
+)
+
+func TestPhaseChangeTriggersSnapshotWhenEnabled(t *testing.T) {
+	next := engine.NewState("room-1")
+	next.Phase = engine.PhaseNight
+	next.LastSeq = 7
+	events := []store.StoredEvent{{Seq: 7, EventType: "phase.night"}}
+
+	off := &RoomActor{RoomID: "room-1", snapshot: 50}
+	if snap := off.snapshotFor(events, next, false); snap != nil {
+		t.Fatalf("expected no snapshot off the interval without the option, got %+v", snap)
+	}
+
+	on := &RoomActor{RoomID: "room-1", snapshot: 50, snapshotOnPhase: true}
+	snap := on.snapshotFor(events, next, false)
+	if snap == nil || snap.LastSeq != 7 || snap.Phase != string(engine.PhaseNight) {
+		t.Fatalf("expected a night snapshot at seq 7, got %+v", snap)
+	}
+	if snap := on.snapshotFor([]store.StoredEvent{{Seq: 7, EventType: "public.chat"}}, next, false); snap != nil {
+		t.Fatalf("expected chat alone not to snapshot, got %+v", snap)
+	}
+}
+
+// snapshotLog serves a fixed snapshot on top of an in-memory event log.
+type snapshotLog struct {
+	*memEventLog
+	snap *store.Snapshot
+}
+
+func (s *snapshotLog) GetLatestSnapshot(context.Context, string) (*store.Snapshot, error) {
+	return s.snap, nil
+}
+
+func TestRoomSettingsSurviveSnapshotReload(t *testing.T) {
+	settings := map[string]string{
+		"translate_announcements":       "true",
+		"discussion_nudge_sec":          "45",
+		"discussion_nudge_message":      "anyone?",
+		"demon_sees_minion_roles":       "true",
+		"min_discussion_sec":            "60",
+		"max_discussion_sec":            "300",
+		"reveal_on_death":               "true",
+		"earliest_nomination_wins_ties": "true",
+		"dm_resolves_ties":              "true",
+		"forbid_self_poison":            "true",
+		"announce_deaths_at_dawn":       "false",
+		"narration_language":            "en",
+	}
+	next := engine.NewState("room-1")
+	next.Reduce(engine.EventPayload{Seq: 1, Type: "room.settings.changed", Payload: settings})
+	next.Reduce(engine.EventPayload{Seq: 2, Type: "phase.first_night", Payload: map[string]string{}})
+	next.Config.VotingDurationSec = 90 // stale timeout from an older build
+
+	on := &RoomActor{RoomID: "room-1", snapshotOnPhase: true}
+	snap := on.snapshotFor([]store.StoredEvent{{Seq: 2, EventType: "phase.first_night"}}, next, false)
+	if snap == nil {
+		t.Fatal("expected a phase change snapshot")
+	}
+
+	ra := newIdleTestActor(t, &snapshotLog{memEventLog: newMemEventLog(), snap: snap})
+	if err := ra.loadState(context.Background()); err != nil {
+		t.Fatalf("loadState: %v", err)
+	}
+	cfg := ra.state.Config
+	if !cfg.TranslateAnnouncements || cfg.DiscussionNudgeSec != 45 || cfg.DiscussionNudgeMessage != "anyone?" ||
+		!cfg.DemonSeesMinionRoles || cfg.MinDiscussionSec != 60 || cfg.MaxDiscussionSec != 300 ||
+		!cfg.RevealOnDeath || !cfg.EarliestNominationWinsTies || !cfg.DMResolvesTies || !cfg.ForbidSelfPoison ||
+		cfg.AnnounceDeathsAtDawn || cfg.NarrationLanguage != "en" {
+		t.Fatalf("room settings lost on snapshot reload: %+v", cfg)
+	}
+	if cfg.VotingDurationSec != 0 {
+		t.Fatalf("expected timeouts reset to defaults, got voting %ds", cfg.VotingDurationSec)
+	}
+}
@@ -0,0 +1,138 @@
+// Package store AutoDM 运行与工具调用审计持久化
+//
+// agent_runs 按 ID 覆盖写入 (运行结束时落盘)，agent_tool_calls 按调用 ID 去重写入；
+// 列表查询均按创建时间倒序。依赖迁移 006 (plan_json 列、agent_tool_calls 表与索引)。
+//
+// [OUT] cmd/server（agent.AgentRunStore 适配器）
+// [POS] 审计存储层，供 DM 调试端点查询
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const agentRunColumns = `id,room_id,seq_from,seq_to,agent_name,viewer_user_id,input_digest,output_digest,status,latency_ms,error_text,created_at,plan_json`
+
+// SaveAgentRun inserts the run or replaces the row with the same ID.
+func (s *Store) SaveAgentRun(ctx context.Context, r AgentRun) error {
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT INTO agent_runs (`+agentRunColumns+`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)
+		 ON DUPLICATE KEY UPDATE seq_to=VALUES(seq_to), output_digest=VALUES(output_digest), status=VALUES(status),
+		 latency_ms=VALUES(latency_ms), error_text=VALUES(error_text), plan_json=VALUES(plan_json)`,
+		r.ID, r.RoomID, r.SeqFrom, r.SeqTo, r.AgentName, r.ViewerUserID, r.InputDigest, r.OutputDigest,
+		r.Status, r.LatencyMs, r.ErrorText, r.CreatedAt, nullString(r.PlanJSON),
+	)
+	if err != nil {
+		return fmt.Errorf("store.SaveAgentRun: %w", err)
+	}
+	return nil
+}
+
+// ListAgentRuns returns a room's runs, newest first.
+func (s *Store) ListAgentRuns(ctx context.Context, roomID string, limit, offset int) ([]AgentRun, error) {
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT `+agentRunColumns+` FROM agent_runs WHERE room_id=? ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`,
+		roomID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("store.ListAgentRuns: %w", err)
+	}
+	defer rows.Close()
+
+	res := []AgentRun{}
+	for rows.Next() {
+		r, err := scanAgentRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("store.ListAgentRuns: %w", err)
+		}
+		res = append(res, *r)
+	}
+	return res, rows.Err()
+}
+
+// GetAgentRun loads one run; a missing run wraps sql.ErrNoRows.
+func (s *Store) GetAgentRun(ctx context.Context, id string) (*AgentRun, error) {
+	row := s.DB.QueryRowContext(ctx, `SELECT `+agentRunColumns+` FROM agent_runs WHERE id=?`, id)
+	r, err := scanAgentRun(row)
+	if err != nil {
+		return nil, fmt.Errorf("store.GetAgentRun: %w", err)
+	}
+	return r, nil
+}
+
+func scanAgentRun(row interface{ Scan(...any) error }) (*AgentRun, error) {
+	var r AgentRun
+	var viewer, inDigest, outDigest, errText, plan sql.NullString
+	var latency sql.NullInt64
+	if err := row.Scan(&r.ID, &r.RoomID, &r.SeqFrom, &r.SeqTo, &r.AgentName, &viewer, &inDigest, &outDigest,
+		&r.Status, &latency, &errText, &r.CreatedAt, &plan); err != nil {
+		return nil, err
+	}
+	if viewer.Valid {
+		r.ViewerUserID = &viewer.String
+	}
+	r.InputDigest, r.OutputDigest, r.ErrorText = inDigest.String, outDigest.String, errText.String
+	r.LatencyMs, r.PlanJSON = latency.Int64, plan.String
+	return &r, nil
+}
+
+// SaveAgentToolCall records a tool call; a repeated ID is ignored so retries are safe.
+func (s *Store) SaveAgentToolCall(ctx context.Context, c AgentToolCall) error {
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT IGNORE INTO agent_tool_calls (id,run_id,room_id,tool_name,args_json,result_json,error_text,duration_ms,created_at)
+		 VALUES (?,?,?,?,?,?,?,?,?)`,
+		c.ID, c.RunID, c.RoomID, c.ToolName, nullString(c.ArgsJSON), nullString(c.ResultJSON), nullString(c.ErrorText),
+		c.DurationMs, c.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("store.SaveAgentToolCall: %w", err)
+	}
+	return nil
+}
+
+// ListAgentToolCalls returns a room's tool calls, newest first; tool filters by exact name when set.
+func (s *Store) ListAgentToolCalls(ctx context.Context, roomID, tool string, limit, offset int) ([]AgentToolCall, error) {
+	query := `SELECT id,run_id,room_id,tool_name,args_json,result_json,error_text,duration_ms,created_at
+		 FROM agent_tool_calls WHERE room_id=?`
+	args := []interface{}{roomID}
+	if tool != "" {
+		query += ` AND tool_name=?`
+		args = append(args, tool)
+	}
+	query += ` ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
+	return s.queryAgentToolCalls(ctx, "store.ListAgentToolCalls", query, args...)
+}
+
+// ListRunToolCalls returns the tool calls made during one run, oldest first.
+func (s *Store) ListRunToolCalls(ctx context.Context, runID string) ([]AgentToolCall, error) {
+	return s.queryAgentToolCalls(ctx, "store.ListRunToolCalls",
+		`SELECT id,run_id,room_id,tool_name,args_json,result_json,error_text,duration_ms,created_at
+		 FROM agent_tool_calls WHERE run_id=? ORDER BY created_at ASC`, runID)
+}
+
+func (s *Store) queryAgentToolCalls(ctx context.Context, op, query string, args ...interface{}) ([]AgentToolCall, error) {
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	res := []AgentToolCall{}
+	for rows.Next() {
+		var c AgentToolCall
+		var argsJSON, resultJSON, errText sql.NullString
+		if err := rows.Scan(&c.ID, &c.RunID, &c.RoomID, &c.ToolName, &argsJSON, &resultJSON, &errText, &c.DurationMs, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		c.ArgsJSON, c.ResultJSON, c.ErrorText = argsJSON.String, resultJSON.String, errText.String
+		res = append(res, c)
+	}
+	return res, rows.Err()
+}
+
+// nullString stores empty strings as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
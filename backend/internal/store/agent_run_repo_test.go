@@ -0,0 +1,63 @@
+//go:build integration
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newTestRoom creates a user and a room owned by it so agent_runs rows satisfy their foreign key.
+func newTestRoom(t *testing.T, st *Store) string {
+	t.Helper()
+	ctx := context.Background()
+	userID, roomID := uuid.NewString(), uuid.NewString()
+	now := time.Now().UTC()
+	if err := st.CreateUser(ctx, User{ID: userID, Email: userID + "@example.com", PasswordHash: "x", CreatedAt: now}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := st.CreateRoom(ctx, Room{ID: roomID, CreatedBy: userID, DMUserID: userID, Status: "lobby", CreatedAt: now}); err != nil {
+		t.Fatalf("create room: %v", err)
+	}
+	return roomID
+}
+
+func TestSavedAgentRunLoadsByIDWithToolCalls(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	roomID := newTestRoom(t, st)
+	run := AgentRun{
+		ID: uuid.NewString(), RoomID: roomID, SeqFrom: 3, SeqTo: 3, AgentName: "autodm",
+		InputDigest: "in", Status: "running", CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if err := st.SaveAgentRun(ctx, run); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	run.Status, run.LatencyMs, run.PlanJSON = "ok", 12, `{"kind":"vote_tally"}`
+	if err := st.SaveAgentRun(ctx, run); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	call := AgentToolCall{ID: uuid.NewString(), RunID: run.ID, RoomID: roomID, ToolName: "send_public_message", ArgsJSON: `{}`, CreatedAt: time.Now().UTC()}
+	if err := st.SaveAgentToolCall(ctx, call); err != nil {
+		t.Fatalf("save call: %v", err)
+	}
+
+	got, err := st.GetAgentRun(ctx, run.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Status != "ok" || got.LatencyMs != 12 || got.PlanJSON != run.PlanJSON || got.SeqFrom != 3 {
+		t.Fatalf("expected the updated run, got %+v", got)
+	}
+	runs, err := st.ListAgentRuns(ctx, roomID, 10, 0)
+	if err != nil || len(runs) != 1 || runs[0].ID != run.ID {
+		t.Fatalf("expected the run to be listed once, got %+v (%v)", runs, err)
+	}
+	calls, err := st.ListRunToolCalls(ctx, run.ID)
+	if err != nil || len(calls) != 1 || calls[0].ToolName != "send_public_message" {
+		t.Fatalf("expected the run's tool call, got %+v (%v)", calls, err)
+	}
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAppendEventsFailureMidBatchPersistsNothing(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	roomID := uuid.NewString()
+	event := func(id string) StoredEvent {
+		return StoredEvent{RoomID: roomID, EventID: id, EventType: "public.chat", ActorUserID: "user-1", PayloadJSON: `{}`, ServerTime: time.Now().UTC()}
+	}
+
+	dup := uuid.NewString()
+	batch := []StoredEvent{event(uuid.NewString()), event(dup), event(dup)} // third insert violates event_id UNIQUE
+	if err := st.AppendEvents(ctx, roomID, batch, nil, nil); err == nil {
+		t.Fatal("expected the batch with a duplicate event_id to fail")
+	}
+	got, err := st.LoadEventsAfter(ctx, roomID, 0, 0)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected zero persisted events after rollback, got %d", len(got))
+	}
+
+	next := []StoredEvent{event(uuid.NewString()), event(uuid.NewString())}
+	if err := st.AppendEvents(ctx, roomID, next, nil, nil); err != nil {
+		t.Fatalf("append after rollback: %v", err)
+	}
+	if next[0].Seq != 1 || next[1].Seq != 2 {
+		t.Fatalf("expected the rolled-back seqs to be reused (1, 2), got %d, %d", next[0].Seq, next[1].Seq)
+	}
+}
+
+func TestAppendEventsRejectsStaleWriter(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	roomID := uuid.NewString()
+	event := func(seq int64) StoredEvent {
+		return StoredEvent{RoomID: roomID, Seq: seq, EventID: uuid.NewString(), EventType: "public.chat", ActorUserID: "user-1", PayloadJSON: `{}`, ServerTime: time.Now().UTC()}
+	}
+
+	if err := st.AppendEvents(ctx, roomID, []StoredEvent{event(1), event(2)}, nil, nil); err != nil {
+		t.Fatalf("first writer: %v", err)
+	}
+	// A second writer still believes the next seq is 2.
+	err := st.AppendEvents(ctx, roomID, []StoredEvent{event(2)}, nil, nil)
+	if !errors.Is(err, ErrSeqConflict) {
+		t.Fatalf("expected the stale writer to get ErrSeqConflict, got %v", err)
+	}
+	got, _ := st.LoadEventsAfter(ctx, roomID, 0, 0)
+	if len(got) != 2 {
+		t.Fatalf("expected only the first writer's 2 events, got %d", len(got))
+	}
+}
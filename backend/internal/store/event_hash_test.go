@@ -0,0 +1,45 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func chainedEvents(n int) []StoredEvent {
+	events := make([]StoredEvent, n)
+	for i := range events {
+		events[i] = StoredEvent{
+			RoomID:           "room-1",
+			Seq:              int64(i + 1),
+			EventID:          fmt.Sprintf("ev-%d", i+1),
+			EventType:        "public.chat",
+			ActorUserID:      "user-1",
+			CausationCommand: fmt.Sprintf("cmd-%d", i+1),
+			PayloadJSON:      fmt.Sprintf(`{"message":"msg %d"}`, i+1),
+		}
+	}
+	ChainEvents(events, "")
+	return events
+}
+
+func TestVerifyChainAcceptsUntouchedChain(t *testing.T) {
+	events := chainedEvents(5)
+	if broken := VerifyChain(events); len(broken) != 0 {
+		t.Fatalf("expected intact chain, broken at %v", broken)
+	}
+	// A window fetched with after_seq verifies from its first PrevHash.
+	if broken := VerifyChain(events[2:]); len(broken) != 0 {
+		t.Fatalf("expected intact window, broken at %v", broken)
+	}
+}
+
+func TestVerifyChainFlippedPayloadBreaksAllLaterEvents(t *testing.T) {
+	events := chainedEvents(5)
+	events[1].PayloadJSON = `{"message":"forged"}`
+
+	broken := VerifyChain(events)
+	if want := []int64{2, 3, 4, 5}; !slices.Equal(broken, want) {
+		t.Fatalf("expected broken seqs %v, got %v", want, broken)
+	}
+}
@@ -0,0 +1,45 @@
+// Package store 按事件类型查询（分析与调试）
+//
+// 例如拉取某房间全部 execution.resolved 事件。依赖迁移 004 的 (room_id, event_type) 索引。
+//
+// [OUT] api（GET /v1/rooms/{room_id}/events?type=）
+// [POS] 事件存储层的只读分析查询
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DefaultEventsByTypeLimit caps LoadEventsByType when limit <= 0.
+const DefaultEventsByTypeLimit = 200
+
+// LoadEventsByType returns a room's events of eventType in seq order.
+func (s *Store) LoadEventsByType(ctx context.Context, roomID, eventType string, limit int) ([]StoredEvent, error) {
+	if limit <= 0 {
+		limit = DefaultEventsByTypeLimit
+	}
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT room_id,seq,event_id,event_type,actor_user_id,causation_command_id,payload_json,server_ts,correlation_id,prev_hash,hash
+		 FROM events WHERE room_id=? AND event_type=? ORDER BY seq ASC LIMIT ?`,
+		roomID, eventType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("store.LoadEventsByType: %w", err)
+	}
+	defer rows.Close()
+
+	var res []StoredEvent
+	for rows.Next() {
+		var e StoredEvent
+		var causation, correlation, prevHash, hash sql.NullString
+		if err := rows.Scan(&e.RoomID, &e.Seq, &e.EventID, &e.EventType, &e.ActorUserID, &causation, &e.PayloadJSON, &e.ServerTime, &correlation, &prevHash, &hash); err != nil {
+			return nil, fmt.Errorf("store.LoadEventsByType: %w", err)
+		}
+		e.CausationCommand = causation.String
+		e.CorrelationID = correlation.String
+		e.PrevHash, e.Hash = prevHash.String, hash.String
+		res = append(res, e)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newTestStore connects to the database in TEST_DB_DSN (migrations applied) or skips.
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DB_DSN not set")
+	}
+	db, err := ConnectMySQL(dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return New(db)
+}
+
+func TestLoadEventsByTypeReturnsMatchingEventsInSeqOrder(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	roomID := uuid.NewString()
+
+	types := []string{"public.chat", "execution.resolved", "public.chat", "phase.day", "execution.resolved"}
+	events := make([]StoredEvent, len(types))
+	for i, eventType := range types {
+		events[i] = StoredEvent{
+			RoomID:      roomID,
+			EventID:     uuid.NewString(),
+			EventType:   eventType,
+			ActorUserID: "user-1",
+			PayloadJSON: `{}`,
+			ServerTime:  time.Now().UTC(),
+		}
+	}
+	if err := st.AppendEvents(ctx, roomID, events, nil, nil); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	got, err := st.LoadEventsByType(ctx, roomID, "execution.resolved", 0)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 execution.resolved events, got %d", len(got))
+	}
+	if got[0].Seq != 2 || got[1].Seq != 5 {
+		t.Fatalf("expected seqs [2 5], got [%d %d]", got[0].Seq, got[1].Seq)
+	}
+	for _, e := range got {
+		if e.EventType != "execution.resolved" {
+			t.Fatalf("unexpected event type %s", e.EventType)
+		}
+	}
+}
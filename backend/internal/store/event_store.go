@@ -20,6 +20,11 @@ type StoredEvent struct {
 	CausationCommand string // FIX-18: scanned via sql.NullString to handle NULL
 	PayloadJSON      string
 	ServerTime       time.Time
+	CorrelationID    string // shared by all events of one command
+
+	// PrevHash / Hash link the room's events into a tamper-evident chain (event_hash.go)
+	PrevHash string
+	Hash     string
 }
 
 func (s *Store) GetDedupRecord(ctx context.Context, roomID, actorUserID, idempotencyKey, commandType string) (*DedupRecord, error) {
@@ -41,9 +46,9 @@ func (s *Store) SaveDedupRecord(ctx context.Context, tx *sql.Tx, r DedupRecord)
 }
 
 func (s *Store) GetLatestSnapshot(ctx context.Context, roomID string) (*Snapshot, error) {
-	row := s.DB.QueryRowContext(ctx, `SELECT room_id,last_seq,state_json,created_at FROM snapshots WHERE room_id=? ORDER BY last_seq DESC LIMIT 1`, roomID)
+	row := s.DB.QueryRowContext(ctx, `SELECT room_id,last_seq,phase,state_json,created_at FROM snapshots WHERE room_id=? ORDER BY last_seq DESC LIMIT 1`, roomID)
 	var snap Snapshot
-	if err := row.Scan(&snap.RoomID, &snap.LastSeq, &snap.StateJSON, &snap.CreatedAt); err != nil {
+	if err := row.Scan(&snap.RoomID, &snap.LastSeq, &snap.Phase, &snap.StateJSON, &snap.CreatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -53,7 +58,7 @@ func (s *Store) GetLatestSnapshot(ctx context.Context, roomID string) (*Snapshot
 }
 
 func (s *Store) SaveSnapshot(ctx context.Context, tx *sql.Tx, snap Snapshot) error {
-	_, err := tx.ExecContext(ctx, `INSERT INTO snapshots (room_id,last_seq,state_json,created_at) VALUES (?,?,?,?)`, snap.RoomID, snap.LastSeq, snap.StateJSON, snap.CreatedAt)
+	_, err := tx.ExecContext(ctx, `INSERT INTO snapshots (room_id,last_seq,phase,state_json,created_at) VALUES (?,?,?,?,?)`, snap.RoomID, snap.LastSeq, snap.Phase, snap.StateJSON, snap.CreatedAt)
 	return err
 }
 
@@ -61,7 +66,7 @@ func (s *Store) LoadEventsAfter(ctx context.Context, roomID string, afterSeq int
 	if limit <= 0 {
 		limit = 200
 	}
-	rows, err := s.DB.QueryContext(ctx, `SELECT room_id,seq,event_id,event_type,actor_user_id,causation_command_id,payload_json,server_ts FROM events WHERE room_id=? AND seq>? ORDER BY seq ASC LIMIT ?`, roomID, afterSeq, limit)
+	rows, err := s.DB.QueryContext(ctx, `SELECT room_id,seq,event_id,event_type,actor_user_id,causation_command_id,payload_json,server_ts,correlation_id,prev_hash,hash FROM events WHERE room_id=? AND seq>? ORDER BY seq ASC LIMIT ?`, roomID, afterSeq, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -69,11 +74,13 @@ func (s *Store) LoadEventsAfter(ctx context.Context, roomID string, afterSeq int
 	var res []StoredEvent
 	for rows.Next() {
 		var e StoredEvent
-		var causation sql.NullString // FIX-18: handle NULL causation_command_id
-		if err := rows.Scan(&e.RoomID, &e.Seq, &e.EventID, &e.EventType, &e.ActorUserID, &causation, &e.PayloadJSON, &e.ServerTime); err != nil {
+		var causation, correlation, prevHash, hash sql.NullString // FIX-18: handle NULL causation_command_id
+		if err := rows.Scan(&e.RoomID, &e.Seq, &e.EventID, &e.EventType, &e.ActorUserID, &causation, &e.PayloadJSON, &e.ServerTime, &correlation, &prevHash, &hash); err != nil {
 			return nil, err
 		}
 		e.CausationCommand = causation.String
+		e.CorrelationID = correlation.String
+		e.PrevHash, e.Hash = prevHash.String, hash.String
 		res = append(res, e)
 	}
 	return res, rows.Err()
@@ -87,12 +94,12 @@ func (s *Store) LoadEventsUpTo(ctx context.Context, roomID string, toSeq int64)
 
 	if toSeq > 0 {
 		rows, err = s.DB.QueryContext(ctx,
-			`SELECT room_id,seq,event_id,event_type,actor_user_id,causation_command_id,payload_json,server_ts
+			`SELECT room_id,seq,event_id,event_type,actor_user_id,causation_command_id,payload_json,server_ts,correlation_id,prev_hash,hash
 			 FROM events WHERE room_id=? AND seq<=? ORDER BY seq ASC`,
 			roomID, toSeq)
 	} else {
 		rows, err = s.DB.QueryContext(ctx,
-			`SELECT room_id,seq,event_id,event_type,actor_user_id,causation_command_id,payload_json,server_ts
+			`SELECT room_id,seq,event_id,event_type,actor_user_id,causation_command_id,payload_json,server_ts,correlation_id,prev_hash,hash
 			 FROM events WHERE room_id=? ORDER BY seq ASC`,
 			roomID)
 	}
@@ -104,11 +111,13 @@ func (s *Store) LoadEventsUpTo(ctx context.Context, roomID string, toSeq int64)
 	var res []StoredEvent
 	for rows.Next() {
 		var e StoredEvent
-		var causation sql.NullString // FIX-18: handle NULL causation_command_id
-		if err := rows.Scan(&e.RoomID, &e.Seq, &e.EventID, &e.EventType, &e.ActorUserID, &causation, &e.PayloadJSON, &e.ServerTime); err != nil {
+		var causation, correlation, prevHash, hash sql.NullString // FIX-18: handle NULL causation_command_id
+		if err := rows.Scan(&e.RoomID, &e.Seq, &e.EventID, &e.EventType, &e.ActorUserID, &causation, &e.PayloadJSON, &e.ServerTime, &correlation, &prevHash, &hash); err != nil {
 			return nil, err
 		}
 		e.CausationCommand = causation.String
+		e.CorrelationID = correlation.String
+		e.PrevHash, e.Hash = prevHash.String, hash.String
 		res = append(res, e)
 	}
 	return res, rows.Err()
@@ -128,6 +137,9 @@ func (s *Store) AppendEvents(ctx context.Context, roomID string, events []Stored
 		default:
 			return err
 		}
+		if err := checkSeqStart(events, current); err != nil {
+			return err
+		}
 
 		for i := range events {
 			events[i].Seq = current + int64(i)
@@ -138,9 +150,9 @@ func (s *Store) AppendEvents(ctx context.Context, roomID string, events []Stored
 		}
 
 		for _, e := range events {
-			if _, err := tx.ExecContext(ctx, `INSERT INTO events (room_id,seq,event_id,event_type,actor_user_id,causation_command_id,payload_json,server_ts) VALUES (?,?,?,?,?,?,?,?)`,
-				e.RoomID, e.Seq, e.EventID, e.EventType, e.ActorUserID, e.CausationCommand, e.PayloadJSON, e.ServerTime); err != nil {
-				return err
+			if _, err := tx.ExecContext(ctx, `INSERT INTO events (room_id,seq,event_id,event_type,actor_user_id,causation_command_id,payload_json,server_ts,correlation_id,prev_hash,hash) VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
+				e.RoomID, e.Seq, e.EventID, e.EventType, e.ActorUserID, e.CausationCommand, e.PayloadJSON, e.ServerTime, e.CorrelationID, nullIfEmpty(e.PrevHash), nullIfEmpty(e.Hash)); err != nil {
+				return asSeqConflict(err)
 			}
 		}
 
@@ -0,0 +1,30 @@
+// Package store AutoDM 记忆持久化
+//
+// [OUT] cmd/server（memory.Store 适配器）
+// [POS] 记忆存储层，关停时批量写入短期记忆
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SaveMemoryEntries 在单个事务中批量写入记忆；重复 ID 会被忽略，Flush 重试安全。
+func (s *Store) SaveMemoryEntries(ctx context.Context, entries []MemoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return s.WithTx(ctx, func(tx *sql.Tx) error {
+		for _, e := range entries {
+			_, err := tx.ExecContext(ctx,
+				`INSERT IGNORE INTO agent_memory (id,room_id,entry_type,content,phase,day_number,tags,created_at) VALUES (?,?,?,?,?,?,?,?)`,
+				e.ID, e.RoomID, e.EntryType, e.Content, e.Phase, e.DayNumber, e.Tags, e.CreatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("store.SaveMemoryEntries: %w", err)
+			}
+		}
+		return nil
+	})
+}
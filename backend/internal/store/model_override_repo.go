@@ -0,0 +1,53 @@
+// Package store 按房间的 AutoDM 模型覆盖持久化
+//
+// autodm_model_overrides 每房间一行，按 room_id 覆盖写入；启动时 ListModelOverrides 全量恢复。
+// 依赖迁移 007。
+//
+// [OUT] api（PUT/DELETE /v1/rooms/{room_id}/autodm/model）
+// [OUT] cmd/server（启动时恢复覆盖）
+// [POS] AutoDM 运行时配置的存储层
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// SaveModelOverride inserts or replaces the room's override.
+func (s *Store) SaveModelOverride(ctx context.Context, o ModelOverride) error {
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT INTO autodm_model_overrides (room_id, provider, model, base_url, updated_by) VALUES (?,?,?,?,?)
+		 ON DUPLICATE KEY UPDATE provider=VALUES(provider), model=VALUES(model), base_url=VALUES(base_url), updated_by=VALUES(updated_by)`,
+		o.RoomID, o.Provider, o.Model, o.BaseURL, o.UpdatedBy)
+	if err != nil {
+		return fmt.Errorf("store.SaveModelOverride: %w", err)
+	}
+	return nil
+}
+
+// DeleteModelOverride removes the room's override; a missing row is not an error.
+func (s *Store) DeleteModelOverride(ctx context.Context, roomID string) error {
+	if _, err := s.DB.ExecContext(ctx, `DELETE FROM autodm_model_overrides WHERE room_id=?`, roomID); err != nil {
+		return fmt.Errorf("store.DeleteModelOverride: %w", err)
+	}
+	return nil
+}
+
+// ListModelOverrides returns every saved override.
+func (s *Store) ListModelOverrides(ctx context.Context) ([]ModelOverride, error) {
+	rows, err := s.DB.QueryContext(ctx, `SELECT room_id, provider, model, base_url, updated_by FROM autodm_model_overrides`)
+	if err != nil {
+		return nil, fmt.Errorf("store.ListModelOverrides: %w", err)
+	}
+	defer rows.Close()
+
+	res := []ModelOverride{}
+	for rows.Next() {
+		var o ModelOverride
+		if err := rows.Scan(&o.RoomID, &o.Provider, &o.Model, &o.BaseURL, &o.UpdatedBy); err != nil {
+			return nil, fmt.Errorf("store.ListModelOverrides: %w", err)
+		}
+		res = append(res, o)
+	}
+	return res, rows.Err()
+}
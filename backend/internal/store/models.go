@@ -1,4 +1,4 @@
-// Package store 数据模型定义：User、Room、RoomMember、DedupRecord、Snapshot、AgentRun
+// Package store 数据模型定义：User、Room、RoomMember、DedupRecord、Snapshot、AgentRun、AgentToolCall
 //
 // [OUT] api（用户与房间查询）
 // [OUT] realtime（事件加载）
@@ -48,6 +48,9 @@ type Snapshot struct {
 	LastSeq   int64
 	StateJSON string
 	CreatedAt time.Time
+
+	// Phase is the game phase the snapshot was taken in (migration 009)
+	Phase string
 }
 
 type AgentRun struct {
@@ -63,4 +66,49 @@ type AgentRun struct {
 	LatencyMs    int64
 	ErrorText    string
 	CreatedAt    time.Time
+
+	// PlanJSON is what the run decided to do (migration 006)
+	PlanJSON string
+}
+
+// AgentToolCall is one audited AutoDM tool call (agent_tool_calls, migration 006).
+type AgentToolCall struct {
+	ID         string
+	RunID      string
+	RoomID     string
+	ToolName   string
+	ArgsJSON   string
+	ResultJSON string
+	ErrorText  string
+	DurationMs int64
+	CreatedAt  time.Time
+}
+
+// ModelOverride is a room's AutoDM model override (autodm_model_overrides, migration 007).
+type ModelOverride struct {
+	RoomID    string
+	Provider  string // legacy column; overrides no longer set a provider (it follows BaseURL)
+	Model     string
+	BaseURL   string
+	UpdatedBy string
+}
+
+// StorytellerNote is a DM's private note for a room (storyteller_notes, migration 010).
+type StorytellerNote struct {
+	ID        string    `json:"id"`
+	RoomID    string    `json:"room_id"`
+	AuthorID  string    `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MemoryEntry struct {
+	ID        string
+	RoomID    string
+	EntryType string
+	Content   string
+	Phase     string
+	DayNumber int
+	Tags      string
+	CreatedAt time.Time
 }
@@ -0,0 +1,94 @@
+// Package store 事件保留期清理
+//
+// 已结束房间 (最后一条 game.ended 早于截止时间) 的事件、快照与说书人笔记按房间事务删除，
+// 可选写入一份最终快照用于归档。清理后 game.ended 不复存在，房间不会被重复选中。
+// 同一事务内把 room_sequences 与剩下的内容对齐：保留终局快照时 next_seq 接在快照之后，
+// 并把最后一个事件的 Hash 记为 chain_head (迁移 011)，之后的事件据此接续哈希链；
+// 不保留快照时房间从空日志重新开始 (next_seq=1，链头为空)。终局快照落后于房间序号
+// (清理期间房间又追加了事件) 时返回 ErrSeqConflict，本次不清理。
+//
+// [OUT] room（RetentionPurger 后台清理任务）
+// [POS] 事件存储层的保留期管理
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ListEndedRoomsBefore returns up to limit rooms whose game ended before cutoff.
+func (s *Store) ListEndedRoomsBefore(ctx context.Context, cutoff time.Time, limit int) ([]string, error) {
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT room_id FROM events WHERE event_type='game.ended'
+		 GROUP BY room_id HAVING MAX(server_ts) < ? ORDER BY MAX(server_ts) ASC LIMIT ?`,
+		cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("store.ListEndedRoomsBefore: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("store.ListEndedRoomsBefore: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// PurgeRoom deletes a room's events, snapshots and storyteller notes in one transaction and, when
+// final is non-nil, stores it as the room's only snapshot. The room's sequence and
+// chain head are reset to match what remains. Returns deleted rows.
+func (s *Store) PurgeRoom(ctx context.Context, roomID string, final *Snapshot) (int64, error) {
+	var purged int64
+	err := s.WithTx(ctx, func(tx *sql.Tx) error {
+		var next int64
+		switch err := tx.QueryRowContext(ctx, `SELECT next_seq FROM room_sequences WHERE room_id=? FOR UPDATE`, roomID).Scan(&next); err {
+		case nil, sql.ErrNoRows:
+		default:
+			return err
+		}
+		if final != nil && next != 0 && next != final.LastSeq+1 {
+			return fmt.Errorf("final snapshot at seq %d, room at %d: %w", final.LastSeq, next-1, ErrSeqConflict)
+		}
+		var head sql.NullString
+		if final != nil {
+			err := tx.QueryRowContext(ctx, `SELECT hash FROM events WHERE room_id=? ORDER BY seq DESC LIMIT 1`, roomID).Scan(&head)
+			if err != nil && err != sql.ErrNoRows {
+				return err
+			}
+		}
+		for _, q := range []string{
+			`DELETE FROM events WHERE room_id=?`,
+			`DELETE FROM snapshots WHERE room_id=?`,
+			`DELETE FROM storyteller_notes WHERE room_id=?`,
+		} {
+			res, err := tx.ExecContext(ctx, q, roomID)
+			if err != nil {
+				return err
+			}
+			n, _ := res.RowsAffected()
+			purged += n
+		}
+		next = 1
+		if final != nil {
+			if err := s.SaveSnapshot(ctx, tx, *final); err != nil {
+				return err
+			}
+			next = final.LastSeq + 1
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO room_sequences (room_id,next_seq,chain_head) VALUES (?,?,?)
+			 ON DUPLICATE KEY UPDATE next_seq=VALUES(next_seq), chain_head=VALUES(chain_head)`,
+			roomID, next, head)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("store.PurgeRoom: %w", err)
+	}
+	return purged, nil
+}
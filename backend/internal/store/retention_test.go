@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// appendEndedRoom appends a hash-chained two-event game and returns the chain head.
+func appendEndedRoom(t *testing.T, st *Store, roomID string, endedAt time.Time) string {
+	t.Helper()
+	var events []StoredEvent
+	for i, eventType := range []string{"game.started", "game.ended"} {
+		events = append(events, StoredEvent{
+			RoomID:      roomID,
+			Seq:         int64(i + 1),
+			EventID:     uuid.NewString(),
+			EventType:   eventType,
+			ActorUserID: "user-1",
+			PayloadJSON: `{}`,
+			ServerTime:  endedAt,
+		})
+	}
+	head := ChainEvents(events, "")
+	if err := st.AppendEvents(context.Background(), roomID, events, nil, nil); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	return head
+}
+
+// appendAfterPurge appends one event at seq chained from prevHash.
+func appendAfterPurge(st *Store, roomID string, seq int64, prevHash string) error {
+	events := []StoredEvent{{RoomID: roomID, Seq: seq, EventID: uuid.NewString(), EventType: "room.settings.changed", ActorUserID: "user-1", PayloadJSON: `{}`, ServerTime: time.Now().UTC()}}
+	ChainEvents(events, prevHash)
+	return st.AppendEvents(context.Background(), roomID, events, nil, nil)
+}
+
+func TestPurgeRoomRemovesOldEndedRoomOnly(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	oldRoom, freshRoom := uuid.NewString(), uuid.NewString()
+	appendEndedRoom(t, st, oldRoom, now.Add(-90*24*time.Hour))
+	appendEndedRoom(t, st, freshRoom, now)
+
+	ids, err := st.ListEndedRoomsBefore(ctx, now.Add(-30*24*time.Hour), 1000)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var sawOld bool
+	for _, id := range ids {
+		if id == freshRoom {
+			t.Fatalf("fresh room must not be listed")
+		}
+		sawOld = sawOld || id == oldRoom
+	}
+	if !sawOld {
+		t.Fatalf("old room not listed: %v", ids)
+	}
+
+	note := StorytellerNote{ID: uuid.NewString(), RoomID: oldRoom, AuthorID: "dm", Body: "imp is seat 5", CreatedAt: now}
+	if err := st.SaveStorytellerNote(ctx, note); err != nil {
+		t.Fatalf("save note: %v", err)
+	}
+	final := &Snapshot{RoomID: oldRoom, LastSeq: 2, StateJSON: `{}`, CreatedAt: now}
+	purged, err := st.PurgeRoom(ctx, oldRoom, final)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if purged != 3 {
+		t.Fatalf("expected 3 purged rows, got %d", purged)
+	}
+	if notes, _ := st.ListStorytellerNotes(ctx, oldRoom); len(notes) != 0 {
+		t.Fatalf("old room notes remain: %d", len(notes))
+	}
+	if left, _ := st.LoadEventsAfter(ctx, oldRoom, 0, 0); len(left) != 0 {
+		t.Fatalf("old room events remain: %d", len(left))
+	}
+	if kept, _ := st.LoadEventsAfter(ctx, freshRoom, 0, 0); len(kept) != 2 {
+		t.Fatalf("fresh room events lost: %d", len(kept))
+	}
+	if snap, err := st.GetLatestSnapshot(ctx, oldRoom); err != nil || snap == nil || snap.LastSeq != 2 {
+		t.Fatalf("expected final snapshot at seq 2, got %+v (%v)", snap, err)
+	}
+}
+
+func TestPurgeRoomKeepsSequenceAndChainHeadConsistent(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	endedAt := time.Now().UTC().Add(-90 * 24 * time.Hour)
+
+	archived := uuid.NewString()
+	head := appendEndedRoom(t, st, archived, endedAt)
+	if _, err := st.PurgeRoom(ctx, archived, &Snapshot{RoomID: archived, LastSeq: 1, StateJSON: `{}`}); !errors.Is(err, ErrSeqConflict) {
+		t.Fatalf("expected a snapshot behind the room to be refused, got %v", err)
+	}
+	if _, err := st.PurgeRoom(ctx, archived, &Snapshot{RoomID: archived, LastSeq: 2, StateJSON: `{}`}); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if got, err := st.LastEventHash(ctx, archived); err != nil || got != head {
+		t.Fatalf("expected the chain head kept across the purge, got %q (%v)", got, err)
+	}
+	if err := appendAfterPurge(st, archived, 3, head); err != nil {
+		t.Fatalf("expected the archived room to continue at seq 3: %v", err)
+	}
+
+	wiped := uuid.NewString()
+	appendEndedRoom(t, st, wiped, endedAt)
+	if _, err := st.PurgeRoom(ctx, wiped, nil); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if got, err := st.LastEventHash(ctx, wiped); err != nil || got != "" {
+		t.Fatalf("expected a wiped room to start a new chain, got %q (%v)", got, err)
+	}
+	if err := appendAfterPurge(st, wiped, 1, ""); err != nil {
+		t.Fatalf("expected a wiped room to start over at seq 1: %v", err)
+	}
+}
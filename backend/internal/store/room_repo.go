@@ -31,9 +31,10 @@ func (s *Store) GetRoom(ctx context.Context, id string) (*Room, error) {
 	return &r, nil
 }
 
+// AddRoomMember inserts a membership; an existing one keeps its role and join time, so re-joins are no-ops.
 func (s *Store) AddRoomMember(ctx context.Context, m RoomMember) error {
 	_, err := s.DB.ExecContext(ctx,
-		`INSERT INTO room_members (room_id,user_id,role,joined_at) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE role=VALUES(role)`,
+		`INSERT INTO room_members (room_id,user_id,role,joined_at) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE role=role`,
 		m.RoomID, m.UserID, m.Role, m.Joined,
 	)
 	return err
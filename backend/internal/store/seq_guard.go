@@ -0,0 +1,44 @@
+// Package store 房间事件序号守卫
+//
+// 房间 Actor 是序号的唯一分配者：它按本地 LastSeq 为事件编号后交给 AppendEvents。
+// 存储层在 room_sequences 行锁内核对首个序号，并依赖 events 主键 (room_id, seq)
+// 拒绝重复写入，二者任一不符都返回 ErrSeqConflict，保证每房间序号严格递增且无空洞。
+//
+// [OUT] event_store.go（AppendEvents 序号校验）
+// [OUT] room（识别并发写入冲突）
+// [POS] 事件存储层的并发写入保护
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrSeqConflict means the appended events do not continue the room's sequence,
+// i.e. another writer already appended events for the room.
+var ErrSeqConflict = errors.New("store: room sequence conflict")
+
+// mysqlDuplicateEntry is MySQL's ER_DUP_ENTRY error number.
+const mysqlDuplicateEntry = 1062
+
+// checkSeqStart verifies caller-assigned seqs start at next. Events without a seq are accepted
+// and numbered by the store.
+func checkSeqStart(events []StoredEvent, next int64) error {
+	if len(events) == 0 || events[0].Seq == 0 || events[0].Seq == next {
+		return nil
+	}
+	return fmt.Errorf("store.AppendEvents: expected seq %d, got %d: %w", next, events[0].Seq, ErrSeqConflict)
+}
+
+// asSeqConflict maps a duplicate (room_id, seq) insert to ErrSeqConflict; other duplicates
+// (e.g. a reused event_id) are real errors, not another writer.
+func asSeqConflict(err error) error {
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) && myErr.Number == mysqlDuplicateEntry && strings.Contains(myErr.Message, "PRIMARY") {
+		return fmt.Errorf("store.AppendEvents: %v: %w", err, ErrSeqConflict)
+	}
+	return fmt.Errorf("store.AppendEvents: %w", err)
+}
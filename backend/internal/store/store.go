@@ -9,6 +9,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
@@ -22,21 +23,63 @@ func New(db *sql.DB) *Store {
 	return &Store{DB: db}
 }
 
+// PoolConfig sizes the MySQL connection pool; zero fields keep DefaultPoolConfig values.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnectTimeout  time.Duration // dial timeout, unless the DSN sets timeout= itself
+}
+
+// DefaultPoolConfig is the pool ConnectMySQL uses.
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{MaxOpenConns: 20, MaxIdleConns: 10, ConnMaxLifetime: 5 * time.Minute, ConnectTimeout: 10 * time.Second}
+}
+
 func ConnectMySQL(dsn string) (*sql.DB, error) {
+	return ConnectMySQLPool(dsn, DefaultPoolConfig())
+}
+
+// ConnectMySQLPool opens dsn with the given pool sizing and connect timeout.
+func ConnectMySQLPool(dsn string, pool PoolConfig) (*sql.DB, error) {
+	pool = pool.withDefaults()
 	cfg, err := mysql.ParseDSN(dsn)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("store.ConnectMySQLPool: %w", err)
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = pool.ConnectTimeout
 	}
 	db, err := sql.Open("mysql", cfg.FormatDSN())
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("store.ConnectMySQLPool: %w", err)
 	}
-	db.SetMaxOpenConns(20)
-	db.SetMaxIdleConns(10)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
 	return db, nil
 }
 
+func (p PoolConfig) withDefaults() PoolConfig {
+	def := DefaultPoolConfig()
+	if p.MaxOpenConns <= 0 {
+		p.MaxOpenConns = def.MaxOpenConns
+	}
+	if p.MaxIdleConns <= 0 {
+		p.MaxIdleConns = def.MaxIdleConns
+	}
+	if p.MaxIdleConns > p.MaxOpenConns {
+		p.MaxIdleConns = p.MaxOpenConns
+	}
+	if p.ConnMaxLifetime <= 0 {
+		p.ConnMaxLifetime = def.ConnMaxLifetime
+	}
+	if p.ConnectTimeout <= 0 {
+		p.ConnectTimeout = def.ConnectTimeout
+	}
+	return p
+}
+
 func (s *Store) WithTx(ctx context.Context, fn func(*sql.Tx) error) error {
 	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
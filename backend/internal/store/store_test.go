@@ -0,0 +1,30 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConnectMySQLPoolAppliesPoolConfig(t *testing.T) {
+	db, err := ConnectMySQLPool("u:p@tcp(127.0.0.1:1)/agentdm?parseTime=true", PoolConfig{
+		MaxOpenConns:    7,
+		MaxIdleConns:    3,
+		ConnMaxLifetime: time.Minute,
+		ConnectTimeout:  time.Second,
+	})
+	if err != nil {
+		t.Fatalf("connect: %v", err) // sql.Open does not dial, so no server is needed
+	}
+	defer db.Close()
+	if got := db.Stats().MaxOpenConnections; got != 7 {
+		t.Fatalf("expected MaxOpenConns 7 on the *sql.DB, got %d", got)
+	}
+}
+
+func TestPoolConfigDefaultsAndClampsIdle(t *testing.T) {
+	got := PoolConfig{MaxOpenConns: 4, MaxIdleConns: 9}.withDefaults()
+	def := DefaultPoolConfig()
+	if got.MaxIdleConns != 4 || got.ConnMaxLifetime != def.ConnMaxLifetime || got.ConnectTimeout != def.ConnectTimeout {
+		t.Fatalf("expected idle clamped to open and defaults filled, got %+v", got)
+	}
+}
@@ -0,0 +1,44 @@
+// Package store 说书人笔记持久化
+//
+// storyteller_notes 保存 DM 按房间的自由文本笔记，与事件流完全分离 (不参与回放、不投影)。
+// 依赖迁移 010。
+//
+// [OUT] api（POST/GET /v1/rooms/{room_id}/notes）
+// [POS] DM 私有草稿的存储层
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// SaveStorytellerNote inserts a note.
+func (s *Store) SaveStorytellerNote(ctx context.Context, n StorytellerNote) error {
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT INTO storyteller_notes (id, room_id, author_id, body, created_at) VALUES (?,?,?,?,?)`,
+		n.ID, n.RoomID, n.AuthorID, n.Body, n.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("store.SaveStorytellerNote: %w", err)
+	}
+	return nil
+}
+
+// ListStorytellerNotes returns the room's notes, oldest first.
+func (s *Store) ListStorytellerNotes(ctx context.Context, roomID string) ([]StorytellerNote, error) {
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT id, room_id, author_id, body, created_at FROM storyteller_notes WHERE room_id=? ORDER BY created_at, id`, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("store.ListStorytellerNotes: %w", err)
+	}
+	defer rows.Close()
+
+	res := []StorytellerNote{}
+	for rows.Next() {
+		var n StorytellerNote
+		if err := rows.Scan(&n.ID, &n.RoomID, &n.AuthorID, &n.Body, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("store.ListStorytellerNotes: %w", err)
+		}
+		res = append(res, n)
+	}
+	return res, rows.Err()
+}
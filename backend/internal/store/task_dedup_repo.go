@@ -0,0 +1,37 @@
+// Package store AutoDM 异步任务去重键
+//
+// autodm_task_dedup 以 "房间:事件" 为主键、带过期时间：ClaimTaskKey 先清理少量过期键，
+// 再 INSERT IGNORE，插入成功即认领。同一事件重复 OnEvent 时后来者认领失败、不再入队。
+// 依赖迁移 008。
+//
+// [OUT] agent（Config.TaskDedup，publishAsyncTask 入队前认领）
+// [POS] AutoDM 任务队列的跨实例去重存储
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// expiredTaskKeyBatch bounds how many expired keys one claim cleans up.
+const expiredTaskKeyBatch = 100
+
+// ClaimTaskKey records key for ttl; it returns false when an unexpired claim already exists.
+func (s *Store) ClaimTaskKey(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	if _, err := s.DB.ExecContext(ctx,
+		`DELETE FROM autodm_task_dedup WHERE expires_at < ? LIMIT ?`, now, expiredTaskKeyBatch); err != nil {
+		return false, fmt.Errorf("store.ClaimTaskKey: %w", err)
+	}
+	res, err := s.DB.ExecContext(ctx,
+		`INSERT IGNORE INTO autodm_task_dedup (dedup_key, expires_at) VALUES (?, ?)`, key, now.Add(ttl))
+	if err != nil {
+		return false, fmt.Errorf("store.ClaimTaskKey: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("store.ClaimTaskKey: %w", err)
+	}
+	return n == 1, nil
+}
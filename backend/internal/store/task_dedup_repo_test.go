@@ -0,0 +1,32 @@
+//go:build integration
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestClaimTaskKeyOnlyOnceUntilExpiry(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	key := "room-1:" + uuid.NewString()
+
+	if ok, err := st.ClaimTaskKey(ctx, key, time.Minute); err != nil || !ok {
+		t.Fatalf("expected the first claim to succeed, got %v %v", ok, err)
+	}
+	if ok, err := st.ClaimTaskKey(ctx, key, time.Minute); err != nil || ok {
+		t.Fatalf("expected a second claim to be refused, got %v %v", ok, err)
+	}
+
+	expired := "room-1:" + uuid.NewString()
+	if _, err := st.ClaimTaskKey(ctx, expired, -time.Second); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if ok, err := st.ClaimTaskKey(ctx, expired, time.Minute); err != nil || !ok {
+		t.Fatalf("expected an expired key to be claimable again, got %v %v", ok, err)
+	}
+}
@@ -72,6 +72,8 @@ type CommandEnvelope struct {
 	LastSeenSeq    int64           `json:"last_seen_seq"`
 	ActorUserID    string          `json:"actor_user_id"`
 	Payload        json.RawMessage `json:"data"`
+	// CorrelationID groups every event a command produces; generated when empty.
+	CorrelationID string `json:"correlation_id,omitempty"`
 }
 
 type Event struct {
@@ -83,6 +85,7 @@ type Event struct {
 	CausationCommand  string          `json:"causation_command_id"`
 	Payload           json.RawMessage `json:"payload"`
 	ServerTimestampMs int64           `json:"server_ts_ms"`
+	CorrelationID     string          `json:"correlation_id,omitempty"`
 }
 
 type CommandResult struct {
@@ -107,3 +110,11 @@ type Viewer struct {
 	Role   string
 	IsDM   bool
 }
+
+// AutoDMActorID is the actor id the Auto-DM issues commands under; "auto-dm" is a legacy spelling.
+const AutoDMActorID = "autodm"
+
+// IsAutoDMActor reports whether id is either Auto-DM actor id variant.
+func IsAutoDMActor(id string) bool {
+	return id == AutoDMActorID || id == "auto-dm"
+}
@@ -19,7 +19,7 @@ import (
 func main() {
 	// Parse command line flags
 	var (
-		scenario             = flag.String("scenario", "", "Specific scenario to run (S1-S11), empty for all")
+		scenario             = flag.String("scenario", "", "Specific scenario to run (S1-S12, S11L), empty for all")
 		users                = flag.Int("users", 10, "Number of concurrent users")
 		duration             = flag.Duration("duration", 30*time.Second, "Test duration")
 		target               = flag.String("target", "http://localhost:8080", "Target HTTP server")
@@ -30,6 +30,12 @@ func main() {
 		geminiMaxConcurrency = flag.Int("gemini-max-concurrency", 5, "Max concurrent Gemini requests")
 		geminiRPSLimit       = flag.Int("gemini-rps-limit", 10, "Gemini requests per second limit")
 		geminiRequestBudget  = flag.Int("gemini-request-budget", 100, "Total Gemini request budget")
+		httpMaxConns         = flag.Int("http-max-conns", 100, "Max pooled HTTP connections to the target shared by all users (0 = unlimited)")
+		seed                 = flag.Int64("seed", 0, "Deterministic seed for keys and client behavior (0 = time-based)")
+		llmFaultRate         = flag.Float64("llm-fault-rate", 0.5, "S11L: share of LLM calls the fault proxy fails (0-1)")
+		llmFaultListen       = flag.String("llm-fault-listen", ":18090", "S11L: fault proxy listen address")
+		llmFaultURL          = flag.String("llm-fault-url", "http://localhost:18090", "S11L: fault proxy URL as seen by the server (must be in AUTODM_BASE_URL_ALLOWLIST)")
+		llmFaultUpstream     = flag.String("llm-fault-upstream", "", "S11L: real LLM base URL (default: the server's)")
 	)
 	flag.Parse()
 
@@ -46,6 +52,12 @@ func main() {
 		Users:                envIntOrDefault("LOADTEST_USERS", *users),
 		Duration:             envDurationOrDefault("LOADTEST_DURATION", *duration),
 		Verbose:              *verbose,
+		HTTPMaxConnsPerHost:  envIntOrDefault("LOADTEST_HTTP_MAX_CONNS", *httpMaxConns),
+		Seed:                 int64(envIntOrDefault("LOADTEST_SEED", int(*seed))),
+		LLMFaultRate:         *llmFaultRate,
+		LLMFaultListen:       *llmFaultListen,
+		LLMFaultProxyURL:     envOrDefault("LOADTEST_LLM_FAULT_URL", *llmFaultURL),
+		LLMFaultUpstream:     *llmFaultUpstream,
 		GeminiMaxConcurrency: envIntOrDefault("GEMINI_MAX_CONCURRENCY", *geminiMaxConcurrency),
 		GeminiRPSLimit:       envIntOrDefault("GEMINI_RPS_LIMIT", *geminiRPSLimit),
 		GeminiRequestBudget:  int64(envIntOrDefault("GEMINI_REQUEST_BUDGET", *geminiRequestBudget)),
@@ -89,6 +101,9 @@ func main() {
 	log.Printf("  Duration: %s", cfg.Duration)
 	log.Printf("  Scenarios: %v", scenarios)
 	log.Printf("  Gemini Budget: %d requests", cfg.GeminiRequestBudget)
+	if cfg.Seed != 0 {
+		log.Printf("  Seed: %d", cfg.Seed)
+	}
 	log.Println()
 
 	// Run scenarios
@@ -103,6 +118,7 @@ func main() {
 	report := loadgen.Report{
 		Timestamp: time.Now().UTC(),
 		Target:    cfg.TargetHTTP,
+		Seed:      cfg.Seed,
 		Scenarios: results,
 		Summary:   buildSummary(results, totalDuration, runner.GetGeminiStats()),
 	}
@@ -178,6 +194,14 @@ func printSummary(report loadgen.Report) {
 			status = "❌ FAIL"
 		}
 		log.Printf("  %s: %s (%dms)", r.Scenario, status, r.DurationMs)
+		if r.Latency != nil {
+			printLatency("command", r.Latency.Command)
+			printLatency("ws rtt", r.Latency.WSRoundTrip)
+		}
+		if r.Sockets != nil {
+			log.Printf("    sockets  http peak=%d opened=%d, ws peak=%d opened=%d still open=%d",
+				r.Sockets.HTTP.Peak, r.Sockets.HTTP.Opened, r.Sockets.WS.Peak, r.Sockets.WS.Opened, r.Sockets.WS.Open)
+		}
 		if len(r.Errors) > 0 {
 			for _, e := range r.Errors {
 				log.Printf("    - Error: %s", e)
@@ -194,6 +218,13 @@ func printSummary(report loadgen.Report) {
 	log.Println(strings.Repeat("=", 60))
 }
 
+func printLatency(label string, p loadgen.LatencyPercentiles) {
+	if p.Count == 0 {
+		return
+	}
+	log.Printf("    %-8s p50=%.1fms p95=%.1fms p99=%.1fms (n=%d)", label, p.P50Ms, p.P95Ms, p.P99Ms, p.Count)
+}
+
 func printScenarios() {
 	fmt.Println("Available Load Test Scenarios:")
 	fmt.Println()
@@ -213,6 +244,8 @@ func printScenarios() {
 		{"S9", "RabbitMQ DLQ Monitoring", "Verify DLQ message count on failures"},
 		{"S10", "Full Game Flow", "Lobby → Night → Day → Vote → End"},
 		{"S11", "Chaos Test", "Random disconnects and commands"},
+		{"S11L", "LLM Failure Chaos", "S11 variant: fault proxy fails LLM calls, game must finish on fallbacks"},
+		{"S12", "Bots Full Game", "Bots fill a room, AutoDM runs the game to game.ended"},
 	}
 
 	for _, s := range scenarios {
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"time"
 )
@@ -14,23 +15,36 @@ import (
 type HTTPClient struct {
 	baseURL string
 	client  *http.Client
+	conns   *connTracker
 }
 
-// NewHTTPClient creates a new HTTP client.
-func NewHTTPClient(baseURL string) (*HTTPClient, error) {
+// NewHTTPClient creates a new HTTP client. All users share one pooled
+// transport; maxConnsPerHost caps its sockets (0 = unlimited) so large
+// user counts queue for a connection instead of exhausting sockets.
+func NewHTTPClient(baseURL string, maxConnsPerHost int) (*HTTPClient, error) {
+	conns := &connTracker{}
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
 	return &HTTPClient{
 		baseURL: baseURL,
+		conns:   conns,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 			Transport: &http.Transport{
+				DialContext:         conns.dialContext(dialer),
 				MaxIdleConns:        100,
 				MaxIdleConnsPerHost: 100,
+				MaxConnsPerHost:     maxConnsPerHost,
 				IdleConnTimeout:     90 * time.Second,
 			},
 		},
 	}, nil
 }
 
+// SocketStats returns the HTTP transport's socket counts.
+func (c *HTTPClient) SocketStats() SocketStats {
+	return c.conns.stats()
+}
+
 // RegisterResponse is the response from user registration.
 type RegisterResponse struct {
 	UserID string `json:"user_id"`
@@ -72,6 +86,21 @@ type EventsResponse struct {
 	Events []EventResponse `json:"events"`
 }
 
+// AddBotsResponse is the response from adding bots.
+type AddBotsResponse struct {
+	BotIDs []string `json:"bot_ids"`
+	Count  int      `json:"count"`
+}
+
+// LLMHealthResponse is the response from the LLM health check.
+type LLMHealthResponse struct {
+	Status   string `json:"status"`
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	BaseURL  string `json:"base_url"`
+	Enabled  bool   `json:"enabled"`
+}
+
 // HealthResponse is the response from health check.
 type HealthResponse struct {
 	Status string `json:"status"`
@@ -179,6 +208,53 @@ func (c *HTTPClient) JoinRoom(ctx context.Context, token, roomID string) error {
 	return nil
 }
 
+// AddBots fills a room with bot players up to targetTotal.
+func (c *HTTPClient) AddBots(ctx context.Context, token, roomID string, targetTotal int) (*AddBotsResponse, error) {
+	headers := map[string]string{
+		"Authorization": "Bearer " + token,
+	}
+
+	body := map[string]int{"target_total": targetTotal}
+	resp, err := c.doJSON(ctx, "POST", fmt.Sprintf("/v1/rooms/%s/bots", roomID), headers, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("add bots failed: %d - %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result AddBotsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// SetAutoDMModel overrides the room's AutoDM model and base URL (DM only).
+func (c *HTTPClient) SetAutoDMModel(ctx context.Context, token, roomID, model, baseURL string) error {
+	headers := map[string]string{
+		"Authorization": "Bearer " + token,
+	}
+
+	body := map[string]string{"model": model, "base_url": baseURL}
+	resp, err := c.doJSON(ctx, "PUT", fmt.Sprintf("/v1/rooms/%s/autodm/model", roomID), headers, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("set autodm model failed: %d - %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
 // GetRoom gets room information.
 func (c *HTTPClient) GetRoom(ctx context.Context, token, roomID string) (*RoomResponse, error) {
 	headers := map[string]string{
@@ -280,6 +356,26 @@ func (c *HTTPClient) Health(ctx context.Context) (*HealthResponse, error) {
 	return &result, nil
 }
 
+// LLMHealth reports whether the AutoDM is enabled on the server.
+func (c *HTTPClient) LLMHealth(ctx context.Context) (*LLMHealthResponse, error) {
+	resp, err := c.doJSON(ctx, "GET", "/v1/llm/health", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("llm health failed: %d", resp.StatusCode)
+	}
+
+	var result LLMHealthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
 // Metrics gets Prometheus metrics.
 func (c *HTTPClient) Metrics(ctx context.Context) (*MetricsResponse, error) {
 	resp, err := c.doJSON(ctx, "GET", "/metrics", nil, nil)
@@ -0,0 +1,75 @@
+package loadgen
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// KeyGen produces idempotency keys, request IDs and behavior RNGs.
+// A seeded generator is fully deterministic so a failing run can be replayed
+// with the same --seed; an unseeded one falls back to timestamps.
+type KeyGen struct {
+	mu     sync.Mutex
+	seed   int64
+	seeded bool
+	rng    *rand.Rand
+	n      int64
+}
+
+// NewKeyGen creates a key generator. A zero seed means non-deterministic.
+func NewKeyGen(seed int64) *KeyGen {
+	g := &KeyGen{seed: seed, seeded: seed != 0}
+	if g.seeded {
+		g.rng = rand.New(rand.NewSource(seed))
+	}
+	return g
+}
+
+// Seeded reports whether the generator is deterministic.
+func (g *KeyGen) Seeded() bool {
+	return g.seeded
+}
+
+// Key returns the next key with the given prefix.
+func (g *KeyGen) Key(prefix string) string {
+	if !g.seeded {
+		return fmt.Sprintf("%s_%d", prefix, time.Now().UnixNano())
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.n++
+	return fmt.Sprintf("%s_%d_%08x", prefix, g.n, g.rng.Uint32())
+}
+
+// Derive returns an independent generator for label. Concurrent workers
+// should each derive their own so key order does not depend on scheduling.
+func (g *KeyGen) Derive(label string) *KeyGen {
+	if !g.seeded {
+		return NewKeyGen(0)
+	}
+	return NewKeyGen(g.deriveSeed(label))
+}
+
+// Rand returns a behavior RNG for label, seeded from the generator when
+// deterministic and from the clock otherwise.
+func (g *KeyGen) Rand(label string) *rand.Rand {
+	if !g.seeded {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return rand.New(rand.NewSource(g.deriveSeed(label)))
+}
+
+// deriveSeed mixes label into the root seed; never returns zero.
+func (g *KeyGen) deriveSeed(label string) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%s", g.seed, label)
+	s := int64(h.Sum64())
+	if s == 0 {
+		s = 1
+	}
+	return s
+}
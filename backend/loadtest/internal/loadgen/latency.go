@@ -0,0 +1,110 @@
+package loadgen
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyHistogram collects latency samples and reports percentiles.
+type LatencyHistogram struct {
+	mu      sync.Mutex
+	samples []float64 // milliseconds
+}
+
+// Record adds a latency sample.
+func (h *LatencyHistogram) Record(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.samples = append(h.samples, float64(d.Microseconds())/1000)
+}
+
+// Percentiles returns the nearest-rank p50/p95/p99 of the recorded samples.
+func (h *LatencyHistogram) Percentiles() LatencyPercentiles {
+	h.mu.Lock()
+	sorted := append([]float64(nil), h.samples...)
+	h.mu.Unlock()
+
+	sort.Float64s(sorted)
+	return LatencyPercentiles{
+		Count: len(sorted),
+		P50Ms: percentile(sorted, 50),
+		P95Ms: percentile(sorted, 95),
+		P99Ms: percentile(sorted, 99),
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted samples.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// latencyCollector gathers one scenario's command and WS round-trip latencies.
+type latencyCollector struct {
+	command   LatencyHistogram
+	roundTrip LatencyHistogram
+}
+
+// summary returns the scenario latency, or nil if nothing was measured.
+func (l *latencyCollector) summary() *ScenarioLatency {
+	cmd := l.command.Percentiles()
+	rtt := l.roundTrip.Percentiles()
+	if cmd.Count == 0 && rtt.Count == 0 {
+		return nil
+	}
+	return &ScenarioLatency{Command: cmd, WSRoundTrip: rtt}
+}
+
+// pendingRequest is a WS request awaiting its reply.
+type pendingRequest struct {
+	start   time.Time
+	command bool
+}
+
+// WithLatency makes the client record reply latencies into l.
+func (c *WSClient) WithLatency(l *latencyCollector) *WSClient {
+	c.latency = l
+	return c
+}
+
+// track notes the send time of a request that expects a reply.
+func (c *WSClient) track(requestID string, command bool) {
+	if c.latency == nil {
+		return
+	}
+	c.pendMu.Lock()
+	defer c.pendMu.Unlock()
+	if c.pending == nil {
+		c.pending = make(map[string]pendingRequest)
+	}
+	c.pending[requestID] = pendingRequest{start: time.Now(), command: command}
+}
+
+// observe records the latency of the reply to requestID.
+// Commands go to the command histogram; subscribe and ping to round-trip.
+func (c *WSClient) observe(requestID string) {
+	if c.latency == nil {
+		return
+	}
+	c.pendMu.Lock()
+	req, ok := c.pending[requestID]
+	delete(c.pending, requestID)
+	c.pendMu.Unlock()
+	if !ok {
+		return
+	}
+
+	if req.command {
+		c.latency.command.Record(time.Since(req.start))
+	} else {
+		c.latency.roundTrip.Record(time.Since(req.start))
+	}
+}
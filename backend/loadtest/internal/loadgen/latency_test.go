@@ -0,0 +1,60 @@
+package loadgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogramPercentiles(t *testing.T) {
+	var h LatencyHistogram
+	// Record 1..100ms in reverse so the histogram has to sort.
+	for i := 100; i >= 1; i-- {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	got := h.Percentiles()
+	want := LatencyPercentiles{Count: 100, P50Ms: 50, P95Ms: 95, P99Ms: 99}
+	if got != want {
+		t.Fatalf("Percentiles() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLatencyHistogramSmallSet(t *testing.T) {
+	var h LatencyHistogram
+	for _, ms := range []int{12, 3, 7, 30, 5} {
+		h.Record(time.Duration(ms) * time.Millisecond)
+	}
+
+	// Nearest rank over [3 5 7 12 30]: p50 -> 3rd, p95/p99 -> 5th.
+	got := h.Percentiles()
+	want := LatencyPercentiles{Count: 5, P50Ms: 7, P95Ms: 30, P99Ms: 30}
+	if got != want {
+		t.Fatalf("Percentiles() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLatencyHistogramEmpty(t *testing.T) {
+	var h LatencyHistogram
+	if got := h.Percentiles(); got != (LatencyPercentiles{}) {
+		t.Fatalf("empty Percentiles() = %+v, want zero", got)
+	}
+	if s := (&latencyCollector{}).summary(); s != nil {
+		t.Fatalf("empty summary = %+v, want nil", s)
+	}
+}
+
+func TestWSClientObserveSplitsCommandAndRoundTrip(t *testing.T) {
+	l := &latencyCollector{}
+	c := NewWSClient("ws://unused", "tok").WithLatency(l)
+
+	c.track("req_1", true)
+	c.track("sub_1", false)
+	c.observe("req_1")
+	c.observe("sub_1")
+	c.observe("unknown")
+
+	s := l.summary()
+	if s == nil || s.Command.Count != 1 || s.WSRoundTrip.Count != 1 {
+		t.Fatalf("summary = %+v, want one command and one round trip", s)
+	}
+}
@@ -0,0 +1,94 @@
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LLMFaultProxy is a reverse proxy in front of the LLM API that fails a
+// fraction of requests with 503, so the AutoDM's fallback path can be
+// exercised against a real server.
+type LLMFaultProxy struct {
+	upstream *url.URL
+	proxy    *httputil.ReverseProxy
+	failRate float64
+
+	rngMu sync.Mutex
+	rng   *rand.Rand
+
+	requests int64
+	failures int64
+	server   *http.Server
+}
+
+// NewLLMFaultProxy creates a proxy forwarding to upstream and failing
+// failRate (0..1) of requests, drawing from rng.
+func NewLLMFaultProxy(upstream string, failRate float64, rng *rand.Rand) (*LLMFaultProxy, error) {
+	u, err := url.Parse(upstream)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid LLM upstream %q", upstream)
+	}
+
+	p := &LLMFaultProxy{upstream: u, failRate: failRate, rng: rng}
+	p.proxy = httputil.NewSingleHostReverseProxy(u)
+	director := p.proxy.Director
+	p.proxy.Director = func(req *http.Request) {
+		director(req)
+		req.Host = u.Host // TLS upstreams route by Host
+	}
+	return p, nil
+}
+
+// ServeHTTP fails or forwards one LLM request.
+func (p *LLMFaultProxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	atomic.AddInt64(&p.requests, 1)
+	if p.shouldFail() {
+		atomic.AddInt64(&p.failures, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"error":{"message":"injected by loadgen LLM fault proxy","type":"server_error"}}`)
+		return
+	}
+	p.proxy.ServeHTTP(w, req)
+}
+
+// shouldFail draws whether the next request is failed.
+func (p *LLMFaultProxy) shouldFail() bool {
+	p.rngMu.Lock()
+	defer p.rngMu.Unlock()
+	return p.rng.Float64() < p.failRate
+}
+
+// Start listens on addr and serves in the background.
+func (p *LLMFaultProxy) Start(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("LLM fault proxy listen %s: %w", addr, err)
+	}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: 10 * time.Second}
+	go p.server.Serve(ln)
+	return nil
+}
+
+// Close stops the proxy.
+func (p *LLMFaultProxy) Close() error {
+	if p.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return p.server.Shutdown(ctx)
+}
+
+// Stats returns the number of proxied requests and injected failures.
+func (p *LLMFaultProxy) Stats() (requests, failures int64) {
+	return atomic.LoadInt64(&p.requests), atomic.LoadInt64(&p.failures)
+}
@@ -0,0 +1,80 @@
+package loadgen
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLLMFaultProxyFailsAndForwards(t *testing.T) {
+	var gotPath string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer upstream.Close()
+
+	alwaysFail, err := NewLLMFaultProxy(upstream.URL+"/v1", 1, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("NewLLMFaultProxy: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	alwaysFail.ServeHTTP(rec, httptest.NewRequest("POST", "/chat/completions", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("fail rate 1: status %d, want 503", rec.Code)
+	}
+
+	neverFail, _ := NewLLMFaultProxy(upstream.URL+"/v1", 0, rand.New(rand.NewSource(1)))
+	rec = httptest.NewRecorder()
+	neverFail.ServeHTTP(rec, httptest.NewRequest("POST", "/chat/completions", nil))
+	if rec.Code != http.StatusOK || gotPath != "/v1/chat/completions" {
+		t.Fatalf("fail rate 0: status %d path %q, want 200 /v1/chat/completions", rec.Code, gotPath)
+	}
+
+	if req, fail := alwaysFail.Stats(); req != 1 || fail != 1 {
+		t.Fatalf("alwaysFail stats = %d/%d, want 1/1", req, fail)
+	}
+	if req, fail := neverFail.Stats(); req != 1 || fail != 0 {
+		t.Fatalf("neverFail stats = %d/%d, want 1/0", req, fail)
+	}
+}
+
+func TestLLMFaultProxySeededFailuresRepeat(t *testing.T) {
+	pattern := func() []bool {
+		p, _ := NewLLMFaultProxy("http://upstream.invalid", 0.5, NewKeyGen(42).Rand("s11l_proxy"))
+		out := make([]bool, 20)
+		for i := range out {
+			out[i] = p.shouldFail()
+		}
+		return out
+	}
+	a, b := pattern(), pattern()
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("seeded failure pattern differs at %d", i)
+		}
+	}
+}
+
+func TestNarrationStatsClassifiesFallbacks(t *testing.T) {
+	chat := func(from, msg string) EventResponse {
+		data, _ := json.Marshal(map[string]string{"from": from, "message": msg})
+		return EventResponse{EventType: "public.chat", Data: data}
+	}
+
+	var n narrationStats
+	n.observe(chat("auto-dm", "🌙 Night falls. Please wait while night actions resolve."))
+	n.observe(chat("auto-dm", "🌙 夜幕降临，请等待夜晚行动结算。"))
+	n.observe(chat("auto-dm", "The moon rises over Ravenswood Bluff..."))
+	n.observe(chat("", "a player message"))
+	n.observe(EventResponse{EventType: "phase.night"})
+
+	if n.fallback != 2 || n.llm != 1 {
+		t.Fatalf("stats = %+v, want 2 fallback, 1 llm", n)
+	}
+	if got := n.fallbackRatio(); got < 0.66 || got > 0.67 {
+		t.Fatalf("fallbackRatio = %v, want 2/3", got)
+	}
+}
@@ -18,6 +18,15 @@ type Runner struct {
 	// HTTP client pool
 	httpClient *HTTPClient
 
+	// Deterministic keys and behavior (--seed)
+	keys *KeyGen
+
+	// Latency of the scenario currently running
+	latency *latencyCollector
+
+	// WS sockets opened by scenario clients
+	wsConns *connTracker
+
 	// Gemini protection
 	geminiSem     chan struct{}
 	geminiLimiter *rate.Limiter
@@ -31,7 +40,7 @@ type Runner struct {
 
 // NewRunner creates a new load test runner.
 func NewRunner(cfg Config) (*Runner, error) {
-	httpClient, err := NewHTTPClient(cfg.TargetHTTP)
+	httpClient, err := NewHTTPClient(cfg.TargetHTTP, cfg.HTTPMaxConnsPerHost)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
 	}
@@ -39,6 +48,8 @@ func NewRunner(cfg Config) (*Runner, error) {
 	return &Runner{
 		cfg:           cfg,
 		httpClient:    httpClient,
+		keys:          NewKeyGen(cfg.Seed),
+		wsConns:       &connTracker{},
 		geminiSem:     make(chan struct{}, cfg.GeminiMaxConcurrency),
 		geminiLimiter: rate.NewLimiter(rate.Limit(cfg.GeminiRPSLimit), cfg.GeminiRPSLimit),
 		geminiBudget:  cfg.GeminiRequestBudget,
@@ -89,6 +100,8 @@ func (r *Runner) runScenario(ctx context.Context, scenarioID string) (ScenarioRe
 
 	var result ScenarioResult
 	var err error
+	r.latency = &latencyCollector{}
+	httpBefore, wsBefore := r.beginSocketWindow()
 
 	switch scenarioID {
 	case "S1":
@@ -113,12 +126,18 @@ func (r *Runner) runScenario(ctx context.Context, scenarioID string) (ScenarioRe
 		result, err = r.runS10FullGameFlow(ctx)
 	case "S11":
 		result, err = r.runS11ChaosTest(ctx)
+	case "S11L":
+		result, err = r.runS11LLMChaos(ctx)
+	case "S12":
+		result, err = r.runS12BotsFullGame(ctx)
 	default:
 		return ScenarioResult{}, fmt.Errorf("unknown scenario: %s", scenarioID)
 	}
 
 	result.Scenario = scenarioID
 	result.DurationMs = time.Since(start).Milliseconds()
+	result.Latency = r.latency.summary()
+	result.Sockets = r.endSocketWindow(httpBefore, wsBefore)
 
 	if err != nil {
 		result.Passed = false
@@ -187,6 +206,27 @@ func (r *Runner) createTestUser(ctx context.Context, suffix string) (userID, tok
 	return regResp.UserID, loginResp.Token, nil
 }
 
+// newWSClient creates a WebSocket client whose IDs derive from the run seed.
+// label must be stable across runs (e.g. scenario and worker index).
+func (r *Runner) newWSClient(token, label string) *WSClient {
+	return NewWSClient(r.cfg.TargetWS, token).WithKeys(r.keys.Derive(label)).WithLatency(r.latency).WithSockets(r.wsConns)
+}
+
+// beginSocketWindow resets peak tracking and returns the counts at scenario start.
+func (r *Runner) beginSocketWindow() (httpBefore, wsBefore SocketStats) {
+	r.httpClient.conns.resetPeak()
+	r.wsConns.resetPeak()
+	return r.httpClient.SocketStats(), r.wsConns.stats()
+}
+
+// endSocketWindow reports the scenario's socket usage since beginSocketWindow.
+func (r *Runner) endSocketWindow(httpBefore, wsBefore SocketStats) *ScenarioSockets {
+	httpNow, wsNow := r.httpClient.SocketStats(), r.wsConns.stats()
+	httpNow.Opened -= httpBefore.Opened
+	wsNow.Opened -= wsBefore.Opened
+	return &ScenarioSockets{HTTP: httpNow, WS: wsNow}
+}
+
 // createTestRoom creates a test room and returns room ID.
 func (r *Runner) createTestRoom(ctx context.Context, token string) (roomID string, err error) {
 	resp, err := r.httpClient.CreateRoom(ctx, token)
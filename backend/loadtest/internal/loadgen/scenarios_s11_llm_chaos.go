@@ -0,0 +1,175 @@
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// llmChaosStallTimeout is how long the room may go without any event
+	// before the game counts as deadlocked
+	llmChaosStallTimeout = 5 * time.Minute
+)
+
+// fallbackNarrations mirrors the AutoDM's per-language fallback lines
+// (agent.defaultMessages); a narration matching one means the LLM call failed.
+var fallbackNarrations = map[string]bool{
+	"☀️ 天亮了，开始讨论并寻找隐藏的邪恶吧。":                                    true,
+	"🌙 夜幕降临，请等待夜晚行动结算。":                                        true,
+	"📣 提名已发起，请进行陈述与投票。":                                        true,
+	"🎲 游戏开始，愿好运站在你这边。":                                         true,
+	"🏁 对局结束，感谢各位参与。":                                           true,
+	"☀️ Dawn breaks. Discuss and hunt for the hidden evil.":    true,
+	"🌙 Night falls. Please wait while night actions resolve.":  true,
+	"📣 A nomination has been made. Make your case, then vote.": true,
+	"🎲 The game begins. May fortune favour you.":               true,
+	"🏁 The game is over. Thank you all for playing.":           true,
+}
+
+// narrationStats counts AutoDM public narrations by source.
+type narrationStats struct {
+	llm      int
+	fallback int
+}
+
+// observe classifies ev if it is an AutoDM public narration.
+func (n *narrationStats) observe(ev EventResponse) {
+	if ev.EventType != "public.chat" {
+		return
+	}
+	var data map[string]string
+	if err := json.Unmarshal(ev.Data, &data); err != nil {
+		return
+	}
+	if data["from"] != "auto-dm" && data["sender_name"] != "autodm" {
+		return
+	}
+	if fallbackNarrations[strings.TrimSpace(data["message"])] {
+		n.fallback++
+	} else {
+		n.llm++
+	}
+}
+
+// fallbackRatio is the share of narrations that were fallbacks.
+func (n *narrationStats) fallbackRatio() float64 {
+	total := n.llm + n.fallback
+	if total == 0 {
+		return 0
+	}
+	return float64(n.fallback) / float64(total)
+}
+
+// runS11LLMChaos is the S11 variant that points one room's AutoDM at a fault
+// proxy failing a share of LLM calls, and asserts the bot game still reaches
+// game.ended on fallback narrations without stalling.
+func (r *Runner) runS11LLMChaos(ctx context.Context) (ScenarioResult, error) {
+	result := ScenarioResult{
+		Metrics: make(map[string]interface{}),
+		Errors:  []string{},
+	}
+
+	proxy, health, err := r.startLLMFaultProxy(ctx)
+	if err != nil {
+		return result, err
+	}
+	defer proxy.Close()
+
+	roomID, ws, err := r.setupLLMChaosRoom(ctx, health)
+	if err != nil {
+		return result, err
+	}
+	defer ws.Close()
+
+	start := time.Now()
+	var stats narrationStats
+	phases, winner, waitErr := waitForGameEndObserving(ctx, ws, s12GameDeadline, llmChaosStallTimeout, stats.observe)
+	requests, failures := proxy.Stats()
+
+	result.Metrics["room_id"] = roomID
+	result.Metrics["game_duration_ms"] = time.Since(start).Milliseconds()
+	result.Metrics["phase_transitions"] = phases
+	result.Metrics["winner"] = winner
+	result.Metrics["llm_proxy_requests"] = requests
+	result.Metrics["llm_injected_failures"] = failures
+	result.Metrics["narrations_llm"] = stats.llm
+	result.Metrics["narrations_fallback"] = stats.fallback
+	result.Metrics["fallback_ratio"] = stats.fallbackRatio()
+
+	if waitErr != nil {
+		result.Errors = append(result.Errors, waitErr.Error())
+	}
+	if waitErr == nil && winner != "good" && winner != "evil" {
+		result.Errors = append(result.Errors, fmt.Sprintf("invalid winner %q", winner))
+	}
+	if requests == 0 {
+		result.Errors = append(result.Errors, "AutoDM never called the LLM through the fault proxy")
+	}
+	if failures > 0 && stats.llm+stats.fallback == 0 {
+		result.Errors = append(result.Errors, "AutoDM went silent instead of falling back")
+	}
+	result.Passed = len(result.Errors) == 0
+	return result, nil
+}
+
+// startLLMFaultProxy starts the fault proxy in front of the server's LLM endpoint.
+func (r *Runner) startLLMFaultProxy(ctx context.Context) (*LLMFaultProxy, *LLMHealthResponse, error) {
+	health, err := r.httpClient.LLMHealth(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("llm health failed: %w", err)
+	}
+	if !health.Enabled {
+		return nil, nil, fmt.Errorf("AutoDM is disabled on the target (set AUTODM_ENABLED=true)")
+	}
+
+	upstream := r.cfg.LLMFaultUpstream
+	if upstream == "" {
+		upstream = health.BaseURL
+	}
+	proxy, err := NewLLMFaultProxy(upstream, r.cfg.LLMFaultRate, r.keys.Rand("s11l_proxy"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := proxy.Start(r.cfg.LLMFaultListen); err != nil {
+		return nil, nil, err
+	}
+	return proxy, health, nil
+}
+
+// setupLLMChaosRoom creates the room, routes its AutoDM through the proxy,
+// fills it with bots and starts the game.
+func (r *Runner) setupLLMChaosRoom(ctx context.Context, health *LLMHealthResponse) (string, *WSClient, error) {
+	_, token, err := r.createTestUser(ctx, "s11l_dm")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create DM: %w", err)
+	}
+	roomID, err := r.createTestRoom(ctx, token)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create room: %w", err)
+	}
+	// The proxy URL must be in the server's AUTODM_BASE_URL_ALLOWLIST
+	if err := r.httpClient.SetAutoDMModel(ctx, token, roomID, health.Model, r.cfg.LLMFaultProxyURL); err != nil {
+		return "", nil, fmt.Errorf("failed to route AutoDM through fault proxy: %w", err)
+	}
+
+	ws := r.newWSClient(token, "s11l")
+	if err := ws.Connect(ctx); err != nil {
+		return "", nil, fmt.Errorf("DM connect failed: %w", err)
+	}
+	ws.Subscribe(ctx, roomID, 0)
+
+	if _, err := r.httpClient.AddBots(ctx, token, roomID, s12Players); err != nil {
+		ws.Close()
+		return "", nil, fmt.Errorf("failed to add bots: %w", err)
+	}
+	time.Sleep(time.Second)
+
+	if err := ws.SendCommand(ctx, roomID, "start_game", ws.Key("s11l_start"), nil); err != nil {
+		ws.Close()
+		return "", nil, fmt.Errorf("start_game failed: %w", err)
+	}
+	return roomID, ws, nil
+}
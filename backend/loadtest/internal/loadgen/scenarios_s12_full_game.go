@@ -0,0 +1,143 @@
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// s12Players is the table size the bots fill to
+	s12Players = 7
+	// s12GameDeadline bounds a full AutoDM-driven game
+	s12GameDeadline = 20 * time.Minute
+	// agentRunCountMetric counts AutoDM orchestrator (LLM) runs on the server
+	agentRunCountMetric = "agent_run_latency_ms_count"
+)
+
+// runS12BotsFullGame fills a room with bots, lets the AutoDM run the game and waits for game.ended.
+func (r *Runner) runS12BotsFullGame(ctx context.Context) (ScenarioResult, error) {
+	result := ScenarioResult{
+		Metrics: make(map[string]interface{}),
+		Errors:  []string{},
+	}
+
+	health, err := r.httpClient.LLMHealth(ctx)
+	if err != nil {
+		return result, fmt.Errorf("llm health failed: %w", err)
+	}
+	if !health.Enabled {
+		return result, fmt.Errorf("AutoDM is disabled on the target (set AUTODM_ENABLED=true)")
+	}
+
+	_, token, err := r.createTestUser(ctx, "s12_dm")
+	if err != nil {
+		return result, fmt.Errorf("failed to create DM: %w", err)
+	}
+	roomID, err := r.createTestRoom(ctx, token)
+	if err != nil {
+		return result, fmt.Errorf("failed to create room: %w", err)
+	}
+
+	ws := r.newWSClient(token, "s12")
+	if err := ws.Connect(ctx); err != nil {
+		return result, fmt.Errorf("DM connect failed: %w", err)
+	}
+	defer ws.Close()
+	ws.Subscribe(ctx, roomID, 0)
+
+	runsBefore := r.agentRunCount(ctx)
+
+	bots, err := r.httpClient.AddBots(ctx, token, roomID, s12Players)
+	if err != nil {
+		return result, fmt.Errorf("failed to add bots: %w", err)
+	}
+	result.Metrics["bots"] = bots.Count
+	time.Sleep(time.Second)
+
+	startKey := ws.Key("s12_start")
+	if err := ws.SendCommand(ctx, roomID, "start_game", startKey, nil); err != nil {
+		return result, fmt.Errorf("start_game failed: %w", err)
+	}
+
+	start := time.Now()
+	phases, winner, waitErr := waitForGameEnd(ctx, ws, s12GameDeadline)
+	llmRequests := r.agentRunCount(ctx) - runsBefore
+
+	result.Metrics["game_duration_ms"] = time.Since(start).Milliseconds()
+	result.Metrics["phase_transitions"] = phases
+	result.Metrics["winner"] = winner
+	result.Metrics["llm_requests"] = llmRequests
+	result.Metrics["llm_budget"] = r.cfg.GeminiRequestBudget
+
+	if waitErr != nil {
+		result.Errors = append(result.Errors, waitErr.Error())
+	}
+	if waitErr == nil && winner != "good" && winner != "evil" {
+		result.Errors = append(result.Errors, fmt.Sprintf("invalid winner %q", winner))
+	}
+	if int64(llmRequests) > r.cfg.GeminiRequestBudget {
+		result.Errors = append(result.Errors, fmt.Sprintf("LLM requests %d exceeded budget %d", llmRequests, r.cfg.GeminiRequestBudget))
+	}
+	result.Passed = len(result.Errors) == 0
+	return result, nil
+}
+
+// waitForGameEnd collects phase events until game.ended and returns the winner.
+func waitForGameEnd(ctx context.Context, ws *WSClient, deadline time.Duration) (phases []string, winner string, err error) {
+	return waitForGameEndObserving(ctx, ws, deadline, 0, nil)
+}
+
+// waitForGameEndObserving is waitForGameEnd that also passes every event to
+// observe (if non-nil) and fails once no event arrives for stall (0 = never).
+func waitForGameEndObserving(ctx context.Context, ws *WSClient, deadline, stall time.Duration, observe func(EventResponse)) (phases []string, winner string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	var stallC <-chan time.Time
+	var stallTimer *time.Timer
+	if stall > 0 {
+		stallTimer = time.NewTimer(stall)
+		defer stallTimer.Stop()
+		stallC = stallTimer.C
+	}
+
+	phases = []string{"lobby"}
+	for {
+		select {
+		case ev, ok := <-ws.Events():
+			if !ok {
+				return phases, "", fmt.Errorf("connection closed before game.ended")
+			}
+			if stallTimer != nil {
+				stallTimer.Reset(stall)
+			}
+			if observe != nil {
+				observe(ev)
+			}
+			if strings.HasPrefix(ev.EventType, "phase.") {
+				phases = append(phases, strings.TrimPrefix(ev.EventType, "phase."))
+			}
+			if ev.EventType == "game.ended" {
+				var data map[string]string
+				_ = json.Unmarshal(ev.Data, &data)
+				return append(phases, "ended"), data["winner"], nil
+			}
+		case <-stallC:
+			return phases, "", fmt.Errorf("game stalled: no event for %s (deadlock)", stall)
+		case <-ctx.Done():
+			return phases, "", fmt.Errorf("game did not end within %s", deadline)
+		}
+	}
+}
+
+// agentRunCount reads the server's AutoDM run count (0 when metrics are unavailable).
+func (r *Runner) agentRunCount(ctx context.Context) int {
+	metricsResp, err := r.httpClient.Metrics(ctx)
+	if err != nil {
+		return 0
+	}
+	return parseMetric(metricsResp.Raw, agentRunCountMetric)
+}
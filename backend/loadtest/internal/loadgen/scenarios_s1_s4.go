@@ -47,7 +47,7 @@ func (r *Runner) runS1WSHandshakeStorm(ctx context.Context) (ScenarioResult, err
 			defer wg.Done()
 
 			start := time.Now()
-			ws := NewWSClient(r.cfg.TargetWS, tokens[idx])
+			ws := r.newWSClient(tokens[idx], fmt.Sprintf("s1_%d", idx))
 
 			if err := ws.Connect(ctx); err != nil {
 				atomic.AddInt64(&failCount, 1)
@@ -131,7 +131,7 @@ func (r *Runner) runS2JoinStorm(ctx context.Context) (ScenarioResult, error) {
 		go func(idx int) {
 			defer wg.Done()
 
-			ws := NewWSClient(r.cfg.TargetWS, tokens[idx])
+			ws := r.newWSClient(tokens[idx], fmt.Sprintf("s2_%d", idx))
 			if err := ws.Connect(ctx); err != nil {
 				return
 			}
@@ -204,7 +204,7 @@ func (r *Runner) runS3IdempotencyVerification(ctx context.Context) (ScenarioResu
 	}
 
 	// Connect WS
-	ws := NewWSClient(r.cfg.TargetWS, token)
+	ws := r.newWSClient(token, "s3")
 	if err := ws.Connect(ctx); err != nil {
 		return result, fmt.Errorf("failed to connect: %w", err)
 	}
@@ -215,7 +215,7 @@ func (r *Runner) runS3IdempotencyVerification(ctx context.Context) (ScenarioResu
 	}
 
 	// Send the same command multiple times with same idempotency key
-	idempotencyKey := fmt.Sprintf("test_idem_%d", time.Now().UnixNano())
+	idempotencyKey := ws.Key("test_idem")
 	duplicateCount := 5
 
 	for i := 0; i < duplicateCount; i++ {
@@ -276,7 +276,7 @@ func (r *Runner) runS4SeqMonotonicity(ctx context.Context) (ScenarioResult, erro
 	}
 
 	// Connect WS
-	ws := NewWSClient(r.cfg.TargetWS, token)
+	ws := r.newWSClient(token, "s4")
 	if err := ws.Connect(ctx); err != nil {
 		return result, fmt.Errorf("failed to connect: %w", err)
 	}
@@ -289,7 +289,7 @@ func (r *Runner) runS4SeqMonotonicity(ctx context.Context) (ScenarioResult, erro
 	// Send many commands rapidly
 	commandCount := 50
 	for i := 0; i < commandCount; i++ {
-		idempotencyKey := fmt.Sprintf("cmd_%d_%d", time.Now().UnixNano(), i)
+		idempotencyKey := ws.Key(fmt.Sprintf("cmd_%d", i))
 		if err := ws.SendCommand(ctx, roomID, "public_chat", idempotencyKey, map[string]string{
 			"message": fmt.Sprintf("message %d", i),
 		}); err != nil {
@@ -303,14 +303,17 @@ func (r *Runner) runS4SeqMonotonicity(ctx context.Context) (ScenarioResult, erro
 	// Validate monotonicity
 	validator := NewCorrectnessValidator()
 	metrics := validator.ValidateSeqMonotonicity(events)
+	orderViolations := validator.ValidateDeliveryOrder(events, 0)
 
 	result.Metrics["commands_sent"] = commandCount
 	result.Metrics["events_received"] = len(events)
 	result.Metrics["seq_monotonic"] = metrics.SeqMonotonic
 	result.Metrics["missing_seqs"] = metrics.MissingSeqs
 	result.Metrics["duplicate_seqs"] = metrics.DuplicateSeqs
+	result.Metrics["order_violations"] = len(orderViolations)
 
-	result.Passed = metrics.SeqMonotonic && len(metrics.DuplicateSeqs) == 0
+	result.Passed = metrics.SeqMonotonic && len(metrics.DuplicateSeqs) == 0 && len(orderViolations) == 0
+	result.Errors = append(result.Errors, orderViolations...)
 
 	if !metrics.SeqMonotonic {
 		result.Errors = append(result.Errors, "sequence is not monotonic")
@@ -0,0 +1,248 @@
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// nightVisibilityPlayers is the minimum player count the engine accepts for start_game.
+const nightVisibilityPlayers = 5
+
+// nightVisibilityStats aggregates what each player saw during the first night.
+type nightVisibilityStats struct {
+	nightInfoLeaks       int
+	actionResultLeaks    int
+	redactedCompletions  int
+	fortuneTellerPresent bool
+	fortuneTellerGotInfo bool
+	promptsAnswered      int
+}
+
+// nightGame is the room and connected players used by the night visibility check.
+type nightGame struct {
+	roomID    string
+	tokens    []string
+	userIDs   []string
+	wsClients []*WSClient
+}
+
+// close disconnects every player's WebSocket.
+func (g *nightGame) close() {
+	for _, ws := range g.wsClients {
+		if ws != nil {
+			ws.Close()
+		}
+	}
+}
+
+// checkNightInfoVisibility starts a real game, drives the first night and
+// asserts that night results (Fortune Teller reads etc.) only reach their owner.
+// Other players must see night.action.completed with a redacted payload.
+func (r *Runner) checkNightInfoVisibility(ctx context.Context, result *ScenarioResult) bool {
+	game, ok := r.setupNightGame(ctx, result)
+	defer game.close()
+	if !ok {
+		return false
+	}
+	stats, ok := r.runFirstNight(ctx, game, result)
+	if !ok {
+		return false
+	}
+	return assertNightVisibility(stats, result)
+}
+
+// setupNightGame creates the players and room, joins and connects everyone.
+// The returned game must be closed even when setup fails.
+func (r *Runner) setupNightGame(ctx context.Context, result *ScenarioResult) (*nightGame, bool) {
+	game := &nightGame{
+		tokens:    make([]string, nightVisibilityPlayers),
+		userIDs:   make([]string, nightVisibilityPlayers),
+		wsClients: make([]*WSClient, nightVisibilityPlayers),
+	}
+	for i := 0; i < nightVisibilityPlayers; i++ {
+		userID, token, err := r.createTestUser(ctx, fmt.Sprintf("s5_night_p%d", i))
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("night visibility: create player %d: %v", i, err))
+			return game, false
+		}
+		game.tokens[i] = token
+		game.userIDs[i] = userID
+	}
+
+	roomID, err := r.createTestRoom(ctx, game.tokens[0])
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("night visibility: create room: %v", err))
+		return game, false
+	}
+	game.roomID = roomID
+	for i := 1; i < nightVisibilityPlayers; i++ {
+		if err := r.httpClient.JoinRoom(ctx, game.tokens[i], roomID); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("night visibility: player %d join: %v", i, err))
+			return game, false
+		}
+	}
+
+	for i := 0; i < nightVisibilityPlayers; i++ {
+		ws := r.newWSClient(game.tokens[i], fmt.Sprintf("s5_night_%d", i))
+		if err := ws.Connect(ctx); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("night visibility: player %d connect: %v", i, err))
+			return game, false
+		}
+		ws.Subscribe(ctx, roomID, 0)
+		game.wsClients[i] = ws
+	}
+	return game, true
+}
+
+// runFirstNight seats everyone, starts the game, answers the first night's
+// prompts and collects what each player saw.
+func (r *Runner) runFirstNight(ctx context.Context, game *nightGame, result *ScenarioResult) (nightVisibilityStats, bool) {
+	stats := nightVisibilityStats{}
+	for i, ws := range game.wsClients {
+		key := ws.Key(fmt.Sprintf("s5_claim_seat_%d", i))
+		ws.SendCommand(ctx, game.roomID, "claim_seat", key, map[string]int{"seat": i})
+		time.Sleep(100 * time.Millisecond)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	startKey := game.wsClients[0].Key("s5_start_game")
+	if err := game.wsClients[0].SendCommand(ctx, game.roomID, "start_game", startKey, nil); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("night visibility: start_game: %v", err))
+		return stats, false
+	}
+	time.Sleep(2 * time.Second)
+
+	stats.promptsAnswered = r.driveFirstNight(ctx, game.roomID, game.tokens, game.userIDs, game.wsClients)
+	time.Sleep(2 * time.Second)
+
+	for i := 0; i < nightVisibilityPlayers; i++ {
+		events, err := r.httpClient.GetEvents(ctx, game.tokens[i], game.roomID, 0)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("night visibility: get events for player %d: %v", i, err))
+			return stats, false
+		}
+		inspectNightEvents(events.Events, game.userIDs[i], &stats)
+	}
+	return stats, true
+}
+
+// assertNightVisibility records the night metrics and reports whether no leak was seen.
+func assertNightVisibility(stats nightVisibilityStats, result *ScenarioResult) bool {
+	result.Metrics["night_prompts_answered"] = stats.promptsAnswered
+	result.Metrics["night_info_leaks"] = stats.nightInfoLeaks
+	result.Metrics["night_action_result_leaks"] = stats.actionResultLeaks
+	result.Metrics["redacted_action_completions"] = stats.redactedCompletions
+	result.Metrics["fortune_teller_present"] = stats.fortuneTellerPresent
+	result.Metrics["fortune_teller_received_info"] = stats.fortuneTellerGotInfo
+
+	passed := true
+	if stats.nightInfoLeaks > 0 {
+		passed = false
+		result.Errors = append(result.Errors, fmt.Sprintf("visibility leak: %d night.info events seen by non-owners", stats.nightInfoLeaks))
+	}
+	if stats.actionResultLeaks > 0 {
+		passed = false
+		result.Errors = append(result.Errors, fmt.Sprintf("visibility leak: %d night.action.completed payloads not redacted for non-owners", stats.actionResultLeaks))
+	}
+	if stats.fortuneTellerPresent && !stats.fortuneTellerGotInfo {
+		passed = false
+		result.Errors = append(result.Errors, "fortune teller did not receive its night.info result")
+	}
+	return passed
+}
+
+// driveFirstNight answers night.action.prompt events until no new prompt
+// arrives or the deadline passes. Returns the number of prompts answered.
+func (r *Runner) driveFirstNight(ctx context.Context, roomID string, tokens, userIDs []string, wsClients []*WSClient) int {
+	answered := make(map[int64]bool)
+	deadline := time.Now().Add(20 * time.Second)
+
+	for time.Now().Before(deadline) {
+		progressed := false
+		for i := range tokens {
+			events, err := r.httpClient.GetEvents(ctx, tokens[i], roomID, 0)
+			if err != nil {
+				continue
+			}
+			for _, ev := range events.Events {
+				if ev.EventType != "night.action.prompt" || answered[ev.Seq] {
+					continue
+				}
+				data := decodeEventData(ev.Data)
+				if data["user_id"] != userIDs[i] {
+					continue
+				}
+				answered[ev.Seq] = true
+				progressed = true
+				sendNightAbility(ctx, wsClients[i], roomID, data["action_type"], pickTargets(userIDs, i))
+			}
+		}
+		if !progressed && len(answered) > 0 {
+			return len(answered)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return len(answered)
+}
+
+// sendNightAbility submits ability.use with targets shaped for the prompt's action type.
+func sendNightAbility(ctx context.Context, ws *WSClient, roomID, actionType string, targets []string) {
+	data := map[string]string{}
+	switch actionType {
+	case "select_one":
+		data["target"] = targets[0]
+	case "select_two":
+		targetsJSON, _ := json.Marshal(targets)
+		data["targets"] = string(targetsJSON)
+	}
+	key := ws.Key("s5_ability")
+	ws.SendCommand(ctx, roomID, "ability.use", key, data)
+}
+
+// pickTargets returns the two players seated after self.
+func pickTargets(userIDs []string, self int) []string {
+	n := len(userIDs)
+	return []string{userIDs[(self+1)%n], userIDs[(self+2)%n]}
+}
+
+// inspectNightEvents checks one player's event stream for night-info leaks.
+func inspectNightEvents(events []EventResponse, viewerID string, stats *nightVisibilityStats) {
+	isFortuneTeller := false
+	for _, ev := range events {
+		data := decodeEventData(ev.Data)
+		switch ev.EventType {
+		case "role.assigned":
+			if data["user_id"] == viewerID && data["role"] == "fortuneteller" {
+				isFortuneTeller = true
+				stats.fortuneTellerPresent = true
+			}
+		case "night.info":
+			if data["user_id"] != viewerID {
+				stats.nightInfoLeaks++
+			} else if isFortuneTeller && data["role_id"] == "fortuneteller" {
+				stats.fortuneTellerGotInfo = true
+			}
+		case "night.action.completed":
+			if data["user_id"] == viewerID {
+				continue
+			}
+			if len(data) > 0 {
+				stats.actionResultLeaks++
+			} else {
+				stats.redactedCompletions++
+			}
+		}
+	}
+}
+
+// decodeEventData parses an event payload into a string map; returns an empty map on failure.
+func decodeEventData(raw json.RawMessage) map[string]string {
+	data := map[string]string{}
+	if len(raw) == 0 {
+		return data
+	}
+	_ = json.Unmarshal(raw, &data)
+	return data
+}
@@ -47,9 +47,9 @@ func (r *Runner) runS5VisibilityLeakDetection(ctx context.Context) (ScenarioResu
 	}
 
 	// Connect all three via WebSocket
-	wsSender := NewWSClient(r.cfg.TargetWS, tokenSender)
-	wsRecipient := NewWSClient(r.cfg.TargetWS, tokenRecipient)
-	wsObserver := NewWSClient(r.cfg.TargetWS, tokenObserver)
+	wsSender := r.newWSClient(tokenSender, "s5_sender")
+	wsRecipient := r.newWSClient(tokenRecipient, "s5_recipient")
+	wsObserver := r.newWSClient(tokenObserver, "s5_observer")
 
 	if err := wsSender.Connect(ctx); err != nil {
 		return result, fmt.Errorf("sender connect failed: %w", err)
@@ -74,7 +74,7 @@ func (r *Runner) runS5VisibilityLeakDetection(ctx context.Context) (ScenarioResu
 	time.Sleep(500 * time.Millisecond)
 
 	// Sender sends a whisper to recipient
-	idempotencyKey := fmt.Sprintf("whisper_%d", time.Now().UnixNano())
+	idempotencyKey := wsSender.Key("whisper")
 	whisperData := map[string]interface{}{
 		"to":      "recipient_user_id", // In real test, use actual user ID
 		"message": "secret message",
@@ -121,6 +121,11 @@ func (r *Runner) runS5VisibilityLeakDetection(ctx context.Context) (ScenarioResu
 		result.Errors = append(result.Errors, fmt.Sprintf("visibility leak: observer saw %d whisper events", observerWhispers))
 	}
 
+	// Night info (Fortune Teller reads etc.) must only reach its owner
+	if !r.checkNightInfoVisibility(ctx, &result) {
+		result.Passed = false
+	}
+
 	return result, nil
 }
 
@@ -146,7 +151,7 @@ func (r *Runner) runS6GeminiCallMonitoring(ctx context.Context) (ScenarioResult,
 	}
 
 	// Connect WS
-	ws := NewWSClient(r.cfg.TargetWS, token)
+	ws := r.newWSClient(token, "s6")
 	if err := ws.Connect(ctx); err != nil {
 		return result, fmt.Errorf("failed to connect: %w", err)
 	}
@@ -156,7 +161,7 @@ func (r *Runner) runS6GeminiCallMonitoring(ctx context.Context) (ScenarioResult,
 
 	// Send commands that might trigger Gemini (depends on AutoDM config)
 	for i := 0; i < 5; i++ {
-		idempotencyKey := fmt.Sprintf("trigger_%d_%d", time.Now().UnixNano(), i)
+		idempotencyKey := ws.Key(fmt.Sprintf("trigger_%d", i))
 		ws.SendCommand(ctx, roomID, "public_chat", idempotencyKey, map[string]string{
 			"message": fmt.Sprintf("test message %d", i),
 		})
@@ -224,7 +229,7 @@ func (r *Runner) runS7MultiRoomIsolation(ctx context.Context) (ScenarioResult, e
 			return result, fmt.Errorf("failed to create room %d: %w", i, err)
 		}
 
-		ws := NewWSClient(r.cfg.TargetWS, token)
+		ws := r.newWSClient(token, fmt.Sprintf("s7_%d", i))
 		if err := ws.Connect(ctx); err != nil {
 			return result, fmt.Errorf("failed to connect room %d: %w", i, err)
 		}
@@ -256,7 +261,7 @@ func (r *Runner) runS7MultiRoomIsolation(ctx context.Context) (ScenarioResult, e
 			rd := &rooms[idx]
 
 			for j := 0; j < 5; j++ {
-				idempotencyKey := fmt.Sprintf("room%d_msg%d_%d", idx, j, time.Now().UnixNano())
+				idempotencyKey := rd.ws.Key(fmt.Sprintf("room%d_msg%d", idx, j))
 				rd.ws.SendCommand(ctx, rd.roomID, "public_chat", idempotencyKey, map[string]string{
 					"message": fmt.Sprintf("room %d message %d", idx, j),
 				})
@@ -327,7 +332,7 @@ func (r *Runner) runS8ReconnectSeqGap(ctx context.Context) (ScenarioResult, erro
 	}
 
 	// Phase 1: Connect and send some commands
-	ws1 := NewWSClient(r.cfg.TargetWS, token)
+	ws1 := r.newWSClient(token, "s8_phase1")
 	if err := ws1.Connect(ctx); err != nil {
 		return result, fmt.Errorf("failed to connect: %w", err)
 	}
@@ -336,7 +341,7 @@ func (r *Runner) runS8ReconnectSeqGap(ctx context.Context) (ScenarioResult, erro
 
 	// Send 5 commands
 	for i := 0; i < 5; i++ {
-		idempotencyKey := fmt.Sprintf("phase1_%d_%d", time.Now().UnixNano(), i)
+		idempotencyKey := ws1.Key(fmt.Sprintf("phase1_%d", i))
 		ws1.SendCommand(ctx, roomID, "public_chat", idempotencyKey, map[string]string{
 			"message": fmt.Sprintf("phase1 message %d", i),
 		})
@@ -357,7 +362,7 @@ func (r *Runner) runS8ReconnectSeqGap(ctx context.Context) (ScenarioResult, erro
 	ws1.Close()
 
 	// Phase 2: Send more commands via a different connection (simulating missed events)
-	ws2 := NewWSClient(r.cfg.TargetWS, token)
+	ws2 := r.newWSClient(token, "s8_phase2")
 	if err := ws2.Connect(ctx); err != nil {
 		return result, fmt.Errorf("failed to reconnect phase2: %w", err)
 	}
@@ -365,7 +370,7 @@ func (r *Runner) runS8ReconnectSeqGap(ctx context.Context) (ScenarioResult, erro
 	ws2.Subscribe(ctx, roomID, 0) // Subscribe from 0 to get all events
 
 	for i := 0; i < 3; i++ {
-		idempotencyKey := fmt.Sprintf("phase2_%d_%d", time.Now().UnixNano(), i)
+		idempotencyKey := ws2.Key(fmt.Sprintf("phase2_%d", i))
 		ws2.SendCommand(ctx, roomID, "public_chat", idempotencyKey, map[string]string{
 			"message": fmt.Sprintf("phase2 message %d", i),
 		})
@@ -376,7 +381,7 @@ func (r *Runner) runS8ReconnectSeqGap(ctx context.Context) (ScenarioResult, erro
 	ws2.Close()
 
 	// Phase 3: Reconnect with last_seq from phase 1 (simulating catching up)
-	ws3 := NewWSClient(r.cfg.TargetWS, token)
+	ws3 := r.newWSClient(token, "s8_phase3")
 	if err := ws3.Connect(ctx); err != nil {
 		return result, fmt.Errorf("failed to reconnect phase3: %w", err)
 	}
@@ -414,6 +419,10 @@ func (r *Runner) runS8ReconnectSeqGap(ctx context.Context) (ScenarioResult, erro
 	result.Metrics["max_replay_seq"] = maxReplaySeq
 	result.Metrics["all_after_last_seq"] = allAfterLastSeq
 
+	// Replay must resume at lastSeq+1 and arrive in order
+	orderViolations := NewCorrectnessValidator().ValidateDeliveryOrder(replayedEvents, lastSeq)
+	result.Metrics["order_violations"] = len(orderViolations)
+
 	// Validate completeness via HTTP replay
 	eventsResp, err := r.httpClient.GetEvents(ctx, token, roomID, lastSeq)
 	if err != nil {
@@ -422,7 +431,8 @@ func (r *Runner) runS8ReconnectSeqGap(ctx context.Context) (ScenarioResult, erro
 		result.Metrics["http_events_after_seq"] = len(eventsResp.Events)
 	}
 
-	result.Passed = allAfterLastSeq && len(replayedEvents) > 0
+	result.Passed = allAfterLastSeq && len(replayedEvents) > 0 && len(orderViolations) == 0
+	result.Errors = append(result.Errors, orderViolations...)
 
 	if !allAfterLastSeq {
 		result.Errors = append(result.Errors, "received events before last_seq")
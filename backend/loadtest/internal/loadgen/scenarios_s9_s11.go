@@ -3,7 +3,6 @@ package loadgen
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -89,7 +88,7 @@ func (r *Runner) runS10FullGameFlow(ctx context.Context) (ScenarioResult, error)
 	// Connect all via WebSocket
 	wsClients := make([]*WSClient, numPlayers)
 	for i := 0; i < numPlayers; i++ {
-		ws := NewWSClient(r.cfg.TargetWS, tokens[i])
+		ws := r.newWSClient(tokens[i], fmt.Sprintf("s10_%d", i))
 		if err := ws.Connect(ctx); err != nil {
 			return result, fmt.Errorf("player %d connect failed: %w", i, err)
 		}
@@ -111,7 +110,7 @@ func (r *Runner) runS10FullGameFlow(ctx context.Context) (ScenarioResult, error)
 
 	// 1. All players claim seats
 	for i := 0; i < numPlayers; i++ {
-		idempotencyKey := fmt.Sprintf("claim_seat_%d_%d", i, time.Now().UnixNano())
+		idempotencyKey := wsClients[i].Key(fmt.Sprintf("claim_seat_%d", i))
 		wsClients[i].SendCommand(ctx, roomID, "claim_seat", idempotencyKey, map[string]int{
 			"seat": i,
 		})
@@ -121,7 +120,7 @@ func (r *Runner) runS10FullGameFlow(ctx context.Context) (ScenarioResult, error)
 	time.Sleep(500 * time.Millisecond)
 
 	// 2. Host starts game
-	startKey := fmt.Sprintf("start_game_%d", time.Now().UnixNano())
+	startKey := wsClients[0].Key("start_game")
 	if err := wsClients[0].SendCommand(ctx, roomID, "start_game", startKey, nil); err != nil {
 		result.Errors = append(result.Errors, fmt.Sprintf("start_game failed: %v", err))
 	}
@@ -145,7 +144,7 @@ func (r *Runner) runS10FullGameFlow(ctx context.Context) (ScenarioResult, error)
 	// Send public chat messages
 	for i := 0; i < 3; i++ {
 		for j := 0; j < numPlayers; j++ {
-			chatKey := fmt.Sprintf("chat_%d_%d_%d", j, i, time.Now().UnixNano())
+			chatKey := wsClients[j].Key(fmt.Sprintf("chat_%d_%d", j, i))
 			wsClients[j].SendCommand(ctx, roomID, "public_chat", chatKey, map[string]string{
 				"message": fmt.Sprintf("player %d message %d", j, i),
 			})
@@ -238,7 +237,10 @@ func (r *Runner) runS11ChaosTest(ctx context.Context) (ScenarioResult, error) {
 		go func(idx int) {
 			defer wg.Done()
 
-			rng := rand.New(rand.NewSource(time.Now().UnixNano() + int64(idx)))
+			// One generator per worker, shared across reconnects, so a
+			// seeded run replays the same action and key sequence.
+			keys := r.keys.Derive(fmt.Sprintf("s11_%d", idx))
+			rng := r.keys.Rand(fmt.Sprintf("s11_%d", idx))
 			var ws *WSClient
 
 			for {
@@ -261,7 +263,7 @@ func (r *Runner) runS11ChaosTest(ctx context.Context) (ScenarioResult, error) {
 						atomic.AddInt64(&totalDisconnects, 1)
 					}
 
-					ws = NewWSClient(r.cfg.TargetWS, tokens[idx])
+					ws = NewWSClient(r.cfg.TargetWS, tokens[idx]).WithKeys(keys).WithLatency(r.latency).WithSockets(r.wsConns)
 					if err := ws.Connect(ctx); err != nil {
 						atomic.AddInt64(&totalErrors, 1)
 						ws = nil
@@ -282,7 +284,7 @@ func (r *Runner) runS11ChaosTest(ctx context.Context) (ScenarioResult, error) {
 						cmdTypes := []string{"public_chat", "join", "ping"}
 						cmdType := cmdTypes[rng.Intn(len(cmdTypes))]
 
-						idempotencyKey := fmt.Sprintf("chaos_%d_%d_%d", idx, time.Now().UnixNano(), rng.Int())
+						idempotencyKey := keys.Key(fmt.Sprintf("chaos_%d", idx))
 
 						var data interface{}
 						if cmdType == "public_chat" {
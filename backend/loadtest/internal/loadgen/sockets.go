@@ -0,0 +1,61 @@
+package loadgen
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// connTracker counts the TCP connections dialed through it.
+type connTracker struct {
+	open   int64
+	peak   int64
+	opened int64
+}
+
+// dialContext returns a DialContext that dials with d and tracks the result.
+func (t *connTracker) dialContext(d *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := d.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		atomic.AddInt64(&t.opened, 1)
+		open := atomic.AddInt64(&t.open, 1)
+		for {
+			peak := atomic.LoadInt64(&t.peak)
+			if open <= peak || atomic.CompareAndSwapInt64(&t.peak, peak, open) {
+				break
+			}
+		}
+		return &trackedConn{Conn: conn, tracker: t}, nil
+	}
+}
+
+// stats returns the current socket counts.
+func (t *connTracker) stats() SocketStats {
+	return SocketStats{
+		Open:   atomic.LoadInt64(&t.open),
+		Peak:   atomic.LoadInt64(&t.peak),
+		Opened: atomic.LoadInt64(&t.opened),
+	}
+}
+
+// resetPeak restarts peak tracking from the sockets open now (per scenario).
+func (t *connTracker) resetPeak() {
+	atomic.StoreInt64(&t.peak, atomic.LoadInt64(&t.open))
+}
+
+// trackedConn decrements its tracker once on Close.
+type trackedConn struct {
+	net.Conn
+	tracker *connTracker
+	once    sync.Once
+}
+
+// Close closes the connection and releases its slot.
+func (c *trackedConn) Close() error {
+	c.once.Do(func() { atomic.AddInt64(&c.tracker.open, -1) })
+	return c.Conn.Close()
+}
@@ -0,0 +1,73 @@
+package loadgen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientPoolStaysBounded(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	const users, maxConns = 50, 4
+	c, _ := NewHTTPClient(srv.URL, maxConns)
+
+	var wg sync.WaitGroup
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Health(context.Background()); err != nil {
+				t.Errorf("Health: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	s := c.SocketStats()
+	if s.Peak > maxConns || s.Opened > maxConns {
+		t.Fatalf("%d users used %+v sockets, want at most %d", users, s, maxConns)
+	}
+}
+
+func TestS1ReleasesSocketsAtNUsers(t *testing.T) {
+	fb := &fakeBackend{}
+	srv := httptest.NewServer(fb.handler())
+	defer srv.Close()
+
+	const users = 30
+	r, err := NewRunner(Config{
+		TargetHTTP:           srv.URL,
+		TargetWS:             "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws",
+		Users:                users,
+		GeminiMaxConcurrency: 1,
+		GeminiRPSLimit:       1,
+		HTTPMaxConnsPerHost:  4,
+	})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	res, _ := r.runScenario(ctx, "S1")
+	if !res.Passed {
+		t.Fatalf("S1 failed: %v", res.Errors)
+	}
+
+	s := res.Sockets
+	if s.WS.Opened != users || s.WS.Peak > users || s.WS.Open != 0 {
+		t.Fatalf("ws sockets = %+v, want %d opened, all closed", s.WS, users)
+	}
+	if s.HTTP.Opened > 4 {
+		t.Fatalf("http sockets = %+v, want at most 4 for %d users", s.HTTP, users)
+	}
+}
@@ -20,11 +20,25 @@ type Config struct {
 	// Output settings
 	Verbose bool
 
+	// HTTPMaxConnsPerHost caps the shared HTTP transport's sockets (0 = unlimited)
+	HTTPMaxConnsPerHost int
+
+	// Seed makes keys and client behavior deterministic (0 = time-based)
+	Seed int64
+
 	// Gemini protection
 	GeminiMaxConcurrency int
 	GeminiRPSLimit       int
 	GeminiRequestBudget  int64
 
+	// LLM fault injection (S11L): the proxy listens on LLMFaultListen, the
+	// server reaches it at LLMFaultProxyURL, and it forwards to
+	// LLMFaultUpstream (default: the server's LLM base URL)
+	LLMFaultListen   string
+	LLMFaultProxyURL string
+	LLMFaultUpstream string
+	LLMFaultRate     float64
+
 	// Internal - JWT token for authenticated requests
 	JWTSecret string
 }
@@ -52,6 +66,18 @@ func (c *Config) Validate() error {
 	if c.GeminiRequestBudget < 1 {
 		c.GeminiRequestBudget = 100
 	}
+	if c.HTTPMaxConnsPerHost < 0 {
+		return errors.New("HTTP max connections must not be negative")
+	}
+	if c.LLMFaultRate < 0 || c.LLMFaultRate > 1 {
+		return errors.New("LLM fault rate must be between 0 and 1")
+	}
+	if c.LLMFaultListen == "" {
+		c.LLMFaultListen = ":18090"
+	}
+	if c.LLMFaultProxyURL == "" {
+		c.LLMFaultProxyURL = "http://localhost:18090"
+	}
 	return nil
 }
 
@@ -59,6 +85,7 @@ func (c *Config) Validate() error {
 type Report struct {
 	Timestamp time.Time        `json:"timestamp"`
 	Target    string           `json:"target"`
+	Seed      int64            `json:"seed,omitempty"`
 	Scenarios []ScenarioResult `json:"scenarios"`
 	Summary   Summary          `json:"summary"`
 }
@@ -79,9 +106,41 @@ type ScenarioResult struct {
 	Passed     bool                   `json:"passed"`
 	DurationMs int64                  `json:"duration_ms"`
 	Metrics    map[string]interface{} `json:"metrics"`
+	Latency    *ScenarioLatency       `json:"latency,omitempty"`
+	Sockets    *ScenarioSockets       `json:"sockets,omitempty"`
 	Errors     []string               `json:"errors"`
 }
 
+// ScenarioLatency holds a scenario's command and WS round-trip latencies.
+// Command latency runs from sending a command to its command_result; WS
+// round-trip from a subscribe or ping to its reply.
+type ScenarioLatency struct {
+	Command     LatencyPercentiles `json:"command"`
+	WSRoundTrip LatencyPercentiles `json:"ws_round_trip"`
+}
+
+// ScenarioSockets holds a scenario's HTTP and WS socket counts.
+type ScenarioSockets struct {
+	HTTP SocketStats `json:"http"`
+	WS   SocketStats `json:"ws"`
+}
+
+// SocketStats counts TCP sockets: open now, peak open during the scenario,
+// and newly opened during the scenario.
+type SocketStats struct {
+	Open   int64 `json:"open"`
+	Peak   int64 `json:"peak"`
+	Opened int64 `json:"opened"`
+}
+
+// LatencyPercentiles summarizes a latency distribution in milliseconds.
+type LatencyPercentiles struct {
+	Count int     `json:"count"`
+	P50Ms float64 `json:"p50_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	P99Ms float64 `json:"p99_ms"`
+}
+
 // GeminiStats holds Gemini API usage statistics.
 type GeminiStats struct {
 	TotalRequests   int64
@@ -123,7 +182,7 @@ type GameFlowMetrics struct {
 
 // AllScenarios returns all available scenario IDs.
 func AllScenarios() []string {
-	return []string{"S1", "S2", "S3", "S4", "S5", "S6", "S7", "S8", "S9", "S10", "S11"}
+	return []string{"S1", "S2", "S3", "S4", "S5", "S6", "S7", "S8", "S9", "S10", "S11", "S11L", "S12"}
 }
 
 // ScenarioInfo returns human-readable info about a scenario.
@@ -138,7 +197,7 @@ func ScenarioInfo(id string) (name, description string) {
 	case "S4":
 		return "Command Seq Monotonicity", "Rapid sequential commands, verify Seq order"
 	case "S5":
-		return "Visibility Leak Detection", "Verify private events not leaked"
+		return "Visibility Leak Detection", "Verify whispers and night info not leaked"
 	case "S6":
 		return "Gemini Call Monitoring", "Monitor AutoDM Gemini calls within budget"
 	case "S7":
@@ -151,6 +210,10 @@ func ScenarioInfo(id string) (name, description string) {
 		return "Full Game Flow", "Lobby -> Night -> Day -> Vote -> End"
 	case "S11":
 		return "Chaos Test", "Random disconnects and commands"
+	case "S11L":
+		return "LLM Failure Chaos", "S11 variant: LLM calls fail via a fault proxy, AutoDM must fall back"
+	case "S12":
+		return "Bots Full Game", "Bots fill a room, AutoDM runs the game to game.ended"
 	default:
 		return "Unknown", fmt.Sprintf("Unknown scenario: %s", id)
 	}
@@ -1,9 +1,13 @@
 package loadgen
 
 import (
+	"fmt"
 	"sort"
 )
 
+// maxOrderViolations caps how many ordering violations are reported per call.
+const maxOrderViolations = 10
+
 // CorrectnessValidator validates correctness of load test results.
 type CorrectnessValidator struct{}
 
@@ -58,6 +62,49 @@ func (v *CorrectnessValidator) ValidateSeqMonotonicity(events []EventResponse) C
 	return metrics
 }
 
+// ValidateDeliveryOrder checks events in the order they were received: per
+// room, each seq must be exactly one more than the previous (no gaps, no
+// reorders, no repeats). afterSeq is the subscribe last_seq; 0 takes the first
+// event of each room as the baseline. Returns one message per violation.
+func (v *CorrectnessValidator) ValidateDeliveryOrder(events []EventResponse, afterSeq int64) []string {
+	violations := []string{}
+	last := make(map[string]int64)
+	total := 0
+
+	for i, ev := range events {
+		prev, seen := last[ev.RoomID]
+		if !seen && afterSeq == 0 {
+			last[ev.RoomID] = ev.Seq
+			continue
+		}
+		if !seen {
+			prev = afterSeq
+		}
+
+		var problem string
+		switch {
+		case ev.Seq <= prev:
+			problem = fmt.Sprintf("room %s: event %d has seq %d after seq %d (reordered or repeated)", ev.RoomID, i, ev.Seq, prev)
+		case ev.Seq > prev+1:
+			problem = fmt.Sprintf("room %s: event %d has seq %d after seq %d (gap)", ev.RoomID, i, ev.Seq, prev)
+		}
+		if problem != "" {
+			total++
+			if total <= maxOrderViolations {
+				violations = append(violations, problem)
+			}
+		}
+		if ev.Seq > prev {
+			last[ev.RoomID] = ev.Seq
+		}
+	}
+
+	if total > maxOrderViolations {
+		violations = append(violations, fmt.Sprintf("... and %d more ordering violations", total-maxOrderViolations))
+	}
+	return violations
+}
+
 // ValidateEventCompleteness checks if events form a complete sequence.
 func (v *CorrectnessValidator) ValidateEventCompleteness(events []EventResponse, expectedStart, expectedEnd int64) bool {
 	if len(events) == 0 {
@@ -0,0 +1,54 @@
+package loadgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func seqEvents(room string, seqs ...int64) []EventResponse {
+	events := make([]EventResponse, len(seqs))
+	for i, s := range seqs {
+		events[i] = EventResponse{RoomID: room, Seq: s}
+	}
+	return events
+}
+
+func TestValidateDeliveryOrderAcceptsContiguous(t *testing.T) {
+	v := NewCorrectnessValidator()
+	if got := v.ValidateDeliveryOrder(seqEvents("r1", 4, 5, 6, 7), 0); len(got) != 0 {
+		t.Fatalf("contiguous events flagged: %v", got)
+	}
+	if got := v.ValidateDeliveryOrder(seqEvents("r1", 11, 12), 10); len(got) != 0 {
+		t.Fatalf("resume after last_seq flagged: %v", got)
+	}
+}
+
+func TestValidateDeliveryOrderFlagsReorder(t *testing.T) {
+	v := NewCorrectnessValidator()
+	got := v.ValidateDeliveryOrder(seqEvents("r1", 1, 2, 4, 3, 5), 0)
+
+	// 4 arrives early (gap after 2), then 3 arrives late (reorder).
+	if len(got) != 2 {
+		t.Fatalf("violations = %v, want gap and reorder", got)
+	}
+	if !strings.Contains(got[0], "gap") || !strings.Contains(got[1], "reordered") {
+		t.Fatalf("unexpected violations: %v", got)
+	}
+}
+
+func TestValidateDeliveryOrderFlagsGapAfterLastSeq(t *testing.T) {
+	v := NewCorrectnessValidator()
+	got := v.ValidateDeliveryOrder(seqEvents("r1", 13, 14), 10)
+	if len(got) != 1 || !strings.Contains(got[0], "gap") {
+		t.Fatalf("violations = %v, want one gap", got)
+	}
+}
+
+func TestValidateDeliveryOrderPerRoom(t *testing.T) {
+	v := NewCorrectnessValidator()
+	events := append(seqEvents("a", 1, 2), seqEvents("b", 7, 8)...)
+	events = append(events, seqEvents("a", 3)...)
+	if got := v.ValidateDeliveryOrder(events, 0); len(got) != 0 {
+		t.Fatalf("interleaved rooms flagged: %v", got)
+	}
+}
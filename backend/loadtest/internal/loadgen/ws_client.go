@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/url"
 	"sync"
 	"sync/atomic"
@@ -20,6 +21,15 @@ type WSClient struct {
 	mu      sync.Mutex
 	closed  int32
 	eventCh chan EventResponse
+	keys    *KeyGen
+
+	// Socket accounting (nil disables it)
+	sockets *connTracker
+
+	// Reply latency tracking (nil latency disables it)
+	latency *latencyCollector
+	pendMu  sync.Mutex
+	pending map[string]pendingRequest
 }
 
 // WSMessage is a message sent/received over WebSocket.
@@ -60,9 +70,16 @@ func NewWSClient(baseWSURL, token string) *WSClient {
 		url:     baseWSURL,
 		token:   token,
 		eventCh: make(chan EventResponse, 1000),
+		keys:    NewKeyGen(0),
 	}
 }
 
+// WithKeys makes the client draw command and request IDs from g.
+func (c *WSClient) WithKeys(g *KeyGen) *WSClient {
+	c.keys = g
+	return c
+}
+
 // Connect establishes the WebSocket connection.
 func (c *WSClient) Connect(ctx context.Context) error {
 	c.mu.Lock()
@@ -81,6 +98,9 @@ func (c *WSClient) Connect(ctx context.Context) error {
 	dialer := websocket.Dialer{
 		HandshakeTimeout: 10 * time.Second,
 	}
+	if c.sockets != nil {
+		dialer.NetDialContext = c.sockets.dialContext(&net.Dialer{})
+	}
 
 	conn, _, err := dialer.DialContext(ctx, u.String(), nil)
 	if err != nil {
@@ -105,6 +125,9 @@ func (c *WSClient) Close() error {
 	defer c.mu.Unlock()
 
 	if c.conn != nil {
+		// Say goodbye so the server frees the session without waiting for a timeout
+		deadline := time.Now().Add(time.Second)
+		_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), deadline)
 		return c.conn.Close()
 	}
 	return nil
@@ -124,10 +147,11 @@ func (c *WSClient) Subscribe(ctx context.Context, roomID string, lastSeq int64)
 
 	msg := WSMessage{
 		Type:      "subscribe",
-		RequestID: fmt.Sprintf("sub_%d", time.Now().UnixNano()),
+		RequestID: c.keys.Key("sub"),
 		Payload:   payloadBytes,
 	}
 
+	c.track(msg.RequestID, false)
 	return c.send(msg)
 }
 
@@ -143,7 +167,7 @@ func (c *WSClient) SendCommand(ctx context.Context, roomID, cmdType, idempotency
 	}
 
 	payload := WSCommandPayload{
-		CommandID:      fmt.Sprintf("cmd_%d", time.Now().UnixNano()),
+		CommandID:      c.keys.Key("cmd"),
 		IdempotencyKey: idempotencyKey,
 		RoomID:         roomID,
 		Type:           cmdType,
@@ -157,10 +181,11 @@ func (c *WSClient) SendCommand(ctx context.Context, roomID, cmdType, idempotency
 
 	msg := WSMessage{
 		Type:      "command",
-		RequestID: fmt.Sprintf("req_%d", time.Now().UnixNano()),
+		RequestID: c.keys.Key("req"),
 		Payload:   payloadBytes,
 	}
 
+	c.track(msg.RequestID, true)
 	return c.send(msg)
 }
 
@@ -168,11 +193,23 @@ func (c *WSClient) SendCommand(ctx context.Context, roomID, cmdType, idempotency
 func (c *WSClient) Ping(ctx context.Context) error {
 	msg := WSMessage{
 		Type:      "ping",
-		RequestID: fmt.Sprintf("ping_%d", time.Now().UnixNano()),
+		RequestID: c.keys.Key("ping"),
 	}
+	c.track(msg.RequestID, false)
 	return c.send(msg)
 }
 
+// WithSockets makes the client count its socket in t.
+func (c *WSClient) WithSockets(t *connTracker) *WSClient {
+	c.sockets = t
+	return c
+}
+
+// Key returns the next idempotency key from the client's generator.
+func (c *WSClient) Key(prefix string) string {
+	return c.keys.Key(prefix)
+}
+
 // Events returns the event channel.
 func (c *WSClient) Events() <-chan EventResponse {
 	return c.eventCh
@@ -250,10 +287,11 @@ func (c *WSClient) reader() {
 				Data:      payload.Data,
 				ServerTS:  payload.ServerTS,
 			}
-		case "pong":
-			// Ignore pong
+		case "subscribed", "command_result", "pong":
+			c.observe(msg.RequestID)
 		case "error":
-			// Log error
+			// A rejected request still completes its round trip
+			c.observe(msg.RequestID)
 		}
 	}
 }
@@ -1,6 +1,6 @@
 #!/bin/bash
 # full_suite.sh - Full load test suite for Blood on the Clocktower Auto-DM
-# Runs all scenarios (S1-S11) with comprehensive load
+# Runs all scenarios (S1-S12, S11L) with comprehensive load
 # Expected duration: ~10-15 minutes
 
 set -e
@@ -8,6 +8,9 @@
 #   ./run_loadtest.sh -u 50 S2        # Run with 50 users
 #   ./run_loadtest.sh -d 60s S4       # Run for 60 seconds
 #   ./run_loadtest.sh                 # Run all scenarios
+#
+# The server under test must keep its auth rate limiter off (AUTH_RATE_LIMIT_BURST=0,
+# the default): every scenario logs many users in from this one IP.
 
 set -e
 
@@ -66,6 +69,8 @@ while [[ $# -gt 0 ]]; do
             echo "  S9  - RabbitMQ DLQ Monitoring"
             echo "  S10 - Full Game Flow"
             echo "  S11 - Chaos Test"
+            echo "  S11L - LLM Failure Chaos"
+            echo "  S12 - Bots Full Game"
             exit 0
             ;;
         -h|--help)
@@ -86,6 +91,9 @@ while [[ $# -gt 0 ]]; do
             echo "  $0 S1,S2,S3              Run multiple scenarios"
             echo "  $0 -u 50 -d 60s S2       Run S2 with 50 users for 60s"
             echo "  $0                       Run all scenarios"
+            echo ""
+            echo "The server must run with AUTH_RATE_LIMIT_BURST=0 (the default); a per-IP"
+            echo "auth limit rejects the logins every scenario makes from this host."
             exit 0
             ;;
         -*)